@@ -99,6 +99,22 @@ func TestECDSAOpts(t *testing.T) {
 	assert.Empty(t, opts.ExpansionValue())
 }
 
+func TestED25519Opts(t *testing.T) {
+	test := func(ephemeral bool) {
+		for _, opts := range []KeyGenOpts{
+			&ED25519KeyGenOpts{ephemeral},
+			&ED25519PKIXPublicKeyImportOpts{ephemeral},
+			&ED25519PrivateKeyImportOpts{ephemeral},
+			&ED25519GoPublicKeyImportOpts{ephemeral},
+		} {
+			assert.Equal(t, "ED25519", opts.Algorithm())
+			assert.Equal(t, ephemeral, opts.Ephemeral())
+		}
+	}
+	test(true)
+	test(false)
+}
+
 func TestHashOpts(t *testing.T) {
 	for _, ho := range []HashOpts{&SHA256Opts{}, &SHA384Opts{}, &SHA3_256Opts{}, &SHA3_384Opts{}} {
 		s := strings.Replace(reflect.TypeOf(ho).String(), "*bccsp.", "", -1)
@@ -155,3 +171,39 @@ func TestKeyGenOpts(t *testing.T) {
 	test(true)
 	test(false)
 }
+
+func TestECDSAP256HDOpts(t *testing.T) {
+	test := func(ephemeral bool) {
+		for _, opts := range []KeyGenOpts{
+			&ECDSAP256HDMasterKeyImportOpts{Temporary: ephemeral},
+			&ECDSAP256HDPublicKeyImportOpts{Temporary: ephemeral},
+			&ECDSAP256HDKeyDerivOpts{Temporary: ephemeral},
+		} {
+			assert.Equal(t, "ECDSAP256_HD", opts.Algorithm())
+			assert.Equal(t, ephemeral, opts.Ephemeral())
+		}
+	}
+	test(true)
+	test(false)
+}
+
+func TestHDPath(t *testing.T) {
+	path, err := ParseHDPath("m/44'/0h/1/2")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{HardenedKeyStart + 44, HardenedKeyStart, 1, 2}, path)
+	assert.Equal(t, "m/44'/0'/1/2", HDPathString(path))
+
+	path, err = ParseHDPath("3/4'")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{3, HardenedKeyStart + 4}, path)
+
+	path, err = ParseHDPath("m")
+	assert.NoError(t, err)
+	assert.Empty(t, path)
+	assert.Equal(t, "m", HDPathString(path))
+
+	for _, invalid := range []string{"m/a", "m/1//2", "m/2147483648", "m/-1"} {
+		_, err = ParseHDPath(invalid)
+		assert.Error(t, err, "path %s should be invalid", invalid)
+	}
+}
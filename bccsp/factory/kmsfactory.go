@@ -0,0 +1,50 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package factory
+
+import (
+	"justledger/bccsp"
+	"justledger/bccsp/kms"
+	"justledger/bccsp/sw"
+	"github.com/pkg/errors"
+)
+
+const (
+	// KMSBasedFactoryName is the name of the factory of the remote KMS-based BCCSP implementation
+	KMSBasedFactoryName = "KMS"
+)
+
+// KMSFactory is the factory of the remote KMS-based BCCSP.
+type KMSFactory struct{}
+
+// Name returns the name of this factory
+func (f *KMSFactory) Name() string {
+	return KMSBasedFactoryName
+}
+
+// Get returns an instance of BCCSP using Opts.
+func (f *KMSFactory) Get(config *FactoryOpts) (bccsp.BCCSP, error) {
+	// Validate arguments
+	if config == nil || config.KmsOpts == nil {
+		return nil, errors.New("Invalid config. It must not be nil.")
+	}
+
+	kmsOpts := config.KmsOpts
+
+	// The keystore only holds the keys that are not managed by the KMS
+	var ks bccsp.KeyStore
+	if !kmsOpts.Ephemeral && kmsOpts.FileKeystore != nil {
+		fks, err := sw.NewFileBasedKeyStore(nil, kmsOpts.FileKeystore.KeyStorePath, false)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to initialize software key store")
+		}
+		ks = fks
+	} else {
+		ks = sw.NewDummyKeyStore()
+	}
+	return kms.New(*kmsOpts, ks)
+}
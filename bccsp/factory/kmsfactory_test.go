@@ -0,0 +1,50 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package factory
+
+import (
+	"testing"
+
+	"justledger/bccsp/kms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKMSFactoryName(t *testing.T) {
+	f := &KMSFactory{}
+	assert.Equal(t, f.Name(), KMSBasedFactoryName)
+}
+
+func TestKMSFactoryGetInvalidArgs(t *testing.T) {
+	f := &KMSFactory{}
+
+	_, err := f.Get(nil)
+	assert.Error(t, err, "Invalid config. It must not be nil.")
+
+	_, err = f.Get(&FactoryOpts{})
+	assert.Error(t, err, "Invalid config. It must not be nil.")
+
+	opts := &FactoryOpts{
+		KmsOpts: &kms.KMSOpts{},
+	}
+	_, err = f.Get(opts)
+	assert.Error(t, err, "Failed initializing configuration")
+
+	opts = &FactoryOpts{
+		KmsOpts: &kms.KMSOpts{
+			SecLevel:   256,
+			HashFamily: "SHA2",
+		},
+	}
+	_, err = f.Get(opts)
+	assert.Error(t, err, "the URL of the KMS must be specified")
+}
+
+func TestGetBCCSPFromOptsKMS(t *testing.T) {
+	_, err := GetBCCSPFromOpts(&FactoryOpts{ProviderName: "KMS"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Could not initialize BCCSP KMS")
+}
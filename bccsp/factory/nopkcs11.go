@@ -19,14 +19,16 @@ package factory
 
 import (
 	"justledger/bccsp"
+	"justledger/bccsp/kms"
 	"github.com/pkg/errors"
 )
 
 // FactoryOpts holds configuration information used to initialize factory implementations
 type FactoryOpts struct {
-	ProviderName string      `mapstructure:"default" json:"default" yaml:"Default"`
-	SwOpts       *SwOpts     `mapstructure:"SW,omitempty" json:"SW,omitempty" yaml:"SwOpts"`
-	PluginOpts   *PluginOpts `mapstructure:"PLUGIN,omitempty" json:"PLUGIN,omitempty" yaml:"PluginOpts"`
+	ProviderName string       `mapstructure:"default" json:"default" yaml:"Default"`
+	SwOpts       *SwOpts      `mapstructure:"SW,omitempty" json:"SW,omitempty" yaml:"SwOpts"`
+	PluginOpts   *PluginOpts  `mapstructure:"PLUGIN,omitempty" json:"PLUGIN,omitempty" yaml:"PluginOpts"`
+	KmsOpts      *kms.KMSOpts `mapstructure:"KMS,omitempty" json:"KMS,omitempty" yaml:"KMSOpts"`
 }
 
 // InitFactories must be called before using factory interfaces
@@ -69,6 +71,15 @@ func InitFactories(config *FactoryOpts) error {
 			}
 		}
 
+		// Remote KMS-Based BCCSP
+		if config.KmsOpts != nil {
+			f := &KMSFactory{}
+			err := initBCCSP(f, config)
+			if err != nil {
+				factoriesInitError = errors.Wrapf(err, "Failed initializing KMS.BCCSP %s", factoriesInitError)
+			}
+		}
+
 		var ok bool
 		defaultBCCSP, ok = bccspMap[config.ProviderName]
 		if !ok {
@@ -87,6 +98,8 @@ func GetBCCSPFromOpts(config *FactoryOpts) (bccsp.BCCSP, error) {
 		f = &SWFactory{}
 	case "PLUGIN":
 		f = &PluginFactory{}
+	case "KMS":
+		f = &KMSFactory{}
 	default:
 		return nil, errors.Errorf("Could not find BCCSP, no '%s' provider", config.ProviderName)
 	}
@@ -19,6 +19,7 @@ package factory
 
 import (
 	"justledger/bccsp"
+	"justledger/bccsp/kms"
 	"justledger/bccsp/pkcs11"
 	"github.com/pkg/errors"
 )
@@ -28,6 +29,7 @@ type FactoryOpts struct {
 	ProviderName string             `mapstructure:"default" json:"default" yaml:"Default"`
 	SwOpts       *SwOpts            `mapstructure:"SW,omitempty" json:"SW,omitempty" yaml:"SwOpts"`
 	PluginOpts   *PluginOpts        `mapstructure:"PLUGIN,omitempty" json:"PLUGIN,omitempty" yaml:"PluginOpts"`
+	KmsOpts      *kms.KMSOpts       `mapstructure:"KMS,omitempty" json:"KMS,omitempty" yaml:"KMSOpts"`
 	Pkcs11Opts   *pkcs11.PKCS11Opts `mapstructure:"PKCS11,omitempty" json:"PKCS11,omitempty" yaml:"PKCS11"`
 }
 
@@ -87,6 +89,15 @@ func setFactories(config *FactoryOpts) error {
 		}
 	}
 
+	// Remote KMS-Based BCCSP
+	if config.KmsOpts != nil {
+		f := &KMSFactory{}
+		err := initBCCSP(f, config)
+		if err != nil {
+			factoriesInitError = errors.Wrapf(err, "Failed initializing KMS.BCCSP %s", factoriesInitError)
+		}
+	}
+
 	var ok bool
 	defaultBCCSP, ok = bccspMap[config.ProviderName]
 	if !ok {
@@ -106,6 +117,8 @@ func GetBCCSPFromOpts(config *FactoryOpts) (bccsp.BCCSP, error) {
 		f = &PKCS11Factory{}
 	case "PLUGIN":
 		f = &PluginFactory{}
+	case "KMS":
+		f = &KMSFactory{}
 	default:
 		return nil, errors.Errorf("Could not find BCCSP, no '%s' provider", config.ProviderName)
 	}
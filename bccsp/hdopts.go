@@ -0,0 +1,139 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bccsp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// HardenedKeyStart is the index of the first hardened child key.
+// Hardened children can only be derived from a private key.
+const HardenedKeyStart uint32 = 0x80000000
+
+// HDKey is a key of a hierarchical deterministic key tree, as in BIP32.
+// Besides the key material, it carries the chain code used
+// to derive its children and the path it was derived along.
+type HDKey interface {
+	Key
+
+	// ChainCode returns the chain code of this key
+	ChainCode() []byte
+
+	// Root returns the SKI of the master key this key was derived from
+	Root() []byte
+
+	// Path returns the derivation path from the master key to this key
+	Path() []uint32
+}
+
+// HDKeyStore is a KeyStore that can also retrieve
+// hierarchical deterministic keys by their derivation path.
+type HDKeyStore interface {
+	KeyStore
+
+	// GetKeyByPath returns the key derived along path from the master key whose SKI is root.
+	GetKeyByPath(root []byte, path []uint32) (k Key, err error)
+}
+
+// ECDSAP256HDMasterKeyImportOpts contains options for the import of the master key
+// of a hierarchical deterministic key tree over P-256 from a seed.
+// The seed must be passed as raw material and be between 16 and 64 bytes long.
+type ECDSAP256HDMasterKeyImportOpts struct {
+	Temporary bool
+}
+
+// Algorithm returns the key importation algorithm identifier (to be used).
+func (opts *ECDSAP256HDMasterKeyImportOpts) Algorithm() string {
+	return ECDSAP256HD
+}
+
+// Ephemeral returns true if the key to generate has to be ephemeral,
+// false otherwise.
+func (opts *ECDSAP256HDMasterKeyImportOpts) Ephemeral() bool {
+	return opts.Temporary
+}
+
+// ECDSAP256HDPublicKeyImportOpts contains options for the import of a hierarchical
+// deterministic public key over P-256. The public key must be passed as raw material
+// in PKIX format, while the chain code is part of the options.
+type ECDSAP256HDPublicKeyImportOpts struct {
+	Temporary bool
+	ChainCode []byte
+}
+
+// Algorithm returns the key importation algorithm identifier (to be used).
+func (opts *ECDSAP256HDPublicKeyImportOpts) Algorithm() string {
+	return ECDSAP256HD
+}
+
+// Ephemeral returns true if the key to generate has to be ephemeral,
+// false otherwise.
+func (opts *ECDSAP256HDPublicKeyImportOpts) Ephemeral() bool {
+	return opts.Temporary
+}
+
+// ECDSAP256HDKeyDerivOpts contains options for hierarchical deterministic
+// key derivation over P-256. The key is derived from the passed key along Path,
+// whose indices starting from HardenedKeyStart denote hardened children.
+type ECDSAP256HDKeyDerivOpts struct {
+	Temporary bool
+	Path      []uint32
+}
+
+// Algorithm returns the key derivation algorithm identifier (to be used).
+func (opts *ECDSAP256HDKeyDerivOpts) Algorithm() string {
+	return ECDSAP256HD
+}
+
+// Ephemeral returns true if the key to generate has to be ephemeral,
+// false otherwise.
+func (opts *ECDSAP256HDKeyDerivOpts) Ephemeral() bool {
+	return opts.Temporary
+}
+
+// ParseHDPath parses a derivation path of the form m/44'/0'/1, where an
+// apostrophe (or h) marks a hardened index. The leading m/ is optional.
+func ParseHDPath(s string) ([]uint32, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "m"), "/")
+	if s == "" {
+		return []uint32{}, nil
+	}
+
+	elements := strings.Split(s, "/")
+	path := make([]uint32, len(elements))
+	for i, e := range elements {
+		hardened := strings.HasSuffix(e, "'") || strings.HasSuffix(e, "h")
+		if hardened {
+			e = e[:len(e)-1]
+		}
+		index, err := strconv.ParseUint(e, 10, 32)
+		if err != nil || uint32(index) >= HardenedKeyStart {
+			return nil, fmt.Errorf("invalid index [%s] in derivation path", elements[i])
+		}
+		path[i] = uint32(index)
+		if hardened {
+			path[i] += HardenedKeyStart
+		}
+	}
+	return path, nil
+}
+
+// HDPathString returns the string representation of a derivation path,
+// as accepted by ParseHDPath.
+func HDPathString(path []uint32) string {
+	s := "m"
+	for _, index := range path {
+		if index >= HardenedKeyStart {
+			s += fmt.Sprintf("/%d'", index-HardenedKeyStart)
+		} else {
+			s += fmt.Sprintf("/%d", index)
+		}
+	}
+	return s
+}
@@ -0,0 +1,69 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+)
+
+// The KMS exposes the following REST API over mutually authenticated TLS.
+// Keys are addressed by the hex encoding of their subject key identifier,
+// which is computed as in the SW BCCSP.
+//
+//	POST /keys                  KeyGenRequest  -> KeyResponse
+//	GET  /keys/{ski}                           -> KeyResponse
+//	POST /keys/{ski}/sign       SignRequest    -> SignResponse
+//
+// Failures are reported with a non 2xx status code and an ErrorResponse.
+const keysPath = "/keys"
+
+// KeyGenRequest asks the KMS to generate a new ECDSA key pair
+type KeyGenRequest struct {
+	// Curve is the name of the elliptic curve, e.g. P-256
+	Curve string `json:"curve"`
+}
+
+// KeyResponse describes a key held by the KMS
+type KeyResponse struct {
+	SKI []byte `json:"ski"`
+	// PublicKey is the PKIX, DER encoded public key
+	PublicKey []byte `json:"publicKey"`
+}
+
+// SignRequest asks the KMS to sign a digest
+type SignRequest struct {
+	Digest []byte `json:"digest"`
+}
+
+// SignResponse carries a DER encoded ECDSA signature
+type SignResponse struct {
+	Signature []byte `json:"signature"`
+}
+
+// ErrorResponse carries the reason of a failed request
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+func ski(pub *ecdsa.PublicKey) []byte {
+	raw := elliptic.Marshal(pub.Curve, pub.X, pub.Y)
+	hash := sha256.Sum256(raw)
+	return hash[:]
+}
+
+func curveByName(name string) (elliptic.Curve, bool) {
+	switch name {
+	case elliptic.P256().Params().Name:
+		return elliptic.P256(), true
+	case elliptic.P384().Params().Name:
+		return elliptic.P384(), true
+	default:
+		return nil, false
+	}
+}
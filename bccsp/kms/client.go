@@ -0,0 +1,182 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// errKeyNotFound is returned when the KMS does not hold the requested key
+var errKeyNotFound = errors.New("key not found")
+
+// client talks to the KMS REST API over mutually authenticated TLS
+type client struct {
+	url        string
+	httpClient *http.Client
+}
+
+func newClient(opts KMSOpts) (*client, error) {
+	if opts.URL == "" {
+		return nil, errors.New("the URL of the KMS must be specified")
+	}
+	if !strings.HasPrefix(opts.URL, "https://") {
+		return nil, errors.Errorf("the URL of the KMS must use https, got %s", opts.URL)
+	}
+	if opts.ClientCert == "" || opts.ClientKey == "" {
+		return nil, errors.New("a client certificate and key are required to authenticate to the KMS")
+	}
+
+	cert, err := tls.LoadX509KeyPair(opts.ClientCert, opts.ClientKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed loading the KMS client certificate")
+	}
+
+	var rootCAs *x509.CertPool
+	if len(opts.RootCAs) != 0 {
+		rootCAs = x509.NewCertPool()
+		for _, file := range opts.RootCAs {
+			pem, err := ioutil.ReadFile(file)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed reading KMS root CA %s", file)
+			}
+			if !rootCAs.AppendCertsFromPEM(pem) {
+				return nil, errors.Errorf("no certificates found in KMS root CA %s", file)
+			}
+		}
+	}
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	return &client{
+		url: strings.TrimRight(opts.URL, "/"),
+		httpClient: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					Certificates: []tls.Certificate{cert},
+					RootCAs:      rootCAs,
+					MinVersion:   tls.VersionTLS12,
+				},
+			},
+		},
+	}, nil
+}
+
+// generateKey asks the KMS to generate a key pair on the given curve
+func (c *client) generateKey(curve elliptic.Curve) (*ecdsaPublicKey, error) {
+	resp := &KeyResponse{}
+	err := c.do(http.MethodPost, keysPath, &KeyGenRequest{Curve: curve.Params().Name}, resp)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed generating key in the KMS")
+	}
+	return parseKeyResponse(resp, nil)
+}
+
+// getPublicKey fetches the public key of the key identified by ski
+func (c *client) getPublicKey(ski []byte) (*ecdsaPublicKey, error) {
+	resp := &KeyResponse{}
+	err := c.do(http.MethodGet, keyPath(ski), nil, resp)
+	if err != nil {
+		return nil, err
+	}
+	return parseKeyResponse(resp, ski)
+}
+
+// sign asks the KMS to sign digest with the key identified by ski
+func (c *client) sign(ski, digest []byte) ([]byte, error) {
+	resp := &SignResponse{}
+	err := c.do(http.MethodPost, keyPath(ski)+"/sign", &SignRequest{Digest: digest}, resp)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed signing with the KMS")
+	}
+	if len(resp.Signature) == 0 {
+		return nil, errors.New("the KMS returned an empty signature")
+	}
+	return resp.Signature, nil
+}
+
+func (c *client) do(method, path string, in, out interface{}) error {
+	var body []byte
+	if in != nil {
+		var err error
+		body, err = json.Marshal(in)
+		if err != nil {
+			return errors.Wrap(err, "failed marshaling KMS request")
+		}
+	}
+
+	req, err := http.NewRequest(method, c.url+path, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed creating KMS request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed contacting the KMS at %s", c.url)
+	}
+	defer resp.Body.Close()
+
+	raw, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed reading KMS response")
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errKeyNotFound
+	case resp.StatusCode != http.StatusOK:
+		errResp := &ErrorResponse{}
+		if json.Unmarshal(raw, errResp) == nil && errResp.Error != "" {
+			return errors.Errorf("KMS returned %d: %s", resp.StatusCode, errResp.Error)
+		}
+		return errors.Errorf("KMS returned %d", resp.StatusCode)
+	}
+
+	return errors.Wrap(json.Unmarshal(raw, out), "failed unmarshaling KMS response")
+}
+
+func keyPath(ski []byte) string {
+	return keysPath + "/" + hex.EncodeToString(ski)
+}
+
+// parseKeyResponse extracts the public key from resp and checks that it
+// matches the expected SKI, if any
+func parseKeyResponse(resp *KeyResponse, expectedSKI []byte) (*ecdsaPublicKey, error) {
+	pk, err := x509.ParsePKIXPublicKey(resp.PublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed parsing public key returned by the KMS")
+	}
+	pub, ok := pk.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.Errorf("the KMS returned a %T public key, expected ECDSA", pk)
+	}
+
+	keySKI := ski(pub)
+	if !bytes.Equal(keySKI, resp.SKI) {
+		return nil, errors.New("the SKI returned by the KMS does not match the public key")
+	}
+	if expectedSKI != nil && !bytes.Equal(keySKI, expectedSKI) {
+		return nil, errors.Errorf("the KMS returned the public key of %x instead of %x", keySKI, expectedSKI)
+	}
+
+	return &ecdsaPublicKey{ski: keySKI, pub: pub}, nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"crypto/elliptic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// KMSOpts contains options for the KMSFactory
+type KMSOpts struct {
+	// Default algorithms when not specified
+	SecLevel   int    `mapstructure:"security" json:"security"`
+	HashFamily string `mapstructure:"hash" json:"hash"`
+
+	// Keystore options for the keys that are not held by the KMS
+	Ephemeral    bool              `mapstructure:"tempkeys,omitempty" json:"tempkeys,omitempty"`
+	FileKeystore *FileKeystoreOpts `mapstructure:"filekeystore,omitempty" json:"filekeystore,omitempty"`
+
+	// KMS options
+	URL        string        `mapstructure:"url" json:"url"`
+	ClientCert string        `mapstructure:"clientcert" json:"clientcert"`
+	ClientKey  string        `mapstructure:"clientkey" json:"clientkey"`
+	RootCAs    []string      `mapstructure:"rootcas" json:"rootcas"`
+	Timeout    time.Duration `mapstructure:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+// FileKeystoreOpts holds the location of the software keystore used
+// for the keys that are not held by the KMS.
+type FileKeystoreOpts struct {
+	KeyStorePath string `mapstructure:"keystore" yaml:"KeyStore"`
+}
+
+// defaultTimeout bounds each request to the KMS when no timeout is configured
+const defaultTimeout = 10 * time.Second
+
+type config struct {
+	ellipticCurve elliptic.Curve
+}
+
+func (conf *config) setSecurityLevel(securityLevel int, hashFamily string) error {
+	switch hashFamily {
+	case "SHA2", "SHA3":
+	default:
+		return errors.Errorf("Hash Family not supported [%s]", hashFamily)
+	}
+
+	switch securityLevel {
+	case 256:
+		conf.ellipticCurve = elliptic.P256()
+	case 384:
+		conf.ellipticCurve = elliptic.P384()
+	default:
+		return errors.Errorf("Security level not supported [%d]", securityLevel)
+	}
+	return nil
+}
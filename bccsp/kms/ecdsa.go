@@ -0,0 +1,43 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"crypto/ecdsa"
+
+	"justledger/bccsp"
+	"justledger/bccsp/utils"
+	"github.com/pkg/errors"
+)
+
+func (csp *impl) signECDSA(k ecdsaPrivateKey, digest []byte, opts bccsp.SignerOpts) ([]byte, error) {
+	signature, err := csp.client.sign(k.ski, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	// The KMS is not trusted to produce canonical signatures
+	return utils.SignatureToLowS(k.pub.pub, signature)
+}
+
+func verifyECDSA(k ecdsaPublicKey, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	r, s, err := utils.UnmarshalECDSASignature(signature)
+	if err != nil {
+		return false, errors.Wrap(err, "Failed unmashalling signature")
+	}
+
+	lowS, err := utils.IsLowS(k.pub, s)
+	if err != nil {
+		return false, err
+	}
+
+	if !lowS {
+		return false, errors.Errorf("Invalid S. Must be smaller than half the order [%s][%s]", s, utils.GetCurveHalfOrdersAt(k.pub.Curve))
+	}
+
+	return ecdsa.Verify(k.pub, digest, r, s), nil
+}
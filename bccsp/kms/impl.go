@@ -0,0 +1,179 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"crypto/elliptic"
+	"encoding/hex"
+	"sync"
+
+	"justledger/bccsp"
+	"justledger/bccsp/sw"
+	"justledger/common/flogging"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("bccsp_kms")
+
+// New returns a new instance of a BCCSP whose ECDSA private keys are held
+// by a remote key management service. Every other operation, as well as
+// keys not known to the KMS, are served by a software-based BCCSP backed by
+// the passed KeyStore.
+func New(opts KMSOpts, keyStore bccsp.KeyStore) (bccsp.BCCSP, error) {
+	// Init config
+	conf := &config{}
+	err := conf.setSecurityLevel(opts.SecLevel, opts.HashFamily)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed initializing configuration")
+	}
+
+	// Check KeyStore
+	if keyStore == nil {
+		return nil, errors.New("Invalid bccsp.KeyStore instance. It must be different from nil")
+	}
+
+	swCSP, err := sw.NewWithParams(opts.SecLevel, opts.HashFamily, keyStore)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed initializing fallback SW BCCSP")
+	}
+
+	c, err := newClient(opts)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed initializing KMS client")
+	}
+
+	return &impl{
+		BCCSP:   swCSP,
+		conf:    conf,
+		client:  c,
+		pubKeys: make(map[string]*ecdsaPublicKey),
+	}, nil
+}
+
+type impl struct {
+	bccsp.BCCSP
+
+	conf   *config
+	client *client
+
+	// pubKeys caches the public keys of the KMS keys, indexed by hex SKI
+	lock    sync.RWMutex
+	pubKeys map[string]*ecdsaPublicKey
+}
+
+// KeyGen generates a key using opts.
+func (csp *impl) KeyGen(opts bccsp.KeyGenOpts) (bccsp.Key, error) {
+	// Validate arguments
+	if opts == nil {
+		return nil, errors.New("Invalid Opts parameter. It must not be nil")
+	}
+
+	var curve elliptic.Curve
+	switch opts.(type) {
+	case *bccsp.ECDSAKeyGenOpts:
+		curve = csp.conf.ellipticCurve
+	case *bccsp.ECDSAP256KeyGenOpts:
+		curve = elliptic.P256()
+	case *bccsp.ECDSAP384KeyGenOpts:
+		curve = elliptic.P384()
+	default:
+		return csp.BCCSP.KeyGen(opts)
+	}
+
+	pub, err := csp.client.generateKey(curve)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed generating ECDSA key")
+	}
+	csp.cachePublicKey(pub)
+
+	return &ecdsaPrivateKey{pub.ski, *pub}, nil
+}
+
+// GetKey returns the key this CSP associates to
+// the Subject Key Identifier ski.
+func (csp *impl) GetKey(ski []byte) (bccsp.Key, error) {
+	if len(ski) == 0 {
+		return nil, errors.New("Invalid SKI. Cannot be of zero length")
+	}
+
+	if pub := csp.cachedPublicKey(ski); pub != nil {
+		return &ecdsaPrivateKey{ski, *pub}, nil
+	}
+
+	pub, err := csp.client.getPublicKey(ski)
+	switch err {
+	case nil:
+		csp.cachePublicKey(pub)
+		return &ecdsaPrivateKey{ski, *pub}, nil
+	case errKeyNotFound:
+		logger.Debugf("Key [%x] is not held by the KMS, falling back to the software keystore", ski)
+		return csp.BCCSP.GetKey(ski)
+	default:
+		return nil, errors.WithMessage(err, "Failed fetching key from the KMS")
+	}
+}
+
+// Sign signs digest using key k.
+// The opts argument should be appropriate for the primitive used.
+//
+// Note that when a signature of a hash of a larger message is needed,
+// the caller is responsible for hashing the larger message and passing
+// the hash (as digest).
+func (csp *impl) Sign(k bccsp.Key, digest []byte, opts bccsp.SignerOpts) ([]byte, error) {
+	// Validate arguments
+	if k == nil {
+		return nil, errors.New("Invalid Key. It must not be nil")
+	}
+	if len(digest) == 0 {
+		return nil, errors.New("Invalid digest. Cannot be empty")
+	}
+
+	// Check key type
+	switch k.(type) {
+	case *ecdsaPrivateKey:
+		return csp.signECDSA(*k.(*ecdsaPrivateKey), digest, opts)
+	default:
+		return csp.BCCSP.Sign(k, digest, opts)
+	}
+}
+
+// Verify verifies signature against key k and digest.
+// Signatures of KMS keys are verified locally with the cached public key.
+func (csp *impl) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	// Validate arguments
+	if k == nil {
+		return false, errors.New("Invalid Key. It must not be nil")
+	}
+	if len(signature) == 0 {
+		return false, errors.New("Invalid signature. Cannot be empty")
+	}
+	if len(digest) == 0 {
+		return false, errors.New("Invalid digest. Cannot be empty")
+	}
+
+	// Check key type
+	switch k.(type) {
+	case *ecdsaPrivateKey:
+		return verifyECDSA(k.(*ecdsaPrivateKey).pub, signature, digest, opts)
+	case *ecdsaPublicKey:
+		return verifyECDSA(*k.(*ecdsaPublicKey), signature, digest, opts)
+	default:
+		return csp.BCCSP.Verify(k, signature, digest, opts)
+	}
+}
+
+func (csp *impl) cachedPublicKey(ski []byte) *ecdsaPublicKey {
+	csp.lock.RLock()
+	defer csp.lock.RUnlock()
+	return csp.pubKeys[hex.EncodeToString(ski)]
+}
+
+func (csp *impl) cachePublicKey(pub *ecdsaPublicKey) {
+	csp.lock.Lock()
+	defer csp.lock.Unlock()
+	csp.pubKeys[hex.EncodeToString(pub.ski)] = pub
+}
@@ -0,0 +1,295 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"justledger/bccsp"
+	"justledger/bccsp/sw"
+	"justledger/common/crypto/tlsgen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testEnv struct {
+	server  *Server
+	opts    KMSOpts
+	tempDir string
+}
+
+func (env *testEnv) cleanup() {
+	env.server.Stop()
+	os.RemoveAll(env.tempDir)
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	tempDir, err := ioutil.TempDir("", "kms")
+	require.NoError(t, err)
+
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+	serverPair, err := ca.NewServerCertKeyPair("127.0.0.1")
+	require.NoError(t, err)
+	clientPair, err := ca.NewClientCertKeyPair()
+	require.NoError(t, err)
+
+	server, err := NewServer(ServerConfig{
+		ListenAddress: "127.0.0.1:0",
+		Certificate:   serverPair.Cert,
+		Key:           serverPair.Key,
+		ClientRootCAs: [][]byte{ca.CertBytes()},
+	})
+	require.NoError(t, err)
+	server.Start()
+
+	writeFile := func(name string, content []byte) string {
+		path := filepath.Join(tempDir, name)
+		require.NoError(t, ioutil.WriteFile(path, content, 0600))
+		return path
+	}
+
+	return &testEnv{
+		server:  server,
+		tempDir: tempDir,
+		opts: KMSOpts{
+			SecLevel:   256,
+			HashFamily: "SHA2",
+			URL:        "https://" + server.Address(),
+			ClientCert: writeFile("client.pem", clientPair.Cert),
+			ClientKey:  writeFile("client.key", clientPair.Key),
+			RootCAs:    []string{writeFile("ca.pem", ca.CertBytes())},
+		},
+	}
+}
+
+func TestNewInvalidOpts(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+
+	_, err := New(env.opts, nil)
+	assert.EqualError(t, err, "Invalid bccsp.KeyStore instance. It must be different from nil")
+
+	opts := env.opts
+	opts.SecLevel = 0
+	_, err = New(opts, sw.NewDummyKeyStore())
+	assert.EqualError(t, err, "Failed initializing configuration: Security level not supported [0]")
+
+	opts = env.opts
+	opts.URL = ""
+	_, err = New(opts, sw.NewDummyKeyStore())
+	assert.EqualError(t, err, "Failed initializing KMS client: the URL of the KMS must be specified")
+
+	opts = env.opts
+	opts.URL = "http://" + env.server.Address()
+	_, err = New(opts, sw.NewDummyKeyStore())
+	assert.Contains(t, err.Error(), "the URL of the KMS must use https")
+
+	opts = env.opts
+	opts.ClientKey = ""
+	_, err = New(opts, sw.NewDummyKeyStore())
+	assert.EqualError(t, err, "Failed initializing KMS client: a client certificate and key are required to authenticate to the KMS")
+
+	opts = env.opts
+	opts.RootCAs = []string{filepath.Join(env.tempDir, "nonexistent.pem")}
+	_, err = New(opts, sw.NewDummyKeyStore())
+	assert.Contains(t, err.Error(), "failed reading KMS root CA")
+}
+
+func TestKeyGenSignVerify(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+
+	csp, err := New(env.opts, sw.NewDummyKeyStore())
+	require.NoError(t, err)
+
+	for _, opts := range []bccsp.KeyGenOpts{
+		&bccsp.ECDSAKeyGenOpts{},
+		&bccsp.ECDSAP256KeyGenOpts{},
+		&bccsp.ECDSAP384KeyGenOpts{},
+	} {
+		k, err := csp.KeyGen(opts)
+		require.NoError(t, err)
+		assert.True(t, k.Private())
+		assert.False(t, k.Symmetric())
+		_, err = k.Bytes()
+		assert.Error(t, err)
+
+		pk, err := k.PublicKey()
+		require.NoError(t, err)
+		assert.False(t, pk.Private())
+		assert.Equal(t, k.SKI(), pk.SKI())
+		raw, err := pk.Bytes()
+		require.NoError(t, err)
+		assert.NotEmpty(t, raw)
+
+		digest := sha256.Sum256([]byte("hello world"))
+		signature, err := csp.Sign(k, digest[:], nil)
+		require.NoError(t, err)
+
+		valid, err := csp.Verify(k, signature, digest[:], nil)
+		assert.NoError(t, err)
+		assert.True(t, valid)
+
+		valid, err = csp.Verify(pk, signature, digest[:], nil)
+		assert.NoError(t, err)
+		assert.True(t, valid)
+
+		otherDigest := sha256.Sum256([]byte("goodbye world"))
+		valid, err = csp.Verify(pk, signature, otherDigest[:], nil)
+		assert.NoError(t, err)
+		assert.False(t, valid)
+
+		// The public key can be imported and used by a software BCCSP
+		swCSP, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+		require.NoError(t, err)
+		swPK, err := swCSP.KeyImport(raw, &bccsp.ECDSAPKIXPublicKeyImportOpts{Temporary: true})
+		require.NoError(t, err)
+		assert.Equal(t, k.SKI(), swPK.SKI())
+		valid, err = swCSP.Verify(swPK, signature, digest[:], nil)
+		assert.NoError(t, err)
+		assert.True(t, valid)
+	}
+}
+
+func TestGetKey(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+
+	ks := sw.NewDummyKeyStore()
+	csp, err := New(env.opts, ks)
+	require.NoError(t, err)
+
+	_, err = csp.GetKey(nil)
+	assert.EqualError(t, err, "Invalid SKI. Cannot be of zero length")
+
+	// A key provisioned in the KMS out of band is fetched and cached
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ski := env.server.ImportKey(priv)
+
+	k, err := csp.GetKey(ski)
+	require.NoError(t, err)
+	assert.True(t, k.Private())
+	assert.Equal(t, ski, k.SKI())
+	assert.NotNil(t, csp.(*impl).cachedPublicKey(ski))
+
+	// Once cached, the public key is served without contacting the KMS,
+	// while signing still requires it
+	env.server.Stop()
+	k, err = csp.GetKey(ski)
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte("hello world"))
+	_, err = csp.Sign(k, digest[:], nil)
+	assert.Contains(t, err.Error(), "failed contacting the KMS")
+}
+
+func TestGetKeyFallback(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+
+	csp, err := New(env.opts, sw.NewDummyKeyStore())
+	require.NoError(t, err)
+
+	// Keys unknown to the KMS are looked up in the software keystore
+	aesKey, err := csp.KeyGen(&bccsp.AESKeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	_, err = csp.GetKey(aesKey.SKI())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "This is a dummy KeyStore")
+	assert.Nil(t, csp.(*impl).cachedPublicKey(aesKey.SKI()))
+
+	// Software keys keep working through the fallback BCCSP
+	swKey, err := csp.KeyImport([]byte("0123456789abcdef0123456789abcdef"), &bccsp.AES256ImportKeyOpts{Temporary: true})
+	require.NoError(t, err)
+	ct, err := csp.Encrypt(swKey, []byte("hello"), &bccsp.AESCBCPKCS7ModeOpts{})
+	require.NoError(t, err)
+	pt, err := csp.Decrypt(swKey, ct, &bccsp.AESCBCPKCS7ModeOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello"), pt)
+}
+
+func TestMutualTLSRequired(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+
+	// A client certificate from an unknown CA is rejected by the server
+	otherCA, err := tlsgen.NewCA()
+	require.NoError(t, err)
+	otherPair, err := otherCA.NewClientCertKeyPair()
+	require.NoError(t, err)
+	opts := env.opts
+	opts.ClientCert = filepath.Join(env.tempDir, "other.pem")
+	opts.ClientKey = filepath.Join(env.tempDir, "other.key")
+	require.NoError(t, ioutil.WriteFile(opts.ClientCert, otherPair.Cert, 0600))
+	require.NoError(t, ioutil.WriteFile(opts.ClientKey, otherPair.Key, 0600))
+
+	csp, err := New(opts, sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	_, err = csp.KeyGen(&bccsp.ECDSAP256KeyGenOpts{})
+	assert.Contains(t, err.Error(), "failed contacting the KMS")
+
+	// The server is not trusted without the configured root CA
+	opts = env.opts
+	opts.RootCAs = nil
+	csp, err = New(opts, sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	_, err = csp.KeyGen(&bccsp.ECDSAP256KeyGenOpts{})
+	assert.Contains(t, err.Error(), "failed contacting the KMS")
+}
+
+func TestServerErrors(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+
+	c, err := newClient(env.opts)
+	require.NoError(t, err)
+
+	_, err = c.sign([]byte{1, 2, 3}, []byte{4, 5, 6})
+	assert.EqualError(t, err, "failed signing with the KMS: key not found")
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ski := env.server.ImportKey(priv)
+	_, err = c.sign(ski, nil)
+	assert.EqualError(t, err, "failed signing with the KMS: KMS returned 400: empty digest")
+
+	err = c.do("POST", keysPath, &KeyGenRequest{Curve: "P-521"}, &KeyResponse{})
+	assert.EqualError(t, err, "KMS returned 400: unsupported curve P-521")
+}
+
+func TestParseKeyResponse(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	resp := &KeyResponse{SKI: ski(&priv.PublicKey)}
+	resp.PublicKey, err = (&ecdsaPublicKey{pub: &priv.PublicKey}).Bytes()
+	require.NoError(t, err)
+
+	pub, err := parseKeyResponse(resp, nil)
+	require.NoError(t, err)
+	assert.Equal(t, resp.SKI, pub.SKI())
+
+	_, err = parseKeyResponse(resp, ski(&other.PublicKey))
+	assert.Contains(t, err.Error(), "the KMS returned the public key of")
+
+	resp.SKI = ski(&other.PublicKey)
+	_, err = parseKeyResponse(resp, nil)
+	assert.EqualError(t, err, "the SKI returned by the KMS does not match the public key")
+
+	resp.PublicKey = []byte("garbage")
+	_, err = parseKeyResponse(resp, nil)
+	assert.Contains(t, err.Error(), "failed parsing public key returned by the KMS")
+}
@@ -0,0 +1,206 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"justledger/bccsp/utils"
+	"github.com/pkg/errors"
+)
+
+// ServerConfig configures the reference KMS server
+type ServerConfig struct {
+	// ListenAddress is the address the server listens on, e.g. 127.0.0.1:0
+	ListenAddress string
+	// Certificate and Key are the PEM encoded TLS credentials of the server
+	Certificate []byte
+	Key         []byte
+	// ClientRootCAs are the PEM encoded CAs that issue the client certificates
+	ClientRootCAs [][]byte
+}
+
+// Server is a minimal, in-memory implementation of the KMS REST API.
+// It is meant as a reference for integrating a real KMS and for tests,
+// and must not be used to hold production keys.
+type Server struct {
+	listener   net.Listener
+	httpServer *http.Server
+
+	lock sync.RWMutex
+	keys map[string]*ecdsa.PrivateKey
+}
+
+// NewServer creates a reference KMS server which requires clients to
+// authenticate with a certificate issued by one of the configured CAs
+func NewServer(conf ServerConfig) (*Server, error) {
+	cert, err := tls.X509KeyPair(conf.Certificate, conf.Key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed loading server TLS credentials")
+	}
+	if len(conf.ClientRootCAs) == 0 {
+		return nil, errors.New("at least one client root CA is required")
+	}
+	clientCAs := x509.NewCertPool()
+	for _, pem := range conf.ClientRootCAs {
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return nil, errors.New("failed adding client root CA")
+		}
+	}
+
+	listener, err := net.Listen("tcp", conf.ListenAddress)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed listening on %s", conf.ListenAddress)
+	}
+
+	s := &Server{
+		listener: listener,
+		keys:     make(map[string]*ecdsa.PrivateKey),
+	}
+	s.httpServer = &http.Server{
+		Handler: s,
+		TLSConfig: &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    clientCAs,
+			MinVersion:   tls.VersionTLS12,
+		},
+	}
+	return s, nil
+}
+
+// Address returns the address the server listens on
+func (s *Server) Address() string {
+	return s.listener.Addr().String()
+}
+
+// Start serves requests in the background
+func (s *Server) Start() {
+	go s.httpServer.ServeTLS(s.listener, "", "")
+}
+
+// Stop closes the listener and all active connections
+func (s *Server) Stop() {
+	s.httpServer.Close()
+}
+
+// ImportKey makes the server hold priv and returns its SKI
+func (s *Server) ImportKey(priv *ecdsa.PrivateKey) []byte {
+	keySKI := ski(&priv.PublicKey)
+	s.lock.Lock()
+	s.keys[hex.EncodeToString(keySKI)] = priv
+	s.lock.Unlock()
+	return keySKI
+}
+
+// ServeHTTP dispatches the requests of the KMS REST API
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == keysPath {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+			return
+		}
+		s.generateKey(w, r)
+		return
+	}
+
+	if !strings.HasPrefix(r.URL.Path, keysPath+"/") {
+		writeError(w, http.StatusNotFound, "unknown path %s", r.URL.Path)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, keysPath+"/"), "/")
+
+	s.lock.RLock()
+	priv, exists := s.keys[parts[0]]
+	s.lock.RUnlock()
+	if !exists {
+		writeError(w, http.StatusNotFound, "key %s not found", parts[0])
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		writeKey(w, priv)
+	case len(parts) == 2 && parts[1] == "sign" && r.Method == http.MethodPost:
+		s.sign(w, r, priv)
+	default:
+		writeError(w, http.StatusNotFound, "unknown operation %s %s", r.Method, r.URL.Path)
+	}
+}
+
+func (s *Server) generateKey(w http.ResponseWriter, r *http.Request) {
+	req := &KeyGenRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		writeError(w, http.StatusBadRequest, "malformed request: %s", err)
+		return
+	}
+	curve, ok := curveByName(req.Curve)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "unsupported curve %s", req.Curve)
+		return
+	}
+
+	priv, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed generating key: %s", err)
+		return
+	}
+	s.ImportKey(priv)
+	writeKey(w, priv)
+}
+
+func (s *Server) sign(w http.ResponseWriter, r *http.Request, priv *ecdsa.PrivateKey) {
+	req := &SignRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		writeError(w, http.StatusBadRequest, "malformed request: %s", err)
+		return
+	}
+	if len(req.Digest) == 0 {
+		writeError(w, http.StatusBadRequest, "empty digest")
+		return
+	}
+
+	sigR, sigS, err := ecdsa.Sign(rand.Reader, priv, req.Digest)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed signing: %s", err)
+		return
+	}
+	signature, err := utils.MarshalECDSASignature(sigR, sigS)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed marshaling signature: %s", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &SignResponse{Signature: signature})
+}
+
+func writeKey(w http.ResponseWriter, priv *ecdsa.PrivateKey) {
+	raw, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed marshaling public key: %s", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &KeyResponse{SKI: ski(&priv.PublicKey), PublicKey: raw})
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	writeJSON(w, status, &ErrorResponse{Error: errors.Errorf(format, args...).Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -32,6 +32,9 @@ const (
 	// ECDSAReRand ECDSA key re-randomization
 	ECDSAReRand = "ECDSA_RERAND"
 
+	// ECDSAP256HD hierarchical deterministic ECDSA key derivation over P-256 curve
+	ECDSAP256HD = "ECDSAP256_HD"
+
 	// RSA at the default security level.
 	// Each BCCSP may or may not support default security level. If not supported than
 	// an error will be returned.
@@ -45,6 +48,9 @@ const (
 	// RSA at 4096 bit security level.
 	RSA4096 = "RSA4096"
 
+	// ED25519 Edwards-curve Digital Signature Algorithm over Curve25519 (key gen, import, sign, verify)
+	ED25519 = "ED25519"
+
 	// AES Advanced Encryption Standard at the default security level.
 	// Each BCCSP may or may not support default security level. If not supported than
 	// an error will be returned.
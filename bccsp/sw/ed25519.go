@@ -0,0 +1,53 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"crypto/ed25519"
+	"fmt"
+
+	"justledger/bccsp"
+)
+
+// Ed25519 hashes the message internally, therefore the digest is signed as it is.
+// This way callers that hash the message before signing, such as the MSP, and callers
+// that sign the whole message, such as TLS, are both supported.
+
+func signED25519(k ed25519.PrivateKey, digest []byte, opts bccsp.SignerOpts) ([]byte, error) {
+	if len(k) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("Invalid ed25519 private key size [%d]", len(k))
+	}
+	return ed25519.Sign(k, digest), nil
+}
+
+func verifyED25519(k ed25519.PublicKey, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	if len(k) != ed25519.PublicKeySize {
+		return false, fmt.Errorf("Invalid ed25519 public key size [%d]", len(k))
+	}
+	if len(signature) != ed25519.SignatureSize {
+		return false, fmt.Errorf("Invalid ed25519 signature size [%d]", len(signature))
+	}
+	return ed25519.Verify(k, digest, signature), nil
+}
+
+type ed25519Signer struct{}
+
+func (s *ed25519Signer) Sign(k bccsp.Key, digest []byte, opts bccsp.SignerOpts) ([]byte, error) {
+	return signED25519(k.(*ed25519PrivateKey).privKey, digest, opts)
+}
+
+type ed25519PrivateKeyVerifier struct{}
+
+func (v *ed25519PrivateKeyVerifier) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	return verifyED25519(k.(*ed25519PrivateKey).privKey.Public().(ed25519.PublicKey), signature, digest, opts)
+}
+
+type ed25519PublicKeyKeyVerifier struct{}
+
+func (v *ed25519PublicKeyKeyVerifier) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	return verifyED25519(k.(*ed25519PublicKey).pubKey, signature, digest, opts)
+}
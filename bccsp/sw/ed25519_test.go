@@ -0,0 +1,180 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"testing"
+
+	"justledger/bccsp"
+	"justledger/bccsp/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestED25519PrivateKey(t *testing.T) {
+	t.Parallel()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	k := &ed25519PrivateKey{priv}
+
+	assert.False(t, k.Symmetric())
+	assert.True(t, k.Private())
+
+	_, err = k.Bytes()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Not supported.")
+
+	k.privKey = nil
+	assert.Nil(t, k.SKI())
+
+	k.privKey = priv
+	hash := sha256.Sum256(pub)
+	assert.Equal(t, hash[:], k.SKI(), "SKI is not computed in the right way.")
+
+	pk, err := k.PublicKey()
+	assert.NoError(t, err)
+	ed25519PK, ok := pk.(*ed25519PublicKey)
+	assert.True(t, ok)
+	assert.Equal(t, pub, ed25519PK.pubKey)
+	assert.Equal(t, k.SKI(), pk.SKI())
+}
+
+func TestED25519PublicKey(t *testing.T) {
+	t.Parallel()
+
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	k := &ed25519PublicKey{pub}
+
+	assert.False(t, k.Symmetric())
+	assert.False(t, k.Private())
+
+	raw, err := k.Bytes()
+	assert.NoError(t, err)
+	pub2, err := x509.ParsePKIXPublicKey(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, pub, pub2)
+
+	pk, err := k.PublicKey()
+	assert.NoError(t, err)
+	assert.Equal(t, k, pk)
+
+	k.pubKey = nil
+	assert.Nil(t, k.SKI())
+}
+
+func TestED25519SignVerify(t *testing.T) {
+	t.Parallel()
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	k := &ed25519PrivateKey{priv}
+	pk, err := k.PublicKey()
+	assert.NoError(t, err)
+
+	digest := sha256.Sum256([]byte("Hello World"))
+	sigma, err := (&ed25519Signer{}).Sign(k, digest[:], nil)
+	assert.NoError(t, err)
+
+	valid, err := (&ed25519PrivateKeyVerifier{}).Verify(k, sigma, digest[:], nil)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = (&ed25519PublicKeyKeyVerifier{}).Verify(pk, sigma, digest[:], nil)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = (&ed25519PublicKeyKeyVerifier{}).Verify(pk, sigma, []byte("another digest"), nil)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	_, err = (&ed25519PublicKeyKeyVerifier{}).Verify(pk, sigma[1:], digest[:], nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid ed25519 signature size")
+
+	_, err = (&ed25519Signer{}).Sign(&ed25519PrivateKey{priv[1:]}, digest[:], nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid ed25519 private key size")
+}
+
+func TestED25519KeyImport(t *testing.T) {
+	t.Parallel()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	assert.NoError(t, err)
+	k, err := (&ed25519PKIXPublicKeyImportOptsKeyImporter{}).KeyImport(der, &bccsp.ED25519PKIXPublicKeyImportOpts{})
+	assert.NoError(t, err)
+	assert.Equal(t, pub, k.(*ed25519PublicKey).pubKey)
+
+	_, err = (&ed25519PKIXPublicKeyImportOptsKeyImporter{}).KeyImport("Hello World", &bccsp.ED25519PKIXPublicKeyImportOpts{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid raw material. Expected byte array.")
+
+	der, err = x509.MarshalPKCS8PrivateKey(priv)
+	assert.NoError(t, err)
+	k, err = (&ed25519PrivateKeyImportOptsKeyImporter{}).KeyImport(der, &bccsp.ED25519PrivateKeyImportOpts{})
+	assert.NoError(t, err)
+	assert.Equal(t, priv, k.(*ed25519PrivateKey).privKey)
+
+	_, err = (&ed25519PrivateKeyImportOptsKeyImporter{}).KeyImport([]byte{}, &bccsp.ED25519PrivateKeyImportOpts{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid raw. It must not be nil.")
+
+	k, err = (&ed25519GoPublicKeyImportOptsKeyImporter{}).KeyImport(pub, &bccsp.ED25519GoPublicKeyImportOpts{})
+	assert.NoError(t, err)
+	assert.Equal(t, pub, k.(*ed25519PublicKey).pubKey)
+
+	_, err = (&ed25519GoPublicKeyImportOptsKeyImporter{}).KeyImport(priv, &bccsp.ED25519GoPublicKeyImportOpts{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid raw material. Expected ed25519.PublicKey.")
+}
+
+func TestED25519Provider(t *testing.T) {
+	provider, ks, cleanup := currentTestConfig.Provider(t)
+	defer cleanup()
+
+	k, err := provider.KeyGen(&bccsp.ED25519KeyGenOpts{Temporary: false})
+	assert.NoError(t, err)
+	assert.True(t, k.Private())
+
+	// the key is stored in and can be retrieved from the keystore
+	k2, err := ks.GetKey(k.SKI())
+	assert.NoError(t, err)
+	assert.Equal(t, k.SKI(), k2.SKI())
+
+	digest, err := provider.Hash([]byte("Hello World"), &bccsp.SHAOpts{})
+	assert.NoError(t, err)
+	signature, err := provider.Sign(k2, digest, nil)
+	assert.NoError(t, err)
+
+	pk, err := k.PublicKey()
+	assert.NoError(t, err)
+	valid, err := provider.Verify(pk, signature, digest, nil)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	// the public key can be exported and imported back
+	raw, err := pk.Bytes()
+	assert.NoError(t, err)
+	pk2, err := provider.KeyImport(raw, &bccsp.ED25519PKIXPublicKeyImportOpts{Temporary: true})
+	assert.NoError(t, err)
+	assert.Equal(t, pk.SKI(), pk2.SKI())
+
+	// the private key can be recovered from its PEM encoding
+	pem, err := utils.PrivateKeyToPEM(k.(*ed25519PrivateKey).privKey, nil)
+	assert.NoError(t, err)
+	lowLevelKey, err := utils.PEMtoPrivateKey(pem, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, k.(*ed25519PrivateKey).privKey, lowLevelKey)
+}
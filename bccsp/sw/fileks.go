@@ -25,8 +25,12 @@ import (
 	"strings"
 
 	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
 	"crypto/rsa"
+	"crypto/x509"
 	"encoding/hex"
+	"encoding/pem"
 	"fmt"
 	"path/filepath"
 
@@ -34,6 +38,12 @@ import (
 	"justledger/bccsp/utils"
 )
 
+const (
+	hdChainCodePEMType = "HD CHAIN CODE"
+	hdRootPEMHeader    = "Root"
+	hdPathPEMHeader    = "Path"
+)
+
 // NewFileBasedKeyStore instantiated a file-based key store at a given position.
 // The key store can be encrypted if a non-empty password is specifiec.
 // It can be also be set as read only. In this case, any store operation
@@ -121,6 +131,14 @@ func (ks *fileBasedKeyStore) GetKey(ski []byte) (bccsp.Key, error) {
 	suffix := ks.getSuffix(hex.EncodeToString(ski))
 
 	switch suffix {
+	case "hdsk", "hdpk":
+		// Load the hierarchical deterministic key
+		key, err := ks.loadHDKey(hex.EncodeToString(ski), suffix)
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading hierarchical deterministic key [%x] [%s]", ski, err)
+		}
+
+		return key, nil
 	case "key":
 		// Load the key
 		key, err := ks.loadKey(hex.EncodeToString(ski))
@@ -141,6 +159,8 @@ func (ks *fileBasedKeyStore) GetKey(ski []byte) (bccsp.Key, error) {
 			return &ecdsaPrivateKey{key.(*ecdsa.PrivateKey)}, nil
 		case *rsa.PrivateKey:
 			return &rsaPrivateKey{key.(*rsa.PrivateKey)}, nil
+		case ed25519.PrivateKey:
+			return &ed25519PrivateKey{key.(ed25519.PrivateKey)}, nil
 		default:
 			return nil, errors.New("Secret key type not recognized")
 		}
@@ -156,6 +176,8 @@ func (ks *fileBasedKeyStore) GetKey(ski []byte) (bccsp.Key, error) {
 			return &ecdsaPublicKey{key.(*ecdsa.PublicKey)}, nil
 		case *rsa.PublicKey:
 			return &rsaPublicKey{key.(*rsa.PublicKey)}, nil
+		case ed25519.PublicKey:
+			return &ed25519PublicKey{key.(ed25519.PublicKey)}, nil
 		default:
 			return nil, errors.New("Public key type not recognized")
 		}
@@ -175,6 +197,22 @@ func (ks *fileBasedKeyStore) StoreKey(k bccsp.Key) (err error) {
 		return errors.New("Invalid key. It must be different from nil.")
 	}
 	switch k.(type) {
+	case *ecdsaHDPrivateKey:
+		kk := k.(*ecdsaHDPrivateKey)
+
+		err = ks.storeHDKey(hex.EncodeToString(k.SKI()), "hdsk", kk.privKey, kk.chainCode, kk.root, kk.path)
+		if err != nil {
+			return fmt.Errorf("Failed storing ECDSA HD private key [%s]", err)
+		}
+
+	case *ecdsaHDPublicKey:
+		kk := k.(*ecdsaHDPublicKey)
+
+		err = ks.storeHDKey(hex.EncodeToString(k.SKI()), "hdpk", kk.pubKey, kk.chainCode, kk.root, kk.path)
+		if err != nil {
+			return fmt.Errorf("Failed storing ECDSA HD public key [%s]", err)
+		}
+
 	case *ecdsaPrivateKey:
 		kk := k.(*ecdsaPrivateKey)
 
@@ -207,6 +245,22 @@ func (ks *fileBasedKeyStore) StoreKey(k bccsp.Key) (err error) {
 			return fmt.Errorf("Failed storing RSA public key [%s]", err)
 		}
 
+	case *ed25519PrivateKey:
+		kk := k.(*ed25519PrivateKey)
+
+		err = ks.storePrivateKey(hex.EncodeToString(k.SKI()), kk.privKey)
+		if err != nil {
+			return fmt.Errorf("Failed storing ED25519 private key [%s]", err)
+		}
+
+	case *ed25519PublicKey:
+		kk := k.(*ed25519PublicKey)
+
+		err = ks.storePublicKey(hex.EncodeToString(k.SKI()), kk.pubKey)
+		if err != nil {
+			return fmt.Errorf("Failed storing ED25519 public key [%s]", err)
+		}
+
 	case *aesPrivateKey:
 		kk := k.(*aesPrivateKey)
 
@@ -222,6 +276,49 @@ func (ks *fileBasedKeyStore) StoreKey(k bccsp.Key) (err error) {
 	return
 }
 
+// GetKeyByPath returns the hierarchical deterministic key derived along path
+// from the master key whose SKI is root. If both the private and the public key
+// are stored, the private key is returned.
+func (ks *fileBasedKeyStore) GetKeyByPath(root []byte, path []uint32) (bccsp.Key, error) {
+	// Validate arguments
+	if len(root) == 0 {
+		return nil, errors.New("Invalid root SKI. Cannot be of zero length.")
+	}
+
+	var publicKey bccsp.Key
+	files, _ := ioutil.ReadDir(ks.path)
+	for _, f := range files {
+		var suffix string
+		switch {
+		case strings.HasSuffix(f.Name(), "_hdsk"):
+			suffix = "hdsk"
+		case strings.HasSuffix(f.Name(), "_hdpk"):
+			suffix = "hdpk"
+		default:
+			continue
+		}
+
+		key, err := ks.loadHDKey(strings.TrimSuffix(f.Name(), "_"+suffix), suffix)
+		if err != nil {
+			continue
+		}
+
+		hdKey := key.(bccsp.HDKey)
+		if !bytes.Equal(hdKey.Root(), root) || bccsp.HDPathString(hdKey.Path()) != bccsp.HDPathString(path) {
+			continue
+		}
+		if key.Private() {
+			return key, nil
+		}
+		publicKey = key
+	}
+
+	if publicKey == nil {
+		return nil, fmt.Errorf("Key with root %s and path %s not found in %s", hex.EncodeToString(root), bccsp.HDPathString(path), ks.path)
+	}
+	return publicKey, nil
+}
+
 func (ks *fileBasedKeyStore) searchKeystoreForSKI(ski []byte) (k bccsp.Key, err error) {
 
 	files, _ := ioutil.ReadDir(ks.path)
@@ -249,6 +346,8 @@ func (ks *fileBasedKeyStore) searchKeystoreForSKI(ski []byte) (k bccsp.Key, err
 			k = &ecdsaPrivateKey{key.(*ecdsa.PrivateKey)}
 		case *rsa.PrivateKey:
 			k = &rsaPrivateKey{key.(*rsa.PrivateKey)}
+		case ed25519.PrivateKey:
+			k = &ed25519PrivateKey{key.(ed25519.PrivateKey)}
 		default:
 			continue
 		}
@@ -266,6 +365,12 @@ func (ks *fileBasedKeyStore) getSuffix(alias string) string {
 	files, _ := ioutil.ReadDir(ks.path)
 	for _, f := range files {
 		if strings.HasPrefix(f.Name(), alias) {
+			if strings.HasSuffix(f.Name(), "hdsk") {
+				return "hdsk"
+			}
+			if strings.HasSuffix(f.Name(), "hdpk") {
+				return "hdpk"
+			}
 			if strings.HasSuffix(f.Name(), "sk") {
 				return "sk"
 			}
@@ -329,6 +434,111 @@ func (ks *fileBasedKeyStore) storeKey(alias string, key []byte) error {
 	return nil
 }
 
+// storeHDKey stores a hierarchical deterministic key as the PEM encoding of the key,
+// followed by a PEM block holding the chain code, whose headers record
+// the root and the derivation path of the key.
+func (ks *fileBasedKeyStore) storeHDKey(alias, suffix string, key interface{}, chainCode, root []byte, path []uint32) error {
+	var rawKey []byte
+	var err error
+	if suffix == "hdsk" {
+		rawKey, err = utils.PrivateKeyToPEM(key, ks.pwd)
+	} else {
+		rawKey, err = utils.PublicKeyToPEM(key, ks.pwd)
+	}
+	if err != nil {
+		logger.Errorf("Failed converting key to PEM [%s]: [%s]", alias, err)
+		return err
+	}
+
+	var block *pem.Block
+	if len(ks.pwd) != 0 {
+		block, err = x509.EncryptPEMBlock(rand.Reader, hdChainCodePEMType, chainCode, ks.pwd, x509.PEMCipherAES256)
+		if err != nil {
+			logger.Errorf("Failed encrypting chain code [%s]: [%s]", alias, err)
+			return err
+		}
+	} else {
+		block = &pem.Block{Type: hdChainCodePEMType, Bytes: chainCode, Headers: map[string]string{}}
+	}
+	block.Headers[hdRootPEMHeader] = hex.EncodeToString(root)
+	block.Headers[hdPathPEMHeader] = bccsp.HDPathString(path)
+
+	err = ioutil.WriteFile(ks.getPathForAlias(alias, suffix), append(rawKey, pem.EncodeToMemory(block)...), 0600)
+	if err != nil {
+		logger.Errorf("Failed storing key [%s]: [%s]", alias, err)
+		return err
+	}
+
+	return nil
+}
+
+func (ks *fileBasedKeyStore) loadHDKey(alias, suffix string) (bccsp.Key, error) {
+	path := ks.getPathForAlias(alias, suffix)
+	logger.Debugf("Loading hierarchical deterministic key [%s] at [%s]...", alias, path)
+
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		logger.Errorf("Failed loading key [%s]: [%s].", alias, err.Error())
+
+		return nil, err
+	}
+
+	keyBlock, rest := pem.Decode(raw)
+	if keyBlock == nil {
+		return nil, errors.New("Failed decoding key PEM block")
+	}
+	block, _ := pem.Decode(rest)
+	if block == nil || block.Type != hdChainCodePEMType {
+		return nil, errors.New("Failed decoding chain code PEM block")
+	}
+
+	chainCode := block.Bytes
+	if x509.IsEncryptedPEMBlock(block) {
+		if len(ks.pwd) == 0 {
+			return nil, errors.New("Encrypted Key. Password must be different fom nil")
+		}
+		chainCode, err = x509.DecryptPEMBlock(block, ks.pwd)
+		if err != nil {
+			return nil, fmt.Errorf("Failed PEM decryption. [%s]", err)
+		}
+	}
+	root, err := hex.DecodeString(block.Headers[hdRootPEMHeader])
+	if err != nil {
+		return nil, fmt.Errorf("Failed decoding root [%s]", err)
+	}
+	hdPath, err := bccsp.ParseHDPath(block.Headers[hdPathPEMHeader])
+	if err != nil {
+		return nil, err
+	}
+
+	rawKey := raw[:len(raw)-len(rest)]
+	if suffix == "hdsk" {
+		key, err := utils.PEMtoPrivateKey(rawKey, ks.pwd)
+		if err != nil {
+			logger.Errorf("Failed parsing private key [%s]: [%s].", alias, err.Error())
+
+			return nil, err
+		}
+		privKey, ok := key.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("Secret key type not recognized")
+		}
+		return &ecdsaHDPrivateKey{&ecdsaPrivateKey{privKey}, chainCode, root, hdPath}, nil
+	}
+
+	key, err := utils.PEMtoPublicKey(rawKey, ks.pwd)
+	if err != nil {
+		logger.Errorf("Failed parsing public key [%s]: [%s].", alias, err.Error())
+
+		return nil, err
+	}
+	pubKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("Public key type not recognized")
+	}
+	return &ecdsaHDPublicKey{&ecdsaPublicKey{pubKey}, chainCode, root, hdPath}, nil
+}
+
 func (ks *fileBasedKeyStore) loadPrivateKey(alias string) (interface{}, error) {
 	path := ks.getPathForAlias(alias, "sk")
 	logger.Debugf("Loading private key [%s] at [%s]...", alias, path)
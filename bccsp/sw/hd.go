@@ -0,0 +1,163 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"math/big"
+
+	"justledger/bccsp"
+	"github.com/pkg/errors"
+)
+
+// Hierarchical deterministic key derivation follows BIP32, instantiated
+// over P-256 as specified by SLIP-0010, so that keys derived here match
+// the ones derived by other SLIP-0010 implementations from the same seed.
+
+// hdMasterKeyHMACKey is the HMAC key used to derive the master key from a seed
+var hdMasterKeyHMACKey = []byte("Nist256p1 seed")
+
+const (
+	hdMinSeedLen   = 16
+	hdMaxSeedLen   = 64
+	hdChainCodeLen = 32
+)
+
+// hdMasterKey derives the master private key and chain code from seed.
+func hdMasterKey(seed []byte) (*ecdsa.PrivateKey, []byte, error) {
+	if len(seed) < hdMinSeedLen || len(seed) > hdMaxSeedLen {
+		return nil, nil, errors.Errorf("invalid seed length [%d], it must be between %d and %d bytes", len(seed), hdMinSeedLen, hdMaxSeedLen)
+	}
+
+	curve := elliptic.P256()
+	data := seed
+	for {
+		mac := hmac.New(sha512.New, hdMasterKeyHMACKey)
+		mac.Write(data)
+		I := mac.Sum(nil)
+
+		k := new(big.Int).SetBytes(I[:32])
+		if k.Sign() != 0 && k.Cmp(curve.Params().N) < 0 {
+			return hdPrivateKey(curve, k), I[32:], nil
+		}
+		// The left half is not a valid key, SLIP-0010 retries with the whole output as seed
+		data = I
+	}
+}
+
+// hdChildPrivateKey derives the child private key and chain code at index from a parent private key.
+func hdChildPrivateKey(parent *ecdsa.PrivateKey, chainCode []byte, index uint32) (*ecdsa.PrivateKey, []byte, error) {
+	if parent.Curve != elliptic.P256() {
+		return nil, nil, errors.New("hierarchical deterministic derivation is only supported over P-256")
+	}
+
+	var data []byte
+	if index >= bccsp.HardenedKeyStart {
+		data = append([]byte{0x00}, hdPaddedBytes(parent.D)...)
+	} else {
+		data = hdCompressedPoint(parent.X, parent.Y)
+	}
+
+	N := parent.Params().N
+	for {
+		I := hdHMAC(chainCode, data, index)
+
+		k := new(big.Int).SetBytes(I[:32])
+		if k.Cmp(N) < 0 {
+			k.Add(k, parent.D)
+			k.Mod(k, N)
+			if k.Sign() != 0 {
+				return hdPrivateKey(parent.Curve, k), I[32:], nil
+			}
+		}
+		data = append([]byte{0x01}, I[32:]...)
+	}
+}
+
+// hdChildPublicKey derives the child public key and chain code at index from a parent public key.
+// Only non-hardened children can be derived from a public key.
+func hdChildPublicKey(parent *ecdsa.PublicKey, chainCode []byte, index uint32) (*ecdsa.PublicKey, []byte, error) {
+	if parent.Curve != elliptic.P256() {
+		return nil, nil, errors.New("hierarchical deterministic derivation is only supported over P-256")
+	}
+	if index >= bccsp.HardenedKeyStart {
+		return nil, nil, errors.Errorf("cannot derive hardened child [%d] from a public key", index-bccsp.HardenedKeyStart)
+	}
+
+	data := hdCompressedPoint(parent.X, parent.Y)
+	for {
+		I := hdHMAC(chainCode, data, index)
+
+		k := new(big.Int).SetBytes(I[:32])
+		if k.Cmp(parent.Params().N) < 0 {
+			x, y := parent.ScalarBaseMult(I[:32])
+			x, y = parent.Add(x, y, parent.X, parent.Y)
+			// The point at infinity is represented as (0, 0)
+			if x.Sign() != 0 || y.Sign() != 0 {
+				return &ecdsa.PublicKey{Curve: parent.Curve, X: x, Y: y}, I[32:], nil
+			}
+		}
+		data = append([]byte{0x01}, I[32:]...)
+	}
+}
+
+func hdHMAC(chainCode, data []byte, index uint32) []byte {
+	var i [4]byte
+	binary.BigEndian.PutUint32(i[:], index)
+
+	mac := hmac.New(sha512.New, chainCode)
+	mac.Write(data)
+	mac.Write(i[:])
+	return mac.Sum(nil)
+}
+
+func hdPrivateKey(curve elliptic.Curve, d *big.Int) *ecdsa.PrivateKey {
+	k := &ecdsa.PrivateKey{D: d}
+	k.PublicKey.Curve = curve
+	k.PublicKey.X, k.PublicKey.Y = curve.ScalarBaseMult(hdPaddedBytes(d))
+	return k
+}
+
+// hdPaddedBytes returns the big-endian encoding of n left-padded to 32 bytes
+func hdPaddedBytes(n *big.Int) []byte {
+	b := n.Bytes()
+	padded := make([]byte, 32)
+	copy(padded[32-len(b):], b)
+	return padded
+}
+
+// hdCompressedPoint returns the 33-byte compressed encoding of a P-256 point,
+// i.e., 0x02 or 0x03 depending on the parity of y, followed by x
+func hdCompressedPoint(x, y *big.Int) []byte {
+	prefix := byte(0x02)
+	if y.Bit(0) == 1 {
+		prefix = 0x03
+	}
+	return append([]byte{prefix}, hdPaddedBytes(x)...)
+}
+
+type ecdsaHDSigner struct{}
+
+func (s *ecdsaHDSigner) Sign(k bccsp.Key, digest []byte, opts bccsp.SignerOpts) ([]byte, error) {
+	return signECDSA(k.(*ecdsaHDPrivateKey).privKey, digest, opts)
+}
+
+type ecdsaHDPrivateKeyVerifier struct{}
+
+func (v *ecdsaHDPrivateKeyVerifier) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	return verifyECDSA(&(k.(*ecdsaHDPrivateKey).privKey.PublicKey), signature, digest, opts)
+}
+
+type ecdsaHDPublicKeyKeyVerifier struct{}
+
+func (v *ecdsaHDPublicKeyKeyVerifier) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	return verifyECDSA(k.(*ecdsaHDPublicKey).pubKey, signature, digest, opts)
+}
@@ -0,0 +1,234 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"justledger/bccsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// SLIP-0010 test vector 1 for nist256p1
+var hdTestVectors = []struct {
+	path      string
+	chainCode string
+	private   string
+	public    string
+}{
+	{
+		path:      "m",
+		chainCode: "beeb672fe4621673f722f38529c07392fecaa61015c80c34f29ce8b41b3cb6ea",
+		private:   "612091aaa12e22dd2abef664f8a01a82cae99ad7441b7ef8110424915c268bc2",
+		public:    "0266874dc6ade47b3ecd096745ca09bcd29638dd52c2c12117b11ed3e458cfa9e8",
+	},
+	{
+		path:      "m/0'",
+		chainCode: "3460cea53e6a6bb5fb391eeef3237ffd8724bf0a40e94943c98b83825342ee11",
+		private:   "6939694369114c67917a182c59ddb8cafc3004e63ca5d3b84403ba8613debc0c",
+		public:    "0384610f5ecffe8fda089363a41f56a5c7ffc1d81b59a612d0d649b2d22355590c",
+	},
+	{
+		path:      "m/0'/1",
+		chainCode: "4187afff1aafa8445010097fb99d23aee9f599450c7bd140b6826ac22ba21d0c",
+		private:   "284e9d38d07d21e4e281b645089a94f4cf5a5a81369acf151a1c3a57f18b2129",
+		public:    "03526c63f8d0b4bbbf9c80df553fe66742df4676b241dabefdef67733e070f6844",
+	},
+}
+
+func newHDTestCSP(t *testing.T, pwd []byte) (bccsp.BCCSP, string) {
+	ksPath, err := ioutil.TempDir("", "bccsphd")
+	require.NoError(t, err)
+	ks, err := NewFileBasedKeyStore(pwd, ksPath, false)
+	require.NoError(t, err)
+	csp, err := NewDefaultSecurityLevelWithKeystore(ks)
+	require.NoError(t, err)
+	return csp, ksPath
+}
+
+func TestHDTestVectors(t *testing.T) {
+	t.Parallel()
+
+	csp, ksPath := newHDTestCSP(t, nil)
+	defer os.RemoveAll(ksPath)
+
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	require.NoError(t, err)
+	master, err := csp.KeyImport(seed, &bccsp.ECDSAP256HDMasterKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+
+	for _, vector := range hdTestVectors {
+		path, err := bccsp.ParseHDPath(vector.path)
+		require.NoError(t, err)
+
+		k, err := csp.KeyDeriv(master, &bccsp.ECDSAP256HDKeyDerivOpts{Temporary: true, Path: path})
+		require.NoError(t, err)
+
+		hdK := k.(*ecdsaHDPrivateKey)
+		assert.Equal(t, vector.chainCode, hex.EncodeToString(hdK.ChainCode()), vector.path)
+		assert.Equal(t, vector.private, hex.EncodeToString(hdPaddedBytes(hdK.privKey.D)), vector.path)
+		assert.Equal(t, vector.public, hex.EncodeToString(hdCompressedPoint(hdK.privKey.X, hdK.privKey.Y)), vector.path)
+		assert.Equal(t, master.SKI(), hdK.Root())
+		assert.Equal(t, path, hdK.Path())
+	}
+}
+
+func TestHDEncoding(t *testing.T) {
+	assert.Equal(t, append(make([]byte, 31), 0x01), hdPaddedBytes(big.NewInt(1)))
+
+	for i := 0; i < 10; i++ {
+		k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		uncompressed := elliptic.Marshal(k.Curve, k.X, k.Y)
+		compressed := hdCompressedPoint(k.X, k.Y)
+		require.Len(t, compressed, 33)
+		assert.Equal(t, uncompressed[1:33], compressed[1:])
+		assert.Equal(t, 0x02+uncompressed[64]&1, compressed[0])
+	}
+}
+
+func TestHDPublicDerivation(t *testing.T) {
+	t.Parallel()
+
+	csp, ksPath := newHDTestCSP(t, nil)
+	defer os.RemoveAll(ksPath)
+
+	master, err := csp.KeyImport([]byte("a master seed of thirty two byte"), &bccsp.ECDSAP256HDMasterKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+	account, err := csp.KeyDeriv(master, &bccsp.ECDSAP256HDKeyDerivOpts{Temporary: true, Path: []uint32{bccsp.HardenedKeyStart + 1}})
+	require.NoError(t, err)
+	accountPK, err := account.PublicKey()
+	require.NoError(t, err)
+
+	// Share the account public key and chain code with someone else
+	raw, err := accountPK.Bytes()
+	require.NoError(t, err)
+	imported, err := csp.KeyImport(raw, &bccsp.ECDSAP256HDPublicKeyImportOpts{Temporary: true, ChainCode: accountPK.(bccsp.HDKey).ChainCode()})
+	require.NoError(t, err)
+	assert.Equal(t, accountPK.SKI(), imported.SKI())
+
+	// Non-hardened children derived from the public key match the ones derived from the private key
+	digest := sha256.Sum256([]byte("a message"))
+	for i := uint32(0); i < 3; i++ {
+		pk, err := csp.KeyDeriv(imported, &bccsp.ECDSAP256HDKeyDerivOpts{Temporary: true, Path: []uint32{0, i}})
+		require.NoError(t, err)
+		sk, err := csp.KeyDeriv(account, &bccsp.ECDSAP256HDKeyDerivOpts{Temporary: true, Path: []uint32{0, i}})
+		require.NoError(t, err)
+		assert.Equal(t, sk.SKI(), pk.SKI())
+		assert.Equal(t, pk.(bccsp.HDKey).ChainCode(), sk.(bccsp.HDKey).ChainCode())
+
+		signature, err := csp.Sign(sk, digest[:], nil)
+		require.NoError(t, err)
+		valid, err := csp.Verify(pk, signature, digest[:], nil)
+		require.NoError(t, err)
+		assert.True(t, valid)
+		valid, err = csp.Verify(sk, signature, digest[:], nil)
+		require.NoError(t, err)
+		assert.True(t, valid)
+	}
+
+	// Hardened children cannot be derived from a public key
+	_, err = csp.KeyDeriv(imported, &bccsp.ECDSAP256HDKeyDerivOpts{Temporary: true, Path: []uint32{bccsp.HardenedKeyStart}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot derive hardened child [0] from a public key")
+}
+
+func TestHDKeyImportBad(t *testing.T) {
+	t.Parallel()
+
+	csp, ksPath := newHDTestCSP(t, nil)
+	defer os.RemoveAll(ksPath)
+
+	_, err := csp.KeyImport("seed", &bccsp.ECDSAP256HDMasterKeyImportOpts{Temporary: true})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid raw material. Expected byte array.")
+
+	_, err = csp.KeyImport([]byte("short"), &bccsp.ECDSAP256HDMasterKeyImportOpts{Temporary: true})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid seed length [5], it must be between 16 and 64 bytes")
+
+	k, err := csp.KeyGen(&bccsp.ECDSAP384KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	pk, err := k.PublicKey()
+	require.NoError(t, err)
+	raw, err := pk.Bytes()
+	require.NoError(t, err)
+	_, err = csp.KeyImport(raw, &bccsp.ECDSAP256HDPublicKeyImportOpts{Temporary: true, ChainCode: make([]byte, 32)})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Failed casting to ECDSA P-256 public key")
+
+	_, err = csp.KeyImport(raw, &bccsp.ECDSAP256HDPublicKeyImportOpts{Temporary: true, ChainCode: make([]byte, 16)})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid chain code length [16]. Must be 32 bytes")
+
+	// Plain ECDSA keys have no chain code to derive children from
+	k, err = csp.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	_, err = csp.KeyDeriv(k, &bccsp.ECDSAP256HDKeyDerivOpts{Temporary: true})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Unsupported 'KeyDerivOpts' provided")
+}
+
+func TestHDKeyStore(t *testing.T) {
+	t.Parallel()
+
+	for _, pwd := range [][]byte{nil, []byte("password")} {
+		csp, ksPath := newHDTestCSP(t, pwd)
+		defer os.RemoveAll(ksPath)
+
+		master, err := csp.KeyImport([]byte("a master seed of thirty two byte"), &bccsp.ECDSAP256HDMasterKeyImportOpts{})
+		require.NoError(t, err)
+		path := []uint32{bccsp.HardenedKeyStart + 44, 7}
+		derived, err := csp.KeyDeriv(master, &bccsp.ECDSAP256HDKeyDerivOpts{Path: path})
+		require.NoError(t, err)
+		derivedPK, err := derived.PublicKey()
+		require.NoError(t, err)
+		otherPath := []uint32{bccsp.HardenedKeyStart + 44, 8}
+		otherPK, err := csp.KeyDeriv(derivedPK, &bccsp.ECDSAP256HDKeyDerivOpts{Path: []uint32{3}})
+		require.NoError(t, err)
+
+		// Keys are retrieved by SKI with their chain code, root and path
+		k, err := csp.GetKey(derived.SKI())
+		require.NoError(t, err)
+		assert.Equal(t, derived, k)
+		k, err = csp.GetKey(otherPK.SKI())
+		require.NoError(t, err)
+		assert.Equal(t, otherPK, k)
+
+		// and by derivation path
+		k, err = csp.(*CSP).GetKeyByPath(master.SKI(), path)
+		require.NoError(t, err)
+		assert.Equal(t, derived, k)
+		k, err = csp.(*CSP).GetKeyByPath(master.SKI(), append(path, 3))
+		require.NoError(t, err)
+		assert.Equal(t, otherPK, k)
+		k, err = csp.(*CSP).GetKeyByPath(master.SKI(), []uint32{})
+		require.NoError(t, err)
+		assert.Equal(t, master, k)
+
+		// Keys not in the key store are derived from the master key
+		_, err = csp.(*CSP).ks.(bccsp.HDKeyStore).GetKeyByPath(master.SKI(), otherPath)
+		assert.Error(t, err)
+		k, err = csp.(*CSP).GetKeyByPath(master.SKI(), otherPath)
+		require.NoError(t, err)
+		assert.True(t, k.Private())
+		assert.Equal(t, otherPath, k.(bccsp.HDKey).Path())
+
+		_, err = csp.(*CSP).GetKeyByPath([]byte("unknown root"), otherPath)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "Failed getting master key")
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"justledger/bccsp"
+	"justledger/bccsp/utils"
+)
+
+// ecdsaHDPrivateKey is an ECDSA private key over P-256 that is part of
+// a hierarchical deterministic key tree. Besides the key itself it holds
+// the chain code used to derive its children, the SKI of the master key
+// of the tree and the path from the master key to this key.
+type ecdsaHDPrivateKey struct {
+	*ecdsaPrivateKey
+	chainCode []byte
+	root      []byte
+	path      []uint32
+}
+
+// ChainCode returns the chain code of this key
+func (k *ecdsaHDPrivateKey) ChainCode() []byte {
+	return utils.Clone(k.chainCode)
+}
+
+// Root returns the SKI of the master key this key was derived from
+func (k *ecdsaHDPrivateKey) Root() []byte {
+	return utils.Clone(k.root)
+}
+
+// Path returns the derivation path from the master key to this key
+func (k *ecdsaHDPrivateKey) Path() []uint32 {
+	return append([]uint32{}, k.path...)
+}
+
+// PublicKey returns the corresponding public key part of an asymmetric public/private key pair.
+// The public key keeps the chain code, so that non-hardened children can be derived from it.
+func (k *ecdsaHDPrivateKey) PublicKey() (bccsp.Key, error) {
+	return &ecdsaHDPublicKey{
+		ecdsaPublicKey: &ecdsaPublicKey{&k.privKey.PublicKey},
+		chainCode:      k.chainCode,
+		root:           k.root,
+		path:           k.path,
+	}, nil
+}
+
+// ecdsaHDPublicKey is the public part of an ecdsaHDPrivateKey.
+type ecdsaHDPublicKey struct {
+	*ecdsaPublicKey
+	chainCode []byte
+	root      []byte
+	path      []uint32
+}
+
+// ChainCode returns the chain code of this key
+func (k *ecdsaHDPublicKey) ChainCode() []byte {
+	return utils.Clone(k.chainCode)
+}
+
+// Root returns the SKI of the master key this key was derived from
+func (k *ecdsaHDPublicKey) Root() []byte {
+	return utils.Clone(k.root)
+}
+
+// Path returns the derivation path from the master key to this key
+func (k *ecdsaHDPublicKey) Path() []uint32 {
+	return append([]uint32{}, k.path...)
+}
+
+// PublicKey returns the corresponding public key part of an asymmetric public/private key pair.
+// This method returns an error in symmetric key schemes.
+func (k *ecdsaHDPublicKey) PublicKey() (bccsp.Key, error) {
+	return k, nil
+}
@@ -173,6 +173,28 @@ func (csp *CSP) GetKey(ski []byte) (k bccsp.Key, err error) {
 	return
 }
 
+// GetKeyByPath returns the hierarchical deterministic key derived along path
+// from the master key whose SKI is root. Keys that are not in the key store
+// are derived from the master key without being stored.
+func (csp *CSP) GetKeyByPath(root []byte, path []uint32) (k bccsp.Key, err error) {
+	if hdks, ok := csp.ks.(bccsp.HDKeyStore); ok {
+		k, err = hdks.GetKeyByPath(root, path)
+		if err == nil {
+			return k, nil
+		}
+	}
+
+	master, err := csp.GetKey(root)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed getting master key")
+	}
+	if _, ok := master.(bccsp.HDKey); !ok {
+		return nil, errors.Errorf("Key for SKI [%x] is not a hierarchical deterministic key", root)
+	}
+
+	return csp.KeyDeriv(master, &bccsp.ECDSAP256HDKeyDerivOpts{Temporary: true, Path: path})
+}
+
 // Hash hashes messages msg using options opts.
 func (csp *CSP) Hash(msg []byte, opts bccsp.HashOpts) (digest []byte, err error) {
 	// Validate arguments
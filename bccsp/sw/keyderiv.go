@@ -155,3 +155,73 @@ func (kd *aesPrivateKeyKeyDeriver) KeyDeriv(k bccsp.Key, opts bccsp.KeyDerivOpts
 		return nil, fmt.Errorf("Unsupported 'KeyDerivOpts' provided [%v]", opts)
 	}
 }
+
+type ecdsaHDPrivateKeyKeyDeriver struct{}
+
+func (kd *ecdsaHDPrivateKeyKeyDeriver) KeyDeriv(k bccsp.Key, opts bccsp.KeyDerivOpts) (bccsp.Key, error) {
+	// Validate opts
+	if opts == nil {
+		return nil, errors.New("Invalid opts parameter. It must not be nil.")
+	}
+
+	hdK := k.(*ecdsaHDPrivateKey)
+
+	switch opts.(type) {
+	// Derive a child along a path in the key tree
+	case *bccsp.ECDSAP256HDKeyDerivOpts:
+		hdOpts := opts.(*bccsp.ECDSAP256HDKeyDerivOpts)
+
+		privKey, chainCode := hdK.privKey, hdK.chainCode
+		for _, index := range hdOpts.Path {
+			var err error
+			privKey, chainCode, err = hdChildPrivateKey(privKey, chainCode, index)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return &ecdsaHDPrivateKey{
+			ecdsaPrivateKey: &ecdsaPrivateKey{privKey},
+			chainCode:       chainCode,
+			root:            hdK.root,
+			path:            append(hdK.Path(), hdOpts.Path...),
+		}, nil
+	default:
+		return nil, fmt.Errorf("Unsupported 'KeyDerivOpts' provided [%v]", opts)
+	}
+}
+
+type ecdsaHDPublicKeyKeyDeriver struct{}
+
+func (kd *ecdsaHDPublicKeyKeyDeriver) KeyDeriv(k bccsp.Key, opts bccsp.KeyDerivOpts) (bccsp.Key, error) {
+	// Validate opts
+	if opts == nil {
+		return nil, errors.New("Invalid opts parameter. It must not be nil.")
+	}
+
+	hdK := k.(*ecdsaHDPublicKey)
+
+	switch opts.(type) {
+	// Derive a non-hardened child along a path in the key tree
+	case *bccsp.ECDSAP256HDKeyDerivOpts:
+		hdOpts := opts.(*bccsp.ECDSAP256HDKeyDerivOpts)
+
+		pubKey, chainCode := hdK.pubKey, hdK.chainCode
+		for _, index := range hdOpts.Path {
+			var err error
+			pubKey, chainCode, err = hdChildPublicKey(pubKey, chainCode, index)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return &ecdsaHDPublicKey{
+			ecdsaPublicKey: &ecdsaPublicKey{pubKey},
+			chainCode:      chainCode,
+			root:           hdK.root,
+			path:           append(hdK.Path(), hdOpts.Path...),
+		}, nil
+	default:
+		return nil, fmt.Errorf("Unsupported 'KeyDerivOpts' provided [%v]", opts)
+	}
+}
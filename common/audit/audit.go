@@ -0,0 +1,316 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package audit keeps a tamper-evident trail of the administrative
+// operations performed on a node. Each entry is appended as a line of JSON
+// to a dedicated file and carries the hash of the previous entry, so that
+// modifying, removing or reordering entries breaks the hash chain.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"justledger/common/flogging"
+	cb "justledger/protos/common"
+	"justledger/protos/msp"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("audit")
+
+// Results of an audited operation
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+	// ResultSubmitted is the result of an operation that was accepted for
+	// asynchronous processing, whose outcome is not known yet
+	ResultSubmitted = "submitted"
+)
+
+// Recorder records administrative operations
+type Recorder interface {
+	// Record records that creator, a serialized identity, performed action on target.
+	// A non-nil err means the operation failed.
+	Record(creator []byte, action, target string, err error)
+
+	// RecordSubmitted records that creator, a serialized identity, submitted
+	// action on target for asynchronous processing.
+	RecordSubmitted(creator []byte, action, target string)
+}
+
+// NoopRecorder is a Recorder that does not record anything.
+// It is used when no audit log is configured.
+type NoopRecorder struct{}
+
+// Record does nothing
+func (NoopRecorder) Record(creator []byte, action, target string, err error) {}
+
+// RecordSubmitted does nothing
+func (NoopRecorder) RecordSubmitted(creator []byte, action, target string) {}
+
+// Entry is an entry of the audit log
+type Entry struct {
+	// Seq is the position of the entry in the log, starting from 1
+	Seq       uint64    `json:"seq"`
+	Timestamp time.Time `json:"timestamp"`
+	// MSPID is the MSP of the identity that performed the operation
+	MSPID string `json:"mspid"`
+	// CertHash is the hex-encoded SHA-256 hash of the certificate
+	// of the identity that performed the operation
+	CertHash string `json:"cert_hash"`
+	Action   string `json:"action"`
+	Target   string `json:"target"`
+	// Result is either ResultSuccess, ResultFailure or ResultSubmitted
+	Result string `json:"result"`
+	// Error describes why the operation failed, if it did
+	Error string `json:"error,omitempty"`
+	// PrevHash is the hash of the previous entry, or empty for the first entry
+	PrevHash string `json:"prev_hash"`
+	// Hash is the hex-encoded SHA-256 hash of the entry with an empty Hash
+	Hash string `json:"hash"`
+}
+
+// computeHash returns the hash of the entry, computed over its
+// JSON encoding with an empty Hash field
+func (e Entry) computeHash() (string, error) {
+	e.Hash = ""
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return "", errors.Wrap(err, "failed marshaling audit entry")
+	}
+	h := sha256.Sum256(raw)
+	return hex.EncodeToString(h[:]), nil
+}
+
+// Log is a Recorder that appends entries to a hash chained audit log file
+type Log struct {
+	mutex    sync.Mutex
+	file     *os.File
+	seq      uint64
+	lastHash string
+	// now is overridden by tests
+	now func() time.Time
+}
+
+// NewLog opens the audit log at path, creating it if it does not exist.
+// A partial last entry, left by a crash in the middle of an append, is
+// removed. The entries already in the log are verified, and new entries are
+// chained to the last of them.
+func NewLog(path string) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, errors.Wrapf(err, "failed creating directory of audit log %s", path)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed opening audit log %s", path)
+	}
+
+	if err := truncatePartialEntry(file, path); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	last, err := Verify(file)
+	if err != nil {
+		file.Close()
+		return nil, errors.WithMessage(err, "existing audit log "+path+" is invalid")
+	}
+
+	l := &Log{file: file, now: time.Now}
+	if last != nil {
+		l.seq = last.Seq
+		l.lastHash = last.Hash
+	}
+	logger.Infof("Recording administrative operations to audit log %s", path)
+	return l, nil
+}
+
+// Record appends an entry for the operation to the audit log. Failures
+// to write the entry are logged, as they must not fail the operation itself.
+func (l *Log) Record(creator []byte, action, target string, err error) {
+	entry := Entry{
+		Action: action,
+		Target: target,
+		Result: ResultSuccess,
+	}
+	if err != nil {
+		entry.Result = ResultFailure
+		entry.Error = err.Error()
+	}
+	l.record(creator, entry)
+}
+
+// RecordSubmitted appends an entry for the submitted operation to the audit log.
+// Failures to write the entry are logged, as they must not fail the operation itself.
+func (l *Log) RecordSubmitted(creator []byte, action, target string) {
+	l.record(creator, Entry{
+		Action: action,
+		Target: target,
+		Result: ResultSubmitted,
+	})
+}
+
+func (l *Log) record(creator []byte, entry Entry) {
+	entry.MSPID, entry.CertHash = identityOf(creator)
+	if err := l.append(entry); err != nil {
+		logger.Errorf("Failed recording %s of %s by %s to the audit log: %s", entry.Action, entry.Target, entry.MSPID, err)
+	}
+}
+
+func (l *Log) append(entry Entry) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	entry.Seq = l.seq + 1
+	entry.Timestamp = l.now().UTC()
+	entry.PrevHash = l.lastHash
+
+	var err error
+	entry.Hash, err = entry.computeHash()
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Wrap(err, "failed marshaling audit entry")
+	}
+
+	if _, err := l.file.Write(append(raw, '\n')); err != nil {
+		return errors.Wrap(err, "failed writing audit entry")
+	}
+	if err := l.file.Sync(); err != nil {
+		return errors.Wrap(err, "failed syncing audit log")
+	}
+
+	l.seq = entry.Seq
+	l.lastHash = entry.Hash
+	return nil
+}
+
+// Close closes the audit log file
+func (l *Log) Close() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.file.Close()
+}
+
+// truncatePartialEntry removes the bytes that follow the last complete line
+// of the audit log. Entries are written as whole lines and synced, so such
+// bytes can only be left by a crash in the middle of an append
+func truncatePartialEntry(file *os.File, path string) error {
+	info, err := file.Stat()
+	if err != nil {
+		return errors.Wrapf(err, "failed reading size of audit log %s", path)
+	}
+
+	size := info.Size()
+	end := size
+	buf := make([]byte, 4096)
+	for end > 0 {
+		n := int64(len(buf))
+		if end < n {
+			n = end
+		}
+		if _, err := file.ReadAt(buf[:n], end-n); err != nil {
+			return errors.Wrapf(err, "failed reading audit log %s", path)
+		}
+		if i := bytes.LastIndexByte(buf[:n], '\n'); i >= 0 {
+			end = end - n + int64(i) + 1
+			break
+		}
+		end -= n
+	}
+	if end == size {
+		return nil
+	}
+
+	logger.Warningf("Removing the partial last entry of audit log %s, of %d bytes, left by an interrupted append", path, size-end)
+	if err := file.Truncate(end); err != nil {
+		return errors.Wrapf(err, "failed removing the partial last entry of audit log %s", path)
+	}
+	return nil
+}
+
+// CreatorOf returns the serialized identity that signed env, to be
+// recorded as the creator of the operation it requests, or nil if it
+// cannot be extracted
+func CreatorOf(env *cb.Envelope) []byte {
+	sd, err := env.AsSignedData()
+	if err != nil || len(sd) == 0 {
+		return nil
+	}
+	return sd[0].Identity
+}
+
+// identityOf returns the MSP ID and the hex-encoded hash of the certificate
+// of a serialized identity. Identities that cannot be parsed are hashed as a whole.
+func identityOf(creator []byte) (string, string) {
+	sID := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(creator, sID); err != nil || len(sID.IdBytes) == 0 {
+		h := sha256.Sum256(creator)
+		return "", hex.EncodeToString(h[:])
+	}
+	h := sha256.Sum256(sID.IdBytes)
+	return sID.Mspid, hex.EncodeToString(h[:])
+}
+
+// Verify reads an audit log from r and checks that its entries are numbered
+// in sequence, that each of them is chained to the previous one and that
+// their hashes are intact. It returns the last entry of the log, or nil if
+// the log is empty.
+func Verify(r io.Reader) (*Entry, error) {
+	var last *Entry
+	reader := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		raw, err := reader.ReadBytes('\n')
+		if err == io.EOF && len(raw) == 0 {
+			return last, nil
+		}
+		if err == io.EOF {
+			return nil, errors.Errorf("line %d: entry is truncated", line)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d: failed reading entry", line)
+		}
+
+		entry := &Entry{}
+		if err := json.Unmarshal(raw, entry); err != nil {
+			return nil, errors.Wrapf(err, "line %d: malformed entry", line)
+		}
+
+		expectedSeq, expectedPrevHash := uint64(1), ""
+		if last != nil {
+			expectedSeq, expectedPrevHash = last.Seq+1, last.Hash
+		}
+		if entry.Seq != expectedSeq {
+			return nil, errors.Errorf("line %d: expected entry %d, found entry %d", line, expectedSeq, entry.Seq)
+		}
+		if entry.PrevHash != expectedPrevHash {
+			return nil, errors.Errorf("line %d: entry %d is not chained to the previous entry", line, entry.Seq)
+		}
+		hash, err := entry.computeHash()
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("line %d", line))
+		}
+		if entry.Hash != hash {
+			return nil, errors.Errorf("line %d: hash of entry %d does not match its content", line, entry.Seq)
+		}
+
+		last = entry
+	}
+}
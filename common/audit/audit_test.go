@@ -0,0 +1,254 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"justledger/protos/common"
+	"justledger/protos/msp"
+	"justledger/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2018, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestLog(t *testing.T) (*Log, string, func()) {
+	dir, err := ioutil.TempDir("", "audit")
+	require.NoError(t, err)
+	path := filepath.Join(dir, "logs", "audit.log")
+	l, err := NewLog(path)
+	require.NoError(t, err)
+	l.now = func() time.Time { return now }
+	return l, path, func() {
+		l.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func readLines(t *testing.T, path string) []string {
+	raw, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSuffix(string(raw), "\n"), "\n")
+}
+
+func TestRecord(t *testing.T) {
+	l, path, cleanup := newTestLog(t)
+	defer cleanup()
+
+	creator := utils.MarshalOrPanic(&msp.SerializedIdentity{Mspid: "SampleOrg", IdBytes: []byte("cert")})
+	l.Record(creator, "JoinChain", "mychannel", nil)
+	l.Record(creator, "JoinChain", "otherchannel", errors.New("access denied"))
+	l.RecordSubmitted(creator, "ConfigUpdate", "mychannel")
+
+	lines := readLines(t, path)
+	require.Len(t, lines, 3)
+
+	certHash := sha256.Sum256([]byte("cert"))
+	var first, second Entry
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+
+	assert.Equal(t, uint64(1), first.Seq)
+	assert.True(t, now.Equal(first.Timestamp))
+	assert.Equal(t, "SampleOrg", first.MSPID)
+	assert.Equal(t, hex.EncodeToString(certHash[:]), first.CertHash)
+	assert.Equal(t, "JoinChain", first.Action)
+	assert.Equal(t, "mychannel", first.Target)
+	assert.Equal(t, ResultSuccess, first.Result)
+	assert.Empty(t, first.Error)
+	assert.Empty(t, first.PrevHash)
+	assert.NotEmpty(t, first.Hash)
+
+	assert.Equal(t, uint64(2), second.Seq)
+	assert.Equal(t, ResultFailure, second.Result)
+	assert.Equal(t, "access denied", second.Error)
+	assert.Equal(t, first.Hash, second.PrevHash)
+
+	var third Entry
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &third))
+	assert.Equal(t, uint64(3), third.Seq)
+	assert.Equal(t, "ConfigUpdate", third.Action)
+	assert.Equal(t, ResultSubmitted, third.Result)
+	assert.Empty(t, third.Error)
+	assert.Equal(t, second.Hash, third.PrevHash)
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	last, err := Verify(f)
+	assert.NoError(t, err)
+	assert.Equal(t, &third, last)
+}
+
+func TestRecordUnparsableCreator(t *testing.T) {
+	l, path, cleanup := newTestLog(t)
+	defer cleanup()
+
+	l.Record([]byte{0xff}, "SetModuleLogLevel", "gossip=debug", nil)
+
+	var entry Entry
+	require.NoError(t, json.Unmarshal([]byte(readLines(t, path)[0]), &entry))
+	h := sha256.Sum256([]byte{0xff})
+	assert.Empty(t, entry.MSPID)
+	assert.Equal(t, hex.EncodeToString(h[:]), entry.CertHash)
+}
+
+func TestReopen(t *testing.T) {
+	l, path, cleanup := newTestLog(t)
+	defer cleanup()
+
+	l.Record(nil, "install", "mycc:1.0", nil)
+	require.NoError(t, l.Close())
+
+	l, err := NewLog(path)
+	require.NoError(t, err)
+	l.Record(nil, "install", "mycc:2.0", nil)
+	require.NoError(t, l.Close())
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	last, err := Verify(f)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), last.Seq)
+	assert.Equal(t, "mycc:2.0", last.Target)
+}
+
+func TestNewLogInvalid(t *testing.T) {
+	l, path, cleanup := newTestLog(t)
+	defer cleanup()
+
+	l.Record(nil, "install", "mycc:1.0", nil)
+	require.NoError(t, l.Close())
+	require.NoError(t, ioutil.WriteFile(path, []byte("garbage\n"), 0600))
+
+	_, err := NewLog(path)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "is invalid: line 1: malformed entry")
+}
+
+func TestNewLogPartialEntry(t *testing.T) {
+	l, path, cleanup := newTestLog(t)
+	defer cleanup()
+
+	l.Record(nil, "install", "mycc:1.0", nil)
+	l.Record(nil, "install", "mycc:2.0", nil)
+	require.NoError(t, l.Close())
+	complete, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	// a crash in the middle of an append leaves a partial last line
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"seq":3,"timestamp":`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	l, err = NewLog(path)
+	require.NoError(t, err)
+	raw, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, complete, raw)
+
+	l.Record(nil, "install", "mycc:3.0", nil)
+	require.NoError(t, l.Close())
+	f, err = os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	last, err := Verify(f)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), last.Seq)
+	assert.Equal(t, "mycc:3.0", last.Target)
+
+	// a partial first entry leaves an empty log
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"seq":1`), 0600))
+	l, err = NewLog(path)
+	require.NoError(t, err)
+	require.NoError(t, l.Close())
+	raw, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, raw)
+}
+
+func TestVerify(t *testing.T) {
+	l, path, cleanup := newTestLog(t)
+	defer cleanup()
+
+	for _, target := range []string{"a", "b", "c"} {
+		l.Record(nil, "JoinChain", target, nil)
+	}
+	lines := readLines(t, path)
+
+	join := func(lines ...string) string {
+		return strings.Join(lines, "\n") + "\n"
+	}
+	tampered := strings.Replace(lines[1], `"target":"b"`, `"target":"x"`, 1)
+	var entry Entry
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	entry.Target = "x"
+	entry.Hash, _ = entry.computeHash()
+	rehashed, err := json.Marshal(entry)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		log         string
+		expectedErr string
+	}{
+		{name: "Empty", log: ""},
+		{name: "Valid", log: join(lines...)},
+		{name: "Truncated", log: join(lines...)[:len(join(lines...))-5], expectedErr: "line 3: entry is truncated"},
+		{name: "Malformed", log: join(lines[0], "{", lines[2]), expectedErr: "line 2: malformed entry"},
+		{name: "Removed", log: join(lines[0], lines[2]), expectedErr: "line 2: expected entry 2, found entry 3"},
+		{name: "Reordered", log: join(lines[1], lines[0]), expectedErr: "line 1: expected entry 1, found entry 2"},
+		{name: "Tampered", log: join(lines[0], tampered, lines[2]), expectedErr: "line 2: hash of entry 2 does not match its content"},
+		{name: "Rehashed", log: join(lines[0], string(rehashed), lines[2]), expectedErr: "line 3: entry 3 is not chained to the previous entry"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Verify(bytes.NewBufferString(tt.log))
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, strings.HasPrefix(err.Error(), tt.expectedErr), err.Error())
+		})
+	}
+}
+
+func TestCreatorOf(t *testing.T) {
+	creator := utils.MarshalOrPanic(&msp.SerializedIdentity{Mspid: "SampleOrg", IdBytes: []byte("cert")})
+	env := &common.Envelope{
+		Payload: utils.MarshalOrPanic(&common.Payload{
+			Header: &common.Header{
+				SignatureHeader: utils.MarshalOrPanic(&common.SignatureHeader{Creator: creator}),
+			},
+		}),
+	}
+	assert.Equal(t, creator, CreatorOf(env))
+	assert.Nil(t, CreatorOf(nil))
+	assert.Nil(t, CreatorOf(&common.Envelope{Payload: []byte{1, 2, 3}}))
+}
+
+func TestNoopRecorder(t *testing.T) {
+	var r Recorder = NoopRecorder{}
+	r.Record(nil, "JoinChain", "mychannel", nil)
+	r.RecordSubmitted(nil, "ConfigUpdate", "mychannel")
+}
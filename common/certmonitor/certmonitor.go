@@ -0,0 +1,270 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package certmonitor tracks the expiration of the certificates a node
+// depends on, logs warnings as their expiration approaches and reports the
+// days left before they expire as metrics.
+package certmonitor
+
+import (
+	"crypto/x509"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"justledger/common/flogging"
+	"justledger/common/metrics"
+)
+
+var logger = flogging.MustGetLogger("certmonitor")
+
+// Kinds of certificates
+const (
+	KindEnrollment        = "enrollment"
+	KindTLSServer         = "tls server"
+	KindTLSClient         = "tls client"
+	KindCA                = "ca"
+	KindIntermediateCA    = "intermediate ca"
+	KindAdmin             = "admin"
+	KindTLSCA             = "tls ca"
+	KindTLSIntermediateCA = "tls intermediate ca"
+)
+
+const (
+	defaultCheckInterval = time.Hour
+	hoursPerDay          = 24
+	// expiredThreshold is the threshold reported once a certificate expired
+	expiredThreshold = time.Duration(0)
+)
+
+// DefaultWarningThresholds are the times before expiration at which
+// warnings are logged when none are configured
+var DefaultWarningThresholds = []time.Duration{30 * 24 * time.Hour, 7 * 24 * time.Hour, 24 * time.Hour}
+
+// Certificate is a certificate tracked by the monitor
+type Certificate struct {
+	// Kind is the role of the certificate, e.g. enrollment or tls server
+	Kind string
+	// Channel is the channel whose configuration carries the certificate, if any
+	Channel string
+	// MSPID is the MSP the certificate belongs to, if any
+	MSPID string
+	Cert  *x509.Certificate
+}
+
+// String describes the certificate in log messages
+func (c Certificate) String() string {
+	s := fmt.Sprintf("%s certificate [%s] (serial %s)", c.Kind, c.Cert.Subject.CommonName, c.Cert.SerialNumber)
+	if c.MSPID != "" {
+		s += fmt.Sprintf(" of MSP [%s]", c.MSPID)
+	}
+	if c.Channel != "" {
+		s += fmt.Sprintf(" in channel [%s]", c.Channel)
+	}
+	return s
+}
+
+func (c Certificate) key() string {
+	return fmt.Sprintf("%s|%s|%s|%x", c.Kind, c.Channel, c.MSPID, c.Cert.Raw)
+}
+
+// Status is the expiration status of a certificate at a point in time
+type Status struct {
+	Certificate
+	// TimeLeft is the time left before the certificate expires, negative
+	// if it has already expired
+	TimeLeft time.Duration
+	// Warning is true if the certificate expires within the largest threshold
+	Warning bool
+}
+
+// DaysLeft returns the number of days left before the certificate expires
+func (s Status) DaysLeft() float64 {
+	return s.TimeLeft.Hours() / hoursPerDay
+}
+
+// Expired returns true if the certificate has expired
+func (s Status) Expired() bool {
+	return s.TimeLeft <= 0
+}
+
+// Source returns the certificates to check
+type Source func() []Certificate
+
+// Config configures the monitor
+type Config struct {
+	// WarningThresholds are the times before expiration at which a warning
+	// is logged, each threshold being reported once per certificate
+	WarningThresholds []time.Duration
+	// CheckInterval is how often the certificates are checked
+	CheckInterval time.Duration
+}
+
+// Monitor periodically checks the expiration of the certificates returned
+// by its sources
+type Monitor struct {
+	thresholds []time.Duration
+	interval   time.Duration
+	sources    []Source
+	scope      metrics.Scope
+
+	lock sync.Mutex
+	// reported is the smallest threshold reported for each certificate
+	reported map[string]time.Duration
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewMonitor creates a monitor that reports to scope, which may be nil
+func NewMonitor(conf Config, scope metrics.Scope, sources ...Source) *Monitor {
+	thresholds := conf.WarningThresholds
+	if len(thresholds) == 0 {
+		thresholds = DefaultWarningThresholds
+	}
+	thresholds = append([]time.Duration(nil), thresholds...)
+	sort.Sort(sort.Reverse(durations(thresholds)))
+
+	interval := conf.CheckInterval
+	if interval <= 0 {
+		interval = defaultCheckInterval
+	}
+
+	return &Monitor{
+		thresholds: thresholds,
+		interval:   interval,
+		sources:    sources,
+		scope:      scope,
+		reported:   make(map[string]time.Duration),
+		stop:       make(chan struct{}),
+	}
+}
+
+// Start checks the certificates right away, and then periodically until
+// Stop is called
+func (m *Monitor) Start() {
+	m.Check(time.Now())
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.Check(time.Now())
+			case <-m.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic checks
+func (m *Monitor) Stop() {
+	m.stopOnce.Do(func() { close(m.stop) })
+}
+
+// Check checks the certificates at the given time, logs a warning for
+// every newly crossed threshold, updates the metrics and returns the
+// status of each certificate
+func (m *Monitor) Check(now time.Time) []Status {
+	statuses := Statuses(now, m.thresholds, m.certificates()...)
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, s := range statuses {
+		m.report(s)
+		if m.scope != nil {
+			m.scope.SubScope("certificate").Tagged(map[string]string{
+				"kind":    s.Kind,
+				"channel": s.Channel,
+				"mspid":   s.MSPID,
+				"serial":  s.Cert.SerialNumber.String(),
+			}).Gauge("days_to_expiry").Update(s.DaysLeft())
+		}
+	}
+	return statuses
+}
+
+func (m *Monitor) certificates() []Certificate {
+	var certs []Certificate
+	seen := make(map[string]bool)
+	for _, source := range m.sources {
+		for _, c := range source() {
+			if c.Cert == nil || seen[c.key()] {
+				continue
+			}
+			seen[c.key()] = true
+			certs = append(certs, c)
+		}
+	}
+	return certs
+}
+
+// report logs the status of a certificate if it crossed a threshold that
+// was not reported yet
+func (m *Monitor) report(s Status) {
+	threshold, crossed := crossedThreshold(s.TimeLeft, m.thresholds)
+	if !crossed {
+		delete(m.reported, s.key())
+		return
+	}
+	if last, reported := m.reported[s.key()]; reported && last <= threshold {
+		return
+	}
+	m.reported[s.key()] = threshold
+
+	if s.Expired() {
+		logger.Errorf("The %s expired on %s", s.Certificate, s.Cert.NotAfter.UTC().Format(time.RFC3339))
+		return
+	}
+	logger.Warningf("The %s expires on %s, in %.1f days", s.Certificate, s.Cert.NotAfter.UTC().Format(time.RFC3339), s.DaysLeft())
+}
+
+// crossedThreshold returns the smallest threshold that timeLeft is within,
+// or expiredThreshold if the certificate has expired
+func crossedThreshold(timeLeft time.Duration, thresholds []time.Duration) (time.Duration, bool) {
+	if timeLeft <= 0 {
+		return expiredThreshold, true
+	}
+	threshold, crossed := time.Duration(0), false
+	for _, t := range thresholds {
+		if timeLeft <= t {
+			threshold, crossed = t, true
+		}
+	}
+	return threshold, crossed
+}
+
+// Statuses returns the expiration status of the given certificates at the
+// given time, sorted by expiration
+func Statuses(now time.Time, thresholds []time.Duration, certs ...Certificate) []Status {
+	var largest time.Duration
+	for _, t := range thresholds {
+		if t > largest {
+			largest = t
+		}
+	}
+
+	statuses := make([]Status, 0, len(certs))
+	for _, c := range certs {
+		timeLeft := c.Cert.NotAfter.Sub(now)
+		statuses = append(statuses, Status{
+			Certificate: c,
+			TimeLeft:    timeLeft,
+			Warning:     timeLeft <= largest,
+		})
+	}
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return statuses[i].TimeLeft < statuses[j].TimeLeft
+	})
+	return statuses
+}
+
+type durations []time.Duration
+
+func (d durations) Len() int           { return len(d) }
+func (d durations) Less(i, j int) bool { return d[i] < d[j] }
+func (d durations) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
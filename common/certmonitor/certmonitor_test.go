@@ -0,0 +1,287 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package certmonitor
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"justledger/common/metrics"
+	"justledger/protos/utils"
+	cb "justledger/protos/common"
+	mspprotos "justledger/protos/msp"
+	"github.com/stretchr/testify/assert"
+)
+
+const day = 24 * time.Hour
+
+var now = time.Date(2018, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+func newCert(t *testing.T, cn string, serial int64, notAfter time.Time) (*x509.Certificate, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    notAfter.Add(-365 * day),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+type gauges struct {
+	sync.Mutex
+	values map[string]float64
+}
+
+type fakeScope struct {
+	metrics.Scope
+	gauges *gauges
+	name   string
+}
+
+func newFakeScope() *fakeScope {
+	return &fakeScope{gauges: &gauges{values: make(map[string]float64)}}
+}
+
+func (s *fakeScope) SubScope(name string) metrics.Scope {
+	return &fakeScope{gauges: s.gauges, name: s.name + name + "."}
+}
+
+func (s *fakeScope) Tagged(tags map[string]string) metrics.Scope {
+	return &fakeScope{gauges: s.gauges, name: s.name + tags["kind"] + "/" + tags["serial"] + "."}
+}
+
+func (s *fakeScope) Gauge(name string) metrics.Gauge {
+	return &fakeGauge{gauges: s.gauges, name: s.name + name}
+}
+
+type fakeGauge struct {
+	gauges *gauges
+	name   string
+}
+
+func (g *fakeGauge) Update(value float64) {
+	g.gauges.Lock()
+	defer g.gauges.Unlock()
+	g.gauges.values[g.name] = value
+}
+
+func TestMonitorCheck(t *testing.T) {
+	valid, _ := newCert(t, "valid", 1, now.Add(100*day))
+	soon, _ := newCert(t, "soon", 2, now.Add(10*day))
+	expired, _ := newCert(t, "expired", 3, now.Add(-day))
+
+	scope := newFakeScope()
+	m := NewMonitor(Config{WarningThresholds: []time.Duration{7 * day, 30 * day}}, scope, func() []Certificate {
+		return []Certificate{
+			{Kind: KindEnrollment, MSPID: "SampleOrg", Cert: valid},
+			{Kind: KindTLSServer, Cert: soon},
+			{Kind: KindCA, Channel: "mychannel", MSPID: "SampleOrg", Cert: expired},
+			{Kind: KindCA, Channel: "mychannel", MSPID: "SampleOrg", Cert: expired},
+			{Kind: KindAdmin},
+		}
+	})
+	assert.Equal(t, []time.Duration{30 * day, 7 * day}, m.thresholds)
+	assert.Equal(t, defaultCheckInterval, m.interval)
+
+	statuses := m.Check(now)
+	assert.Len(t, statuses, 3)
+	assert.Equal(t, "expired", statuses[0].Cert.Subject.CommonName)
+	assert.True(t, statuses[0].Expired())
+	assert.True(t, statuses[0].Warning)
+	assert.Equal(t, "soon", statuses[1].Cert.Subject.CommonName)
+	assert.False(t, statuses[1].Expired())
+	assert.True(t, statuses[1].Warning)
+	assert.Equal(t, 10.0, statuses[1].DaysLeft())
+	assert.Equal(t, "valid", statuses[2].Cert.Subject.CommonName)
+	assert.False(t, statuses[2].Warning)
+
+	assert.Equal(t, map[string]float64{
+		"certificate.enrollment/1.days_to_expiry": 100,
+		"certificate.tls server/2.days_to_expiry": 10,
+		"certificate.ca/3.days_to_expiry":         -1,
+	}, scope.gauges.values)
+
+	reported := func(c *x509.Certificate, kind, channel, mspID string) (time.Duration, bool) {
+		threshold, exists := m.reported[Certificate{Kind: kind, Channel: channel, MSPID: mspID, Cert: c}.key()]
+		return threshold, exists
+	}
+
+	// The certificate expiring in 10 days crossed the 30 days threshold
+	threshold, exists := reported(soon, KindTLSServer, "", "")
+	assert.True(t, exists)
+	assert.Equal(t, 30*day, threshold)
+	threshold, exists = reported(expired, KindCA, "mychannel", "SampleOrg")
+	assert.True(t, exists)
+	assert.Equal(t, expiredThreshold, threshold)
+	_, exists = reported(valid, KindEnrollment, "", "SampleOrg")
+	assert.False(t, exists)
+
+	// A few days later it crosses the 7 days threshold
+	m.Check(now.Add(4 * day))
+	threshold, _ = reported(soon, KindTLSServer, "", "")
+	assert.Equal(t, 7*day, threshold)
+	assert.Equal(t, 6.0, scope.gauges.values["certificate.tls server/2.days_to_expiry"])
+
+	// and then expires
+	m.Check(now.Add(11 * day))
+	threshold, _ = reported(soon, KindTLSServer, "", "")
+	assert.Equal(t, expiredThreshold, threshold)
+
+	// Once renewed, i.e. no longer within a threshold, it is forgotten
+	m.Check(now.Add(-100 * day))
+	_, exists = reported(soon, KindTLSServer, "", "")
+	assert.False(t, exists)
+}
+
+func TestMonitorStartStop(t *testing.T) {
+	cert, _ := newCert(t, "peer0", 1, time.Now().Add(day))
+	checked := make(chan struct{}, 10)
+	m := NewMonitor(Config{CheckInterval: 10 * time.Millisecond}, nil, func() []Certificate {
+		checked <- struct{}{}
+		return []Certificate{{Kind: KindEnrollment, Cert: cert}}
+	})
+	assert.Equal(t, DefaultWarningThresholds, m.thresholds)
+
+	m.Start()
+	for i := 0; i < 3; i++ {
+		select {
+		case <-checked:
+		case <-time.After(5 * time.Second):
+			t.Fatal("certificates were not checked")
+		}
+	}
+	m.Stop()
+	m.Stop()
+}
+
+func TestFromPEM(t *testing.T) {
+	_, pem1 := newCert(t, "first", 1, now)
+	_, pem2 := newCert(t, "second", 2, now)
+
+	certs, err := FromPEM(KindTLSCA, "", "SampleOrg", append(pem1, pem2...))
+	assert.NoError(t, err)
+	assert.Len(t, certs, 2)
+	assert.Equal(t, "first", certs[0].Cert.Subject.CommonName)
+	assert.Equal(t, "second", certs[1].Cert.Subject.CommonName)
+	assert.Equal(t, "SampleOrg", certs[1].MSPID)
+	assert.Equal(t, "tls ca certificate [second] (serial 2) of MSP [SampleOrg]", certs[1].String())
+
+	_, err = FromPEM(KindTLSCA, "", "", []byte("garbage"))
+	assert.EqualError(t, err, "no tls ca certificate found")
+
+	_, err = FromPEM(KindTLSCA, "", "", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("garbage")}))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed parsing tls ca certificate")
+}
+
+func TestFromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certmonitor")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, raw := newCert(t, "peer0", 1, now)
+	path := filepath.Join(dir, "server.crt")
+	assert.NoError(t, ioutil.WriteFile(path, raw, 0600))
+
+	certs, err := FromFile(KindTLSServer, "", "", path)
+	assert.NoError(t, err)
+	assert.Len(t, certs, 1)
+	assert.Equal(t, KindTLSServer, certs[0].Kind)
+
+	_, err = FromFile(KindTLSServer, "", "", filepath.Join(dir, "missing.crt"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed reading tls server certificate")
+}
+
+func TestFromSerializedIdentity(t *testing.T) {
+	_, raw := newCert(t, "peer0", 1, now)
+	sID := utils.MarshalOrPanic(&mspprotos.SerializedIdentity{Mspid: "SampleOrg", IdBytes: raw})
+
+	certs, err := FromSerializedIdentity(KindEnrollment, "", sID)
+	assert.NoError(t, err)
+	assert.Len(t, certs, 1)
+	assert.Equal(t, "SampleOrg", certs[0].MSPID)
+
+	_, err = FromSerializedIdentity(KindEnrollment, "", []byte("garbage"))
+	assert.Error(t, err)
+}
+
+func TestFromChannelConfig(t *testing.T) {
+	_, ca := newCert(t, "ca", 1, now)
+	_, admin := newCert(t, "admin", 2, now)
+	_, tlsCA := newCert(t, "tlsca", 3, now)
+
+	mspValue := func(mspConfig *mspprotos.MSPConfig) map[string]*cb.ConfigValue {
+		return map[string]*cb.ConfigValue{
+			"MSP": {Value: utils.MarshalOrPanic(mspConfig)},
+		}
+	}
+	fabricMSP := func(name string, config *mspprotos.FabricMSPConfig) *mspprotos.MSPConfig {
+		config.Name = name
+		return &mspprotos.MSPConfig{Type: 0, Config: utils.MarshalOrPanic(config)}
+	}
+
+	config := &cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				"Application": {
+					Groups: map[string]*cb.ConfigGroup{
+						"Org1": {Values: mspValue(fabricMSP("Org1MSP", &mspprotos.FabricMSPConfig{
+							RootCerts:    [][]byte{ca},
+							Admins:       [][]byte{admin},
+							TlsRootCerts: [][]byte{tlsCA},
+						}))},
+						"Idemix": {Values: mspValue(&mspprotos.MSPConfig{Type: 1, Config: []byte("idemix")})},
+					},
+				},
+				"Orderer": {
+					Groups: map[string]*cb.ConfigGroup{
+						"OrdererOrg": {Values: mspValue(fabricMSP("OrdererMSP", &mspprotos.FabricMSPConfig{
+							RootCerts: [][]byte{ca},
+						}))},
+					},
+				},
+			},
+		},
+	}
+
+	certs, err := FromChannelConfig("mychannel", config)
+	assert.NoError(t, err)
+	assert.Len(t, certs, 4)
+	var kinds []string
+	for _, c := range certs {
+		assert.Equal(t, "mychannel", c.Channel)
+		kinds = append(kinds, c.MSPID+"/"+c.Kind)
+	}
+	assert.Equal(t, []string{"Org1MSP/ca", "Org1MSP/admin", "Org1MSP/tls ca", "OrdererMSP/ca"}, kinds)
+
+	_, err = FromChannelConfig("mychannel", nil)
+	assert.EqualError(t, err, "no config for channel mychannel")
+
+	config.ChannelGroup.Groups["Orderer"].Groups["OrdererOrg"].Values = mspValue(fabricMSP("OrdererMSP", &mspprotos.FabricMSPConfig{
+		RootCerts: [][]byte{[]byte("garbage")},
+	}))
+	_, err = FromChannelConfig("mychannel", config)
+	assert.EqualError(t, err, "failed collecting the certificates of channel mychannel: invalid MSP OrdererMSP: no ca certificate found")
+}
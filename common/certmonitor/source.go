@@ -0,0 +1,139 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package certmonitor
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"sort"
+
+	"justledger/common/channelconfig"
+	"justledger/msp"
+	cb "justledger/protos/common"
+	mspprotos "justledger/protos/msp"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// FromPEM parses all the certificates in the given PEM bytes
+func FromPEM(kind, channel, mspID string, raw []byte) ([]Certificate, error) {
+	var certs []Certificate
+	for {
+		var block *pem.Block
+		block, raw = pem.Decode(raw)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed parsing %s certificate", kind)
+		}
+		certs = append(certs, Certificate{Kind: kind, Channel: channel, MSPID: mspID, Cert: cert})
+	}
+	if len(certs) == 0 {
+		return nil, errors.Errorf("no %s certificate found", kind)
+	}
+	return certs, nil
+}
+
+// FromFile parses all the certificates in the given PEM file
+func FromFile(kind, channel, mspID, path string) ([]Certificate, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed reading %s certificate", kind)
+	}
+	return FromPEM(kind, channel, mspID, raw)
+}
+
+// FromSerializedIdentity parses the certificate of a serialized identity
+func FromSerializedIdentity(kind, channel string, identityBytes []byte) ([]Certificate, error) {
+	sID := &mspprotos.SerializedIdentity{}
+	if err := proto.Unmarshal(identityBytes, sID); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshaling serialized identity")
+	}
+	return FromPEM(kind, channel, sID.Mspid, sID.IdBytes)
+}
+
+// FromChannelConfig returns the CA, intermediate CA, admin and TLS CA
+// certificates of the X.509 MSPs defined in the given channel config
+func FromChannelConfig(channel string, config *cb.Config) ([]Certificate, error) {
+	if config == nil || config.ChannelGroup == nil {
+		return nil, errors.Errorf("no config for channel %s", channel)
+	}
+	var certs []Certificate
+	err := walkGroup(config.ChannelGroup, func(mspConfig *mspprotos.MSPConfig) error {
+		mspCerts, err := FromMSPConfig(channel, mspConfig)
+		if err != nil {
+			return errors.WithMessage(err, "failed collecting the certificates of channel "+channel)
+		}
+		certs = append(certs, mspCerts...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return certs, nil
+}
+
+// FromMSPConfig returns the CA, intermediate CA, admin and TLS CA
+// certificates of the given MSP config, if it is an X.509 one
+func FromMSPConfig(channel string, mspConfig *mspprotos.MSPConfig) ([]Certificate, error) {
+	if mspConfig.Type != int32(msp.FABRIC) {
+		return nil, nil
+	}
+	fabricConfig := &mspprotos.FabricMSPConfig{}
+	if err := proto.Unmarshal(mspConfig.Config, fabricConfig); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshaling MSP config")
+	}
+	var certs []Certificate
+	for _, set := range []struct {
+		kind  string
+		certs [][]byte
+	}{
+		{KindCA, fabricConfig.RootCerts},
+		{KindIntermediateCA, fabricConfig.IntermediateCerts},
+		{KindAdmin, fabricConfig.Admins},
+		{KindTLSCA, fabricConfig.TlsRootCerts},
+		{KindTLSIntermediateCA, fabricConfig.TlsIntermediateCerts},
+	} {
+		for _, raw := range set.certs {
+			parsed, err := FromPEM(set.kind, channel, fabricConfig.Name, raw)
+			if err != nil {
+				return nil, errors.WithMessage(err, "invalid MSP "+fabricConfig.Name)
+			}
+			certs = append(certs, parsed...)
+		}
+	}
+	return certs, nil
+}
+
+func walkGroup(group *cb.ConfigGroup, f func(*mspprotos.MSPConfig) error) error {
+	if value, exists := group.Values[channelconfig.MSPKey]; exists {
+		mspConfig := &mspprotos.MSPConfig{}
+		if err := proto.Unmarshal(value.Value, mspConfig); err != nil {
+			return errors.Wrap(err, "failed unmarshaling MSP config value")
+		}
+		if err := f(mspConfig); err != nil {
+			return err
+		}
+	}
+	names := make([]string, 0, len(group.Groups))
+	for name := range group.Groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := walkGroup(group.Groups[name], f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ package channelconfig
 import (
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"justledger/core/config/configtest"
 	"justledger/msp"
 	mspprotos "justledger/protos/msp"
@@ -48,6 +49,26 @@ func TestMSPConfigManager(t *testing.T) {
 	}
 }
 
+func TestMSPConfigOnlineRevocation(t *testing.T) {
+	mspDir, err := configtest.GetDevMspDir()
+	assert.NoError(t, err)
+	conf, err := msp.GetVerifyingMspConfig(mspDir, "SampleOrg", msp.ProviderTypeToString(msp.FABRIC))
+	assert.NoError(t, err)
+
+	fabricConfig := &mspprotos.FabricMSPConfig{}
+	err = proto.Unmarshal(conf.Config, fabricConfig)
+	assert.NoError(t, err)
+	fabricConfig.OnlineRevocation = &mspprotos.FabricOnlineRevocation{Ocsp: true, CrlDistributionPoints: true}
+	conf.Config, err = proto.Marshal(fabricConfig)
+	assert.NoError(t, err)
+
+	// channel MSPs check the revocation status of certificates online at
+	// endorsement time
+	mspInst, err := NewMSPConfigHandler(msp.MSPv1_1).ProposeMSP(conf)
+	assert.NoError(t, err)
+	assert.Implements(t, (*msp.OnlineRevocationChecker)(nil), mspInst)
+}
+
 func TestMSPConfigFailure(t *testing.T) {
 	mspCH := NewMSPConfigHandler(msp.MSPv1_0)
 
@@ -0,0 +1,450 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package ocsp implements the subset of the Online Certificate Status
+// Protocol (RFC 6960) needed to check the revocation status of a
+// certificate, along with the creation of responses for local responders.
+package ocsp
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Certificate statuses reported by a responder
+const (
+	Good = iota
+	Revoked
+	Unknown
+)
+
+// Response is the status of a single certificate, as reported by a responder
+type Response struct {
+	Status       int
+	SerialNumber *big.Int
+	ThisUpdate   time.Time
+	// NextUpdate is zero if the responder does not provide it
+	NextUpdate time.Time
+	// RevokedAt is only set when Status is Revoked
+	RevokedAt time.Time
+}
+
+// Request identifies the certificate whose status is queried
+type Request struct {
+	HashAlgorithm  crypto.Hash
+	IssuerNameHash []byte
+	IssuerKeyHash  []byte
+	SerialNumber   *big.Int
+}
+
+var (
+	oidBasicResponse = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 1}
+
+	hashOIDs = map[crypto.Hash]asn1.ObjectIdentifier{
+		crypto.SHA1:   {1, 3, 14, 3, 2, 26},
+		crypto.SHA256: {2, 16, 840, 1, 101, 3, 4, 2, 1},
+	}
+
+	signatureAlgorithms = []struct {
+		algorithm x509.SignatureAlgorithm
+		oid       asn1.ObjectIdentifier
+	}{
+		{x509.ECDSAWithSHA256, asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}},
+		{x509.ECDSAWithSHA384, asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 3}},
+		{x509.ECDSAWithSHA512, asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 4}},
+		{x509.SHA256WithRSA, asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}},
+		{x509.SHA384WithRSA, asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 12}},
+		{x509.SHA512WithRSA, asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 13}},
+		{x509.PureEd25519, asn1.ObjectIdentifier{1, 3, 101, 112}},
+	}
+)
+
+// The ASN.1 structures of RFC 6960
+
+type certID struct {
+	HashAlgorithm pkix.AlgorithmIdentifier
+	NameHash      []byte
+	IssuerKeyHash []byte
+	SerialNumber  *big.Int
+}
+
+type singleRequest struct {
+	Cert certID
+}
+
+type tbsRequest struct {
+	Version     int `asn1:"explicit,tag:0,default:0,optional"`
+	RequestList []singleRequest
+}
+
+type ocspRequest struct {
+	TBSRequest tbsRequest
+}
+
+type responseASN1 struct {
+	Status   asn1.Enumerated
+	Response responseBytes `asn1:"explicit,tag:0,optional"`
+}
+
+type responseBytes struct {
+	ResponseType asn1.ObjectIdentifier
+	Response     []byte
+}
+
+type basicResponse struct {
+	TBSResponseData    responseData
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          asn1.BitString
+	Certificates       []asn1.RawValue `asn1:"explicit,tag:0,optional"`
+}
+
+type responseData struct {
+	Raw            asn1.RawContent
+	Version        int `asn1:"optional,default:0,explicit,tag:0"`
+	RawResponderID asn1.RawValue
+	ProducedAt     time.Time `asn1:"generalized"`
+	Responses      []singleResponse
+}
+
+type singleResponse struct {
+	CertID     certID
+	Good       asn1.Flag   `asn1:"tag:0,optional"`
+	Revoked    revokedInfo `asn1:"tag:1,optional"`
+	Unknown    asn1.Flag   `asn1:"tag:2,optional"`
+	ThisUpdate time.Time   `asn1:"generalized"`
+	NextUpdate time.Time   `asn1:"generalized,explicit,tag:0,optional"`
+}
+
+type revokedInfo struct {
+	RevocationTime time.Time `asn1:"generalized"`
+}
+
+type subjectPublicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// CreateRequest returns the DER encoding of a request for the status of cert,
+// which has been issued by issuer
+func CreateRequest(cert, issuer *x509.Certificate) ([]byte, error) {
+	id, err := newCertID(crypto.SHA1, cert.SerialNumber, issuer)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(ocspRequest{
+		TBSRequest: tbsRequest{
+			RequestList: []singleRequest{{Cert: *id}},
+		},
+	})
+}
+
+// ParseRequest parses the DER encoding of a request for the status of a single certificate
+func ParseRequest(der []byte) (*Request, error) {
+	req := &ocspRequest{}
+	rest, err := asn1.Unmarshal(der, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed parsing OCSP request")
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("trailing data in OCSP request")
+	}
+	if len(req.TBSRequest.RequestList) != 1 {
+		return nil, errors.Errorf("OCSP request must contain exactly one certificate, got %d", len(req.TBSRequest.RequestList))
+	}
+
+	id := req.TBSRequest.RequestList[0].Cert
+	hash, err := hashFromOID(id.HashAlgorithm.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	return &Request{
+		HashAlgorithm:  hash,
+		IssuerNameHash: id.NameHash,
+		IssuerKeyHash:  id.IssuerKeyHash,
+		SerialNumber:   id.SerialNumber,
+	}, nil
+}
+
+// Matches returns true if the request is for a certificate issued by issuer
+func (r *Request) Matches(issuer *x509.Certificate) bool {
+	id, err := newCertID(r.HashAlgorithm, r.SerialNumber, issuer)
+	if err != nil {
+		return false
+	}
+	return string(id.NameHash) == string(r.IssuerNameHash) && string(id.IssuerKeyHash) == string(r.IssuerKeyHash)
+}
+
+// ParseResponse parses the DER encoding of a response for the status of cert,
+// which has been issued by issuer. The response must be signed either by
+// issuer or by a responder certificate that issuer delegated OCSP signing to
+// and that is currently valid, and must not be dated in the future.
+func ParseResponse(der []byte, cert, issuer *x509.Certificate) (*Response, error) {
+	resp := &responseASN1{}
+	rest, err := asn1.Unmarshal(der, resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed parsing OCSP response")
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("trailing data in OCSP response")
+	}
+	if resp.Status != 0 {
+		return nil, errors.Errorf("OCSP responder returned status %d", resp.Status)
+	}
+	if !resp.Response.ResponseType.Equal(oidBasicResponse) {
+		return nil, errors.Errorf("unsupported OCSP response type %s", resp.Response.ResponseType)
+	}
+
+	basic := &basicResponse{}
+	if _, err := asn1.Unmarshal(resp.Response.Response, basic); err != nil {
+		return nil, errors.Wrap(err, "failed parsing basic OCSP response")
+	}
+
+	now := time.Now()
+	if err := checkResponseSignature(basic, issuer, now); err != nil {
+		return nil, err
+	}
+
+	for _, single := range basic.TBSResponseData.Responses {
+		if single.CertID.SerialNumber == nil || single.CertID.SerialNumber.Cmp(cert.SerialNumber) != 0 {
+			continue
+		}
+		hash, err := hashFromOID(single.CertID.HashAlgorithm.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		r := &Request{
+			HashAlgorithm:  hash,
+			IssuerNameHash: single.CertID.NameHash,
+			IssuerKeyHash:  single.CertID.IssuerKeyHash,
+			SerialNumber:   single.CertID.SerialNumber,
+		}
+		if !r.Matches(issuer) {
+			continue
+		}
+
+		if single.ThisUpdate.After(now) {
+			return nil, errors.Errorf("OCSP response is not valid yet, its thisUpdate is %s", single.ThisUpdate)
+		}
+
+		response := &Response{
+			SerialNumber: single.CertID.SerialNumber,
+			ThisUpdate:   single.ThisUpdate,
+			NextUpdate:   single.NextUpdate,
+		}
+		switch {
+		case bool(single.Good):
+			response.Status = Good
+		case bool(single.Unknown):
+			response.Status = Unknown
+		default:
+			response.Status = Revoked
+			response.RevokedAt = single.Revoked.RevocationTime
+		}
+		return response, nil
+	}
+
+	return nil, errors.Errorf("OCSP response does not contain the status of certificate %s", cert.SerialNumber)
+}
+
+// CreateResponse returns the DER encoding of a response carrying the status
+// in template. The response is signed with signer, which is the key of
+// responderCert. If responderCert is not issuer, it must be a certificate
+// issued by issuer for OCSP signing.
+func CreateResponse(issuer, responderCert *x509.Certificate, template Response, signer crypto.Signer) ([]byte, error) {
+	id, err := newCertID(crypto.SHA1, template.SerialNumber, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	single := singleResponse{
+		CertID:     *id,
+		ThisUpdate: template.ThisUpdate.UTC(),
+		NextUpdate: template.NextUpdate.UTC(),
+	}
+	switch template.Status {
+	case Good:
+		single.Good = true
+	case Unknown:
+		single.Unknown = true
+	case Revoked:
+		single.Revoked = revokedInfo{RevocationTime: template.RevokedAt.UTC()}
+	default:
+		return nil, errors.Errorf("invalid status %d", template.Status)
+	}
+
+	keyHash, err := publicKeyHash(crypto.SHA1, responderCert)
+	if err != nil {
+		return nil, err
+	}
+	responderID, err := asn1.Marshal(keyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	tbs, err := asn1.Marshal(responseData{
+		RawResponderID: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 2, IsCompound: true, Bytes: responderID},
+		ProducedAt:     time.Now().UTC().Truncate(time.Second),
+		Responses:      []singleResponse{single},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshaling response data")
+	}
+
+	sigAlg, hash, err := signatureAlgorithmFor(responderCert)
+	if err != nil {
+		return nil, err
+	}
+	digest := tbs
+	var opts crypto.SignerOpts = crypto.Hash(0)
+	if hash != 0 {
+		h := hash.New()
+		h.Write(tbs)
+		digest = h.Sum(nil)
+		opts = hash
+	}
+	signature, err := signer.Sign(rand.Reader, digest, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed signing response")
+	}
+
+	basic := basicResponse{
+		TBSResponseData:    responseData{Raw: tbs},
+		SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: sigAlg},
+		Signature:          asn1.BitString{Bytes: signature, BitLength: 8 * len(signature)},
+	}
+	if responderCert != issuer {
+		basic.Certificates = []asn1.RawValue{{FullBytes: responderCert.Raw}}
+	}
+	basicDER, err := asn1.Marshal(basic)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshaling basic response")
+	}
+
+	return asn1.Marshal(responseASN1{
+		Response: responseBytes{ResponseType: oidBasicResponse, Response: basicDER},
+	})
+}
+
+func checkResponseSignature(basic *basicResponse, issuer *x509.Certificate, now time.Time) error {
+	var sigAlg x509.SignatureAlgorithm = x509.UnknownSignatureAlgorithm
+	for _, alg := range signatureAlgorithms {
+		if alg.oid.Equal(basic.SignatureAlgorithm.Algorithm) {
+			sigAlg = alg.algorithm
+		}
+	}
+	if sigAlg == x509.UnknownSignatureAlgorithm {
+		return errors.Errorf("unsupported OCSP signature algorithm %s", basic.SignatureAlgorithm.Algorithm)
+	}
+
+	signer := issuer
+	if len(basic.Certificates) > 0 {
+		responderCert, err := x509.ParseCertificate(basic.Certificates[0].FullBytes)
+		if err != nil {
+			return errors.Wrap(err, "failed parsing OCSP responder certificate")
+		}
+		if !responderCert.Equal(issuer) {
+			if err := responderCert.CheckSignatureFrom(issuer); err != nil {
+				return errors.Wrap(err, "OCSP responder certificate is not issued by the certificate issuer")
+			}
+			if !hasOCSPSigning(responderCert) {
+				return errors.New("OCSP responder certificate is not authorized for OCSP signing")
+			}
+			if now.Before(responderCert.NotBefore) || now.After(responderCert.NotAfter) {
+				return errors.Errorf("OCSP responder certificate is not valid at %s, it is valid from %s to %s", now, responderCert.NotBefore, responderCert.NotAfter)
+			}
+			signer = responderCert
+		}
+	}
+
+	err := signer.CheckSignature(sigAlg, basic.TBSResponseData.Raw, basic.Signature.RightAlign())
+	return errors.Wrap(err, "invalid OCSP response signature")
+}
+
+func hasOCSPSigning(cert *x509.Certificate) bool {
+	for _, usage := range cert.ExtKeyUsage {
+		if usage == x509.ExtKeyUsageOCSPSigning {
+			return true
+		}
+	}
+	return false
+}
+
+func newCertID(hash crypto.Hash, serial *big.Int, issuer *x509.Certificate) (*certID, error) {
+	oid, ok := hashOIDs[hash]
+	if !ok || !hash.Available() {
+		return nil, errors.Errorf("unsupported hash function %s", hash)
+	}
+	keyHash, err := publicKeyHash(hash, issuer)
+	if err != nil {
+		return nil, err
+	}
+	h := hash.New()
+	h.Write(issuer.RawSubject)
+	return &certID{
+		HashAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oid, Parameters: asn1.NullRawValue},
+		NameHash:      h.Sum(nil),
+		IssuerKeyHash: keyHash,
+		SerialNumber:  serial,
+	}, nil
+}
+
+// publicKeyHash hashes the subjectPublicKey bit string of cert
+func publicKeyHash(hash crypto.Hash, cert *x509.Certificate) ([]byte, error) {
+	spki := &subjectPublicKeyInfo{}
+	if _, err := asn1.Unmarshal(cert.RawSubjectPublicKeyInfo, spki); err != nil {
+		return nil, errors.Wrap(err, "failed parsing public key")
+	}
+	switch hash {
+	case crypto.SHA1:
+		sum := sha1.Sum(spki.PublicKey.RightAlign())
+		return sum[:], nil
+	case crypto.SHA256:
+		sum := sha256.Sum256(spki.PublicKey.RightAlign())
+		return sum[:], nil
+	default:
+		return nil, errors.Errorf("unsupported hash function %s", hash)
+	}
+}
+
+func hashFromOID(oid asn1.ObjectIdentifier) (crypto.Hash, error) {
+	for hash, hashOID := range hashOIDs {
+		if hashOID.Equal(oid) {
+			return hash, nil
+		}
+	}
+	return 0, errors.Errorf("unsupported hash algorithm %s", oid)
+}
+
+// signatureAlgorithmFor picks the signature algorithm for the key of cert
+func signatureAlgorithmFor(cert *x509.Certificate) (asn1.ObjectIdentifier, crypto.Hash, error) {
+	var alg x509.SignatureAlgorithm
+	var hash crypto.Hash
+	switch cert.PublicKeyAlgorithm {
+	case x509.ECDSA:
+		alg, hash = x509.ECDSAWithSHA256, crypto.SHA256
+	case x509.RSA:
+		alg, hash = x509.SHA256WithRSA, crypto.SHA256
+	case x509.Ed25519:
+		alg = x509.PureEd25519
+	default:
+		return nil, 0, errors.Errorf("unsupported responder key algorithm %s", cert.PublicKeyAlgorithm)
+	}
+	for _, a := range signatureAlgorithms {
+		if a.algorithm == alg {
+			return a.oid, hash, nil
+		}
+	}
+	return nil, 0, errors.Errorf("unsupported signature algorithm %s", alg)
+}
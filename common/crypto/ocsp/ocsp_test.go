@@ -0,0 +1,166 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ocsp
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCert(t *testing.T, cn string, serial int64, isCA bool, usages []x509.ExtKeyUsage, parent *x509.Certificate, parentKey crypto.Signer) (*x509.Certificate, *ecdsa.PrivateKey) {
+	return newCertValidFrom(t, cn, serial, isCA, usages, parent, parentKey, time.Now().Add(-time.Hour))
+}
+
+func newCertValidFrom(t *testing.T, cn string, serial int64, isCA bool, usages []x509.ExtKeyUsage, parent *x509.Certificate, parentKey crypto.Signer, notBefore time.Time) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(2 * time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		ExtKeyUsage:           usages,
+	}
+	if isCA {
+		template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func TestRequestRoundTrip(t *testing.T) {
+	ca, caKey := newCert(t, "ca", 1, true, nil, nil, nil)
+	leaf, _ := newCert(t, "leaf", 42, false, nil, ca, caKey)
+	otherCA, _ := newCert(t, "other", 1, true, nil, nil, nil)
+
+	der, err := CreateRequest(leaf, ca)
+	require.NoError(t, err)
+
+	req, err := ParseRequest(der)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.SHA1, req.HashAlgorithm)
+	assert.Equal(t, int64(42), req.SerialNumber.Int64())
+	assert.True(t, req.Matches(ca))
+	assert.False(t, req.Matches(otherCA))
+
+	_, err = ParseRequest([]byte("garbage"))
+	assert.Contains(t, err.Error(), "failed parsing OCSP request")
+}
+
+func TestResponseRoundTrip(t *testing.T) {
+	ca, caKey := newCert(t, "ca", 1, true, nil, nil, nil)
+	leaf, _ := newCert(t, "leaf", 42, false, nil, ca, caKey)
+	now := time.Now().Truncate(time.Second)
+
+	for _, template := range []Response{
+		{Status: Good, SerialNumber: leaf.SerialNumber, ThisUpdate: now, NextUpdate: now.Add(time.Hour)},
+		{Status: Unknown, SerialNumber: leaf.SerialNumber, ThisUpdate: now},
+		{Status: Revoked, SerialNumber: leaf.SerialNumber, ThisUpdate: now, RevokedAt: now.Add(-time.Minute)},
+	} {
+		der, err := CreateResponse(ca, ca, template, caKey)
+		require.NoError(t, err)
+
+		resp, err := ParseResponse(der, leaf, ca)
+		require.NoError(t, err)
+		assert.Equal(t, template.Status, resp.Status)
+		assert.Equal(t, 0, template.SerialNumber.Cmp(resp.SerialNumber))
+		assert.True(t, template.ThisUpdate.Equal(resp.ThisUpdate))
+		assert.True(t, template.NextUpdate.Equal(resp.NextUpdate))
+		assert.True(t, template.RevokedAt.Equal(resp.RevokedAt))
+	}
+}
+
+func TestDelegatedResponder(t *testing.T) {
+	ca, caKey := newCert(t, "ca", 1, true, nil, nil, nil)
+	leaf, _ := newCert(t, "leaf", 42, false, nil, ca, caKey)
+	responder, responderKey := newCert(t, "responder", 2, false, []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning}, ca, caKey)
+	template := Response{Status: Good, SerialNumber: leaf.SerialNumber, ThisUpdate: time.Now()}
+
+	der, err := CreateResponse(ca, responder, template, responderKey)
+	require.NoError(t, err)
+	resp, err := ParseResponse(der, leaf, ca)
+	require.NoError(t, err)
+	assert.Equal(t, Good, resp.Status)
+
+	// A responder without the OCSP signing extended key usage is not trusted
+	notResponder, notResponderKey := newCert(t, "not-responder", 3, false, nil, ca, caKey)
+	der, err = CreateResponse(ca, notResponder, template, notResponderKey)
+	require.NoError(t, err)
+	_, err = ParseResponse(der, leaf, ca)
+	assert.EqualError(t, err, "OCSP responder certificate is not authorized for OCSP signing")
+
+	// A responder certified by another CA is not trusted
+	otherCA, otherCAKey := newCert(t, "other", 1, true, nil, nil, nil)
+	otherResponder, otherResponderKey := newCert(t, "responder", 2, false, []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning}, otherCA, otherCAKey)
+	der, err = CreateResponse(ca, otherResponder, template, otherResponderKey)
+	require.NoError(t, err)
+	_, err = ParseResponse(der, leaf, ca)
+	assert.Contains(t, err.Error(), "OCSP responder certificate is not issued by the certificate issuer")
+
+	// A responder certificate that expired or is not valid yet is not trusted
+	for _, notBefore := range []time.Time{time.Now().Add(-3 * time.Hour), time.Now().Add(time.Hour)} {
+		responder, responderKey := newCertValidFrom(t, "responder", 4, false, []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning}, ca, caKey, notBefore)
+		der, err = CreateResponse(ca, responder, template, responderKey)
+		require.NoError(t, err)
+		_, err = ParseResponse(der, leaf, ca)
+		assert.Contains(t, err.Error(), "OCSP responder certificate is not valid at")
+	}
+}
+
+func TestParseResponseErrors(t *testing.T) {
+	ca, caKey := newCert(t, "ca", 1, true, nil, nil, nil)
+	leaf, _ := newCert(t, "leaf", 42, false, nil, ca, caKey)
+	other, _ := newCert(t, "other-leaf", 43, false, nil, ca, caKey)
+	template := Response{Status: Good, SerialNumber: leaf.SerialNumber, ThisUpdate: time.Now()}
+
+	_, err := ParseResponse([]byte("garbage"), leaf, ca)
+	assert.Contains(t, err.Error(), "failed parsing OCSP response")
+
+	// Signed by a key other than the issuer's
+	_, otherKey := newCert(t, "ca", 1, true, nil, nil, nil)
+	der, err := CreateResponse(ca, ca, template, otherKey)
+	require.NoError(t, err)
+	_, err = ParseResponse(der, leaf, ca)
+	assert.Contains(t, err.Error(), "invalid OCSP response signature")
+
+	// The response is about another certificate
+	der, err = CreateResponse(ca, ca, template, caKey)
+	require.NoError(t, err)
+	_, err = ParseResponse(der, other, ca)
+	assert.EqualError(t, err, "OCSP response does not contain the status of certificate 43")
+
+	// The status is not valid yet
+	der, err = CreateResponse(ca, ca, Response{Status: Good, SerialNumber: leaf.SerialNumber, ThisUpdate: time.Now().Add(time.Hour)}, caKey)
+	require.NoError(t, err)
+	_, err = ParseResponse(der, leaf, ca)
+	assert.Contains(t, err.Error(), "OCSP response is not valid yet")
+
+	// An unsuccessful response, e.g. tryLater
+	_, err = ParseResponse([]byte{0x30, 0x03, 0x0a, 0x01, 0x03}, leaf, ca)
+	assert.EqualError(t, err, "OCSP responder returned status 3")
+
+	_, err = CreateResponse(ca, ca, Response{Status: 7, SerialNumber: leaf.SerialNumber}, caKey)
+	assert.EqualError(t, err, "invalid status 7")
+}
@@ -101,10 +101,12 @@ type Receiver interface {
 //go:generate counterfeiter -o mock/response_sender.go -fake-name ResponseSender . ResponseSender
 
 // ResponseSender defines the interface a handler must implement to send
-// responses.
+// responses. The block responses are given the channel and the chain the
+// block was read from, and the envelope of the deliver request, so that
+// they can be tailored to the requester.
 type ResponseSender interface {
 	SendStatusResponse(status cb.Status) error
-	SendBlockResponse(block *cb.Block) error
+	SendBlockResponse(block *cb.Block, channelID string, chain Chain, envelope *cb.Envelope) error
 }
 
 // Server is a polymorphic structure to support generalization of this handler
@@ -282,7 +284,7 @@ func (h *Handler) deliverBlocks(ctx context.Context, srv *Server, envelope *cb.E
 
 		logger.Debugf("[channel: %s] Delivering block for (%p) for %s", chdr.ChannelId, seekInfo, addr)
 
-		if err := srv.SendBlockResponse(block); err != nil {
+		if err := srv.SendBlockResponse(block, chdr.ChannelId, chain, envelope); err != nil {
 			logger.Warningf("[channel: %s] Error sending to %s: %s", chdr.ChannelId, addr, err)
 			return err
 		}
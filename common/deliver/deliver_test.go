@@ -235,6 +235,17 @@ var _ = Describe("Deliver", func() {
 			Expect(cid).To(Equal("chain-id"))
 		})
 
+		It("passes the channel, the chain and the request envelope to the response sender", func() {
+			err := handler.Handle(context.Background(), server)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(1))
+			_, cid, chain, e := fakeResponseSender.SendBlockResponseArgsForCall(0)
+			Expect(cid).To(Equal("chain-id"))
+			Expect(chain).To(Equal(fakeChain))
+			Expect(proto.Equal(e, envelope)).To(BeTrue())
+		})
+
 		It("gets a block iterator from the starting block", func() {
 			err := handler.Handle(context.Background(), server)
 			Expect(err).NotTo(HaveOccurred())
@@ -266,7 +277,7 @@ var _ = Describe("Deliver", func() {
 
 				Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(5))
 				for i := 0; i < 5; i++ {
-					b := fakeResponseSender.SendBlockResponseArgsForCall(i)
+					b, _, _, _ := fakeResponseSender.SendBlockResponseArgsForCall(i)
 					Expect(b).To(Equal(&cb.Block{
 						Header: &cb.BlockHeader{Number: 995 + uint64(i)},
 					}))
@@ -289,7 +300,7 @@ var _ = Describe("Deliver", func() {
 				Expect(fakeBlockIterator.NextCallCount()).To(Equal(1))
 
 				Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(1))
-				b := fakeResponseSender.SendBlockResponseArgsForCall(0)
+				b, _, _, _ := fakeResponseSender.SendBlockResponseArgsForCall(0)
 				Expect(b).To(Equal(&cb.Block{
 					Header: &cb.BlockHeader{Number: 100},
 				}))
@@ -320,7 +331,7 @@ var _ = Describe("Deliver", func() {
 				Expect(fakeBlockIterator.NextCallCount()).To(Equal(2))
 				Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(2))
 				for i := 0; i < fakeResponseSender.SendBlockResponseCallCount(); i++ {
-					b := fakeResponseSender.SendBlockResponseArgsForCall(i)
+					b, _, _, _ := fakeResponseSender.SendBlockResponseArgsForCall(i)
 					Expect(b).To(Equal(&cb.Block{
 						Header: &cb.BlockHeader{Number: uint64(i + 1)},
 					}))
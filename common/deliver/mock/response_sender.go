@@ -20,10 +20,13 @@ type ResponseSender struct {
 	sendStatusResponseReturnsOnCall map[int]struct {
 		result1 error
 	}
-	SendBlockResponseStub        func(block *cb.Block) error
+	SendBlockResponseStub        func(block *cb.Block, channelID string, chain deliver.Chain, envelope *cb.Envelope) error
 	sendBlockResponseMutex       sync.RWMutex
 	sendBlockResponseArgsForCall []struct {
-		block *cb.Block
+		block     *cb.Block
+		channelID string
+		chain     deliver.Chain
+		envelope  *cb.Envelope
 	}
 	sendBlockResponseReturns struct {
 		result1 error
@@ -83,16 +86,19 @@ func (fake *ResponseSender) SendStatusResponseReturnsOnCall(i int, result1 error
 	}{result1}
 }
 
-func (fake *ResponseSender) SendBlockResponse(block *cb.Block) error {
+func (fake *ResponseSender) SendBlockResponse(block *cb.Block, channelID string, chain deliver.Chain, envelope *cb.Envelope) error {
 	fake.sendBlockResponseMutex.Lock()
 	ret, specificReturn := fake.sendBlockResponseReturnsOnCall[len(fake.sendBlockResponseArgsForCall)]
 	fake.sendBlockResponseArgsForCall = append(fake.sendBlockResponseArgsForCall, struct {
-		block *cb.Block
-	}{block})
-	fake.recordInvocation("SendBlockResponse", []interface{}{block})
+		block     *cb.Block
+		channelID string
+		chain     deliver.Chain
+		envelope  *cb.Envelope
+	}{block, channelID, chain, envelope})
+	fake.recordInvocation("SendBlockResponse", []interface{}{block, channelID, chain, envelope})
 	fake.sendBlockResponseMutex.Unlock()
 	if fake.SendBlockResponseStub != nil {
-		return fake.SendBlockResponseStub(block)
+		return fake.SendBlockResponseStub(block, channelID, chain, envelope)
 	}
 	if specificReturn {
 		return ret.result1
@@ -106,10 +112,10 @@ func (fake *ResponseSender) SendBlockResponseCallCount() int {
 	return len(fake.sendBlockResponseArgsForCall)
 }
 
-func (fake *ResponseSender) SendBlockResponseArgsForCall(i int) *cb.Block {
+func (fake *ResponseSender) SendBlockResponseArgsForCall(i int) (*cb.Block, string, deliver.Chain, *cb.Envelope) {
 	fake.sendBlockResponseMutex.RLock()
 	defer fake.sendBlockResponseMutex.RUnlock()
-	return fake.sendBlockResponseArgsForCall[i].block
+	return fake.sendBlockResponseArgsForCall[i].block, fake.sendBlockResponseArgsForCall[i].channelID, fake.sendBlockResponseArgsForCall[i].chain, fake.sendBlockResponseArgsForCall[i].envelope
 }
 
 func (fake *ResponseSender) SendBlockResponseReturns(result1 error) {
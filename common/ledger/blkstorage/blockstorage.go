@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package blkstorage
 
 import (
+	"time"
+
 	"justledger/common/ledger"
 	l "justledger/core/ledger"
 	"justledger/protos/common"
@@ -25,6 +27,8 @@ const (
 	IndexableAttrBlockNumTranNum  = IndexableAttr("BlockNumTranNum")
 	IndexableAttrBlockTxID        = IndexableAttr("BlockTxID")
 	IndexableAttrTxValidationCode = IndexableAttr("TxValidationCode")
+	IndexableAttrBlockTime        = IndexableAttr("BlockTime")
+	IndexableAttrTxTimestamp      = IndexableAttr("TxTimestamp")
 )
 
 // IndexConfig - a configuration that includes a list of attributes that should be indexed
@@ -46,6 +50,8 @@ type BlockStoreProvider interface {
 	OpenBlockStore(ledgerid string) (BlockStore, error)
 	Exists(ledgerid string) (bool, error)
 	List() ([]string, error)
+	// Remove deletes the BlockStore with given id. The BlockStore should not be open
+	Remove(ledgerid string) error
 	Close()
 }
 
@@ -62,5 +68,12 @@ type BlockStore interface {
 	RetrieveTxByBlockNumTranNum(blockNum uint64, tranNum uint64) (*common.Envelope, error)
 	RetrieveBlockByTxID(txID string) (*common.Block, error)
 	RetrieveTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error)
+	// RetrieveBlocksByTimeRange returns the blocks committed in the time range [startTime, endTime) in the order of
+	// their commit time. At most pageSize blocks (all the blocks if pageSize is 0) are returned, starting from the
+	// given bookmark (empty for the first page), along with the bookmark of the next page (empty for the last page)
+	RetrieveBlocksByTimeRange(startTime, endTime time.Time, pageSize int, bookmark string) ([]*common.Block, string, error)
+	// RetrieveTxsByTimeRange returns the transactions whose channel header timestamp is in the time range
+	// [startTime, endTime) in the order of their timestamp. The paging is the same as in RetrieveBlocksByTimeRange
+	RetrieveTxsByTimeRange(startTime, endTime time.Time, pageSize int, bookmark string) ([]*peer.ProcessedTransaction, string, error)
 	Shutdown()
 }
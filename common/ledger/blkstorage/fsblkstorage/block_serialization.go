@@ -17,6 +17,8 @@ limitations under the License.
 package fsblkstorage
 
 import (
+	"time"
+
 	"github.com/golang/protobuf/proto"
 	ledgerutil "justledger/common/ledger/util"
 	"justledger/protos/common"
@@ -34,6 +36,8 @@ type txindexInfo struct {
 	txID        string
 	loc         *locPointer
 	isDuplicate bool
+	// timestamp is the timestamp in the channel header of the transaction, zero if not present
+	timestamp time.Time
 }
 
 func serializeBlock(block *common.Block) ([]byte, *serializedBlockInfo, error) {
@@ -112,14 +116,14 @@ func addDataBytes(blockData *common.BlockData, buf *proto.Buffer) ([]*txindexInf
 	}
 	for _, txEnvelopeBytes := range blockData.Data {
 		offset := len(buf.Bytes())
-		txid, err := extractTxID(txEnvelopeBytes)
+		txid, timestamp, err := extractTxIDAndTimestamp(txEnvelopeBytes)
 		if err != nil {
 			return nil, err
 		}
 		if err := buf.EncodeRawBytes(txEnvelopeBytes); err != nil {
 			return nil, err
 		}
-		idxInfo := &txindexInfo{txID: txid, loc: &locPointer{offset, len(buf.Bytes()) - offset}}
+		idxInfo := &txindexInfo{txID: txid, loc: &locPointer{offset, len(buf.Bytes()) - offset}, timestamp: timestamp}
 		txOffsets = append(txOffsets, idxInfo)
 	}
 	return txOffsets, nil
@@ -171,15 +175,16 @@ func extractData(buf *ledgerutil.Buffer) (*common.BlockData, []*txindexInfo, err
 	for i := uint64(0); i < numItems; i++ {
 		var txEnvBytes []byte
 		var txid string
+		var timestamp time.Time
 		txOffset := buf.GetBytesConsumed()
 		if txEnvBytes, err = buf.DecodeRawBytes(false); err != nil {
 			return nil, nil, err
 		}
-		if txid, err = extractTxID(txEnvBytes); err != nil {
+		if txid, timestamp, err = extractTxIDAndTimestamp(txEnvBytes); err != nil {
 			return nil, nil, err
 		}
 		data.Data = append(data.Data, txEnvBytes)
-		idxInfo := &txindexInfo{txID: txid, loc: &locPointer{txOffset, buf.GetBytesConsumed() - txOffset}}
+		idxInfo := &txindexInfo{txID: txid, loc: &locPointer{txOffset, buf.GetBytesConsumed() - txOffset}, timestamp: timestamp}
 		txOffsets = append(txOffsets, idxInfo)
 	}
 	return data, txOffsets, nil
@@ -203,17 +208,28 @@ func extractMetadata(buf *ledgerutil.Buffer) (*common.BlockMetadata, error) {
 }
 
 func extractTxID(txEnvelopBytes []byte) (string, error) {
+	txID, _, err := extractTxIDAndTimestamp(txEnvelopBytes)
+	return txID, err
+}
+
+// extractTxIDAndTimestamp returns the txid and the timestamp in the channel header of the transaction.
+// The returned timestamp is zero if the channel header does not carry one
+func extractTxIDAndTimestamp(txEnvelopBytes []byte) (string, time.Time, error) {
 	txEnvelope, err := utils.GetEnvelopeFromBlock(txEnvelopBytes)
 	if err != nil {
-		return "", err
+		return "", time.Time{}, err
 	}
 	txPayload, err := utils.GetPayload(txEnvelope)
 	if err != nil {
-		return "", nil
+		return "", time.Time{}, nil
 	}
 	chdr, err := utils.UnmarshalChannelHeader(txPayload.Header.ChannelHeader)
 	if err != nil {
-		return "", err
+		return "", time.Time{}, err
+	}
+	var timestamp time.Time
+	if chdr.Timestamp != nil {
+		timestamp = time.Unix(chdr.Timestamp.Seconds, int64(chdr.Timestamp.Nanos)).UTC()
 	}
-	return chdr.TxId, nil
+	return chdr.TxId, timestamp, nil
 }
@@ -25,7 +25,9 @@ var ErrUnexpectedEndOfBlockfile = errors.New("unexpected end of blockfile")
 // It starts from the given offset and can traverse till the end of the file
 type blockfileStream struct {
 	fileNum       int
+	filePath      string
 	file          *os.File
+	format        *blockfileFormat
 	reader        *bufio.Reader
 	currentOffset int64
 }
@@ -46,6 +48,10 @@ type blockPlacementInfo struct {
 	fileNum          int
 	blockStartOffset int64
 	blockBytesOffset int64
+	// txLocsRelativeToBlock is true if the block is stored in a block file of the checksummed format, where
+	// the serialized block does not lie as such in the file. The locations of the transactions are then
+	// relative to the serialized block instead of the file
+	txLocsRelativeToBlock bool
 }
 
 ///////////////////////////////////
@@ -59,6 +65,15 @@ func newBlockfileStream(rootDir string, fileNum int, startOffset int64) (*blockf
 	if file, err = os.OpenFile(filePath, os.O_RDONLY, 0600); err != nil {
 		return nil, errors.Wrapf(err, "error opening block file %s", filePath)
 	}
+	format, dataOffset, err := detectBlockfileFormat(filePath, file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	// the records of the block file start after the header, if any
+	if startOffset < dataOffset {
+		startOffset = dataOffset
+	}
 	var newPosition int64
 	if newPosition, err = file.Seek(startOffset, 0); err != nil {
 		return nil, errors.Wrapf(err, "error seeking block file [%s] to startOffset [%d]", filePath, startOffset)
@@ -67,10 +82,36 @@ func newBlockfileStream(rootDir string, fileNum int, startOffset int64) (*blockf
 		panic(fmt.Sprintf("Could not seek block file [%s] to startOffset [%d]. New position = [%d]",
 			filePath, startOffset, newPosition))
 	}
-	s := &blockfileStream{fileNum, file, bufio.NewReader(file), startOffset}
+	s := &blockfileStream{fileNum, filePath, file, format, bufio.NewReader(file), startOffset}
 	return s, nil
 }
 
+// detectBlockfileFormat returns the format of the given block file along with the offset of its first record.
+// A file that is empty or contains only a part of the header is treated as a file without any record
+func detectBlockfileFormat(filePath string, file *os.File) (*blockfileFormat, int64, error) {
+	firstBytes, err := readBlockfileFirstBytes(filePath, file)
+	if err != nil {
+		return nil, 0, err
+	}
+	format, incompleteHeader, err := readBlockfileFormat(filePath, firstBytes)
+	if err != nil {
+		return nil, 0, err
+	}
+	if incompleteHeader {
+		return legacyBlockfileFormat, int64(len(firstBytes)), nil
+	}
+	return format, format.dataOffset(), nil
+}
+
+func readBlockfileFirstBytes(filePath string, file *os.File) ([]byte, error) {
+	firstBytes := make([]byte, blockfileHeaderSize)
+	n, err := file.ReadAt(firstBytes, 0)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrapf(err, "error reading the header of block file %s", filePath)
+	}
+	return firstBytes[:n], nil
+}
+
 func (s *blockfileStream) nextBlockBytes() ([]byte, error) {
 	blockBytes, _, err := s.nextBlockBytesAndPlacementInfo()
 	return blockBytes, err
@@ -114,7 +155,8 @@ func (s *blockfileStream) nextBlockBytesAndPlacementInfo() ([]byte, *blockPlacem
 		}
 		panic(errors.Errorf("Error in decoding varint bytes [%#v]", lenBytes))
 	}
-	bytesExpected := int64(n) + int64(length)
+	trailerSize := s.format.trailerSize()
+	bytesExpected := int64(n) + int64(length) + int64(trailerSize)
 	if bytesExpected > remainingBytes {
 		logger.Debugf("At least [%d] bytes expected. Remaining bytes = [%d]. Returning with error [%s]",
 			bytesExpected, remainingBytes, ErrUnexpectedEndOfBlockfile)
@@ -124,16 +166,28 @@ func (s *blockfileStream) nextBlockBytesAndPlacementInfo() ([]byte, *blockPlacem
 	if _, err = s.reader.Discard(n); err != nil {
 		return nil, nil, errors.Wrapf(err, "error discarding [%d] bytes", n)
 	}
-	blockBytes := make([]byte, length)
-	if _, err = io.ReadAtLeast(s.reader, blockBytes, int(length)); err != nil {
-		logger.Errorf("Error reading [%d] bytes from file number [%d], error: %s", length, s.fileNum, err)
-		return nil, nil, errors.Wrapf(err, "error reading [%d] bytes from file number [%d]", length, s.fileNum)
+	recordBytes := make([]byte, int(length)+trailerSize)
+	if _, err = io.ReadAtLeast(s.reader, recordBytes, len(recordBytes)); err != nil {
+		logger.Errorf("Error reading [%d] bytes from file number [%d], error: %s", len(recordBytes), s.fileNum, err)
+		return nil, nil, errors.Wrapf(err, "error reading [%d] bytes from file number [%d]", len(recordBytes), s.fileNum)
+	}
+	blockBytes, err := s.format.decodePayload(recordBytes[:length], recordBytes[length:])
+	if err != nil {
+		corruptionErr := &BlockfileCorruptionError{
+			FilePath:   s.filePath,
+			Offset:     s.currentOffset,
+			Reason:     err.Error(),
+			lastRecord: bytesExpected == remainingBytes,
+		}
+		logger.Errorf("Error reading block from file number [%d]: %s", s.fileNum, corruptionErr)
+		return nil, nil, corruptionErr
 	}
 	blockPlacementInfo := &blockPlacementInfo{
-		fileNum:          s.fileNum,
-		blockStartOffset: s.currentOffset,
-		blockBytesOffset: s.currentOffset + int64(n)}
-	s.currentOffset += int64(n) + int64(length)
+		fileNum:               s.fileNum,
+		blockStartOffset:      s.currentOffset,
+		blockBytesOffset:      s.currentOffset + int64(n),
+		txLocsRelativeToBlock: !s.format.isLegacy()}
+	s.currentOffset += bytesExpected
 	logger.Debugf("Returning blockbytes - length=[%d], placementInfo={%s}", len(blockBytes), blockPlacementInfo)
 	return blockBytes, blockPlacementInfo, nil
 }
@@ -190,6 +244,10 @@ func (s *blockStream) nextBlockBytesAndPlacementInfo() ([]byte, *blockPlacementI
 }
 
 func (s *blockStream) close() error {
+	// the current file stream is nil if opening the next block file failed
+	if s.currentFileStream == nil {
+		return nil
+	}
 	return s.currentFileStream.close()
 }
 
@@ -0,0 +1,226 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+)
+
+// Block files come in two formats.
+//
+// The legacy format, used by the block files created by the previous releases, is a sequence of records
+// <varint length of the serialized block><serialized block>
+//
+// The checksummed format starts with a header that records the format version and the compression of the
+// blocks in the file and is followed by a sequence of records
+// <varint length of the payload><payload><crc32c of the payload, 4 bytes big endian>
+// where the payload is the serialized block, compressed as specified in the header. The header starts with a
+// zero byte, which never starts a file of the legacy format since a serialized block is never empty.
+//
+// The files of both formats are readable. The new block files are always created in the checksummed format,
+// while a block file of the legacy format keeps being appended in the legacy format
+const (
+	legacyBlockfileFormatVersion      = byte(0)
+	checksummedBlockfileFormatVersion = byte(1)
+	blockRecordChecksumSize           = 4
+)
+
+var blockfileMagic = []byte{0x00, 'J', 'L', 'B', 'F'}
+
+// blockfileHeaderSize is the size of the header of a block file of the checksummed format, i.e., the magic bytes
+// followed by the version of the format and the compression of the blocks
+var blockfileHeaderSize = len(blockfileMagic) + 2
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Compression identifies the algorithm used for compressing the blocks stored in a block file
+type Compression byte
+
+const (
+	// CompressionNone stores the serialized blocks as they are
+	CompressionNone Compression = iota
+	// CompressionSnappy compresses the serialized blocks with snappy
+	CompressionSnappy
+)
+
+var compressionNames = map[Compression]string{
+	CompressionNone:   "none",
+	CompressionSnappy: "snappy",
+}
+
+// ParseCompression returns the Compression for the given name - "none" (or empty), or "snappy"
+func ParseCompression(name string) (Compression, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return CompressionNone, nil
+	}
+	for compression, compressionName := range compressionNames {
+		if name == compressionName {
+			return compression, nil
+		}
+	}
+	return CompressionNone, errors.Errorf("unknown block file compression [%s]", name)
+}
+
+func (c Compression) String() string {
+	if name, ok := compressionNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", c)
+}
+
+func (c Compression) compress(b []byte) ([]byte, error) {
+	switch c {
+	case CompressionNone:
+		return b, nil
+	case CompressionSnappy:
+		return snappy.Encode(nil, b), nil
+	}
+	return nil, errors.Errorf("unknown block file compression [%s]", c)
+}
+
+func (c Compression) decompress(b []byte) ([]byte, error) {
+	switch c {
+	case CompressionNone:
+		return b, nil
+	case CompressionSnappy:
+		return snappy.Decode(nil, b)
+	}
+	return nil, errors.Errorf("unknown block file compression [%s]", c)
+}
+
+// blockfileFormat captures the format of a block file
+type blockfileFormat struct {
+	version     byte
+	compression Compression
+}
+
+var legacyBlockfileFormat = &blockfileFormat{version: legacyBlockfileFormatVersion}
+
+func newChecksummedBlockfileFormat(compression Compression) *blockfileFormat {
+	return &blockfileFormat{version: checksummedBlockfileFormatVersion, compression: compression}
+}
+
+func (f *blockfileFormat) isLegacy() bool {
+	return f.version == legacyBlockfileFormatVersion
+}
+
+// dataOffset returns the offset of the first record in a block file of this format
+func (f *blockfileFormat) dataOffset() int64 {
+	if f.isLegacy() {
+		return 0
+	}
+	return int64(blockfileHeaderSize)
+}
+
+func (f *blockfileFormat) header() []byte {
+	return append(append([]byte{}, blockfileMagic...), f.version, byte(f.compression))
+}
+
+// encodeRecord returns the record to be appended to a block file of this format for the given serialized block
+// along with the offset of the serialized block in the record. In the checksummed format, the serialized block
+// is not present as such in the record and the returned offset is -1
+func (f *blockfileFormat) encodeRecord(blockBytes []byte) ([]byte, int, error) {
+	if f.isLegacy() {
+		lenBytes := proto.EncodeVarint(uint64(len(blockBytes)))
+		return append(lenBytes, blockBytes...), len(lenBytes), nil
+	}
+	payload, err := f.compression.compress(blockBytes)
+	if err != nil {
+		return nil, 0, errors.WithMessage(err, "error compressing block")
+	}
+	lenBytes := proto.EncodeVarint(uint64(len(payload)))
+	record := make([]byte, 0, len(lenBytes)+len(payload)+blockRecordChecksumSize)
+	record = append(record, lenBytes...)
+	record = append(record, payload...)
+	record = append(record, make([]byte, blockRecordChecksumSize)...)
+	binary.BigEndian.PutUint32(record[len(record)-blockRecordChecksumSize:], crc32.Checksum(payload, crc32cTable))
+	return record, -1, nil
+}
+
+// trailerSize returns the number of bytes that follow the payload in a record of this format
+func (f *blockfileFormat) trailerSize() int {
+	if f.isLegacy() {
+		return 0
+	}
+	return blockRecordChecksumSize
+}
+
+// decodePayload verifies the checksum of the payload of a record of this format and returns the serialized block
+func (f *blockfileFormat) decodePayload(payload, trailer []byte) ([]byte, error) {
+	if f.isLegacy() {
+		return payload, nil
+	}
+	expectedChecksum := binary.BigEndian.Uint32(trailer)
+	if checksum := crc32.Checksum(payload, crc32cTable); checksum != expectedChecksum {
+		return nil, errors.Errorf("checksum mismatch: expected [%08x], computed [%08x]", expectedChecksum, checksum)
+	}
+	blockBytes, err := f.compression.decompress(payload)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error decompressing block with [%s]", f.compression)
+	}
+	return blockBytes, nil
+}
+
+func (f *blockfileFormat) String() string {
+	if f.isLegacy() {
+		return "legacy"
+	}
+	return fmt.Sprintf("checksummed(version=%d, compression=%s)", f.version, f.compression)
+}
+
+// readBlockfileFormat determines the format of a block file from its first bytes. The returned boolean is
+// true if the file is empty or contains only a part of the header of the checksummed format, which can happen
+// if a crash occurs while a new block file is being created
+func readBlockfileFormat(filePath string, firstBytes []byte) (*blockfileFormat, bool, error) {
+	if len(firstBytes) == 0 {
+		return nil, true, nil
+	}
+	if firstBytes[0] != blockfileMagic[0] {
+		return legacyBlockfileFormat, false, nil
+	}
+	if len(firstBytes) < blockfileHeaderSize {
+		return nil, true, nil
+	}
+	if !bytes.Equal(firstBytes[:len(blockfileMagic)], blockfileMagic) {
+		return nil, false, &BlockfileCorruptionError{FilePath: filePath, Offset: 0, Reason: "invalid block file header"}
+	}
+	version := firstBytes[len(blockfileMagic)]
+	compression := Compression(firstBytes[len(blockfileMagic)+1])
+	if version != checksummedBlockfileFormatVersion {
+		return nil, false, errors.Errorf("unsupported version [%d] of block file [%s]", version, filePath)
+	}
+	if _, ok := compressionNames[compression]; !ok {
+		return nil, false, errors.Errorf("unsupported compression [%d] of block file [%s]", compression, filePath)
+	}
+	return newChecksummedBlockfileFormat(compression), false, nil
+}
+
+// BlockfileCorruptionError is returned when the content of a block file is found corrupted
+type BlockfileCorruptionError struct {
+	// FilePath is the path of the corrupted block file
+	FilePath string
+	// Offset is the offset in the block file of the corrupted record
+	Offset int64
+	// Reason describes the detected corruption
+	Reason string
+	// lastRecord is true if the corrupted record is the last one of the file, which can be the result
+	// of a crash during the append of a block
+	lastRecord bool
+}
+
+func (e *BlockfileCorruptionError) Error() string {
+	return fmt.Sprintf("corrupted block file [%s] at offset [%d]: %s", e.FilePath, e.Offset, e.Reason)
+}
@@ -0,0 +1,315 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"justledger/common/ledger/testutil"
+	"justledger/common/ledger/util"
+	"justledger/protos/common"
+	putil "justledger/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCompression(t *testing.T) {
+	for name, expected := range map[string]Compression{
+		"":         CompressionNone,
+		"none":     CompressionNone,
+		"snappy":   CompressionSnappy,
+		" SNAPPY ": CompressionSnappy,
+		"zstd":     CompressionNone,
+		"unknown":  CompressionNone,
+	} {
+		compression, err := ParseCompression(name)
+		if name == "zstd" || name == "unknown" {
+			assert.EqualError(t, err, "unknown block file compression ["+name+"]")
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, expected, compression, name)
+	}
+	assert.Equal(t, "snappy", CompressionSnappy.String())
+	assert.Equal(t, "unknown(9)", Compression(9).String())
+}
+
+func TestBlockfileFormatRecords(t *testing.T) {
+	blockBytes := []byte("serialized block serialized block serialized block")
+	for _, format := range []*blockfileFormat{
+		legacyBlockfileFormat,
+		newChecksummedBlockfileFormat(CompressionNone),
+		newChecksummedBlockfileFormat(CompressionSnappy),
+	} {
+		t.Run(format.String(), func(t *testing.T) {
+			record, blockBytesOffset, err := format.encodeRecord(blockBytes)
+			require.NoError(t, err)
+			length, n := proto.DecodeVarint(record)
+			require.Equal(t, len(record), n+int(length)+format.trailerSize())
+			if format.isLegacy() {
+				assert.Equal(t, n, blockBytesOffset)
+			} else {
+				assert.Equal(t, -1, blockBytesOffset)
+			}
+			payload, trailer := record[n:n+int(length)], record[n+int(length):]
+			decodedBlockBytes, err := format.decodePayload(payload, trailer)
+			assert.NoError(t, err)
+			assert.Equal(t, blockBytes, decodedBlockBytes)
+
+			if format.isLegacy() {
+				return
+			}
+			payload[0] ^= 0xff
+			_, err = format.decodePayload(payload, trailer)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "checksum mismatch")
+		})
+	}
+}
+
+func TestReadBlockfileFormat(t *testing.T) {
+	header := newChecksummedBlockfileFormat(CompressionSnappy).header()
+	format, incompleteHeader, err := readBlockfileFormat("file", header)
+	assert.NoError(t, err)
+	assert.False(t, incompleteHeader)
+	assert.Equal(t, newChecksummedBlockfileFormat(CompressionSnappy), format)
+
+	format, incompleteHeader, err = readBlockfileFormat("file", proto.EncodeVarint(200))
+	assert.NoError(t, err)
+	assert.False(t, incompleteHeader)
+	assert.Equal(t, legacyBlockfileFormat, format)
+
+	for _, firstBytes := range [][]byte{nil, header[:1], header[:blockfileHeaderSize-1]} {
+		_, incompleteHeader, err = readBlockfileFormat("file", firstBytes)
+		assert.NoError(t, err)
+		assert.True(t, incompleteHeader)
+	}
+
+	_, _, err = readBlockfileFormat("file", []byte{0x00, 'X', 'L', 'B', 'F', 1, 0})
+	assert.EqualError(t, err, "corrupted block file [file] at offset [0]: invalid block file header")
+	_, _, err = readBlockfileFormat("file", append(append([]byte{}, blockfileMagic...), 2, 0))
+	assert.EqualError(t, err, "unsupported version [2] of block file [file]")
+	_, _, err = readBlockfileFormat("file", append(append([]byte{}, blockfileMagic...), 1, 9))
+	assert.EqualError(t, err, "unsupported compression [9] of block file [file]")
+}
+
+func TestBlockfileMgrWithCompression(t *testing.T) {
+	for _, compression := range []Compression{CompressionNone, CompressionSnappy} {
+		t.Run(compression.String(), func(t *testing.T) {
+			blocks := testutil.ConstructTestBlocks(t, 30)
+			env := newTestEnv(t, NewConfWithCompression(testPath(), 20000, compression))
+			defer func() { env.Cleanup() }()
+			ledgerid := "testLedger"
+			blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+			blkfileMgrWrapper.addBlocks(blocks)
+			assert.NotEqual(t, 0, blkfileMgrWrapper.blockfileMgr.cpInfo.latestFileChunkSuffixNum)
+			testRetrieveBlocksAndTxs(t, blkfileMgrWrapper.blockfileMgr, blocks)
+			blkfileMgrWrapper.close()
+
+			// every block file records its format in the header
+			rootDir := env.provider.conf.getLedgerBlockDir(ledgerid)
+			for fileNum := 0; fileNum <= blkfileMgrWrapper.blockfileMgr.cpInfo.latestFileChunkSuffixNum; fileNum++ {
+				b, err := ioutil.ReadFile(deriveBlockfilePath(rootDir, fileNum))
+				require.NoError(t, err)
+				assert.Equal(t, newChecksummedBlockfileFormat(compression).header(), b[:blockfileHeaderSize])
+			}
+
+			// rebuild the index from the block files
+			env.provider.Close()
+			os.RemoveAll(env.provider.conf.getIndexDir())
+			env = newTestEnv(t, NewConfWithCompression(env.provider.conf.blockStorageDir, 20000, compression))
+			blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+			defer blkfileMgrWrapper.close()
+			testRetrieveBlocksAndTxs(t, blkfileMgrWrapper.blockfileMgr, blocks)
+		})
+	}
+}
+
+func TestBlockfileMgrReadsLegacyBlockfiles(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 20)
+	env := newTestEnv(t, NewConfWithCompression(testPath(), 0, CompressionSnappy))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+
+	// write a block file in the legacy format, as the previous releases did
+	rootDir := env.provider.conf.getLedgerBlockDir(ledgerid)
+	_, err := util.CreateDirIfMissing(rootDir)
+	require.NoError(t, err)
+	var legacyFileBytes []byte
+	for _, block := range blocks[:10] {
+		blockBytes, _, err := serializeBlock(block)
+		require.NoError(t, err)
+		legacyFileBytes = append(legacyFileBytes, proto.EncodeVarint(uint64(len(blockBytes)))...)
+		legacyFileBytes = append(legacyFileBytes, blockBytes...)
+	}
+	require.NoError(t, ioutil.WriteFile(deriveBlockfilePath(rootDir, 0), legacyFileBytes, 0660))
+
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	assert.Equal(t, legacyBlockfileFormat, blkfileMgrWrapper.blockfileMgr.currentFileWriter.format)
+	testRetrieveBlocksAndTxs(t, blkfileMgrWrapper.blockfileMgr, blocks[:10])
+
+	// the blocks keep being appended in the legacy format to the legacy block file
+	blkfileMgrWrapper.addBlocks(blocks[10:15])
+	fileBytes, err := ioutil.ReadFile(deriveBlockfilePath(rootDir, 0))
+	require.NoError(t, err)
+	assert.Equal(t, legacyFileBytes, fileBytes[:len(legacyFileBytes)])
+	_, _, numBlocks, err := scanForLastCompleteBlock(rootDir, 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 15, numBlocks)
+
+	// a new block file is created in the checksummed format
+	blkfileMgrWrapper.blockfileMgr.moveToNextFile()
+	blkfileMgrWrapper.addBlocks(blocks[15:])
+	assert.Equal(t, newChecksummedBlockfileFormat(CompressionSnappy), blkfileMgrWrapper.blockfileMgr.currentFileWriter.format)
+	testRetrieveBlocksAndTxs(t, blkfileMgrWrapper.blockfileMgr, blocks)
+}
+
+func TestBlockfileMgrDetectsCorruption(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	env := newTestEnv(t, NewConfWithCompression(testPath(), 0, CompressionSnappy))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	blkfileMgrWrapper.addBlocks(blocks)
+	mgr := blkfileMgrWrapper.blockfileMgr
+
+	blockLoc, err := mgr.index.getBlockLocByBlockNum(5)
+	require.NoError(t, err)
+	filePath := deriveBlockfilePath(mgr.rootDir, 0)
+	flipByte(t, filePath, int64(blockLoc.offset+5))
+
+	_, err = mgr.retrieveBlockByNumber(5)
+	require.Error(t, err)
+	corruptionErr, ok := err.(*BlockfileCorruptionError)
+	require.True(t, ok)
+	assert.Equal(t, filePath, corruptionErr.FilePath)
+	assert.Equal(t, int64(blockLoc.offset), corruptionErr.Offset)
+	assert.Contains(t, err.Error(), "checksum mismatch")
+
+	txID, err := extractTxID(blocks[5].Data.Data[0])
+	require.NoError(t, err)
+	_, err = mgr.retrieveTransactionByID(txID)
+	assert.IsType(t, &BlockfileCorruptionError{}, err)
+
+	// the blocks stored before and after the corrupted one are still readable
+	_, err = mgr.retrieveBlockByNumber(4)
+	assert.NoError(t, err)
+	_, err = mgr.retrieveBlockByNumber(6)
+	assert.NoError(t, err)
+
+	// a corrupted block is not skipped while iterating
+	itr, err := mgr.retrieveBlocks(0)
+	require.NoError(t, err)
+	defer itr.Close()
+	for i := 0; i < 5; i++ {
+		_, err = itr.Next()
+		assert.NoError(t, err)
+	}
+	_, err = itr.Next()
+	assert.IsType(t, &BlockfileCorruptionError{}, err)
+}
+
+func TestBlockFileScanLastRecordCorrupted(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 5)
+	env := newTestEnv(t, NewConfWithCompression(testPath(), 0, CompressionSnappy))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+	blkfileMgrWrapper.addBlocks(blocks)
+	lastBlockLoc, err := blkfileMgrWrapper.blockfileMgr.index.getBlockLocByBlockNum(4)
+	require.NoError(t, err)
+	blkfileMgrWrapper.close()
+
+	// the corruption of the last record is treated as a block partially written during a crash
+	rootDir := env.provider.conf.getLedgerBlockDir(ledgerid)
+	flipByte(t, deriveBlockfilePath(rootDir, 0), int64(lastBlockLoc.offset+5))
+	lastBlockBytes, endOffset, numBlocks, err := scanForLastCompleteBlock(rootDir, 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, numBlocks)
+	assert.Equal(t, int64(lastBlockLoc.offset), endOffset)
+	expectedLastBlockBytes, _, err := serializeBlock(blocks[3])
+	require.NoError(t, err)
+	assert.Equal(t, expectedLastBlockBytes, lastBlockBytes)
+}
+
+func TestBlockfileWriterCompletesPartialHeader(t *testing.T) {
+	dir := testPath()
+	defer os.RemoveAll(dir)
+	filePath := deriveBlockfilePath(dir, 0)
+	format := newChecksummedBlockfileFormat(CompressionSnappy)
+	require.NoError(t, ioutil.WriteFile(filePath, format.header()[:3], 0660))
+
+	writer, err := newBlockfileWriter(filePath, format)
+	require.NoError(t, err)
+	defer writer.close()
+	assert.Equal(t, format, writer.format)
+	assert.Equal(t, blockfileHeaderSize, writer.dataOffset())
+	b, err := ioutil.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, format.header(), b)
+
+	// the header is never truncated
+	require.NoError(t, writer.truncateFile(0))
+	b, err = ioutil.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, format.header(), b)
+}
+
+func TestFileLocPointerEncoding(t *testing.T) {
+	legacyFLP := newFileLocationPointer(1, 100, &locPointer{offset: 10, bytesLength: 20})
+	b, err := legacyFLP.marshal()
+	require.NoError(t, err)
+	unmarshaledFLP := &fileLocPointer{}
+	require.NoError(t, unmarshaledFLP.unmarshal(b))
+	assert.Equal(t, legacyFLP, unmarshaledFLP)
+	assert.False(t, unmarshaledFLP.relativeToBlock)
+
+	relativeFLP := newBlockRelativeLocationPointer(1, 100, &locPointer{offset: 10, bytesLength: 20})
+	b, err = relativeFLP.marshal()
+	require.NoError(t, err)
+	unmarshaledFLP = &fileLocPointer{}
+	require.NoError(t, unmarshaledFLP.unmarshal(b))
+	assert.Equal(t, relativeFLP, unmarshaledFLP)
+	assert.Equal(t, "fileSuffixNum=1, blockOffset=100, offset=10, bytesLength=20", unmarshaledFLP.String())
+}
+
+func testRetrieveBlocksAndTxs(t *testing.T, mgr *blockfileMgr, blocks []*common.Block) {
+	for _, block := range blocks {
+		b, err := mgr.retrieveBlockByHash(block.Header.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, block, b)
+		for tranNum, txEnvelopeBytes := range block.Data.Data {
+			txEnvelope, err := putil.GetEnvelopeFromBlock(txEnvelopeBytes)
+			require.NoError(t, err)
+			txID, err := extractTxID(txEnvelopeBytes)
+			require.NoError(t, err)
+			txEnvelopeFromFileMgr, err := mgr.retrieveTransactionByID(txID)
+			require.NoError(t, err)
+			assert.Equal(t, txEnvelope, txEnvelopeFromFileMgr)
+			txEnvelopeFromFileMgr, err = mgr.retrieveTransactionByBlockNumTranNum(block.Header.Number, uint64(tranNum))
+			require.NoError(t, err)
+			assert.Equal(t, txEnvelope, txEnvelopeFromFileMgr)
+		}
+	}
+}
+
+func flipByte(t *testing.T, filePath string, offset int64) {
+	file, err := os.OpenFile(filePath, os.O_RDWR, 0660)
+	require.NoError(t, err)
+	defer file.Close()
+	b := make([]byte, 1)
+	_, err = file.ReadAt(b, offset)
+	require.NoError(t, err)
+	b[0] ^= 0xff
+	_, err = file.WriteAt(b, offset)
+	require.NoError(t, err)
+}
@@ -12,6 +12,7 @@ import (
 	"math"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/golang/protobuf/proto"
@@ -122,10 +123,17 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 
 	//Open a writer to the file identified by the number and truncate it to only contain the latest block
 	// that was completely saved (file system, index, cpinfo, etc)
-	currentFileWriter, err := newBlockfileWriter(deriveBlockfilePath(rootDir, cpInfo.latestFileChunkSuffixNum))
+	currentFileWriter, err := newBlockfileWriter(deriveBlockfilePath(rootDir, cpInfo.latestFileChunkSuffixNum), conf.newBlockfileFormat())
 	if err != nil {
 		panic(fmt.Sprintf("Could not open writer to current file: %s", err))
 	}
+	// A block file that gets created starts with a header
+	if cpInfo.latestFileChunksize < currentFileWriter.dataOffset() {
+		cpInfo.latestFileChunksize = currentFileWriter.dataOffset()
+		if err = mgr.saveCurrentInfo(cpInfo, true); err != nil {
+			panic(fmt.Sprintf("Could not save next block file info to db: %s", err))
+		}
+	}
 	//Truncate the file to remove excess past last block
 	err = currentFileWriter.truncateFile(cpInfo.latestFileChunksize)
 	if err != nil {
@@ -223,11 +231,12 @@ func (mgr *blockfileMgr) moveToNextFile() {
 		lastBlockNumber:          mgr.cpInfo.lastBlockNumber}
 
 	nextFileWriter, err := newBlockfileWriter(
-		deriveBlockfilePath(mgr.rootDir, cpInfo.latestFileChunkSuffixNum))
+		deriveBlockfilePath(mgr.rootDir, cpInfo.latestFileChunkSuffixNum), mgr.conf.newBlockfileFormat())
 
 	if err != nil {
 		panic(fmt.Sprintf("Could not open writer to next file: %s", err))
 	}
+	cpInfo.latestFileChunksize = nextFileWriter.dataOffset()
 	mgr.currentFileWriter.close()
 	err = mgr.saveCurrentInfo(cpInfo, true)
 	if err != nil {
@@ -265,22 +274,25 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 	txOffsets := info.txOffsets
 	currentOffset := mgr.cpInfo.latestFileChunksize
 
-	blockBytesLen := len(blockBytes)
-	blockBytesEncodedLen := proto.EncodeVarint(uint64(blockBytesLen))
-	totalBytesToAppend := blockBytesLen + len(blockBytesEncodedLen)
+	recordBytes, blockBytesOffsetInRecord, err := mgr.currentFileWriter.format.encodeRecord(blockBytes)
+	if err != nil {
+		return err
+	}
+	totalBytesToAppend := len(recordBytes)
 
 	//Determine if we need to start a new file since the size of this block
 	//exceeds the amount of space left in the current file
 	if currentOffset+totalBytesToAppend > mgr.conf.maxBlockfileSize {
 		mgr.moveToNextFile()
-		currentOffset = 0
-	}
-	//append blockBytesEncodedLen to the file
-	err = mgr.currentFileWriter.append(blockBytesEncodedLen, false)
-	if err == nil {
-		//append the actual block bytes to the file
-		err = mgr.currentFileWriter.append(blockBytes, true)
+		currentOffset = mgr.cpInfo.latestFileChunksize
+		// the new file may use a different format than the previous one
+		if recordBytes, blockBytesOffsetInRecord, err = mgr.currentFileWriter.format.encodeRecord(blockBytes); err != nil {
+			return err
+		}
+		totalBytesToAppend = len(recordBytes)
 	}
+	//append the record of the block, i.e., the length of the block followed by the block, to the file
+	err = mgr.currentFileWriter.append(recordBytes, true)
 	if err != nil {
 		truncateErr := mgr.currentFileWriter.truncateFile(mgr.cpInfo.latestFileChunksize)
 		if truncateErr != nil {
@@ -308,14 +320,18 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 	//Index block file location pointer updated with file suffex and offset for the new block
 	blockFLP := &fileLocPointer{fileSuffixNum: newCPInfo.latestFileChunkSuffixNum}
 	blockFLP.offset = currentOffset
-	// shift the txoffset because we prepend length of bytes before block bytes
-	for _, txOffset := range txOffsets {
-		txOffset.loc.offset += len(blockBytesEncodedLen)
+	txLocsRelativeToBlock := blockBytesOffsetInRecord < 0
+	if !txLocsRelativeToBlock {
+		// shift the txoffset because we prepend length of bytes before block bytes
+		for _, txOffset := range txOffsets {
+			txOffset.loc.offset += blockBytesOffsetInRecord
+		}
 	}
 	//save the index in the database
 	if err = mgr.index.indexBlock(&blockIdxInfo{
 		blockNum: block.Header.Number, blockHash: blockHash,
-		flp: blockFLP, txOffsets: txOffsets, metadata: block.Metadata}); err != nil {
+		flp: blockFLP, txOffsets: txOffsets, metadata: block.Metadata,
+		txLocsRelativeToBlock: txLocsRelativeToBlock, commitTime: time.Now().UTC()}); err != nil {
 		return err
 	}
 
@@ -402,9 +418,11 @@ func (mgr *blockfileMgr) syncIndex() error {
 
 		//The blockStartOffset will get applied to the txOffsets prior to indexing within indexBlock(),
 		//therefore just shift by the difference between blockBytesOffset and blockStartOffset
-		numBytesToShift := int(blockPlacementInfo.blockBytesOffset - blockPlacementInfo.blockStartOffset)
-		for _, offset := range info.txOffsets {
-			offset.loc.offset += numBytesToShift
+		if !blockPlacementInfo.txLocsRelativeToBlock {
+			numBytesToShift := int(blockPlacementInfo.blockBytesOffset - blockPlacementInfo.blockStartOffset)
+			for _, offset := range info.txOffsets {
+				offset.loc.offset += numBytesToShift
+			}
 		}
 
 		//Update the blockIndexInfo with what was actually stored in file system
@@ -414,6 +432,9 @@ func (mgr *blockfileMgr) syncIndex() error {
 			locPointer: locPointer{offset: int(blockPlacementInfo.blockStartOffset)}}
 		blockIdxInfo.txOffsets = info.txOffsets
 		blockIdxInfo.metadata = info.metadata
+		blockIdxInfo.txLocsRelativeToBlock = blockPlacementInfo.txLocsRelativeToBlock
+		// the commit time is not recorded in the block files
+		blockIdxInfo.commitTime = approximateCommitTime(info.txOffsets)
 
 		logger.Debugf("syncIndex() indexing block [%d]", blockIdxInfo.blockNum)
 		if err = mgr.index.indexBlock(blockIdxInfo); err != nil {
@@ -427,6 +448,21 @@ func (mgr *blockfileMgr) syncIndex() error {
 	return nil
 }
 
+// approximateCommitTime returns the time used as the commit time of a block that is indexed from the block files,
+// i.e., the latest timestamp of the transactions of the block or, if none of them carries a timestamp, the current time
+func approximateCommitTime(txOffsets []*txindexInfo) time.Time {
+	var commitTime time.Time
+	for _, txOffset := range txOffsets {
+		if txOffset.timestamp.After(commitTime) {
+			commitTime = txOffset.timestamp
+		}
+	}
+	if commitTime.IsZero() {
+		return time.Now().UTC()
+	}
+	return commitTime
+}
+
 func (mgr *blockfileMgr) getBlockchainInfo() *common.BlockchainInfo {
 	return mgr.bcInfo.Load().(*common.BlockchainInfo)
 }
@@ -489,6 +525,42 @@ func (mgr *blockfileMgr) retrieveTxValidationCodeByTxID(txID string) (peer.TxVal
 	return mgr.index.getTxValidationCodeByTxID(txID)
 }
 
+func (mgr *blockfileMgr) retrieveBlocksByTimeRange(startTime, endTime time.Time, pageSize int, bookmark string) ([]*common.Block, string, error) {
+	logger.Debugf("retrieveBlocksByTimeRange() - startTime = [%s], endTime = [%s], pageSize = [%d], bookmark = [%s]",
+		startTime, endTime, pageSize, bookmark)
+	locs, nextBookmark, err := mgr.index.getBlockLocsByTimeRange(startTime, endTime, pageSize, bookmark)
+	if err != nil {
+		return nil, "", err
+	}
+	var blocks []*common.Block
+	for _, loc := range locs {
+		block, err := mgr.fetchBlock(loc)
+		if err != nil {
+			return nil, "", err
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nextBookmark, nil
+}
+
+func (mgr *blockfileMgr) retrieveTxsByTimeRange(startTime, endTime time.Time, pageSize int, bookmark string) ([]*peer.ProcessedTransaction, string, error) {
+	logger.Debugf("retrieveTxsByTimeRange() - startTime = [%s], endTime = [%s], pageSize = [%d], bookmark = [%s]",
+		startTime, endTime, pageSize, bookmark)
+	entries, nextBookmark, err := mgr.index.getTxEntriesByTimeRange(startTime, endTime, pageSize, bookmark)
+	if err != nil {
+		return nil, "", err
+	}
+	var txs []*peer.ProcessedTransaction
+	for _, entry := range entries {
+		txEnvelope, err := mgr.fetchTransactionEnvelope(entry.txLoc)
+		if err != nil {
+			return nil, "", err
+		}
+		txs = append(txs, &peer.ProcessedTransaction{TransactionEnvelope: txEnvelope, ValidationCode: int32(entry.validationCode)})
+	}
+	return txs, nextBookmark, nil
+}
+
 func (mgr *blockfileMgr) retrieveBlockHeaderByNumber(blockNum uint64) (*common.BlockHeader, error) {
 	logger.Debugf("retrieveBlockHeaderByNumber() - blockNum = [%d]", blockNum)
 	loc, err := mgr.index.getBlockLocByBlockNum(blockNum)
@@ -565,6 +637,16 @@ func (mgr *blockfileMgr) fetchBlockBytes(lp *fileLocPointer) ([]byte, error) {
 }
 
 func (mgr *blockfileMgr) fetchRawBytes(lp *fileLocPointer) ([]byte, error) {
+	if lp.relativeToBlock {
+		blockBytes, err := mgr.fetchBlockBytes(&fileLocPointer{fileSuffixNum: lp.fileSuffixNum, locPointer: locPointer{offset: lp.blockOffset}})
+		if err != nil {
+			return nil, err
+		}
+		if blockBytes == nil || lp.offset+lp.bytesLength > len(blockBytes) {
+			return nil, errors.Errorf("location [%s] is out of the block", lp)
+		}
+		return blockBytes[lp.offset : lp.offset+lp.bytesLength], nil
+	}
 	filePath := deriveBlockfilePath(mgr.rootDir, lp.fileSuffixNum)
 	reader, err := newBlockfileReader(filePath)
 	if err != nil {
@@ -625,6 +707,11 @@ func scanForLastCompleteBlock(rootDir string, fileNum int, startingOffset int64)
 		lastBlockBytes = blockBytes
 		numBlocks++
 	}
+	if corruptionErr, ok := errRead.(*BlockfileCorruptionError); ok && corruptionErr.lastRecord {
+		// the checksum of the last record does not match, the block has not been completely written
+		logger.Warningf("Ignoring the last record of block file [%s]: %s", corruptionErr.FilePath, corruptionErr)
+		errRead = ErrUnexpectedEndOfBlockfile
+	}
 	if errRead == ErrUnexpectedEndOfBlockfile {
 		logger.Debugf(`Error:%s
 		The error may happen if a crash has happened during block appending.
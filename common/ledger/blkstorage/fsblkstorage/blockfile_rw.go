@@ -16,11 +16,50 @@ import (
 type blockfileWriter struct {
 	filePath string
 	file     *os.File
+	format   *blockfileFormat
 }
 
-func newBlockfileWriter(filePath string) (*blockfileWriter, error) {
+// newBlockfileWriter opens a writer to the given block file. If the file does not exist yet (or contains only a
+// part of a header because of a crash during its creation), the file is created in the given format. Otherwise,
+// the blocks keep being appended in the format of the existing file
+func newBlockfileWriter(filePath string, newFileFormat *blockfileFormat) (*blockfileWriter, error) {
 	writer := &blockfileWriter{filePath: filePath}
-	return writer, writer.open()
+	if err := writer.open(); err != nil {
+		return nil, err
+	}
+	if err := writer.initFormat(newFileFormat); err != nil {
+		writer.close()
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (w *blockfileWriter) initFormat(newFileFormat *blockfileFormat) error {
+	firstBytes, err := readBlockfileFirstBytes(w.filePath, w.file)
+	if err != nil {
+		return err
+	}
+	format, incompleteHeader, err := readBlockfileFormat(w.filePath, firstBytes)
+	if err != nil {
+		return err
+	}
+	if incompleteHeader {
+		if err := w.file.Truncate(0); err != nil {
+			return errors.Wrapf(err, "error truncating block file %s", w.filePath)
+		}
+		if err := w.append(newFileFormat.header(), true); err != nil {
+			return errors.Wrapf(err, "error writing the header of block file %s", w.filePath)
+		}
+		format = newFileFormat
+	}
+	logger.Debugf("Block file [%s] uses the %s format", w.filePath, format)
+	w.format = format
+	return nil
+}
+
+// dataOffset returns the offset of the first block in the file, i.e., the size of the file without any block
+func (w *blockfileWriter) dataOffset() int {
+	return int(w.format.dataOffset())
 }
 
 func (w *blockfileWriter) truncateFile(targetSize int) error {
@@ -28,6 +67,10 @@ func (w *blockfileWriter) truncateFile(targetSize int) error {
 	if err != nil {
 		return err
 	}
+	// never truncate the header of the file
+	if targetSize < w.dataOffset() {
+		targetSize = w.dataOffset()
+	}
 	if fileStat.Size() > int64(targetSize) {
 		w.file.Truncate(int64(targetSize))
 	}
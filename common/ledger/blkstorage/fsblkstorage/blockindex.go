@@ -9,6 +9,7 @@ package fsblkstorage
 import (
 	"bytes"
 	"fmt"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"justledger/common/ledger/blkstorage"
@@ -27,6 +28,9 @@ const (
 	blockNumTranNumIdxKeyPrefix    = 'a'
 	blockTxIDIdxKeyPrefix          = 'b'
 	txValidationResultIdxKeyPrefix = 'v'
+	blockTimeIdxKeyPrefix          = 'c'
+	blockNumTimeIdxKeyPrefix       = 'd'
+	txTimestampIdxKeyPrefix        = 's'
 	indexCheckpointKeyStr          = "indexCheckpointKey"
 )
 
@@ -42,6 +46,8 @@ type index interface {
 	getTXLocByBlockNumTranNum(blockNum uint64, tranNum uint64) (*fileLocPointer, error)
 	getBlockLocByTxID(txID string) (*fileLocPointer, error)
 	getTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error)
+	getBlockLocsByTimeRange(startTime, endTime time.Time, pageSize int, bookmark string) ([]*fileLocPointer, string, error)
+	getTxEntriesByTimeRange(startTime, endTime time.Time, pageSize int, bookmark string) ([]*txTimeIndexEntry, string, error)
 }
 
 type blockIdxInfo struct {
@@ -50,6 +56,19 @@ type blockIdxInfo struct {
 	flp       *fileLocPointer
 	txOffsets []*txindexInfo
 	metadata  *common.BlockMetadata
+	// txLocsRelativeToBlock is true if the txOffsets are relative to the serialized block
+	// instead of the block file - see blockPlacementInfo
+	txLocsRelativeToBlock bool
+	// commitTime is the time at which the block is committed
+	commitTime time.Time
+}
+
+func (blockIdxInfo *blockIdxInfo) txLoc(txoffset *txindexInfo) *fileLocPointer {
+	flp := blockIdxInfo.flp
+	if blockIdxInfo.txLocsRelativeToBlock {
+		return newBlockRelativeLocationPointer(flp.fileSuffixNum, flp.offset, txoffset.loc)
+	}
+	return newFileLocationPointer(flp.fileSuffixNum, flp.offset, txoffset.loc)
 }
 
 type blockIndex struct {
@@ -124,7 +143,7 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 				logger.Debugf("txid [%s] is a duplicate of a previous tx. Not indexing in txid-index", txoffset.txID)
 				continue
 			}
-			txFlp := newFileLocationPointer(flp.fileSuffixNum, flp.offset, txoffset.loc)
+			txFlp := blockIdxInfo.txLoc(txoffset)
 			logger.Debugf("Adding txLoc [%s] for tx ID: [%s] to txid-index", txFlp, txoffset.txID)
 			txFlpBytes, marshalErr := txFlp.marshal()
 			if marshalErr != nil {
@@ -137,7 +156,7 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 	//Index4 - Store BlockNumTranNum will be used to query history data
 	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockNumTranNum]; ok {
 		for txIterator, txoffset := range txOffsets {
-			txFlp := newFileLocationPointer(flp.fileSuffixNum, flp.offset, txoffset.loc)
+			txFlp := blockIdxInfo.txLoc(txoffset)
 			logger.Debugf("Adding txLoc [%s] for tx number:[%d] ID: [%s] to blockNumTranNum index", txFlp, txIterator, txoffset.txID)
 			txFlpBytes, marshalErr := txFlp.marshal()
 			if marshalErr != nil {
@@ -167,6 +186,27 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 		}
 	}
 
+	// Index7 - Store the commit time of the block, used to find the blocks committed in a time range
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockTime]; ok {
+		batch.Put(constructBlockTimeKey(blockIdxInfo.commitTime, blockIdxInfo.blockNum), flpBytes)
+		batch.Put(constructBlockNumTimeKey(blockIdxInfo.blockNum), encodeTime(blockIdxInfo.commitTime))
+	}
+
+	// Index8 - Store the transactions by the timestamp of their channel header along with their validation result
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxTimestamp]; ok {
+		for idx, txoffset := range txOffsets {
+			if txoffset.timestamp.IsZero() {
+				continue
+			}
+			txFlpBytes, marshalErr := blockIdxInfo.txLoc(txoffset).marshal()
+			if marshalErr != nil {
+				return marshalErr
+			}
+			batch.Put(constructTxTimestampKey(txoffset.timestamp, blockIdxInfo.blockNum, uint64(idx)),
+				encodeTxTimeIndexValue(txsfltr.Flag(idx), txFlpBytes))
+		}
+	}
+
 	batch.Put(indexCheckpointKey, encodeBlockNum(blockIdxInfo.blockNum))
 	// Setting snyc to true as a precaution, false may be an ok optimization after further testing.
 	if err := index.db.WriteBatch(batch, true); err != nil {
@@ -348,6 +388,10 @@ func (lp *locPointer) String() string {
 type fileLocPointer struct {
 	fileSuffixNum int
 	locPointer
+	// relativeToBlock is true for the location of a transaction in a block file of the checksummed format.
+	// The locPointer is then relative to the serialized block, which is stored in the record at blockOffset
+	relativeToBlock bool
+	blockOffset     int
 }
 
 func newFileLocationPointer(fileSuffixNum int, beginningOffset int, relativeLP *locPointer) *fileLocPointer {
@@ -357,6 +401,13 @@ func newFileLocationPointer(fileSuffixNum int, beginningOffset int, relativeLP *
 	return flp
 }
 
+func newBlockRelativeLocationPointer(fileSuffixNum int, blockOffset int, relativeLP *locPointer) *fileLocPointer {
+	flp := &fileLocPointer{fileSuffixNum: fileSuffixNum, locPointer: *relativeLP}
+	flp.relativeToBlock = true
+	flp.blockOffset = blockOffset
+	return flp
+}
+
 func (flp *fileLocPointer) marshal() ([]byte, error) {
 	buffer := proto.NewBuffer([]byte{})
 	e := buffer.EncodeVarint(uint64(flp.fileSuffixNum))
@@ -371,6 +422,14 @@ func (flp *fileLocPointer) marshal() ([]byte, error) {
 	if e != nil {
 		return nil, e
 	}
+	// the offset of the block is appended only for a location relative to a block so that the
+	// locations in the block files of the legacy format keep their encoding
+	if flp.relativeToBlock {
+		e = buffer.EncodeVarint(uint64(flp.blockOffset))
+		if e != nil {
+			return nil, e
+		}
+	}
 	return buffer.Bytes(), nil
 }
 
@@ -392,10 +451,19 @@ func (flp *fileLocPointer) unmarshal(b []byte) error {
 		return e
 	}
 	flp.bytesLength = int(i)
+	if i, e = buffer.DecodeVarint(); e != nil {
+		// no block offset, the location is relative to the file
+		return nil
+	}
+	flp.relativeToBlock = true
+	flp.blockOffset = int(i)
 	return nil
 }
 
 func (flp *fileLocPointer) String() string {
+	if flp.relativeToBlock {
+		return fmt.Sprintf("fileSuffixNum=%d, blockOffset=%d, %s", flp.fileSuffixNum, flp.blockOffset, flp.locPointer.String())
+	}
 	return fmt.Sprintf("fileSuffixNum=%d, %s", flp.fileSuffixNum, flp.locPointer.String())
 }
 
@@ -19,6 +19,7 @@ package fsblkstorage
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"justledger/common/ledger/blkstorage"
 	"justledger/common/ledger/testutil"
@@ -59,6 +60,14 @@ func (i *noopIndex) getTxValidationCodeByTxID(txID string) (peer.TxValidationCod
 	return peer.TxValidationCode(-1), nil
 }
 
+func (i *noopIndex) getBlockLocsByTimeRange(startTime, endTime time.Time, pageSize int, bookmark string) ([]*fileLocPointer, string, error) {
+	return nil, "", nil
+}
+
+func (i *noopIndex) getTxEntriesByTimeRange(startTime, endTime time.Time, pageSize int, bookmark string) ([]*txTimeIndexEntry, string, error) {
+	return nil, "", nil
+}
+
 func TestBlockIndexSync(t *testing.T) {
 	testBlockIndexSync(t, 10, 5, false)
 	testBlockIndexSync(t, 10, 5, true)
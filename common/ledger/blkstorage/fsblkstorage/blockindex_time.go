@@ -0,0 +1,164 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+
+	"justledger/common/ledger/blkstorage"
+	"justledger/common/ledger/util"
+	"justledger/protos/peer"
+	"github.com/pkg/errors"
+)
+
+// The time index keys start with the time, encoded as the number of nanoseconds since the unix epoch in 8 bytes
+// big endian so that the keys are ordered by time. The times before the epoch are indexed as the epoch.
+// The keys of the blocks are followed by the block number and the keys of the transactions by the block number
+// and the transaction number so that the entries with the same time are ordered as the blocks and transactions.
+//
+// The bookmark of a page of a time range query is the hex encoding of the key of the first entry of the page,
+// without the prefix
+
+// txTimeIndexEntry is the value of an entry of the transaction timestamp index
+type txTimeIndexEntry struct {
+	validationCode peer.TxValidationCode
+	txLoc          *fileLocPointer
+}
+
+func (index *blockIndex) getBlockLocsByTimeRange(startTime, endTime time.Time, pageSize int, bookmark string) ([]*fileLocPointer, string, error) {
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockTime]; !ok {
+		return nil, "", blkstorage.ErrAttrNotIndexed
+	}
+	values, nextBookmark, err := index.scanTimeRange(blockTimeIdxKeyPrefix, startTime, endTime, pageSize, bookmark)
+	if err != nil {
+		return nil, "", err
+	}
+	var blockLocs []*fileLocPointer
+	for _, value := range values {
+		blockLoc := &fileLocPointer{}
+		if err := blockLoc.unmarshal(value); err != nil {
+			return nil, "", err
+		}
+		blockLocs = append(blockLocs, blockLoc)
+	}
+	return blockLocs, nextBookmark, nil
+}
+
+func (index *blockIndex) getTxEntriesByTimeRange(startTime, endTime time.Time, pageSize int, bookmark string) ([]*txTimeIndexEntry, string, error) {
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxTimestamp]; !ok {
+		return nil, "", blkstorage.ErrAttrNotIndexed
+	}
+	values, nextBookmark, err := index.scanTimeRange(txTimestampIdxKeyPrefix, startTime, endTime, pageSize, bookmark)
+	if err != nil {
+		return nil, "", err
+	}
+	var entries []*txTimeIndexEntry
+	for _, value := range values {
+		entry, err := decodeTxTimeIndexValue(value)
+		if err != nil {
+			return nil, "", err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nextBookmark, nil
+}
+
+// getBlockCommitTime returns the commit time of the given block recorded in the block time index
+func (index *blockIndex) getBlockCommitTime(blockNum uint64) (time.Time, error) {
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockTime]; !ok {
+		return time.Time{}, blkstorage.ErrAttrNotIndexed
+	}
+	b, err := index.db.Get(constructBlockNumTimeKey(blockNum))
+	if err != nil {
+		return time.Time{}, err
+	}
+	if b == nil {
+		return time.Time{}, blkstorage.ErrNotFoundInIndex
+	}
+	return decodeTime(b), nil
+}
+
+// scanTimeRange returns the values of at most pageSize entries, with the given prefix, whose time is in the
+// range [startTime, endTime) starting from the given bookmark along with the bookmark of the next page
+func (index *blockIndex) scanTimeRange(prefix byte, startTime, endTime time.Time, pageSize int, bookmark string) ([][]byte, string, error) {
+	if pageSize < 0 {
+		return nil, "", errors.Errorf("invalid page size [%d]", pageSize)
+	}
+	startKey := append([]byte{prefix}, encodeTime(startTime)...)
+	endKey := append([]byte{prefix}, encodeTime(endTime)...)
+	if bookmark != "" {
+		bookmarkKey, err := hex.DecodeString(bookmark)
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "invalid bookmark [%s]", bookmark)
+		}
+		bookmarkKey = append([]byte{prefix}, bookmarkKey...)
+		if bytes.Compare(bookmarkKey, startKey) < 0 || bytes.Compare(bookmarkKey, endKey) >= 0 {
+			return nil, "", errors.Errorf("bookmark [%s] is out of the time range", bookmark)
+		}
+		startKey = bookmarkKey
+	}
+	itr := index.db.GetIterator(startKey, endKey)
+	defer itr.Release()
+	var values [][]byte
+	for itr.Next() {
+		if pageSize > 0 && len(values) == pageSize {
+			return values, hex.EncodeToString(itr.Key()[1:]), nil
+		}
+		values = append(values, append([]byte{}, itr.Value()...))
+	}
+	if err := itr.Error(); err != nil {
+		return nil, "", errors.Wrap(err, "error scanning the time index")
+	}
+	return values, "", nil
+}
+
+func constructBlockTimeKey(commitTime time.Time, blockNum uint64) []byte {
+	key := append([]byte{blockTimeIdxKeyPrefix}, encodeTime(commitTime)...)
+	return append(key, util.EncodeOrderPreservingVarUint64(blockNum)...)
+}
+
+func constructBlockNumTimeKey(blockNum uint64) []byte {
+	return append([]byte{blockNumTimeIdxKeyPrefix}, util.EncodeOrderPreservingVarUint64(blockNum)...)
+}
+
+func constructTxTimestampKey(timestamp time.Time, blockNum uint64, txNum uint64) []byte {
+	key := append([]byte{txTimestampIdxKeyPrefix}, encodeTime(timestamp)...)
+	key = append(key, util.EncodeOrderPreservingVarUint64(blockNum)...)
+	return append(key, util.EncodeOrderPreservingVarUint64(txNum)...)
+}
+
+func encodeTime(t time.Time) []byte {
+	var nanos uint64
+	if t.After(time.Unix(0, 0)) {
+		nanos = uint64(t.UnixNano())
+	}
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, nanos)
+	return b
+}
+
+func decodeTime(b []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(b))).UTC()
+}
+
+func encodeTxTimeIndexValue(validationCode peer.TxValidationCode, txFlpBytes []byte) []byte {
+	return append([]byte{byte(validationCode)}, txFlpBytes...)
+}
+
+func decodeTxTimeIndexValue(b []byte) (*txTimeIndexEntry, error) {
+	if len(b) < 2 {
+		return nil, errors.New("invalid value in the transaction timestamp index")
+	}
+	txLoc := &fileLocPointer{}
+	if err := txLoc.unmarshal(b[1:]); err != nil {
+		return nil, err
+	}
+	return &txTimeIndexEntry{validationCode: peer.TxValidationCode(int32(b[0])), txLoc: txLoc}, nil
+}
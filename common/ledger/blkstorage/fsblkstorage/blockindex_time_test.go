@@ -0,0 +1,269 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"justledger/common/ledger/blkstorage"
+	"justledger/common/ledger/testutil"
+	"justledger/core/ledger/util"
+	"justledger/protos/common"
+	"justledger/protos/peer"
+	putil "justledger/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var timeIndexAttrs = []blkstorage.IndexableAttr{
+	blkstorage.IndexableAttrBlockHash,
+	blkstorage.IndexableAttrBlockNum,
+	blkstorage.IndexableAttrTxID,
+	blkstorage.IndexableAttrBlockNumTranNum,
+	blkstorage.IndexableAttrBlockTxID,
+	blkstorage.IndexableAttrTxValidationCode,
+	blkstorage.IndexableAttrBlockTime,
+	blkstorage.IndexableAttrTxTimestamp,
+}
+
+func TestRetrieveBlocksByTimeRange(t *testing.T) {
+	env := newTestEnvSelectiveIndexing(t, NewConf(testPath(), 0), timeIndexAttrs)
+	defer env.Cleanup()
+	store, err := env.provider.OpenBlockStore("ledger1")
+	require.NoError(t, err)
+	defer store.Shutdown()
+
+	blocks := testutil.ConstructTestBlocks(t, 5)
+	var times []time.Time
+	for _, b := range blocks {
+		times = append(times, time.Now())
+		time.Sleep(time.Millisecond)
+		require.NoError(t, store.AddBlock(b))
+		time.Sleep(time.Millisecond)
+	}
+	times = append(times, time.Now())
+
+	// all the blocks in pages of two blocks
+	var retrievedBlocks []*common.Block
+	var pages int
+	bookmark := ""
+	for {
+		page, nextBookmark, err := store.RetrieveBlocksByTimeRange(times[0], times[5], 2, bookmark)
+		require.NoError(t, err)
+		retrievedBlocks = append(retrievedBlocks, page...)
+		pages++
+		if nextBookmark == "" {
+			break
+		}
+		bookmark = nextBookmark
+	}
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, blocks, retrievedBlocks)
+
+	// a part of the blocks in a single page
+	page, nextBookmark, err := store.RetrieveBlocksByTimeRange(times[1], times[3], 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, blocks[1:3], page)
+	assert.Empty(t, nextBookmark)
+
+	page, nextBookmark, err = store.RetrieveBlocksByTimeRange(times[5], times[5].Add(time.Hour), 0, "")
+	require.NoError(t, err)
+	assert.Empty(t, page)
+	assert.Empty(t, nextBookmark)
+
+	_, _, err = store.RetrieveBlocksByTimeRange(times[0], times[5], -1, "")
+	assert.EqualError(t, err, "invalid page size [-1]")
+
+	_, _, err = store.RetrieveBlocksByTimeRange(times[0], times[5], 1, "zz")
+	assert.Contains(t, err.Error(), "invalid bookmark [zz]")
+
+	// a bookmark cannot be used to retrieve the blocks out of the time range
+	_, bookmark, err = store.RetrieveBlocksByTimeRange(times[0], times[5], 4, "")
+	require.NoError(t, err)
+	_, _, err = store.RetrieveBlocksByTimeRange(times[0], times[3], 4, bookmark)
+	assert.EqualError(t, err, fmt.Sprintf("bookmark [%s] is out of the time range", bookmark))
+}
+
+func TestRetrieveTxsByTimeRange(t *testing.T) {
+	env := newTestEnvSelectiveIndexing(t, NewConf(testPath(), 0), timeIndexAttrs)
+	defer env.Cleanup()
+	store, err := env.provider.OpenBlockStore("ledger1")
+	require.NoError(t, err)
+	defer store.Shutdown()
+
+	baseTime := time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time {
+		return baseTime.Add(time.Duration(minutes) * time.Minute)
+	}
+	// the transactions are ordered by their timestamp, which does not follow the order of the blocks
+	block0 := constructBlockWithTxTimestamps(t, 0, nil, "tx0", at(10), "tx1", at(30))
+	block1 := constructBlockWithTxTimestamps(t, 1, block0.Header.Hash(), "tx2", at(20), "tx3", at(40))
+	txsFilter := util.TxValidationFlags(block1.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	txsFilter.SetFlag(1, peer.TxValidationCode_MVCC_READ_CONFLICT)
+	require.NoError(t, store.AddBlock(block0))
+	require.NoError(t, store.AddBlock(block1))
+
+	txs, nextBookmark, err := store.RetrieveTxsByTimeRange(at(0), at(60), 0, "")
+	require.NoError(t, err)
+	assert.Empty(t, nextBookmark)
+	assert.Equal(t, []string{"tx0", "tx2", "tx1", "tx3"}, txIDsOf(t, txs))
+	assert.Equal(t, int32(peer.TxValidationCode_VALID), txs[2].ValidationCode)
+	assert.Equal(t, int32(peer.TxValidationCode_MVCC_READ_CONFLICT), txs[3].ValidationCode)
+
+	txs, nextBookmark, err = store.RetrieveTxsByTimeRange(at(15), at(40), 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tx2"}, txIDsOf(t, txs))
+	txs, nextBookmark, err = store.RetrieveTxsByTimeRange(at(15), at(40), 1, nextBookmark)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tx1"}, txIDsOf(t, txs))
+	assert.Empty(t, nextBookmark)
+}
+
+func TestTimeRangeNotIndexed(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+	store, err := env.provider.OpenBlockStore("ledger1")
+	require.NoError(t, err)
+	defer store.Shutdown()
+
+	_, _, err = store.RetrieveBlocksByTimeRange(time.Unix(0, 0), time.Now(), 0, "")
+	assert.Equal(t, blkstorage.ErrAttrNotIndexed, err)
+	_, _, err = store.RetrieveTxsByTimeRange(time.Unix(0, 0), time.Now(), 0, "")
+	assert.Equal(t, blkstorage.ErrAttrNotIndexed, err)
+}
+
+func TestTimeIndexSync(t *testing.T) {
+	env := newTestEnvSelectiveIndexing(t, NewConf(testPath(), 0), timeIndexAttrs)
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "ledger1")
+	defer blkfileMgrWrapper.close()
+	blkfileMgr := blkfileMgrWrapper.blockfileMgr
+
+	baseTime := time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)
+	block0 := constructBlockWithTxTimestamps(t, 0, nil, "tx0", baseTime, "tx1", baseTime.Add(time.Minute))
+	block1 := constructBlockWithTxTimestamps(t, 1, block0.Header.Hash(), "tx2", baseTime.Add(2*time.Minute))
+	// simulate a block that is stored in the block files but not indexed
+	origIndex := blkfileMgr.index
+	blkfileMgr.index = &noopIndex{}
+	blkfileMgrWrapper.addBlocks([]*common.Block{block0, block1})
+	blkfileMgr.index = origIndex
+	require.NoError(t, blkfileMgr.syncIndex())
+
+	// when the blocks are indexed from the block files, the commit time is the latest timestamp of the transactions
+	commitTime, err := blkfileMgr.index.(*blockIndex).getBlockCommitTime(0)
+	require.NoError(t, err)
+	assert.Equal(t, baseTime.Add(time.Minute), commitTime)
+	blocks, _, err := blkfileMgr.retrieveBlocksByTimeRange(baseTime, baseTime.Add(2*time.Minute), 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, []*common.Block{block0}, blocks)
+	txs, _, err := blkfileMgr.retrieveTxsByTimeRange(baseTime, baseTime.Add(time.Hour), 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tx0", "tx1", "tx2"}, txIDsOf(t, txs))
+}
+
+func TestTimeIndexRollback(t *testing.T) {
+	path := testPath()
+	conf := NewConf(path, 0)
+	env := newTestEnvSelectiveIndexing(t, conf, timeIndexAttrs)
+	defer env.Cleanup()
+
+	baseTime := time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)
+	block0 := constructBlockWithTxTimestamps(t, 0, nil, "tx0", baseTime)
+	block1 := constructBlockWithTxTimestamps(t, 1, block0.Header.Hash(), "tx1", baseTime.Add(time.Minute))
+	block2 := constructBlockWithTxTimestamps(t, 2, block1.Header.Hash(), "tx2", baseTime.Add(2*time.Minute))
+	store, err := env.provider.OpenBlockStore("ledger1")
+	require.NoError(t, err)
+	startTime := time.Now()
+	for _, b := range []*common.Block{block0, block1, block2} {
+		require.NoError(t, store.AddBlock(b))
+	}
+	store.Shutdown()
+	env.provider.Close()
+
+	require.NoError(t, Rollback(path, "ledger1", 0))
+
+	env = newTestEnvSelectiveIndexing(t, conf, timeIndexAttrs)
+	store, err = env.provider.OpenBlockStore("ledger1")
+	require.NoError(t, err)
+	defer store.Shutdown()
+	blocks, _, err := store.RetrieveBlocksByTimeRange(startTime, time.Now().Add(time.Hour), 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, []*common.Block{block0}, blocks)
+	txs, _, err := store.RetrieveTxsByTimeRange(baseTime, baseTime.Add(time.Hour), 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tx0"}, txIDsOf(t, txs))
+	commitTimeBytes, err := env.provider.leveldbProvider.GetDBHandle("ledger1").Get(constructBlockNumTimeKey(1))
+	require.NoError(t, err)
+	assert.Nil(t, commitTimeBytes)
+}
+
+func TestVerifyBlockStoreTimeIndex(t *testing.T) {
+	conf := NewConf(testPath(), 0)
+	env := newTestEnvSelectiveIndexing(t, conf, timeIndexAttrs)
+	defer env.Cleanup()
+	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: timeIndexAttrs}
+
+	blocks := testutil.ConstructTestBlocks(t, 5)
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	blkfileMgrWrapper.addBlocks(blocks)
+	blkfileMgrWrapper.close()
+	indexStore := env.provider.leveldbProvider.GetDBHandle("testLedger")
+	commitTimeBytes, err := indexStore.Get(constructBlockNumTimeKey(2))
+	require.NoError(t, err)
+	env.provider.Close()
+
+	numBlocks, err := VerifyBlockStore(conf.blockStorageDir, "testLedger", indexConfig, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), numBlocks)
+
+	env = newTestEnvSelectiveIndexing(t, conf, timeIndexAttrs)
+	indexStore = env.provider.leveldbProvider.GetDBHandle("testLedger")
+	require.NoError(t, indexStore.Delete(constructBlockTimeKey(decodeTime(commitTimeBytes), 2), true))
+	env.provider.Close()
+
+	numBlocks, err = VerifyBlockStore(conf.blockStorageDir, "testLedger", indexConfig, nil)
+	require.Error(t, err)
+	inconsistencyErr, ok := err.(*BlockStoreInconsistencyError)
+	require.True(t, ok, "unexpected error type %T", err)
+	assert.Equal(t, uint64(2), inconsistencyErr.BlockNum)
+	assert.Equal(t, uint64(2), numBlocks)
+	assert.Equal(t, "missing index entry for the block commit time", inconsistencyErr.Reason)
+}
+
+// constructBlockWithTxTimestamps constructs a block with the transactions of the given ids and timestamps,
+// passed as pairs of txid and time.Time
+func constructBlockWithTxTimestamps(t *testing.T, blockNum uint64, previousHash []byte, txs ...interface{}) *common.Block {
+	var envs []*common.Envelope
+	for i := 0; i < len(txs); i += 2 {
+		ts := txs[i+1].(time.Time)
+		chdr := &common.ChannelHeader{
+			Type:      int32(common.HeaderType_ENDORSER_TRANSACTION),
+			ChannelId: "ledger1",
+			TxId:      txs[i].(string),
+			Timestamp: &timestamp.Timestamp{Seconds: ts.Unix(), Nanos: int32(ts.Nanosecond())},
+		}
+		payload := &common.Payload{
+			Header: &common.Header{ChannelHeader: putil.MarshalOrPanic(chdr)},
+			Data:   []byte(fmt.Sprintf("data of %s", txs[i])),
+		}
+		envs = append(envs, &common.Envelope{Payload: putil.MarshalOrPanic(payload)})
+	}
+	return testutil.NewBlock(envs, blockNum, previousHash)
+}
+
+func txIDsOf(t *testing.T, txs []*peer.ProcessedTransaction) []string {
+	var txIDs []string
+	for _, tx := range txs {
+		txID, err := extractTxID(putil.MarshalOrPanic(tx.TransactionEnvelope))
+		require.NoError(t, err)
+		txIDs = append(txIDs, txID)
+	}
+	return txIDs
+}
@@ -30,15 +30,26 @@ const (
 type Conf struct {
 	blockStorageDir  string
 	maxBlockfileSize int
+	compression      Compression
 }
 
 // NewConf constructs new `Conf`.
 // blockStorageDir is the top level folder under which `FsBlockStore` manages its data
 func NewConf(blockStorageDir string, maxBlockfileSize int) *Conf {
+	return NewConfWithCompression(blockStorageDir, maxBlockfileSize, CompressionNone)
+}
+
+// NewConfWithCompression constructs new `Conf` for a `FsBlockStore` that compresses the blocks
+// stored in the block files it creates with the given compression
+func NewConfWithCompression(blockStorageDir string, maxBlockfileSize int, compression Compression) *Conf {
 	if maxBlockfileSize <= 0 {
 		maxBlockfileSize = defaultMaxBlockfileSize
 	}
-	return &Conf{blockStorageDir, maxBlockfileSize}
+	return &Conf{blockStorageDir, maxBlockfileSize, compression}
+}
+
+func (conf *Conf) newBlockfileFormat() *blockfileFormat {
+	return newChecksummedBlockfileFormat(conf.compression)
 }
 
 func (conf *Conf) getIndexDir() string {
@@ -17,6 +17,8 @@ limitations under the License.
 package fsblkstorage
 
 import (
+	"time"
+
 	"justledger/common/ledger"
 	"justledger/common/ledger/blkstorage"
 	"justledger/common/ledger/util/leveldbhelper"
@@ -86,6 +88,16 @@ func (store *fsBlockStore) RetrieveTxValidationCodeByTxID(txID string) (peer.TxV
 	return store.fileMgr.retrieveTxValidationCodeByTxID(txID)
 }
 
+// RetrieveBlocksByTimeRange returns a page of the blocks committed in the time range [startTime, endTime)
+func (store *fsBlockStore) RetrieveBlocksByTimeRange(startTime, endTime time.Time, pageSize int, bookmark string) ([]*common.Block, string, error) {
+	return store.fileMgr.retrieveBlocksByTimeRange(startTime, endTime, pageSize, bookmark)
+}
+
+// RetrieveTxsByTimeRange returns a page of the transactions whose timestamp is in the time range [startTime, endTime)
+func (store *fsBlockStore) RetrieveTxsByTimeRange(startTime, endTime time.Time, pageSize int, bookmark string) ([]*peer.ProcessedTransaction, string, error) {
+	return store.fileMgr.retrieveTxsByTimeRange(startTime, endTime, pageSize, bookmark)
+}
+
 // Shutdown shuts down the block store
 func (store *fsBlockStore) Shutdown() {
 	logger.Debugf("closing fs blockStore:%s", store.id)
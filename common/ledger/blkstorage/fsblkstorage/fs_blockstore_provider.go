@@ -17,9 +17,13 @@ limitations under the License.
 package fsblkstorage
 
 import (
+	"fmt"
+	"os"
+
 	"justledger/common/ledger/blkstorage"
 	"justledger/common/ledger/util"
 	"justledger/common/ledger/util/leveldbhelper"
+	"github.com/pkg/errors"
 )
 
 // FsBlockstoreProvider provides handle to block storage - this is not thread-safe
@@ -59,6 +63,19 @@ func (p *FsBlockstoreProvider) List() ([]string, error) {
 	return util.ListSubdirs(p.conf.getChainsDir())
 }
 
+// Remove deletes the block files and the block index of the given ledger.
+// The block store of the ledger should have been shut down before calling this function
+func (p *FsBlockstoreProvider) Remove(ledgerid string) error {
+	if err := p.leveldbProvider.GetDBHandle(ledgerid).DeleteAll(); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error deleting the block index of ledger [%s]", ledgerid))
+	}
+	ledgerDir := p.conf.getLedgerBlockDir(ledgerid)
+	if err := os.RemoveAll(ledgerDir); err != nil {
+		return errors.Wrapf(err, "error removing the block files [%s]", ledgerDir)
+	}
+	return nil
+}
+
 // Close closes the FsBlockstoreProvider
 func (p *FsBlockstoreProvider) Close() {
 	p.leveldbProvider.Close()
@@ -148,6 +148,41 @@ func TestBlockStoreProvider(t *testing.T) {
 
 }
 
+func TestRemove(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+
+	provider := env.provider
+	store1, _ := provider.OpenBlockStore("ledger1")
+	store2, _ := provider.OpenBlockStore("ledger2")
+	defer store2.Shutdown()
+	blocks := testutil.ConstructTestBlocks(t, 5)
+	for _, b := range blocks {
+		assert.NoError(t, store1.AddBlock(b))
+		assert.NoError(t, store2.AddBlock(b))
+	}
+	store1.Shutdown()
+
+	assert.NoError(t, provider.Remove("ledger1"))
+	exists, err := provider.Exists("ledger1")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	storeNames, err := provider.List()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ledger2"}, storeNames)
+	itr := provider.leveldbProvider.GetDBHandle("ledger1").GetIterator(nil, nil)
+	defer itr.Release()
+	assert.False(t, itr.Next())
+	checkBlocks(t, blocks, store2)
+
+	// a ledger with the removed id starts afresh
+	store1, _ = provider.OpenBlockStore("ledger1")
+	defer store1.Shutdown()
+	bcInfo, err := store1.GetBlockchainInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), bcInfo.Height)
+}
+
 func constructLedgerid(id int) string {
 	return fmt.Sprintf("ledger_%d", id)
 }
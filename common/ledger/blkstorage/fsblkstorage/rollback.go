@@ -0,0 +1,311 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"os"
+
+	"justledger/common/ledger/util"
+	"justledger/common/ledger/util/leveldbhelper"
+	"github.com/pkg/errors"
+)
+
+// ValidateRollbackParams checks that the block store of the given ledger exists and
+// that the target block number is below the last block present in the block files
+func ValidateRollbackParams(blockStorageDir, ledgerID string, targetBlockNum uint64) error {
+	logger.Infof("Validating the rollback parameters: ledgerID [%s], block number [%d]", ledgerID, targetBlockNum)
+	ledgerDir := NewConf(blockStorageDir, 0).getLedgerBlockDir(ledgerID)
+	cpInfo, err := checkpointInfoForRollback(ledgerDir, ledgerID)
+	if err != nil {
+		return err
+	}
+	if targetBlockNum >= cpInfo.lastBlockNumber {
+		return errors.Errorf("target block number [%d] should be less than the biggest block number [%d]",
+			targetBlockNum, cpInfo.lastBlockNumber)
+	}
+	return nil
+}
+
+// Rollback removes the blocks above targetBlockNum from the block files of the given ledger
+// and the corresponding entries from the block index. The block store must not be open
+// while the rollback is in progress.
+//
+// The block index is rolled back before the block files and the checkpoint info is dropped
+// so that it gets reconstructed from the block files on the next start. Hence, a crash in
+// between leaves the store in a state that is either rebuilt at start or can be rolled back again
+func Rollback(blockStorageDir, ledgerID string, targetBlockNum uint64) error {
+	if err := ValidateRollbackParams(blockStorageDir, ledgerID, targetBlockNum); err != nil {
+		return err
+	}
+	conf := NewConf(blockStorageDir, 0)
+	ledgerDir := conf.getLedgerBlockDir(ledgerID)
+
+	lastFileNum, err := retrieveLastFileSuffix(ledgerDir)
+	if err != nil {
+		return err
+	}
+	targetFileNum, err := findBlockfileForBlock(ledgerDir, lastFileNum, targetBlockNum)
+	if err != nil {
+		return err
+	}
+	endOffset, err := findEndOffsetOfBlock(ledgerDir, targetFileNum, targetBlockNum)
+	if err != nil {
+		return err
+	}
+
+	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: conf.getIndexDir()})
+	defer dbProvider.Close()
+	indexStore := dbProvider.GetDBHandle(ledgerID)
+
+	logger.Infof("Rolling back the block index of ledger [%s] to block number [%d]", ledgerID, targetBlockNum)
+	if err := rollbackBlockIndex(indexStore, ledgerDir, targetFileNum, endOffset, lastFileNum, targetBlockNum); err != nil {
+		return err
+	}
+	logger.Infof("Rolling back the block files of ledger [%s] to block number [%d]", ledgerID, targetBlockNum)
+	return truncateBlockfiles(ledgerDir, targetFileNum, endOffset, lastFileNum)
+}
+
+// ResetBlockStore truncates the block files of all the ledgers to their genesis block and
+// drops the block index, which gets rebuilt from the block files on the next start.
+// The block store must not be open while the reset is in progress
+func ResetBlockStore(blockStorageDir string) error {
+	conf := NewConf(blockStorageDir, 0)
+	logger.Infof("Dropping the block index [%s]", conf.getIndexDir())
+	if err := os.RemoveAll(conf.getIndexDir()); err != nil {
+		return errors.Wrapf(err, "error removing the block index [%s]", conf.getIndexDir())
+	}
+	exists, _, err := util.FileExists(conf.getChainsDir())
+	if err != nil || !exists {
+		return err
+	}
+	ledgerIDs, err := util.ListSubdirs(conf.getChainsDir())
+	if err != nil {
+		return err
+	}
+	for _, ledgerID := range ledgerIDs {
+		ledgerDir := conf.getLedgerBlockDir(ledgerID)
+		lastFileNum, err := retrieveLastFileSuffix(ledgerDir)
+		if err != nil {
+			return err
+		}
+		if lastFileNum == -1 {
+			logger.Infof("No block file found for ledger [%s]. Nothing to reset", ledgerID)
+			continue
+		}
+		_, found, err := retrieveFirstBlockNumFromFile(ledgerDir, 0)
+		if err != nil {
+			return err
+		}
+		if !found {
+			logger.Infof("The block store of ledger [%s] is empty. Nothing to reset", ledgerID)
+			continue
+		}
+		endOffset, err := findEndOffsetOfBlock(ledgerDir, 0, 0)
+		if err != nil {
+			return err
+		}
+		logger.Infof("Resetting the block files of ledger [%s] to the genesis block", ledgerID)
+		if err := truncateBlockfiles(ledgerDir, 0, endOffset, lastFileNum); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkpointInfoForRollback constructs the checkpoint info from the block files of a ledger.
+// The checkpoint info persisted in the index is not trusted as it may lag behind the block files
+func checkpointInfoForRollback(ledgerDir, ledgerID string) (*checkpointInfo, error) {
+	exists, _, err := util.FileExists(ledgerDir)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.Errorf("ledgerID [%s] does not exist", ledgerID)
+	}
+	cpInfo, err := constructCheckpointInfoFromBlockFiles(ledgerDir)
+	if err != nil {
+		return nil, err
+	}
+	if cpInfo.isChainEmpty {
+		return nil, errors.Errorf("the block store of ledger [%s] is empty", ledgerID)
+	}
+	return cpInfo, nil
+}
+
+// findBlockfileForBlock returns the suffix number of the block file that contains the given block.
+// The block files are inspected from the last one since a rollback is expected to target recent blocks
+func findBlockfileForBlock(ledgerDir string, lastFileNum int, blockNum uint64) (int, error) {
+	for fileNum := lastFileNum; fileNum >= 0; fileNum-- {
+		firstBlockNum, found, err := retrieveFirstBlockNumFromFile(ledgerDir, fileNum)
+		if err != nil {
+			return -1, err
+		}
+		if found && firstBlockNum <= blockNum {
+			return fileNum, nil
+		}
+	}
+	return -1, errors.Errorf("block number [%d] not found in the block files", blockNum)
+}
+
+func retrieveFirstBlockNumFromFile(ledgerDir string, fileNum int) (uint64, bool, error) {
+	stream, err := newBlockfileStream(ledgerDir, fileNum, 0)
+	if err != nil {
+		return 0, false, err
+	}
+	defer stream.close()
+	blockBytes, err := stream.nextBlockBytes()
+	if err == ErrUnexpectedEndOfBlockfile || (err == nil && blockBytes == nil) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	info, err := extractSerializedBlockInfo(blockBytes)
+	if err != nil {
+		return 0, false, err
+	}
+	return info.blockHeader.Number, true, nil
+}
+
+// findEndOffsetOfBlock returns the offset in the given block file right after the given block
+func findEndOffsetOfBlock(ledgerDir string, fileNum int, blockNum uint64) (int64, error) {
+	stream, err := newBlockfileStream(ledgerDir, fileNum, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer stream.close()
+	for {
+		blockBytes, err := stream.nextBlockBytes()
+		if err != nil {
+			return 0, err
+		}
+		if blockBytes == nil {
+			return 0, errors.Errorf("block number [%d] not found in block file [%d]", blockNum, fileNum)
+		}
+		info, err := extractSerializedBlockInfo(blockBytes)
+		if err != nil {
+			return 0, err
+		}
+		if info.blockHeader.Number == blockNum {
+			return stream.currentOffset, nil
+		}
+	}
+}
+
+// rollbackBlockIndex removes the index entries of every block stored after the given offset
+// and moves the index checkpoint back to the target block
+func rollbackBlockIndex(indexStore *leveldbhelper.DBHandle, ledgerDir string,
+	targetFileNum int, endOffset int64, lastFileNum int, targetBlockNum uint64) error {
+	lastBlockIndexed, err := indexStore.Get(indexCheckpointKey)
+	if err != nil {
+		return err
+	}
+	batch := leveldbhelper.NewUpdateBatch()
+	if lastBlockIndexed != nil && decodeBlockNum(lastBlockIndexed) > targetBlockNum {
+		stream, err := newBlockStream(ledgerDir, targetFileNum, endOffset, lastFileNum)
+		if err != nil {
+			return err
+		}
+		defer stream.close()
+		for {
+			blockBytes, placementInfo, err := stream.nextBlockBytesAndPlacementInfo()
+			if err == ErrUnexpectedEndOfBlockfile {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if blockBytes == nil {
+				break
+			}
+			if err := addIndexEntriesToBeDeleted(batch, indexStore, blockBytes, placementInfo); err != nil {
+				return err
+			}
+		}
+		batch.Put(indexCheckpointKey, encodeBlockNum(targetBlockNum))
+	}
+	// drop the checkpoint info so that it gets reconstructed from the truncated block files
+	batch.Delete(blkMgrInfoKey)
+	return indexStore.WriteBatch(batch, true)
+}
+
+func addIndexEntriesToBeDeleted(batch *leveldbhelper.UpdateBatch, indexStore *leveldbhelper.DBHandle,
+	blockBytes []byte, placementInfo *blockPlacementInfo) error {
+	info, err := extractSerializedBlockInfo(blockBytes)
+	if err != nil {
+		return err
+	}
+	blockNum := info.blockHeader.Number
+	logger.Debugf("Removing the index entries of block [%d]", blockNum)
+	batch.Delete(constructBlockNumKey(blockNum))
+	batch.Delete(constructBlockHashKey(info.blockHeader.Hash()))
+
+	commitTimeBytes, err := indexStore.Get(constructBlockNumTimeKey(blockNum))
+	if err != nil {
+		return err
+	}
+	if commitTimeBytes != nil {
+		batch.Delete(constructBlockTimeKey(decodeTime(commitTimeBytes), blockNum))
+		batch.Delete(constructBlockNumTimeKey(blockNum))
+	}
+
+	blockEndOffset := placementInfo.blockBytesOffset + int64(len(blockBytes))
+	for txNum, txOffset := range info.txOffsets {
+		batch.Delete(constructBlockNumTranNumKey(blockNum, uint64(txNum)))
+		if !txOffset.timestamp.IsZero() {
+			batch.Delete(constructTxTimestampKey(txOffset.timestamp, blockNum, uint64(txNum)))
+		}
+
+		// a txid that is a duplicate of a txid in an earlier block points to the earlier block
+		// in the index and its entries must be retained
+		txLocBytes, err := indexStore.Get(constructTxIDKey(txOffset.txID))
+		if err != nil {
+			return err
+		}
+		if txLocBytes != nil {
+			txLoc := &fileLocPointer{}
+			if err := txLoc.unmarshal(txLocBytes); err != nil {
+				return err
+			}
+			if !txLocIsInBlock(txLoc, placementInfo, blockEndOffset) {
+				continue
+			}
+		}
+		batch.Delete(constructTxIDKey(txOffset.txID))
+		batch.Delete(constructBlockTxIDKey(txOffset.txID))
+		batch.Delete(constructTxValidationCodeIDKey(txOffset.txID))
+	}
+	return nil
+}
+
+func txLocIsInBlock(txLoc *fileLocPointer, placementInfo *blockPlacementInfo, blockEndOffset int64) bool {
+	if txLoc.fileSuffixNum != placementInfo.fileNum {
+		return false
+	}
+	if txLoc.relativeToBlock {
+		return int64(txLoc.blockOffset) == placementInfo.blockStartOffset
+	}
+	return int64(txLoc.offset) >= placementInfo.blockStartOffset && int64(txLoc.offset) < blockEndOffset
+}
+
+// truncateBlockfiles removes the block files after targetFileNum and truncates
+// the target file to the given offset
+func truncateBlockfiles(ledgerDir string, targetFileNum int, endOffset int64, lastFileNum int) error {
+	for fileNum := lastFileNum; fileNum > targetFileNum; fileNum-- {
+		filePath := deriveBlockfilePath(ledgerDir, fileNum)
+		logger.Debugf("Removing block file [%s]", filePath)
+		if err := os.Remove(filePath); err != nil {
+			return errors.Wrapf(err, "error removing block file [%s]", filePath)
+		}
+	}
+	filePath := deriveBlockfilePath(ledgerDir, targetFileNum)
+	logger.Debugf("Truncating block file [%s] to size [%d]", filePath, endOffset)
+	if err := os.Truncate(filePath, endOffset); err != nil {
+		return errors.Wrapf(err, "error truncating block file [%s]", filePath)
+	}
+	return nil
+}
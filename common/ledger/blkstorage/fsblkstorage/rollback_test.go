@@ -0,0 +1,169 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"testing"
+
+	"justledger/common/ledger/blkstorage"
+	"justledger/common/ledger/testutil"
+	"justledger/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollback(t *testing.T) {
+	path := testPath()
+	blocks := testutil.ConstructTestBlocks(t, 50)
+	blocksPerFile := 8
+	// small block files so that the rollback spans multiple files
+	conf := NewConf(path, blockfileSizeFor(t, blocks[1:blocksPerFile+1]))
+	env := newTestEnv(t, conf)
+	defer env.Cleanup()
+
+	store, err := env.provider.OpenBlockStore("ledger1")
+	require.NoError(t, err)
+	for _, b := range blocks {
+		require.NoError(t, store.AddBlock(b))
+	}
+	lastFileNum, err := retrieveLastFileSuffix(conf.getLedgerBlockDir("ledger1"))
+	require.NoError(t, err)
+	require.True(t, lastFileNum > 2)
+	store.Shutdown()
+	env.provider.Close()
+
+	require.NoError(t, Rollback(path, "ledger1", 20))
+
+	env = newTestEnv(t, conf)
+	store, err = env.provider.OpenBlockStore("ledger1")
+	require.NoError(t, err)
+	checkBlocks(t, blocks[:21], store)
+	for _, b := range blocks[21:] {
+		_, err := store.RetrieveBlockByHash(b.Header.Hash())
+		assert.Equal(t, blkstorage.ErrNotFoundInIndex, err)
+		txid, err := extractTxID(b.Data.Data[0])
+		require.NoError(t, err)
+		_, err = store.RetrieveTxByID(txid)
+		assert.Equal(t, blkstorage.ErrNotFoundInIndex, err)
+	}
+
+	// the removed blocks can be committed again
+	for _, b := range blocks[21:] {
+		require.NoError(t, store.AddBlock(b))
+	}
+	checkBlocks(t, blocks, store)
+	store.Shutdown()
+}
+
+func TestRollbackWithIndexBehind(t *testing.T) {
+	path := testPath()
+	conf := NewConf(path, 0)
+	env := newTestEnv(t, conf)
+	defer env.Cleanup()
+
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	store, err := env.provider.OpenBlockStore("ledger1")
+	require.NoError(t, err)
+	for _, b := range blocks {
+		require.NoError(t, store.AddBlock(b))
+	}
+	store.Shutdown()
+
+	// simulate a crash before the last blocks were indexed
+	indexStore := env.provider.leveldbProvider.GetDBHandle("ledger1")
+	require.NoError(t, indexStore.Put(indexCheckpointKey, encodeBlockNum(3), true))
+	env.provider.Close()
+
+	require.NoError(t, Rollback(path, "ledger1", 5))
+
+	env = newTestEnv(t, conf)
+	store, err = env.provider.OpenBlockStore("ledger1")
+	require.NoError(t, err)
+	checkBlocks(t, blocks[:6], store)
+	store.Shutdown()
+}
+
+func TestRollbackBadParams(t *testing.T) {
+	path := testPath()
+	env := newTestEnv(t, NewConf(path, 0))
+	defer env.Cleanup()
+
+	store, err := env.provider.OpenBlockStore("ledger1")
+	require.NoError(t, err)
+	for _, b := range testutil.ConstructTestBlocks(t, 5) {
+		require.NoError(t, store.AddBlock(b))
+	}
+	store.Shutdown()
+	_, err = env.provider.OpenBlockStore("emptyledger")
+	require.NoError(t, err)
+	env.provider.Close()
+
+	err = Rollback(path, "ledger1", 4)
+	assert.EqualError(t, err, "target block number [4] should be less than the biggest block number [4]")
+
+	err = Rollback(path, "ledger1", 10)
+	assert.EqualError(t, err, "target block number [10] should be less than the biggest block number [4]")
+
+	err = Rollback(path, "nonexistent", 1)
+	assert.EqualError(t, err, "ledgerID [nonexistent] does not exist")
+
+	err = Rollback(path, "emptyledger", 1)
+	assert.EqualError(t, err, "the block store of ledger [emptyledger] is empty")
+}
+
+func TestResetBlockStore(t *testing.T) {
+	path := testPath()
+	blocks1 := testutil.ConstructTestBlocks(t, 20)
+	conf := NewConf(path, blockfileSizeFor(t, blocks1[1:5]))
+	env := newTestEnv(t, conf)
+	defer env.Cleanup()
+
+	store1, err := env.provider.OpenBlockStore("ledger1")
+	require.NoError(t, err)
+	for _, b := range blocks1 {
+		require.NoError(t, store1.AddBlock(b))
+	}
+	store1.Shutdown()
+
+	blocks2 := testutil.ConstructTestBlocks(t, 1)
+	store2, err := env.provider.OpenBlockStore("ledger2")
+	require.NoError(t, err)
+	require.NoError(t, store2.AddBlock(blocks2[0]))
+	store2.Shutdown()
+
+	_, err = env.provider.OpenBlockStore("emptyledger")
+	require.NoError(t, err)
+	env.provider.Close()
+
+	require.NoError(t, ResetBlockStore(path))
+
+	env = newTestEnv(t, conf)
+	store1, err = env.provider.OpenBlockStore("ledger1")
+	require.NoError(t, err)
+	checkBlocks(t, blocks1[:1], store1)
+	lastFileNum, err := retrieveLastFileSuffix(conf.getLedgerBlockDir("ledger1"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, lastFileNum)
+
+	store2, err = env.provider.OpenBlockStore("ledger2")
+	require.NoError(t, err)
+	checkBlocks(t, blocks2, store2)
+
+	store1.Shutdown()
+	store2.Shutdown()
+}
+
+// blockfileSizeFor returns a block file size that fits exactly the given blocks
+func blockfileSizeFor(t *testing.T, blocks []*common.Block) int {
+	size := 0
+	for _, b := range blocks {
+		blockBytes, _, err := serializeBlock(b)
+		require.NoError(t, err)
+		size += len(blockBytes) + 8
+	}
+	return size
+}
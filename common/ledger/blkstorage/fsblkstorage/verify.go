@@ -0,0 +1,345 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"bytes"
+	"fmt"
+
+	"justledger/common/ledger/blkstorage"
+	"justledger/common/ledger/util"
+	"justledger/common/ledger/util/leveldbhelper"
+	ledgerUtil "justledger/core/ledger/util"
+	"justledger/protos/common"
+	"github.com/pkg/errors"
+)
+
+// BlockStoreInconsistencyError is returned by VerifyBlockStore for the first inconsistency found in a block store
+type BlockStoreInconsistencyError struct {
+	// BlockNum is the number of the block, or of the expected block, where the inconsistency is found
+	BlockNum uint64
+	// FilePath is the path of the block file that stores the block
+	FilePath string
+	// Offset is the offset of the block in the block file
+	Offset int64
+	// Reason describes the inconsistency
+	Reason string
+}
+
+func (e *BlockStoreInconsistencyError) Error() string {
+	return fmt.Sprintf("inconsistency found in block [%d] stored in block file [%s] at offset [%d]: %s",
+		e.BlockNum, e.FilePath, e.Offset, e.Reason)
+}
+
+// VerifyBlockStore walks the block files of the given ledger and checks that the stored chain is internally
+// consistent, i.e., the blocks are numbered in sequence, the DataHash of every block matches its data, the
+// PreviousHash of every block matches the header of the previous block and the entries of the block index,
+// as configured by indexConfig, point to the stored blocks and transactions. In addition, verifyBlock, if not nil,
+// is invoked for every block in sequence after the checks above succeed for the block.
+//
+// The number of blocks verified is returned. The first inconsistency found is returned as a
+// *BlockStoreInconsistencyError. The block store must not be open while the verification is in progress
+func VerifyBlockStore(blockStorageDir, ledgerID string, indexConfig *blkstorage.IndexConfig,
+	verifyBlock func(block *common.Block) error) (uint64, error) {
+	conf := NewConf(blockStorageDir, 0)
+	ledgerDir := conf.getLedgerBlockDir(ledgerID)
+	exists, _, err := util.FileExists(ledgerDir)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, errors.Errorf("ledgerID [%s] does not exist", ledgerID)
+	}
+	lastFileNum, err := retrieveLastFileSuffix(ledgerDir)
+	if err != nil {
+		return 0, err
+	}
+	if lastFileNum == -1 {
+		return 0, errors.Errorf("the block store of ledger [%s] is empty", ledgerID)
+	}
+
+	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: conf.getIndexDir()})
+	defer dbProvider.Close()
+	index, err := newBlockIndex(indexConfig, dbProvider.GetDBHandle(ledgerID))
+	if err != nil {
+		return 0, err
+	}
+	v := &blockStoreVerifier{ledgerDir: ledgerDir, index: index, verifyBlock: verifyBlock}
+	if v.lastBlockIndexed, err = index.getLastBlockIndexed(); err != nil {
+		if err != errIndexEmpty {
+			return 0, err
+		}
+		v.indexEmpty = true
+	}
+
+	stream, err := newBlockStream(ledgerDir, 0, 0, lastFileNum)
+	if err != nil {
+		return 0, err
+	}
+	defer stream.close()
+	for {
+		blockBytes, placementInfo, err := stream.nextBlockBytesAndPlacementInfo()
+		if err != nil {
+			if v.isIncompleteLastBlock(err, stream, lastFileNum) {
+				logger.Warningf("Ignoring the incomplete block after block [%d] at the end of block file [%d] of ledger [%s]: %s",
+					v.numBlocks-1, lastFileNum, ledgerID, err)
+				break
+			}
+			return v.numBlocks, v.streamError(err, stream)
+		}
+		if blockBytes == nil {
+			break
+		}
+		if err := v.verify(blockBytes, placementInfo); err != nil {
+			return v.numBlocks, err
+		}
+		v.numBlocks++
+	}
+	if v.numBlocks == 0 {
+		return 0, errors.Errorf("the block store of ledger [%s] is empty", ledgerID)
+	}
+	if !v.indexEmpty && v.lastBlockIndexed >= v.numBlocks {
+		return v.numBlocks, errors.Errorf("the block index of ledger [%s] has block [%d] indexed while the last block in the block files is [%d]",
+			ledgerID, v.lastBlockIndexed, v.numBlocks-1)
+	}
+	return v.numBlocks, nil
+}
+
+// blockStoreVerifier carries the state of VerifyBlockStore from one block to the next
+type blockStoreVerifier struct {
+	ledgerDir        string
+	index            *blockIndex
+	indexEmpty       bool
+	lastBlockIndexed uint64
+	verifyBlock      func(block *common.Block) error
+	numBlocks        uint64
+	previousHash     []byte
+}
+
+func (v *blockStoreVerifier) verify(blockBytes []byte, placementInfo *blockPlacementInfo) error {
+	inconsistency := func(format string, args ...interface{}) error {
+		return &BlockStoreInconsistencyError{
+			BlockNum: v.numBlocks,
+			FilePath: deriveBlockfilePath(v.ledgerDir, placementInfo.fileNum),
+			Offset:   placementInfo.blockStartOffset,
+			Reason:   fmt.Sprintf(format, args...),
+		}
+	}
+	block, err := deserializeBlock(blockBytes)
+	if err != nil {
+		return inconsistency("error deserializing block: %s", err)
+	}
+	if block.Header.Number != v.numBlocks {
+		return inconsistency("unexpected block number [%d]", block.Header.Number)
+	}
+	if !bytes.Equal(block.Data.Hash(), block.Header.DataHash) {
+		return inconsistency("DataHash [%x] in the header does not match the hash of the block data [%x]",
+			block.Header.DataHash, block.Data.Hash())
+	}
+	if v.numBlocks > 0 && !bytes.Equal(block.Header.PreviousHash, v.previousHash) {
+		return inconsistency("PreviousHash [%x] in the header does not match the hash of the header of block [%d] [%x]",
+			block.Header.PreviousHash, v.numBlocks-1, v.previousHash)
+	}
+	v.previousHash = block.Header.Hash()
+
+	if !v.indexEmpty && v.numBlocks <= v.lastBlockIndexed {
+		if reason, err := v.verifyIndexEntries(blockBytes, placementInfo); err != nil {
+			return err
+		} else if reason != "" {
+			return inconsistency("%s", reason)
+		}
+	}
+	if v.verifyBlock != nil {
+		if err := v.verifyBlock(block); err != nil {
+			return inconsistency("%s", err)
+		}
+	}
+	return nil
+}
+
+// verifyIndexEntries compares the entries of the block index for the given block with the entries that
+// indexing the block produces. A non-empty description of the first mismatch found is returned
+func (v *blockStoreVerifier) verifyIndexEntries(blockBytes []byte, placementInfo *blockPlacementInfo) (string, error) {
+	info, err := extractSerializedBlockInfo(blockBytes)
+	if err != nil {
+		return fmt.Sprintf("error extracting the block info: %s", err), nil
+	}
+	// the locations of the transactions are computed the same way as the block files are indexed - see syncIndex
+	if !placementInfo.txLocsRelativeToBlock {
+		numBytesToShift := int(placementInfo.blockBytesOffset - placementInfo.blockStartOffset)
+		for _, offset := range info.txOffsets {
+			offset.loc.offset += numBytesToShift
+		}
+	}
+	blockIdxInfo := &blockIdxInfo{
+		blockNum:              info.blockHeader.Number,
+		blockHash:             info.blockHeader.Hash(),
+		flp:                   newFileLocationPointer(placementInfo.fileNum, int(placementInfo.blockStartOffset), &locPointer{}),
+		txOffsets:             info.txOffsets,
+		metadata:              info.metadata,
+		txLocsRelativeToBlock: placementInfo.txLocsRelativeToBlock,
+	}
+	flpBytes, err := blockIdxInfo.flp.marshal()
+	if err != nil {
+		return "", err
+	}
+	indexItems := v.index.indexItemsMap
+	db := v.index.db
+
+	if indexItems[blkstorage.IndexableAttrBlockHash] {
+		if reason, err := verifyIndexEntry(db, constructBlockHashKey(blockIdxInfo.blockHash), flpBytes, "block hash"); reason != "" || err != nil {
+			return reason, err
+		}
+	}
+	if indexItems[blkstorage.IndexableAttrBlockNum] {
+		if reason, err := verifyIndexEntry(db, constructBlockNumKey(blockIdxInfo.blockNum), flpBytes, "block number"); reason != "" || err != nil {
+			return reason, err
+		}
+	}
+
+	txsFilter := ledgerUtil.TxValidationFlags(info.metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	// the blocks committed before the time index was enabled have no time index entries
+	timeIndexed := false
+	if indexItems[blkstorage.IndexableAttrBlockTime] {
+		commitTimeBytes, err := db.Get(constructBlockNumTimeKey(blockIdxInfo.blockNum))
+		if err != nil {
+			return "", err
+		}
+		if timeIndexed = commitTimeBytes != nil; timeIndexed {
+			key := constructBlockTimeKey(decodeTime(commitTimeBytes), blockIdxInfo.blockNum)
+			if reason, err := verifyIndexEntry(db, key, flpBytes, "block commit time"); reason != "" || err != nil {
+				return reason, err
+			}
+		}
+	}
+
+	seenTxIDs := make(map[string]bool)
+	for txNum, txOffset := range info.txOffsets {
+		txFlpBytes, err := blockIdxInfo.txLoc(txOffset).marshal()
+		if err != nil {
+			return "", err
+		}
+		if indexItems[blkstorage.IndexableAttrBlockNumTranNum] {
+			key := constructBlockNumTranNumKey(blockIdxInfo.blockNum, uint64(txNum))
+			if reason, err := verifyIndexEntry(db, key, txFlpBytes, fmt.Sprintf("transaction number [%d]", txNum)); reason != "" || err != nil {
+				return reason, err
+			}
+		}
+		if timeIndexed && indexItems[blkstorage.IndexableAttrTxTimestamp] && !txOffset.timestamp.IsZero() {
+			key := constructTxTimestampKey(txOffset.timestamp, blockIdxInfo.blockNum, uint64(txNum))
+			expected := encodeTxTimeIndexValue(txsFilter.Flag(txNum), txFlpBytes)
+			if reason, err := verifyIndexEntry(db, key, expected, fmt.Sprintf("timestamp of transaction number [%d]", txNum)); reason != "" || err != nil {
+				return reason, err
+			}
+		}
+		if !indexItems[blkstorage.IndexableAttrTxID] || seenTxIDs[txOffset.txID] {
+			continue
+		}
+		seenTxIDs[txOffset.txID] = true
+
+		isDuplicate, reason, err := v.verifyTxIDIndexEntry(txOffset.txID, txFlpBytes, placementInfo)
+		if reason != "" || err != nil {
+			return reason, err
+		}
+		// the other entries of a txid that is a duplicate of a txid in an earlier block belong to the earlier block
+		if isDuplicate {
+			continue
+		}
+		if indexItems[blkstorage.IndexableAttrBlockTxID] {
+			key := constructBlockTxIDKey(txOffset.txID)
+			if reason, err := verifyIndexEntry(db, key, flpBytes, fmt.Sprintf("block of txid [%s]", txOffset.txID)); reason != "" || err != nil {
+				return reason, err
+			}
+		}
+		if indexItems[blkstorage.IndexableAttrTxValidationCode] {
+			key := constructTxValidationCodeIDKey(txOffset.txID)
+			expected := []byte{byte(txsFilter.Flag(txNum))}
+			if reason, err := verifyIndexEntry(db, key, expected, fmt.Sprintf("validation code of txid [%s]", txOffset.txID)); reason != "" || err != nil {
+				return reason, err
+			}
+		}
+	}
+	return "", nil
+}
+
+// verifyTxIDIndexEntry checks the txid index entry of a transaction of the given block. The entry points either to
+// the transaction or, if the txid is a duplicate, to a transaction stored before the block. The returned boolean
+// is true in the latter case
+func (v *blockStoreVerifier) verifyTxIDIndexEntry(txID string, txFlpBytes []byte,
+	placementInfo *blockPlacementInfo) (bool, string, error) {
+	entry, err := v.index.db.Get(constructTxIDKey(txID))
+	if err != nil {
+		return false, "", err
+	}
+	if entry == nil {
+		return false, fmt.Sprintf("missing txid index entry for txid [%s]", txID), nil
+	}
+	if bytes.Equal(entry, txFlpBytes) {
+		return false, "", nil
+	}
+	txLoc := &fileLocPointer{}
+	if err := txLoc.unmarshal(entry); err != nil {
+		return false, fmt.Sprintf("invalid txid index entry for txid [%s]: %s", txID, err), nil
+	}
+	storedBefore := txLoc.fileSuffixNum < placementInfo.fileNum ||
+		(txLoc.fileSuffixNum == placementInfo.fileNum && int64(txLoc.offset) < placementInfo.blockStartOffset)
+	if txLoc.relativeToBlock && txLoc.fileSuffixNum == placementInfo.fileNum {
+		storedBefore = int64(txLoc.blockOffset) < placementInfo.blockStartOffset
+	}
+	if !storedBefore {
+		return false, fmt.Sprintf("txid index entry for txid [%s] points to [%s] instead of the transaction in the block", txID, txLoc), nil
+	}
+	return true, "", nil
+}
+
+func verifyIndexEntry(db *leveldbhelper.DBHandle, key, expected []byte, entryDesc string) (string, error) {
+	entry, err := db.Get(key)
+	if err != nil {
+		return "", err
+	}
+	if entry == nil {
+		return fmt.Sprintf("missing index entry for the %s", entryDesc), nil
+	}
+	if !bytes.Equal(entry, expected) {
+		return fmt.Sprintf("index entry for the %s is [%x] while [%x] is expected", entryDesc, entry, expected), nil
+	}
+	return "", nil
+}
+
+// isIncompleteLastBlock returns true if the given error is caused by a block that is partially written at the end
+// of the last block file, which can be the result of a crash during the append of a block and is discarded when
+// the block store is opened
+func (v *blockStoreVerifier) isIncompleteLastBlock(err error, stream *blockStream, lastFileNum int) bool {
+	if stream.currentFileNum != lastFileNum {
+		return false
+	}
+	if err == ErrUnexpectedEndOfBlockfile {
+		return true
+	}
+	corruptionErr, ok := err.(*BlockfileCorruptionError)
+	return ok && corruptionErr.lastRecord
+}
+
+func (v *blockStoreVerifier) streamError(err error, stream *blockStream) error {
+	inconsistencyErr := &BlockStoreInconsistencyError{
+		BlockNum: v.numBlocks,
+		FilePath: deriveBlockfilePath(v.ledgerDir, stream.currentFileNum),
+		Reason:   err.Error(),
+	}
+	if stream.currentFileStream != nil {
+		inconsistencyErr.Offset = stream.currentFileStream.currentOffset
+	}
+	switch err := err.(type) {
+	case *BlockfileCorruptionError:
+		inconsistencyErr.FilePath, inconsistencyErr.Offset, inconsistencyErr.Reason = err.FilePath, err.Offset, err.Reason
+	default:
+		if err == ErrUnexpectedEndOfBlockfile {
+			inconsistencyErr.Reason = "incomplete block in the middle of the block files"
+		}
+	}
+	return inconsistencyErr
+}
@@ -0,0 +1,279 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"justledger/common/ledger/blkstorage"
+	"justledger/common/ledger/testutil"
+	"justledger/common/ledger/util"
+	"justledger/common/ledger/util/leveldbhelper"
+	"justledger/protos/common"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var verifyTestIndexConfig = &blkstorage.IndexConfig{AttrsToIndex: []blkstorage.IndexableAttr{
+	blkstorage.IndexableAttrBlockHash,
+	blkstorage.IndexableAttrBlockNum,
+	blkstorage.IndexableAttrTxID,
+	blkstorage.IndexableAttrBlockNumTranNum,
+	blkstorage.IndexableAttrBlockTxID,
+	blkstorage.IndexableAttrTxValidationCode,
+}}
+
+func TestVerifyBlockStore(t *testing.T) {
+	for _, compression := range []Compression{CompressionNone, CompressionSnappy} {
+		t.Run(compression.String(), func(t *testing.T) {
+			blocks := testutil.ConstructTestBlocks(t, 30)
+			conf := NewConfWithCompression(testPath(), blockfileSizeFor(t, blocks[1:9]), compression)
+			env := newTestEnv(t, conf)
+			defer env.Cleanup()
+			blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+			blkfileMgrWrapper.addBlocks(blocks)
+			require.True(t, blkfileMgrWrapper.blockfileMgr.cpInfo.latestFileChunkSuffixNum > 1)
+			blkfileMgrWrapper.close()
+			env.provider.Close()
+
+			var verifiedBlocks []*common.Block
+			numBlocks, err := VerifyBlockStore(conf.blockStorageDir, "testLedger", verifyTestIndexConfig,
+				func(block *common.Block) error {
+					verifiedBlocks = append(verifiedBlocks, block)
+					return nil
+				})
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(30), numBlocks)
+			assert.Equal(t, blocks, verifiedBlocks)
+
+			_, err = VerifyBlockStore(conf.blockStorageDir, "nonExistingLedger", verifyTestIndexConfig, nil)
+			assert.EqualError(t, err, "ledgerID [nonExistingLedger] does not exist")
+		})
+	}
+}
+
+func TestVerifyBlockStoreLegacyBlockfiles(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	conf := NewConf(testPath(), 0)
+	env := newTestEnv(t, conf)
+	defer env.Cleanup()
+
+	// write a block file in the legacy format, as the previous releases did
+	rootDir := conf.getLedgerBlockDir("testLedger")
+	_, err := util.CreateDirIfMissing(rootDir)
+	require.NoError(t, err)
+	var legacyFileBytes []byte
+	for _, block := range blocks[:5] {
+		blockBytes, _, err := serializeBlock(block)
+		require.NoError(t, err)
+		legacyFileBytes = append(legacyFileBytes, proto.EncodeVarint(uint64(len(blockBytes)))...)
+		legacyFileBytes = append(legacyFileBytes, blockBytes...)
+	}
+	require.NoError(t, ioutil.WriteFile(deriveBlockfilePath(rootDir, 0), legacyFileBytes, 0660))
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	blkfileMgrWrapper.addBlocks(blocks[5:])
+	blkfileMgrWrapper.close()
+	env.provider.Close()
+
+	numBlocks, err := VerifyBlockStore(conf.blockStorageDir, "testLedger", verifyTestIndexConfig, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), numBlocks)
+}
+
+func TestVerifyBlockStoreDuplicateTxIDs(t *testing.T) {
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	blocks := []*common.Block{
+		gb,
+		bg.NextBlockWithTxid([][]byte{[]byte("a"), []byte("b")}, []string{"txid1", "txid2"}),
+		bg.NextBlockWithTxid([][]byte{[]byte("c"), []byte("d")}, []string{"txid3", "txid1"}),
+		bg.NextBlockWithTxid([][]byte{[]byte("e"), []byte("f")}, []string{"txid4", "txid4"}),
+	}
+	conf := NewConf(testPath(), 0)
+	env := newTestEnv(t, conf)
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	blkfileMgrWrapper.addBlocks(blocks)
+	blkfileMgrWrapper.close()
+	env.provider.Close()
+
+	numBlocks, err := VerifyBlockStore(conf.blockStorageDir, "testLedger", verifyTestIndexConfig, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), numBlocks)
+}
+
+func TestVerifyBlockStoreInconsistencies(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	txID, err := extractTxID(blocks[3].Data.Data[1])
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name           string
+		tamper         func(t *testing.T, mgr *blockfileMgr, indexStore *leveldbhelper.DBHandle)
+		verifyBlock    func(block *common.Block) error
+		blockNum       uint64
+		expectedReason string
+	}{
+		{
+			name: "corrupted block",
+			tamper: func(t *testing.T, mgr *blockfileMgr, indexStore *leveldbhelper.DBHandle) {
+				blockLoc, err := mgr.index.getBlockLocByBlockNum(3)
+				require.NoError(t, err)
+				flipByte(t, deriveBlockfilePath(mgr.rootDir, 0), int64(blockLoc.offset+5))
+			},
+			blockNum:       3,
+			expectedReason: "checksum mismatch",
+		},
+		{
+			name: "missing txid index entry",
+			tamper: func(t *testing.T, mgr *blockfileMgr, indexStore *leveldbhelper.DBHandle) {
+				require.NoError(t, indexStore.Delete(constructTxIDKey(txID), true))
+			},
+			blockNum:       3,
+			expectedReason: "missing txid index entry for txid [" + txID + "]",
+		},
+		{
+			name: "txid index entry pointing to a later block",
+			tamper: func(t *testing.T, mgr *blockfileMgr, indexStore *leveldbhelper.DBHandle) {
+				otherTxID, err := extractTxID(blocks[5].Data.Data[0])
+				require.NoError(t, err)
+				entry, err := indexStore.Get(constructTxIDKey(otherTxID))
+				require.NoError(t, err)
+				require.NoError(t, indexStore.Put(constructTxIDKey(txID), entry, true))
+			},
+			blockNum:       3,
+			expectedReason: "txid index entry for txid [" + txID + "] points to",
+		},
+		{
+			name: "block number index entry pointing to another block",
+			tamper: func(t *testing.T, mgr *blockfileMgr, indexStore *leveldbhelper.DBHandle) {
+				entry, err := indexStore.Get(constructBlockNumKey(2))
+				require.NoError(t, err)
+				require.NoError(t, indexStore.Put(constructBlockNumKey(6), entry, true))
+			},
+			blockNum:       6,
+			expectedReason: "index entry for the block number is",
+		},
+		{
+			name: "validation code index entry",
+			tamper: func(t *testing.T, mgr *blockfileMgr, indexStore *leveldbhelper.DBHandle) {
+				require.NoError(t, indexStore.Put(constructTxValidationCodeIDKey(txID), []byte{1}, true))
+			},
+			blockNum:       3,
+			expectedReason: "index entry for the validation code of txid [" + txID + "] is [01]",
+		},
+		{
+			name: "block rejected by verifyBlock",
+			verifyBlock: func(block *common.Block) error {
+				if block.Header.Number == 7 {
+					return errors.New("invalid signature")
+				}
+				return nil
+			},
+			blockNum:       7,
+			expectedReason: "invalid signature",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			conf := NewConfWithCompression(testPath(), 0, CompressionSnappy)
+			env := newTestEnv(t, conf)
+			defer env.Cleanup()
+			blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+			blkfileMgrWrapper.addBlocks(blocks)
+			if testCase.tamper != nil {
+				testCase.tamper(t, blkfileMgrWrapper.blockfileMgr, env.provider.leveldbProvider.GetDBHandle("testLedger"))
+			}
+			blkfileMgrWrapper.close()
+			env.provider.Close()
+
+			numBlocks, err := VerifyBlockStore(conf.blockStorageDir, "testLedger", verifyTestIndexConfig, testCase.verifyBlock)
+			require.Error(t, err)
+			inconsistencyErr, ok := err.(*BlockStoreInconsistencyError)
+			require.True(t, ok, "unexpected error type %T", err)
+			assert.Equal(t, testCase.blockNum, inconsistencyErr.BlockNum)
+			assert.Equal(t, testCase.blockNum, numBlocks)
+			assert.Equal(t, deriveBlockfilePath(conf.getLedgerBlockDir("testLedger"), 0), inconsistencyErr.FilePath)
+			assert.Contains(t, inconsistencyErr.Reason, testCase.expectedReason)
+		})
+	}
+}
+
+func TestVerifyBlockStoreHeaderInconsistencies(t *testing.T) {
+	t.Run("DataHash", func(t *testing.T) {
+		blocks := testutil.ConstructTestBlocks(t, 10)
+		// the data of the block does not match the DataHash in its header, which the block chain is built upon
+		blocks[4].Data.Data = blocks[4].Data.Data[1:]
+		conf := NewConf(testPath(), 0)
+		env := newTestEnv(t, conf)
+		defer env.Cleanup()
+		blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+		blkfileMgrWrapper.addBlocks(blocks)
+		blockLoc, err := blkfileMgrWrapper.blockfileMgr.index.getBlockLocByBlockNum(4)
+		require.NoError(t, err)
+		blkfileMgrWrapper.close()
+		env.provider.Close()
+
+		_, err = VerifyBlockStore(conf.blockStorageDir, "testLedger", verifyTestIndexConfig, nil)
+		require.Error(t, err)
+		inconsistencyErr, ok := err.(*BlockStoreInconsistencyError)
+		require.True(t, ok)
+		assert.Equal(t, uint64(4), inconsistencyErr.BlockNum)
+		assert.Equal(t, int64(blockLoc.offset), inconsistencyErr.Offset)
+		assert.Contains(t, inconsistencyErr.Reason, "does not match the hash of the block data")
+	})
+
+	t.Run("PreviousHash", func(t *testing.T) {
+		blocks := testutil.ConstructTestBlocks(t, 6)
+		blocks[5].Header.PreviousHash = []byte("unexpected previous hash")
+		conf := NewConf(testPath(), 0)
+		env := newTestEnv(t, conf)
+		defer env.Cleanup()
+		blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+		mgr := blkfileMgrWrapper.blockfileMgr
+		blkfileMgrWrapper.addBlocks(blocks[:5])
+		// bypass the check of the previous hash on the append of a block
+		bcInfo := proto.Clone(mgr.getBlockchainInfo()).(*common.BlockchainInfo)
+		bcInfo.CurrentBlockHash = blocks[5].Header.PreviousHash
+		mgr.bcInfo.Store(bcInfo)
+		blkfileMgrWrapper.addBlocks(blocks[5:])
+		blkfileMgrWrapper.close()
+		env.provider.Close()
+
+		numBlocks, err := VerifyBlockStore(conf.blockStorageDir, "testLedger", verifyTestIndexConfig, nil)
+		require.Error(t, err)
+		assert.Equal(t, uint64(5), numBlocks)
+		inconsistencyErr, ok := err.(*BlockStoreInconsistencyError)
+		require.True(t, ok)
+		assert.Equal(t, uint64(5), inconsistencyErr.BlockNum)
+		assert.Contains(t, inconsistencyErr.Reason, "does not match the hash of the header of block [4]")
+	})
+}
+
+func TestVerifyBlockStoreIncompleteLastBlock(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 5)
+	conf := NewConfWithCompression(testPath(), 0, CompressionSnappy)
+	env := newTestEnv(t, conf)
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	blkfileMgrWrapper.addBlocks(blocks)
+	mgr := blkfileMgrWrapper.blockfileMgr
+	lastBlockLoc, err := mgr.index.getBlockLocByBlockNum(4)
+	require.NoError(t, err)
+	// simulate a crash during the append of the last block, before it was indexed
+	flipByte(t, deriveBlockfilePath(mgr.rootDir, 0), int64(lastBlockLoc.offset+5))
+	require.NoError(t, env.provider.leveldbProvider.GetDBHandle("testLedger").Put(indexCheckpointKey, encodeBlockNum(3), true))
+	blkfileMgrWrapper.close()
+	env.provider.Close()
+
+	numBlocks, err := VerifyBlockStore(conf.blockStorageDir, "testLedger", verifyTestIndexConfig, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), numBlocks)
+}
@@ -9,6 +9,7 @@ package leveldbhelper
 import (
 	"fmt"
 	"sync"
+	"syscall"
 
 	"justledger/common/flogging"
 	"justledger/common/ledger/util"
@@ -154,3 +155,48 @@ func (dbInst *DB) WriteBatch(batch *leveldb.Batch, sync bool) error {
 	}
 	return nil
 }
+
+// FileLock is an exclusive lock on a directory that is held by at most one process at a time.
+// It is backed by the lock that leveldb acquires on the directory of an open db
+type FileLock struct {
+	db       *leveldb.DB
+	filePath string
+}
+
+// NewFileLock constructs a `FileLock` for the given directory
+func NewFileLock(filePath string) *FileLock {
+	return &FileLock{filePath: filePath}
+}
+
+// Lock acquires the lock by opening a leveldb in the directory. An error is returned
+// if the lock is already held, either by another process or by this one
+func (f *FileLock) Lock() error {
+	dbOpts := &opt.Options{}
+	var err error
+	var dirEmpty bool
+	if dirEmpty, err = util.CreateDirIfMissing(f.filePath); err != nil {
+		panic(fmt.Sprintf("Error creating dir if missing: %s", err))
+	}
+	dbOpts.ErrorIfMissing = !dirEmpty
+	db, err := leveldb.OpenFile(f.filePath, dbOpts)
+	if err == syscall.EAGAIN {
+		return errors.Errorf("lock is already acquired on file %s", f.filePath)
+	}
+	if err != nil {
+		panic(fmt.Sprintf("Error acquiring lock on file %s: %s", f.filePath, err))
+	}
+	f.db = db
+	return nil
+}
+
+// Unlock releases the lock. It is safe to call Unlock on a lock that is not held
+func (f *FileLock) Unlock() {
+	if f.db == nil {
+		return
+	}
+	if err := f.db.Close(); err != nil {
+		logger.Warningf("Error releasing lock on file %s: %s", f.filePath, err)
+		return
+	}
+	f.db = nil
+}
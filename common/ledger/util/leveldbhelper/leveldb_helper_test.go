@@ -17,6 +17,7 @@ limitations under the License.
 package leveldbhelper
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -137,3 +138,21 @@ func TestCreateDBInNonEmptyDir(t *testing.T) {
 	}()
 	db.Open()
 }
+
+func TestFileLock(t *testing.T) {
+	assert.NoError(t, os.RemoveAll(testDBPath), "")
+	defer os.RemoveAll(testDBPath)
+
+	fileLock := NewFileLock(testDBPath)
+	assert.NoError(t, fileLock.Lock())
+
+	// the lock cannot be acquired a second time, even by the same process
+	anotherLock := NewFileLock(testDBPath)
+	assert.EqualError(t, anotherLock.Lock(), fmt.Sprintf("lock is already acquired on file %s", testDBPath))
+	anotherLock.Unlock()
+
+	fileLock.Unlock()
+	fileLock.Unlock()
+	assert.NoError(t, anotherLock.Lock())
+	anotherLock.Unlock()
+}
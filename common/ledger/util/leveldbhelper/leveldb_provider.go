@@ -113,6 +113,34 @@ func (h *DBHandle) GetIterator(startKey []byte, endKey []byte) *Iterator {
 	return &Iterator{h.db.GetIterator(sKey, eKey)}
 }
 
+// maxBatchSizeForDeleteAll limits the number of keys that DeleteAll deletes in a single batch
+const maxBatchSizeForDeleteAll = 10000
+
+// DeleteAll deletes all the keys that belong to the named db.
+// The keys are deleted in batches and hence the deletion is not atomic
+func (h *DBHandle) DeleteAll() error {
+	itr := h.GetIterator(nil, nil)
+	defer itr.Release()
+	numKeys := 0
+	batch := &leveldb.Batch{}
+	for itr.Next() {
+		batch.Delete(constructLevelKey(h.dbName, itr.Key()))
+		numKeys++
+		if batch.Len() < maxBatchSizeForDeleteAll {
+			continue
+		}
+		if err := h.db.WriteBatch(batch, true); err != nil {
+			return err
+		}
+		batch.Reset()
+	}
+	if err := itr.Error(); err != nil {
+		return err
+	}
+	logger.Debugf("Deleting [%d] keys of db [%s]", numKeys, h.dbName)
+	return h.db.WriteBatch(batch, true)
+}
+
 // UpdateBatch encloses the details of multiple `updates`
 type UpdateBatch struct {
 	KVs map[string][]byte
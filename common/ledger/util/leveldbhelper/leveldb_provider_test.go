@@ -90,6 +90,36 @@ func TestBatchedUpdates(t *testing.T) {
 	}
 }
 
+func TestDeleteAll(t *testing.T) {
+	env := newTestProviderEnv(t, testDBPath)
+	defer env.cleanup()
+	p := env.provider
+
+	db1 := p.GetDBHandle("db1")
+	db2 := p.GetDBHandle("db2")
+	db11 := p.GetDBHandle("db1/1")
+	numKeys := maxBatchSizeForDeleteAll + 5
+	for _, db := range []*DBHandle{db1, db2, db11} {
+		batch := NewUpdateBatch()
+		for i := 0; i < numKeys; i++ {
+			batch.Put([]byte(createTestKey(i)), []byte(createTestValue(db.dbName, i)))
+		}
+		assert.NoError(t, db.WriteBatch(batch, true))
+	}
+
+	assert.NoError(t, db1.DeleteAll())
+	itr := db1.GetIterator(nil, nil)
+	defer itr.Release()
+	assert.False(t, itr.Next())
+
+	// the other dbs are not affected
+	checkItrResults(t, db2.GetIterator(nil, nil), createTestKeys(0, numKeys-1), createTestValues("db2", 0, numKeys-1))
+	checkItrResults(t, db11.GetIterator(nil, nil), createTestKeys(0, numKeys-1), createTestValues("db1/1", 0, numKeys-1))
+
+	// deleting an empty db is a no-op
+	assert.NoError(t, db1.DeleteAll())
+}
+
 func testDBBasicWriteAndReads(t *testing.T, dbNames ...string) {
 	env := newTestProviderEnv(t, testDBPath)
 	defer env.cleanup()
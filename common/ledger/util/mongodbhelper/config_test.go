@@ -19,12 +19,12 @@ package mongodbhelper
 import (
 	"testing"
 
-	"justledger/common/ledger/testutil"
 	ledgertestutil "justledger/core/ledger/testutil"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestGetCouchDBDefinition(t *testing.T) {
 	ledgertestutil.SetupCoreYAMLConfig()
 	conf := GetMongoDBConf()
-	testutil.AssertEquals(t, conf.CollectionName, "test")
+	assert.Equal(t, "test", conf.CollectionName)
 }
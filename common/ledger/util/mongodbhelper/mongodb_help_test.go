@@ -22,10 +22,10 @@ import (
 	"os"
 	"testing"
 
-	"justledger/common/ledger/testutil"
 	ledgertestutil "justledger/core/ledger/testutil"
 
 	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
 	"gopkg.in/mgo.v2"
 )
 
@@ -45,14 +45,14 @@ func TestMain(m *testing.M) {
 
 func TestMongoDBQueryDocumentPagingComplex(t *testing.T) {
 	session, err := mgo.Dial(mongoDBConf.Url)
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 
 	db := session.DB(mongoDBConf.DBName)
 	mongoDB := &MongoDB{db, mongoDBConf}
 
 	query := "{\"owner\":\"fred\"}"
 	queryBson, err := GetQueryBson("ns2", query)
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 
 	pageInfo := &PagingOrQuery{
 		PagingInfo: &PagingInfo{
@@ -70,20 +70,20 @@ func TestMongoDBQueryDocumentPagingComplex(t *testing.T) {
 	}
 
 	pageResult, _, err := mongoDB.QueryDocumentPagingComplex(pageInfo)
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 
-	testutil.AssertEquals(t, pageResult.LastQueryPageNum, 0)
+	assert.Equal(t, 0, pageResult.LastQueryPageNum)
 }
 
 func Benchmark_MongoDBQueryDocumentPagingComplex(b *testing.B) {
 	b.StopTimer()
 
 	session, err := mgo.Dial(mongoDBConf.Url)
-	testutil.AssertNoError(b, err, "")
+	assert.NoError(b, err)
 
 	db := session.DB(mongoDBConf.DBName)
 	mongoDB := &MongoDB{db, mongoDBConf}
-	testutil.AssertNoError(b, err, "")
+	assert.NoError(b, err)
 
 	query := "{\"owner\":\"fred\"}"
 	queryBson, err := GetQueryBson("ns2", query)
@@ -104,7 +104,7 @@ func Benchmark_MongoDBQueryDocumentPagingComplex(b *testing.B) {
 	}
 
 	pageResult, docs, err := mongoDB.QueryDocumentPagingComplex(pageInfo)
-	testutil.AssertNoError(b, err, "")
+	assert.NoError(b, err)
 	pageResultJson, _ := json.Marshal(docs)
 	logger.Infof(string(pageResultJson))
 
@@ -120,7 +120,7 @@ func Benchmark_MongoDBQueryDocumentPagingComplex(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		pageResult, docs, err = mongoDB.QueryDocumentPagingComplex(pageInfo)
 	}
-	testutil.AssertNoError(b, err, "")
+	assert.NoError(b, err)
 
 }
 
@@ -135,7 +135,7 @@ func TestMongoDBOpen(t *testing.T) {
 
 	session, err := mgo.Dial(mongoDBConf.Url)
 	fmt.Println(mongoDBConf.Url)
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 
 	db := session.DB(mongoDBConf.DBName)
 	mongoDB := &MongoDB{db, mongoDBConf}
@@ -154,7 +154,7 @@ func BenchmarkMongoDB_SaveDoc(b *testing.B) {
 	b.StopTimer()
 
 	session, err := mgo.Dial(mongoDBConf.Url)
-	testutil.AssertNoError(b, err, "")
+	assert.NoError(b, err)
 
 	db := session.DB(mongoDBConf.DBName)
 	mongoDB := &MongoDB{db, mongoDBConf}
@@ -156,6 +156,35 @@ func (mongoDB *MongoDB) GetDoc(ns, key string) (*MongodbDoc, error) {
 	return &resultDoc, nil
 }
 
+// maxKeysPerVersionsQuery limits the number of keys in the `$in` operator of a single query issued by GetVersions
+const maxKeysPerVersionsQuery = 1000
+
+// GetVersions retrieves the versions of the given keys of the namespace with `$in` queries.
+// Only the key and the version of the docs are fetched. The keys that do not exist are absent in the returned map
+func (mongoDB *MongoDB) GetVersions(ns string, keys []string) (map[string]*version.Height, error) {
+	collection := mongoDB.GetDefaultCollection()
+	versions := make(map[string]*version.Height, len(keys))
+	for start := 0; start < len(keys); start += maxKeysPerVersionsQuery {
+		end := start + maxKeysPerVersionsQuery
+		if end > len(keys) {
+			end = len(keys)
+		}
+		iter := collection.Find(bson.M{NS: ns, KEY: bson.M{"$in": keys[start:end]}}).
+			Select(bson.M{KEY: 1, VERSION: 1}).Iter()
+		var doc MongodbDoc
+		for iter.Next(&doc) {
+			ver := doc.Version
+			versions[doc.Key] = &ver
+			doc = MongodbDoc{}
+		}
+		if err := iter.Close(); err != nil {
+			logger.Errorf("Error while retrieving the versions of [%d] keys of namespace [%s], error : %s", end-start, ns, err.Error())
+			return nil, err
+		}
+	}
+	return versions, nil
+}
+
 //Simple query method which don't use paging
 //Get the limit number result of query
 func (mongoDB *MongoDB) QueryDocuments(query interface{}) (*mgo.Iter, error) {
@@ -26,6 +26,7 @@ import (
 KEY is used as the item referring to key stored in mongodb.
 KEY and NS will be a unique index build by default to query.
 ID used for paging query function whose result is sorted by "_id"(can't be changed).
+VERSION is used as the item referring to the version of the key stored in mongodb.
 The detial about paging query will be found in mongodb_page.go
 dataWrapper
 */
@@ -33,6 +34,7 @@ const KEY = "key"
 const dataWrapper = "value"
 const NS = "chaincodeid"
 const ID = "_id"
+const VERSION = "version"
 
 // Vaild operations in mongodb query
 var VALID_OPERATORS = []string{
@@ -0,0 +1,60 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+// auditlog is a command line tool that verifies the integrity of the
+// audit log of administrative operations kept by peers and orderers
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"justledger/common/audit"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+// command line flags
+var (
+	app = kingpin.New("auditlog", "Utility for verifying the audit log of administrative operations of peers and orderers")
+
+	verify     = app.Command("verify", "Verify that the entries of an audit log are intact and correctly chained")
+	verifyFile = verify.Flag("file", "The audit log to verify").Short('f').Required().String()
+)
+
+func main() {
+	app.HelpFlag.Short('h')
+
+	switch kingpin.MustParse(app.Parse(os.Args[1:])) {
+	case verify.FullCommand():
+		if err := verifyLog(os.Stdout, *verifyFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Verification of %s failed: %s\n", *verifyFile, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// verifyLog verifies the audit log at path and reports its last entry to out
+func verifyLog(out io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	last, err := audit.Verify(f)
+	if err != nil {
+		return err
+	}
+	if last == nil {
+		fmt.Fprintf(out, "%s is empty\n", path)
+		return nil
+	}
+	fmt.Fprintf(out, "%s is intact: %d entries, last recorded at %s with hash %s\n",
+		path, last.Seq, last.Timestamp.Format("2006-01-02T15:04:05Z07:00"), last.Hash)
+	return nil
+}
@@ -0,0 +1,48 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"justledger/common/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auditlog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+
+	out := &bytes.Buffer{}
+	assert.Error(t, verifyLog(out, path))
+
+	l, err := audit.NewLog(path)
+	require.NoError(t, err)
+	assert.NoError(t, verifyLog(out, path))
+	assert.Contains(t, out.String(), "is empty")
+
+	l.Record(nil, "JoinChain", "mychannel", nil)
+	l.Record(nil, "JoinChain", "otherchannel", nil)
+	require.NoError(t, l.Close())
+	out.Reset()
+	assert.NoError(t, verifyLog(out, path))
+	assert.Contains(t, out.String(), "is intact: 2 entries")
+
+	raw, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(path, bytes.Replace(raw, []byte("otherchannel"), []byte("evilchannel"), 1), 0600))
+	err = verifyLog(out, path)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2: hash of entry 2 does not match its content")
+}
@@ -7,12 +7,16 @@ package ca_test
 
 import (
 	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/x509"
 	"net"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"justledger/bccsp"
 	"justledger/common/tools/cryptogen/ca"
 	"justledger/common/tools/cryptogen/csp"
 	"github.com/stretchr/testify/assert"
@@ -101,6 +105,109 @@ func TestNewCA(t *testing.T) {
 
 }
 
+func TestNewCAWithKeyAlgorithm(t *testing.T) {
+	defer cleanup(testDir)
+
+	caDir := filepath.Join(testDir, "ca")
+	certDir := filepath.Join(testDir, "certs")
+	rootCA, err := ca.NewCAWithKeyAlgorithm(caDir, testCAName, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode, bccsp.ED25519)
+	assert.NoError(t, err, "Error generating CA")
+	assert.Equal(t, bccsp.ED25519, rootCA.KeyAlgorithm)
+	assert.IsType(t, ed25519.PublicKey{}, rootCA.SignCert.PublicKey)
+	assert.Equal(t, x509.PureEd25519, rootCA.SignCert.SignatureAlgorithm)
+
+	// an Ed25519 CA certifies both Ed25519 and ECDSA keys
+	for _, keyAlg := range []string{bccsp.ED25519, bccsp.ECDSA} {
+		priv, _, err := csp.GeneratePrivateKeyWithAlgorithm(certDir, keyAlg)
+		assert.NoError(t, err)
+		pubKey, err := csp.GetPublicKey(priv)
+		assert.NoError(t, err)
+
+		cert, err := rootCA.SignCertificate(certDir, testName, nil, nil, pubKey,
+			x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{x509.ExtKeyUsageAny})
+		assert.NoError(t, err, "Failed to generate signed certificate")
+		assert.Equal(t, pubKey, cert.PublicKey)
+		assert.NoError(t, cert.CheckSignatureFrom(rootCA.SignCert))
+	}
+
+	_, err = ca.NewCAWithKeyAlgorithm(caDir, testCA2Name, testCA2Name, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode, "DSA")
+	assert.EqualError(t, err, "unsupported key algorithm: DSA")
+}
+
+func TestNewCAWithOptions(t *testing.T) {
+	defer cleanup(testDir)
+
+	certDir := filepath.Join(testDir, "certs")
+	rootCA, err := ca.NewCAWithOptions(filepath.Join(testDir, "ca"), testCAName, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode,
+		ca.Options{KeyAlgorithm: bccsp.ECDSAP384, CAValidity: 48 * time.Hour})
+	assert.NoError(t, err, "Error generating CA")
+	assert.Equal(t, elliptic.P384(), rootCA.SignCert.PublicKey.(*ecdsa.PublicKey).Curve)
+	assert.Equal(t, 48*time.Hour, rootCA.SignCert.NotAfter.Sub(rootCA.SignCert.NotBefore))
+	assert.Equal(t, rootCA.SignCert, rootCA.RootCert())
+	assert.Empty(t, rootCA.IntermediateCerts())
+
+	// the certificate of an intermediate CA never outlives the one of its parent
+	intermediateCA, err := ca.NewCAWithOptions(filepath.Join(testDir, "ica"), testCAName, testCA2Name, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode,
+		ca.Options{KeyAlgorithm: bccsp.ECDSA, Validity: time.Hour, Parent: rootCA})
+	assert.NoError(t, err, "Error generating intermediate CA")
+	assert.True(t, intermediateCA.SignCert.IsCA)
+	assert.NoError(t, intermediateCA.SignCert.CheckSignatureFrom(rootCA.SignCert))
+	assert.Equal(t, rootCA.SignCert.NotAfter, intermediateCA.SignCert.NotAfter)
+	assert.Equal(t, []*x509.Certificate{rootCA.SignCert}, intermediateCA.Chain)
+	assert.Equal(t, rootCA.SignCert, intermediateCA.RootCert())
+	assert.Equal(t, []*x509.Certificate{intermediateCA.SignCert}, intermediateCA.IntermediateCerts())
+
+	priv, _, err := csp.GeneratePrivateKey(certDir)
+	assert.NoError(t, err)
+	pubKey, err := csp.GetPublicKey(priv)
+	assert.NoError(t, err)
+	cert, err := intermediateCA.SignCertificate(certDir, testName, []string{"peer"}, []string{"peer0", testIP}, pubKey,
+		x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth})
+	assert.NoError(t, err, "Failed to generate signed certificate")
+	assert.Equal(t, time.Hour, cert.NotAfter.Sub(cert.NotBefore))
+
+	// the certificate verifies against the root CA through the intermediate CA
+	roots := x509.NewCertPool()
+	roots.AddCert(rootCA.SignCert)
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(intermediateCA.SignCert)
+	_, err = cert.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}})
+	assert.NoError(t, err)
+}
+
+func TestRenewCertificate(t *testing.T) {
+	defer cleanup(testDir)
+
+	certDir := filepath.Join(testDir, "certs")
+	rootCA, err := ca.NewCA(filepath.Join(testDir, "ca"), testCAName, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+
+	priv, _, err := csp.GeneratePrivateKey(certDir)
+	assert.NoError(t, err)
+	pubKey, err := csp.GetPublicKey(priv)
+	assert.NoError(t, err)
+	cert, err := rootCA.SignCertificate(certDir, testName, []string{"peer"}, []string{"peer0", testIP}, pubKey,
+		x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth})
+	assert.NoError(t, err, "Failed to generate signed certificate")
+
+	rootCA.Validity = 24 * time.Hour
+	renewed, err := rootCA.RenewCertificate(certDir, testName, cert, cert.PublicKey)
+	assert.NoError(t, err, "Failed to renew certificate")
+	assert.NotEqual(t, cert.SerialNumber, renewed.SerialNumber)
+	assert.Equal(t, 24*time.Hour, renewed.NotAfter.Sub(renewed.NotBefore))
+	assert.Equal(t, cert.Subject.String(), renewed.Subject.String())
+	assert.Equal(t, cert.DNSNames, renewed.DNSNames)
+	assert.True(t, cert.IPAddresses[0].Equal(renewed.IPAddresses[0]))
+	assert.Equal(t, cert.KeyUsage, renewed.KeyUsage)
+	assert.Equal(t, cert.ExtKeyUsage, renewed.ExtKeyUsage)
+	assert.Equal(t, cert.PublicKey, renewed.PublicKey)
+	assert.NoError(t, renewed.CheckSignatureFrom(rootCA.SignCert))
+
+	loadedCert, err := ca.LoadCertificateECDSA(certDir)
+	assert.NoError(t, err)
+	assert.Equal(t, renewed.SerialNumber, loadedCert.SerialNumber)
+}
+
 func TestGenerateSignCertificate(t *testing.T) {
 
 	caDir := filepath.Join(testDir, "ca")
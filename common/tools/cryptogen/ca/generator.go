@@ -7,7 +7,6 @@ package ca
 
 import (
 	"crypto"
-	"crypto/ecdsa"
 	"crypto/rand"
 	"crypto/x509"
 	"crypto/x509/pkix"
@@ -20,10 +19,15 @@ import (
 	"strings"
 	"time"
 
+	"justledger/bccsp"
 	"justledger/bccsp/utils"
 	"justledger/common/tools/cryptogen/csp"
 )
 
+// DefaultValidity is the validity period of the certificates of CAs, nodes
+// and users for which no other validity period is given
+const DefaultValidity = 3650 * 24 * time.Hour
+
 type CA struct {
 	Name               string
 	Country            string
@@ -35,68 +39,145 @@ type CA struct {
 	//SignKey  *ecdsa.PrivateKey
 	Signer   crypto.Signer
 	SignCert *x509.Certificate
+	// KeyAlgorithm is the algorithm of the keys (bccsp.ECDSA, bccsp.ECDSAP384
+	// or bccsp.ED25519) generated for the certificates issued by this CA.
+	// Empty means bccsp.ECDSA
+	KeyAlgorithm string
+	// Validity is the validity period of the certificates issued by this CA.
+	// Zero means DefaultValidity
+	Validity time.Duration
+	// Chain holds, for an intermediate CA, the certificates of the CAs above
+	// it, from the CA that issued SignCert up to the root CA. It is empty for
+	// a root CA
+	Chain []*x509.Certificate
+}
+
+// Options are the settings of a CA created by NewCAWithOptions
+type Options struct {
+	// KeyAlgorithm is the algorithm of the key pair of the CA and of the keys
+	// it certifies. Empty means bccsp.ECDSA
+	KeyAlgorithm string
+	// CAValidity is the validity period of the certificate of the CA.
+	// Zero means DefaultValidity
+	CAValidity time.Duration
+	// Validity is the validity period of the certificates issued by the CA.
+	// Zero means DefaultValidity
+	Validity time.Duration
+	// Parent is the CA that issues the certificate of an intermediate CA.
+	// Nil means that the CA is a self-signed root CA
+	Parent *CA
 }
 
 // NewCA creates an instance of CA and saves the signing key pair in
 // baseDir/name
 func NewCA(baseDir, org, name, country, province, locality, orgUnit, streetAddress, postalCode string) (*CA, error) {
+	return NewCAWithKeyAlgorithm(baseDir, org, name, country, province, locality, orgUnit, streetAddress, postalCode, bccsp.ECDSA)
+}
 
-	var response error
-	var ca *CA
+// NewCAWithKeyAlgorithm creates an instance of CA whose signing key pair,
+// saved in baseDir/name, is of the given algorithm (bccsp.ECDSA,
+// bccsp.ECDSAP384 or bccsp.ED25519)
+func NewCAWithKeyAlgorithm(baseDir, org, name, country, province, locality, orgUnit, streetAddress, postalCode, keyAlg string) (*CA, error) {
+	return NewCAWithOptions(baseDir, org, name, country, province, locality, orgUnit, streetAddress, postalCode, Options{KeyAlgorithm: keyAlg})
+}
+
+// NewCAWithOptions creates an instance of CA with the given options and saves
+// the signing key pair in baseDir/name. The certificate of the CA is
+// self-signed unless opts.Parent is set, in which case the CA is an
+// intermediate CA whose certificate is issued by opts.Parent
+func NewCAWithOptions(baseDir, org, name, country, province, locality, orgUnit, streetAddress, postalCode string, opts Options) (*CA, error) {
+	keyAlg := opts.KeyAlgorithm
+	if keyAlg == "" {
+		keyAlg = bccsp.ECDSA
+	}
 
 	err := os.MkdirAll(baseDir, 0755)
-	if err == nil {
-		priv, signer, err := csp.GeneratePrivateKey(baseDir)
-		response = err
-		if err == nil {
-			// get public signing certificate
-			ecPubKey, err := csp.GetECPublicKey(priv)
-			response = err
-			if err == nil {
-				template := x509Template()
-				//this is a CA
-				template.IsCA = true
-				template.KeyUsage |= x509.KeyUsageDigitalSignature |
-					x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign |
-					x509.KeyUsageCRLSign
-				template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageAny}
-
-				//set the organization for the subject
-				subject := subjectTemplateAdditional(country, province, locality, orgUnit, streetAddress, postalCode)
-				subject.Organization = []string{org}
-				subject.CommonName = name
-
-				template.Subject = subject
-				template.SubjectKeyId = priv.SKI()
-
-				x509Cert, err := genCertificateECDSA(baseDir, name, &template, &template,
-					ecPubKey, signer)
-				response = err
-				if err == nil {
-					ca = &CA{
-						Name:               name,
-						Signer:             signer,
-						SignCert:           x509Cert,
-						Country:            country,
-						Province:           province,
-						Locality:           locality,
-						OrganizationalUnit: orgUnit,
-						StreetAddress:      streetAddress,
-						PostalCode:         postalCode,
-					}
-				}
-			}
-		}
+	if err != nil {
+		return nil, err
 	}
-	return ca, response
+	priv, signer, err := csp.GeneratePrivateKeyWithAlgorithm(baseDir, keyAlg)
+	if err != nil {
+		return nil, err
+	}
+	// get public signing certificate
+	pubKey, err := csp.GetPublicKey(priv)
+	if err != nil {
+		return nil, err
+	}
+
+	template := x509Template(opts.CAValidity)
+	//this is a CA
+	template.IsCA = true
+	template.KeyUsage |= x509.KeyUsageDigitalSignature |
+		x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign |
+		x509.KeyUsageCRLSign
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageAny}
+
+	//set the organization for the subject
+	subject := subjectTemplateAdditional(country, province, locality, orgUnit, streetAddress, postalCode)
+	subject.Organization = []string{org}
+	subject.CommonName = name
+
+	template.Subject = subject
+	template.SubjectKeyId = priv.SKI()
+
+	// a root CA signs its own certificate, an intermediate CA has it
+	// signed by its parent
+	parentCert, parentSigner := &template, signer
+	var chain []*x509.Certificate
+	if opts.Parent != nil {
+		parentCert, parentSigner = opts.Parent.SignCert, opts.Parent.Signer
+		chain = append([]*x509.Certificate{opts.Parent.SignCert}, opts.Parent.Chain...)
+		capNotAfter(&template, parentCert)
+	}
+
+	x509Cert, err := genCertificate(baseDir, name, &template, parentCert,
+		pubKey, parentSigner)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CA{
+		Name:               name,
+		Signer:             signer,
+		SignCert:           x509Cert,
+		Country:            country,
+		Province:           province,
+		Locality:           locality,
+		OrganizationalUnit: orgUnit,
+		StreetAddress:      streetAddress,
+		PostalCode:         postalCode,
+		KeyAlgorithm:       keyAlg,
+		Validity:           opts.Validity,
+		Chain:              chain,
+	}, nil
+}
+
+// RootCert returns the certificate of the root CA of the chain of this CA,
+// which is SignCert for a root CA
+func (ca *CA) RootCert() *x509.Certificate {
+	if len(ca.Chain) == 0 {
+		return ca.SignCert
+	}
+	return ca.Chain[len(ca.Chain)-1]
+}
+
+// IntermediateCerts returns the certificates of the intermediate CAs of the
+// chain of this CA, starting with SignCert for an intermediate CA. It is
+// empty for a root CA
+func (ca *CA) IntermediateCerts() []*x509.Certificate {
+	if len(ca.Chain) == 0 {
+		return nil
+	}
+	return append([]*x509.Certificate{ca.SignCert}, ca.Chain[:len(ca.Chain)-1]...)
 }
 
 // SignCertificate creates a signed certificate based on a built-in template
 // and saves it in baseDir/name
-func (ca *CA) SignCertificate(baseDir, name string, ous, sans []string, pub *ecdsa.PublicKey,
+func (ca *CA) SignCertificate(baseDir, name string, ous, sans []string, pub crypto.PublicKey,
 	ku x509.KeyUsage, eku []x509.ExtKeyUsage) (*x509.Certificate, error) {
 
-	template := x509Template()
+	template := x509Template(ca.Validity)
 	template.KeyUsage = ku
 	template.ExtKeyUsage = eku
 
@@ -116,8 +197,11 @@ func (ca *CA) SignCertificate(baseDir, name string, ous, sans []string, pub *ecd
 			template.DNSNames = append(template.DNSNames, san)
 		}
 	}
+	if ca.SignCert != nil {
+		capNotAfter(&template, ca.SignCert)
+	}
 
-	cert, err := genCertificateECDSA(baseDir, name, &template, ca.SignCert,
+	cert, err := genCertificate(baseDir, name, &template, ca.SignCert,
 		pub, ca.Signer)
 
 	if err != nil {
@@ -127,6 +211,22 @@ func (ca *CA) SignCertificate(baseDir, name string, ous, sans []string, pub *ecd
 	return cert, nil
 }
 
+// RenewCertificate issues a new certificate, with a new serial number and
+// validity period, for the subject, subject alternative names and key usages
+// of the given certificate and the given public key, which may be the one of
+// cert, and saves it in baseDir/name
+func (ca *CA) RenewCertificate(baseDir, name string, cert *x509.Certificate, pub crypto.PublicKey) (*x509.Certificate, error) {
+	template := x509Template(ca.Validity)
+	template.Subject = cert.Subject
+	template.KeyUsage = cert.KeyUsage
+	template.ExtKeyUsage = cert.ExtKeyUsage
+	template.DNSNames = cert.DNSNames
+	template.IPAddresses = cert.IPAddresses
+	capNotAfter(&template, ca.SignCert)
+
+	return genCertificate(baseDir, name, &template, ca.SignCert, pub, ca.Signer)
+}
+
 // default template for X509 subject
 func subjectTemplate() pkix.Name {
 	return pkix.Name{
@@ -161,15 +261,17 @@ func subjectTemplateAdditional(country, province, locality, orgUnit, streetAddre
 	return name
 }
 
-// default template for X509 certificates
-func x509Template() x509.Certificate {
+// default template for X509 certificates valid for the given period,
+// or DefaultValidity if it is zero
+func x509Template(expiry time.Duration) x509.Certificate {
 
 	// generate a serial number
 	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
 	serialNumber, _ := rand.Int(rand.Reader, serialNumberLimit)
 
-	// set expiry to around 10 years
-	expiry := 3650 * 24 * time.Hour
+	if expiry <= 0 {
+		expiry = DefaultValidity
+	}
 	// round minute and backdate 5 minutes
 	notBefore := time.Now().Round(time.Minute).Add(-5 * time.Minute).UTC()
 
@@ -184,8 +286,16 @@ func x509Template() x509.Certificate {
 
 }
 
-// generate a signed X509 certificate using ECDSA
-func genCertificateECDSA(baseDir, name string, template, parent *x509.Certificate, pub *ecdsa.PublicKey,
+// capNotAfter makes a certificate issued by the given issuer expire no later
+// than the certificate of the issuer
+func capNotAfter(template, issuer *x509.Certificate) {
+	if template.NotAfter.After(issuer.NotAfter) {
+		template.NotAfter = issuer.NotAfter
+	}
+}
+
+// generate a signed X509 certificate for an ECDSA or Ed25519 public key
+func genCertificate(baseDir, name string, template, parent *x509.Certificate, pub crypto.PublicKey,
 	priv interface{}) (*x509.Certificate, error) {
 
 	//create the x509 public cert
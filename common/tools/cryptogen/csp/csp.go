@@ -8,8 +8,10 @@ package csp
 import (
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/x509"
 	"encoding/pem"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -18,6 +20,7 @@ import (
 	"justledger/bccsp"
 	"justledger/bccsp/factory"
 	"justledger/bccsp/signer"
+	"justledger/bccsp/utils"
 )
 
 // LoadPrivateKey loads a private key from file in keystorePath
@@ -51,7 +54,13 @@ func LoadPrivateKey(keystorePath string) (bccsp.Key, crypto.Signer, error) {
 			}
 
 			block, _ := pem.Decode(rawKey)
-			priv, err = csp.KeyImport(block.Bytes, &bccsp.ECDSAPrivateKeyImportOpts{Temporary: true})
+			var importOpts bccsp.KeyImportOpts = &bccsp.ECDSAPrivateKeyImportOpts{Temporary: true}
+			if key, err := utils.DERToPrivateKey(block.Bytes); err == nil {
+				if _, isED25519 := key.(ed25519.PrivateKey); isED25519 {
+					importOpts = &bccsp.ED25519PrivateKeyImportOpts{Temporary: true}
+				}
+			}
+			priv, err = csp.KeyImport(block.Bytes, importOpts)
 			if err != nil {
 				return err
 			}
@@ -74,14 +83,34 @@ func LoadPrivateKey(keystorePath string) (bccsp.Key, crypto.Signer, error) {
 	return priv, s, err
 }
 
-// GeneratePrivateKey creates a private key and stores it in keystorePath
+// GeneratePrivateKey creates an ECDSA private key and stores it in keystorePath
 func GeneratePrivateKey(keystorePath string) (bccsp.Key,
 	crypto.Signer, error) {
+	return GeneratePrivateKeyWithAlgorithm(keystorePath, bccsp.ECDSA)
+}
+
+// GeneratePrivateKeyWithAlgorithm creates a private key of the given algorithm
+// (bccsp.ECDSA or bccsp.ECDSAP256 for ECDSA over curve P-256, bccsp.ECDSAP384
+// for ECDSA over curve P-384, or bccsp.ED25519) and stores it in keystorePath
+func GeneratePrivateKeyWithAlgorithm(keystorePath, keyAlg string) (bccsp.Key,
+	crypto.Signer, error) {
 
 	var err error
 	var priv bccsp.Key
 	var s crypto.Signer
 
+	var keyGenOpts bccsp.KeyGenOpts
+	switch keyAlg {
+	case bccsp.ECDSA, bccsp.ECDSAP256:
+		keyGenOpts = &bccsp.ECDSAP256KeyGenOpts{Temporary: false}
+	case bccsp.ECDSAP384:
+		keyGenOpts = &bccsp.ECDSAP384KeyGenOpts{Temporary: false}
+	case bccsp.ED25519:
+		keyGenOpts = &bccsp.ED25519KeyGenOpts{Temporary: false}
+	default:
+		return nil, nil, fmt.Errorf("unsupported key algorithm: %s", keyAlg)
+	}
+
 	opts := &factory.FactoryOpts{
 		ProviderName: "SW",
 		SwOpts: &factory.SwOpts{
@@ -96,7 +125,7 @@ func GeneratePrivateKey(keystorePath string) (bccsp.Key,
 	csp, err := factory.GetBCCSPFromOpts(opts)
 	if err == nil {
 		// generate a key
-		priv, err = csp.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: false})
+		priv, err = csp.KeyGen(keyGenOpts)
 		if err == nil {
 			// create a crypto.Signer
 			s, err = signer.New(csp, priv)
@@ -105,7 +134,9 @@ func GeneratePrivateKey(keystorePath string) (bccsp.Key,
 	return priv, s, err
 }
 
-func GetECPublicKey(priv bccsp.Key) (*ecdsa.PublicKey, error) {
+// GetPublicKey returns the public key of priv as a Go public key,
+// that is an *ecdsa.PublicKey or an ed25519.PublicKey
+func GetPublicKey(priv bccsp.Key) (crypto.PublicKey, error) {
 
 	// get the public key
 	pubKey, err := priv.PublicKey()
@@ -118,9 +149,17 @@ func GetECPublicKey(priv bccsp.Key) (*ecdsa.PublicKey, error) {
 		return nil, err
 	}
 	// unmarshal using pkix
-	ecPubKey, err := x509.ParsePKIXPublicKey(pubKeyBytes)
+	return x509.ParsePKIXPublicKey(pubKeyBytes)
+}
+
+func GetECPublicKey(priv bccsp.Key) (*ecdsa.PublicKey, error) {
+	pubKey, err := GetPublicKey(priv)
 	if err != nil {
 		return nil, err
 	}
-	return ecPubKey.(*ecdsa.PublicKey), nil
+	ecPubKey, ok := pubKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is of type %T, not *ecdsa.PublicKey", pubKey)
+	}
+	return ecPubKey, nil
 }
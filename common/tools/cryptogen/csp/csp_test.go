@@ -7,6 +7,8 @@ package csp_test
 
 import (
 	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"encoding/hex"
 	"errors"
 	"os"
@@ -73,6 +75,46 @@ func TestGeneratePrivateKey(t *testing.T) {
 
 }
 
+func TestGeneratePrivateKeyWithAlgorithm(t *testing.T) {
+	defer cleanup(testDir)
+
+	priv, signer, err := csp.GeneratePrivateKeyWithAlgorithm(testDir, bccsp.ED25519)
+	assert.NoError(t, err, "Failed to generate private key")
+	assert.Equal(t, true, priv.Private(), "Failed to return private key")
+	assert.IsType(t, ed25519.PublicKey{}, signer.Public())
+
+	pubKey, err := csp.GetPublicKey(priv)
+	assert.NoError(t, err, "Failed to get public key from private key")
+	assert.Equal(t, signer.Public(), pubKey)
+
+	_, err = csp.GetECPublicKey(priv)
+	assert.EqualError(t, err, "public key is of type ed25519.PublicKey, not *ecdsa.PublicKey")
+
+	loadedPriv, loadedSigner, err := csp.LoadPrivateKey(testDir)
+	assert.NoError(t, err)
+	assert.Equal(t, priv.SKI(), loadedPriv.SKI(), "Should have same subject identifier")
+	assert.Equal(t, signer.Public(), loadedSigner.Public())
+
+	_, _, err = csp.GeneratePrivateKeyWithAlgorithm(testDir, "DSA")
+	assert.EqualError(t, err, "unsupported key algorithm: DSA")
+}
+
+func TestGeneratePrivateKeyP384(t *testing.T) {
+	defer cleanup(testDir)
+
+	priv, signer, err := csp.GeneratePrivateKeyWithAlgorithm(testDir, bccsp.ECDSAP384)
+	assert.NoError(t, err, "Failed to generate private key")
+	ecPubKey, err := csp.GetECPublicKey(priv)
+	assert.NoError(t, err, "Failed to get public key from private key")
+	assert.Equal(t, elliptic.P384(), ecPubKey.Curve)
+	assert.Equal(t, signer.Public(), ecPubKey)
+
+	loadedPriv, loadedSigner, err := csp.LoadPrivateKey(testDir)
+	assert.NoError(t, err)
+	assert.Equal(t, priv.SKI(), loadedPriv.SKI(), "Should have same subject identifier")
+	assert.Equal(t, signer.Public(), loadedSigner.Public())
+}
+
 func TestGetECPublicKey(t *testing.T) {
 
 	priv, _, err := csp.GeneratePrivateKey(testDir)
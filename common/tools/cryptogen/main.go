@@ -7,13 +7,20 @@ package main
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/x509"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"text/template"
+	"time"
 
+	"justledger/bccsp"
 	"justledger/common/tools/cryptogen/ca"
 	"justledger/common/tools/cryptogen/csp"
 	"justledger/common/tools/cryptogen/metadata"
@@ -64,14 +71,27 @@ type UsersSpec struct {
 	Count int `yaml:"Count"`
 }
 
+type ValiditySpec struct {
+	CA   string `yaml:"CA"`
+	Node string `yaml:"Node"`
+
+	// the parsed validity periods, zero if not set
+	ca   time.Duration
+	node time.Duration
+}
+
 type OrgSpec struct {
-	Name          string       `yaml:"Name"`
-	Domain        string       `yaml:"Domain"`
-	EnableNodeOUs bool         `yaml:"EnableNodeOUs"`
-	CA            NodeSpec     `yaml:"CA"`
-	Template      NodeTemplate `yaml:"Template"`
-	Specs         []NodeSpec   `yaml:"Specs"`
-	Users         UsersSpec    `yaml:"Users"`
+	Name            string       `yaml:"Name"`
+	Domain          string       `yaml:"Domain"`
+	EnableNodeOUs   bool         `yaml:"EnableNodeOUs"`
+	KeyAlgorithm    string       `yaml:"KeyAlgorithm"`
+	Curve           string       `yaml:"Curve"`
+	Validity        ValiditySpec `yaml:"Validity"`
+	IntermediateCAs int          `yaml:"IntermediateCAs"`
+	CA              NodeSpec     `yaml:"CA"`
+	Template        NodeTemplate `yaml:"Template"`
+	Specs           []NodeSpec   `yaml:"Specs"`
+	Users           UsersSpec    `yaml:"Users"`
 }
 
 type Config struct {
@@ -107,6 +127,45 @@ PeerOrgs:
     Domain: org1.example.com
     EnableNodeOUs: false
 
+    # ---------------------------------------------------------------------------
+    # "KeyAlgorithm"
+    # ---------------------------------------------------------------------------
+    # The algorithm of the keys of the CAs, nodes and users of this organization:
+    # ECDSA (default) or ED25519
+    # ---------------------------------------------------------------------------
+    # KeyAlgorithm: ECDSA
+
+    # ---------------------------------------------------------------------------
+    # "Curve"
+    # ---------------------------------------------------------------------------
+    # The curve of the ECDSA keys: P-256 (default) or P-384
+    # ---------------------------------------------------------------------------
+    # Curve: P-256
+
+    # ---------------------------------------------------------------------------
+    # "Validity"
+    # ---------------------------------------------------------------------------
+    # The validity periods of the certificates of the CAs (CA) and of the nodes
+    # and users (Node) of this organization, as durations such as 8760h.  Both
+    # default to 87600h, that is about 10 years.  A certificate never outlives
+    # the certificate of the CA that issued it.  "cryptogen renew" issues the
+    # certificates of the nodes and users for the Node validity period.
+    # ---------------------------------------------------------------------------
+    # Validity:
+    #   CA: 87600h
+    #   Node: 8760h
+
+    # ---------------------------------------------------------------------------
+    # "IntermediateCAs"
+    # ---------------------------------------------------------------------------
+    # The number of intermediate CAs chained below each of the root CA and the
+    # TLS root CA of this organization (default 0).  The intermediate CAs
+    # ica1.<Domain>, ica2.<Domain>, ... and tlsica1.<Domain>, ... are generated
+    # in the ica1, ica2, ... and tlsica1, ... folders, and the last of them
+    # issues the certificates of the nodes and users.
+    # ---------------------------------------------------------------------------
+    # IntermediateCAs: 1
+
     # ---------------------------------------------------------------------------
     # "CA"
     # ---------------------------------------------------------------------------
@@ -210,6 +269,11 @@ var (
 	ext           = app.Command("extend", "Extend existing network")
 	inputDir      = ext.Flag("input", "The input directory in which existing network place").Default("crypto-config").String()
 	extConfigFile = ext.Flag("config", "The configuration template to use").File()
+
+	ren           = app.Command("renew", "Renew the certificates of the nodes and users of existing organizations")
+	renInputDir   = ren.Flag("input", "The input directory in which existing network place").Default("crypto-config").String()
+	renConfigFile = ren.Flag("config", "The configuration template to use").File()
+	renNewKeys    = ren.Flag("newKeys", "Generate new keys for the renewed certificates instead of keeping the current ones").Bool()
 )
 
 func main() {
@@ -223,6 +287,10 @@ func main() {
 	case ext.FullCommand():
 		extend()
 
+		// "renew" command
+	case ren.FullCommand():
+		renew()
+
 		// "showtemplate" command
 	case showtemplate.FullCommand():
 		fmt.Print(defaultConfig)
@@ -251,6 +319,13 @@ func getConfig() (*Config, error) {
 			return nil, fmt.Errorf("Error reading configuration: %s", err)
 		}
 
+		configData = string(data)
+	} else if *renConfigFile != nil {
+		data, err := ioutil.ReadAll(*renConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading configuration: %s", err)
+		}
+
 		configData = string(data)
 	} else {
 		configData = defaultConfig
@@ -302,11 +377,9 @@ func extendPeerOrg(orgSpec OrgSpec) {
 
 	peersDir := filepath.Join(orgDir, "peers")
 	usersDir := filepath.Join(orgDir, "users")
-	caDir := filepath.Join(orgDir, "ca")
-	tlscaDir := filepath.Join(orgDir, "tlsca")
 
-	signCA := getCA(caDir, orgSpec, orgSpec.CA.CommonName)
-	tlsCA := getCA(tlscaDir, orgSpec, "tls"+orgSpec.CA.CommonName)
+	signCA := getCAChain(orgDir, "", orgSpec)
+	tlsCA := getCAChain(orgDir, "tls", orgSpec)
 
 	generateNodes(peersDir, orgSpec.Specs, signCA, tlsCA, msp.PEER, orgSpec.EnableNodeOUs)
 
@@ -341,17 +414,15 @@ func extendOrdererOrg(orgSpec OrgSpec) {
 	orgName := orgSpec.Domain
 
 	orgDir := filepath.Join(*inputDir, "ordererOrganizations", orgName)
-	caDir := filepath.Join(orgDir, "ca")
 	usersDir := filepath.Join(orgDir, "users")
-	tlscaDir := filepath.Join(orgDir, "tlsca")
 	orderersDir := filepath.Join(orgDir, "orderers")
 	if _, err := os.Stat(orgDir); os.IsNotExist(err) {
 		generateOrdererOrg(*inputDir, orgSpec)
 		return
 	}
 
-	signCA := getCA(caDir, orgSpec, orgSpec.CA.CommonName)
-	tlsCA := getCA(tlscaDir, orgSpec, "tls"+orgSpec.CA.CommonName)
+	signCA := getCAChain(orgDir, "", orgSpec)
+	tlsCA := getCAChain(orgDir, "tls", orgSpec)
 
 	generateNodes(orderersDir, orgSpec.Specs, signCA, tlsCA, msp.ORDERER, false)
 
@@ -370,6 +441,118 @@ func extendOrdererOrg(orgSpec OrgSpec) {
 	}
 }
 
+func renew() {
+	config, err := getConfig()
+	if err != nil {
+		fmt.Printf("Error reading config: %s", err)
+		os.Exit(-1)
+	}
+
+	for _, orgSpec := range config.PeerOrgs {
+		err = renderOrgSpec(&orgSpec, "peer")
+		if err != nil {
+			fmt.Printf("Error processing peer configuration: %s", err)
+			os.Exit(-1)
+		}
+		renewOrg(filepath.Join(*renInputDir, "peerOrganizations", orgSpec.Domain), orgSpec, "peers")
+	}
+
+	for _, orgSpec := range config.OrdererOrgs {
+		err = renderOrgSpec(&orgSpec, "orderer")
+		if err != nil {
+			fmt.Printf("Error processing orderer configuration: %s", err)
+			os.Exit(-1)
+		}
+		renewOrg(filepath.Join(*renInputDir, "ordererOrganizations", orgSpec.Domain), orgSpec, "orderers")
+	}
+}
+
+// renewOrg renews the certificates of all the nodes found in orgDir/nodesDir
+// and of all the users of the organization with the existing CAs of the
+// organization, which are left untouched
+func renewOrg(orgDir string, orgSpec OrgSpec, nodesDir string) {
+	orgName := orgSpec.Domain
+	if _, err := os.Stat(orgDir); err != nil {
+		fmt.Printf("Error renewing certificates of org %s:\n%v\n", orgName, err)
+		os.Exit(1)
+	}
+	fmt.Println(orgName)
+
+	signCA := getCAChain(orgDir, "", orgSpec)
+	tlsCA := getCAChain(orgDir, "tls", orgSpec)
+	if signCA.Signer == nil || signCA.SignCert == nil || tlsCA.Signer == nil || tlsCA.SignCert == nil {
+		fmt.Printf("Error renewing certificates of org %s: failed loading its CAs\n", orgName)
+		os.Exit(1)
+	}
+
+	nodesDir = filepath.Join(orgDir, nodesDir)
+	usersDir := filepath.Join(orgDir, "users")
+	nodes := renewNodes(nodesDir, signCA, tlsCA)
+	renewNodes(usersDir, signCA, tlsCA)
+
+	// replace the copies of the renewed admin certs
+	adminCertsDirs := []string{filepath.Join(orgDir, "msp", "admincerts")}
+	for _, node := range nodes {
+		adminCertsDirs = append(adminCertsDirs, filepath.Join(nodesDir, node, "msp", "admincerts"))
+	}
+	for _, adminCertsDir := range adminCertsDirs {
+		err := renewAdminCerts(usersDir, adminCertsDir)
+		if err != nil {
+			fmt.Printf("Error copying admin certs for org %s into %s:\n%v\n",
+				orgName, adminCertsDir, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// renewNodes renews the certificates of the nodes or users found in baseDir
+// and returns their names
+func renewNodes(baseDir string, signCA *ca.CA, tlsCA *ca.CA) []string {
+	files, err := ioutil.ReadDir(baseDir)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Printf("Error reading %s:\n%v\n", baseDir, err)
+		os.Exit(1)
+	}
+
+	var nodes []string
+	for _, file := range files {
+		if !file.IsDir() {
+			continue
+		}
+		err := msp.RenewLocalMSP(filepath.Join(baseDir, file.Name()), file.Name(), signCA, tlsCA, *renNewKeys)
+		if err != nil {
+			fmt.Printf("Error renewing local MSP for %s:\n%v\n", file.Name(), err)
+			os.Exit(1)
+		}
+		nodes = append(nodes, file.Name())
+	}
+	return nodes
+}
+
+// renewAdminCerts replaces the certificates of users in adminCertsDir with
+// their current certificates
+func renewAdminCerts(usersDir, adminCertsDir string) error {
+	files, err := ioutil.ReadDir(adminCertsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, file := range files {
+		userName := strings.TrimSuffix(file.Name(), "-cert.pem")
+		userCert := filepath.Join(usersDir, userName, "msp", "signcerts", file.Name())
+		if _, err := os.Stat(userCert); err != nil {
+			continue
+		}
+		err = copyFile(userCert, filepath.Join(adminCertsDir, file.Name()))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func generate() {
 
 	config, err := getConfig()
@@ -488,11 +671,48 @@ func renderOrgSpec(orgSpec *OrgSpec, prefix string) error {
 		orgSpec.Specs[idx] = spec
 	}
 
+	switch strings.ToUpper(orgSpec.KeyAlgorithm) {
+	case "", bccsp.ECDSA:
+		switch strings.ToUpper(orgSpec.Curve) {
+		case "", "P-256", "P256":
+			orgSpec.KeyAlgorithm = bccsp.ECDSA
+		case "P-384", "P384":
+			orgSpec.KeyAlgorithm = bccsp.ECDSAP384
+		default:
+			return fmt.Errorf("unsupported curve %s, supported curves are P-256 and P-384", orgSpec.Curve)
+		}
+	case bccsp.ED25519:
+		if orgSpec.Curve != "" {
+			return fmt.Errorf("a curve cannot be specified for %s keys", bccsp.ED25519)
+		}
+		orgSpec.KeyAlgorithm = bccsp.ED25519
+	default:
+		return fmt.Errorf("unsupported key algorithm %s, supported algorithms are %s and %s", orgSpec.KeyAlgorithm, bccsp.ECDSA, bccsp.ED25519)
+	}
+
+	var err error
+	if orgSpec.Validity.CA != "" {
+		orgSpec.Validity.ca, err = time.ParseDuration(orgSpec.Validity.CA)
+		if err != nil || orgSpec.Validity.ca <= 0 {
+			return fmt.Errorf("invalid CA validity %s", orgSpec.Validity.CA)
+		}
+	}
+	if orgSpec.Validity.Node != "" {
+		orgSpec.Validity.node, err = time.ParseDuration(orgSpec.Validity.Node)
+		if err != nil || orgSpec.Validity.node <= 0 {
+			return fmt.Errorf("invalid node validity %s", orgSpec.Validity.Node)
+		}
+	}
+
+	if orgSpec.IntermediateCAs < 0 {
+		return fmt.Errorf("invalid number of intermediate CAs %d", orgSpec.IntermediateCAs)
+	}
+
 	// Process the CA node-spec in the same manner
 	if len(orgSpec.CA.Hostname) == 0 {
 		orgSpec.CA.Hostname = "ca"
 	}
-	err := renderNodeSpec(orgSpec.Domain, &orgSpec.CA)
+	err = renderNodeSpec(orgSpec.Domain, &orgSpec.CA)
 	if err != nil {
 		return err
 	}
@@ -507,20 +727,18 @@ func generatePeerOrg(baseDir string, orgSpec OrgSpec) {
 	fmt.Println(orgName)
 	// generate CAs
 	orgDir := filepath.Join(baseDir, "peerOrganizations", orgName)
-	caDir := filepath.Join(orgDir, "ca")
-	tlsCADir := filepath.Join(orgDir, "tlsca")
 	mspDir := filepath.Join(orgDir, "msp")
 	peersDir := filepath.Join(orgDir, "peers")
 	usersDir := filepath.Join(orgDir, "users")
 	adminCertsDir := filepath.Join(mspDir, "admincerts")
 	// generate signing CA
-	signCA, err := ca.NewCA(caDir, orgName, orgSpec.CA.CommonName, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode)
+	signCA, err := generateCAChain(orgDir, "", orgSpec)
 	if err != nil {
 		fmt.Printf("Error generating signCA for org %s:\n%v\n", orgName, err)
 		os.Exit(1)
 	}
 	// generate TLS CA
-	tlsCA, err := ca.NewCA(tlsCADir, orgName, "tls"+orgSpec.CA.CommonName, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode)
+	tlsCA, err := generateCAChain(orgDir, "tls", orgSpec)
 	if err != nil {
 		fmt.Printf("Error generating tlsCA for org %s:\n%v\n", orgName, err)
 		os.Exit(1)
@@ -616,20 +834,18 @@ func generateOrdererOrg(baseDir string, orgSpec OrgSpec) {
 
 	// generate CAs
 	orgDir := filepath.Join(baseDir, "ordererOrganizations", orgName)
-	caDir := filepath.Join(orgDir, "ca")
-	tlsCADir := filepath.Join(orgDir, "tlsca")
 	mspDir := filepath.Join(orgDir, "msp")
 	orderersDir := filepath.Join(orgDir, "orderers")
 	usersDir := filepath.Join(orgDir, "users")
 	adminCertsDir := filepath.Join(mspDir, "admincerts")
 	// generate signing CA
-	signCA, err := ca.NewCA(caDir, orgName, orgSpec.CA.CommonName, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode)
+	signCA, err := generateCAChain(orgDir, "", orgSpec)
 	if err != nil {
 		fmt.Printf("Error generating signCA for org %s:\n%v\n", orgName, err)
 		os.Exit(1)
 	}
 	// generate TLS CA
-	tlsCA, err := ca.NewCA(tlsCADir, orgName, "tls"+orgSpec.CA.CommonName, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode)
+	tlsCA, err := generateCAChain(orgDir, "tls", orgSpec)
 	if err != nil {
 		fmt.Printf("Error generating tlsCA for org %s:\n%v\n", orgName, err)
 		os.Exit(1)
@@ -697,10 +913,70 @@ func printVersion() {
 	fmt.Println(metadata.GetVersionInfo())
 }
 
+// generateCAChain generates the root CA of the organization in
+// orgDir/<prefix>ca followed by orgSpec.IntermediateCAs intermediate CAs in
+// orgDir/<prefix>ica1, orgDir/<prefix>ica2, ... and returns the last CA of
+// the chain
+func generateCAChain(orgDir, prefix string, orgSpec OrgSpec) (*ca.CA, error) {
+	opts := ca.Options{
+		KeyAlgorithm: orgSpec.KeyAlgorithm,
+		CAValidity:   orgSpec.Validity.ca,
+		Validity:     orgSpec.Validity.node,
+	}
+	signCA, err := ca.NewCAWithOptions(filepath.Join(orgDir, prefix+"ca"), orgSpec.Domain, prefix+orgSpec.CA.CommonName, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := 1; i <= orgSpec.IntermediateCAs; i++ {
+		opts.Parent = signCA
+		name := fmt.Sprintf("%sica%d", prefix, i)
+		signCA, err = ca.NewCAWithOptions(filepath.Join(orgDir, name), orgSpec.Domain, name+"."+orgSpec.Domain, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return signCA, nil
+}
+
+// getCAChain loads the root CA of the organization in orgDir/<prefix>ca and
+// the intermediate CAs found in orgDir/<prefix>ica1, orgDir/<prefix>ica2, ...
+// and returns the last CA of the chain
+func getCAChain(orgDir, prefix string, spec OrgSpec) *ca.CA {
+	signCA := getCA(filepath.Join(orgDir, prefix+"ca"), spec, prefix+spec.CA.CommonName)
+	for i := 1; ; i++ {
+		caDir := filepath.Join(orgDir, fmt.Sprintf("%sica%d", prefix, i))
+		if _, err := os.Stat(caDir); err != nil {
+			break
+		}
+		parent := signCA
+		signCA = getCA(caDir, spec, "")
+		if signCA.SignCert != nil {
+			signCA.Name = signCA.SignCert.Subject.CommonName
+		}
+		if parent.SignCert != nil {
+			signCA.Chain = append([]*x509.Certificate{parent.SignCert}, parent.Chain...)
+		}
+	}
+	return signCA
+}
+
 func getCA(caDir string, spec OrgSpec, name string) *ca.CA {
 	_, signer, _ := csp.LoadPrivateKey(caDir)
 	cert, _ := ca.LoadCertificateECDSA(caDir)
 
+	// keep issuing keys of the algorithm of the existing CA
+	keyAlg := bccsp.ECDSA
+	if cert != nil {
+		switch pubKey := cert.PublicKey.(type) {
+		case ed25519.PublicKey:
+			keyAlg = bccsp.ED25519
+		case *ecdsa.PublicKey:
+			if pubKey.Curve == elliptic.P384() {
+				keyAlg = bccsp.ECDSAP384
+			}
+		}
+	}
+
 	return &ca.CA{
 		Name:               name,
 		Signer:             signer,
@@ -711,5 +987,7 @@ func getCA(caDir string, spec OrgSpec, name string) *ca.CA {
 		OrganizationalUnit: spec.CA.OrganizationalUnit,
 		StreetAddress:      spec.CA.StreetAddress,
 		PostalCode:         spec.CA.PostalCode,
+		KeyAlgorithm:       keyAlg,
+		Validity:           spec.Validity.node,
 	}
 }
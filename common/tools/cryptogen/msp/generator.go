@@ -9,9 +9,11 @@ import (
 	"crypto/x509"
 	"encoding/hex"
 	"encoding/pem"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 
+	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
 
 	"justledger/bccsp"
@@ -60,14 +62,14 @@ func GenerateLocalMSP(baseDir, name string, sans []string, signCA *ca.CA,
 	// get keystore path
 	keystore := filepath.Join(mspDir, "keystore")
 
-	// generate private key
-	priv, _, err := csp.GeneratePrivateKey(keystore)
+	// generate private key of the same algorithm as the one of the signing CA
+	priv, _, err := csp.GeneratePrivateKeyWithAlgorithm(keystore, keyAlgorithm(signCA))
 	if err != nil {
 		return err
 	}
 
 	// get public key
-	ecPubKey, err := csp.GetECPublicKey(priv)
+	pubKey, err := csp.GetPublicKey(priv)
 	if err != nil {
 		return err
 	}
@@ -77,27 +79,27 @@ func GenerateLocalMSP(baseDir, name string, sans []string, signCA *ca.CA,
 		ous = []string{nodeOUMap[nodeType]}
 	}
 	cert, err := signCA.SignCertificate(filepath.Join(mspDir, "signcerts"),
-		name, ous, nil, ecPubKey, x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{})
+		name, ous, nil, pubKey, x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{})
 	if err != nil {
 		return err
 	}
 
 	// write artifacts to MSP folders
 
-	// the signing CA certificate goes into cacerts
-	err = x509Export(filepath.Join(mspDir, "cacerts", x509Filename(signCA.Name)), signCA.SignCert)
+	// the signing CA certificates go into cacerts and intermediatecerts
+	err = exportCACerts(mspDir, "cacerts", "intermediatecerts", signCA)
 	if err != nil {
 		return err
 	}
-	// the TLS CA certificate goes into tlscacerts
-	err = x509Export(filepath.Join(mspDir, "tlscacerts", x509Filename(tlsCA.Name)), tlsCA.SignCert)
+	// the TLS CA certificates go into tlscacerts and tlsintermediatecerts
+	err = exportCACerts(mspDir, "tlscacerts", "tlsintermediatecerts", tlsCA)
 	if err != nil {
 		return err
 	}
 
 	// generate config.yaml if required
 	if nodeOUs && nodeType == PEER {
-		exportConfig(mspDir, "cacerts/"+x509Filename(signCA.Name), true)
+		exportConfig(mspDir, ouCertificate(signCA), true)
 	}
 
 	// the signing identity goes into admincerts.
@@ -117,23 +119,23 @@ func GenerateLocalMSP(baseDir, name string, sans []string, signCA *ca.CA,
 	*/
 
 	// generate private key
-	tlsPrivKey, _, err := csp.GeneratePrivateKey(tlsDir)
+	tlsPrivKey, _, err := csp.GeneratePrivateKeyWithAlgorithm(tlsDir, keyAlgorithm(tlsCA))
 	if err != nil {
 		return err
 	}
 	// get public key
-	tlsPubKey, err := csp.GetECPublicKey(tlsPrivKey)
+	tlsPubKey, err := csp.GetPublicKey(tlsPrivKey)
 	if err != nil {
 		return err
 	}
 	// generate X509 certificate using TLS CA
-	_, err = tlsCA.SignCertificate(filepath.Join(tlsDir),
+	tlsCert, err := tlsCA.SignCertificate(filepath.Join(tlsDir),
 		name, nil, sans, tlsPubKey, x509.KeyUsageDigitalSignature|x509.KeyUsageKeyEncipherment,
 		[]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth})
 	if err != nil {
 		return err
 	}
-	err = x509Export(filepath.Join(tlsDir, "ca.crt"), tlsCA.SignCert)
+	err = x509Export(filepath.Join(tlsDir, "ca.crt"), tlsCA.RootCert())
 	if err != nil {
 		return err
 	}
@@ -143,8 +145,7 @@ func GenerateLocalMSP(baseDir, name string, sans []string, signCA *ca.CA,
 	if nodeType == CLIENT {
 		tlsFilePrefix = "client"
 	}
-	err = os.Rename(filepath.Join(tlsDir, x509Filename(name)),
-		filepath.Join(tlsDir, tlsFilePrefix+".crt"))
+	err = tlsCertExport(tlsDir, name, tlsFilePrefix, tlsCert, tlsCA)
 	if err != nil {
 		return err
 	}
@@ -162,13 +163,13 @@ func GenerateVerifyingMSP(baseDir string, signCA *ca.CA, tlsCA *ca.CA, nodeOUs b
 	// create folder structure and write artifacts to proper locations
 	err := createFolderStructure(baseDir, false)
 	if err == nil {
-		// the signing CA certificate goes into cacerts
-		err = x509Export(filepath.Join(baseDir, "cacerts", x509Filename(signCA.Name)), signCA.SignCert)
+		// the signing CA certificates go into cacerts and intermediatecerts
+		err = exportCACerts(baseDir, "cacerts", "intermediatecerts", signCA)
 		if err != nil {
 			return err
 		}
-		// the TLS CA certificate goes into tlscacerts
-		err = x509Export(filepath.Join(baseDir, "tlscacerts", x509Filename(tlsCA.Name)), tlsCA.SignCert)
+		// the TLS CA certificates go into tlscacerts and tlsintermediatecerts
+		err = exportCACerts(baseDir, "tlscacerts", "tlsintermediatecerts", tlsCA)
 		if err != nil {
 			return err
 		}
@@ -176,7 +177,7 @@ func GenerateVerifyingMSP(baseDir string, signCA *ca.CA, tlsCA *ca.CA, nodeOUs b
 
 	// generate config.yaml if required
 	if nodeOUs {
-		exportConfig(baseDir, "cacerts/"+x509Filename(signCA.Name), true)
+		exportConfig(baseDir, ouCertificate(signCA), true)
 	}
 
 	// create a throwaway cert to act as an admin cert
@@ -200,6 +201,120 @@ func GenerateVerifyingMSP(baseDir string, signCA *ca.CA, tlsCA *ca.CA, nodeOUs b
 	return nil
 }
 
+// RenewLocalMSP re-issues the signing certificate and the TLS certificate of
+// the local MSP generated by GenerateLocalMSP in baseDir, keeping their
+// subjects, subject alternative names and key usages. The certificates are
+// issued by signCA and tlsCA, which must be the CAs that issued the current
+// ones, for their current public keys or, if newKeys is true, for newly
+// generated keys that replace the current ones.
+// The CA certificates of the MSP are left untouched
+func RenewLocalMSP(baseDir, name string, signCA *ca.CA, tlsCA *ca.CA, newKeys bool) error {
+	mspDir := filepath.Join(baseDir, "msp")
+	tlsDir := filepath.Join(baseDir, "tls")
+
+	/*
+		Renew the MSP identity
+	*/
+	signcertsDir := filepath.Join(mspDir, "signcerts")
+	oldCert, err := loadCertificate(filepath.Join(signcertsDir, x509Filename(name)))
+	if err != nil {
+		return err
+	}
+	if err := oldCert.CheckSignatureFrom(signCA.SignCert); err != nil {
+		return errors.Errorf("the certificate of %s was not issued by CA %s", name, signCA.SignCert.Subject.CommonName)
+	}
+
+	pubKey := oldCert.PublicKey
+	if newKeys {
+		keystore := filepath.Join(mspDir, "keystore")
+		// the MSP loads whichever key it finds in the keystore,
+		// so the old key has to go
+		err = os.RemoveAll(keystore)
+		if err != nil {
+			return err
+		}
+		err = os.MkdirAll(keystore, 0755)
+		if err != nil {
+			return err
+		}
+		priv, _, err := csp.GeneratePrivateKeyWithAlgorithm(keystore, keyAlgorithm(signCA))
+		if err != nil {
+			return err
+		}
+		pubKey, err = csp.GetPublicKey(priv)
+		if err != nil {
+			return err
+		}
+	}
+
+	cert, err := signCA.RenewCertificate(signcertsDir, name, oldCert, pubKey)
+	if err != nil {
+		return err
+	}
+	// the signing identity may be an admin of its own MSP
+	selfAdminCert := filepath.Join(mspDir, "admincerts", x509Filename(name))
+	if _, err := os.Stat(selfAdminCert); err == nil {
+		err = x509Export(selfAdminCert, cert)
+		if err != nil {
+			return err
+		}
+	}
+
+	/*
+		Renew the TLS certificate
+	*/
+	for _, tlsFilePrefix := range []string{"server", "client"} {
+		oldTLSCert, err := loadCertificate(filepath.Join(tlsDir, tlsFilePrefix+".crt"))
+		if os.IsNotExist(errors.Cause(err)) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := oldTLSCert.CheckSignatureFrom(tlsCA.SignCert); err != nil {
+			return errors.Errorf("the TLS certificate of %s was not issued by CA %s", name, tlsCA.SignCert.Subject.CommonName)
+		}
+
+		tlsPubKey := oldTLSCert.PublicKey
+		var tlsPrivKey bccsp.Key
+		if newKeys {
+			tlsPrivKey, _, err = csp.GeneratePrivateKeyWithAlgorithm(tlsDir, keyAlgorithm(tlsCA))
+			if err != nil {
+				return err
+			}
+			tlsPubKey, err = csp.GetPublicKey(tlsPrivKey)
+			if err != nil {
+				return err
+			}
+		}
+
+		tlsCert, err := tlsCA.RenewCertificate(tlsDir, name, oldTLSCert, tlsPubKey)
+		if err != nil {
+			return err
+		}
+		if tlsPrivKey != nil {
+			err = keyExport(tlsDir, filepath.Join(tlsDir, tlsFilePrefix+".key"), tlsPrivKey)
+			if err != nil {
+				return err
+			}
+		}
+		err = tlsCertExport(tlsDir, name, tlsFilePrefix, tlsCert, tlsCA)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// keyAlgorithm returns the algorithm of the keys certified by the given CA
+func keyAlgorithm(ca *ca.CA) string {
+	if ca.KeyAlgorithm == "" {
+		return bccsp.ECDSA
+	}
+	return ca.KeyAlgorithm
+}
+
 func createFolderStructure(rootDir string, local bool) error {
 
 	var folders []string
@@ -224,6 +339,79 @@ func createFolderStructure(rootDir string, local bool) error {
 	return nil
 }
 
+// exportCACerts writes the certificate of the root CA of the given CA into
+// mspDir/rootDir and the certificates of its intermediate CAs, if any, into
+// mspDir/intermediateDir
+func exportCACerts(mspDir, rootDir, intermediateDir string, ca *ca.CA) error {
+	rootName := ca.Name
+	if len(ca.Chain) != 0 {
+		rootName = ca.RootCert().Subject.CommonName
+	}
+	err := x509Export(filepath.Join(mspDir, rootDir, x509Filename(rootName)), ca.RootCert())
+	if err != nil {
+		return err
+	}
+
+	intermediateCerts := ca.IntermediateCerts()
+	if len(intermediateCerts) == 0 {
+		return nil
+	}
+	err = os.MkdirAll(filepath.Join(mspDir, intermediateDir), 0755)
+	if err != nil {
+		return err
+	}
+	for _, cert := range intermediateCerts {
+		err = x509Export(filepath.Join(mspDir, intermediateDir, x509Filename(cert.Subject.CommonName)), cert)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ouCertificate returns the path, relative to the MSP folder, of the
+// certificate of the CA that issues the identities classified by the
+// NodeOUs, i.e. the given CA
+func ouCertificate(ca *ca.CA) string {
+	if len(ca.Chain) != 0 {
+		return "intermediatecerts/" + x509Filename(ca.Name)
+	}
+	return "cacerts/" + x509Filename(ca.Name)
+}
+
+// tlsCertExport moves the TLS certificate saved as tlsDir/name by the CA to
+// tlsDir/prefix.crt, followed by the certificates of the intermediate TLS
+// CAs so that peers only need to trust the TLS root CA
+func tlsCertExport(tlsDir, name, prefix string, cert *x509.Certificate, tlsCA *ca.CA) error {
+	certs := append([]*x509.Certificate{cert}, tlsCA.IntermediateCerts()...)
+	var chain []byte
+	for _, cert := range certs {
+		chain = append(chain, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+	err := ioutil.WriteFile(filepath.Join(tlsDir, prefix+".crt"), chain, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Remove(filepath.Join(tlsDir, x509Filename(name)))
+}
+
+// loadCertificate reads the first certificate of the PEM file at path
+func loadCertificate(path string) (*x509.Certificate, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.Errorf("no PEM data found in %s", path)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed parsing the certificate in %s", path)
+	}
+	return cert, nil
+}
+
 func x509Filename(name string) string {
 	return name + "-cert.pem"
 }
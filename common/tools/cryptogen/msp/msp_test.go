@@ -6,14 +6,20 @@ SPDX-License-Identifier: Apache-2.0
 package msp_test
 
 import (
+	"crypto/ed25519"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v2"
 
+	"justledger/bccsp"
 	"justledger/common/tools/cryptogen/ca"
 	"justledger/common/tools/cryptogen/msp"
 	fabricmsp "justledger/msp"
@@ -127,6 +133,216 @@ func TestGenerateLocalMSP(t *testing.T) {
 
 }
 
+func TestGenerateLocalMSPED25519(t *testing.T) {
+	cleanup(testDir)
+	defer cleanup(testDir)
+
+	caDir := filepath.Join(testDir, "ca")
+	tlsCADir := filepath.Join(testDir, "tlsca")
+	mspDir := filepath.Join(testDir, "msp")
+	tlsDir := filepath.Join(testDir, "tls")
+
+	signCA, err := ca.NewCAWithKeyAlgorithm(caDir, testCAOrg, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode, bccsp.ED25519)
+	assert.NoError(t, err, "Error generating CA")
+	tlsCA, err := ca.NewCAWithKeyAlgorithm(tlsCADir, testCAOrg, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode, bccsp.ED25519)
+	assert.NoError(t, err, "Error generating CA")
+	assert.Equal(t, x509.PureEd25519, signCA.SignCert.SignatureAlgorithm)
+
+	err = msp.GenerateLocalMSP(testDir, testName, nil, signCA, tlsCA, msp.PEER, false)
+	assert.NoError(t, err, "Failed to generate local MSP")
+
+	// the TLS key pair is an Ed25519 one
+	tlsCert, err := tls.LoadX509KeyPair(filepath.Join(tlsDir, "server.crt"), filepath.Join(tlsDir, "server.key"))
+	assert.NoError(t, err, "Failed to load TLS key pair")
+	assert.IsType(t, ed25519.PrivateKey{}, tlsCert.PrivateKey)
+
+	// the local MSP can be set up, and its signing identity signs and verifies messages
+	testMSPConfig, err := fabricmsp.GetLocalMspConfig(mspDir, nil, testName)
+	assert.NoError(t, err, "Error parsing local MSP config")
+	testMSP, err := fabricmsp.New(&fabricmsp.BCCSPNewOpts{NewBaseOpts: fabricmsp.NewBaseOpts{Version: fabricmsp.MSPv1_0}})
+	assert.NoError(t, err, "Error creating new BCCSP MSP")
+	err = testMSP.Setup(testMSPConfig)
+	assert.NoError(t, err, "Error setting up local MSP")
+
+	id, err := testMSP.GetDefaultSigningIdentity()
+	assert.NoError(t, err)
+	assert.NoError(t, id.Validate())
+	msg := []byte("hello")
+	sig, err := id.Sign(msg)
+	assert.NoError(t, err)
+	assert.NoError(t, id.Verify(msg, sig))
+	assert.Error(t, id.Verify([]byte("world"), sig))
+}
+
+func TestGenerateLocalMSPWithIntermediateCAs(t *testing.T) {
+	cleanup(testDir)
+	defer cleanup(testDir)
+
+	mspDir := filepath.Join(testDir, "msp")
+	tlsDir := filepath.Join(testDir, "tls")
+
+	newCAChain := func(prefix string) *ca.CA {
+		rootCA, err := ca.NewCA(filepath.Join(testDir, prefix+"ca"), testCAOrg, prefix+testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+		assert.NoError(t, err, "Error generating CA")
+		intermediateCA, err := ca.NewCAWithOptions(filepath.Join(testDir, prefix+"ica1"), testCAOrg, prefix+"ica1."+testCAOrg, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode,
+			ca.Options{KeyAlgorithm: bccsp.ECDSAP384, Parent: rootCA})
+		assert.NoError(t, err, "Error generating intermediate CA")
+		return intermediateCA
+	}
+	signCA := newCAChain("")
+	tlsCA := newCAChain("tls")
+
+	err := msp.GenerateLocalMSP(testDir, testName, []string{"localhost"}, signCA, tlsCA, msp.PEER, true)
+	assert.NoError(t, err, "Failed to generate local MSP")
+
+	for _, file := range []string{
+		filepath.Join(mspDir, "cacerts", testCAName+"-cert.pem"),
+		filepath.Join(mspDir, "intermediatecerts", "ica1."+testCAOrg+"-cert.pem"),
+		filepath.Join(mspDir, "tlscacerts", "tls"+testCAName+"-cert.pem"),
+		filepath.Join(mspDir, "tlsintermediatecerts", "tlsica1."+testCAOrg+"-cert.pem"),
+	} {
+		assert.Equal(t, true, checkForFile(file), "Expected to find file "+file)
+	}
+
+	// the NodeOUs are bound to the CA that issues the identities
+	configBytes, err := ioutil.ReadFile(filepath.Join(mspDir, "config.yaml"))
+	assert.NoError(t, err)
+	config := &fabricmsp.Configuration{}
+	assert.NoError(t, yaml.Unmarshal(configBytes, config))
+	assert.Equal(t, "intermediatecerts/ica1."+testCAOrg+"-cert.pem", config.NodeOUs.PeerOUIdentifier.Certificate)
+
+	// the TLS certificate is followed by the intermediate CA certificate
+	// and verifies against the TLS root CA
+	tlsCert, err := tls.LoadX509KeyPair(filepath.Join(tlsDir, "server.crt"), filepath.Join(tlsDir, "server.key"))
+	assert.NoError(t, err, "Failed to load TLS key pair")
+	assert.Len(t, tlsCert.Certificate, 2)
+	assert.Equal(t, tlsCA.SignCert.Raw, tlsCert.Certificate[1])
+	caBytes, err := ioutil.ReadFile(filepath.Join(tlsDir, "ca.crt"))
+	assert.NoError(t, err)
+	roots := x509.NewCertPool()
+	assert.True(t, roots.AppendCertsFromPEM(caBytes))
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(tlsCA.SignCert)
+	leaf, err := x509.ParseCertificate(tlsCert.Certificate[0])
+	assert.NoError(t, err)
+	_, err = leaf.Verify(x509.VerifyOptions{DNSName: "localhost", Roots: roots, Intermediates: intermediates})
+	assert.NoError(t, err)
+
+	// with NodeOUs, the admins must be clients of the MSP, like the admin
+	// users copied into the admincerts of the peers by cryptogen
+	adminDir := filepath.Join(testDir, "admin")
+	err = msp.GenerateLocalMSP(adminDir, "admin", nil, signCA, tlsCA, msp.CLIENT, true)
+	assert.NoError(t, err, "Failed to generate local MSP")
+	assert.NoError(t, os.RemoveAll(filepath.Join(mspDir, "admincerts")))
+	assert.NoError(t, os.Rename(filepath.Join(adminDir, "msp", "signcerts"), filepath.Join(mspDir, "admincerts")))
+
+	testMSPConfig, err := fabricmsp.GetLocalMspConfig(mspDir, nil, testName)
+	assert.NoError(t, err, "Error parsing local MSP config")
+	testMSP, err := fabricmsp.New(&fabricmsp.BCCSPNewOpts{NewBaseOpts: fabricmsp.NewBaseOpts{Version: fabricmsp.MSPv1_1}})
+	assert.NoError(t, err, "Error creating new BCCSP MSP")
+	err = testMSP.Setup(testMSPConfig)
+	assert.NoError(t, err, "Error setting up local MSP")
+	id, err := testMSP.GetDefaultSigningIdentity()
+	assert.NoError(t, err)
+	assert.NoError(t, id.Validate())
+}
+
+func TestRenewLocalMSP(t *testing.T) {
+	cleanup(testDir)
+	defer cleanup(testDir)
+
+	mspDir := filepath.Join(testDir, "msp")
+	tlsDir := filepath.Join(testDir, "tls")
+	signCertFile := filepath.Join(mspDir, "signcerts", testName+"-cert.pem")
+
+	signCA, err := ca.NewCA(filepath.Join(testDir, "ca"), testCAOrg, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+	tlsCA, err := ca.NewCA(filepath.Join(testDir, "tlsca"), testCAOrg, "tls"+testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+
+	err = msp.GenerateLocalMSP(testDir, testName, []string{"localhost"}, signCA, tlsCA, msp.CLIENT, true)
+	assert.NoError(t, err, "Failed to generate local MSP")
+
+	loadCerts := func() (*x509.Certificate, tls.Certificate) {
+		signCertBytes, err := ioutil.ReadFile(signCertFile)
+		assert.NoError(t, err)
+		block, _ := pem.Decode(signCertBytes)
+		signCert, err := x509.ParseCertificate(block.Bytes)
+		assert.NoError(t, err)
+		tlsCert, err := tls.LoadX509KeyPair(filepath.Join(tlsDir, "client.crt"), filepath.Join(tlsDir, "client.key"))
+		assert.NoError(t, err, "Failed to load TLS key pair")
+		return signCert, tlsCert
+	}
+	setupMSP := func() {
+		testMSPConfig, err := fabricmsp.GetLocalMspConfig(mspDir, nil, testName)
+		assert.NoError(t, err, "Error parsing local MSP config")
+		testMSP, err := fabricmsp.New(&fabricmsp.BCCSPNewOpts{NewBaseOpts: fabricmsp.NewBaseOpts{Version: fabricmsp.MSPv1_0}})
+		assert.NoError(t, err, "Error creating new BCCSP MSP")
+		assert.NoError(t, testMSP.Setup(testMSPConfig), "Error setting up local MSP")
+		id, err := testMSP.GetDefaultSigningIdentity()
+		assert.NoError(t, err)
+		msg := []byte("hello")
+		sig, err := id.Sign(msg)
+		assert.NoError(t, err)
+		assert.NoError(t, id.Verify(msg, sig))
+	}
+	oldSignCert, oldTLSCert := loadCerts()
+	caCertBytes, err := ioutil.ReadFile(filepath.Join(mspDir, "cacerts", testCAName+"-cert.pem"))
+	assert.NoError(t, err)
+
+	// renewing with the same keys
+	signCA.Validity = 24 * time.Hour
+	tlsCA.Validity = 24 * time.Hour
+	err = msp.RenewLocalMSP(testDir, testName, signCA, tlsCA, false)
+	assert.NoError(t, err, "Failed to renew local MSP")
+	signCert, tlsCert := loadCerts()
+	assert.NotEqual(t, oldSignCert.SerialNumber, signCert.SerialNumber)
+	assert.Equal(t, oldSignCert.Subject.String(), signCert.Subject.String())
+	assert.Equal(t, oldSignCert.PublicKey, signCert.PublicKey)
+	assert.Equal(t, 24*time.Hour, signCert.NotAfter.Sub(signCert.NotBefore))
+	assert.NotEqual(t, oldTLSCert.Certificate[0], tlsCert.Certificate[0])
+	assert.Equal(t, oldTLSCert.PrivateKey, tlsCert.PrivateKey)
+	leaf, err := x509.ParseCertificate(tlsCert.Certificate[0])
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"localhost"}, leaf.DNSNames)
+	assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}, leaf.ExtKeyUsage)
+	// the signing identity is still its own admin and the CAs are untouched
+	adminCertBytes, err := ioutil.ReadFile(filepath.Join(mspDir, "admincerts", testName+"-cert.pem"))
+	assert.NoError(t, err)
+	assert.Equal(t, signCert.Raw, decodePEM(t, adminCertBytes))
+	newCACertBytes, err := ioutil.ReadFile(filepath.Join(mspDir, "cacerts", testCAName+"-cert.pem"))
+	assert.NoError(t, err)
+	assert.Equal(t, caCertBytes, newCACertBytes)
+	setupMSP()
+
+	// renewing with new keys
+	err = msp.RenewLocalMSP(testDir, testName, signCA, tlsCA, true)
+	assert.NoError(t, err, "Failed to renew local MSP")
+	newSignCert, newTLSCert := loadCerts()
+	assert.NotEqual(t, signCert.PublicKey, newSignCert.PublicKey)
+	assert.NotEqual(t, tlsCert.PrivateKey, newTLSCert.PrivateKey)
+	keys, err := ioutil.ReadDir(filepath.Join(mspDir, "keystore"))
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+	setupMSP()
+
+	// the certificates can only be renewed by the CAs that issued them
+	otherCA, err := ca.NewCA(filepath.Join(testDir, "otherca"), testCAOrg, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+	err = msp.RenewLocalMSP(testDir, testName, otherCA, tlsCA, false)
+	assert.EqualError(t, err, "the certificate of peer0 was not issued by CA "+testCAName)
+	err = msp.RenewLocalMSP(testDir, testName, signCA, otherCA, false)
+	assert.EqualError(t, err, "the TLS certificate of peer0 was not issued by CA "+testCAName)
+	err = msp.RenewLocalMSP(testDir, "peer1", signCA, tlsCA, false)
+	assert.Error(t, err)
+}
+
+func decodePEM(t *testing.T, raw []byte) []byte {
+	block, _ := pem.Decode(raw)
+	assert.NotNil(t, block)
+	return block.Bytes
+}
+
 func TestGenerateVerifyingMSP(t *testing.T) {
 
 	caDir := filepath.Join(testDir, "ca")
@@ -7,9 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package idemixca
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-amcl/amcl"
 	"github.com/hyperledger/fabric-amcl/amcl/FP256BN"
 	"justledger/idemix"
 	"justledger/msp"
@@ -42,7 +44,8 @@ func GenerateIssuerKey() ([]byte, []byte, error) {
 // GenerateSignerConfig creates a new signer config.
 // It generates a fresh user secret and issues a credential
 // with four attributes (described above) using the CA's key pair.
-func GenerateSignerConfig(roleMask int, ouString string, enrollmentId string, revocationHandle int, key *idemix.IssuerKey, revKey *ecdsa.PrivateKey) ([]byte, error) {
+// The credential revocation information of the signer is created for the given epoch.
+func GenerateSignerConfig(roleMask int, ouString string, enrollmentId string, revocationHandle int, key *idemix.IssuerKey, revKey *ecdsa.PrivateKey, epoch int) ([]byte, error) {
 	attrs := make([]*FP256BN.BIG, 4)
 
 	if ouString == "" {
@@ -75,15 +78,10 @@ func GenerateSignerConfig(roleMask int, ouString string, enrollmentId string, re
 		return nil, errors.WithMessage(err, "failed to marshal credential")
 	}
 
-	// NOTE currently, idemixca creates CRI's with "ALG_NO_REVOCATION"
-	cri, err := idemix.CreateCRI(revKey, []*FP256BN.BIG{FP256BN.NewBIGint(revocationHandle)}, 0, idemix.ALG_NO_REVOCATION, rng)
+	criBytes, err := generateCRI(attrs[msp.AttributeIndexRevocationHandle], epoch, revKey, rng)
 	if err != nil {
 		return nil, err
 	}
-	criBytes, err := proto.Marshal(cri)
-	if err != nil {
-		return nil, errors.WithMessage(err, "failed to marshal CRI")
-	}
 
 	signer := &m.IdemixMSPSignerConfig{
 		Cred: credBytes,
@@ -96,3 +94,55 @@ func GenerateSignerConfig(roleMask int, ouString string, enrollmentId string, re
 
 	return proto.Marshal(signer)
 }
+
+// RenewSignerConfig replaces the credential revocation information of a signer config
+// with one for the given epoch. It is used to keep the signers whose credential
+// was not revoked working after the revocation authority moved to a new epoch,
+// and fails if the revocation handle of the signer is among the revoked ones.
+func RenewSignerConfig(signerBytes []byte, revokedHandles []int, revKey *ecdsa.PrivateKey, epoch int) ([]byte, error) {
+	signer := &m.IdemixMSPSignerConfig{}
+	err := proto.Unmarshal(signerBytes, signer)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal signer config")
+	}
+	cred := &idemix.Credential{}
+	err = proto.Unmarshal(signer.Cred, cred)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal credential")
+	}
+	if len(cred.Attrs) <= msp.AttributeIndexRevocationHandle {
+		return nil, errors.Errorf("the credential does not contain a revocation handle")
+	}
+
+	rh := FP256BN.FromBytes(cred.Attrs[msp.AttributeIndexRevocationHandle])
+	for _, revokedHandle := range revokedHandles {
+		if bytes.Equal(cred.Attrs[msp.AttributeIndexRevocationHandle], idemix.BigToBytes(FP256BN.NewBIGint(revokedHandle))) {
+			return nil, errors.Errorf("the credential with revocation handle %d is revoked", revokedHandle)
+		}
+	}
+
+	rng, err := idemix.GetRand()
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error getting PRNG")
+	}
+	signer.CredentialRevocationInformation, err = generateCRI(rh, epoch, revKey, rng)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(signer)
+}
+
+// generateCRI creates the serialized credential revocation information which proves,
+// for the given epoch, that the revocation handle is not revoked
+func generateCRI(revocationHandle *FP256BN.BIG, epoch int, revKey *ecdsa.PrivateKey, rng *amcl.RAND) ([]byte, error) {
+	cri, err := idemix.CreateCRI(revKey, []*FP256BN.BIG{revocationHandle}, epoch, idemix.ALG_PLAIN_SIGNATURE, rng)
+	if err != nil {
+		return nil, err
+	}
+	criBytes, err := proto.Marshal(cri)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to marshal CRI")
+	}
+	return criBytes, nil
+}
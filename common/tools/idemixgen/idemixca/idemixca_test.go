@@ -10,6 +10,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 	"testing"
 
 	"crypto/x509"
@@ -47,13 +48,13 @@ func TestIdemixCa(t *testing.T) {
 
 	key := &idemix.IssuerKey{Isk: isk, Ipk: ipk}
 
-	conf, err := GenerateSignerConfig(m.GetRoleMaskFromIdemixRole(m.MEMBER), "OU1", "enrollmentid1", 1, key, revocationkey)
+	conf, err := GenerateSignerConfig(m.GetRoleMaskFromIdemixRole(m.MEMBER), "OU1", "enrollmentid1", 1, key, revocationkey, 0)
 	assert.NoError(t, err)
 	cleanupSigner()
 	assert.NoError(t, writeSignerToFile(conf))
 	assert.NoError(t, setupMSP())
 
-	conf, err = GenerateSignerConfig(m.GetRoleMaskFromIdemixRole(m.ADMIN), "OU1", "enrollmentid2", 1234, key, revocationkey)
+	conf, err = GenerateSignerConfig(m.GetRoleMaskFromIdemixRole(m.ADMIN), "OU1", "enrollmentid2", 1234, key, revocationkey, 0)
 	assert.NoError(t, err)
 	cleanupSigner()
 	assert.NoError(t, writeSignerToFile(conf))
@@ -63,13 +64,57 @@ func TestIdemixCa(t *testing.T) {
 	cleanupVerifier()
 	assert.Error(t, setupMSP())
 
-	_, err = GenerateSignerConfig(m.GetRoleMaskFromIdemixRole(m.ADMIN), "", "enrollmentid", 1, key, revocationkey)
+	_, err = GenerateSignerConfig(m.GetRoleMaskFromIdemixRole(m.ADMIN), "", "enrollmentid", 1, key, revocationkey, 0)
 	assert.EqualError(t, err, "the OU attribute value is empty")
 
-	_, err = GenerateSignerConfig(m.GetRoleMaskFromIdemixRole(m.ADMIN), "OU1", "", 1, key, revocationkey)
+	_, err = GenerateSignerConfig(m.GetRoleMaskFromIdemixRole(m.ADMIN), "OU1", "", 1, key, revocationkey, 0)
 	assert.EqualError(t, err, "the enrollment id value is empty")
 }
 
+func TestIdemixCaRevocation(t *testing.T) {
+	cleanup()
+
+	isk, ipkBytes, err := GenerateIssuerKey()
+	assert.NoError(t, err)
+	ipk := &idemix.IssuerPublicKey{}
+	assert.NoError(t, proto.Unmarshal(ipkBytes, ipk))
+	key := &idemix.IssuerKey{Isk: isk, Ipk: ipk}
+
+	revocationkey, err := idemix.GenerateLongTermRevocationKey()
+	assert.NoError(t, err)
+	encodedRevocationPK, err := x509.MarshalPKIXPublicKey(revocationkey.Public())
+	assert.NoError(t, err)
+	pemEncodedRevocationPK := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: encodedRevocationPK})
+	assert.NoError(t, writeVerifierToFile(ipkBytes, pemEncodedRevocationPK))
+	assert.NoError(t, writeEpochToFile(1))
+
+	conf, err := GenerateSignerConfig(m.GetRoleMaskFromIdemixRole(m.MEMBER), "OU1", "enrollmentid1", 7, key, revocationkey, 1)
+	assert.NoError(t, err)
+	cleanupSigner()
+	assert.NoError(t, writeSignerToFile(conf))
+	assert.NoError(t, setupMSP())
+
+	// once the epoch moves on, the signer config has to be renewed
+	assert.NoError(t, writeEpochToFile(2))
+	err = setupMSP()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "credential revocation information is for epoch 1, but the current epoch is 2")
+
+	conf, err = RenewSignerConfig(conf, []int{3, 5}, revocationkey, 2)
+	assert.NoError(t, err)
+	cleanupSigner()
+	assert.NoError(t, writeSignerToFile(conf))
+	assert.NoError(t, setupMSP())
+
+	// a revoked signer cannot be renewed
+	_, err = RenewSignerConfig(conf, []int{3, 7}, revocationkey, 3)
+	assert.EqualError(t, err, "the credential with revocation handle 7 is revoked")
+
+	_, err = RenewSignerConfig([]byte("barf"), nil, revocationkey, 3)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to unmarshal signer config")
+}
+
 func cleanup() error {
 	// clean up any previous files
 	err := os.RemoveAll(testDir)
@@ -100,6 +145,10 @@ func writeVerifierToFile(ipkBytes []byte, revpkBytes []byte) error {
 	return ioutil.WriteFile(filepath.Join(testDir, m.IdemixConfigDirMsp, m.IdemixConfigFileRevocationPublicKey), revpkBytes, 0644)
 }
 
+func writeEpochToFile(epoch int) error {
+	return ioutil.WriteFile(filepath.Join(testDir, m.IdemixConfigDirMsp, m.IdemixConfigFileRevocationEpoch), []byte(strconv.Itoa(epoch)), 0644)
+}
+
 func writeSignerToFile(signerBytes []byte) error {
 	err := os.Mkdir(filepath.Join(testDir, m.IdemixConfigDirUser), os.ModePerm)
 	if err != nil {
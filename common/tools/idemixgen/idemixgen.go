@@ -17,6 +17,8 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"crypto/ecdsa"
 
@@ -35,6 +37,7 @@ const (
 	IdemixDirIssuer             = "ca"
 	IdemixConfigIssuerSecretKey = "IssuerSecretKey"
 	IdemixConfigRevocationKey   = "RevocationKey"
+	IdemixConfigRevokedHandles  = "RevokedHandles"
 )
 
 // command line flags
@@ -50,6 +53,11 @@ var (
 	genCredEnrollmentId     = genSignerConfig.Flag("enrollmentId", "The enrollment id of the default signer").Short('e').String()
 	genCredRevocationHandle = genSignerConfig.Flag("revocationHandle", "The handle used to revoke this signer").Short('r').Int()
 
+	revoke                 = app.Command("revoke", "Revoke a credential and move the revocation authority to the next epoch")
+	revokeRevocationHandle = revoke.Flag("revocationHandle", "The revocation handle of the credential to revoke").Short('r').Required().Int()
+
+	renewCRI = app.Command("cri", "Renew the credential revocation information of the default signer for the current epoch")
+
 	version = app.Command("version", "Show version information")
 )
 
@@ -95,7 +103,12 @@ func main() {
 		} else {
 			roleMask = msp.GetRoleMaskFromIdemixRole(msp.MEMBER)
 		}
-		config, err := idemixca.GenerateSignerConfig(roleMask, *genCredOU, *genCredEnrollmentId, *genCredRevocationHandle, readIssuerKey(), readRevocationKey())
+		for _, revokedHandle := range readRevokedHandles() {
+			if revokedHandle == *genCredRevocationHandle {
+				handleError(errors.Errorf("revocation handle %d is revoked", revokedHandle))
+			}
+		}
+		config, err := idemixca.GenerateSignerConfig(roleMask, *genCredOU, *genCredEnrollmentId, *genCredRevocationHandle, readIssuerKey(), readRevocationKey(), readRevocationEpoch())
 		handleError(err)
 
 		path := filepath.Join(*outputDir, msp.IdemixConfigDirUser)
@@ -105,6 +118,36 @@ func main() {
 		handleError(os.Mkdir(filepath.Join(*outputDir, msp.IdemixConfigDirUser), 0770))
 		writeFile(filepath.Join(*outputDir, msp.IdemixConfigDirUser, msp.IdemixConfigFileSigner), config)
 
+	case revoke.FullCommand():
+		revokedHandles := readRevokedHandles()
+		for _, revokedHandle := range revokedHandles {
+			if revokedHandle == *revokeRevocationHandle {
+				handleError(errors.Errorf("revocation handle %d is already revoked", revokedHandle))
+			}
+		}
+		revokedHandles = append(revokedHandles, *revokeRevocationHandle)
+
+		// Record the revoked handle and move to the next epoch, which invalidates
+		// the credential revocation information of every signer
+		var contents []byte
+		for _, revokedHandle := range revokedHandles {
+			contents = append(contents, []byte(strconv.Itoa(revokedHandle)+"\n")...)
+		}
+		epoch := readRevocationEpoch() + 1
+		writeFile(filepath.Join(*outputDir, IdemixDirIssuer, IdemixConfigRevokedHandles), contents)
+		writeFile(filepath.Join(*outputDir, msp.IdemixConfigDirMsp, msp.IdemixConfigFileRevocationEpoch), []byte(strconv.Itoa(epoch)+"\n"))
+		fmt.Printf("Revoked handle %d, the revocation epoch is now %d\n", *revokeRevocationHandle, epoch)
+
+	case renewCRI.FullCommand():
+		path := filepath.Join(*outputDir, msp.IdemixConfigDirUser, msp.IdemixConfigFileSigner)
+		signerBytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			handleError(errors.Wrapf(err, "failed to open signer config file: %s", path))
+		}
+		config, err := idemixca.RenewSignerConfig(signerBytes, readRevokedHandles(), readRevocationKey(), readRevocationEpoch())
+		handleError(err)
+		writeFile(path, config)
+
 	case version.FullCommand():
 		printVersion()
 	}
@@ -155,6 +198,44 @@ func readRevocationKey() *ecdsa.PrivateKey {
 	return key
 }
 
+// readRevocationEpoch reads the current revocation epoch, which is 0 until a credential is revoked
+func readRevocationEpoch() int {
+	path := filepath.Join(*outputDir, msp.IdemixConfigDirMsp, msp.IdemixConfigFileRevocationEpoch)
+	epochBytes, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0
+	}
+	if err != nil {
+		handleError(errors.Wrapf(err, "failed to open revocation epoch file: %s", path))
+	}
+	epoch, err := strconv.Atoi(strings.TrimSpace(string(epochBytes)))
+	if err != nil {
+		handleError(errors.Wrapf(err, "failed to parse revocation epoch file: %s", path))
+	}
+	return epoch
+}
+
+// readRevokedHandles reads the revocation handles of the revoked credentials
+func readRevokedHandles() []int {
+	path := filepath.Join(*outputDir, IdemixDirIssuer, IdemixConfigRevokedHandles)
+	handlesBytes, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		handleError(errors.Wrapf(err, "failed to open revoked handles file: %s", path))
+	}
+	var handles []int
+	for _, line := range strings.Fields(string(handlesBytes)) {
+		handle, err := strconv.Atoi(line)
+		if err != nil {
+			handleError(errors.Wrapf(err, "failed to parse revoked handles file: %s", path))
+		}
+		handles = append(handles, handle)
+	}
+	return handles
+}
+
 // checkDirectoryNotExists checks whether a directory with the given path already exists and exits if this is the case
 func checkDirectoryNotExists(path string, errorMessage string) {
 	_, err := os.Stat(path)
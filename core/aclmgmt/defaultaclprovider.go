@@ -75,6 +75,10 @@ func (d *defaultACLProvider) initialize() {
 	d.cResourcePolicyMap[resources.Qscc_GetBlockByHash] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.Qscc_GetTransactionByID] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.Qscc_GetBlockByTxID] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_GetHistoryForKey] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_GetHistoryForKeysByPrefix] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_GetBlocksByTimeRange] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_GetTransactionsByTimeRange] = CHANNELREADERS
 
 	//--------------- CSCC resources -----------
 	//p resources (implemented by the chaincode currently)
@@ -94,6 +98,7 @@ func (d *defaultACLProvider) initialize() {
 	//Event resources
 	d.cResourcePolicyMap[resources.Event_Block] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.Event_FilteredBlock] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Event_BlockAndPrivateData] = CHANNELREADERS
 }
 
 //this should cover an exhaustive list of everything called from the peer
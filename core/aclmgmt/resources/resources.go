@@ -6,10 +6,10 @@ SPDX-License-Identifier: Apache-2.0
 
 package resources
 
-//fabric resources used for ACL checks. Note that some of the checks
-//such as Lscc_INSTALL are "peer wide" (current access checks in peer are
-//based on local MSP). These are not currently covered by resource or default
-//ACLProviders
+// fabric resources used for ACL checks. Note that some of the checks
+// such as Lscc_INSTALL are "peer wide" (current access checks in peer are
+// based on local MSP). These are not currently covered by resource or default
+// ACLProviders
 const (
 	//Lscc resources
 	Lscc_Install                   = "lscc/Install"
@@ -23,11 +23,15 @@ const (
 	Lscc_GetCollectionsConfig      = "lscc/GetCollectionsConfig"
 
 	//Qscc resources
-	Qscc_GetChainInfo       = "qscc/GetChainInfo"
-	Qscc_GetBlockByNumber   = "qscc/GetBlockByNumber"
-	Qscc_GetBlockByHash     = "qscc/GetBlockByHash"
-	Qscc_GetTransactionByID = "qscc/GetTransactionByID"
-	Qscc_GetBlockByTxID     = "qscc/GetBlockByTxID"
+	Qscc_GetChainInfo               = "qscc/GetChainInfo"
+	Qscc_GetBlockByNumber           = "qscc/GetBlockByNumber"
+	Qscc_GetBlockByHash             = "qscc/GetBlockByHash"
+	Qscc_GetTransactionByID         = "qscc/GetTransactionByID"
+	Qscc_GetBlockByTxID             = "qscc/GetBlockByTxID"
+	Qscc_GetHistoryForKey           = "qscc/GetHistoryForKey"
+	Qscc_GetHistoryForKeysByPrefix  = "qscc/GetHistoryForKeysByPrefix"
+	Qscc_GetBlocksByTimeRange       = "qscc/GetBlocksByTimeRange"
+	Qscc_GetTransactionsByTimeRange = "qscc/GetTransactionsByTimeRange"
 
 	//Cscc resources
 	Cscc_JoinChain                = "cscc/JoinChain"
@@ -41,6 +45,7 @@ const (
 	Peer_ChaincodeToChaincode = "peer/ChaincodeToChaincode"
 
 	//Events
-	Event_Block         = "event/Block"
-	Event_FilteredBlock = "event/FilteredBlock"
+	Event_Block               = "event/Block"
+	Event_FilteredBlock       = "event/FilteredBlock"
+	Event_BlockAndPrivateData = "event/BlockAndPrivateData"
 )
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/golang/protobuf/ptypes/empty"
+	"justledger/common/audit"
 	"justledger/common/flogging"
 	"justledger/protos/common"
 	pb "justledger/protos/peer"
@@ -33,11 +34,17 @@ type AccessControlEvaluator interface {
 }
 
 // NewAdminServer creates and returns a Admin service instance.
-func NewAdminServer(ace AccessControlEvaluator) *ServerAdmin {
+// Operations that change the state of the peer are recorded to the given
+// audit recorder, which may be nil if no audit log is configured.
+func NewAdminServer(ace AccessControlEvaluator, recorder audit.Recorder) *ServerAdmin {
+	if recorder == nil {
+		recorder = audit.NoopRecorder{}
+	}
 	s := &ServerAdmin{
 		v: &validator{
 			ace: ace,
 		},
+		recorder:        recorder,
 		levelsAtStartup: flogging.GetModuleLevels(),
 	}
 	return s
@@ -45,7 +52,8 @@ func NewAdminServer(ace AccessControlEvaluator) *ServerAdmin {
 
 // ServerAdmin implementation of the Admin service for the Peer
 type ServerAdmin struct {
-	v requestValidator
+	v        requestValidator
+	recorder audit.Recorder
 
 	levelsAtStartup map[string]zapcore.Level
 }
@@ -85,21 +93,27 @@ func (s *ServerAdmin) GetModuleLogLevel(ctx context.Context, env *common.Envelop
 func (s *ServerAdmin) SetModuleLogLevel(ctx context.Context, env *common.Envelope) (*pb.LogLevelResponse, error) {
 	op, err := s.v.validate(ctx, env)
 	if err != nil {
+		s.recorder.Record(audit.CreatorOf(env), "SetModuleLogLevel", "", err)
 		return nil, err
 	}
 	request := op.GetLogReq()
 	if request == nil {
-		return nil, errors.New("request is nil")
+		err := errors.New("request is nil")
+		s.recorder.Record(audit.CreatorOf(env), "SetModuleLogLevel", "", err)
+		return nil, err
 	}
 	err = flogging.SetModuleLevels(request.LogModule, request.LogLevel)
+	s.recorder.Record(audit.CreatorOf(env), "SetModuleLogLevel", request.LogModule+"="+request.LogLevel, err)
 	logResponse := &pb.LogLevelResponse{LogModule: request.LogModule, LogLevel: strings.ToUpper(request.LogLevel)}
 	return logResponse, err
 }
 
 func (s *ServerAdmin) RevertLogLevels(ctx context.Context, env *common.Envelope) (*empty.Empty, error) {
 	if _, err := s.v.validate(ctx, env); err != nil {
+		s.recorder.Record(audit.CreatorOf(env), "RevertLogLevels", "", err)
 		return nil, err
 	}
 	flogging.RestoreLevels(s.levelsAtStartup)
+	s.recorder.Record(audit.CreatorOf(env), "RevertLogLevels", "", nil)
 	return &empty.Empty{}, nil
 }
@@ -35,7 +35,7 @@ func (v *mockValidator) validate(ctx context.Context, env *common.Envelope) (*pb
 }
 
 func TestGetStatus(t *testing.T) {
-	adminServer := NewAdminServer(nil)
+	adminServer := NewAdminServer(nil, nil)
 	adminServer.v = &mockValidator{}
 	mv := adminServer.v.(*mockValidator)
 	mv.On("validate").Return(nil, nil).Once()
@@ -45,7 +45,7 @@ func TestGetStatus(t *testing.T) {
 }
 
 func TestStartServer(t *testing.T) {
-	adminServer := NewAdminServer(nil)
+	adminServer := NewAdminServer(nil, nil)
 	adminServer.v = &mockValidator{}
 	mv := adminServer.v.(*mockValidator)
 	mv.On("validate").Return(nil, nil).Once()
@@ -55,7 +55,7 @@ func TestStartServer(t *testing.T) {
 }
 
 func TestForbidden(t *testing.T) {
-	adminServer := NewAdminServer(nil)
+	adminServer := NewAdminServer(nil, nil)
 	adminServer.v = &mockValidator{}
 	mv := adminServer.v.(*mockValidator)
 	mv.On("validate").Return(nil, accessDenied).Times(5)
@@ -81,7 +81,7 @@ func TestForbidden(t *testing.T) {
 }
 
 func TestLoggingCalls(t *testing.T) {
-	adminServer := NewAdminServer(nil)
+	adminServer := NewAdminServer(nil, nil)
 	adminServer.v = &mockValidator{}
 	mv := adminServer.v.(*mockValidator)
 	flogging.MustGetLogger("test")
@@ -130,3 +130,52 @@ func TestLoggingCalls(t *testing.T) {
 	assert.Equal(t, flogging.DefaultLevel(), logResponse.LogLevel, "logger level should have been the default")
 	assert.Nil(t, err, "Error should have been nil")
 }
+
+type mockRecorder struct {
+	mock.Mock
+}
+
+func (r *mockRecorder) Record(creator []byte, action, target string, err error) {
+	r.Called(creator, action, target, err)
+}
+
+func (r *mockRecorder) RecordSubmitted(creator []byte, action, target string) {
+	r.Called(creator, action, target)
+}
+
+func TestAuditedCalls(t *testing.T) {
+	recorder := &mockRecorder{}
+	adminServer := NewAdminServer(nil, recorder)
+	adminServer.v = &mockValidator{}
+	mv := adminServer.v.(*mockValidator)
+	flogging.MustGetLogger("test")
+
+	ctx := context.Background()
+
+	mv.On("validate").Return(nil, accessDenied).Once()
+	recorder.On("Record", []byte(nil), "SetModuleLogLevel", "", accessDenied).Once()
+	_, err := adminServer.SetModuleLogLevel(ctx, nil)
+	assert.Equal(t, accessDenied, err)
+
+	op := &pb.AdminOperation{
+		Content: &pb.AdminOperation_LogReq{
+			LogReq: &pb.LogLevelRequest{LogModule: "test", LogLevel: "debug"},
+		},
+	}
+	mv.On("validate").Return(op, nil).Once()
+	recorder.On("Record", []byte(nil), "SetModuleLogLevel", "test=debug", nil).Once()
+	_, err = adminServer.SetModuleLogLevel(ctx, nil)
+	assert.NoError(t, err)
+
+	mv.On("validate").Return(nil, accessDenied).Once()
+	recorder.On("Record", []byte(nil), "RevertLogLevels", "", accessDenied).Once()
+	_, err = adminServer.RevertLogLevels(ctx, nil)
+	assert.Equal(t, accessDenied, err)
+
+	mv.On("validate").Return(nil, nil).Once()
+	recorder.On("Record", []byte(nil), "RevertLogLevels", "", nil).Once()
+	_, err = adminServer.RevertLogLevels(ctx, nil)
+	assert.NoError(t, err)
+
+	recorder.AssertExpectations(t)
+}
@@ -201,6 +201,8 @@ func (h *Handler) handleMessageReadyState(msg *pb.ChaincodeMessage) error {
 		go h.HandleTransaction(msg, h.HandlePutState)
 	case pb.ChaincodeMessage_DEL_STATE:
 		go h.HandleTransaction(msg, h.HandleDelState)
+	case pb.ChaincodeMessage_PURGE_PRIVATE_DATA:
+		go h.HandleTransaction(msg, h.HandlePurgePrivateData)
 	case pb.ChaincodeMessage_INVOKE_CHAINCODE:
 		go h.HandleTransaction(msg, h.HandleInvokeChaincode)
 
@@ -813,7 +815,21 @@ func (h *Handler) HandleGetHistoryForKey(msg *pb.ChaincodeMessage, txContext *Tr
 		return nil, errors.Wrap(err, "unmarshal failed")
 	}
 
-	historyIter, err := txContext.HistoryQueryExecutor.GetHistoryForKey(chaincodeName, getHistoryForKey.Key)
+	var historyIter commonledger.ResultsIterator
+	if !getHistoryForKey.KeyPrefix && getHistoryForKey.Options == nil {
+		historyIter, err = txContext.HistoryQueryExecutor.GetHistoryForKey(chaincodeName, getHistoryForKey.Key)
+	} else {
+		var options *ledger.HistoryQueryOptions
+		options, err = ledger.NewHistoryQueryOptions(getHistoryForKey.Options)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid history query options")
+		}
+		if getHistoryForKey.KeyPrefix {
+			historyIter, err = txContext.HistoryQueryExecutor.GetHistoryForKeysByPrefix(chaincodeName, getHistoryForKey.Key, options)
+		} else {
+			historyIter, err = txContext.HistoryQueryExecutor.GetHistoryForKeyWithOptions(chaincodeName, getHistoryForKey.Key, options)
+		}
+	}
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -996,6 +1012,24 @@ func (h *Handler) HandleDelState(msg *pb.ChaincodeMessage, txContext *Transactio
 	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
 }
 
+func (h *Handler) HandlePurgePrivateData(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
+	delState := &pb.DelState{}
+	err := proto.Unmarshal(msg.Payload, delState)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal failed")
+	}
+	if !isCollectionSet(delState.Collection) {
+		return nil, errors.New("only private data can be purged, collection must be set")
+	}
+
+	err = txContext.TXSimulator.PurgePrivateData(h.ChaincodeName(), delState.Collection, delState.Key)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
+}
+
 // Handles requests that modify ledger state
 func (h *Handler) HandleInvokeChaincode(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
 	chaincodeLogger.Debugf("[%s] C-call-C", shorttxid(msg.Txid))
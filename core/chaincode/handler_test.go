@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"justledger/common/mocks/config"
 	"justledger/common/util"
 	"justledger/core/aclmgmt/resources"
@@ -19,6 +20,7 @@ import (
 	"justledger/core/chaincode/mock"
 	"justledger/core/common/ccprovider"
 	"justledger/core/common/sysccprovider"
+	"justledger/core/ledger"
 	pb "justledger/protos/peer"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -723,6 +725,80 @@ var _ = Describe("Handler", func() {
 		})
 	})
 
+	Describe("HandlePurgePrivateData", func() {
+		var incomingMessage *pb.ChaincodeMessage
+		var request *pb.DelState
+
+		BeforeEach(func() {
+			request = &pb.DelState{
+				Collection: "collection-name",
+				Key:        "purge-key",
+			}
+			payload, err := proto.Marshal(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			incomingMessage = &pb.ChaincodeMessage{
+				Type:      pb.ChaincodeMessage_PURGE_PRIVATE_DATA,
+				Payload:   payload,
+				Txid:      "tx-id",
+				ChannelId: "channel-id",
+			}
+		})
+
+		It("calls PurgePrivateData on the transaction simulator and returns a response message", func() {
+			resp, err := handler.HandlePurgePrivateData(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(Equal(&pb.ChaincodeMessage{
+				Type:      pb.ChaincodeMessage_RESPONSE,
+				Txid:      "tx-id",
+				ChannelId: "channel-id",
+			}))
+
+			Expect(fakeTxSimulator.PurgePrivateDataCallCount()).To(Equal(1))
+			ccname, collection, key := fakeTxSimulator.PurgePrivateDataArgsForCall(0)
+			Expect(ccname).To(Equal("cc-instance-name"))
+			Expect(collection).To(Equal("collection-name"))
+			Expect(key).To(Equal("purge-key"))
+		})
+
+		Context("when unmarshalling the request fails", func() {
+			BeforeEach(func() {
+				incomingMessage.Payload = []byte("this-is-a-bogus-payload")
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandlePurgePrivateData(incomingMessage, txContext)
+				Expect(err).To(MatchError("unmarshal failed: proto: can't skip unknown wire type 4"))
+			})
+		})
+
+		Context("when collection is not set", func() {
+			BeforeEach(func() {
+				request.Collection = ""
+				payload, err := proto.Marshal(request)
+				Expect(err).NotTo(HaveOccurred())
+				incomingMessage.Payload = payload
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandlePurgePrivateData(incomingMessage, txContext)
+				Expect(err).To(MatchError("only private data can be purged, collection must be set"))
+				Expect(fakeTxSimulator.PurgePrivateDataCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when PurgePrivateData returns an error", func() {
+			BeforeEach(func() {
+				fakeTxSimulator.PurgePrivateDataReturns(errors.New("papaya"))
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandlePurgePrivateData(incomingMessage, txContext)
+				Expect(err).To(MatchError("papaya"))
+			})
+		})
+	})
+
 	Describe("HandleGetState", func() {
 		var (
 			incomingMessage  *pb.ChaincodeMessage
@@ -1596,6 +1672,75 @@ var _ = Describe("Handler", func() {
 			Expect(key).To(Equal("history-key"))
 		})
 
+		Context("when the request has options", func() {
+			BeforeEach(func() {
+				request.Options = &pb.HistoryQueryOptions{
+					StartBlock:  3,
+					EndBlock:    7,
+					StartTime:   &timestamp.Timestamp{Seconds: 1538352000},
+					NewestFirst: true,
+				}
+				payload, err := proto.Marshal(request)
+				Expect(err).NotTo(HaveOccurred())
+				incomingMessage.Payload = payload
+
+				fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsReturns(fakeIterator, nil)
+			})
+
+			It("calls GetHistoryForKeyWithOptions on the history query executor", func() {
+				_, err := handler.HandleGetHistoryForKey(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeHistoryQueryExecutor.GetHistoryForKeyCallCount()).To(Equal(0))
+				Expect(fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsCallCount()).To(Equal(1))
+				ccname, key, options := fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsArgsForCall(0)
+				Expect(ccname).To(Equal("cc-instance-name"))
+				Expect(key).To(Equal("history-key"))
+				Expect(options).To(Equal(&ledger.HistoryQueryOptions{
+					StartBlock:  3,
+					EndBlock:    7,
+					StartTime:   time.Unix(1538352000, 0).UTC(),
+					NewestFirst: true,
+				}))
+			})
+
+			Context("when the options are invalid", func() {
+				BeforeEach(func() {
+					request.Options.EndTime = &timestamp.Timestamp{Seconds: 1, Nanos: -1}
+					payload, err := proto.Marshal(request)
+					Expect(err).NotTo(HaveOccurred())
+					incomingMessage.Payload = payload
+				})
+
+				It("returns an error", func() {
+					_, err := handler.HandleGetHistoryForKey(incomingMessage, txContext)
+					Expect(err).To(MatchError(ContainSubstring("invalid history query options")))
+				})
+			})
+		})
+
+		Context("when the request is for a key prefix", func() {
+			BeforeEach(func() {
+				request.KeyPrefix = true
+				payload, err := proto.Marshal(request)
+				Expect(err).NotTo(HaveOccurred())
+				incomingMessage.Payload = payload
+
+				fakeHistoryQueryExecutor.GetHistoryForKeysByPrefixReturns(fakeIterator, nil)
+			})
+
+			It("calls GetHistoryForKeysByPrefix on the history query executor", func() {
+				_, err := handler.HandleGetHistoryForKey(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeHistoryQueryExecutor.GetHistoryForKeysByPrefixCallCount()).To(Equal(1))
+				ccname, keyPrefix, options := fakeHistoryQueryExecutor.GetHistoryForKeysByPrefixArgsForCall(0)
+				Expect(ccname).To(Equal("cc-instance-name"))
+				Expect(keyPrefix).To(Equal("history-key"))
+				Expect(options).To(Equal(&ledger.HistoryQueryOptions{}))
+			})
+		})
+
 		It("initializes a query context", func() {
 			_, err := handler.HandleGetHistoryForKey(incomingMessage, txContext)
 			Expect(err).NotTo(HaveOccurred())
@@ -278,6 +278,35 @@ type ChaincodeStub struct {
 		result1 shim.HistoryQueryIteratorInterface
 		result2 error
 	}
+	GetHistoryForKeyWithOptionsStub        func(key string, options *shim.HistoryQueryOptions) (shim.HistoryQueryIteratorInterface, error)
+	getHistoryForKeyWithOptionsMutex       sync.RWMutex
+	getHistoryForKeyWithOptionsArgsForCall []struct {
+		key     string
+		options *shim.HistoryQueryOptions
+	}
+	getHistoryForKeyWithOptionsReturns struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 error
+	}
+	getHistoryForKeyWithOptionsReturnsOnCall map[int]struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 error
+	}
+	GetHistoryForPartialCompositeKeyStub        func(objectType string, attributes []string, options *shim.HistoryQueryOptions) (shim.HistoryQueryIteratorInterface, error)
+	getHistoryForPartialCompositeKeyMutex       sync.RWMutex
+	getHistoryForPartialCompositeKeyArgsForCall []struct {
+		objectType string
+		attributes []string
+		options    *shim.HistoryQueryOptions
+	}
+	getHistoryForPartialCompositeKeyReturns struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 error
+	}
+	getHistoryForPartialCompositeKeyReturnsOnCall map[int]struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 error
+	}
 	GetPrivateDataStub        func(collection, key string) ([]byte, error)
 	getPrivateDataMutex       sync.RWMutex
 	getPrivateDataArgsForCall []struct {
@@ -317,6 +346,18 @@ type ChaincodeStub struct {
 	delPrivateDataReturnsOnCall map[int]struct {
 		result1 error
 	}
+	PurgePrivateDataStub        func(collection string, key string) error
+	purgePrivateDataMutex       sync.RWMutex
+	purgePrivateDataArgsForCall []struct {
+		collection string
+		key        string
+	}
+	purgePrivateDataReturns struct {
+		result1 error
+	}
+	purgePrivateDataReturnsOnCall map[int]struct {
+		result1 error
+	}
 	SetPrivateDataValidationParameterStub        func(collection, key string, ep []byte) error
 	setPrivateDataValidationParameterMutex       sync.RWMutex
 	setPrivateDataValidationParameterArgsForCall []struct {
@@ -1524,6 +1565,111 @@ func (fake *ChaincodeStub) GetHistoryForKeyReturnsOnCall(i int, result1 shim.His
 	}{result1, result2}
 }
 
+func (fake *ChaincodeStub) GetHistoryForKeyWithOptions(key string, options *shim.HistoryQueryOptions) (shim.HistoryQueryIteratorInterface, error) {
+	fake.getHistoryForKeyWithOptionsMutex.Lock()
+	ret, specificReturn := fake.getHistoryForKeyWithOptionsReturnsOnCall[len(fake.getHistoryForKeyWithOptionsArgsForCall)]
+	fake.getHistoryForKeyWithOptionsArgsForCall = append(fake.getHistoryForKeyWithOptionsArgsForCall, struct {
+		key     string
+		options *shim.HistoryQueryOptions
+	}{key, options})
+	fake.recordInvocation("GetHistoryForKeyWithOptions", []interface{}{key, options})
+	fake.getHistoryForKeyWithOptionsMutex.Unlock()
+	if fake.GetHistoryForKeyWithOptionsStub != nil {
+		return fake.GetHistoryForKeyWithOptionsStub(key, options)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getHistoryForKeyWithOptionsReturns.result1, fake.getHistoryForKeyWithOptionsReturns.result2
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyWithOptionsCallCount() int {
+	fake.getHistoryForKeyWithOptionsMutex.RLock()
+	defer fake.getHistoryForKeyWithOptionsMutex.RUnlock()
+	return len(fake.getHistoryForKeyWithOptionsArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyWithOptionsArgsForCall(i int) (string, *shim.HistoryQueryOptions) {
+	fake.getHistoryForKeyWithOptionsMutex.RLock()
+	defer fake.getHistoryForKeyWithOptionsMutex.RUnlock()
+	return fake.getHistoryForKeyWithOptionsArgsForCall[i].key, fake.getHistoryForKeyWithOptionsArgsForCall[i].options
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyWithOptionsReturns(result1 shim.HistoryQueryIteratorInterface, result2 error) {
+	fake.GetHistoryForKeyWithOptionsStub = nil
+	fake.getHistoryForKeyWithOptionsReturns = struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyWithOptionsReturnsOnCall(i int, result1 shim.HistoryQueryIteratorInterface, result2 error) {
+	fake.GetHistoryForKeyWithOptionsStub = nil
+	if fake.getHistoryForKeyWithOptionsReturnsOnCall == nil {
+		fake.getHistoryForKeyWithOptionsReturnsOnCall = make(map[int]struct {
+			result1 shim.HistoryQueryIteratorInterface
+			result2 error
+		})
+	}
+	fake.getHistoryForKeyWithOptionsReturnsOnCall[i] = struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetHistoryForPartialCompositeKey(objectType string, attributes []string, options *shim.HistoryQueryOptions) (shim.HistoryQueryIteratorInterface, error) {
+	fake.getHistoryForPartialCompositeKeyMutex.Lock()
+	ret, specificReturn := fake.getHistoryForPartialCompositeKeyReturnsOnCall[len(fake.getHistoryForPartialCompositeKeyArgsForCall)]
+	fake.getHistoryForPartialCompositeKeyArgsForCall = append(fake.getHistoryForPartialCompositeKeyArgsForCall, struct {
+		objectType string
+		attributes []string
+		options    *shim.HistoryQueryOptions
+	}{objectType, attributes, options})
+	fake.recordInvocation("GetHistoryForPartialCompositeKey", []interface{}{objectType, attributes, options})
+	fake.getHistoryForPartialCompositeKeyMutex.Unlock()
+	if fake.GetHistoryForPartialCompositeKeyStub != nil {
+		return fake.GetHistoryForPartialCompositeKeyStub(objectType, attributes, options)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getHistoryForPartialCompositeKeyReturns.result1, fake.getHistoryForPartialCompositeKeyReturns.result2
+}
+
+func (fake *ChaincodeStub) GetHistoryForPartialCompositeKeyCallCount() int {
+	fake.getHistoryForPartialCompositeKeyMutex.RLock()
+	defer fake.getHistoryForPartialCompositeKeyMutex.RUnlock()
+	return len(fake.getHistoryForPartialCompositeKeyArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetHistoryForPartialCompositeKeyArgsForCall(i int) (string, []string, *shim.HistoryQueryOptions) {
+	fake.getHistoryForPartialCompositeKeyMutex.RLock()
+	defer fake.getHistoryForPartialCompositeKeyMutex.RUnlock()
+	return fake.getHistoryForPartialCompositeKeyArgsForCall[i].objectType, fake.getHistoryForPartialCompositeKeyArgsForCall[i].attributes, fake.getHistoryForPartialCompositeKeyArgsForCall[i].options
+}
+
+func (fake *ChaincodeStub) GetHistoryForPartialCompositeKeyReturns(result1 shim.HistoryQueryIteratorInterface, result2 error) {
+	fake.GetHistoryForPartialCompositeKeyStub = nil
+	fake.getHistoryForPartialCompositeKeyReturns = struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetHistoryForPartialCompositeKeyReturnsOnCall(i int, result1 shim.HistoryQueryIteratorInterface, result2 error) {
+	fake.GetHistoryForPartialCompositeKeyStub = nil
+	if fake.getHistoryForPartialCompositeKeyReturnsOnCall == nil {
+		fake.getHistoryForPartialCompositeKeyReturnsOnCall = make(map[int]struct {
+			result1 shim.HistoryQueryIteratorInterface
+			result2 error
+		})
+	}
+	fake.getHistoryForPartialCompositeKeyReturnsOnCall[i] = struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ChaincodeStub) GetPrivateData(collection string, key string) ([]byte, error) {
 	fake.getPrivateDataMutex.Lock()
 	ret, specificReturn := fake.getPrivateDataReturnsOnCall[len(fake.getPrivateDataArgsForCall)]
@@ -1680,6 +1826,55 @@ func (fake *ChaincodeStub) DelPrivateDataReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *ChaincodeStub) PurgePrivateData(collection string, key string) error {
+	fake.purgePrivateDataMutex.Lock()
+	ret, specificReturn := fake.purgePrivateDataReturnsOnCall[len(fake.purgePrivateDataArgsForCall)]
+	fake.purgePrivateDataArgsForCall = append(fake.purgePrivateDataArgsForCall, struct {
+		collection string
+		key        string
+	}{collection, key})
+	fake.recordInvocation("PurgePrivateData", []interface{}{collection, key})
+	fake.purgePrivateDataMutex.Unlock()
+	if fake.PurgePrivateDataStub != nil {
+		return fake.PurgePrivateDataStub(collection, key)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.purgePrivateDataReturns.result1
+}
+
+func (fake *ChaincodeStub) PurgePrivateDataCallCount() int {
+	fake.purgePrivateDataMutex.RLock()
+	defer fake.purgePrivateDataMutex.RUnlock()
+	return len(fake.purgePrivateDataArgsForCall)
+}
+
+func (fake *ChaincodeStub) PurgePrivateDataArgsForCall(i int) (string, string) {
+	fake.purgePrivateDataMutex.RLock()
+	defer fake.purgePrivateDataMutex.RUnlock()
+	return fake.purgePrivateDataArgsForCall[i].collection, fake.purgePrivateDataArgsForCall[i].key
+}
+
+func (fake *ChaincodeStub) PurgePrivateDataReturns(result1 error) {
+	fake.PurgePrivateDataStub = nil
+	fake.purgePrivateDataReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ChaincodeStub) PurgePrivateDataReturnsOnCall(i int, result1 error) {
+	fake.PurgePrivateDataStub = nil
+	if fake.purgePrivateDataReturnsOnCall == nil {
+		fake.purgePrivateDataReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.purgePrivateDataReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *ChaincodeStub) SetPrivateDataValidationParameter(collection string, key string, ep []byte) error {
 	var epCopy []byte
 	if ep != nil {
@@ -2304,12 +2499,18 @@ func (fake *ChaincodeStub) Invocations() map[string][][]interface{} {
 	defer fake.getQueryResultWithPaginationMutex.RUnlock()
 	fake.getHistoryForKeyMutex.RLock()
 	defer fake.getHistoryForKeyMutex.RUnlock()
+	fake.getHistoryForKeyWithOptionsMutex.RLock()
+	defer fake.getHistoryForKeyWithOptionsMutex.RUnlock()
+	fake.getHistoryForPartialCompositeKeyMutex.RLock()
+	defer fake.getHistoryForPartialCompositeKeyMutex.RUnlock()
 	fake.getPrivateDataMutex.RLock()
 	defer fake.getPrivateDataMutex.RUnlock()
 	fake.putPrivateDataMutex.RLock()
 	defer fake.putPrivateDataMutex.RUnlock()
 	fake.delPrivateDataMutex.RLock()
 	defer fake.delPrivateDataMutex.RUnlock()
+	fake.purgePrivateDataMutex.RLock()
+	defer fake.purgePrivateDataMutex.RUnlock()
 	fake.setPrivateDataValidationParameterMutex.RLock()
 	defer fake.setPrivateDataValidationParameterMutex.RUnlock()
 	fake.getPrivateDataValidationParameterMutex.RLock()
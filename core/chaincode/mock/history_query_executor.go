@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	commonledger "justledger/common/ledger"
+	"justledger/core/ledger"
 )
 
 type HistoryQueryExecutor struct {
@@ -22,6 +23,36 @@ type HistoryQueryExecutor struct {
 		result1 commonledger.ResultsIterator
 		result2 error
 	}
+	GetHistoryForKeyWithOptionsStub        func(namespace string, key string, options *ledger.HistoryQueryOptions) (commonledger.ResultsIterator, error)
+	getHistoryForKeyWithOptionsMutex       sync.RWMutex
+	getHistoryForKeyWithOptionsArgsForCall []struct {
+		namespace string
+		key       string
+		options   *ledger.HistoryQueryOptions
+	}
+	getHistoryForKeyWithOptionsReturns struct {
+		result1 commonledger.ResultsIterator
+		result2 error
+	}
+	getHistoryForKeyWithOptionsReturnsOnCall map[int]struct {
+		result1 commonledger.ResultsIterator
+		result2 error
+	}
+	GetHistoryForKeysByPrefixStub        func(namespace string, keyPrefix string, options *ledger.HistoryQueryOptions) (commonledger.QueryResultsIterator, error)
+	getHistoryForKeysByPrefixMutex       sync.RWMutex
+	getHistoryForKeysByPrefixArgsForCall []struct {
+		namespace string
+		keyPrefix string
+		options   *ledger.HistoryQueryOptions
+	}
+	getHistoryForKeysByPrefixReturns struct {
+		result1 commonledger.QueryResultsIterator
+		result2 error
+	}
+	getHistoryForKeysByPrefixReturnsOnCall map[int]struct {
+		result1 commonledger.QueryResultsIterator
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -78,11 +109,121 @@ func (fake *HistoryQueryExecutor) GetHistoryForKeyReturnsOnCall(i int, result1 c
 	}{result1, result2}
 }
 
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptions(namespace string, key string, options *ledger.HistoryQueryOptions) (commonledger.ResultsIterator, error) {
+	fake.getHistoryForKeyWithOptionsMutex.Lock()
+	ret, specificReturn := fake.getHistoryForKeyWithOptionsReturnsOnCall[len(fake.getHistoryForKeyWithOptionsArgsForCall)]
+	fake.getHistoryForKeyWithOptionsArgsForCall = append(fake.getHistoryForKeyWithOptionsArgsForCall, struct {
+		namespace string
+		key       string
+		options   *ledger.HistoryQueryOptions
+	}{namespace, key, options})
+	fake.recordInvocation("GetHistoryForKeyWithOptions", []interface{}{namespace, key, options})
+	fake.getHistoryForKeyWithOptionsMutex.Unlock()
+	if fake.GetHistoryForKeyWithOptionsStub != nil {
+		return fake.GetHistoryForKeyWithOptionsStub(namespace, key, options)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getHistoryForKeyWithOptionsReturns.result1, fake.getHistoryForKeyWithOptionsReturns.result2
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptionsCallCount() int {
+	fake.getHistoryForKeyWithOptionsMutex.RLock()
+	defer fake.getHistoryForKeyWithOptionsMutex.RUnlock()
+	return len(fake.getHistoryForKeyWithOptionsArgsForCall)
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptionsArgsForCall(i int) (string, string, *ledger.HistoryQueryOptions) {
+	fake.getHistoryForKeyWithOptionsMutex.RLock()
+	defer fake.getHistoryForKeyWithOptionsMutex.RUnlock()
+	return fake.getHistoryForKeyWithOptionsArgsForCall[i].namespace, fake.getHistoryForKeyWithOptionsArgsForCall[i].key, fake.getHistoryForKeyWithOptionsArgsForCall[i].options
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptionsReturns(result1 commonledger.ResultsIterator, result2 error) {
+	fake.GetHistoryForKeyWithOptionsStub = nil
+	fake.getHistoryForKeyWithOptionsReturns = struct {
+		result1 commonledger.ResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptionsReturnsOnCall(i int, result1 commonledger.ResultsIterator, result2 error) {
+	fake.GetHistoryForKeyWithOptionsStub = nil
+	if fake.getHistoryForKeyWithOptionsReturnsOnCall == nil {
+		fake.getHistoryForKeyWithOptionsReturnsOnCall = make(map[int]struct {
+			result1 commonledger.ResultsIterator
+			result2 error
+		})
+	}
+	fake.getHistoryForKeyWithOptionsReturnsOnCall[i] = struct {
+		result1 commonledger.ResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeysByPrefix(namespace string, keyPrefix string, options *ledger.HistoryQueryOptions) (commonledger.QueryResultsIterator, error) {
+	fake.getHistoryForKeysByPrefixMutex.Lock()
+	ret, specificReturn := fake.getHistoryForKeysByPrefixReturnsOnCall[len(fake.getHistoryForKeysByPrefixArgsForCall)]
+	fake.getHistoryForKeysByPrefixArgsForCall = append(fake.getHistoryForKeysByPrefixArgsForCall, struct {
+		namespace string
+		keyPrefix string
+		options   *ledger.HistoryQueryOptions
+	}{namespace, keyPrefix, options})
+	fake.recordInvocation("GetHistoryForKeysByPrefix", []interface{}{namespace, keyPrefix, options})
+	fake.getHistoryForKeysByPrefixMutex.Unlock()
+	if fake.GetHistoryForKeysByPrefixStub != nil {
+		return fake.GetHistoryForKeysByPrefixStub(namespace, keyPrefix, options)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getHistoryForKeysByPrefixReturns.result1, fake.getHistoryForKeysByPrefixReturns.result2
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeysByPrefixCallCount() int {
+	fake.getHistoryForKeysByPrefixMutex.RLock()
+	defer fake.getHistoryForKeysByPrefixMutex.RUnlock()
+	return len(fake.getHistoryForKeysByPrefixArgsForCall)
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeysByPrefixArgsForCall(i int) (string, string, *ledger.HistoryQueryOptions) {
+	fake.getHistoryForKeysByPrefixMutex.RLock()
+	defer fake.getHistoryForKeysByPrefixMutex.RUnlock()
+	return fake.getHistoryForKeysByPrefixArgsForCall[i].namespace, fake.getHistoryForKeysByPrefixArgsForCall[i].keyPrefix, fake.getHistoryForKeysByPrefixArgsForCall[i].options
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeysByPrefixReturns(result1 commonledger.QueryResultsIterator, result2 error) {
+	fake.GetHistoryForKeysByPrefixStub = nil
+	fake.getHistoryForKeysByPrefixReturns = struct {
+		result1 commonledger.QueryResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeysByPrefixReturnsOnCall(i int, result1 commonledger.QueryResultsIterator, result2 error) {
+	fake.GetHistoryForKeysByPrefixStub = nil
+	if fake.getHistoryForKeysByPrefixReturnsOnCall == nil {
+		fake.getHistoryForKeysByPrefixReturnsOnCall = make(map[int]struct {
+			result1 commonledger.QueryResultsIterator
+			result2 error
+		})
+	}
+	fake.getHistoryForKeysByPrefixReturnsOnCall[i] = struct {
+		result1 commonledger.QueryResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *HistoryQueryExecutor) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.getHistoryForKeyMutex.RLock()
 	defer fake.getHistoryForKeyMutex.RUnlock()
+	fake.getHistoryForKeyWithOptionsMutex.RLock()
+	defer fake.getHistoryForKeyWithOptionsMutex.RUnlock()
+	fake.getHistoryForKeysByPrefixMutex.RLock()
+	defer fake.getHistoryForKeysByPrefixMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -3,6 +3,7 @@ package mock
 
 import (
 	"sync"
+	"time"
 
 	commonledger "justledger/common/ledger"
 	"justledger/core/ledger"
@@ -103,6 +104,42 @@ type PeerLedger struct {
 		result1 peer.TxValidationCode
 		result2 error
 	}
+	GetBlocksByTimeRangeStub        func(startTime time.Time, endTime time.Time, pageSize int, bookmark string) ([]*common.Block, string, error)
+	getBlocksByTimeRangeMutex       sync.RWMutex
+	getBlocksByTimeRangeArgsForCall []struct {
+		startTime time.Time
+		endTime   time.Time
+		pageSize  int
+		bookmark  string
+	}
+	getBlocksByTimeRangeReturns struct {
+		result1 []*common.Block
+		result2 string
+		result3 error
+	}
+	getBlocksByTimeRangeReturnsOnCall map[int]struct {
+		result1 []*common.Block
+		result2 string
+		result3 error
+	}
+	GetTransactionsByTimeRangeStub        func(startTime time.Time, endTime time.Time, pageSize int, bookmark string) ([]*peer.ProcessedTransaction, string, error)
+	getTransactionsByTimeRangeMutex       sync.RWMutex
+	getTransactionsByTimeRangeArgsForCall []struct {
+		startTime time.Time
+		endTime   time.Time
+		pageSize  int
+		bookmark  string
+	}
+	getTransactionsByTimeRangeReturns struct {
+		result1 []*peer.ProcessedTransaction
+		result2 string
+		result3 error
+	}
+	getTransactionsByTimeRangeReturnsOnCall map[int]struct {
+		result1 []*peer.ProcessedTransaction
+		result2 string
+		result3 error
+	}
 	NewTxSimulatorStub        func(txid string) (ledger.TxSimulator, error)
 	newTxSimulatorMutex       sync.RWMutex
 	newTxSimulatorArgsForCall []struct {
@@ -619,6 +656,120 @@ func (fake *PeerLedger) GetTxValidationCodeByTxIDReturnsOnCall(i int, result1 pe
 	}{result1, result2}
 }
 
+func (fake *PeerLedger) GetBlocksByTimeRange(startTime time.Time, endTime time.Time, pageSize int, bookmark string) ([]*common.Block, string, error) {
+	fake.getBlocksByTimeRangeMutex.Lock()
+	ret, specificReturn := fake.getBlocksByTimeRangeReturnsOnCall[len(fake.getBlocksByTimeRangeArgsForCall)]
+	fake.getBlocksByTimeRangeArgsForCall = append(fake.getBlocksByTimeRangeArgsForCall, struct {
+		startTime time.Time
+		endTime   time.Time
+		pageSize  int
+		bookmark  string
+	}{startTime, endTime, pageSize, bookmark})
+	fake.recordInvocation("GetBlocksByTimeRange", []interface{}{startTime, endTime, pageSize, bookmark})
+	fake.getBlocksByTimeRangeMutex.Unlock()
+	if fake.GetBlocksByTimeRangeStub != nil {
+		return fake.GetBlocksByTimeRangeStub(startTime, endTime, pageSize, bookmark)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.getBlocksByTimeRangeReturns.result1, fake.getBlocksByTimeRangeReturns.result2, fake.getBlocksByTimeRangeReturns.result3
+}
+
+func (fake *PeerLedger) GetBlocksByTimeRangeCallCount() int {
+	fake.getBlocksByTimeRangeMutex.RLock()
+	defer fake.getBlocksByTimeRangeMutex.RUnlock()
+	return len(fake.getBlocksByTimeRangeArgsForCall)
+}
+
+func (fake *PeerLedger) GetBlocksByTimeRangeArgsForCall(i int) (time.Time, time.Time, int, string) {
+	fake.getBlocksByTimeRangeMutex.RLock()
+	defer fake.getBlocksByTimeRangeMutex.RUnlock()
+	return fake.getBlocksByTimeRangeArgsForCall[i].startTime, fake.getBlocksByTimeRangeArgsForCall[i].endTime, fake.getBlocksByTimeRangeArgsForCall[i].pageSize, fake.getBlocksByTimeRangeArgsForCall[i].bookmark
+}
+
+func (fake *PeerLedger) GetBlocksByTimeRangeReturns(result1 []*common.Block, result2 string, result3 error) {
+	fake.GetBlocksByTimeRangeStub = nil
+	fake.getBlocksByTimeRangeReturns = struct {
+		result1 []*common.Block
+		result2 string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *PeerLedger) GetBlocksByTimeRangeReturnsOnCall(i int, result1 []*common.Block, result2 string, result3 error) {
+	fake.GetBlocksByTimeRangeStub = nil
+	if fake.getBlocksByTimeRangeReturnsOnCall == nil {
+		fake.getBlocksByTimeRangeReturnsOnCall = make(map[int]struct {
+			result1 []*common.Block
+			result2 string
+			result3 error
+		})
+	}
+	fake.getBlocksByTimeRangeReturnsOnCall[i] = struct {
+		result1 []*common.Block
+		result2 string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *PeerLedger) GetTransactionsByTimeRange(startTime time.Time, endTime time.Time, pageSize int, bookmark string) ([]*peer.ProcessedTransaction, string, error) {
+	fake.getTransactionsByTimeRangeMutex.Lock()
+	ret, specificReturn := fake.getTransactionsByTimeRangeReturnsOnCall[len(fake.getTransactionsByTimeRangeArgsForCall)]
+	fake.getTransactionsByTimeRangeArgsForCall = append(fake.getTransactionsByTimeRangeArgsForCall, struct {
+		startTime time.Time
+		endTime   time.Time
+		pageSize  int
+		bookmark  string
+	}{startTime, endTime, pageSize, bookmark})
+	fake.recordInvocation("GetTransactionsByTimeRange", []interface{}{startTime, endTime, pageSize, bookmark})
+	fake.getTransactionsByTimeRangeMutex.Unlock()
+	if fake.GetTransactionsByTimeRangeStub != nil {
+		return fake.GetTransactionsByTimeRangeStub(startTime, endTime, pageSize, bookmark)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.getTransactionsByTimeRangeReturns.result1, fake.getTransactionsByTimeRangeReturns.result2, fake.getTransactionsByTimeRangeReturns.result3
+}
+
+func (fake *PeerLedger) GetTransactionsByTimeRangeCallCount() int {
+	fake.getTransactionsByTimeRangeMutex.RLock()
+	defer fake.getTransactionsByTimeRangeMutex.RUnlock()
+	return len(fake.getTransactionsByTimeRangeArgsForCall)
+}
+
+func (fake *PeerLedger) GetTransactionsByTimeRangeArgsForCall(i int) (time.Time, time.Time, int, string) {
+	fake.getTransactionsByTimeRangeMutex.RLock()
+	defer fake.getTransactionsByTimeRangeMutex.RUnlock()
+	return fake.getTransactionsByTimeRangeArgsForCall[i].startTime, fake.getTransactionsByTimeRangeArgsForCall[i].endTime, fake.getTransactionsByTimeRangeArgsForCall[i].pageSize, fake.getTransactionsByTimeRangeArgsForCall[i].bookmark
+}
+
+func (fake *PeerLedger) GetTransactionsByTimeRangeReturns(result1 []*peer.ProcessedTransaction, result2 string, result3 error) {
+	fake.GetTransactionsByTimeRangeStub = nil
+	fake.getTransactionsByTimeRangeReturns = struct {
+		result1 []*peer.ProcessedTransaction
+		result2 string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *PeerLedger) GetTransactionsByTimeRangeReturnsOnCall(i int, result1 []*peer.ProcessedTransaction, result2 string, result3 error) {
+	fake.GetTransactionsByTimeRangeStub = nil
+	if fake.getTransactionsByTimeRangeReturnsOnCall == nil {
+		fake.getTransactionsByTimeRangeReturnsOnCall = make(map[int]struct {
+			result1 []*peer.ProcessedTransaction
+			result2 string
+			result3 error
+		})
+	}
+	fake.getTransactionsByTimeRangeReturnsOnCall[i] = struct {
+		result1 []*peer.ProcessedTransaction
+		result2 string
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *PeerLedger) NewTxSimulator(txid string) (ledger.TxSimulator, error) {
 	fake.newTxSimulatorMutex.Lock()
 	ret, specificReturn := fake.newTxSimulatorReturnsOnCall[len(fake.newTxSimulatorArgsForCall)]
@@ -637,6 +788,10 @@ func (fake *PeerLedger) NewTxSimulator(txid string) (ledger.TxSimulator, error)
 }
 
 func (fake *PeerLedger) NewTxSimulatorCallCount() int {
+	fake.getBlocksByTimeRangeMutex.RLock()
+	defer fake.getBlocksByTimeRangeMutex.RUnlock()
+	fake.getTransactionsByTimeRangeMutex.RLock()
+	defer fake.getTransactionsByTimeRangeMutex.RUnlock()
 	fake.newTxSimulatorMutex.RLock()
 	defer fake.newTxSimulatorMutex.RUnlock()
 	return len(fake.newTxSimulatorArgsForCall)
@@ -21,6 +21,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"justledger/bccsp/factory"
 	commonledger "justledger/common/ledger"
@@ -540,6 +541,17 @@ func (stub *ChaincodeStub) DelPrivateData(collection string, key string) error {
 	return stub.handler.handleDelState(collection, key, stub.ChannelId, stub.TxID)
 }
 
+// PurgePrivateData documentation can be found in interfaces.go
+func (stub *ChaincodeStub) PurgePrivateData(collection string, key string) error {
+	if collection == "" {
+		return fmt.Errorf("collection must not be an empty string")
+	}
+	if key == "" {
+		return fmt.Errorf("key must not be an empty string")
+	}
+	return stub.handler.handlePurgePrivateData(collection, key, stub.ChannelId, stub.TxID)
+}
+
 // GetPrivateDataByRange documentation can be found in interfaces.go
 func (stub *ChaincodeStub) GetPrivateDataByRange(collection, startKey, endKey string) (StateQueryIteratorInterface, error) {
 	if collection == "" {
@@ -700,7 +712,67 @@ func (stub *ChaincodeStub) GetStateByRange(startKey, endKey string) (StateQueryI
 
 // GetHistoryForKey documentation can be found in interfaces.go
 func (stub *ChaincodeStub) GetHistoryForKey(key string) (HistoryQueryIteratorInterface, error) {
-	response, err := stub.handler.handleGetHistoryForKey(key, stub.ChannelId, stub.TxID)
+	return stub.GetHistoryForKeyWithOptions(key, nil)
+}
+
+// HistoryQueryOptions restricts and orders the results of a history query.
+type HistoryQueryOptions struct {
+	// StartBlock is the first block whose modifications are returned
+	StartBlock uint64
+	// EndBlock is the last block whose modifications are returned, 0 means
+	// no upper bound
+	EndBlock uint64
+	// StartTime, if set, excludes the modifications of the transactions
+	// created before it
+	StartTime time.Time
+	// EndTime, if set, excludes the modifications of the transactions
+	// created at or after it
+	EndTime time.Time
+	// NewestFirst returns the most recent modifications first
+	NewestFirst bool
+}
+
+func (options *HistoryQueryOptions) toProto() (*pb.HistoryQueryOptions, error) {
+	if options == nil {
+		return nil, nil
+	}
+	protoOptions := &pb.HistoryQueryOptions{StartBlock: options.StartBlock,
+		EndBlock: options.EndBlock, NewestFirst: options.NewestFirst}
+	var err error
+	if !options.StartTime.IsZero() {
+		if protoOptions.StartTime, err = ptypes.TimestampProto(options.StartTime); err != nil {
+			return nil, err
+		}
+	}
+	if !options.EndTime.IsZero() {
+		if protoOptions.EndTime, err = ptypes.TimestampProto(options.EndTime); err != nil {
+			return nil, err
+		}
+	}
+	return protoOptions, nil
+}
+
+// GetHistoryForKeyWithOptions documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetHistoryForKeyWithOptions(key string, options *HistoryQueryOptions) (HistoryQueryIteratorInterface, error) {
+	return stub.handleGetHistory(key, false, options)
+}
+
+// GetHistoryForPartialCompositeKey documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetHistoryForPartialCompositeKey(objectType string, attributes []string,
+	options *HistoryQueryOptions) (HistoryQueryIteratorInterface, error) {
+	partialCompositeKey, err := stub.CreateCompositeKey(objectType, attributes)
+	if err != nil {
+		return nil, err
+	}
+	return stub.handleGetHistory(partialCompositeKey, true, options)
+}
+
+func (stub *ChaincodeStub) handleGetHistory(key string, keyPrefix bool, options *HistoryQueryOptions) (HistoryQueryIteratorInterface, error) {
+	protoOptions, err := options.toProto()
+	if err != nil {
+		return nil, err
+	}
+	response, err := stub.handler.handleGetHistoryForKey(key, keyPrefix, protoOptions, stub.ChannelId, stub.TxID)
 	if err != nil {
 		return nil, err
 	}
@@ -25,6 +25,7 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 
@@ -41,6 +42,20 @@ var (
 	AttrOIDString = "1.2.3.4.5.6.7.8.1"
 )
 
+const (
+	// IdemixOUAttr is the name of the attribute holding the OU of an idemix identity
+	IdemixOUAttr = "ou"
+	// IdemixRoleAttr is the name of the attribute holding the role of an idemix identity
+	IdemixRoleAttr = "role"
+	// EnrollmentIDAttr is the name of the attribute holding the enrollment ID.
+	// Fabric CA adds it to X509 certificates under the same name, and idemix
+	// identities carry it when they disclose it.
+	EnrollmentIDAttr = "hf.EnrollmentID"
+	// RevocationHandleAttr is the name of the attribute holding the
+	// hex-encoded revocation handle of an idemix identity that discloses it
+	RevocationHandleAttr = "hf.RevocationHandle"
+)
+
 // Attribute is a name/value pair
 type Attribute interface {
 	// GetName returns the name of the attribute
@@ -159,7 +174,7 @@ func (mgr *Mgr) GetAttributesFromIdemix(creator []byte) (*Attributes, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to unmarshal transaction invoker's ou")
 	}
-	attrs.Attrs["ou"] = ou.OrganizationalUnitIdentifier
+	attrs.Attrs[IdemixOUAttr] = ou.OrganizationalUnitIdentifier
 
 	role := &msp.MSPRole{}
 	err = proto.Unmarshal(idemixID.Role, role)
@@ -177,7 +192,16 @@ func (mgr *Mgr) GetAttributesFromIdemix(creator []byte) (*Attributes, error) {
 	case 3:
 		roleStr = "peer"
 	}
-	attrs.Attrs["role"] = roleStr
+	attrs.Attrs[IdemixRoleAttr] = roleStr
+
+	// The enrollment ID and the revocation handle are only present
+	// if the identity chose to disclose them
+	if idemixID.EnrollmentId != "" {
+		attrs.Attrs[EnrollmentIDAttr] = idemixID.EnrollmentId
+	}
+	if len(idemixID.RevocationHandle) != 0 {
+		attrs.Attrs[RevocationHandleAttr] = hex.EncodeToString(idemixID.RevocationHandle)
+	}
 
 	return attrs, nil
 }
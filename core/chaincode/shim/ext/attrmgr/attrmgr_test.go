@@ -20,7 +20,9 @@ import (
 	"encoding/base64"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"justledger/core/chaincode/shim/ext/attrmgr"
+	"justledger/protos/msp"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -95,6 +97,34 @@ func TestIdemixAttrs(t *testing.T) {
 	checkAttr(t, "ou", "org1.department1", attrs)
 	checkAttr(t, "role", "member", attrs)
 	checkAttr(t, "id", "", attrs)
+	checkAttr(t, attrmgr.EnrollmentIDAttr, "", attrs)
+	checkAttr(t, attrmgr.RevocationHandleAttr, "", attrs)
+}
+
+func TestIdemixDisclosedAttrs(t *testing.T) {
+	mgr := attrmgr.New()
+
+	ou, err := proto.Marshal(&msp.OrganizationUnit{OrganizationalUnitIdentifier: "org1.department1"})
+	assert.NoError(t, err)
+	role, err := proto.Marshal(&msp.MSPRole{Role: msp.MSPRole_CLIENT})
+	assert.NoError(t, err)
+	idemixID, err := proto.Marshal(&msp.SerializedIdemixIdentity{
+		Ou:               ou,
+		Role:             role,
+		EnrollmentId:     "user1",
+		RevocationHandle: []byte{0x01, 0x02, 0xab},
+	})
+	assert.NoError(t, err)
+	creatorBytes, err := proto.Marshal(&msp.SerializedIdentity{Mspid: "idemixMSPID", IdBytes: idemixID})
+	assert.NoError(t, err)
+
+	attrs, err := mgr.GetAttributesFromIdemix(creatorBytes)
+	assert.NoError(t, err)
+	assert.Len(t, attrs.Names(), 4)
+	checkAttr(t, attrmgr.IdemixOUAttr, "org1.department1", attrs)
+	checkAttr(t, attrmgr.IdemixRoleAttr, "client", attrs)
+	checkAttr(t, attrmgr.EnrollmentIDAttr, "user1", attrs)
+	checkAttr(t, attrmgr.RevocationHandleAttr, "0102ab", attrs)
 }
 
 func checkAttr(t *testing.T, name, val string, attrs *attrmgr.Attributes) {
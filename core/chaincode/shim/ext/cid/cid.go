@@ -79,12 +79,23 @@ func GetX509Certificate(stub ChaincodeStubInterface) (*x509.Certificate, error)
 	return c.GetX509Certificate()
 }
 
+// GetIdemixAttributes returns the attributes disclosed by the client's
+// idemix identity, or nil if it was not identified by an idemix identity.
+func GetIdemixAttributes(stub ChaincodeStubInterface) (*IdemixAttributes, error) {
+	c, err := New(stub)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetIdemixAttributes()
+}
+
 // ClientIdentityImpl implements the ClientIdentity interface
 type clientIdentityImpl struct {
-	stub  ChaincodeStubInterface
-	mspID string
-	cert  *x509.Certificate
-	attrs *attrmgr.Attributes
+	stub        ChaincodeStubInterface
+	mspID       string
+	cert        *x509.Certificate
+	idemixAttrs *IdemixAttributes
+	attrs       *attrmgr.Attributes
 }
 
 // New returns an instance of ClientIdentity
@@ -99,6 +110,15 @@ func New(stub ChaincodeStubInterface) (ClientIdentity, error) {
 
 // GetID returns a unique ID associated with the invoking identity.
 func (c *clientIdentityImpl) GetID() (string, error) {
+	if c.idemixAttrs != nil {
+		// An idemix identity is anonymous unless it discloses its enrollment ID,
+		// which is unique among the identities issued by the idemix MSP
+		if c.idemixAttrs.EnrollmentID == "" {
+			return "", errors.New("the idemix identity does not disclose its enrollment ID")
+		}
+		id := fmt.Sprintf("idemix::%s", c.idemixAttrs.EnrollmentID)
+		return base64.StdEncoding.EncodeToString([]byte(id)), nil
+	}
 	// The leading "x509::" distinquishes this as an X509 certificate, and
 	// the subject and issuer DNs uniquely identify the X509 certificate.
 	// The resulting ID will remain the same if the certificate is renewed.
@@ -141,6 +161,12 @@ func (c *clientIdentityImpl) GetX509Certificate() (*x509.Certificate, error) {
 	return c.cert, nil
 }
 
+// GetIdemixAttributes returns the attributes disclosed by the client's
+// idemix identity, or nil if it was not identified by an idemix identity.
+func (c *clientIdentityImpl) GetIdemixAttributes() (*IdemixAttributes, error) {
+	return c.idemixAttrs, nil
+}
+
 // Initialize the client
 func (c *clientIdentityImpl) init() error {
 	signingID, err := c.getIdentity()
@@ -192,6 +218,19 @@ func (c *clientIdentityImpl) getAttributesFromIdemix() error {
 		return errors.WithMessage(err, "failed to get attributes from the transaction invoker's idemix credential")
 	}
 	c.attrs = attrs
+
+	idemixAttrs := &IdemixAttributes{
+		OU:           attrs.Attrs[attrmgr.IdemixOUAttr],
+		Role:         attrs.Attrs[attrmgr.IdemixRoleAttr],
+		EnrollmentID: attrs.Attrs[attrmgr.EnrollmentIDAttr],
+	}
+	if rh, ok := attrs.Attrs[attrmgr.RevocationHandleAttr]; ok {
+		idemixAttrs.RevocationHandle, err = hex.DecodeString(rh)
+		if err != nil {
+			return errors.Wrap(err, "failed to decode the transaction invoker's revocation handle")
+		}
+	}
+	c.idemixAttrs = idemixAttrs
 	return nil
 }
 
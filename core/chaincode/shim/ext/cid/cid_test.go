@@ -124,6 +124,51 @@ func TestIdemix(t *testing.T) {
 	assert.Equal(t, attrVal, "member", "Value of attribute 'attr1' should be 'val1'")
 	attrVal, found, err = sinfo.GetAttributeValue("id")
 	assert.False(t, found, "Attribute 'id' should not be found in the submitter cert")
+
+	// the identity does not disclose its enrollment ID, so it has no ID
+	_, err = sinfo.GetID()
+	assert.Error(t, err, "GetID should fail for an idemix identity without enrollment ID")
+	cert, err := sinfo.GetX509Certificate()
+	assert.NoError(t, err)
+	assert.Nil(t, cert, "An idemix identity has no X509 certificate")
+	idemixAttrs, err := sinfo.GetIdemixAttributes()
+	assert.NoError(t, err)
+	assert.Equal(t, &cid.IdemixAttributes{OU: "org1.department1", Role: "member"}, idemixAttrs)
+}
+
+func TestIdemixDisclosedAttributes(t *testing.T) {
+	stub, err := getIdemixMockStubWithDisclosedAttrs("user1", []byte{0x01, 0x02, 0xab})
+	assert.NoError(t, err, "Failed to get mock idemix stub")
+	sinfo, err := cid.New(stub)
+	assert.NoError(t, err, "Failed to new client")
+
+	id, err := sinfo.GetID()
+	assert.NoError(t, err, "Failed to get ID of an idemix identity disclosing its enrollment ID")
+	decodedID, err := base64.StdEncoding.DecodeString(id)
+	assert.NoError(t, err)
+	assert.Equal(t, "idemix::user1", string(decodedID))
+
+	idemixAttrs, err := cid.GetIdemixAttributes(stub)
+	assert.NoError(t, err)
+	assert.Equal(t, &cid.IdemixAttributes{
+		OU:               "org1.department1",
+		Role:             "client",
+		EnrollmentID:     "user1",
+		RevocationHandle: []byte{0x01, 0x02, 0xab},
+	}, idemixAttrs)
+
+	// the enrollment ID is looked up under the same name as in Fabric CA certificates
+	err = sinfo.AssertAttributeValue("hf.EnrollmentID", "user1")
+	assert.NoError(t, err)
+	err = sinfo.AssertAttributeValue("hf.RevocationHandle", "0102ab")
+	assert.NoError(t, err)
+
+	// X509 identities have no idemix attributes
+	stub, err = getMockStubWithAttrs()
+	assert.NoError(t, err, "Failed to get mock submitter")
+	idemixAttrs, err = cid.GetIdemixAttributes(stub)
+	assert.NoError(t, err)
+	assert.Nil(t, idemixAttrs)
 }
 
 func getMockStub() (cid.ChaincodeStubInterface, error) {
@@ -167,6 +212,36 @@ func getIdemixMockStubWithAttrs() (cid.ChaincodeStubInterface, error) {
 	return stub, nil
 }
 
+func getIdemixMockStubWithDisclosedAttrs(enrollmentID string, revocationHandle []byte) (cid.ChaincodeStubInterface, error) {
+	stub := &mockStub{}
+	ou, err := proto.Marshal(&msp.OrganizationUnit{OrganizationalUnitIdentifier: "org1.department1"})
+	if err != nil {
+		return nil, err
+	}
+	role, err := proto.Marshal(&msp.MSPRole{Role: msp.MSPRole_CLIENT})
+	if err != nil {
+		return nil, err
+	}
+	idBytes, err := proto.Marshal(&msp.SerializedIdemixIdentity{
+		Ou:               ou,
+		Role:             role,
+		EnrollmentId:     enrollmentID,
+		RevocationHandle: revocationHandle,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sid := &msp.SerializedIdentity{Mspid: "idemixOrg",
+		IdBytes: idBytes,
+	}
+	b, err := proto.Marshal(sid)
+	if err != nil {
+		return nil, err
+	}
+	stub.creator = b
+	return stub, nil
+}
+
 func getMockStubWithNilCreator() (cid.ChaincodeStubInterface, error) {
 	c := &mockStub{}
 	c.creator = nil
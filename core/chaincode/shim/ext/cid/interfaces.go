@@ -33,6 +33,7 @@ type ClientIdentity interface {
 
 	// GetID returns the ID associated with the invoking identity.  This ID
 	// is guaranteed to be unique within the MSP.
+	// An idemix identity only has an ID if it discloses its enrollment ID.
 	GetID() (string, error)
 
 	// Return the MSP ID of the client
@@ -52,4 +53,18 @@ type ClientIdentity interface {
 	// GetX509Certificate returns the X509 certificate associated with the client,
 	// or nil if it was not identified by an X509 certificate.
 	GetX509Certificate() (*x509.Certificate, error)
+
+	// GetIdemixAttributes returns the attributes disclosed by the client's
+	// idemix identity, or nil if it was not identified by an idemix identity.
+	GetIdemixAttributes() (*IdemixAttributes, error)
+}
+
+// IdemixAttributes holds the attributes disclosed by an idemix identity.
+// The OU and the role are always disclosed, while the enrollment ID and
+// the revocation handle are empty unless the identity chose to disclose them.
+type IdemixAttributes struct {
+	OU               string
+	Role             string
+	EnrollmentID     string
+	RevocationHandle []byte
 }
@@ -236,6 +236,27 @@ type ChaincodeStubInterface interface {
 	// update ledger, and should limit use to read-only chaincode operations.
 	GetHistoryForKey(key string) (HistoryQueryIteratorInterface, error)
 
+	// GetHistoryForKeyWithOptions returns the history of key values like
+	// GetHistoryForKey, restricted to the block and time ranges of the
+	// given options and in the order they request. A nil options returns
+	// the whole history, oldest first.
+	// Like GetHistoryForKey, the query is NOT re-executed during validation
+	// phase and should be limited to read-only chaincode operations.
+	GetHistoryForKeyWithOptions(key string, options *HistoryQueryOptions) (HistoryQueryIteratorInterface, error)
+
+	// GetHistoryForPartialCompositeKey returns the history of the values of
+	// all the composite keys whose prefix matches the given partial composite
+	// key. The Key field of each returned KeyModification holds the composite
+	// key which was modified. The modifications are grouped by key and, for
+	// each key, ordered as requested by the options.
+	// The `objectType` and attributes are expected to have only valid utf8
+	// strings and should not contain U+0000 (nil byte) and U+10FFFF (biggest
+	// and unallocated code point).
+	// Like GetHistoryForKey, the query is NOT re-executed during validation
+	// phase and should be limited to read-only chaincode operations.
+	GetHistoryForPartialCompositeKey(objectType string, attributes []string,
+		options *HistoryQueryOptions) (HistoryQueryIteratorInterface, error)
+
 	// GetPrivateData returns the value of the specified `key` from the specified
 	// `collection`. Note that GetPrivateData doesn't read data from the
 	// private writeset, which has not been committed to the `collection`. In
@@ -262,6 +283,16 @@ type ChaincodeStubInterface interface {
 	// when the transaction is validated and successfully committed.
 	DelPrivateData(collection, key string) error
 
+	// PurgePrivateData records the specified `key` to be purged in the private
+	// writeset of the transaction. In addition to deleting the `key` from the
+	// collection, a purge permanently removes all the past values of the `key`
+	// from the private data store, the state database and the transient store of
+	// the peers when the transaction is validated and successfully committed.
+	// The hashes of the `key` and its past values on the chain are not affected.
+	// The transaction is valid only if its endorsements satisfy the purge policy
+	// of the collection; collections without a purge policy cannot be purged.
+	PurgePrivateData(collection, key string) error
+
 	// SetPrivateDataValidationParameter sets the key-level endorsement policy
 	// for the private data specified by `key`.
 	SetPrivateDataValidationParameter(collection, key string, ep []byte) error
@@ -181,6 +181,10 @@ func (stub *MockStub) DelPrivateData(collection string, key string) error {
 	return errors.New("Not Implemented")
 }
 
+func (stub *MockStub) PurgePrivateData(collection string, key string) error {
+	return errors.New("Not Implemented")
+}
+
 func (stub *MockStub) GetPrivateDataByRange(collection, startKey, endKey string) (StateQueryIteratorInterface, error) {
 	return nil, errors.New("Not Implemented")
 }
@@ -286,6 +290,19 @@ func (stub *MockStub) GetHistoryForKey(key string) (HistoryQueryIteratorInterfac
 	return nil, errors.New("not implemented")
 }
 
+// GetHistoryForKeyWithOptions function can be invoked by a chaincode to return a
+// restricted history of key values across time.
+func (stub *MockStub) GetHistoryForKeyWithOptions(key string, options *HistoryQueryOptions) (HistoryQueryIteratorInterface, error) {
+	return nil, errors.New("not implemented")
+}
+
+// GetHistoryForPartialCompositeKey function can be invoked by a chaincode to return
+// the history of the values of the keys matching a partial composite key.
+func (stub *MockStub) GetHistoryForPartialCompositeKey(objectType string, attributes []string,
+	options *HistoryQueryOptions) (HistoryQueryIteratorInterface, error) {
+	return nil, errors.New("not implemented")
+}
+
 //GetStateByPartialCompositeKey function can be invoked by a chaincode to query the
 //state based on a given partial composite key. This function returns an
 //iterator which can be used to iterate over all composite keys whose prefix
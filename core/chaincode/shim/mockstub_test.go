@@ -265,6 +265,8 @@ func TestMockMock(t *testing.T) {
 	stub.GetArgsSlice()
 	stub.SetEvent("e", nil)
 	stub.GetHistoryForKey("k")
+	stub.GetHistoryForKeyWithOptions("k", nil)
+	stub.GetHistoryForPartialCompositeKey("t", []string{"a"}, nil)
 	iter := &MockStateRangeQueryIterator{}
 	iter.HasNext()
 	iter.Close()
@@ -22,6 +22,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"justledger/common/flogging"
 	mockpeer "justledger/common/mocks/peer"
@@ -82,6 +83,9 @@ func (t *shimTestCC) Invoke(stub ChaincodeStubInterface) pb.Response {
 	} else if function == "delete" {
 		// Deletes an entity from its state
 		return t.delete(stub, args)
+	} else if function == "purge" {
+		// Purges an entity from a private data collection
+		return t.purge(stub, args)
 	} else if function == "query" {
 		// the old "Query" is now implemtned in invoke
 		return t.query(stub, args)
@@ -175,6 +179,20 @@ func (t *shimTestCC) delete(stub ChaincodeStubInterface, args []string) pb.Respo
 	return Success(nil)
 }
 
+// Purges an entity from a private data collection
+func (t *shimTestCC) purge(stub ChaincodeStubInterface, args []string) pb.Response {
+	if len(args) != 2 {
+		return Error("Incorrect number of arguments. Expecting 2")
+	}
+
+	err := stub.PurgePrivateData(args[0], args[1])
+	if err != nil {
+		return Error("Failed to purge private data")
+	}
+
+	return Success(nil)
+}
+
 // query callback representing the query of a chaincode
 func (t *shimTestCC) query(stub ChaincodeStubInterface, args []string) pb.Response {
 	var A string // Entities
@@ -727,6 +745,42 @@ func TestInvoke(t *testing.T) {
 	//wait for done
 	processDone(t, done, false)
 
+	//bad purge
+	respSet = &mockpeer.MockResponseSet{
+		DoneFunc:  errorFunc,
+		ErrorFunc: errorFunc,
+		Responses: []*mockpeer.MockResponse{
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_PURGE_PRIVATE_DATA, Txid: "4b", ChannelId: channelId}, RespMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Txid: "4b", ChannelId: channelId}},
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Txid: "4b", ChannelId: channelId}, RespMsg: nil},
+		},
+	}
+	peerSide.SetResponses(respSet)
+
+	ci = &pb.ChaincodeInput{Args: [][]byte{[]byte("purge"), []byte("coll"), []byte("A")}, Decorations: nil}
+	payload = utils.MarshalOrPanic(ci)
+	peerSide.Send(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_TRANSACTION, Payload: payload, Txid: "4b", ChannelId: channelId})
+
+	//wait for done
+	processDone(t, done, false)
+
+	//good purge
+	respSet = &mockpeer.MockResponseSet{
+		DoneFunc:  errorFunc,
+		ErrorFunc: errorFunc,
+		Responses: []*mockpeer.MockResponse{
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_PURGE_PRIVATE_DATA, Txid: "4c", ChannelId: channelId}, RespMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Txid: "4c", ChannelId: channelId}},
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Txid: "4c", ChannelId: channelId}, RespMsg: nil},
+		},
+	}
+	peerSide.SetResponses(respSet)
+
+	ci = &pb.ChaincodeInput{Args: [][]byte{[]byte("purge"), []byte("coll"), []byte("A")}, Decorations: nil}
+	payload = utils.MarshalOrPanic(ci)
+	peerSide.Send(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_TRANSACTION, Payload: payload, Txid: "4c", ChannelId: channelId})
+
+	//wait for done
+	processDone(t, done, false)
+
 	//bad invoke
 	respSet = &mockpeer.MockResponseSet{
 		DoneFunc:  errorFunc,
@@ -1197,3 +1251,23 @@ func TestSend(t *testing.T) {
 	err := stream.Send(msg)
 	assert.NotNil(t, err, "should have errored on panic")
 }
+
+func TestHistoryQueryOptionsToProto(t *testing.T) {
+	var nilOptions *HistoryQueryOptions
+	protoOptions, err := nilOptions.toProto()
+	assert.NoError(t, err)
+	assert.Nil(t, protoOptions)
+
+	protoOptions, err = (&HistoryQueryOptions{StartBlock: 2, EndBlock: 5, NewestFirst: true}).toProto()
+	assert.NoError(t, err)
+	assert.Equal(t, &pb.HistoryQueryOptions{StartBlock: 2, EndBlock: 5, NewestFirst: true}, protoOptions)
+
+	start := time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	protoOptions, err = (&HistoryQueryOptions{StartTime: start, EndTime: start.Add(time.Hour)}).toProto()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1538352000), protoOptions.StartTime.Seconds)
+	assert.Equal(t, int64(1538355600), protoOptions.EndTime.Seconds)
+
+	_, err = (&HistoryQueryOptions{StartTime: time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)}).toProto()
+	assert.Error(t, err)
+}
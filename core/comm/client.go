@@ -86,6 +86,10 @@ func (client *GRPCClient) parseSecureOptions(opts *SecureOptions) error {
 		}
 	}
 	if opts.RequireClientCert {
+		if opts.GetClientCertificate != nil {
+			client.tlsConfig.GetClientCertificate = opts.GetClientCertificate
+			return nil
+		}
 		// make sure we have both Key and Certificate
 		if opts.Key != nil &&
 			opts.Certificate != nil {
@@ -109,7 +113,11 @@ func (client *GRPCClient) parseSecureOptions(opts *SecureOptions) error {
 // when client certificates are required by the server
 func (client *GRPCClient) Certificate() tls.Certificate {
 	cert := tls.Certificate{}
-	if client.tlsConfig != nil && len(client.tlsConfig.Certificates) > 0 {
+	if client.tlsConfig != nil && client.tlsConfig.GetClientCertificate != nil {
+		if c, err := client.tlsConfig.GetClientCertificate(&tls.CertificateRequestInfo{}); err == nil && c != nil {
+			cert = *c
+		}
+	} else if client.tlsConfig != nil && len(client.tlsConfig.Certificates) > 0 {
 		cert = client.tlsConfig.Certificates[0]
 	}
 	return cert
@@ -125,7 +133,7 @@ func (client *GRPCClient) TLSEnabled() bool {
 // must send a certificate when making TLS connections
 func (client *GRPCClient) MutualTLSRequired() bool {
 	return client.tlsConfig != nil &&
-		len(client.tlsConfig.Certificates) > 0
+		(len(client.tlsConfig.Certificates) > 0 || client.tlsConfig.GetClientCertificate != nil)
 }
 
 // SetMaxRecvMsgSize sets the maximum message size the client can receive
@@ -81,6 +81,21 @@ func TestNewGRPCClient_GoodConfig(t *testing.T) {
 	assert.True(t, client.TLSEnabled())
 	assert.True(t, client.MutualTLSRequired())
 	assert.Equal(t, testCerts.clientCert, client.Certificate())
+
+	secOpts = &comm.SecureOptions{
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return &testCerts.clientCert, nil
+		},
+		UseTLS:            true,
+		ServerRootCAs:     [][]byte{testCerts.caPEM},
+		RequireClientCert: true,
+	}
+	config.SecOpts = secOpts
+	client, err = comm.NewGRPCClient(config)
+	assert.NoError(t, err)
+	assert.True(t, client.TLSEnabled())
+	assert.True(t, client.MutualTLSRequired())
+	assert.Equal(t, testCerts.clientCert, client.Certificate())
 }
 
 func TestNewGRPCClient_BadConfig(t *testing.T) {
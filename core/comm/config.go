@@ -29,7 +29,8 @@ var (
 		ServerTimeout:     time.Duration(20) * time.Second, // 20 sec - gRPC default
 		ServerMinInterval: time.Duration(1) * time.Minute,  // match ClientInterval
 	}
-	// strong TLS cipher suites
+	// strong TLS cipher suites. The ECDHE_ECDSA suites are also
+	// the ones negotiated for Ed25519 certificates
 	DefaultTLSCipherSuites = []uint16{
 		tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
 		tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
@@ -83,6 +84,10 @@ type SecureOptions struct {
 	Certificate []byte
 	// PEM-encoded private key to be used for TLS communication
 	Key []byte
+	// GetClientCertificate, if not nil, is called by TLS clients to obtain
+	// the client certificate of each handshake instead of using Certificate
+	// and Key, so that the certificate can be replaced without a restart
+	GetClientCertificate func(*tls.CertificateRequestInfo) (*tls.Certificate, error)
 	// Set of PEM-encoded X509 certificate authorities used by clients to
 	// verify server certificates
 	ServerRootCAs [][]byte
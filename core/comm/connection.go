@@ -42,7 +42,10 @@ type CASupport struct {
 // CredentialSupport type manages credentials used for gRPC client connections
 type CredentialSupport struct {
 	*CASupport
-	clientCert tls.Certificate
+	// lock to protect concurrent access to the client certificate, which
+	// may be replaced while connections are being established
+	clientCertLock sync.RWMutex
+	clientCert     tls.Certificate
 }
 
 // GetCredentialSupport returns the singleton CredentialSupport instance
@@ -108,16 +111,34 @@ func (cas *CASupport) GetClientRootCAs() (appRootCAs, ordererRootCAs [][]byte) {
 }
 
 // SetClientCertificate sets the tls.Certificate to use for gRPC client
-// connections
+// connections. The credentials returned before the call also use the new
+// certificate for the connections they establish afterwards
 func (cs *CredentialSupport) SetClientCertificate(cert tls.Certificate) {
+	cs.clientCertLock.Lock()
+	defer cs.clientCertLock.Unlock()
 	cs.clientCert = cert
 }
 
 // GetClientCertificate returns the client certificate of the CredentialSupport
 func (cs *CredentialSupport) GetClientCertificate() tls.Certificate {
+	cs.clientCertLock.RLock()
+	defer cs.clientCertLock.RUnlock()
 	return cs.clientCert
 }
 
+// getClientCertificate returns the current client certificate during a TLS handshake
+func (cs *CredentialSupport) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	cert := cs.GetClientCertificate()
+	return &cert, nil
+}
+
+// ClientCertificateCallback returns the tls.Config.GetClientCertificate callback
+// of the CredentialSupport, for clients that build their own TLS configuration
+// but must follow the changes of the client certificate
+func (cs *CredentialSupport) ClientCertificateCallback() func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return cs.getClientCertificate
+}
+
 // GetDeliverServiceCredentials returns GRPC transport credentials for given channel to be used by GRPC
 // clients which communicate with ordering service endpoints.
 // If the channel isn't found, error is returned.
@@ -127,7 +148,7 @@ func (cs *CredentialSupport) GetDeliverServiceCredentials(channelID string) (cre
 
 	var creds credentials.TransportCredentials
 	tlsConfig := &tls.Config{
-		Certificates: []tls.Certificate{cs.clientCert},
+		GetClientCertificate: cs.getClientCertificate,
 	}
 	certPool := x509.NewCertPool()
 
@@ -160,7 +181,7 @@ func (cs *CredentialSupport) GetDeliverServiceCredentials(channelID string) (cre
 func (cs *CredentialSupport) GetPeerCredentials() credentials.TransportCredentials {
 	var creds credentials.TransportCredentials
 	tlsConfig := &tls.Config{
-		Certificates: []tls.Certificate{cs.clientCert},
+		GetClientCertificate: cs.getClientCertificate,
 	}
 	certPool := x509.NewCertPool()
 	// loop through the server root CAs
@@ -18,6 +18,7 @@ import (
 	"testing"
 	"time"
 
+	"justledger/common/crypto/tlsgen"
 	testpb "justledger/core/comm/testdata/grpc"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
@@ -370,3 +371,66 @@ func testInvoke(
 	assert.NoError(t, err)
 	s.assertServiced(t)
 }
+
+func TestCredentialSupportClientCertificateRotation(t *testing.T) {
+	t.Parallel()
+	ca, err := tlsgen.NewCA()
+	assert.NoError(t, err)
+	serverKeyPair, err := ca.NewServerCertKeyPair("127.0.0.1")
+	assert.NoError(t, err)
+	serverCert, err := tls.X509KeyPair(serverKeyPair.Cert, serverKeyPair.Key)
+	assert.NoError(t, err)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAnyClientCert,
+	})
+	assert.NoError(t, err)
+	defer listener.Close()
+	clientCerts := make(chan []byte, 2)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			tlsConn := conn.(*tls.Conn)
+			if err := tlsConn.Handshake(); err == nil {
+				clientCerts <- tlsConn.ConnectionState().PeerCertificates[0].Raw
+			}
+			tlsConn.Close()
+		}
+	}()
+
+	cs := &CredentialSupport{
+		CASupport: &CASupport{
+			AppRootCAsByChain:     make(map[string][][]byte),
+			OrdererRootCAsByChain: make(map[string][][]byte),
+			ServerRootCAs:         [][]byte{ca.CertBytes()},
+		},
+	}
+	handshakeCert := func(creds credentials.TransportCredentials) []byte {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		assert.NoError(t, err)
+		defer conn.Close()
+		_, _, err = creds.ClientHandshake(context.Background(), "127.0.0.1", conn)
+		assert.NoError(t, err)
+		select {
+		case cert := <-clientCerts:
+			return cert
+		case <-time.After(5 * time.Second):
+			t.Fatal("the server did not complete the handshake")
+			return nil
+		}
+	}
+
+	for _, keyPair := range []func() (*tlsgen.CertKeyPair, error){ca.NewClientCertKeyPair, ca.NewClientCertKeyPair} {
+		creds := cs.GetPeerCredentials()
+		kp, err := keyPair()
+		assert.NoError(t, err)
+		cert, err := tls.X509KeyPair(kp.Cert, kp.Key)
+		assert.NoError(t, err)
+		// credentials obtained before the client certificate is set use it in later handshakes
+		cs.SetClientCertificate(cert)
+		assert.Equal(t, kp.TLSCert.Raw, handshakeCert(creds))
+	}
+}
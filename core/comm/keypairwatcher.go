@@ -0,0 +1,125 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// KeyPairWatcher watches the files of a TLS key pair and hands the new key
+// pair to its listeners whenever the files change, e.g. after the
+// certificate was renewed, so that the TLS credentials of servers and
+// clients can be replaced without a restart.
+// Connections established before the change keep the key pair negotiated
+// during their handshake until they close; only new handshakes use the new
+// key pair
+type KeyPairWatcher struct {
+	certFile  string
+	keyFile   string
+	interval  time.Duration
+	listeners []func(tls.Certificate)
+
+	lock    sync.Mutex
+	certPEM []byte
+	keyPEM  []byte
+	stop    chan struct{}
+	stopped sync.Once
+}
+
+// NewKeyPairWatcher creates a KeyPairWatcher for the given certificate and
+// key files, which checks them for changes every interval. The key pair
+// currently in the files is loaded and must be valid
+func NewKeyPairWatcher(certFile, keyFile string, interval time.Duration, listeners ...func(tls.Certificate)) (*KeyPairWatcher, error) {
+	if interval <= 0 {
+		return nil, errors.Errorf("invalid interval %s", interval)
+	}
+	w := &KeyPairWatcher{
+		certFile:  certFile,
+		keyFile:   keyFile,
+		interval:  interval,
+		listeners: listeners,
+		stop:      make(chan struct{}),
+	}
+	certPEM, keyPEM, err := w.read()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		return nil, errors.Wrapf(err, "invalid key pair in %s and %s", certFile, keyFile)
+	}
+	w.certPEM, w.keyPEM = certPEM, keyPEM
+	return w, nil
+}
+
+// Start checks the files for changes every interval until Stop is called
+func (w *KeyPairWatcher) Start() {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := w.Check(); err != nil {
+					commLogger.Warningf("Failed reloading TLS key pair: %s", err)
+				}
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops checking the files for changes
+func (w *KeyPairWatcher) Stop() {
+	w.stopped.Do(func() { close(w.stop) })
+}
+
+// Check reads the files and, if they changed and hold a valid key pair,
+// hands the new key pair to the listeners and returns true.
+// An invalid key pair, such as a certificate that was replaced but not yet
+// its key, is reported as an error and the current key pair is kept
+func (w *KeyPairWatcher) Check() (bool, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	certPEM, keyPEM, err := w.read()
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(certPEM, w.certPEM) && bytes.Equal(keyPEM, w.keyPEM) {
+		return false, nil
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid key pair in %s and %s", w.certFile, w.keyFile)
+	}
+	w.certPEM, w.keyPEM = certPEM, keyPEM
+
+	commLogger.Infof("TLS key pair in %s and %s changed, new connections will use it", w.certFile, w.keyFile)
+	for _, listener := range w.listeners {
+		listener(cert)
+	}
+	return true, nil
+}
+
+func (w *KeyPairWatcher) read() ([]byte, []byte, error) {
+	certPEM, err := ioutil.ReadFile(w.certFile)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed reading TLS certificate")
+	}
+	keyPEM, err := ioutil.ReadFile(w.keyFile)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed reading TLS key")
+	}
+	return certPEM, keyPEM, nil
+}
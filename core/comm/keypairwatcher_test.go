@@ -0,0 +1,167 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"justledger/common/crypto/tlsgen"
+	"justledger/core/comm"
+	testpb "justledger/core/comm/testdata/grpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+func TestKeyPairWatcherRotatesServerCertificate(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "keypairwatcher")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+	writeKeyPair := func(kp *tlsgen.CertKeyPair) {
+		require.NoError(t, ioutil.WriteFile(certFile, kp.Cert, 0600))
+		require.NoError(t, ioutil.WriteFile(keyFile, kp.Key, 0600))
+	}
+
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+	oldKeyPair, err := ca.NewServerCertKeyPair("127.0.0.1")
+	require.NoError(t, err)
+	newKeyPair, err := ca.NewServerCertKeyPair("127.0.0.1")
+	require.NoError(t, err)
+	writeKeyPair(oldKeyPair)
+
+	srv, err := comm.NewGRPCServer("127.0.0.1:", comm.ServerConfig{
+		SecOpts: &comm.SecureOptions{
+			UseTLS:      true,
+			Certificate: oldKeyPair.Cert,
+			Key:         oldKeyPair.Key,
+		},
+	})
+	require.NoError(t, err)
+	testpb.RegisterEmptyServiceServer(srv.Server(), &emptyServiceServer{})
+	go srv.Start()
+	defer srv.Stop()
+
+	watcher, err := comm.NewKeyPairWatcher(certFile, keyFile, time.Minute, srv.SetServerCertificate)
+	require.NoError(t, err)
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AppendCertsFromPEM(ca.CertBytes())
+	// serverCert returns the certificate presented by the server in a new handshake
+	serverCert := func() []byte {
+		conn, err := tls.Dial("tcp", srv.Address(), &tls.Config{RootCAs: rootCAs})
+		require.NoError(t, err)
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].Raw
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	clientConn, err := grpc.DialContext(ctx, srv.Address(), grpc.WithBlock(),
+		grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{RootCAs: rootCAs})))
+	require.NoError(t, err)
+	defer clientConn.Close()
+	client := testpb.NewEmptyServiceClient(clientConn)
+	_, err = client.EmptyCall(context.Background(), &testpb.Empty{})
+	require.NoError(t, err)
+
+	changed, err := watcher.Check()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, oldKeyPair.TLSCert.Raw, serverCert())
+
+	// A certificate replaced before its key is not picked up
+	require.NoError(t, ioutil.WriteFile(certFile, newKeyPair.Cert, 0600))
+	changed, err = watcher.Check()
+	assert.False(t, changed)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid key pair in")
+	assert.Equal(t, oldKeyPair.TLSCert.Raw, serverCert())
+
+	writeKeyPair(newKeyPair)
+	changed, err = watcher.Check()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, newKeyPair.TLSCert.Raw, serverCert())
+
+	// The connection established before the rotation is still usable
+	_, err = client.EmptyCall(context.Background(), &testpb.Empty{})
+	assert.NoError(t, err)
+	assert.Equal(t, oldKeyPair.TLSCert.Raw, srvCertOfConn(t, clientConn))
+
+	// The watcher checks the files periodically once started
+	rotated := make(chan tls.Certificate, 1)
+	periodicWatcher, err := comm.NewKeyPairWatcher(certFile, keyFile, 10*time.Millisecond, func(cert tls.Certificate) {
+		rotated <- cert
+	})
+	require.NoError(t, err)
+	periodicWatcher.Start()
+	defer periodicWatcher.Stop()
+	writeKeyPair(oldKeyPair)
+	select {
+	case cert := <-rotated:
+		assert.Equal(t, oldKeyPair.TLSCert.Raw, cert.Certificate[0])
+	case <-time.After(5 * time.Second):
+		t.Fatal("the key pair was not reloaded")
+	}
+}
+
+// srvCertOfConn returns the server certificate of the connection underlying a gRPC client connection
+func srvCertOfConn(t *testing.T, clientConn *grpc.ClientConn) []byte {
+	p := &peer.Peer{}
+	client := testpb.NewEmptyServiceClient(clientConn)
+	_, err := client.EmptyCall(context.Background(), &testpb.Empty{}, grpc.Peer(p))
+	require.NoError(t, err)
+	return p.AuthInfo.(credentials.TLSInfo).State.PeerCertificates[0].Raw
+}
+
+func TestNewKeyPairWatcherErrors(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "keypairwatcher")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+	keyPair, err := ca.NewServerCertKeyPair("127.0.0.1")
+	require.NoError(t, err)
+	otherKeyPair, err := ca.NewServerCertKeyPair("127.0.0.1")
+	require.NoError(t, err)
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+	require.NoError(t, ioutil.WriteFile(certFile, keyPair.Cert, 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, otherKeyPair.Key, 0600))
+
+	_, err = comm.NewKeyPairWatcher(certFile, keyFile, 0)
+	assert.EqualError(t, err, "invalid interval 0s")
+
+	_, err = comm.NewKeyPairWatcher(filepath.Join(dir, "missing.crt"), keyFile, time.Minute)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed reading TLS certificate")
+
+	_, err = comm.NewKeyPairWatcher(certFile, filepath.Join(dir, "missing.key"), time.Minute)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed reading TLS key")
+
+	_, err = comm.NewKeyPairWatcher(certFile, keyFile, time.Minute)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid key pair in")
+}
@@ -9,13 +9,18 @@ package comm_test
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
+	"math/big"
 	"net"
 	"path/filepath"
 	"sync"
@@ -1724,6 +1729,110 @@ func TestCipherSuites(t *testing.T) {
 	}
 }
 
+// newED25519CertKeyPair issues an Ed25519 certificate for localhost, signed by
+// the given parent, and returns it together with its key in PEM format.
+// If parent is nil, the certificate is a self signed CA certificate
+func newED25519CertKeyPair(t *testing.T, parent *x509.Certificate, parentKey ed25519.PrivateKey) (*x509.Certificate, ed25519.PrivateKey, []byte, []byte) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = template, priv
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, parentKey)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	assert.NoError(t, err)
+
+	return cert, priv,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestED25519MutualTLS(t *testing.T) {
+	t.Parallel()
+
+	caCert, caKey, caPEM, _ := newED25519CertKeyPair(t, nil, nil)
+	_, _, serverCert, serverKey := newED25519CertKeyPair(t, caCert, caKey)
+	_, _, clientCert, clientKey := newED25519CertKeyPair(t, caCert, caKey)
+
+	srv, err := comm.NewGRPCServer("localhost:0", comm.ServerConfig{
+		SecOpts: &comm.SecureOptions{
+			UseTLS:            true,
+			Certificate:       serverCert,
+			Key:               serverKey,
+			RequireClientCert: true,
+			ClientRootCAs:     [][]byte{caPEM},
+		},
+	})
+	assert.NoError(t, err)
+	testpb.RegisterEmptyServiceServer(srv.Server(), &emptyServiceServer{})
+	go srv.Start()
+	defer srv.Stop()
+
+	client, err := comm.NewGRPCClient(comm.ClientConfig{
+		Timeout: timeout,
+		SecOpts: &comm.SecureOptions{
+			UseTLS:            true,
+			ServerRootCAs:     [][]byte{caPEM},
+			RequireClientCert: true,
+			Certificate:       clientCert,
+			Key:               clientKey,
+		},
+	})
+	assert.NoError(t, err)
+	conn, err := client.NewConnection(srv.Address(), "localhost")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+	_, err = testpb.NewEmptyServiceClient(conn).EmptyCall(context.Background(), &testpb.Empty{})
+	assert.NoError(t, err)
+
+	// a client whose certificate is issued by another CA is rejected
+	otherCACert, otherCAKey, _, _ := newED25519CertKeyPair(t, nil, nil)
+	_, _, untrustedCert, untrustedKey := newED25519CertKeyPair(t, otherCACert, otherCAKey)
+	_, err = invokeEmptyCall(srv.Address(), []grpc.DialOption{
+		grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+			ServerName:   "localhost",
+			RootCAs:      createCertPoolOrPanic(caPEM),
+			Certificates: []tls.Certificate{loadX509KeyPairOrPanic(untrustedCert, untrustedKey)},
+		})),
+		grpc.WithBlock(),
+	})
+	assert.Error(t, err)
+}
+
+func createCertPoolOrPanic(rootCA []byte) *x509.CertPool {
+	certPool, err := createCertPool([][]byte{rootCA})
+	if err != nil {
+		panic(err)
+	}
+	return certPool
+}
+
+func loadX509KeyPairOrPanic(certPEM, keyPEM []byte) tls.Certificate {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		panic(err)
+	}
+	return cert
+}
+
 func TestServerInterceptors(t *testing.T) {
 
 	lis, err := net.Listen("tcp", "127.0.0.1:0")
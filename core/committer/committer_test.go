@@ -10,6 +10,7 @@ import (
 	"errors"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"justledger/common/configtx/test"
 	"justledger/common/ledger"
@@ -80,6 +81,16 @@ func (m *mockLedger) GetTxValidationCodeByTxID(txID string) (peer.TxValidationCo
 	return args.Get(0).(peer.TxValidationCode), args.Error(1)
 }
 
+func (m *mockLedger) GetBlocksByTimeRange(startTime, endTime time.Time, pageSize int, bookmark string) ([]*common.Block, string, error) {
+	args := m.Called(startTime, endTime, pageSize, bookmark)
+	return args.Get(0).([]*common.Block), args.String(1), args.Error(2)
+}
+
+func (m *mockLedger) GetTransactionsByTimeRange(startTime, endTime time.Time, pageSize int, bookmark string) ([]*peer.ProcessedTransaction, string, error) {
+	args := m.Called(startTime, endTime, pageSize, bookmark)
+	return args.Get(0).([]*peer.ProcessedTransaction), args.String(1), args.Error(2)
+}
+
 func (m *mockLedger) NewTxSimulator(txid string) (ledger2.TxSimulator, error) {
 	args := m.Called(txid)
 	return args.Get(0).(ledger2.TxSimulator), args.Error(1)
@@ -1413,6 +1413,18 @@ func (m *mockLedger) GetTxValidationCodeByTxID(txID string) (peer.TxValidationCo
 	return args.Get(0).(peer.TxValidationCode), nil
 }
 
+// GetBlocksByTimeRange returns the blocks committed in the given time range
+func (m *mockLedger) GetBlocksByTimeRange(startTime, endTime time.Time, pageSize int, bookmark string) ([]*common.Block, string, error) {
+	args := m.Called(startTime, endTime, pageSize, bookmark)
+	return args.Get(0).([]*common.Block), args.String(1), nil
+}
+
+// GetTransactionsByTimeRange returns the transactions whose timestamp is in the given time range
+func (m *mockLedger) GetTransactionsByTimeRange(startTime, endTime time.Time, pageSize int, bookmark string) ([]*peer.ProcessedTransaction, string, error) {
+	args := m.Called(startTime, endTime, pageSize, bookmark)
+	return args.Get(0).([]*peer.ProcessedTransaction), args.String(1), nil
+}
+
 // NewTxSimulator creates new transaction simulator
 func (m *mockLedger) NewTxSimulator(txid string) (ledger.TxSimulator, error) {
 	args := m.Called()
@@ -22,6 +22,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"justledger/common/channelconfig"
 	"justledger/common/flogging"
+	mspi "justledger/msp"
 	mspmgmt "justledger/msp/mgmt"
 	"justledger/protos/common"
 	"justledger/protos/msp"
@@ -100,6 +101,9 @@ func ValidateProposalMessage(signedProp *pb.SignedProposal) (*pb.Proposal, *comm
 
 	// validate the signature
 	err = checkSignatureFromCreator(shdr.Creator, signedProp.Signature, signedProp.ProposalBytes, chdr.ChannelId)
+	if err == nil {
+		err = checkCreatorRevocationOnline(shdr.Creator, chdr.ChannelId)
+	}
 	if err != nil {
 		// log the exact message on the peer but return a generic error message to
 		// avoid malicious users scanning for channels
@@ -190,6 +194,50 @@ func checkSignatureFromCreator(creatorBytes []byte, sig []byte, msg []byte, Chai
 	return nil
 }
 
+// checkCreatorRevocationOnline checks the revocation status of the creator of
+// a proposal online, if the MSP of the creator in the channel or the local MSP
+// is configured to. This is only done at endorsement time: the outcome depends
+// on remote services, so it must never decide the validity of a transaction
+func checkCreatorRevocationOnline(creatorBytes []byte, ChainID string) error {
+	sId := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(creatorBytes, sId); err != nil {
+		return errors.Wrap(err, "could not deserialize a SerializedIdentity")
+	}
+
+	var msps []mspi.MSP
+	if ChainID != "" {
+		channelMSPs, err := mspmgmt.GetManagerForChain(ChainID).GetMSPs()
+		if err != nil {
+			return errors.WithMessage(err, "MSP error")
+		}
+		if channelMSP, ok := channelMSPs[sId.Mspid]; ok {
+			msps = append(msps, channelMSP)
+		}
+	}
+	localMSP := mspmgmt.GetLocalMSP()
+	if localMSPID, err := localMSP.GetIdentifier(); err == nil && localMSPID == sId.Mspid {
+		msps = append(msps, localMSP)
+	}
+
+	for _, mspObj := range msps {
+		checker, ok := mspObj.(mspi.OnlineRevocationChecker)
+		if !ok {
+			continue
+		}
+
+		creator, err := mspObj.DeserializeIdentity(creatorBytes)
+		if err != nil {
+			return errors.WithMessage(err, "MSP error")
+		}
+
+		if err := checker.CheckRevocationOnline(creator); err != nil {
+			return errors.WithMessage(err, "creator certificate is not valid")
+		}
+	}
+
+	return nil
+}
+
 // checks for a valid SignatureHeader
 func validateSignatureHeader(sHdr *common.SignatureHeader) error {
 	// check for nil argument
@@ -4,11 +4,16 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"justledger/common/util"
+	mspi "justledger/msp"
 	"justledger/msp/mgmt"
+	"justledger/msp/mocks"
 	"justledger/protos/common"
+	"justledger/protos/msp"
 	"justledger/protos/peer"
 	"justledger/protos/utils"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -94,6 +99,50 @@ func TestCheckSignatureFromCreator(t *testing.T) {
 	assert.Contains(t, err.Error(), "MSP error: channel doesn't exist")
 }
 
+type revocationCheckingMSP struct {
+	*mocks.MockMSP
+	err error
+}
+
+func (m *revocationCheckingMSP) CheckRevocationOnline(id mspi.Identity) error {
+	return m.err
+}
+
+func TestCheckCreatorRevocationOnline(t *testing.T) {
+	// The MSPs do not check revocation online unless configured to
+	assert.NoError(t, checkCreatorRevocationOnline(signerSerialized, ""))
+	assert.NoError(t, checkCreatorRevocationOnline(signerSerialized, util.GetTestChainID()))
+
+	// Creators of MSPs that are neither local nor in the channel are not checked
+	foreign, err := proto.Marshal(&msp.SerializedIdentity{Mspid: "OtherOrg", IdBytes: []byte("cert")})
+	assert.NoError(t, err)
+	assert.NoError(t, checkCreatorRevocationOnline(foreign, ""))
+	assert.NoError(t, checkCreatorRevocationOnline(foreign, util.GetTestChainID()))
+
+	// Bad creator
+	err = checkCreatorRevocationOnline([]byte("junk"), util.GetTestChainID())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "could not deserialize a SerializedIdentity")
+
+	// The channel MSP of the creator checks revocation online
+	mockMSP := &mocks.MockMSP{}
+	mockMSP.On("GetIdentifier").Return("OtherOrg", nil)
+	mockMSP.On("GetType").Return(mspi.FABRIC)
+	mockMSP.On("DeserializeIdentity", foreign).Return(&mocks.MockIdentity{ID: "OtherOrg"}, nil)
+	channelMSP := &revocationCheckingMSP{MockMSP: mockMSP, err: errors.New("certificate is revoked")}
+	mspMgr := mspi.NewMSPManager()
+	err = mspMgr.Setup([]mspi.MSP{channelMSP})
+	assert.NoError(t, err)
+	mgmt.XXXSetMSPManager("revocationchannel", mspMgr)
+
+	err = checkCreatorRevocationOnline(foreign, "revocationchannel")
+	assert.EqualError(t, err, "creator certificate is not valid: certificate is revoked")
+	assert.NoError(t, checkCreatorRevocationOnline(foreign, ""))
+
+	channelMSP.err = nil
+	assert.NoError(t, checkCreatorRevocationOnline(foreign, "revocationchannel"))
+}
+
 func TestValidateProposalMessage(t *testing.T) {
 	// nonexistent channel
 	fakeChannel := "fakechannel"
@@ -95,6 +95,9 @@ type Support interface {
 
 	// GetLedgerHeight returns ledger height for given channelID
 	GetLedgerHeight(channelID string) (uint64, error)
+
+	// IsChannelPaused returns true if the channel with the given channelID is paused on this peer
+	IsChannelPaused(channelID string) (bool, error)
 }
 
 // Endorser provides the Endorser service ProcessProposal
@@ -387,6 +390,16 @@ func (e *Endorser) preProcess(signedProp *pb.SignedProposal) (*validateResult, e
 				return vr, err
 			}
 		}
+
+		// reject proposals for a channel that is paused on this peer, as its ledger is not opened
+		paused, err := e.s.IsChannelPaused(chainID)
+		if err == nil && paused {
+			err = errors.Errorf("channel [%s] is paused on this peer", chainID)
+		}
+		if err != nil {
+			vr.resp = &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}
+			return vr, err
+		}
 	} else {
 		// chainless proposals do not/cannot affect ledger and cannot be submitted as transactions
 		// ignore uniqueness checks; also, chainless proposals are not validated using the policies
@@ -278,6 +278,44 @@ func TestEndorserDupTXId(t *testing.T) {
 	assert.Regexp(t, "duplicate transaction found", pResp.Response.Message)
 }
 
+func TestEndorserPausedChannel(t *testing.T) {
+	support := &em.MockSupport{
+		GetApplicationConfigBoolRv: true,
+		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
+		IsChannelPausedRv:          true,
+		GetTransactionByIDErr:      errors.New(""),
+		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC"},
+		ExecuteResp:                &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}})},
+		GetTxSimulatorRv: &mockccprovider.MockTxSim{
+			GetTxSimulationResultsRv: &ledger.TxSimulationResults{
+				PubSimulationResults: &rwset.TxReadWriteSet{},
+			},
+		},
+	}
+	es := endorser.NewEndorserServer(pvtEmptyDistributor, support, platforms.NewRegistry(&golang.Platform{}))
+
+	signedProp := getSignedProp("ccid", "0", t)
+
+	pResp, err := es.ProcessProposal(context.Background(), signedProp)
+	assert.EqualError(t, err, fmt.Sprintf("channel [%s] is paused on this peer", util.GetTestChainID()))
+	assert.EqualValues(t, 500, pResp.Response.Status)
+	assert.Equal(t, fmt.Sprintf("channel [%s] is paused on this peer", util.GetTestChainID()), pResp.Response.Message)
+
+	support.IsChannelPausedRv = false
+	support.IsChannelPausedErr = errors.New("ledger mgmt should be initialized before using")
+	pResp, err = es.ProcessProposal(context.Background(), signedProp)
+	assert.EqualError(t, err, "ledger mgmt should be initialized before using")
+	assert.EqualValues(t, 500, pResp.Response.Status)
+
+	// the ACL is checked before whether the channel is paused
+	support.IsChannelPausedRv = true
+	support.IsChannelPausedErr = nil
+	support.CheckACLErr = errors.New("access denied")
+	pResp, err = es.ProcessProposal(context.Background(), signedProp)
+	assert.EqualError(t, err, "access denied")
+	assert.EqualValues(t, 500, pResp.Response.Status)
+}
+
 func TestEndorserBadACL(t *testing.T) {
 	es := endorser.NewEndorserServer(pvtEmptyDistributor, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
@@ -98,6 +98,20 @@ func (_m *Store) PurgeByHeight(maxBlockNumToRetain uint64) error {
 	return r0
 }
 
+// PurgeByKeyHashes provides a mock function with given fields: blockNum, purges
+func (_m *Store) PurgeByKeyHashes(blockNum uint64, purges []*ledger.PvtdataPurge) error {
+	ret := _m.Called(blockNum, purges)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint64, []*ledger.PvtdataPurge) error); ok {
+		r0 = rf(blockNum, purges)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // PurgeByTxids provides a mock function with given fields: txids
 func (_m *Store) PurgeByTxids(txids []string) error {
 	ret := _m.Called(txids)
@@ -263,6 +263,19 @@ type Support struct {
 		result1 uint64
 		result2 error
 	}
+	IsChannelPausedStub        func(channelID string) (bool, error)
+	isChannelPausedMutex       sync.RWMutex
+	isChannelPausedArgsForCall []struct {
+		channelID string
+	}
+	isChannelPausedReturns struct {
+		result1 bool
+		result2 error
+	}
+	isChannelPausedReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -1203,6 +1216,57 @@ func (fake *Support) GetLedgerHeightReturnsOnCall(i int, result1 uint64, result2
 	}{result1, result2}
 }
 
+func (fake *Support) IsChannelPaused(channelID string) (bool, error) {
+	fake.isChannelPausedMutex.Lock()
+	ret, specificReturn := fake.isChannelPausedReturnsOnCall[len(fake.isChannelPausedArgsForCall)]
+	fake.isChannelPausedArgsForCall = append(fake.isChannelPausedArgsForCall, struct {
+		channelID string
+	}{channelID})
+	fake.recordInvocation("IsChannelPaused", []interface{}{channelID})
+	fake.isChannelPausedMutex.Unlock()
+	if fake.IsChannelPausedStub != nil {
+		return fake.IsChannelPausedStub(channelID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.isChannelPausedReturns.result1, fake.isChannelPausedReturns.result2
+}
+
+func (fake *Support) IsChannelPausedCallCount() int {
+	fake.isChannelPausedMutex.RLock()
+	defer fake.isChannelPausedMutex.RUnlock()
+	return len(fake.isChannelPausedArgsForCall)
+}
+
+func (fake *Support) IsChannelPausedArgsForCall(i int) string {
+	fake.isChannelPausedMutex.RLock()
+	defer fake.isChannelPausedMutex.RUnlock()
+	return fake.isChannelPausedArgsForCall[i].channelID
+}
+
+func (fake *Support) IsChannelPausedReturns(result1 bool, result2 error) {
+	fake.IsChannelPausedStub = nil
+	fake.isChannelPausedReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Support) IsChannelPausedReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.IsChannelPausedStub = nil
+	if fake.isChannelPausedReturnsOnCall == nil {
+		fake.isChannelPausedReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.isChannelPausedReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Support) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -1242,6 +1306,8 @@ func (fake *Support) Invocations() map[string][][]interface{} {
 	defer fake.endorseWithPluginMutex.RUnlock()
 	fake.getLedgerHeightMutex.RLock()
 	defer fake.getLedgerHeightMutex.RUnlock()
+	fake.isChannelPausedMutex.RLock()
+	defer fake.isChannelPausedMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -19,6 +19,7 @@ import (
 	. "justledger/core/handlers/endorsement/api/identities"
 	"justledger/core/handlers/library"
 	"justledger/core/ledger"
+	"justledger/core/ledger/ledgermgmt"
 	"justledger/core/peer"
 	"justledger/core/scc"
 	"justledger/protos/common"
@@ -181,3 +182,8 @@ func (s *SupportImpl) CheckInstantiationPolicy(name, version string, cd ccprovid
 func (s *SupportImpl) GetApplicationConfig(cid string) (channelconfig.Application, bool) {
 	return s.PeerSupport.GetApplicationConfig(cid)
 }
+
+// IsChannelPaused returns true if the channel with the given channelID is paused on this peer
+func (s *SupportImpl) IsChannelPaused(channelID string) (bool, error) {
+	return ledgermgmt.IsLedgerPaused(channelID)
+}
@@ -146,6 +146,46 @@ func (vscc *Validator) Validate(
 		}
 	}
 
+	// purging private data requires the purge policy of the collection to be enforced, which only
+	// the newer validation logic does, so such transactions are invalid here
+	if err := vscc.checkNoPurges(cap); err != nil {
+		logger.Errorf("VSCC error: checkNoPurges failed, err %s", err)
+		return err
+	}
+
+	return nil
+}
+
+// checkNoPurges returns an error if the transaction purges private data
+func (vscc *Validator) checkNoPurges(cap *pb.ChaincodeActionPayload) commonerrors.TxValidationError {
+	pRespPayload, err := utils.GetProposalResponsePayload(cap.Action.ProposalResponsePayload)
+	if err != nil {
+		return policyErr(fmt.Errorf("GetProposalResponsePayload error %s", err))
+	}
+	if pRespPayload.Extension == nil {
+		return policyErr(fmt.Errorf("nil pRespPayload.Extension"))
+	}
+	respPayload, err := utils.GetChaincodeAction(pRespPayload.Extension)
+	if err != nil {
+		return policyErr(fmt.Errorf("GetChaincodeAction error %s", err))
+	}
+
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err = txRWSet.FromProtoBytes(respPayload.Results); err != nil {
+		// a malformed rwset is rejected by the ledger, so there is nothing to check here
+		logger.Debugf("could not extract purges from rwset, error %s", err)
+		return nil
+	}
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		for _, collRWSet := range nsRWSet.CollHashedRwSets {
+			for _, hashedWrite := range collRWSet.HashedRwSet.HashedWrites {
+				if hashedWrite.IsPurge {
+					return policyErr(fmt.Errorf("purging private data of collection %s of chaincode %s is not supported by this validation logic",
+						collRWSet.CollectionName, nsRWSet.NameSpace))
+				}
+			}
+		}
+	}
 	return nil
 }
 
@@ -2073,3 +2073,34 @@ func TestInValidCollectionName(t *testing.T) {
 		assert.Error(t, validateCollectionName(name), "Testing for name = "+name)
 	}
 }
+
+func TestPurgeNotSupported(t *testing.T) {
+	v := newValidationInstance(make(map[string]map[string][]byte))
+
+	ccid := &peer.ChaincodeID{Name: "foo", Version: "v1"}
+	cis := &peer.ChaincodeInvocationSpec{ChaincodeSpec: &peer.ChaincodeSpec{ChaincodeId: ccid}}
+	prop, _, err := utils.CreateProposalFromCIS(common.HeaderType_ENDORSER_TRANSACTION, util.GetTestChainID(), cis, sid)
+	assert.NoError(t, err)
+
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder.AddToPvtAndHashedWriteSetForPurge("foo", "coll", "key")
+	simRes, err := rwsetBuilder.GetTxSimulationResults()
+	assert.NoError(t, err)
+	res, err := simRes.GetPubSimulationBytes()
+	assert.NoError(t, err)
+
+	presp, err := utils.CreateProposalResponse(prop.Header, prop.Payload, &peer.Response{Status: 200}, res, nil, ccid, nil, id)
+	assert.NoError(t, err)
+	tx, err := utils.CreateSignedTx(prop, id, presp)
+	assert.NoError(t, err)
+	envBytes, err := utils.GetBytesEnvelope(tx)
+	assert.NoError(t, err)
+
+	policy, err := getSignedByMSPMemberPolicy(mspid)
+	assert.NoError(t, err)
+
+	b := &common.Block{Data: &common.BlockData{Data: [][]byte{envBytes}}, Header: &common.BlockHeader{}}
+	err = v.Validate(b, "foo", 0, 0, policy)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "purging private data of collection coll of chaincode foo is not supported")
+}
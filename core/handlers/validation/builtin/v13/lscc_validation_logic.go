@@ -95,6 +95,11 @@ func validateNewCollectionConfigs(newCollectionConfigs []*common.CollectionConfi
 		if err != nil {
 			return errors.WithMessage(err, fmt.Sprintf("collection-name: %s -- error in member org policy", collectionName))
 		}
+
+		// a purge policy, if any, has to be a signature policy
+		if newCollection.PurgePolicy != nil && newCollection.PurgePolicy.GetSignaturePolicy() == nil {
+			return fmt.Errorf("collection-name: %s -- purge policy must be a signature policy", collectionName)
+		}
 	}
 	return nil
 }
@@ -0,0 +1,119 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v13
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	commonerrors "justledger/common/errors"
+	"justledger/core/common/privdata"
+	"justledger/core/ledger/kvledger/txmgmt/rwsetutil"
+	"justledger/protos/common"
+	"justledger/protos/peer"
+)
+
+// purgedCollections returns the names of the collections of the given namespace
+// in which the supplied rwset purges at least one private data key
+func purgedCollections(namespace string, rwsetBytes []byte) ([]string, error) {
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(rwsetBytes); err != nil {
+		return nil, err
+	}
+
+	var colls []string
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if nsRWSet.NameSpace != namespace {
+			continue
+		}
+		for _, collRWSet := range nsRWSet.CollHashedRwSets {
+			for _, hashedWrite := range collRWSet.HashedRwSet.HashedWrites {
+				if hashedWrite.IsPurge {
+					colls = append(colls, collRWSet.CollectionName)
+					break
+				}
+			}
+		}
+	}
+	return colls, nil
+}
+
+// validatePurges checks that, for every collection in which the transaction purges private
+// data, the collection defines a purge policy and the endorsements of the transaction satisfy it
+func (vscc *Validator) validatePurges(namespace string, va *validationArtifacts) commonerrors.TxValidationError {
+	colls, err := purgedCollections(namespace, va.rwset)
+	if err != nil {
+		// a malformed rwset is rejected by the state-based validation
+		// and by the ledger, so there is nothing to check here
+		logger.Debugf("could not extract purges from rwset, error %s", err)
+		return nil
+	}
+	if len(colls) == 0 {
+		return nil
+	}
+
+	channelState, err := vscc.stateFetcher.FetchState()
+	if err != nil {
+		return &commonerrors.VSCCExecutionFailureError{Err: fmt.Errorf("failed obtaining query executor: %v", err)}
+	}
+	defer channelState.Done()
+
+	colCriteria := common.CollectionCriteria{Channel: va.chdr.ChannelId, Namespace: namespace}
+	ccp, err := privdata.RetrieveCollectionConfigPackageFromState(colCriteria, &state{channelState})
+	if err != nil {
+		if _, ok := err.(privdata.NoSuchCollectionError); ok {
+			return policyErr(fmt.Errorf("no collection configuration found for chaincode %s, cannot purge private data", namespace))
+		}
+		return &commonerrors.VSCCExecutionFailureError{Err: fmt.Errorf("unable to retrieve collection configuration for chaincode %s: %v", namespace, err)}
+	}
+
+	signatureSet := endorsementSignatureSet(va.prp, va.endorsements)
+	for _, coll := range colls {
+		purgePolicy, err := collectionPurgePolicy(ccp, coll)
+		if err != nil {
+			return policyErr(fmt.Errorf("collection %s of chaincode %s does not allow purging private data: %s", coll, namespace, err))
+		}
+		if err := vscc.policyEvaluator.Evaluate(purgePolicy, signatureSet); err != nil {
+			return policyErr(fmt.Errorf("purge policy of collection %s of chaincode %s violated, error %s", coll, namespace, err))
+		}
+	}
+	return nil
+}
+
+// collectionPurgePolicy returns the serialized purge policy of the given collection
+func collectionPurgePolicy(ccp *common.CollectionConfigPackage, coll string) ([]byte, error) {
+	for _, cconf := range ccp.Config {
+		staticConf := cconf.GetStaticCollectionConfig()
+		if staticConf == nil || staticConf.Name != coll {
+			continue
+		}
+		signaturePolicy := staticConf.PurgePolicy.GetSignaturePolicy()
+		if signaturePolicy == nil {
+			return nil, fmt.Errorf("no purge policy defined")
+		}
+		return proto.Marshal(signaturePolicy)
+	}
+	return nil, fmt.Errorf("collection not defined")
+}
+
+// endorsementSignatureSet returns the signed data of the given endorsements, whose signatures
+// are over the concatenation of the proposal response bytes and the endorser identity
+func endorsementSignatureSet(prp []byte, endorsements []*peer.Endorsement) []*common.SignedData {
+	signatureSet := []*common.SignedData{}
+	for _, endorsement := range endorsements {
+		data := make([]byte, len(prp)+len(endorsement.Endorser))
+		copy(data, prp)
+		copy(data[len(prp):], endorsement.Endorser)
+
+		signatureSet = append(signatureSet, &common.SignedData{
+			Data:      data,
+			Identity:  endorsement.Endorser,
+			Signature: endorsement.Signature,
+		})
+	}
+	return signatureSet
+}
@@ -187,6 +187,13 @@ func (vscc *Validator) Validate(
 		}
 	}
 
+	// purging private data additionally requires the purge policy of the collection to be satisfied
+	if err := vscc.validatePurges(namespace, va); err != nil {
+		logger.Errorf("VSCC error: validatePurges failed, err %s", err)
+		vscc.stateBasedValidator.PostValidate(namespace, block.Header.Number, uint64(txPosition), err)
+		return err
+	}
+
 	vscc.stateBasedValidator.PostValidate(namespace, block.Header.Number, uint64(txPosition), nil)
 	return nil
 }
@@ -1896,6 +1896,19 @@ func TestValidateRWSetAndCollectionForDeploy(t *testing.T) {
 	err = testValidateCollection(t, v, []*common.CollectionConfig{coll3}, cdRWSet, lsccFunc, ac, chid)
 	assert.EqualError(t, err, "collection-name: mycollection3 -- error in member org policy: signature policy is not an OR concatenation, NOutOf 2")
 
+	// Test 12a: purge policy without a signature policy -> error
+	coll3 = createCollectionConfig(collName3, cauthdsl.SignedByAnyMember([]string{"SampleOrg"}), requiredPeerCount, maximumPeerCount, blockToLive)
+	coll3.GetStaticCollectionConfig().PurgePolicy = &common.CollectionPolicyConfig{}
+	err = testValidateCollection(t, v, []*common.CollectionConfig{coll3}, cdRWSet, lsccFunc, ac, chid)
+	assert.EqualError(t, err, "collection-name: mycollection3 -- purge policy must be a signature policy")
+
+	// Test 12b: purge policy with an AND concatenation of orgs -> success
+	coll3.GetStaticCollectionConfig().PurgePolicy = &common.CollectionPolicyConfig{
+		Payload: &common.CollectionPolicyConfig_SignaturePolicy{SignaturePolicy: policyEnvelope},
+	}
+	err = testValidateCollection(t, v, []*common.CollectionConfig{coll3}, cdRWSet, lsccFunc, ac, chid)
+	assert.NoError(t, err)
+
 	// Test 13: deploy with existing collection config on the ledger -> error
 	ccp := &common.CollectionConfigPackage{Config: []*common.CollectionConfig{coll1}}
 	ccpBytes, err := proto.Marshal(ccp)
@@ -2038,3 +2051,85 @@ func TestInValidCollectionName(t *testing.T) {
 		assert.Error(t, validateCollectionName(name), "Testing for name = "+name)
 	}
 }
+
+func createPurgeTx(ccname, coll, key string) (*common.Envelope, error) {
+	ccid := &peer.ChaincodeID{Name: ccname, Version: "v1"}
+	cis := &peer.ChaincodeInvocationSpec{ChaincodeSpec: &peer.ChaincodeSpec{ChaincodeId: ccid}}
+
+	prop, _, err := utils.CreateProposalFromCIS(common.HeaderType_ENDORSER_TRANSACTION, util.GetTestChainID(), cis, sid)
+	if err != nil {
+		return nil, err
+	}
+
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder.AddToPvtAndHashedWriteSetForPurge(ccname, coll, key)
+	simRes, err := rwsetBuilder.GetTxSimulationResults()
+	if err != nil {
+		return nil, err
+	}
+	res, err := simRes.GetPubSimulationBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	presp, err := utils.CreateProposalResponse(prop.Header, prop.Payload, &peer.Response{Status: 200}, res, nil, ccid, nil, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.CreateSignedTx(prop, id, presp)
+}
+
+func TestValidatePurges(t *testing.T) {
+	tx, err := createPurgeTx("foo", "coll", "key")
+	assert.NoError(t, err)
+	envBytes, err := utils.GetBytesEnvelope(tx)
+	assert.NoError(t, err)
+	b := &common.Block{Data: &common.BlockData{Data: [][]byte{envBytes}}, Header: &common.BlockHeader{}}
+
+	policy, err := getSignedByMSPMemberPolicy(mspid)
+	assert.NoError(t, err)
+
+	collConfigState := func(collConfigs ...*common.CollectionConfig) map[string]map[string][]byte {
+		ccpBytes, err := proto.Marshal(&common.CollectionConfigPackage{Config: collConfigs})
+		assert.NoError(t, err)
+		return map[string]map[string][]byte{"lscc": {privdata.BuildCollectionKVSKey("foo"): ccpBytes}}
+	}
+	withPurgePolicy := func(collConfig *common.CollectionConfig, purgePolicy *common.SignaturePolicyEnvelope) *common.CollectionConfig {
+		collConfig.GetStaticCollectionConfig().PurgePolicy = &common.CollectionPolicyConfig{
+			Payload: &common.CollectionPolicyConfig_SignaturePolicy{SignaturePolicy: purgePolicy},
+		}
+		return collConfig
+	}
+	memberPolicy := cauthdsl.SignedByMspMember(mspid)
+
+	// bad path: no collection config for the chaincode
+	v := newValidationInstance(map[string]map[string][]byte{"lscc": {}})
+	err = v.Validate(b, "foo", 0, 0, policy)
+	assert.Error(t, err)
+	assert.IsType(t, &commonerrors.VSCCEndorsementPolicyError{}, err)
+
+	// bad path: the purged collection is not defined
+	v = newValidationInstance(collConfigState(withPurgePolicy(createCollectionConfig("othercoll", memberPolicy, 0, 1, 0), memberPolicy)))
+	err = v.Validate(b, "foo", 0, 0, policy)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "collection not defined")
+
+	// bad path: the collection does not define a purge policy
+	v = newValidationInstance(collConfigState(createCollectionConfig("coll", memberPolicy, 0, 1, 0)))
+	err = v.Validate(b, "foo", 0, 0, policy)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no purge policy defined")
+
+	// bad path: the endorsements do not satisfy the purge policy
+	v = newValidationInstance(collConfigState(withPurgePolicy(createCollectionConfig("coll", memberPolicy, 0, 1, 0), cauthdsl.SignedByMspMember("barf"))))
+	err = v.Validate(b, "foo", 0, 0, policy)
+	assert.Error(t, err)
+	assert.IsType(t, &commonerrors.VSCCEndorsementPolicyError{}, err)
+	assert.Contains(t, err.Error(), "purge policy of collection coll of chaincode foo violated")
+
+	// good path: the endorsements satisfy the purge policy
+	v = newValidationInstance(collConfigState(withPurgePolicy(createCollectionConfig("coll", memberPolicy, 0, 1, 0), memberPolicy)))
+	err = v.Validate(b, "foo", 0, 0, policy)
+	assert.NoError(t, err)
+}
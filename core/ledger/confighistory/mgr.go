@@ -29,6 +29,7 @@ const (
 type Mgr interface {
 	ledger.StateListener
 	GetRetriever(ledgerID string, ledgerInfoRetriever LedgerInfoRetriever) ledger.ConfigHistoryRetriever
+	Drop(ledgerID string) error
 	Close()
 }
 
@@ -70,6 +71,11 @@ func (m *mgr) GetRetriever(ledgerID string, ledgerInfoRetriever LedgerInfoRetrie
 	return &retriever{dbHandle: m.dbProvider.getDB(ledgerID), ledgerInfoRetriever: ledgerInfoRetriever}
 }
 
+// Drop implements the function in the interface 'Mgr'. It deletes the config history of the given ledger
+func (m *mgr) Drop(ledgerID string) error {
+	return m.dbProvider.getDB(ledgerID).DeleteAll()
+}
+
 // Close implements the function in the interface 'Mgr'
 func (m *mgr) Close() {
 	m.dbProvider.Close()
@@ -96,6 +96,19 @@ func TestMgr(t *testing.T) {
 		assert.True(t, ok)
 		assert.Equal(t, maxBlockNumberInLedger, typedErr.MaxBlockNumCommitted)
 	})
+
+	t.Run("test-api-Drop()", func(t *testing.T) {
+		assert.NoError(t, mgr.Drop("ledgerid1"))
+		retrievedConfig, err := mgr.GetRetriever("ledgerid1", dummyLedgerInfoRetriever).
+			MostRecentCollectionConfigBelow(math.MaxUint64, chaincodeName)
+		assert.NoError(t, err)
+		assert.Nil(t, retrievedConfig)
+
+		retrievedConfig, err = mgr.GetRetriever("ledger2", dummyLedgerInfoRetriever).
+			MostRecentCollectionConfigBelow(math.MaxUint64, chaincodeName)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(100), retrievedConfig.CommittingBlockNum)
+	})
 }
 
 type testEnv struct {
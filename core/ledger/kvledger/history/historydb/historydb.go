@@ -27,6 +27,8 @@ import (
 type HistoryDBProvider interface {
 	// GetDBHandle returns a handle to a HistoryDB
 	GetDBHandle(id string) (HistoryDB, error)
+	// Drop deletes the HistoryDB with the given id
+	Drop(id string) error
 	// Close closes all the HistoryDB instances and releases any resources held by HistoryDBProvider
 	Close()
 }
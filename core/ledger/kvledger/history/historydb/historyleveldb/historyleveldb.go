@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package historyleveldb
 
 import (
+	"github.com/golang/protobuf/proto"
 	"justledger/common/flogging"
 	"justledger/common/ledger/blkstorage"
 	"justledger/common/ledger/util/leveldbhelper"
@@ -17,13 +18,13 @@ import (
 	"justledger/core/ledger/ledgerconfig"
 	"justledger/core/ledger/util"
 	"justledger/protos/common"
+	"justledger/protos/ledger/queryresult"
 	putils "justledger/protos/utils"
 )
 
 var logger = flogging.MustGetLogger("historyleveldb")
 
 var savePointKey = []byte{0x00}
-var emptyValue = []byte{}
 
 // HistoryDBProvider implements interface HistoryDBProvider
 type HistoryDBProvider struct {
@@ -43,6 +44,11 @@ func (provider *HistoryDBProvider) GetDBHandle(dbName string) (historydb.History
 	return newHistoryDB(provider.dbProvider.GetDBHandle(dbName), dbName), nil
 }
 
+// Drop deletes the history of the named database
+func (provider *HistoryDBProvider) Drop(dbName string) error {
+	return provider.dbProvider.GetDBHandle(dbName).DeleteAll()
+}
+
 // Close closes the underlying db
 func (provider *HistoryDBProvider) Close() {
 	provider.dbProvider.Close()
@@ -138,8 +144,19 @@ func (historyDB *historyDB) Commit(block *common.Block) error {
 					//composite key for history records is in the form ns~key~blockNo~tranNo
 					compositeHistoryKey := historydb.ConstructCompositeHistoryKey(ns, writeKey, blockNo, tranNo)
 
-					// No value is required, write an empty byte array (emptyValue) since Put() of nil is not allowed
-					dbBatch.Put(compositeHistoryKey, emptyValue)
+					// the history record holds the modification so that the history queries do not need to
+					// retrieve the transaction from the block store
+					keyModificationBytes, err := proto.Marshal(&queryresult.KeyModification{
+						TxId:      chdr.TxId,
+						Value:     kvWrite.Value,
+						Timestamp: chdr.Timestamp,
+						IsDelete:  kvWrite.IsDelete,
+						Key:       writeKey,
+					})
+					if err != nil {
+						return err
+					}
+					dbBatch.Put(compositeHistoryKey, keyModificationBytes)
 				}
 			}
 
@@ -8,10 +8,15 @@ package historyleveldb
 
 import (
 	"bytes"
+	"encoding/hex"
+	"math"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	commonledger "justledger/common/ledger"
 	"justledger/common/ledger/blkstorage"
 	"justledger/common/ledger/util"
+	"justledger/core/ledger"
 	"justledger/core/ledger/kvledger/history/historydb"
 	"justledger/core/ledger/kvledger/txmgmt/rwsetutil"
 	"justledger/core/ledger/ledgerconfig"
@@ -30,91 +35,313 @@ type LevelHistoryDBQueryExecutor struct {
 
 // GetHistoryForKey implements method in interface `ledger.HistoryQueryExecutor`
 func (q *LevelHistoryDBQueryExecutor) GetHistoryForKey(namespace string, key string) (commonledger.ResultsIterator, error) {
+	return q.GetHistoryForKeyWithOptions(namespace, key, nil)
+}
+
+// GetHistoryForKeyWithOptions implements method in interface `ledger.HistoryQueryExecutor`
+func (q *LevelHistoryDBQueryExecutor) GetHistoryForKeyWithOptions(namespace string, key string,
+	options *ledger.HistoryQueryOptions) (commonledger.ResultsIterator, error) {
 
 	if ledgerconfig.IsHistoryDBEnabled() == false {
 		return nil, errors.New("history database not enabled")
 	}
+	if options == nil {
+		options = &ledger.HistoryQueryOptions{}
+	}
+	if err := validateHistoryQueryOptions(options); err != nil {
+		return nil, err
+	}
 
-	var compositeStartKey []byte
+	compositePartialKey := historydb.ConstructPartialCompositeHistoryKey(namespace, key, false)
+	// the history records are ordered by height so that the block range is a range of the history keys
+	compositeStartKey := append(historydb.ConstructPartialCompositeHistoryKey(namespace, key, false),
+		util.EncodeOrderPreservingVarUint64(options.StartBlock)...)
 	var compositeEndKey []byte
-	compositeStartKey = historydb.ConstructPartialCompositeHistoryKey(namespace, key, false)
-	compositeEndKey = historydb.ConstructPartialCompositeHistoryKey(namespace, key, true)
+	if options.EndBlock == 0 || options.EndBlock == math.MaxUint64 {
+		compositeEndKey = historydb.ConstructPartialCompositeHistoryKey(namespace, key, true)
+	} else {
+		compositeEndKey = append(historydb.ConstructPartialCompositeHistoryKey(namespace, key, false),
+			util.EncodeOrderPreservingVarUint64(options.EndBlock+1)...)
+	}
 
 	// range scan to find any history records starting with namespace~key
 	dbItr := q.historyDB.db.GetIterator(compositeStartKey, compositeEndKey)
-	return newHistoryScanner(compositeStartKey, namespace, key, dbItr, q.blockStore), nil
+	return newHistoryScanner(compositePartialKey, namespace, key, dbItr, q.blockStore, options), nil
+}
+
+// GetHistoryForKeysByPrefix implements method in interface `ledger.HistoryQueryExecutor`
+func (q *LevelHistoryDBQueryExecutor) GetHistoryForKeysByPrefix(namespace string, keyPrefix string,
+	options *ledger.HistoryQueryOptions) (commonledger.QueryResultsIterator, error) {
+
+	if ledgerconfig.IsHistoryDBEnabled() == false {
+		return nil, errors.New("history database not enabled")
+	}
+	if options == nil {
+		options = &ledger.HistoryQueryOptions{}
+	}
+	if err := validateHistoryQueryOptions(options); err != nil {
+		return nil, err
+	}
+
+	// the history keys of the keys with the prefix are in the form namespace~keyPrefix...~blocknum~trannum.
+	// As a key is a valid UTF-8 string, it does not contain the byte 0xff
+	compositeStartKey := append([]byte(namespace), historydb.CompositeKeySep...)
+	compositeStartKey = append(compositeStartKey, []byte(keyPrefix)...)
+	compositeEndKey := append(append([]byte{}, compositeStartKey...), 0xff)
+	if options.Bookmark != "" {
+		// the bookmark is the history key of the first result to retrieve, without the namespace
+		bookmarkKey, err := hex.DecodeString(options.Bookmark)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid bookmark [%s]", options.Bookmark)
+		}
+		bookmarkKey = append(append([]byte(namespace), historydb.CompositeKeySep...), bookmarkKey...)
+		if bytes.Compare(bookmarkKey, compositeStartKey) < 0 || bytes.Compare(bookmarkKey, compositeEndKey) >= 0 {
+			return nil, errors.Errorf("bookmark [%s] is out of the range of the keys with prefix [%s]", options.Bookmark, keyPrefix)
+		}
+		if options.NewestFirst {
+			compositeEndKey = append(bookmarkKey, 0x00)
+		} else {
+			compositeStartKey = bookmarkKey
+		}
+	}
+
+	dbItr := q.historyDB.db.GetIterator(compositeStartKey, compositeEndKey)
+	return newPrefixHistoryScanner(namespace, keyPrefix, dbItr, q.blockStore, options), nil
+}
+
+func validateHistoryQueryOptions(options *ledger.HistoryQueryOptions) error {
+	if options.EndBlock != 0 && options.EndBlock < options.StartBlock {
+		return errors.Errorf("invalid block range: end block [%d] is lower than start block [%d]",
+			options.EndBlock, options.StartBlock)
+	}
+	if !options.StartTime.IsZero() && !options.EndTime.IsZero() && !options.StartTime.Before(options.EndTime) {
+		return errors.Errorf("invalid time range: end time [%s] is not after start time [%s]",
+			options.EndTime, options.StartTime)
+	}
+	return nil
 }
 
 //historyScanner implements ResultsIterator for iterating through history results
 type historyScanner struct {
 	compositePartialKey []byte //compositePartialKey includes namespace~key
 	namespace           string
-	key                 string
+	key                 string // the key, or the key prefix if keyPrefix is true
+	keyPrefix           bool
 	dbItr               iterator.Iterator
 	blockStore          blkstorage.BlockStore
+	options             *ledger.HistoryQueryOptions
+	started             bool
 }
 
 func newHistoryScanner(compositePartialKey []byte, namespace string, key string,
-	dbItr iterator.Iterator, blockStore blkstorage.BlockStore) *historyScanner {
-	return &historyScanner{compositePartialKey, namespace, key, dbItr, blockStore}
+	dbItr iterator.Iterator, blockStore blkstorage.BlockStore, options *ledger.HistoryQueryOptions) *historyScanner {
+	return &historyScanner{compositePartialKey: compositePartialKey, namespace: namespace, key: key,
+		dbItr: dbItr, blockStore: blockStore, options: options}
+}
+
+func newPrefixHistoryScanner(namespace string, keyPrefix string,
+	dbItr iterator.Iterator, blockStore blkstorage.BlockStore, options *ledger.HistoryQueryOptions) *historyScanner {
+	return &historyScanner{compositePartialKey: append([]byte(namespace), historydb.CompositeKeySep...),
+		namespace: namespace, key: keyPrefix, keyPrefix: true, dbItr: dbItr, blockStore: blockStore, options: options}
 }
 
 func (scanner *historyScanner) Next() (commonledger.QueryResult, error) {
 	for {
-		if !scanner.dbItr.Next() {
+		if !scanner.moveToNextRecord() {
 			return nil, nil
 		}
 		historyKey := scanner.dbItr.Key() // history key is in the form namespace~key~blocknum~trannum
 
-		// SplitCompositeKey(namespace~key~blocknum~trannum, namespace~key~) will return the blocknum~trannum in second position
-		_, blockNumTranNumBytes := historydb.SplitCompositeHistoryKey(historyKey, scanner.compositePartialKey)
-
-		// check that blockNumTranNumBytes does not contain a nil byte (FAB-11244) - except the last byte.
-		// if this contains a nil byte that indicate that its a different key other than the one we are
-		// scanning the history for. However, the last byte can be nil even for the valid key (indicating the transaction numer being zero)
-		// This is because, if 'blockNumTranNumBytes' really is the suffix of the desired key - only possibility of this containing a nil byte
-		// is the last byte when the transaction number in blockNumTranNumBytes is zero).
-		// On the other hand, if 'blockNumTranNumBytes' really is NOT the suffix of the desired key, then this has to be a prefix
-		// of some other key (other than the desired key) and in this case, there has to be at least one nil byte (other than the last byte),
-		// for the 'last' CompositeKeySep in the composite key
-		// Take an example of two keys "key" and "key\x00" in a namespace ns. The entries for these keys will be
-		// of type "ns-\x00-key-\x00-blkNumTranNumBytes" and ns-\x00-key-\x00-\x00-blkNumTranNumBytes respectively.
-		// "-" in above examples are just for readability. Further, when scanning the range
-		// {ns-\x00-key-\x00 - ns-\x00-key-xff} for getting the history for <ns, key>, the entry for the other key
-		// falls in the range and needs to be ignored
-		if bytes.Contains(blockNumTranNumBytes[:len(blockNumTranNumBytes)-1], historydb.CompositeKeySep) {
-			logger.Debugf("Some other key [%#v] found in the range while scanning history for key [%#v]. Skipping...",
-				historyKey, scanner.key)
-			continue
+		var queryResult *queryresult.KeyModification
+		var blockNum uint64
+		var err error
+		if scanner.keyPrefix {
+			queryResult, blockNum, err = scanner.keyModificationOfPrefixRecord(historyKey, scanner.dbItr.Value())
+		} else {
+			queryResult, blockNum, err = scanner.keyModificationOfKeyRecord(historyKey, scanner.dbItr.Value())
 		}
-		blockNum, bytesConsumed := util.DecodeOrderPreservingVarUint64(blockNumTranNumBytes[0:])
-		tranNum, _ := util.DecodeOrderPreservingVarUint64(blockNumTranNumBytes[bytesConsumed:])
-		logger.Debugf("Found history record for namespace:%s key:%s at blockNumTranNum %v:%v\n",
-			scanner.namespace, scanner.key, blockNum, tranNum)
-
-		// Get the transaction from block storage that is associated with this history record
-		tranEnvelope, err := scanner.blockStore.RetrieveTxByBlockNumTranNum(blockNum, tranNum)
 		if err != nil {
 			return nil, err
 		}
+		if queryResult == nil || !scanner.inRange(blockNum, queryResult) {
+			continue
+		}
+		logger.Debugf("Found historic key value for namespace:%s key:%s from transaction %s\n",
+			scanner.namespace, queryResult.Key, queryResult.TxId)
+		return queryResult, nil
+	}
+}
+
+// moveToNextRecord moves the iterator to the next history record in the requested order
+func (scanner *historyScanner) moveToNextRecord() bool {
+	if !scanner.options.NewestFirst {
+		return scanner.dbItr.Next()
+	}
+	if !scanner.started {
+		scanner.started = true
+		return scanner.dbItr.Last()
+	}
+	return scanner.dbItr.Prev()
+}
+
+// keyModificationOfKeyRecord returns the modification of the scanned key held by the history record
+// along with the block number of the modification, or nil if the record belongs to another key
+func (scanner *historyScanner) keyModificationOfKeyRecord(historyKey, value []byte) (*queryresult.KeyModification, uint64, error) {
+	// SplitCompositeKey(namespace~key~blocknum~trannum, namespace~key~) will return the blocknum~trannum in second position
+	_, blockNumTranNumBytes := historydb.SplitCompositeHistoryKey(historyKey, scanner.compositePartialKey)
+
+	// check that blockNumTranNumBytes does not contain a nil byte (FAB-11244) - except the last byte.
+	// if this contains a nil byte that indicate that its a different key other than the one we are
+	// scanning the history for. However, the last byte can be nil even for the valid key (indicating the transaction numer being zero)
+	// This is because, if 'blockNumTranNumBytes' really is the suffix of the desired key - only possibility of this containing a nil byte
+	// is the last byte when the transaction number in blockNumTranNumBytes is zero).
+	// On the other hand, if 'blockNumTranNumBytes' really is NOT the suffix of the desired key, then this has to be a prefix
+	// of some other key (other than the desired key) and in this case, there has to be at least one nil byte (other than the last byte),
+	// for the 'last' CompositeKeySep in the composite key
+	// Take an example of two keys "key" and "key\x00" in a namespace ns. The entries for these keys will be
+	// of type "ns-\x00-key-\x00-blkNumTranNumBytes" and ns-\x00-key-\x00-\x00-blkNumTranNumBytes respectively.
+	// "-" in above examples are just for readability. Further, when scanning the range
+	// {ns-\x00-key-\x00 - ns-\x00-key-xff} for getting the history for <ns, key>, the entry for the other key
+	// falls in the range and needs to be ignored
+	if bytes.Contains(blockNumTranNumBytes[:len(blockNumTranNumBytes)-1], historydb.CompositeKeySep) {
+		logger.Debugf("Some other key [%#v] found in the range while scanning history for key [%#v]. Skipping...",
+			historyKey, scanner.key)
+		return nil, 0, nil
+	}
+	blockNum, bytesConsumed := util.DecodeOrderPreservingVarUint64(blockNumTranNumBytes[0:])
+	tranNum, _ := util.DecodeOrderPreservingVarUint64(blockNumTranNumBytes[bytesConsumed:])
+	logger.Debugf("Found history record for namespace:%s key:%s at blockNumTranNum %v:%v\n",
+		scanner.namespace, scanner.key, blockNum, tranNum)
+
+	queryResult, err := scanner.keyModification(value, scanner.key, blockNum, tranNum)
+	if err != nil {
+		return nil, 0, err
+	}
+	return queryResult, blockNum, nil
+}
+
+// keyModificationOfPrefixRecord returns the modification held by the history record of a key with the scanned
+// prefix along with the block number of the modification
+func (scanner *historyScanner) keyModificationOfPrefixRecord(historyKey, value []byte) (*queryresult.KeyModification, uint64, error) {
+	keyAndHeight := historyKey[len(scanner.compositePartialKey):]
+	if len(value) > 0 {
+		queryResult := &queryresult.KeyModification{}
+		if err := proto.Unmarshal(value, queryResult); err != nil {
+			return nil, 0, errors.Wrap(err, "error unmarshaling the history record")
+		}
+		blockNum, _, ok := decodeHeight(keyAndHeight[len(queryResult.Key)+len(historydb.CompositeKeySep):])
+		if !ok {
+			return nil, 0, errors.Errorf("invalid history key [%#v]", historyKey)
+		}
+		return queryResult, blockNum, nil
+	}
 
-		// Get the txid, key write value, timestamp, and delete indicator associated with this transaction
-		queryResult, err := getKeyModificationFromTran(tranEnvelope, scanner.namespace, scanner.key)
+	// the records written by the previous releases do not hold the key, which may contain the separator, so the
+	// key is the one of the possible splits of the history key which is written by the transaction
+	for i := len(scanner.key); i < len(keyAndHeight); i++ {
+		if keyAndHeight[i] != historydb.CompositeKeySep[0] {
+			continue
+		}
+		blockNum, tranNum, ok := decodeHeight(keyAndHeight[i+1:])
+		if !ok {
+			continue
+		}
+		queryResult, err := scanner.keyModification(value, string(keyAndHeight[:i]), blockNum, tranNum)
+		if err == errKeyNotWritten {
+			continue
+		}
 		if err != nil {
-			return nil, err
+			return nil, 0, err
+		}
+		return queryResult, blockNum, nil
+	}
+	return nil, 0, errors.Errorf("invalid history key [%#v]", historyKey)
+}
+
+// keyModification returns the modification of the key held by the value of a history record or, if the record
+// was written by a previous release and holds no value, the modification written by the transaction
+func (scanner *historyScanner) keyModification(value []byte, key string, blockNum, tranNum uint64) (*queryresult.KeyModification, error) {
+	queryResult := &queryresult.KeyModification{}
+	if len(value) > 0 {
+		if err := proto.Unmarshal(value, queryResult); err != nil {
+			return nil, errors.Wrap(err, "error unmarshaling the history record")
 		}
-		logger.Debugf("Found historic key value for namespace:%s key:%s from transaction %s\n",
-			scanner.namespace, scanner.key, queryResult.(*queryresult.KeyModification).TxId)
 		return queryResult, nil
 	}
+
+	// Get the transaction from block storage that is associated with this history record
+	tranEnvelope, err := scanner.blockStore.RetrieveTxByBlockNumTranNum(blockNum, tranNum)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the txid, key write value, timestamp, and delete indicator associated with this transaction
+	queryResult, err = getKeyModificationFromTran(tranEnvelope, scanner.namespace, key)
+	if err != nil {
+		return nil, err
+	}
+	queryResult.Key = key
+	return queryResult, nil
+}
+
+// inRange returns true if the modification satisfies the block and time ranges of the options
+func (scanner *historyScanner) inRange(blockNum uint64, queryResult *queryresult.KeyModification) bool {
+	options := scanner.options
+	if blockNum < options.StartBlock || (options.EndBlock != 0 && blockNum > options.EndBlock) {
+		return false
+	}
+	if options.StartTime.IsZero() && options.EndTime.IsZero() {
+		return true
+	}
+	timestamp, err := ptypes.Timestamp(queryResult.Timestamp)
+	if err != nil {
+		return false
+	}
+	if !options.StartTime.IsZero() && timestamp.Before(options.StartTime) {
+		return false
+	}
+	return options.EndTime.IsZero() || timestamp.Before(options.EndTime)
 }
 
 func (scanner *historyScanner) Close() {
 	scanner.dbItr.Release()
 }
 
+// GetBookmarkAndClose returns the bookmark of the next result, or an empty bookmark if there is none, and
+// releases the iterator
+func (scanner *historyScanner) GetBookmarkAndClose() string {
+	defer scanner.Close()
+	result, err := scanner.Next()
+	if err != nil || result == nil {
+		return ""
+	}
+	return hex.EncodeToString(scanner.dbItr.Key()[len(scanner.compositePartialKey):])
+}
+
+// decodeHeight decodes the block number and the transaction number which end a history key and
+// returns false if the bytes are not an encoded block number followed by an encoded transaction number
+func decodeHeight(blockNumTranNumBytes []byte) (uint64, uint64, bool) {
+	var numbers []uint64
+	remaining := blockNumTranNumBytes
+	for i := 0; i < 2; i++ {
+		// the order preserving encoding is the number of bytes, at most 8, followed by the bytes
+		if len(remaining) == 0 || remaining[0] > 8 || len(remaining) < int(remaining[0])+1 {
+			return 0, 0, false
+		}
+		number, bytesConsumed := util.DecodeOrderPreservingVarUint64(remaining)
+		numbers = append(numbers, number)
+		remaining = remaining[bytesConsumed:]
+	}
+	if len(remaining) != 0 {
+		return 0, 0, false
+	}
+	return numbers[0], numbers[1], true
+}
+
+var errKeyNotWritten = errors.New("key not found in namespace's writeset")
+
 // getTxIDandKeyWriteValueFromTran inspects a transaction for writes to a given key
-func getKeyModificationFromTran(tranEnvelope *common.Envelope, namespace string, key string) (commonledger.QueryResult, error) {
+func getKeyModificationFromTran(tranEnvelope *common.Envelope, namespace string, key string) (*queryresult.KeyModification, error) {
 	logger.Debugf("Entering getKeyModificationFromTran()\n", namespace, key)
 
 	// extract action from the envelope
@@ -159,7 +386,7 @@ func getKeyModificationFromTran(tranEnvelope *common.Envelope, namespace string,
 						Timestamp: timestamp, IsDelete: kvWrite.IsDelete}, nil
 				}
 			} // end keys loop
-			return nil, errors.New("key not found in namespace's writeset")
+			return nil, errKeyNotWritten
 		} // end if
 	} //end namespaces loop
 	return nil, errors.New("namespace not found in transaction's ReadWriteSets")
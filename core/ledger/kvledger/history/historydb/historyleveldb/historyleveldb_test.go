@@ -11,8 +11,11 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/golang/protobuf/ptypes"
 	configtxtest "justledger/common/configtx/test"
 	"justledger/common/flogging"
+	commonledger "justledger/common/ledger"
+	"justledger/common/ledger/blkstorage"
 	"justledger/common/ledger/testutil"
 	util2 "justledger/common/util"
 	"justledger/core/ledger"
@@ -95,6 +98,25 @@ func TestSavepoint(t *testing.T) {
 	assert.Equal(t, uint64(3), blockNum)
 }
 
+func TestDrop(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+	otherHistoryDB, err := env.testHistoryDBProvider.GetDBHandle("TestOtherHistoryDB")
+	assert.NoError(t, err)
+
+	_, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	assert.NoError(t, env.testHistoryDB.Commit(gb))
+	assert.NoError(t, otherHistoryDB.Commit(gb))
+
+	assert.NoError(t, env.testHistoryDBProvider.Drop("TestHistoryDB"))
+	savepoint, err := env.testHistoryDB.GetLastSavepoint()
+	assert.NoError(t, err)
+	assert.Nil(t, savepoint)
+	savepoint, err = otherHistoryDB.GetLastSavepoint()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), savepoint.BlockNum)
+}
+
 func TestHistory(t *testing.T) {
 	env := newTestHistoryEnv(t)
 	defer env.cleanup()
@@ -338,3 +360,204 @@ func testutilVerifyResults(t *testing.T, hqe ledger.HistoryQueryExecutor, ns, ke
 	}
 	assert.Equal(t, expectedVals, retrievedVals)
 }
+
+func TestHistoryWithOptions(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+	store1, err := env.testBlockStorageEnv.provider.OpenBlockStore("ledger1")
+	assert.NoError(t, err, "Error upon provider.OpenBlockStore()")
+	defer store1.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, "ledger1", false)
+	assert.NoError(t, store1.AddBlock(gb))
+	assert.NoError(t, env.testHistoryDB.Commit(gb))
+	commitBlockWithWrites(t, env, store1, bg, map[string]string{"key7": "value1"})
+	commitBlockWithWrites(t, env, store1, bg, map[string]string{"key7": "value2"}, map[string]string{"key7": "value3"})
+	commitBlockWithWrites(t, env, store1, bg, map[string]string{"key7": ""})
+	commitBlockWithWrites(t, env, store1, bg, map[string]string{"key7": "value5"})
+
+	qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(store1)
+	assert.NoError(t, err, "Error upon NewHistoryQueryExecutor")
+
+	kmods := retrieveHistory(t, qhistory, "ns1", "key7", false, &ledger.HistoryQueryOptions{})
+	assert.Equal(t, []string{"key7:value1", "key7:value2", "key7:value3", "key7:<deleted>", "key7:value5"}, historyEntries(kmods))
+
+	kmods = retrieveHistory(t, qhistory, "ns1", "key7", false, &ledger.HistoryQueryOptions{StartBlock: 2, EndBlock: 3})
+	assert.Equal(t, []string{"key7:value2", "key7:value3", "key7:<deleted>"}, historyEntries(kmods))
+
+	kmods = retrieveHistory(t, qhistory, "ns1", "key7", false, &ledger.HistoryQueryOptions{StartBlock: 3})
+	assert.Equal(t, []string{"key7:<deleted>", "key7:value5"}, historyEntries(kmods))
+
+	kmods = retrieveHistory(t, qhistory, "ns1", "key7", false, &ledger.HistoryQueryOptions{NewestFirst: true})
+	assert.Equal(t, []string{"key7:value5", "key7:<deleted>", "key7:value3", "key7:value2", "key7:value1"}, historyEntries(kmods))
+
+	kmods = retrieveHistory(t, qhistory, "ns1", "key7", false, &ledger.HistoryQueryOptions{EndBlock: 2, NewestFirst: true})
+	assert.Equal(t, []string{"key7:value3", "key7:value2", "key7:value1"}, historyEntries(kmods))
+
+	// the time range includes its start and excludes its end
+	kmods = retrieveHistory(t, qhistory, "ns1", "key7", false, &ledger.HistoryQueryOptions{})
+	deleteTime, err := ptypes.Timestamp(kmods[3].Timestamp)
+	assert.NoError(t, err)
+	kmods = retrieveHistory(t, qhistory, "ns1", "key7", false, &ledger.HistoryQueryOptions{StartTime: deleteTime})
+	assert.Equal(t, []string{"key7:<deleted>", "key7:value5"}, historyEntries(kmods))
+	kmods = retrieveHistory(t, qhistory, "ns1", "key7", false, &ledger.HistoryQueryOptions{EndTime: deleteTime, NewestFirst: true})
+	assert.Equal(t, []string{"key7:value3", "key7:value2", "key7:value1"}, historyEntries(kmods))
+
+	_, err = qhistory.GetHistoryForKeyWithOptions("ns1", "key7", &ledger.HistoryQueryOptions{StartBlock: 3, EndBlock: 2})
+	assert.EqualError(t, err, "invalid block range: end block [2] is lower than start block [3]")
+	_, err = qhistory.GetHistoryForKeysByPrefix("ns1", "key", &ledger.HistoryQueryOptions{StartTime: deleteTime, EndTime: deleteTime})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid time range")
+}
+
+func TestHistoryForKeysByPrefix(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+	store1, err := env.testBlockStorageEnv.provider.OpenBlockStore("ledger1")
+	assert.NoError(t, err, "Error upon provider.OpenBlockStore()")
+	defer store1.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, "ledger1", false)
+	assert.NoError(t, store1.AddBlock(gb))
+	assert.NoError(t, env.testHistoryDB.Commit(gb))
+	// composite keys of the object types asset and other
+	assetA, assetAX, assetB, otherA := "\x00asset\x00a\x00", "\x00asset\x00a\x00x\x00", "\x00asset\x00b\x00", "\x00other\x00a\x00"
+	commitBlockWithWrites(t, env, store1, bg, map[string]string{assetA: "a1", assetB: "b1", otherA: "o1"})
+	commitBlockWithWrites(t, env, store1, bg, map[string]string{assetAX: "ax1"}, map[string]string{assetA: "a2"})
+	commitBlockWithWrites(t, env, store1, bg, map[string]string{assetB: ""})
+
+	qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(store1)
+	assert.NoError(t, err, "Error upon NewHistoryQueryExecutor")
+
+	verifyPrefixHistory := func() {
+		kmods := retrieveHistory(t, qhistory, "ns1", "\x00asset\x00", true, nil)
+		assert.Equal(t, []string{assetA + ":a1", assetA + ":a2", assetAX + ":ax1", assetB + ":b1", assetB + ":<deleted>"},
+			historyEntries(kmods))
+
+		kmods = retrieveHistory(t, qhistory, "ns1", "\x00asset\x00a\x00", true, nil)
+		assert.Equal(t, []string{assetA + ":a1", assetA + ":a2", assetAX + ":ax1"}, historyEntries(kmods))
+
+		kmods = retrieveHistory(t, qhistory, "ns1", "\x00asset\x00", true, &ledger.HistoryQueryOptions{NewestFirst: true})
+		assert.Equal(t, []string{assetB + ":<deleted>", assetB + ":b1", assetAX + ":ax1", assetA + ":a2", assetA + ":a1"},
+			historyEntries(kmods))
+
+		kmods = retrieveHistory(t, qhistory, "ns1", "\x00asset\x00", true, &ledger.HistoryQueryOptions{StartBlock: 2})
+		assert.Equal(t, []string{assetA + ":a2", assetAX + ":ax1", assetB + ":<deleted>"}, historyEntries(kmods))
+
+		kmods = retrieveHistory(t, qhistory, "ns1", assetA, false, nil)
+		assert.Equal(t, []string{assetA + ":a1", assetA + ":a2"}, historyEntries(kmods))
+
+		kmods = retrieveHistory(t, qhistory, "ns2", "\x00asset\x00", true, nil)
+		assert.Empty(t, kmods)
+
+		// the history is retrieved from the bookmark of the next result
+		for _, newestFirst := range []bool{false, true} {
+			itr, err := qhistory.GetHistoryForKeysByPrefix("ns1", "\x00asset\x00", &ledger.HistoryQueryOptions{NewestFirst: newestFirst})
+			assert.NoError(t, err)
+			for i := 0; i < 2; i++ {
+				kmod, err := itr.Next()
+				assert.NoError(t, err)
+				assert.NotNil(t, kmod)
+			}
+			bookmark := itr.GetBookmarkAndClose()
+			assert.NotEmpty(t, bookmark)
+			kmods = retrieveHistory(t, qhistory, "ns1", "\x00asset\x00", true,
+				&ledger.HistoryQueryOptions{NewestFirst: newestFirst, Bookmark: bookmark})
+			if newestFirst {
+				assert.Equal(t, []string{assetAX + ":ax1", assetA + ":a2", assetA + ":a1"}, historyEntries(kmods))
+			} else {
+				assert.Equal(t, []string{assetAX + ":ax1", assetB + ":b1", assetB + ":<deleted>"}, historyEntries(kmods))
+			}
+		}
+
+		// there is no bookmark past the last result
+		itr, err := qhistory.GetHistoryForKeysByPrefix("ns1", "\x00asset\x00b", nil)
+		assert.NoError(t, err)
+		for i := 0; i < 2; i++ {
+			kmod, err := itr.Next()
+			assert.NoError(t, err)
+			assert.NotNil(t, kmod)
+		}
+		assert.Empty(t, itr.GetBookmarkAndClose())
+
+		_, err = qhistory.GetHistoryForKeysByPrefix("ns1", "\x00asset\x00", &ledger.HistoryQueryOptions{Bookmark: "bogus"})
+		assert.Contains(t, err.Error(), "invalid bookmark [bogus]")
+		_, err = qhistory.GetHistoryForKeysByPrefix("ns1", "\x00asset\x00", &ledger.HistoryQueryOptions{Bookmark: "00"})
+		assert.EqualError(t, err, "bookmark [00] is out of the range of the keys with prefix [\x00asset\x00]")
+	}
+	verifyPrefixHistory()
+
+	// the records written by the previous releases hold no value and the
+	// modifications are retrieved from the block store
+	db := env.testHistoryDB.(*historyDB).db
+	itr := db.GetIterator([]byte("ns1\x00"), []byte("ns1\x01"))
+	var legacyKeys [][]byte
+	for itr.Next() {
+		legacyKeys = append(legacyKeys, append([]byte{}, itr.Key()...))
+	}
+	itr.Release()
+	assert.Len(t, legacyKeys, 6)
+	for _, legacyKey := range legacyKeys {
+		assert.NoError(t, db.Put(legacyKey, []byte{}, true))
+	}
+	verifyPrefixHistory()
+}
+
+func commitBlockWithWrites(t *testing.T, env *levelDBLockBasedHistoryEnv, store blkstorage.BlockStore,
+	bg *testutil.BlockGenerator, txWrites ...map[string]string) {
+	simulationResults := [][]byte{}
+	for _, writes := range txWrites {
+		simulator, err := env.txmgr.NewTxSimulator(util2.GenerateUUID())
+		assert.NoError(t, err)
+		for key, value := range writes {
+			if value == "" {
+				assert.NoError(t, simulator.DeleteState("ns1", key))
+			} else {
+				assert.NoError(t, simulator.SetState("ns1", key, []byte(value)))
+			}
+		}
+		simulator.Done()
+		simRes, err := simulator.GetTxSimulationResults()
+		assert.NoError(t, err)
+		pubSimResBytes, err := simRes.GetPubSimulationBytes()
+		assert.NoError(t, err)
+		simulationResults = append(simulationResults, pubSimResBytes)
+	}
+	block := bg.NextBlock(simulationResults)
+	assert.NoError(t, store.AddBlock(block))
+	assert.NoError(t, env.testHistoryDB.Commit(block))
+}
+
+func retrieveHistory(t *testing.T, hqe ledger.HistoryQueryExecutor, ns, key string, keyPrefix bool,
+	options *ledger.HistoryQueryOptions) []*queryresult.KeyModification {
+	var itr commonledger.ResultsIterator
+	var err error
+	if keyPrefix {
+		itr, err = hqe.GetHistoryForKeysByPrefix(ns, key, options)
+	} else {
+		itr, err = hqe.GetHistoryForKeyWithOptions(ns, key, options)
+	}
+	assert.NoError(t, err)
+	defer itr.Close()
+	var kmods []*queryresult.KeyModification
+	for {
+		kmod, err := itr.Next()
+		assert.NoError(t, err)
+		if kmod == nil {
+			return kmods
+		}
+		kmods = append(kmods, kmod.(*queryresult.KeyModification))
+	}
+}
+
+func historyEntries(kmods []*queryresult.KeyModification) []string {
+	var entries []string
+	for _, kmod := range kmods {
+		if kmod.IsDelete {
+			entries = append(entries, kmod.Key+":<deleted>")
+		} else {
+			entries = append(entries, kmod.Key+":"+string(kmod.Value))
+		}
+	}
+	return entries
+}
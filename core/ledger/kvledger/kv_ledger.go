@@ -110,6 +110,11 @@ func (l *kvLedger) recoverDBs() error {
 		if err != nil {
 			return err
 		}
+		if recoverFlag && firstBlockNum > lastAvailableBlockNum+1 {
+			// this is possible only if the block store was rolled back without dropping the databases
+			return errors.Errorf("a database [height=%d] is ahead of the block store [height=%d]",
+				firstBlockNum, lastAvailableBlockNum+1)
+		}
 		if recoverFlag {
 			recoverers = append(recoverers, &recoverer{firstBlockNum, recoverable})
 		}
@@ -225,6 +230,22 @@ func (l *kvLedger) GetTxValidationCodeByTxID(txID string) (peer.TxValidationCode
 	return txValidationCode, err
 }
 
+// GetBlocksByTimeRange returns a page of the blocks committed in the given time range
+func (l *kvLedger) GetBlocksByTimeRange(startTime, endTime time.Time, pageSize int, bookmark string) ([]*common.Block, string, error) {
+	blocks, nextBookmark, err := l.blockStore.RetrieveBlocksByTimeRange(startTime, endTime, pageSize, bookmark)
+	l.blockAPIsRWLock.RLock()
+	l.blockAPIsRWLock.RUnlock()
+	return blocks, nextBookmark, err
+}
+
+// GetTransactionsByTimeRange returns a page of the transactions whose timestamp is in the given time range
+func (l *kvLedger) GetTransactionsByTimeRange(startTime, endTime time.Time, pageSize int, bookmark string) ([]*peer.ProcessedTransaction, string, error) {
+	txs, nextBookmark, err := l.blockStore.RetrieveTxsByTimeRange(startTime, endTime, pageSize, bookmark)
+	l.blockAPIsRWLock.RLock()
+	l.blockAPIsRWLock.RUnlock()
+	return txs, nextBookmark, err
+}
+
 //Prune prunes the blocks/transactions that satisfy the given policy
 func (l *kvLedger) Prune(policy commonledger.PrunePolicy) error {
 	return errors.New("not yet implemented")
@@ -346,6 +367,12 @@ func (l *kvLedger) GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, err
 	return l, nil
 }
 
+// Drain waits for the in-flight transaction simulators and query executors to be done
+// and makes the subsequent requests for new ones fail. It is invoked before the ledger is removed
+func (l *kvLedger) Drain() {
+	l.txtmgmt.Drain()
+}
+
 // Close closes `KVLedger`
 func (l *kvLedger) Close() {
 	l.blockStore.Shutdown()
@@ -7,7 +7,6 @@ SPDX-License-Identifier: Apache-2.0
 package kvledger
 
 import (
-	"bytes"
 	"fmt"
 
 	"github.com/golang/protobuf/proto"
@@ -36,6 +35,18 @@ var (
 
 	underConstructionLedgerKey = []byte("underConstructionLedgerKey")
 	ledgerKeyPrefix            = []byte("l")
+	ledgerKeyStop              = []byte{ledgerKeyPrefix[0] + 1}
+	ledgerStatusKeyPrefix      = []byte("s")
+)
+
+// ledgerStatus is the status of a ledger persisted in the ID store.
+// A ledger that has no status entry is active
+type ledgerStatus byte
+
+const (
+	ledgerStatusActive ledgerStatus = iota
+	ledgerStatusPaused
+	ledgerStatusUnderDeletion
 )
 
 // Provider implements interface ledger.PeerLedgerProvider
@@ -48,12 +59,18 @@ type Provider struct {
 	stateListeners      []ledger.StateListener
 	bookkeepingProvider bookkeeping.Provider
 	initializer         *ledger.Initializer
+	fileLock            *leveldbhelper.FileLock
 }
 
 // NewProvider instantiates a new Provider.
 // This is not thread-safe and assumed to be synchronized be the caller
 func NewProvider() (ledger.PeerLedgerProvider, error) {
 	logger.Info("Initializing ledger provider")
+	// Prevent the offline ledger commands (such as rollback and reset) from running while the ledger is in use
+	fileLock := leveldbhelper.NewFileLock(ledgerconfig.GetFileLockPath())
+	if err := fileLock.Lock(); err != nil {
+		return nil, errors.WithMessage(err, "the ledger data is in use by another peer process or peer node command")
+	}
 	// Initialize the ID store (inventory of chainIds/ledgerIds)
 	idStore := openIDStore(ledgerconfig.GetLedgerProviderPath())
 	ledgerStoreProvider := ledgerstorage.NewProvider()
@@ -61,13 +78,14 @@ func NewProvider() (ledger.PeerLedgerProvider, error) {
 	// Initialize the versioned database (state database)
 	vdbProvider, err := privacyenabledstate.NewCommonStorageDBProvider(bookkeepingProvider)
 	if err != nil {
+		fileLock.Unlock()
 		return nil, err
 	}
 	// Initialize the history database (index for history of values by key)
 	historydbProvider := historyleveldb.NewHistoryDBProvider()
 	logger.Info("ledger provider Initialized")
 	provider := &Provider{idStore, ledgerStoreProvider,
-		vdbProvider, historydbProvider, nil, nil, bookkeepingProvider, nil}
+		vdbProvider, historydbProvider, nil, nil, bookkeepingProvider, nil, fileLock}
 	return provider, nil
 }
 
@@ -77,6 +95,7 @@ func (provider *Provider) Initialize(initializer *ledger.Initializer) {
 	provider.configHistoryMgr = confighistory.NewMgr()
 	provider.stateListeners = initializer.StateListeners
 	provider.recoverUnderConstructionLedger()
+	provider.recoverUnderDeletionLedgers()
 }
 
 // Create implements the corresponding method from interface ledger.PeerLedgerProvider
@@ -127,6 +146,16 @@ func (provider *Provider) Open(ledgerID string) (ledger.PeerLedger, error) {
 	if !exists {
 		return nil, ErrNonExistingLedgerID
 	}
+	status, err := provider.idStore.getLedgerStatus(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case ledgerStatusPaused:
+		return nil, errors.Errorf("ledger [%s] is paused and cannot be opened", ledgerID)
+	case ledgerStatusUnderDeletion:
+		return nil, errors.Errorf("ledger [%s] is being removed and cannot be opened", ledgerID)
+	}
 	return provider.openInternal(ledgerID)
 }
 
@@ -164,9 +193,40 @@ func (provider *Provider) Exists(ledgerID string) (bool, error) {
 	return provider.idStore.ledgerIDExists(ledgerID)
 }
 
+// IsPaused implements the corresponding method from interface ledger.PeerLedgerProvider
+func (provider *Provider) IsPaused(ledgerID string) (bool, error) {
+	status, err := provider.idStore.getLedgerStatus(ledgerID)
+	if err != nil {
+		return false, err
+	}
+	return status == ledgerStatusPaused, nil
+}
+
 // List implements the corresponding method from interface ledger.PeerLedgerProvider
 func (provider *Provider) List() ([]string, error) {
-	return provider.idStore.getAllLedgerIds()
+	return provider.idStore.getActiveLedgerIds()
+}
+
+// ListPaused implements the corresponding method from interface ledger.PeerLedgerProvider
+func (provider *Provider) ListPaused() ([]string, error) {
+	return provider.idStore.getLedgerIdsWithStatus(ledgerStatusPaused)
+}
+
+// Remove implements the corresponding method from interface ledger.PeerLedgerProvider.
+// The ledger is first marked as under deletion in the ID store so that a crash in the middle of
+// the deletion leaves a ledger that is not opened and whose deletion is completed on the next start
+func (provider *Provider) Remove(ledgerID string) error {
+	if err := provider.idStore.updateLedgerStatus(ledgerID, ledgerStatusUnderDeletion); err != nil {
+		return err
+	}
+	if err := provider.deleteLedgerData(ledgerID); err != nil {
+		return err
+	}
+	if err := provider.idStore.deleteLedgerID(ledgerID); err != nil {
+		return err
+	}
+	logger.Infof("Ledger [%s] has been removed", ledgerID)
+	return nil
 }
 
 // Close implements the corresponding method from interface ledger.PeerLedgerProvider
@@ -177,6 +237,7 @@ func (provider *Provider) Close() {
 	provider.historydbProvider.Close()
 	provider.bookkeepingProvider.Close()
 	provider.configHistoryMgr.Close()
+	provider.fileLock.Unlock()
 }
 
 // recoverUnderConstructionLedger checks whether the under construction flag is set - this would be the case
@@ -216,14 +277,48 @@ func (provider *Provider) recoverUnderConstructionLedger() {
 	return
 }
 
+// recoverUnderDeletionLedgers completes the deletion of the ledgers that were being removed when the peer crashed
+func (provider *Provider) recoverUnderDeletionLedgers() {
+	ledgerIDs, err := provider.idStore.getAllLedgerIds()
+	panicOnErr(err, "Error while listing the ledgers")
+	for _, ledgerID := range ledgerIDs {
+		status, err := provider.idStore.getLedgerStatus(ledgerID)
+		panicOnErr(err, "Error while retrieving the status of ledger [%s]", ledgerID)
+		if status != ledgerStatusUnderDeletion {
+			continue
+		}
+		logger.Infof("ledger [%s] found as under deletion. Completing the deletion", ledgerID)
+		panicOnErr(provider.Remove(ledgerID), "Error while removing ledger [%s]", ledgerID)
+	}
+}
+
 // runCleanup cleans up blockstorage, statedb, and historydb for what
 // may have got created during in-complete ledger creation
 func (provider *Provider) runCleanup(ledgerID string) error {
-	// TODO - though, not having this is harmless for kv ledger.
-	// If we want, following could be done:
-	// - blockstorage could remove empty folders
-	// - couchdb backed statedb could delete the database if got created
-	// - leveldb backed statedb and history db need not perform anything as it uses a single db shared across ledgers
+	return provider.deleteLedgerData(ledgerID)
+}
+
+// deleteLedgerData deletes the data of the given ledger from all the stores.
+// The stores that are shared across ledgers are left in place
+func (provider *Provider) deleteLedgerData(ledgerID string) error {
+	logger.Infof("Deleting the data of ledger [%s]", ledgerID)
+	if err := provider.ledgerStoreProvider.Remove(ledgerID); err != nil {
+		return errors.WithMessage(err, "error deleting the block store and the pvt data store")
+	}
+	if err := provider.vdbProvider.Drop(ledgerID); err != nil {
+		return errors.WithMessage(err, "error deleting the state database")
+	}
+	if err := provider.historydbProvider.Drop(ledgerID); err != nil {
+		return errors.WithMessage(err, "error deleting the history database")
+	}
+	if err := provider.bookkeepingProvider.Drop(ledgerID); err != nil {
+		return errors.WithMessage(err, "error deleting the bookkeeping data")
+	}
+	if provider.configHistoryMgr != nil {
+		if err := provider.configHistoryMgr.Drop(ledgerID); err != nil {
+			return errors.WithMessage(err, "error deleting the config history")
+		}
+	}
 	return nil
 }
 
@@ -295,18 +390,71 @@ func (s *idStore) ledgerIDExists(ledgerID string) (bool, error) {
 
 func (s *idStore) getAllLedgerIds() ([]string, error) {
 	var ids []string
-	itr := s.db.GetIterator(nil, nil)
+	itr := s.db.GetIterator(ledgerKeyPrefix, ledgerKeyStop)
 	defer itr.Release()
-	itr.First()
-	for itr.Valid() {
-		if bytes.Equal(itr.Key(), underConstructionLedgerKey) {
-			continue
-		}
+	for itr.Next() {
 		id := string(s.decodeLedgerID(itr.Key()))
 		ids = append(ids, id)
-		itr.Next()
 	}
-	return ids, nil
+	return ids, itr.Error()
+}
+
+func (s *idStore) getActiveLedgerIds() ([]string, error) {
+	return s.getLedgerIdsWithStatus(ledgerStatusActive)
+}
+
+func (s *idStore) getLedgerIdsWithStatus(status ledgerStatus) ([]string, error) {
+	ids, err := s.getAllLedgerIds()
+	if err != nil {
+		return nil, err
+	}
+	var matchingIDs []string
+	for _, id := range ids {
+		idStatus, err := s.getLedgerStatus(id)
+		if err != nil {
+			return nil, err
+		}
+		if idStatus == status {
+			matchingIDs = append(matchingIDs, id)
+		}
+	}
+	return matchingIDs, nil
+}
+
+func (s *idStore) getLedgerStatus(ledgerID string) (ledgerStatus, error) {
+	val, err := s.db.Get(s.encodeLedgerStatusKey(ledgerID))
+	if err != nil {
+		return ledgerStatusActive, err
+	}
+	if len(val) == 0 {
+		return ledgerStatusActive, nil
+	}
+	return ledgerStatus(val[0]), nil
+}
+
+func (s *idStore) updateLedgerStatus(ledgerID string, status ledgerStatus) error {
+	exists, err := s.ledgerIDExists(ledgerID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNonExistingLedgerID
+	}
+	currentStatus, err := s.getLedgerStatus(ledgerID)
+	if err != nil {
+		return err
+	}
+	if currentStatus == ledgerStatusUnderDeletion && status != ledgerStatusUnderDeletion {
+		return errors.Errorf("ledger [%s] is being removed", ledgerID)
+	}
+	return s.db.Put(s.encodeLedgerStatusKey(ledgerID), []byte{byte(status)}, true)
+}
+
+func (s *idStore) deleteLedgerID(ledgerID string) error {
+	batch := &leveldb.Batch{}
+	batch.Delete(s.encodeLedgerKey(ledgerID))
+	batch.Delete(s.encodeLedgerStatusKey(ledgerID))
+	return s.db.WriteBatch(batch, true)
 }
 
 func (s *idStore) close() {
@@ -317,6 +465,10 @@ func (s *idStore) encodeLedgerKey(ledgerID string) []byte {
 	return append(ledgerKeyPrefix, []byte(ledgerID)...)
 }
 
+func (s *idStore) encodeLedgerStatusKey(ledgerID string) []byte {
+	return append(append([]byte{}, ledgerStatusKeyPrefix...), []byte(ledgerID)...)
+}
+
 func (s *idStore) decodeLedgerID(key []byte) string {
 	return string(key[len(ledgerKeyPrefix):])
 }
@@ -136,6 +136,77 @@ func TestRecovery(t *testing.T) {
 
 }
 
+func TestRemove(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := testutilNewProvider(t)
+	testLedgerid := "testLedger"
+	bg, gb := testutil.NewBlockGenerator(t, testLedgerid, false)
+	ledger, err := provider.Create(gb)
+	assert.NoError(t, err)
+	collectionConfigBlk := prepareNextBlockForTestCollectionConfigs(t, ledger, bg, "simulationForCollConfig", "ns", map[string]uint64{"coll": 0})
+	assert.NoError(t, ledger.CommitWithPvtData(collectionConfigBlk))
+	blk := prepareNextBlockForTest(t, ledger, bg, "SimulateForBlk2",
+		map[string]string{"key1": "value1"}, map[string]string{"key1": "pvtValue1"})
+	assert.NoError(t, ledger.CommitWithPvtData(blk))
+	ledger.Close()
+	otherGB, _ := configtxtest.MakeGenesisBlock(constructTestLedgerID(1))
+	otherLedger, err := provider.Create(otherGB)
+	assert.NoError(t, err)
+	otherLedger.Close()
+
+	assert.NoError(t, provider.Remove(testLedgerid))
+	assert.Equal(t, ErrNonExistingLedgerID, provider.Remove(testLedgerid))
+	exists, err := provider.Exists(testLedgerid)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	ledgerIDs, err := provider.List()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{constructTestLedgerID(1)}, ledgerIDs)
+	_, err = provider.Open(testLedgerid)
+	assert.Equal(t, ErrNonExistingLedgerID, err)
+
+	// a ledger with the removed id starts afresh
+	ledger, err = provider.Create(gb)
+	assert.NoError(t, err)
+	bcInfo, err := ledger.GetBlockchainInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), bcInfo.Height)
+	qe, err := ledger.NewQueryExecutor()
+	assert.NoError(t, err)
+	val, err := qe.GetState("ns", "key1")
+	assert.NoError(t, err)
+	assert.Nil(t, val)
+	qe.Done()
+	pvtdata, err := ledger.GetPvtDataByNum(0, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, pvtdata)
+	hqe, err := ledger.NewHistoryQueryExecutor()
+	assert.NoError(t, err)
+	itr, err := hqe.GetHistoryForKey("ns", "key1")
+	assert.NoError(t, err)
+	defer itr.Close()
+	kmod, err := itr.Next()
+	assert.NoError(t, err)
+	assert.Nil(t, kmod)
+	ledger.Close()
+
+	// a crash in the middle of the removal of a ledger is recovered on the next start
+	assert.NoError(t, provider.(*Provider).idStore.updateLedgerStatus(constructTestLedgerID(1), ledgerStatusUnderDeletion))
+	_, err = provider.Open(constructTestLedgerID(1))
+	assert.EqualError(t, err, "ledger [ledger_000001] is being removed and cannot be opened")
+	assert.EqualError(t, provider.(*Provider).idStore.updateLedgerStatus(constructTestLedgerID(1), ledgerStatusPaused),
+		"ledger [ledger_000001] is being removed")
+	provider.Close()
+	provider = testutilNewProvider(t)
+	defer provider.Close()
+	exists, err = provider.Exists(constructTestLedgerID(1))
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	_, err = os.Stat(filepath.Join(ledgerconfig.GetBlockStorePath(), fsblkstorage.ChainsDir, constructTestLedgerID(1)))
+	assert.True(t, os.IsNotExist(err))
+}
+
 func TestMultipleLedgerBasicRW(t *testing.T) {
 	env := newTestEnv(t)
 	defer env.cleanup()
@@ -0,0 +1,52 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"justledger/core/ledger/ledgerconfig"
+	"github.com/pkg/errors"
+)
+
+// PauseChannel marks the ledger of the given channel as paused in the ID store. The peer does not open
+// a paused ledger, nor does it start the gossip and deliver services of the channel, when it starts.
+// This function fails if the ledger data is in use by a running peer
+func PauseChannel(ledgerID string) error {
+	if err := updateLedgerStatus(ledgerID, ledgerStatusPaused); err != nil {
+		return err
+	}
+	logger.Infof("The channel [%s] has been successfully paused", ledgerID)
+	return nil
+}
+
+// ResumeChannel marks the ledger of the given channel as active in the ID store so that the peer
+// opens the ledger and joins the channel again when it starts.
+// This function fails if the ledger data is in use by a running peer
+func ResumeChannel(ledgerID string) error {
+	if err := updateLedgerStatus(ledgerID, ledgerStatusActive); err != nil {
+		return err
+	}
+	logger.Infof("The channel [%s] has been successfully resumed", ledgerID)
+	return nil
+}
+
+func updateLedgerStatus(ledgerID string, status ledgerStatus) error {
+	fileLock, err := lockLedgerData()
+	if err != nil {
+		return err
+	}
+	defer fileLock.Unlock()
+
+	idStore := openIDStore(ledgerconfig.GetLedgerProviderPath())
+	defer idStore.close()
+	if err := idStore.updateLedgerStatus(ledgerID, status); err != nil {
+		if err == ErrNonExistingLedgerID {
+			return errors.Errorf("ledger [%s] does not exist", ledgerID)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"testing"
+
+	configtxtest "justledger/common/configtx/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPauseAndResume(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := testutilNewProvider(t)
+	numLedgers := 3
+	for i := 0; i < numLedgers; i++ {
+		genesisBlock, _ := configtxtest.MakeGenesisBlock(constructTestLedgerID(i))
+		l, err := provider.Create(genesisBlock)
+		require.NoError(t, err)
+		l.Close()
+	}
+
+	// pause and resume are not allowed while the ledger data is in use
+	err := PauseChannel(constructTestLedgerID(1))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the ledger data is in use")
+	err = ResumeChannel(constructTestLedgerID(1))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the ledger data is in use")
+	provider.Close()
+
+	assert.EqualError(t, PauseChannel("nonExistingLedger"), "ledger [nonExistingLedger] does not exist")
+	assert.EqualError(t, ResumeChannel("nonExistingLedger"), "ledger [nonExistingLedger] does not exist")
+	require.NoError(t, PauseChannel(constructTestLedgerID(1)))
+	// pausing a paused ledger is a no-op
+	require.NoError(t, PauseChannel(constructTestLedgerID(1)))
+
+	provider = testutilNewProvider(t)
+	ledgerIDs, err := provider.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{constructTestLedgerID(0), constructTestLedgerID(2)}, ledgerIDs)
+	pausedIDs, err := provider.ListPaused()
+	require.NoError(t, err)
+	assert.Equal(t, []string{constructTestLedgerID(1)}, pausedIDs)
+	paused, err := provider.IsPaused(constructTestLedgerID(1))
+	require.NoError(t, err)
+	assert.True(t, paused)
+	paused, err = provider.IsPaused(constructTestLedgerID(0))
+	require.NoError(t, err)
+	assert.False(t, paused)
+	exists, err := provider.Exists(constructTestLedgerID(1))
+	require.NoError(t, err)
+	assert.True(t, exists)
+	_, err = provider.Open(constructTestLedgerID(1))
+	assert.EqualError(t, err, "ledger [ledger_000001] is paused and cannot be opened")
+	genesisBlock, _ := configtxtest.MakeGenesisBlock(constructTestLedgerID(1))
+	_, err = provider.Create(genesisBlock)
+	assert.Equal(t, ErrLedgerIDExists, err)
+	provider.Close()
+
+	require.NoError(t, ResumeChannel(constructTestLedgerID(1)))
+
+	provider = testutilNewProvider(t)
+	defer provider.Close()
+	ledgerIDs, err = provider.List()
+	require.NoError(t, err)
+	assert.Len(t, ledgerIDs, numLedgers)
+	pausedIDs, err = provider.ListPaused()
+	require.NoError(t, err)
+	assert.Empty(t, pausedIDs)
+	paused, err = provider.IsPaused(constructTestLedgerID(1))
+	require.NoError(t, err)
+	assert.False(t, paused)
+	l, err := provider.Open(constructTestLedgerID(1))
+	require.NoError(t, err)
+	bcInfo, err := l.GetBlockchainInfo()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), bcInfo.Height)
+	l.Close()
+}
@@ -0,0 +1,144 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"os"
+
+	"justledger/common/ledger/util/leveldbhelper"
+	"justledger/core/ledger/kvledger/txmgmt/statedb/statecouchdb"
+	"justledger/core/ledger/kvledger/txmgmt/statedb/statemongodb"
+	"justledger/core/ledger/ledgerconfig"
+	"justledger/core/ledger/ledgerstorage"
+	"github.com/pkg/errors"
+)
+
+// RollbackKVLedger rolls back the block store and the pvt data store of the given ledger to the given
+// block number. The state, history, bookkeeping and config history databases of all the ledgers are
+// dropped and get rebuilt from the block stores when the peer starts. The blocks that are removed by the
+// rollback are received again from the ordering service or the other peers.
+// This function fails if the ledger data is in use by a running peer
+func RollbackKVLedger(ledgerID string, blockNum uint64) error {
+	fileLock, err := lockLedgerData()
+	if err != nil {
+		return err
+	}
+	defer fileLock.Unlock()
+
+	ledgerIDs, err := loadLedgerIDs()
+	if err != nil {
+		return err
+	}
+	if !contains(ledgerIDs, ledgerID) {
+		return errors.Errorf("ledger [%s] does not exist", ledgerID)
+	}
+	if err := ledgerstorage.ValidateRollbackParams(ledgerID, blockNum); err != nil {
+		return err
+	}
+
+	logger.Info("Dropping the databases")
+	if err := dropDBs(ledgerIDs); err != nil {
+		return err
+	}
+	logger.Infof("Rolling back the ledger [%s] to block number [%d]", ledgerID, blockNum)
+	if err := ledgerstorage.Rollback(ledgerID, blockNum); err != nil {
+		return err
+	}
+	logger.Infof("The ledger [%s] has been rolled back to block number [%d]", ledgerID, blockNum)
+	return nil
+}
+
+// ResetAllKVLedgers resets the block stores and the pvt data stores of all the ledgers to the genesis
+// block and drops the state, history, bookkeeping and config history databases. When the peer starts,
+// the databases get rebuilt and the rest of the blocks are received again from the ordering service
+// or the other peers. This function fails if the ledger data is in use by a running peer
+func ResetAllKVLedgers() error {
+	fileLock, err := lockLedgerData()
+	if err != nil {
+		return err
+	}
+	defer fileLock.Unlock()
+
+	ledgerIDs, err := loadLedgerIDs()
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Dropping the databases")
+	if err := dropDBs(ledgerIDs); err != nil {
+		return err
+	}
+	logger.Info("Resetting the ledgers to the genesis block")
+	if err := ledgerstorage.Reset(ledgerIDs); err != nil {
+		return err
+	}
+	logger.Infof("The ledgers %s have been reset to the genesis block", ledgerIDs)
+	return nil
+}
+
+// lockLedgerData acquires the lock that a running peer holds on the ledger data, so that the offline
+// ledger commands fail instead of modifying the ledger data of a running peer
+func lockLedgerData() (*leveldbhelper.FileLock, error) {
+	fileLock := leveldbhelper.NewFileLock(ledgerconfig.GetFileLockPath())
+	if err := fileLock.Lock(); err != nil {
+		return nil, errors.WithMessage(err, "the ledger data is in use; stop the peer before running this command")
+	}
+	return fileLock, nil
+}
+
+func loadLedgerIDs() ([]string, error) {
+	idStore := openIDStore(ledgerconfig.GetLedgerProviderPath())
+	defer idStore.close()
+	return idStore.getAllLedgerIds()
+}
+
+// dropDBs drops the databases that are rebuilt from the block store when a ledger is opened.
+// The state database is dropped first because the bookkeeping and config history databases are
+// rebuilt only along with it. Hence, if a failure happens in between, the command can be retried
+// or the peer started without leaving the dropped databases behind the state database
+func dropDBs(ledgerIDs []string) error {
+	if err := dropStateDBs(ledgerIDs); err != nil {
+		return err
+	}
+	for _, dbPath := range []string{
+		ledgerconfig.GetConfigHistoryPath(),
+		ledgerconfig.GetInternalBookkeeperPath(),
+		ledgerconfig.GetHistoryLevelDBPath(),
+	} {
+		if err := removeDB(dbPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dropStateDBs(ledgerIDs []string) error {
+	if ledgerconfig.IsCouchDBEnabled() {
+		return statecouchdb.DropLedgerDBs(ledgerIDs)
+	}
+	if ledgerconfig.IsMongoDBEnabled() {
+		return statemongodb.DropLedgerDBs(ledgerIDs)
+	}
+	return removeDB(ledgerconfig.GetStateLevelDBPath())
+}
+
+func removeDB(dbPath string) error {
+	logger.Infof("Dropping database [%s]", dbPath)
+	if err := os.RemoveAll(dbPath); err != nil {
+		return errors.Wrapf(err, "error removing database [%s]", dbPath)
+	}
+	return nil
+}
+
+func contains(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"testing"
+
+	"justledger/common/ledger/testutil"
+	lgr "justledger/core/ledger"
+	"justledger/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollbackKVLedger(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := testutilNewProvider(t)
+	testLedgerid := "testLedger"
+	bg, gb := testutil.NewBlockGenerator(t, testLedgerid, false)
+	ledger, err := provider.Create(gb)
+	require.NoError(t, err)
+
+	collectionConfigBlk := prepareNextBlockForTestCollectionConfigs(t, ledger, bg, "simulationForCollConfig", "ns", map[string]uint64{"coll": 0})
+	require.NoError(t, ledger.CommitWithPvtData(collectionConfigBlk))
+	blocks := []*lgr.BlockAndPvtData{collectionConfigBlk}
+	for i, v := range []string{"1", "2", "3"} {
+		blk := prepareNextBlockForTest(t, ledger, bg, "SimulateForBlk"+v,
+			map[string]string{"key1": "value1." + v, "key2": "value2." + v},
+			map[string]string{"key1": "pvtValue1." + v, "key2": "pvtValue2." + v})
+		require.NoError(t, ledger.CommitWithPvtData(blk), "block %d", i+2)
+		blocks = append(blocks, blk)
+	}
+
+	// rollback is not allowed while the ledger data is in use
+	err = RollbackKVLedger(testLedgerid, 2)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the ledger data is in use")
+	ledger.Close()
+	provider.Close()
+
+	assert.EqualError(t, RollbackKVLedger("nonExistingLedger", 2), "ledger [nonExistingLedger] does not exist")
+	assert.EqualError(t, RollbackKVLedger(testLedgerid, 4), "target block number [4] should be less than the biggest block number [4]")
+	require.NoError(t, RollbackKVLedger(testLedgerid, 2))
+
+	provider = testutilNewProvider(t)
+	defer provider.Close()
+	ledger, err = provider.Open(testLedgerid)
+	require.NoError(t, err)
+	defer ledger.Close()
+	checkBCSummaryForTest(t, ledger,
+		&bcSummary{
+			bcInfo: &common.BlockchainInfo{Height: 3,
+				CurrentBlockHash:  blocks[1].Block.Header.Hash(),
+				PreviousBlockHash: blocks[0].Block.Header.Hash()},
+			stateDBSavePoint:   2,
+			stateDBKVs:         map[string]string{"key1": "value1.1", "key2": "value2.1"},
+			stateDBPvtKVs:      map[string]string{"key1": "pvtValue1.1", "key2": "pvtValue2.1"},
+			historyDBSavePoint: 2,
+			historyKey:         "key1",
+			historyVals:        []string{"value1.1"},
+		},
+	)
+
+	// the removed blocks can be committed again
+	for _, blk := range blocks[2:] {
+		require.NoError(t, ledger.CommitWithPvtData(blk))
+	}
+	checkBCSummaryForTest(t, ledger,
+		&bcSummary{
+			bcInfo: &common.BlockchainInfo{Height: 5,
+				CurrentBlockHash:  blocks[3].Block.Header.Hash(),
+				PreviousBlockHash: blocks[2].Block.Header.Hash()},
+			stateDBKVs:    map[string]string{"key1": "value1.3", "key2": "value2.3"},
+			stateDBPvtKVs: map[string]string{"key1": "pvtValue1.3", "key2": "pvtValue2.3"},
+		},
+	)
+}
+
+func TestResetAllKVLedgers(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := testutilNewProvider(t)
+	testLedgerids := []string{"testLedger1", "testLedger2"}
+	genesisBlocks := []*common.Block{}
+	for _, ledgerid := range testLedgerids {
+		bg, gb := testutil.NewBlockGenerator(t, ledgerid, false)
+		genesisBlocks = append(genesisBlocks, gb)
+		ledger, err := provider.Create(gb)
+		require.NoError(t, err)
+		simulator, err := ledger.NewTxSimulator("SimulateForBlk1")
+		require.NoError(t, err)
+		require.NoError(t, simulator.SetState("ns", "key1", []byte("value1.1")))
+		simulator.Done()
+		simRes, err := simulator.GetTxSimulationResults()
+		require.NoError(t, err)
+		pubSimBytes, err := simRes.GetPubSimulationBytes()
+		require.NoError(t, err)
+		require.NoError(t, ledger.CommitWithPvtData(&lgr.BlockAndPvtData{Block: bg.NextBlock([][]byte{pubSimBytes})}))
+		ledger.Close()
+	}
+
+	// reset is not allowed while the ledger data is in use
+	err := ResetAllKVLedgers()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the ledger data is in use")
+	provider.Close()
+
+	require.NoError(t, ResetAllKVLedgers())
+
+	provider = testutilNewProvider(t)
+	defer provider.Close()
+	for i, ledgerid := range testLedgerids {
+		ledger, err := provider.Open(ledgerid)
+		require.NoError(t, err)
+		checkBCSummaryForTest(t, ledger,
+			&bcSummary{
+				bcInfo: &common.BlockchainInfo{Height: 1, CurrentBlockHash: genesisBlocks[i].Header.Hash()},
+			},
+		)
+		qe, err := ledger.NewQueryExecutor()
+		require.NoError(t, err)
+		val, err := qe.GetState("ns", "key1")
+		require.NoError(t, err)
+		assert.Nil(t, val)
+		qe.Done()
+		ledger.Close()
+	}
+}
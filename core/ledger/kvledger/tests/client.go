@@ -26,7 +26,7 @@ type client struct {
 	assert         *assert.Assertions
 }
 
-func newClient(lgr ledger.PeerLedger, t *testing.T) *client {
+func newClient(lgr ledger.PeerLedger, t testing.TB) *client {
 	return &client{lgr, nil, assert.New(t)}
 }
 
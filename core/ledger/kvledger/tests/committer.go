@@ -22,7 +22,7 @@ type committer struct {
 	assert *assert.Assertions
 }
 
-func newCommitter(lgr ledger.PeerLedger, t *testing.T) *committer {
+func newCommitter(lgr ledger.PeerLedger, t testing.TB) *committer {
 	return &committer{lgr, newBlockGenerator(lgr, t), assert.New(t)}
 }
 
@@ -67,7 +67,7 @@ type blkGenerator struct {
 // newBlockGenerator constructs a 'blkGenerator' and initializes the 'blkGenerator'
 // from the last block available in the ledger so that the next block can be populated
 // with the correct block number and previous block hash
-func newBlockGenerator(lgr ledger.PeerLedger, t *testing.T) *blkGenerator {
+func newBlockGenerator(lgr ledger.PeerLedger, t testing.TB) *blkGenerator {
 	assert := assert.New(t)
 	info, err := lgr.GetBlockchainInfo()
 	assert.NoError(err)
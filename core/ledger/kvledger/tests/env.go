@@ -32,8 +32,9 @@ const (
 
 var (
 	defaultConfig = config{
-		"peer.fileSystemPath":        "/tmp/fabric/ledgertests",
-		"ledger.state.stateDatabase": "goleveldb",
+		"peer.fileSystemPath":                         "/tmp/fabric/ledgertests",
+		"ledger.state.stateDatabase":                  "goleveldb",
+		"ledger.validation.mvccValidationParallelism": 1,
 	}
 )
 
@@ -41,7 +42,7 @@ type env struct {
 	assert *assert.Assertions
 }
 
-func newEnv(conf config, t *testing.T) *env {
+func newEnv(conf config, t testing.TB) *env {
 	setupConfigs(conf)
 	env := &env{assert.New(t)}
 	initLedgerMgmt()
@@ -0,0 +1,118 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tests
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"justledger/common/flogging"
+	protopeer "justledger/protos/peer"
+)
+
+func parallelValidationConfig(parallelism int) config {
+	conf := config{}
+	for k, v := range defaultConfig {
+		conf[k] = v
+	}
+	conf["ledger.validation.mvccValidationParallelism"] = parallelism
+	return conf
+}
+
+func TestParallelMVCCValidation(t *testing.T) {
+	env := newEnv(parallelValidationConfig(4), t)
+	defer env.cleanup()
+	h := newTestHelperCreateLgr("ledger1", t)
+
+	h.simulateDeployTx("cc1", nil)
+	h.cutBlockAndCommitWithPvtdata()
+	h.simulateDataTx("txid1", func(s *simulator) {
+		for i := 0; i < 5; i++ {
+			s.setState("cc1", fmt.Sprintf("key%d", i), "value")
+		}
+	})
+	h.cutBlockAndCommitWithPvtdata()
+
+	// all the transactions are simulated against the same committed state
+	h.simulateDataTx("txid2", func(s *simulator) {
+		s.getState("cc1", "key1")
+		s.setState("cc1", "key1", "value-txid2")
+	})
+	h.simulateDataTx("txid3", func(s *simulator) {
+		s.getState("cc1", "key1")
+		s.setState("cc1", "key2", "value-txid3")
+	})
+	h.simulateDataTx("txid4", func(s *simulator) {
+		s.getState("cc1", "key2")
+		s.setState("cc1", "key3", "value-txid4")
+	})
+	h.simulateDataTx("txid5", func(s *simulator) {
+		s.getState("cc1", "key4")
+		s.setState("cc1", "key4", "value-txid5")
+	})
+	h.cutBlockAndCommitWithPvtdata()
+
+	h.verifyTxValidationCode("txid2", protopeer.TxValidationCode_VALID)
+	h.verifyTxValidationCode("txid3", protopeer.TxValidationCode_MVCC_READ_CONFLICT)
+	// txid4 reads key2 which is written only by the invalid txid3
+	h.verifyTxValidationCode("txid4", protopeer.TxValidationCode_VALID)
+	h.verifyTxValidationCode("txid5", protopeer.TxValidationCode_VALID)
+	h.verifyPubState("cc1", "key1", "value-txid2")
+	h.verifyPubState("cc1", "key2", "value")
+	h.verifyPubState("cc1", "key3", "value-txid4")
+	h.verifyPubState("cc1", "key4", "value-txid5")
+}
+
+func BenchmarkMVCCValidationSerial(b *testing.B) {
+	benchmarkMVCCValidation(b, 1)
+}
+
+func BenchmarkMVCCValidationParallel4(b *testing.B) {
+	benchmarkMVCCValidation(b, 4)
+}
+
+func BenchmarkMVCCValidationParallelNumCPU(b *testing.B) {
+	benchmarkMVCCValidation(b, runtime.NumCPU())
+}
+
+// benchmarkMVCCValidation measures the commit of blocks of non-conflicting transactions,
+// each of which reads and updates its own set of keys
+func benchmarkMVCCValidation(b *testing.B, parallelism int) {
+	const txsPerBlock = 500
+	const keysPerTx = 4
+	for _, module := range []string{"lockbasedtxmgr", "statebasedval", "valimpl", "kvledger"} {
+		flogging.SetModuleLevel(module, "error")
+	}
+	env := newEnv(parallelValidationConfig(parallelism), b)
+	defer env.cleanup()
+	h := newTestHelperCreateLgr("ledger1", b)
+	h.simulateDeployTx("cc1", nil)
+	h.cutBlockAndCommitWithPvtdata()
+
+	simulateBlock := func() {
+		for i := 0; i < txsPerBlock; i++ {
+			h.simulateDataTx("", func(s *simulator) {
+				for j := 0; j < keysPerTx; j++ {
+					key := fmt.Sprintf("key-%d-%d", i, j)
+					s.getState("cc1", key)
+					s.setState("cc1", key, "value")
+				}
+			})
+		}
+	}
+	simulateBlock()
+	h.cutBlockAndCommitWithPvtdata()
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		b.StopTimer()
+		simulateBlock()
+		b.StartTimer()
+		h.cutBlockAndCommitWithPvtdata()
+	}
+}
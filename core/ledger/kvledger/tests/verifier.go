@@ -25,10 +25,10 @@ import (
 type verifier struct {
 	lgr    ledger.PeerLedger
 	assert *assert.Assertions
-	t      *testing.T
+	t      testing.TB
 }
 
-func newVerifier(lgr ledger.PeerLedger, t *testing.T) *verifier {
+func newVerifier(lgr ledger.PeerLedger, t testing.TB) *verifier {
 	return &verifier{lgr, assert.New(t), t}
 }
 
@@ -9,10 +9,12 @@ package privacyenabledstate
 import (
 	"encoding/base64"
 	"strings"
+	"sync"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 	"justledger/common/flogging"
+	"justledger/common/metrics"
 	"justledger/core/common/ccprovider"
 	"justledger/core/common/privdata"
 	"justledger/core/ledger/cceventmgmt"
@@ -69,6 +71,11 @@ func (p *CommonStorageDBProvider) GetDBHandle(id string) (DB, error) {
 	return NewCommonStorageDB(vdb, id, metadataHint)
 }
 
+// Drop implements function from interface DBProvider
+func (p *CommonStorageDBProvider) Drop(id string) error {
+	return p.VersionedDBProvider.Drop(id)
+}
+
 // Close implements function from interface DBProvider
 func (p *CommonStorageDBProvider) Close() {
 	p.VersionedDBProvider.Close()
@@ -79,12 +86,119 @@ func (p *CommonStorageDBProvider) Close() {
 type CommonStorageDB struct {
 	statedb.VersionedDB
 	metadataHint *metadataHint
+	ledgerid     string
+	// stateCache, if not nil, caches the committed state of the public, hashed, and private data.
+	// commitLock is held exclusively while an update batch is applied to the db and to the cache and
+	// shared while a value read from the db is added to the cache, so that a value that is being
+	// replaced by a commit never makes its way into the cache
+	stateCache *statedb.Cache
+	commitLock sync.RWMutex
+	// metricsScope returns the scope the statistics of the state cache are reported to after each commit.
+	// The metrics are initialized after the ledgers are opened, hence the scope is looked up at every report
+	metricsScope func() metrics.Scope
 }
 
 // NewCommonStorageDB wraps a VersionedDB instance. The public data is managed directly by the wrapped versionedDB.
 // For managing the hashed data and private data, this implementation creates separate namespaces in the wrapped db
 func NewCommonStorageDB(vdb statedb.VersionedDB, ledgerid string, metadataHint *metadataHint) (DB, error) {
-	return &CommonStorageDB{vdb, metadataHint}, nil
+	db := &CommonStorageDB{
+		VersionedDB:  vdb,
+		metadataHint: metadataHint,
+		ledgerid:     ledgerid,
+		metricsScope: func() metrics.Scope { return metrics.RootScope },
+	}
+	if cacheSize := ledgerconfig.GetStateCacheSize(); cacheSize > 0 {
+		logger.Debugf("Enabling the state cache of [%d] MB for ledger [%s]", cacheSize, ledgerid)
+		db.stateCache = statedb.NewCache(cacheSize * 1024 * 1024)
+	}
+	return db, nil
+}
+
+// GetState overrides the function in statedb.VersionedDB for serving the committed values from the state cache
+func (s *CommonStorageDB) GetState(namespace, key string) (*statedb.VersionedValue, error) {
+	if s.stateCache == nil {
+		return s.VersionedDB.GetState(namespace, key)
+	}
+	if vv, ok := s.stateCache.Get(namespace, key); ok {
+		return vv, nil
+	}
+	return s.getStateAndAddToCache(namespace, key)
+}
+
+// GetVersion overrides the function in statedb.VersionedDB for serving the committed versions from the state cache
+func (s *CommonStorageDB) GetVersion(namespace, key string) (*version.Height, error) {
+	if s.stateCache == nil {
+		return s.VersionedDB.GetVersion(namespace, key)
+	}
+	if vv, ok := s.stateCache.Get(namespace, key); ok {
+		if vv == nil {
+			return nil, nil
+		}
+		return vv.Version, nil
+	}
+	// a bulk optimizable db serves the versions loaded before the validation of a block
+	// without fetching the values, which are then not worth adding to the cache
+	if _, ok := s.VersionedDB.(statedb.BulkOptimizable); ok {
+		return s.VersionedDB.GetVersion(namespace, key)
+	}
+	vv, err := s.getStateAndAddToCache(namespace, key)
+	if err != nil || vv == nil {
+		return nil, err
+	}
+	return vv.Version, nil
+}
+
+func (s *CommonStorageDB) getStateAndAddToCache(namespace, key string) (*statedb.VersionedValue, error) {
+	s.commitLock.RLock()
+	defer s.commitLock.RUnlock()
+	vv, err := s.VersionedDB.GetState(namespace, key)
+	if err != nil {
+		return nil, err
+	}
+	s.stateCache.Put(namespace, key, vv)
+	return vv, nil
+}
+
+// GetStateMultipleKeys overrides the function in statedb.VersionedDB for serving the committed values from the state cache.
+// Only the keys that are missing in the cache are fetched from the db
+func (s *CommonStorageDB) GetStateMultipleKeys(namespace string, keys []string) ([]*statedb.VersionedValue, error) {
+	if s.stateCache == nil {
+		return s.VersionedDB.GetStateMultipleKeys(namespace, keys)
+	}
+	vals := make([]*statedb.VersionedValue, len(keys))
+	var missingKeys []string
+	var missingIndexes []int
+	for i, key := range keys {
+		vv, ok := s.stateCache.Get(namespace, key)
+		if !ok {
+			missingKeys = append(missingKeys, key)
+			missingIndexes = append(missingIndexes, i)
+			continue
+		}
+		vals[i] = vv
+	}
+	if len(missingKeys) == 0 {
+		return vals, nil
+	}
+	s.commitLock.RLock()
+	defer s.commitLock.RUnlock()
+	missingVals, err := s.VersionedDB.GetStateMultipleKeys(namespace, missingKeys)
+	if err != nil {
+		return nil, err
+	}
+	for i, vv := range missingVals {
+		vals[missingIndexes[i]] = vv
+		s.stateCache.Put(namespace, missingKeys[i], vv)
+	}
+	return vals, nil
+}
+
+// GetStateCacheStats implements corresponding function in interface DB
+func (s *CommonStorageDB) GetStateCacheStats() (statedb.CacheStats, bool) {
+	if s.stateCache == nil {
+		return statedb.CacheStats{}, false
+	}
+	return s.stateCache.Stats(), true
 }
 
 // IsBulkOptimizable implements corresponding function in interface DB
@@ -190,12 +304,13 @@ func (s *CommonStorageDB) GetPrivateDataRangeScanIterator(namespace, collection,
 }
 
 // ExecuteQueryOnPrivateData implements corresponding function in interface DB
-func (s CommonStorageDB) ExecuteQueryOnPrivateData(namespace, collection, query string) (statedb.ResultsIterator, error) {
+func (s *CommonStorageDB) ExecuteQueryOnPrivateData(namespace, collection, query string) (statedb.ResultsIterator, error) {
 	return s.ExecuteQuery(derivePvtDataNs(namespace, collection), query)
 }
 
 // ApplyUpdates overrides the function in statedb.VersionedDB and throws appropriate error message
-// Otherwise, somewhere in the code, usage of this function could lead to updating only public data.
+// Otherwise, somewhere in the code, usage of this function could lead to updating only public data,
+// and to writing to the db behind the back of the state cache, which would then serve stale values.
 func (s *CommonStorageDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version.Height) error {
 	return errors.New("this function should not be invoked on this type. Please invoke function ApplyPrivacyAwareUpdates")
 }
@@ -207,7 +322,36 @@ func (s *CommonStorageDB) ApplyPrivacyAwareUpdates(updates *UpdateBatch, height
 	addPvtUpdates(combinedUpdates, updates.PvtUpdates)
 	addHashedUpdates(combinedUpdates, updates.HashUpdates, !s.BytesKeySuppoted())
 	s.metadataHint.setMetadataUsedFlag(updates)
-	return s.VersionedDB.ApplyUpdates(combinedUpdates.UpdateBatch, height)
+	if s.stateCache == nil {
+		return s.VersionedDB.ApplyUpdates(combinedUpdates.UpdateBatch, height)
+	}
+
+	s.commitLock.Lock()
+	defer s.commitLock.Unlock()
+	if err := s.VersionedDB.ApplyUpdates(combinedUpdates.UpdateBatch, height); err != nil {
+		// the updates may have been partially applied to the db
+		s.stateCache.Clear()
+		return err
+	}
+	s.stateCache.UpdateBatch(combinedUpdates.UpdateBatch)
+	s.reportStateCacheStats()
+	return nil
+}
+
+func (s *CommonStorageDB) reportStateCacheStats() {
+	stats := s.stateCache.Stats()
+	logger.Debugf("State cache of ledger [%s]: hits=%d, misses=%d, hit ratio=%.2f, entries=%d, size=%d bytes",
+		s.ledgerid, stats.Hits, stats.Misses, stats.HitRatio(), stats.Entries, stats.Bytes)
+	scope := s.metricsScope()
+	if scope == nil {
+		return
+	}
+	scope = scope.SubScope("ledger").SubScope("state_cache").Tagged(map[string]string{"channel": s.ledgerid})
+	scope.Gauge("hits").Update(float64(stats.Hits))
+	scope.Gauge("misses").Update(float64(stats.Misses))
+	scope.Gauge("hit_ratio").Update(stats.HitRatio())
+	scope.Gauge("entries").Update(float64(stats.Entries))
+	scope.Gauge("size_bytes").Update(float64(stats.Bytes))
 }
 
 // GetStateMetadata implements corresponding function in interface DB. This implementation provides
@@ -18,6 +18,8 @@ import (
 type DBProvider interface {
 	// GetDBHandle returns a handle to a PvtVersionedDB
 	GetDBHandle(id string) (DB, error)
+	// Drop deletes the PvtVersionedDB with the given id and all of its data
+	Drop(id string) error
 	// Close closes all the PvtVersionedDB instances and releases any resources held by VersionedDBProvider
 	Close()
 }
@@ -39,6 +41,9 @@ type DB interface {
 	GetPrivateDataMetadataByHash(namespace, collection string, keyHash []byte) ([]byte, error)
 	ExecuteQueryOnPrivateData(namespace, collection, query string) (statedb.ResultsIterator, error)
 	ApplyPrivacyAwareUpdates(updates *UpdateBatch, height *version.Height) error
+	// GetStateCacheStats returns the statistics of the state cache. The returned boolean is false if the
+	// state cache is disabled
+	GetStateCacheStats() (statedb.CacheStats, bool)
 }
 
 // PvtdataCompositeKey encloses Namespace, CollectionName and Key components
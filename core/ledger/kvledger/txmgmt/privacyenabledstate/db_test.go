@@ -9,16 +9,21 @@ package privacyenabledstate
 import (
 	"fmt"
 	"os"
+	"sync"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 
 	"justledger/common/ledger/testutil"
+	"justledger/common/metrics"
 	"justledger/core/common/ccprovider"
 	"justledger/core/ledger/cceventmgmt"
+	"justledger/core/ledger/kvledger/bookkeeping"
 	"justledger/core/ledger/kvledger/txmgmt/statedb"
+	"justledger/core/ledger/kvledger/txmgmt/statedb/mock"
 	"justledger/core/ledger/kvledger/txmgmt/version"
 	"justledger/core/ledger/util"
 	"justledger/protos/common"
@@ -596,3 +601,206 @@ func deletePvtUpdates(t *testing.T, updates *UpdateBatch, ns, coll, key string,
 	updates.PvtUpdates.Delete(ns, coll, key, ver)
 	updates.HashUpdates.Delete(ns, coll, util.ComputeStringHash(key), ver)
 }
+
+func TestStateCache(t *testing.T) {
+	viper.Set("ledger.state.cacheSize", 1)
+	defer viper.Set("ledger.state.cacheSize", 0)
+	testEnv := &LevelDBCommonStorageTestEnv{}
+	testEnv.Init(t)
+	defer testEnv.Cleanup()
+	db := testEnv.GetDBHandle("test-state-cache")
+	commonStorageDB := db.(*CommonStorageDB)
+
+	updates := NewUpdateBatch()
+	updates.PubUpdates.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	updates.PubUpdates.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 2))
+	updates.PvtUpdates.Put("ns1", "coll1", "key1", []byte("pvtValue1"), version.NewHeight(1, 3))
+	updates.HashUpdates.Put("ns1", "coll1", util.ComputeStringHash("key1"), util.ComputeStringHash("pvtValue1"), version.NewHeight(1, 3))
+	assert.NoError(t, db.ApplyPrivacyAwareUpdates(updates, version.NewHeight(1, 3)))
+
+	// the first reads populate the cache and the following ones are served by the cache
+	for i := 0; i < 2; i++ {
+		vv, err := db.GetState("ns1", "key1")
+		assert.NoError(t, err)
+		assert.Equal(t, &statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)}, vv)
+		vv, err = db.GetState("ns1", "nonExistingKey")
+		assert.NoError(t, err)
+		assert.Nil(t, vv)
+		vv, err = db.GetPrivateData("ns1", "coll1", "key1")
+		assert.NoError(t, err)
+		assert.Equal(t, &statedb.VersionedValue{Value: []byte("pvtValue1"), Version: version.NewHeight(1, 3)}, vv)
+		ver, err := db.GetKeyHashVersion("ns1", "coll1", util.ComputeStringHash("key1"))
+		assert.NoError(t, err)
+		assert.Equal(t, version.NewHeight(1, 3), ver)
+	}
+	stats, enabled := commonStorageDB.GetStateCacheStats()
+	assert.True(t, enabled)
+	assert.Equal(t, uint64(4), stats.Hits)
+	assert.Equal(t, uint64(4), stats.Misses)
+	assert.Equal(t, 4, stats.Entries)
+
+	vals, err := db.GetStateMultipleKeys("ns1", []string{"key1", "key2", "key3"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*statedb.VersionedValue{
+		{Value: []byte("value1"), Version: version.NewHeight(1, 1)},
+		{Value: []byte("value2"), Version: version.NewHeight(1, 2)},
+		nil,
+	}, vals)
+	stats, _ = commonStorageDB.GetStateCacheStats()
+	assert.Equal(t, 6, stats.Entries)
+
+	// the db cannot be updated behind the back of the cache
+	pubUpdates := statedb.NewUpdateBatch()
+	pubUpdates.Put("ns1", "key1", []byte("value1_stale"), version.NewHeight(2, 1))
+	assert.Error(t, db.ApplyUpdates(pubUpdates, version.NewHeight(2, 1)))
+
+	// a commit updates the cached keys and reports the statistics of the cache
+	scope := &fakeScope{gauges: make(map[string]float64)}
+	commonStorageDB.metricsScope = func() metrics.Scope { return scope }
+	updates = NewUpdateBatch()
+	updates.PubUpdates.Put("ns1", "key1", []byte("value1_new"), version.NewHeight(2, 1))
+	updates.PubUpdates.Delete("ns1", "key2", version.NewHeight(2, 2))
+	updates.PubUpdates.Put("ns1", "nonExistingKey", []byte("newValue"), version.NewHeight(2, 3))
+	updates.PvtUpdates.Delete("ns1", "coll1", "key1", version.NewHeight(2, 4))
+	updates.HashUpdates.Delete("ns1", "coll1", util.ComputeStringHash("key1"), version.NewHeight(2, 4))
+	assert.NoError(t, db.ApplyPrivacyAwareUpdates(updates, version.NewHeight(2, 4)))
+	stats, _ = commonStorageDB.GetStateCacheStats()
+	assert.Equal(t, map[string]float64{
+		"ledger.state_cache.test-state-cache.hits":       5,
+		"ledger.state_cache.test-state-cache.misses":     6,
+		"ledger.state_cache.test-state-cache.hit_ratio":  5.0 / 11,
+		"ledger.state_cache.test-state-cache.entries":    6,
+		"ledger.state_cache.test-state-cache.size_bytes": float64(stats.Bytes),
+	}, scope.gauges)
+
+	vals, err = db.GetStateMultipleKeys("ns1", []string{"key1", "key2", "nonExistingKey"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*statedb.VersionedValue{
+		{Value: []byte("value1_new"), Version: version.NewHeight(2, 1)},
+		nil,
+		{Value: []byte("newValue"), Version: version.NewHeight(2, 3)},
+	}, vals)
+	vv, err := db.GetPrivateData("ns1", "coll1", "key1")
+	assert.NoError(t, err)
+	assert.Nil(t, vv)
+	ver, err := db.GetKeyHashVersion("ns1", "coll1", util.ComputeStringHash("key1"))
+	assert.NoError(t, err)
+	assert.Nil(t, ver)
+	stats, _ = commonStorageDB.GetStateCacheStats()
+	assert.Equal(t, uint64(4+1+5), stats.Hits)
+	assert.Equal(t, uint64(4+2), stats.Misses)
+}
+
+type fakeScope struct {
+	metrics.Scope
+	gauges map[string]float64
+	name   string
+}
+
+func (s *fakeScope) SubScope(name string) metrics.Scope {
+	return &fakeScope{gauges: s.gauges, name: s.name + name + "."}
+}
+
+func (s *fakeScope) Tagged(tags map[string]string) metrics.Scope {
+	return &fakeScope{gauges: s.gauges, name: s.name + tags["channel"] + "."}
+}
+
+func (s *fakeScope) Gauge(name string) metrics.Gauge {
+	return &fakeGauge{gauges: s.gauges, name: s.name + name}
+}
+
+type fakeGauge struct {
+	gauges map[string]float64
+	name   string
+}
+
+func (g *fakeGauge) Update(value float64) {
+	g.gauges[g.name] = value
+}
+
+func TestStateCacheDisabled(t *testing.T) {
+	viper.Set("ledger.state.cacheSize", 0)
+	testEnv := &LevelDBCommonStorageTestEnv{}
+	testEnv.Init(t)
+	defer testEnv.Cleanup()
+	db := testEnv.GetDBHandle("test-state-cache-disabled")
+	_, enabled := db.(*CommonStorageDB).GetStateCacheStats()
+	assert.False(t, enabled)
+}
+
+func TestStateCacheSkipsGoingToDB(t *testing.T) {
+	viper.Set("ledger.state.cacheSize", 1)
+	defer viper.Set("ledger.state.cacheSize", 0)
+	bookkeepingTestEnv := bookkeeping.NewTestEnv(t)
+	defer bookkeepingTestEnv.Cleanup()
+	bookkeeper := bookkeepingTestEnv.TestProvider.GetDBHandle("ledger1", bookkeeping.MetadataPresenceIndicator)
+
+	mockVersionedDB := &mock.VersionedDB{}
+	mockVersionedDB.GetStateReturns(&statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)}, nil)
+	db, err := NewCommonStorageDB(mockVersionedDB, "testledger", newMetadataHint(bookkeeper))
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		vv, err := db.GetState("ns1", "key1")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("value1"), vv.Value)
+		ver, err := db.GetVersion("ns1", "key1")
+		assert.NoError(t, err)
+		assert.Equal(t, version.NewHeight(1, 1), ver)
+	}
+	assert.Equal(t, 1, mockVersionedDB.GetStateCallCount())
+	assert.Equal(t, 0, mockVersionedDB.GetVersionCallCount())
+
+	// a failed commit clears the cache
+	mockVersionedDB.ApplyUpdatesReturns(errors.New("error applying updates"))
+	updates := NewUpdateBatch()
+	updates.PubUpdates.Put("ns1", "key1", []byte("value1_new"), version.NewHeight(2, 1))
+	assert.Error(t, db.ApplyPrivacyAwareUpdates(updates, version.NewHeight(2, 1)))
+	_, err = db.GetState("ns1", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, mockVersionedDB.GetStateCallCount())
+}
+
+func TestStateCacheConcurrentReadsAndCommits(t *testing.T) {
+	viper.Set("ledger.state.cacheSize", 1)
+	defer viper.Set("ledger.state.cacheSize", 0)
+	testEnv := &LevelDBCommonStorageTestEnv{}
+	testEnv.Init(t)
+	defer testEnv.Cleanup()
+	db := testEnv.GetDBHandle("test-state-cache-concurrency")
+
+	numBlocks := 50
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				vv, err := db.GetState("ns1", "key1")
+				assert.NoError(t, err)
+				if vv == nil {
+					continue
+				}
+				// the value is always the one committed along with the version
+				assert.Equal(t, []byte(fmt.Sprintf("value-%d", vv.Version.BlockNum)), vv.Value)
+			}
+		}()
+	}
+	for i := 1; i <= numBlocks; i++ {
+		updates := NewUpdateBatch()
+		updates.PubUpdates.Put("ns1", "key1", []byte(fmt.Sprintf("value-%d", i)), version.NewHeight(uint64(i), 0))
+		assert.NoError(t, db.ApplyPrivacyAwareUpdates(updates, version.NewHeight(uint64(i), 0)))
+	}
+	close(done)
+	wg.Wait()
+
+	vv, err := db.GetState("ns1", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(fmt.Sprintf("value-%d", numBlocks)), vv.Value)
+}
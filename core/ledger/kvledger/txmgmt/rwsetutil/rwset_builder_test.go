@@ -347,6 +347,42 @@ func constructTestPvtKVReadHash(t *testing.T, key string, version *version.Heigh
 	return kvReadHash
 }
 
+func TestTxSimulationResultWithPurge(t *testing.T) {
+	rwSetBuilder := NewRWSetBuilder()
+	rwSetBuilder.AddToPvtAndHashedWriteSet("ns1", "coll1", "key1", []byte("pvt-ns1-coll1-key1-value"))
+	rwSetBuilder.AddToPvtAndHashedWriteSetForPurge("ns1", "coll1", "key2")
+
+	actualSimRes, err := rwSetBuilder.GetTxSimulationResults()
+	assert.NoError(t, err)
+
+	pvtNs1Coll1 := &kvrwset.KVRWSet{
+		Writes: []*kvrwset.KVWrite{
+			newKVWrite("key1", []byte("pvt-ns1-coll1-key1-value")),
+			{Key: "key2", IsDelete: true},
+		},
+	}
+	assert.Equal(t, serializeTestProtoMsg(t, pvtNs1Coll1), actualSimRes.PvtSimulationResults.NsPvtRwset[0].CollectionPvtRwset[0].Rwset)
+
+	hashedNs1Coll1 := &kvrwset.HashedRWSet{
+		HashedWrites: []*kvrwset.KVWriteHash{
+			constructTestPvtKVWriteHash(t, "key1", []byte("pvt-ns1-coll1-key1-value")),
+			{KeyHash: util.ComputeStringHash("key2"), IsDelete: true, IsPurge: true},
+		},
+	}
+	expectedNs1 := &rwset.NsReadWriteSet{
+		Namespace: "ns1",
+		Rwset:     serializeTestProtoMsg(t, &kvrwset.KVRWSet{}),
+		CollectionHashedRwset: []*rwset.CollectionHashedReadWriteSet{
+			{
+				CollectionName: "coll1",
+				HashedRwset:    serializeTestProtoMsg(t, hashedNs1Coll1),
+				PvtRwsetHash:   util.ComputeHash(serializeTestProtoMsg(t, pvtNs1Coll1)),
+			},
+		},
+	}
+	assert.Equal(t, expectedNs1, actualSimRes.PubSimulationResults.NsRwset[0])
+}
+
 func constructTestPvtKVWriteHash(t *testing.T, key string, value []byte) *kvrwset.KVWriteHash {
 	_, kvWriteHash := newPvtKVWriteAndHash(key, value)
 	return kvWriteHash
@@ -0,0 +1,154 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statedb
+
+import (
+	"container/list"
+	"sync"
+)
+
+// cacheEntryOverhead is the approximate number of bytes, in addition to the keys, the value and the metadata,
+// consumed by an entry of the cache
+const cacheEntryOverhead = 128
+
+// Cache is a bounded LRU cache of the committed state of a channel. The entries are keyed by
+// namespace and key and hold the committed VersionedValue, or nil for a key that is known not
+// to exist in the state. The size of the cache is bounded by an approximate number of bytes.
+// Cache is safe for concurrent use
+type Cache struct {
+	mux      sync.Mutex
+	maxBytes int
+	bytes    int
+	lru      *list.List
+	entries  map[CompositeKey]*list.Element
+	hits     uint64
+	misses   uint64
+}
+
+type cacheEntry struct {
+	key   CompositeKey
+	value *VersionedValue
+	size  int
+}
+
+// CacheStats contains the statistics of a Cache
+type CacheStats struct {
+	Hits    uint64
+	Misses  uint64
+	Entries int
+	Bytes   int
+}
+
+// HitRatio returns the ratio of the lookups that were served by the cache
+func (s CacheStats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// NewCache constructs a Cache that holds up to approximately maxBytes bytes
+func NewCache(maxBytes int) *Cache {
+	return &Cache{
+		maxBytes: maxBytes,
+		lru:      list.New(),
+		entries:  make(map[CompositeKey]*list.Element),
+	}
+}
+
+// Get returns the cached value for the given namespace and key. The returned boolean is false on a cache miss.
+// On a cache hit, a nil value indicates that the key does not exist in the state
+func (c *Cache) Get(namespace, key string) (*VersionedValue, bool) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	elem, ok := c.entries[CompositeKey{namespace, key}]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).value, true
+}
+
+// Put adds the given value to the cache, evicting the least recently used entries if required.
+// A nil value records that the key does not exist in the state
+func (c *Cache) Put(namespace, key string, value *VersionedValue) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.put(CompositeKey{namespace, key}, value)
+}
+
+// UpdateBatch brings the cache in line with the state after the commit of the given batch. The keys that are
+// present in the cache get the value from the batch (the deleted keys are cached as not existing) and the
+// keys that are not in the cache are left out so that a commit does not evict the frequently read keys
+func (c *Cache) UpdateBatch(batch *UpdateBatch) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	for _, ns := range batch.GetUpdatedNamespaces() {
+		for key, vv := range batch.GetUpdates(ns) {
+			compositeKey := CompositeKey{ns, key}
+			if _, ok := c.entries[compositeKey]; !ok {
+				continue
+			}
+			if vv.IsDelete() {
+				vv = nil
+			}
+			c.put(compositeKey, vv)
+		}
+	}
+}
+
+// Clear removes all the entries from the cache
+func (c *Cache) Clear() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.lru.Init()
+	c.entries = make(map[CompositeKey]*list.Element)
+	c.bytes = 0
+}
+
+// Stats returns the statistics of the cache
+func (c *Cache) Stats() CacheStats {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return CacheStats{
+		Hits:    c.hits,
+		Misses:  c.misses,
+		Entries: c.lru.Len(),
+		Bytes:   c.bytes,
+	}
+}
+
+func (c *Cache) put(key CompositeKey, value *VersionedValue) {
+	size := cacheEntrySize(key, value)
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	if size > c.maxBytes {
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key, value, size})
+	c.bytes += size
+	for c.bytes > c.maxBytes {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *Cache) remove(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*cacheEntry)
+	delete(c.entries, entry.key)
+	c.bytes -= entry.size
+}
+
+func cacheEntrySize(key CompositeKey, value *VersionedValue) int {
+	size := len(key.Namespace) + len(key.Key) + cacheEntryOverhead
+	if value != nil {
+		size += len(value.Value) + len(value.Metadata)
+	}
+	return size
+}
@@ -0,0 +1,99 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statedb
+
+import (
+	"testing"
+
+	"justledger/core/ledger/kvledger/txmgmt/version"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheGetPut(t *testing.T) {
+	cache := NewCache(1024 * 1024)
+	_, ok := cache.Get("ns1", "key1")
+	assert.False(t, ok)
+
+	vv := &VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)}
+	cache.Put("ns1", "key1", vv)
+	cache.Put("ns1", "key2", nil)
+	cachedVV, ok := cache.Get("ns1", "key1")
+	assert.True(t, ok)
+	assert.Equal(t, vv, cachedVV)
+	cachedVV, ok = cache.Get("ns1", "key2")
+	assert.True(t, ok)
+	assert.Nil(t, cachedVV)
+	_, ok = cache.Get("ns2", "key1")
+	assert.False(t, ok)
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(2), stats.Misses)
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, 0.5, stats.HitRatio())
+	assert.Equal(t, 0.0, CacheStats{}.HitRatio())
+
+	cache.Clear()
+	_, ok = cache.Get("ns1", "key1")
+	assert.False(t, ok)
+	assert.Equal(t, 0, cache.Stats().Entries)
+	assert.Equal(t, 0, cache.Stats().Bytes)
+}
+
+func TestCacheEviction(t *testing.T) {
+	value := make([]byte, 100)
+	entrySize := cacheEntrySize(CompositeKey{"ns", "key0"}, &VersionedValue{Value: value})
+	cache := NewCache(3 * entrySize)
+	for _, key := range []string{"key0", "key1", "key2"} {
+		cache.Put("ns", key, &VersionedValue{Value: value})
+	}
+	// key0 becomes the most recently used
+	_, ok := cache.Get("ns", "key0")
+	assert.True(t, ok)
+	cache.Put("ns", "key3", &VersionedValue{Value: value})
+
+	_, ok = cache.Get("ns", "key1")
+	assert.False(t, ok)
+	for _, key := range []string{"key0", "key2", "key3"} {
+		_, ok = cache.Get("ns", key)
+		assert.True(t, ok, key)
+	}
+	assert.Equal(t, 3*entrySize, cache.Stats().Bytes)
+
+	// a value larger than the cache is not cached
+	cache.Put("ns", "key4", &VersionedValue{Value: make([]byte, 4*entrySize)})
+	_, ok = cache.Get("ns", "key4")
+	assert.False(t, ok)
+	assert.Equal(t, 3, cache.Stats().Entries)
+}
+
+func TestCacheUpdateBatch(t *testing.T) {
+	cache := NewCache(1024 * 1024)
+	cache.Put("ns1", "key1", &VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)})
+	cache.Put("ns1", "key2", &VersionedValue{Value: []byte("value2"), Version: version.NewHeight(1, 2)})
+	cache.Put("ns1", "key3", nil)
+
+	batch := NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1_new"), version.NewHeight(2, 1))
+	batch.Delete("ns1", "key2", version.NewHeight(2, 2))
+	batch.PutValAndMetadata("ns1", "key3", []byte("value3"), []byte("metadata3"), version.NewHeight(2, 3))
+	batch.Put("ns1", "key4", []byte("value4"), version.NewHeight(2, 4))
+	cache.UpdateBatch(batch)
+
+	vv, ok := cache.Get("ns1", "key1")
+	assert.True(t, ok)
+	assert.Equal(t, &VersionedValue{Value: []byte("value1_new"), Version: version.NewHeight(2, 1)}, vv)
+	vv, ok = cache.Get("ns1", "key2")
+	assert.True(t, ok)
+	assert.Nil(t, vv)
+	vv, ok = cache.Get("ns1", "key3")
+	assert.True(t, ok)
+	assert.Equal(t, &VersionedValue{Value: []byte("value3"), Metadata: []byte("metadata3"), Version: version.NewHeight(2, 3)}, vv)
+	// the keys that were not cached are not added by a commit
+	_, ok = cache.Get("ns1", "key4")
+	assert.False(t, ok)
+}
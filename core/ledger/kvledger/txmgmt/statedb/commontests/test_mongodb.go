@@ -23,15 +23,15 @@ import (
 
 	"encoding/json"
 
-	"justledger/common/ledger/testutil"
 	"justledger/common/ledger/util/mongodbhelper"
 	"justledger/core/ledger/kvledger/txmgmt/statedb"
 	"justledger/core/ledger/kvledger/txmgmt/version"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestMongoQuery(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 	db, err := dbProvider.GetDBHandle("testquery")
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 	db.Open()
 	defer db.Close()
 	batch := statedb.NewUpdateBatch()
@@ -75,201 +75,201 @@ func TestMongoQuery(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 
 	// query for owner=jerry, use namespace "ns1"
 	itr, err := db.ExecuteQuery("ns1", "{\"query\":{\"owner\":\"jerry\"}}")
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 
 	// verify one jerry result
 	queryResult1, err := itr.Next()
-	testutil.AssertNoError(t, err, "")
-	testutil.AssertNotNil(t, queryResult1)
+	assert.NoError(t, err)
+	assert.NotNil(t, queryResult1)
 	versionedQueryRecord := queryResult1.(*statedb.VersionedKV)
 	stringRecord := string(versionedQueryRecord.Value)
 	bFoundRecord := strings.Contains(stringRecord, "jerry")
-	testutil.AssertEquals(t, bFoundRecord, true)
+	assert.True(t, bFoundRecord)
 
 	// verify no more results
 	queryResult2, err := itr.Next()
-	testutil.AssertNoError(t, err, "")
-	testutil.AssertNil(t, queryResult2)
+	assert.NoError(t, err)
+	assert.Nil(t, queryResult2)
 
 	// query for owner=jerry, use namespace "ns2"
 	itr, err = db.ExecuteQuery("ns2", "{\"query\":{\"owner\":\"jerry\"}}")
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 
 	// verify one jerry result
 	queryResult1, err = itr.Next()
-	testutil.AssertNoError(t, err, "")
-	testutil.AssertNotNil(t, queryResult1)
+	assert.NoError(t, err)
+	assert.NotNil(t, queryResult1)
 	versionedQueryRecord = queryResult1.(*statedb.VersionedKV)
 	stringRecord = string(versionedQueryRecord.Value)
 	bFoundRecord = strings.Contains(stringRecord, "jerry")
-	testutil.AssertEquals(t, bFoundRecord, true)
+	assert.True(t, bFoundRecord)
 
 	// verify no more results
 	queryResult2, err = itr.Next()
-	testutil.AssertNoError(t, err, "")
-	testutil.AssertNil(t, queryResult2)
+	assert.NoError(t, err)
+	assert.Nil(t, queryResult2)
 
 	// query for owner=jerry, use namespace "ns3"
 	itr, err = db.ExecuteQuery("ns3", "{\"query\":{\"owner\":\"jerry\"}}")
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 
 	// verify results - should be no records
 	queryResult1, err = itr.Next()
-	testutil.AssertNoError(t, err, "")
-	testutil.AssertNil(t, queryResult1)
+	assert.NoError(t, err)
+	assert.Nil(t, queryResult1)
 
 	// query using bad query string
 	itr, err = db.ExecuteQuery("ns1", "this is an invalid query string")
-	testutil.AssertError(t, err, "Should have received an error for invalid query string")
+	assert.Error(t, err, "Should have received an error for invalid query string")
 
 	// query returns 0 records
 	itr, err = db.ExecuteQuery("ns1", "{\"query\":{\"owner\":\"not_a_valid_name\"}}")
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 
 	// verify no results
 	queryResult3, err := itr.Next()
-	testutil.AssertNoError(t, err, "")
-	testutil.AssertNil(t, queryResult3)
+	assert.NoError(t, err)
+	assert.Nil(t, queryResult3)
 
 	// query with complex selector, namespace "ns1"
 	itr, err = db.ExecuteQuery("ns1", "{\"query\":{\"$and\":[{\"size\":{\"$gt\": 5}},{\"size\":{\"$lt\":8}},{\"size\":{\"$not\":{\"$eq\":6}}}]}}")
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 
 	// verify one fred result
 	queryResult1, err = itr.Next()
-	testutil.AssertNoError(t, err, "")
-	testutil.AssertNotNil(t, queryResult1)
+	assert.NoError(t, err)
+	assert.NotNil(t, queryResult1)
 	versionedQueryRecord = queryResult1.(*statedb.VersionedKV)
 	stringRecord = string(versionedQueryRecord.Value)
 	bFoundRecord = strings.Contains(stringRecord, "fred")
-	testutil.AssertEquals(t, bFoundRecord, true)
+	assert.True(t, bFoundRecord)
 
 	// verify no more results
 	queryResult2, err = itr.Next()
-	testutil.AssertNoError(t, err, "")
-	testutil.AssertNil(t, queryResult2)
+	assert.NoError(t, err)
+	assert.Nil(t, queryResult2)
 
 	// query with complex selector, namespace "ns2"
 	itr, err = db.ExecuteQuery("ns2", "{\"query\":{\"$and\":[{\"size\":{\"$gt\": 5}},{\"size\":{\"$lt\":8}},{\"size\":{\"$not\":{\"$eq\":6}}}]}}")
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 
 	// verify one fred result
 	queryResult1, err = itr.Next()
-	testutil.AssertNoError(t, err, "")
-	testutil.AssertNotNil(t, queryResult1)
+	assert.NoError(t, err)
+	assert.NotNil(t, queryResult1)
 	versionedQueryRecord = queryResult1.(*statedb.VersionedKV)
 	stringRecord = string(versionedQueryRecord.Value)
 	bFoundRecord = strings.Contains(stringRecord, "fred")
-	testutil.AssertEquals(t, bFoundRecord, true)
+	assert.True(t, bFoundRecord)
 
 	// verify no more results
 	queryResult2, err = itr.Next()
-	testutil.AssertNoError(t, err, "")
-	testutil.AssertNil(t, queryResult2)
+	assert.NoError(t, err)
+	assert.Nil(t, queryResult2)
 
 	// query with complex selector, namespace "ns3"
 	itr, err = db.ExecuteQuery("ns3", "{\"query\":{\"$and\":[{\"size\":{\"$gt\": 5}},{\"size\":{\"$lt\":8}},{\"size\":{\"$not\":{\"$eq\":6}}}]}}")
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 
 	// verify no more results
 	queryResult1, err = itr.Next()
-	testutil.AssertNoError(t, err, "")
-	testutil.AssertNil(t, queryResult1)
+	assert.NoError(t, err)
+	assert.Nil(t, queryResult1)
 
 	// query with embedded implicit "AND" and explicit "OR", namespace "ns1"
 	itr, err = db.ExecuteQuery("ns1", "{\"query\":{\"color\":\"green\",\"$or\":[{\"owner\":\"fred\"},{\"owner\":\"mary\"}]}}")
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 
 	// verify one green result
 	queryResult1, err = itr.Next()
-	testutil.AssertNoError(t, err, "")
-	testutil.AssertNotNil(t, queryResult1)
+	assert.NoError(t, err)
+	assert.NotNil(t, queryResult1)
 	versionedQueryRecord = queryResult1.(*statedb.VersionedKV)
 	stringRecord = string(versionedQueryRecord.Value)
 	bFoundRecord = strings.Contains(stringRecord, "green")
-	testutil.AssertEquals(t, bFoundRecord, true)
+	assert.True(t, bFoundRecord)
 
 	// verify another green result
 	queryResult2, err = itr.Next()
-	testutil.AssertNoError(t, err, "")
-	testutil.AssertNotNil(t, queryResult2)
+	assert.NoError(t, err)
+	assert.NotNil(t, queryResult2)
 	versionedQueryRecord = queryResult2.(*statedb.VersionedKV)
 	stringRecord = string(versionedQueryRecord.Value)
 	bFoundRecord = strings.Contains(stringRecord, "green")
-	testutil.AssertEquals(t, bFoundRecord, true)
+	assert.True(t, bFoundRecord)
 
 	// verify no more results
 	queryResult3, err = itr.Next()
-	testutil.AssertNoError(t, err, "")
-	testutil.AssertNil(t, queryResult3)
+	assert.NoError(t, err)
+	assert.Nil(t, queryResult3)
 
 	// query with embedded implicit "AND" and explicit "OR", namespace "ns2"
 	itr, err = db.ExecuteQuery("ns2", "{\"query\":{\"color\":\"green\",\"$or\":[{\"owner\":\"fred\"},{\"owner\":\"mary\"}]}}")
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 
 	// verify one green result
 	queryResult1, err = itr.Next()
-	testutil.AssertNoError(t, err, "")
-	testutil.AssertNotNil(t, queryResult1)
+	assert.NoError(t, err)
+	assert.NotNil(t, queryResult1)
 	versionedQueryRecord = queryResult1.(*statedb.VersionedKV)
 	stringRecord = string(versionedQueryRecord.Value)
 	bFoundRecord = strings.Contains(stringRecord, "green")
-	testutil.AssertEquals(t, bFoundRecord, true)
+	assert.True(t, bFoundRecord)
 
 	// verify another green result
 	queryResult2, err = itr.Next()
-	testutil.AssertNoError(t, err, "")
-	testutil.AssertNotNil(t, queryResult2)
+	assert.NoError(t, err)
+	assert.NotNil(t, queryResult2)
 	versionedQueryRecord = queryResult2.(*statedb.VersionedKV)
 	stringRecord = string(versionedQueryRecord.Value)
 	bFoundRecord = strings.Contains(stringRecord, "green")
-	testutil.AssertEquals(t, bFoundRecord, true)
+	assert.True(t, bFoundRecord)
 
 	// verify no more results
 	queryResult3, err = itr.Next()
-	testutil.AssertNoError(t, err, "")
-	testutil.AssertNil(t, queryResult3)
+	assert.NoError(t, err)
+	assert.Nil(t, queryResult3)
 
 	// query with embedded implicit "AND" and explicit "OR", namespace "ns3"
 	itr, err = db.ExecuteQuery("ns3", "{\"query\":{\"color\":\"green\",\"$or\":[{\"owner\":\"fred\"},{\"owner\":\"mary\"}]}}")
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 
 	// verify no results
 	queryResult1, err = itr.Next()
-	testutil.AssertNoError(t, err, "")
-	testutil.AssertNil(t, queryResult1)
+	assert.NoError(t, err)
+	assert.Nil(t, queryResult1)
 
 	// query with integer with digit-count equals 7 and response received is also received
 	// with same digit-count and there is no float transformation
 	itr, err = db.ExecuteQuery("ns1", "{\"query\":{\"$and\":[{\"size\":{\"$eq\": 1000007}}]}}")
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 
 	// verify one jerry result
 	queryResult1, err = itr.Next()
-	testutil.AssertNoError(t, err, "")
-	testutil.AssertNotNil(t, queryResult1)
+	assert.NoError(t, err)
+	assert.NotNil(t, queryResult1)
 	versionedQueryRecord = queryResult1.(*statedb.VersionedKV)
 	stringRecord = string(versionedQueryRecord.Value)
 	bFoundRecord = strings.Contains(stringRecord, "joe")
-	testutil.AssertEquals(t, bFoundRecord, true)
+	assert.True(t, bFoundRecord)
 	bFoundRecord = strings.Contains(stringRecord, "1000007")
-	testutil.AssertEquals(t, bFoundRecord, true)
+	assert.True(t, bFoundRecord)
 
 	// verify no more results
 	queryResult2, err = itr.Next()
-	testutil.AssertNoError(t, err, "")
-	testutil.AssertNil(t, queryResult2)
+	assert.NoError(t, err)
+	assert.Nil(t, queryResult2)
 }
 
 func testInsert(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 	db, err := dbProvider.GetDBHandle("testpaging")
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 
 	db.Open()
 	defer db.Close()
 
 	err = insertMultiData(db)
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 }
 
 func insertMultiData(db statedb.VersionedDB) error {
@@ -291,24 +291,24 @@ func TestPaingQuery(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 	testInsert(t, dbProvider)
 
 	db, err := dbProvider.GetDBHandle("testpaging")
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 
 	db.Open()
 	defer db.Close()
 
 	firstQuery := "{\"query\":{\"color\":\"blue\"},\"pagingInfo\":{\"currentPageNum\":1,\"pageSize\":30}}"
 	resItr, err := db.ExecuteQuery("ns1", firstQuery)
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 
 	paging, err := resItr.Next()
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 	pagingV := paging.(*statedb.VersionedKV)
 
 	var pagingDoc mongodbhelper.PagingDoc
 	err = json.Unmarshal(pagingV.Value, &pagingDoc)
-	testutil.AssertNoError(t, err, "")
-	testutil.AssertEquals(t, pagingDoc.ReturnPageResult.TotalPage, 401)
-	testutil.AssertEquals(t, pagingDoc.ReturnPageResult.TotalCount, 12001)
+	assert.NoError(t, err)
+	assert.Equal(t, 401, pagingDoc.ReturnPageResult.TotalPage)
+	assert.Equal(t, 12001, pagingDoc.ReturnPageResult.TotalCount)
 
 }
 
@@ -317,13 +317,13 @@ func TestExecuteQueryPaging(t *testing.T, dbProvider statedb.VersionedDBProvider
 	testInsert(t, dbProvider)
 
 	db, err := dbProvider.GetDBHandle("testpaging")
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 
 	db.Open()
 	defer db.Close()
 
 	queryOrPaging := "{\"query\":{\"age\":\"12\"}}"
 	resItr, err := db.ExecuteQuery("ns1", queryOrPaging)
-	testutil.AssertNoError(t, err, "")
+	assert.NoError(t, err)
 	_, err = resItr.Next()
 }
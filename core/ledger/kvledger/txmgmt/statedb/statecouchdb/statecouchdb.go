@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 
 	"justledger/common/flogging"
@@ -67,6 +68,57 @@ func (provider *VersionedDBProvider) Close() {
 	// No close needed on Couch
 }
 
+// Drop drops the metadata database and the namespace databases of the given ledger
+func (provider *VersionedDBProvider) Drop(dbName string) error {
+	provider.mux.Lock()
+	defer provider.mux.Unlock()
+	if err := dropLedgerDBs(provider.couchInstance, dbName); err != nil {
+		return err
+	}
+	delete(provider.databases, dbName)
+	return nil
+}
+
+// DropLedgerDBs drops the metadata database and the namespace databases of the given ledgers
+// so that their state gets rebuilt from the block store when the ledgers are opened next time
+func DropLedgerDBs(ledgerIDs []string) error {
+	couchDBDef := couchdb.GetCouchDBDefinition()
+	couchInstance, err := couchdb.CreateCouchInstance(couchDBDef.URL, couchDBDef.Username, couchDBDef.Password,
+		couchDBDef.MaxRetries, couchDBDef.MaxRetriesOnStartup, couchDBDef.RequestTimeout, couchDBDef.CreateGlobalChangesDB)
+	if err != nil {
+		return err
+	}
+	for _, ledgerID := range ledgerIDs {
+		if err := dropLedgerDBs(couchInstance, ledgerID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dropLedgerDBs(couchInstance *couchdb.CouchInstance, ledgerID string) error {
+	dbNames, err := couchInstance.RetrieveApplicationDBNames()
+	if err != nil {
+		return err
+	}
+	metadataDB, err := couchdb.NewCouchDatabase(couchInstance, couchdb.ConstructMetadataDBName(ledgerID))
+	if err != nil {
+		return err
+	}
+	// the metadata database is named '<ledgerID>_' and the namespace databases '<ledgerID>_<namespace>'
+	for _, dbName := range dbNames {
+		if !strings.HasPrefix(dbName, metadataDB.DBName) {
+			continue
+		}
+		logger.Infof("Dropping CouchDB database [%s] of ledger [%s]", dbName, ledgerID)
+		db := &couchdb.CouchDatabase{CouchInstance: couchInstance, DBName: dbName}
+		if _, err := db.DropDatabase(); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("error dropping CouchDB database [%s]", dbName))
+		}
+	}
+	return nil
+}
+
 // VersionedDB implements VersionedDB interface
 type VersionedDB struct {
 	couchInstance      *couchdb.CouchInstance
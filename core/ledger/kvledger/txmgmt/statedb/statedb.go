@@ -22,6 +22,8 @@ import (
 type VersionedDBProvider interface {
 	// GetDBHandle returns a handle to a VersionedDB
 	GetDBHandle(id string) (VersionedDB, error)
+	// Drop deletes the VersionedDB with the given id and all of its data
+	Drop(id string) error
 	// Close closes all the VersionedDB instances and releases any resources held by VersionedDBProvider
 	Close()
 }
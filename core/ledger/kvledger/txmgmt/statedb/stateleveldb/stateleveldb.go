@@ -41,6 +41,11 @@ func (provider *VersionedDBProvider) GetDBHandle(dbName string) (statedb.Version
 	return newVersionedDB(provider.dbProvider.GetDBHandle(dbName), dbName), nil
 }
 
+// Drop deletes all the data of the named database
+func (provider *VersionedDBProvider) Drop(dbName string) error {
+	return provider.dbProvider.GetDBHandle(dbName).DeleteAll()
+}
+
 // Close closes the underlying db
 func (provider *VersionedDBProvider) Close() {
 	provider.dbProvider.Close()
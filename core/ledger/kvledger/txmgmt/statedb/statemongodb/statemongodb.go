@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"gopkg.in/mgo.v2"
+	"strings"
 	"sync"
 	"unicode/utf8"
 
@@ -47,23 +48,18 @@ type VersionedDBProvider struct {
 }
 
 type VersionedDB struct {
-	mongoDB *mongodbhelper.MongoDB
-	dbName  string
+	mongoDB            *mongodbhelper.MongoDB
+	dbName             string
+	committedDataCache *versionsCache // Used as a local cache during bulk processing of a block.
+	verCacheLock       sync.RWMutex
 }
 
 func NewVersionedDBProvider() (*VersionedDBProvider, error) {
 
 	logger.Debugf("constructing MongoDB VersionedDBProvider")
 
-	mongodbConf := mongodbhelper.GetMongoDBConf()
-	dialInfo, err := mgo.ParseURL(mongodbConf.Url)
-	if err != nil {
-		return nil, err
-	}
-	dialInfo.Timeout = mongodbConf.RequestTimeout
-	mgoSession, err := mgo.DialWithInfo(dialInfo)
+	mgoSession, err := dialMongoDB()
 	if err != nil {
-		logger.Errorf(err.Error())
 		return nil, err
 	}
 
@@ -88,14 +84,61 @@ func (provider *VersionedDBProvider) GetDBHandle(dbName string) (statedb.Version
 	if err != nil {
 		return nil, err
 	}
+	provider.databases[dbName] = vdr
 
 	return vdr, nil
 }
 
+// Drop drops the MongoDB database of the given ledger
+func (provider *VersionedDBProvider) Drop(dbName string) error {
+	provider.mux.Lock()
+	defer provider.mux.Unlock()
+
+	logger.Infof("Dropping MongoDB database of ledger [%s]", dbName)
+	if err := provider.session.DB(dbName).DropDatabase(); err != nil {
+		return errors.Wrapf(err, "error dropping MongoDB database [%s]", dbName)
+	}
+	delete(provider.databases, dbName)
+	return nil
+}
+
 func (provider *VersionedDBProvider) Close() {
 	provider.session.Close()
 }
 
+// DropLedgerDBs drops the databases of the given ledgers so that their state
+// gets rebuilt from the block store when the ledgers are opened next time
+func DropLedgerDBs(ledgerIDs []string) error {
+	mgoSession, err := dialMongoDB()
+	if err != nil {
+		return err
+	}
+	defer mgoSession.Close()
+
+	for _, ledgerID := range ledgerIDs {
+		logger.Infof("Dropping MongoDB database of ledger [%s]", ledgerID)
+		if err := mgoSession.DB(ledgerID).DropDatabase(); err != nil {
+			return errors.Wrapf(err, "error dropping MongoDB database [%s]", ledgerID)
+		}
+	}
+	return nil
+}
+
+func dialMongoDB() (*mgo.Session, error) {
+	mongodbConf := mongodbhelper.GetMongoDBConf()
+	dialInfo, err := mgo.ParseURL(mongodbConf.Url)
+	if err != nil {
+		return nil, err
+	}
+	dialInfo.Timeout = mongodbConf.RequestTimeout
+	mgoSession, err := mgo.DialWithInfo(dialInfo)
+	if err != nil {
+		logger.Errorf(err.Error())
+		return nil, err
+	}
+	return mgoSession, nil
+}
+
 // GetState implements method in VersionedDB interface
 func (vdb *VersionedDB) GetState(namespace string, key string) (*statedb.VersionedValue, error) {
 	logger.Debugf("GetState(). ns=%s, key=%s", namespace, key)
@@ -119,8 +162,13 @@ func (vdb *VersionedDB) GetState(namespace string, key string) (*statedb.Version
 }
 
 // GetVersion implements method in VersionedDB interface
-//TODO support GetVersion
 func (vdb *VersionedDB) GetVersion(namespace string, key string) (*version.Height, error) {
+	returnVersion, keyFound := vdb.GetCachedVersion(namespace, key)
+	if keyFound {
+		return returnVersion, nil
+	}
+	// This is reached only during simulation because during commit
+	// `LoadCommittedVersions` is always called before calling `GetVersion`
 	versionedValue, err := vdb.GetState(namespace, key)
 	if err != nil {
 		return nil, err
@@ -131,38 +179,114 @@ func (vdb *VersionedDB) GetVersion(namespace string, key string) (*version.Heigh
 	return versionedValue.Version, nil
 }
 
-// Add "value." to all keys
+// LoadCommittedVersions populates the committed versions of the given keys into the cache.
+// The versions are retrieved with one `$in` query per namespace and are used for the
+// state validation of the readsets of a block
+func (vdb *VersionedDB) LoadCommittedVersions(keys []*statedb.CompositeKey) error {
+	nsKeysMap := map[string][]string{}
+	committedDataCache := newVersionCache()
+	for _, compositeKey := range keys {
+		ns, key := compositeKey.Namespace, compositeKey.Key
+		if _, ok := committedDataCache.getVersion(ns, key); ok {
+			continue
+		}
+		committedDataCache.setVer(ns, key, nil)
+		logger.Debugf("Load into version cache: %s~%s", ns, key)
+		nsKeysMap[ns] = append(nsKeysMap[ns], key)
+	}
+	for ns, nsKeys := range nsKeysMap {
+		nsVersions, err := vdb.mongoDB.GetVersions(ns, nsKeys)
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("error loading the committed versions of namespace [%s]", ns))
+		}
+		for key, ver := range nsVersions {
+			committedDataCache.setVer(ns, key, ver)
+		}
+	}
+	vdb.verCacheLock.Lock()
+	defer vdb.verCacheLock.Unlock()
+	vdb.committedDataCache = committedDataCache
+	return nil
+}
+
+// GetCachedVersion returns version from cache. `LoadCommittedVersions` function populates the cache
+func (vdb *VersionedDB) GetCachedVersion(namespace string, key string) (*version.Height, bool) {
+	logger.Debugf("Retrieving cached version: %s~%s", key, namespace)
+	vdb.verCacheLock.RLock()
+	defer vdb.verCacheLock.RUnlock()
+	return vdb.committedDataCache.getVersion(namespace, key)
+}
+
+// ClearCachedVersions clears the committed versions cache
+func (vdb *VersionedDB) ClearCachedVersions() {
+	logger.Debugf("Clear Cache")
+	vdb.verCacheLock.Lock()
+	defer vdb.verCacheLock.Unlock()
+	vdb.committedDataCache = newVersionCache()
+}
+
+// Add "value." to the key
 func processKey(key string) string {
 	return fmt.Sprintf("%v.%v", dataWrapper, key)
 }
 
-// traverse through the value
-func wrapIndex(index mgo.Index) {
-	for i, key := range index.Key {
-		wrapKey := processKey(key)
-		index.Key[i] = wrapKey
+// wrapIndexKey adds "value." to the field of the given index key while preserving
+// the index type prefixes understood by mgo, such as "-" or "$text:"
+func wrapIndexKey(key string) string {
+	prefix := ""
+	switch {
+	case strings.HasPrefix(key, "$"):
+		if i := strings.Index(key, ":"); i >= 0 {
+			prefix, key = key[:i+1], key[i+1:]
+		}
+	case strings.HasPrefix(key, "-"), strings.HasPrefix(key, "+"), strings.HasPrefix(key, "@"):
+		prefix, key = key[:1], key[1:]
+	}
+	return prefix + processKey(key)
+}
+
+// buildNamespaceIndex constructs the index for the given namespace from an index definition.
+// The docs of all the namespaces are stored in a single collection and the queries always filter
+// by namespace, so the namespace is prepended to the keys of the index. The name of the index,
+// if any, is qualified with the namespace so that the indexes of different chaincodes do not clash
+func buildNamespaceIndex(namespace string, indexDefinition []byte) (mgo.Index, error) {
+	index := mgo.Index{}
+	if err := json.Unmarshal(indexDefinition, &index); err != nil {
+		return index, errors.Wrap(err, "invalid index definition")
+	}
+	if len(index.Key) == 0 {
+		return index, errors.New("invalid index definition: no keys provided")
 	}
+	keys := []string{mongodbhelper.NS}
+	for _, key := range index.Key {
+		keys = append(keys, wrapIndexKey(key))
+	}
+	index.Key = keys
+	if index.Name != "" {
+		index.Name = namespace + "_" + index.Name
+	}
+	return index, nil
 }
 
 // ProcessIndexesForChaincodeDeploy creates indexes for a specified namespace
 func (vdb *VersionedDB) ProcessIndexesForChaincodeDeploy(namespace string, fileEntries []*ccprovider.TarFileEntry) error {
 	c := vdb.mongoDB.GetDefaultCollection()
 	for _, fileEntry := range fileEntries {
-		indexData := fileEntry.FileContent
-		index := mgo.Index{}
-		json.Unmarshal(indexData, &index)
-		//TODO warpper
-		wrapIndex(index)
 		filename := fileEntry.FileHeader.Name
-		err := vdb.mongoDB.BuildIndex(index, *c)
+		index, err := buildNamespaceIndex(namespace, fileEntry.FileContent)
+		if err == nil {
+			err = vdb.mongoDB.BuildIndex(index, *c)
+		}
 		if err != nil {
-			return fmt.Errorf("error during creation of index from file=[%s] for chain=[%s]. Error=%s",
-				filename, namespace, err)
+			return errors.WithMessage(err, fmt.Sprintf(
+				"error creating index from file [%s] for namespace [%s]", filename, namespace))
 		}
 	}
 	return nil
 }
 
+// GetDBType returns the type of the database, which selects the index definitions
+// of the chaincode packages under META-INF/statedb/mongodb
 func (vdb *VersionedDB) GetDBType() string {
 	return "mongodb"
 }
@@ -206,7 +330,7 @@ func (vdb *VersionedDB) ExecuteQuery(namespace, queryOrPaingStr string) (statedb
 	paingOrQuery := &mongodbhelper.PagingOrQuery{}
 	err := json.Unmarshal(queryByte, paingOrQuery)
 	if err != nil {
-		return nil, fmt.Errorf("the queryOrPaingStr string is not a pagingOrQuery json string:" + err.Error())
+		return nil, fmt.Errorf("the queryOrPaingStr string is not a pagingOrQuery json string:%s", err)
 	}
 
 	pagingInfo := paingOrQuery.PagingInfo
@@ -230,7 +354,7 @@ func (vdb *VersionedDB) ExecuteQueryWithMetadata(namespace, query string, metada
 }
 
 // ApplyUpdates implements method in VersionedDB interface
-func (vdb VersionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version.Height) error {
+func (vdb *VersionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version.Height) error {
 	namespaces := batch.GetUpdatedNamespaces()
 	var out interface{}
 	var err error
@@ -478,7 +602,7 @@ func newVersionDB(mgoSession *mgo.Session, dbName string) (*VersionedDB, error)
 	db := mgoSession.DB(dbName)
 	conf := mongodbhelper.GetMongoDBConf()
 	conf.DBName = dbName
-	MongoDB := &mongodbhelper.MongoDB{db, conf}
+	MongoDB := &mongodbhelper.MongoDB{Db: db, Conf: conf}
 
 	collectionsName, err := db.CollectionNames()
 	if err != nil {
@@ -499,7 +623,7 @@ func newVersionDB(mgoSession *mgo.Session, dbName string) (*VersionedDB, error)
 		return nil, err
 	}
 
-	return &VersionedDB{MongoDB, dbName}, nil
+	return &VersionedDB{mongoDB: MongoDB, dbName: dbName, committedDataCache: newVersionCache()}, nil
 }
 
 func isJson(value []byte) bool {
@@ -21,6 +21,9 @@ import (
 	"testing"
 
 	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2"
+	"justledger/core/ledger/kvledger/txmgmt/statedb"
 	"justledger/core/ledger/kvledger/txmgmt/statedb/commontests"
 	"justledger/core/ledger/kvledger/txmgmt/version"
 	ledgertestutil "justledger/core/ledger/testutil"
@@ -70,8 +73,8 @@ func TestEncodeDecodeValueAndVersion(t *testing.T) {
 func testValueAndVersionEncodeing(t *testing.T, value []byte, version *version.Height) {
 	//encodedValue := statedb.EncodeValue(value, version)
 	//val, ver := statedb.DecodeValue(encodedValue)
-	//testutil.AssertEquals(t, val, value)
-	//testutil.AssertEquals(t, ver, version)
+	//assert.Equal(t, value, val)
+	//assert.Equal(t, version, ver)
 }
 
 func TestIterator(t *testing.T) {
@@ -97,3 +100,75 @@ func TestQueryOrPaging(t *testing.T) {
 	defer env.Cleanup("testpaging")
 	commontests.TestExecuteQueryPaging(t, env.DBProvider)
 }
+
+func TestLoadCommittedVersions(t *testing.T) {
+	env := NewTestDBEnv(t)
+	defer env.Cleanup("testloadcommittedversions")
+	db, err := env.DBProvider.GetDBHandle("testloadcommittedversions")
+	assert.NoError(t, err)
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte(`{"asset_name": "marble1"}`), version.NewHeight(1, 1))
+	batch.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 2))
+	batch.Put("ns2", "key1", []byte(`{"asset_name": "marble1"}`), version.NewHeight(1, 3))
+	assert.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 3)))
+
+	bulkOptimizable, ok := db.(statedb.BulkOptimizable)
+	assert.True(t, ok)
+	keys := []*statedb.CompositeKey{
+		{Namespace: "ns1", Key: "key1"},
+		{Namespace: "ns1", Key: "key2"},
+		{Namespace: "ns1", Key: "key3"},
+		{Namespace: "ns2", Key: "key1"},
+		{Namespace: "ns2", Key: "key1"},
+	}
+	assert.NoError(t, bulkOptimizable.LoadCommittedVersions(keys))
+
+	ver, found := bulkOptimizable.GetCachedVersion("ns1", "key1")
+	assert.True(t, found)
+	assert.Equal(t, version.NewHeight(1, 1), ver)
+	ver, found = bulkOptimizable.GetCachedVersion("ns1", "key2")
+	assert.True(t, found)
+	assert.Equal(t, version.NewHeight(1, 2), ver)
+	ver, found = bulkOptimizable.GetCachedVersion("ns2", "key1")
+	assert.True(t, found)
+	assert.Equal(t, version.NewHeight(1, 3), ver)
+	// a missing key is cached with a nil version
+	ver, found = bulkOptimizable.GetCachedVersion("ns1", "key3")
+	assert.True(t, found)
+	assert.Nil(t, ver)
+	_, found = bulkOptimizable.GetCachedVersion("ns2", "key2")
+	assert.False(t, found)
+
+	// GetVersion is served from the cache
+	ver, err = db.GetVersion("ns1", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, version.NewHeight(1, 1), ver)
+
+	bulkOptimizable.ClearCachedVersions()
+	_, found = bulkOptimizable.GetCachedVersion("ns1", "key1")
+	assert.False(t, found)
+	ver, err = db.GetVersion("ns1", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, version.NewHeight(1, 1), ver)
+}
+
+func TestBuildNamespaceIndex(t *testing.T) {
+	index, err := buildNamespaceIndex("ns1", []byte(`{"key":["owner","-size","$text:description"],"name":"indexOwner","unique":true}`))
+	assert.NoError(t, err)
+	assert.Equal(t, mgo.Index{
+		Key:    []string{"chaincodeid", "value.owner", "-value.size", "$text:value.description"},
+		Name:   "ns1_indexOwner",
+		Unique: true,
+	}, index)
+
+	index, err = buildNamespaceIndex("ns1", []byte(`{"key":["owner"]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, mgo.Index{Key: []string{"chaincodeid", "value.owner"}}, index)
+
+	_, err = buildNamespaceIndex("ns1", []byte(`{"key":["owner"`))
+	assert.Contains(t, err.Error(), "invalid index definition")
+
+	_, err = buildNamespaceIndex("ns1", []byte(`{"name":"noKeys"}`))
+	assert.EqualError(t, err, "invalid index definition: no keys provided")
+}
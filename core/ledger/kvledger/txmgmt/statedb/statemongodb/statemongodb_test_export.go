@@ -29,12 +29,19 @@ type TestDBEnv struct {
 
 func NewTestDBEnv(t testing.TB) *TestDBEnv {
 	t.Logf("Creating new TestDBEnv")
-	versionedDBProvider, _ := NewVersionedDBProvider()
+	versionedDBProvider, err := NewVersionedDBProvider()
+	if err != nil {
+		t.Fatalf("Error creating the MongoDB versioned DB provider: %s", err)
+	}
 	return &TestDBEnv{t: t, DBProvider: versionedDBProvider}
 }
 
 func (env *TestDBEnv) Cleanup(dbName string) {
-	versionedDBProvider, _ := NewVersionedDBProvider()
+	versionedDBProvider, err := NewVersionedDBProvider()
+	if err != nil {
+		env.t.Errorf("Error creating the MongoDB versioned DB provider: %s", err)
+		return
+	}
 	versionedDBProvider.session.DB(dbName).DropDatabase()
 	versionedDBProvider.session.Close()
 }
@@ -0,0 +1,40 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statemongodb
+
+import (
+	"justledger/core/ledger/kvledger/txmgmt/version"
+)
+
+type versions map[string]nsVersions
+type nsVersions map[string]*version.Height
+
+// versionsCache contains the committed versions of the keys loaded in bulk during
+// the processing of a block. It is used for the state validation of the readsets.
+// A nil version denotes a key that is known not to exist in the state
+type versionsCache struct {
+	vers versions
+}
+
+func newVersionCache() *versionsCache {
+	return &versionsCache{make(versions)}
+}
+
+func (c *versionsCache) getVersion(ns, key string) (*version.Height, bool) {
+	ver, ok := c.vers[ns][key]
+	if ok {
+		return ver, true
+	}
+	return nil, false
+}
+
+func (c *versionsCache) setVer(ns, key string, ver *version.Height) {
+	_, ok := c.vers[ns]
+	if !ok {
+		c.vers[ns] = make(nsVersions)
+	}
+	c.vers[ns][key] = ver
+}
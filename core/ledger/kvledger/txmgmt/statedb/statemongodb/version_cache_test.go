@@ -0,0 +1,43 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statemongodb
+
+import (
+	"testing"
+
+	"justledger/core/ledger/kvledger/txmgmt/version"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionCache(t *testing.T) {
+	verCache := newVersionCache()
+	ver1 := version.NewHeight(1, 1)
+	ver2 := version.NewHeight(2, 2)
+	verCache.setVer("ns1", "key1", version.NewHeight(1, 1))
+	verCache.setVer("ns2", "key2", version.NewHeight(2, 2))
+	verCache.setVer("ns2", "key3", nil)
+
+	ver, found := verCache.getVersion("ns1", "key1")
+	assert.True(t, found)
+	assert.Equal(t, ver1, ver)
+
+	ver, found = verCache.getVersion("ns2", "key2")
+	assert.True(t, found)
+	assert.Equal(t, ver2, ver)
+
+	// a key loaded with a nil version is known not to exist
+	ver, found = verCache.getVersion("ns2", "key3")
+	assert.True(t, found)
+	assert.Nil(t, ver)
+
+	ver, found = verCache.getVersion("ns1", "key3")
+	assert.False(t, found)
+	assert.Nil(t, ver)
+
+	ver, found = verCache.getVersion("ns3", "key4")
+	assert.False(t, found)
+	assert.Nil(t, ver)
+}
@@ -20,6 +20,7 @@ import (
 	"justledger/core/ledger/pvtdatapolicy"
 	"justledger/protos/common"
 	"justledger/protos/ledger/rwset/kvrwset"
+	"github.com/pkg/errors"
 )
 
 var logger = flogging.MustGetLogger("lockbasedtxmgr")
@@ -34,6 +35,7 @@ type LockBasedTxMgr struct {
 	stateListeners  []ledger.StateListener
 	commitRWLock    sync.RWMutex
 	current         *current
+	drained         bool
 }
 
 type current struct {
@@ -74,6 +76,10 @@ func (txmgr *LockBasedTxMgr) GetLastSavepoint() (*version.Height, error) {
 func (txmgr *LockBasedTxMgr) NewQueryExecutor(txid string) (ledger.QueryExecutor, error) {
 	qe := newQueryExecutor(txmgr, txid)
 	txmgr.commitRWLock.RLock()
+	if txmgr.drained {
+		txmgr.commitRWLock.RUnlock()
+		return nil, errors.Errorf("ledger [%s] is being closed", txmgr.ledgerid)
+	}
 	return qe, nil
 }
 
@@ -85,6 +91,10 @@ func (txmgr *LockBasedTxMgr) NewTxSimulator(txid string) (ledger.TxSimulator, er
 		return nil, err
 	}
 	txmgr.commitRWLock.RLock()
+	if txmgr.drained {
+		txmgr.commitRWLock.RUnlock()
+		return nil, errors.Errorf("ledger [%s] is being closed", txmgr.ledgerid)
+	}
 	return s, nil
 }
 
@@ -140,6 +150,14 @@ func (txmgr *LockBasedTxMgr) invokeNamespaceListeners() error {
 	return nil
 }
 
+// Drain implements method in interface `txmgmt.TxMgr`
+func (txmgr *LockBasedTxMgr) Drain() {
+	// the in-flight simulators and query executors hold the read lock until they are done
+	txmgr.commitRWLock.Lock()
+	defer txmgr.commitRWLock.Unlock()
+	txmgr.drained = true
+}
+
 // Shutdown implements method in interface `txmgmt.TxMgr`
 func (txmgr *LockBasedTxMgr) Shutdown() {
 	// wait for background go routine to finish else the timing issue causes a nil pointer inside goleveldb code
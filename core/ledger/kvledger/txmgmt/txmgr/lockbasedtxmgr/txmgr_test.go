@@ -12,11 +12,13 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"justledger/common/flogging"
 	"justledger/common/ledger/testutil"
 	"justledger/core/ledger"
 	"justledger/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"justledger/core/ledger/kvledger/txmgmt/rwsetutil"
 	"justledger/core/ledger/kvledger/txmgmt/txmgr"
 	"justledger/core/ledger/kvledger/txmgmt/version"
 	"justledger/core/ledger/pvtdatapolicy"
@@ -24,6 +26,7 @@ import (
 	ledgertestutil "justledger/core/ledger/testutil"
 	"justledger/core/ledger/util"
 	"justledger/protos/ledger/queryresult"
+	"justledger/protos/ledger/rwset/kvrwset"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
@@ -73,6 +76,41 @@ func testTxSimulatorWithNoExistingData(t *testing.T, env testEnv) {
 	assert.Nil(t, simulationResults.PvtSimulationResults)
 }
 
+func TestDrain(t *testing.T) {
+	testEnv := testEnvsMap[levelDBtestEnvName]
+	testEnv.init(t, "testLedger", nil)
+	defer testEnv.cleanup()
+	txMgr := testEnv.getTxMgr()
+
+	s, err := txMgr.NewTxSimulator("txid1")
+	assert.NoError(t, err)
+	qe, err := txMgr.NewQueryExecutor("txid2")
+	assert.NoError(t, err)
+
+	drained := make(chan struct{})
+	go func() {
+		txMgr.Drain()
+		close(drained)
+	}()
+	s.Done()
+	select {
+	case <-drained:
+		t.Fatal("Drain returned while a query executor is in use")
+	case <-time.After(100 * time.Millisecond):
+	}
+	qe.Done()
+	select {
+	case <-drained:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Drain did not return after the query executor was done")
+	}
+
+	_, err = txMgr.NewTxSimulator("txid3")
+	assert.EqualError(t, err, "ledger [testLedger] is being closed")
+	_, err = txMgr.NewQueryExecutor("txid4")
+	assert.EqualError(t, err, "ledger [testLedger] is being closed")
+}
+
 func TestTxSimulatorGetResults(t *testing.T) {
 	testEnv := testEnvsMap[levelDBtestEnvName]
 	testEnv.init(t, "testLedger", nil)
@@ -1216,6 +1254,71 @@ func testTxWithPvtdataMetadata(t *testing.T, env testEnv, ns, coll string) {
 	qe.Done()
 }
 
+func TestTxWithPvtdataPurge(t *testing.T) {
+	ledgerid, ns, coll := "testtxwithpvtdatapurge", "ns", "coll"
+	cs := btltestutil.NewMockCollectionStore()
+	cs.SetBTL("ns", "coll", 1000)
+	btlPolicy := pvtdatapolicy.ConstructBTLPolicy(cs)
+	for _, testEnv := range testEnvs {
+		t.Logf("Running test for TestEnv = %s", testEnv.getName())
+		testEnv.init(t, ledgerid, btlPolicy)
+		testTxWithPvtdataPurge(t, testEnv, ns, coll)
+		testEnv.cleanup()
+	}
+}
+
+func testTxWithPvtdataPurge(t *testing.T, env testEnv, ns, coll string) {
+	ledgerid := "testtxwithpvtdatapurge"
+	txMgr := env.getTxMgr()
+	bg, _ := testutil.NewBlockGenerator(t, ledgerid, false)
+
+	populateCollConfigForTest(t, txMgr.(*LockBasedTxMgr), []collConfigkey{{"ns", "coll"}}, version.NewHeight(1, 1))
+
+	// Simulate and commit tx1 - set val for key1 and key2
+	s1, _ := txMgr.NewTxSimulator("test_tx1")
+	s1.SetPrivateData(ns, coll, "key1", []byte("value1"))
+	s1.SetPrivateData(ns, coll, "key2", []byte("value2"))
+	s1.Done()
+	blkAndPvtdata1 := prepareNextBlockForTestFromSimulator(t, bg, s1)
+	assert.NoError(t, txMgr.ValidateAndPrepare(blkAndPvtdata1, true))
+	assert.NoError(t, txMgr.Commit())
+
+	// Simulate tx2 - purge key1. The purge should be recorded as a delete in the pvt and the hashed write-sets
+	s2, _ := txMgr.NewTxSimulator("test_tx2")
+	assert.NoError(t, s2.PurgePrivateData(ns, coll, "key1"))
+	s2.Done()
+	simRes, err := s2.GetTxSimulationResults()
+	assert.NoError(t, err)
+	txRWSet, err := rwsetutil.TxRwSetFromProtoMsg(simRes.PubSimulationResults)
+	assert.NoError(t, err)
+	var hashedWrites []*kvrwset.KVWriteHash
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if nsRWSet.NameSpace == ns {
+			hashedWrites = nsRWSet.CollHashedRwSets[0].HashedRwSet.HashedWrites
+		}
+	}
+	assert.Len(t, hashedWrites, 1)
+	assert.True(t, hashedWrites[0].IsDelete)
+	assert.True(t, hashedWrites[0].IsPurge)
+
+	pubSimBytes, err := simRes.GetPubSimulationBytes()
+	assert.NoError(t, err)
+	blkAndPvtdata2 := &ledger.BlockAndPvtData{Block: bg.NextBlock([][]byte{pubSimBytes}),
+		BlockPvtData: map[uint64]*ledger.TxPvtData{0: {SeqInBlock: 0, WriteSet: simRes.PvtSimulationResults}},
+	}
+	assert.NoError(t, txMgr.ValidateAndPrepare(blkAndPvtdata2, true))
+	assert.NoError(t, txMgr.Commit())
+
+	// key1 should not exist anymore, neither by value nor by hash. key2 should be unaffected
+	qe, _ := txMgr.NewQueryExecutor("test_tx3")
+	checkPvtdataTestQueryResults(t, qe, ns, coll, "key1", nil, nil)
+	checkPvtdataTestQueryResults(t, qe, ns, coll, "key2", []byte("value2"), nil)
+	qe.Done()
+	valueHash, _, err := newQueryHelper(txMgr.(*LockBasedTxMgr), nil).getPrivateDataValueHash(ns, coll, "key1")
+	assert.NoError(t, err)
+	assert.Nil(t, valueHash)
+}
+
 func prepareNextBlockForTest(t *testing.T, txMgr txmgr.TxMgr, bg *testutil.BlockGenerator,
 	txid string, pubKVs map[string]string, pvtKVs map[string]string) *ledger.BlockAndPvtData {
 	simulator, _ := txMgr.NewTxSimulator(txid)
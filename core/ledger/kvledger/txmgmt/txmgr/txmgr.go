@@ -31,6 +31,9 @@ type TxMgr interface {
 	CommitLostBlock(blockAndPvtdata *ledger.BlockAndPvtData) error
 	Commit() error
 	Rollback()
+	// Drain waits for the in-flight transaction simulators and query executors to be done
+	// and makes the subsequent requests for new ones fail
+	Drain()
 	Shutdown()
 }
 
@@ -0,0 +1,83 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statebasedval
+
+import (
+	"sync"
+
+	"justledger/core/ledger/kvledger/txmgmt/rwsetutil"
+	"justledger/core/ledger/kvledger/txmgmt/validator/internal"
+	"justledger/protos/peer"
+)
+
+// validateAgainstCommittedState performs, with 'v.parallelism' goroutines, the mvcc validation of all the
+// transactions in the block against the committed state only, i.e., as if none of the preceding transactions
+// in the block were valid. For a transaction whose reads do not overlap with the writes of the preceding valid
+// transactions in the block (see function 'dependsOnUpdates'), the returned validation code is the same as
+// the one the serial validation would compute
+func (v *Validator) validateAgainstCommittedState(block *internal.Block) ([]peer.TxValidationCode, error) {
+	validationCodes := make([]peer.TxValidationCode, len(block.Txs))
+	noUpdates := internal.NewPubAndHashUpdates()
+
+	var err error
+	var errOnce sync.Once
+	txIndexes := make(chan int, len(block.Txs))
+	for i := range block.Txs {
+		txIndexes <- i
+	}
+	close(txIndexes)
+
+	var wg sync.WaitGroup
+	for w := 0; w < v.parallelism && w < len(block.Txs); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range txIndexes {
+				validationCode, validationErr := v.validateTx(block.Txs[i].RWSet, noUpdates)
+				if validationErr != nil {
+					errOnce.Do(func() { err = validationErr })
+					return
+				}
+				validationCodes[i] = validationCode
+			}
+		}()
+	}
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return validationCodes, nil
+}
+
+// dependsOnUpdates returns true if the mvcc validation of the transaction may be affected by the updates of the
+// preceding valid transactions in the block, i.e., if the transaction reads a key, a key hash, or a key range
+// in which any of these transactions writes
+func dependsOnUpdates(txRWSet *rwsetutil.TxRwSet, updates *internal.PubAndHashUpdates) bool {
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		ns := nsRWSet.NameSpace
+		for _, kvRead := range nsRWSet.KvRwSet.Reads {
+			if updates.PubUpdates.Exists(ns, kvRead.Key) {
+				return true
+			}
+		}
+		for _, rqi := range nsRWSet.KvRwSet.RangeQueriesInfo {
+			itr := updates.PubUpdates.GetRangeScanIterator(ns, rqi.StartKey, rqi.EndKey)
+			update, _ := itr.Next()
+			itr.Close()
+			if update != nil || (rqi.EndKey != "" && updates.PubUpdates.Exists(ns, rqi.EndKey)) {
+				return true
+			}
+		}
+		for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+			for _, kvReadHash := range collHashedRWSet.HashedRwSet.HashedReads {
+				if updates.HashUpdates.Contains(ns, collHashedRWSet.CollectionName, kvReadHash.KeyHash) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
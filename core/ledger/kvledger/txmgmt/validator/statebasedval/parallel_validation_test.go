@@ -0,0 +1,185 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statebasedval
+
+import (
+	"fmt"
+	"testing"
+
+	"justledger/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"justledger/core/ledger/kvledger/txmgmt/rwsetutil"
+	"justledger/core/ledger/kvledger/txmgmt/validator/internal"
+	"justledger/core/ledger/kvledger/txmgmt/version"
+	"justledger/core/ledger/util"
+	"justledger/protos/ledger/rwset/kvrwset"
+	"justledger/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParallelValidationMatchesSerialValidation(t *testing.T) {
+	testDBEnv := privacyenabledstate.LevelDBCommonStorageTestEnv{}
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	db := testDBEnv.GetDBHandle("TestDB")
+
+	//populate db with initial data
+	batch := privacyenabledstate.NewUpdateBatch()
+	for i := 0; i < 10; i++ {
+		batch.PubUpdates.Put("ns1", fmt.Sprintf("key%d", i), []byte(fmt.Sprintf("value%d", i)), version.NewHeight(1, uint64(i)))
+	}
+	batch.HashUpdates.Put("ns2", "coll1", util.ComputeStringHash("pvtKey1"), []byte("pvtValue1"), version.NewHeight(1, 10))
+	db.ApplyPrivacyAwareUpdates(batch, version.NewHeight(1, 10))
+
+	rangeQuery := func() *kvrwset.RangeQueryInfo {
+		rqi := &kvrwset.RangeQueryInfo{StartKey: "key3", EndKey: "key5", ItrExhausted: true}
+		rqi.SetRawReads([]*kvrwset.KVRead{
+			rwsetutil.NewKVRead("key3", version.NewHeight(1, 3)),
+			rwsetutil.NewKVRead("key4", version.NewHeight(1, 4))})
+		return rqi
+	}
+	var builders []*rwsetutil.RWSetBuilder
+	addTx := func(f func(b *rwsetutil.RWSetBuilder)) {
+		b := rwsetutil.NewRWSetBuilder()
+		f(b)
+		builders = append(builders, b)
+	}
+	// tx0 is valid and updates key1
+	addTx(func(b *rwsetutil.RWSetBuilder) {
+		b.AddToReadSet("ns1", "key1", version.NewHeight(1, 1))
+		b.AddToWriteSet("ns1", "key1", []byte("value1_new"))
+	})
+	// tx1 is valid as per the committed state but reads key1 that is updated by tx0
+	addTx(func(b *rwsetutil.RWSetBuilder) {
+		b.AddToReadSet("ns1", "key1", version.NewHeight(1, 1))
+		b.AddToWriteSet("ns1", "key8", []byte("value8_new"))
+	})
+	// tx2 is invalid as per the committed state
+	addTx(func(b *rwsetutil.RWSetBuilder) {
+		b.AddToReadSet("ns1", "key2", version.NewHeight(1, 0))
+	})
+	// tx3 is valid
+	addTx(func(b *rwsetutil.RWSetBuilder) {
+		b.AddToRangeQuerySet("ns1", rangeQuery())
+	})
+	// tx4 adds a key in the range of the range query
+	addTx(func(b *rwsetutil.RWSetBuilder) {
+		b.AddToWriteSet("ns1", "key3_1", []byte("value3_1"))
+	})
+	// tx5 is valid as per the committed state but has a phantom read because of tx4
+	addTx(func(b *rwsetutil.RWSetBuilder) {
+		b.AddToRangeQuerySet("ns1", rangeQuery())
+	})
+	// tx6 is valid and updates pvtKey1
+	addTx(func(b *rwsetutil.RWSetBuilder) {
+		b.AddToHashedReadSet("ns2", "coll1", "pvtKey1", version.NewHeight(1, 10))
+		b.AddToPvtAndHashedWriteSet("ns2", "coll1", "pvtKey1", []byte("pvtValue1_new"))
+	})
+	// tx7 is valid as per the committed state but reads pvtKey1 that is updated by tx6
+	addTx(func(b *rwsetutil.RWSetBuilder) {
+		b.AddToHashedReadSet("ns2", "coll1", "pvtKey1", version.NewHeight(1, 10))
+	})
+	// tx8 is valid as it reads key8 that is updated by tx1, which is invalid
+	addTx(func(b *rwsetutil.RWSetBuilder) {
+		b.AddToReadSet("ns1", "key8", version.NewHeight(1, 8))
+		b.AddToWriteSet("ns1", "key9", []byte("value9_new"))
+	})
+	// tx9 is invalid as per the committed state and also reads key9 that is updated by tx8
+	addTx(func(b *rwsetutil.RWSetBuilder) {
+		b.AddToReadSet("ns1", "key9", version.NewHeight(1, 0))
+	})
+	txRWSets := getTestPubSimulationRWSet(t, builders...)
+
+	validate := func(parallelism int) ([]peer.TxValidationCode, *internal.PubAndHashUpdates) {
+		var trans []*internal.Transaction
+		for i, txRWSet := range txRWSets {
+			trans = append(trans, &internal.Transaction{
+				ID:             fmt.Sprintf("txid-%d", i),
+				IndexInBlock:   i,
+				ValidationCode: peer.TxValidationCode_VALID,
+				RWSet:          txRWSet,
+			})
+		}
+		block := &internal.Block{Num: 2, Txs: trans}
+		validator := &Validator{db: db, parallelism: parallelism}
+		updates, err := validator.ValidateAndPrepareBatch(block, true)
+		assert.NoError(t, err)
+		var validationCodes []peer.TxValidationCode
+		for _, tx := range block.Txs {
+			validationCodes = append(validationCodes, tx.ValidationCode)
+		}
+		return validationCodes, updates
+	}
+
+	serialCodes, serialUpdates := validate(1)
+	assert.Equal(t, []peer.TxValidationCode{
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_MVCC_READ_CONFLICT,
+		peer.TxValidationCode_MVCC_READ_CONFLICT,
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_PHANTOM_READ_CONFLICT,
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_MVCC_READ_CONFLICT,
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_MVCC_READ_CONFLICT,
+	}, serialCodes)
+
+	for _, parallelism := range []int{2, 3, 16} {
+		codes, updates := validate(parallelism)
+		assert.Equal(t, serialCodes, codes, "parallelism = %d", parallelism)
+		assert.Equal(t, serialUpdates, updates, "parallelism = %d", parallelism)
+	}
+}
+
+func TestDependsOnUpdates(t *testing.T) {
+	updates := internal.NewPubAndHashUpdates()
+	updates.PubUpdates.Put("ns1", "key5", []byte("value5"), version.NewHeight(2, 0))
+	updates.HashUpdates.Put("ns1", "coll1", util.ComputeStringHash("pvtKey1"), []byte("pvtValue1"), version.NewHeight(2, 0))
+
+	txRWSet := func(f func(b *rwsetutil.RWSetBuilder)) *rwsetutil.TxRwSet {
+		b := rwsetutil.NewRWSetBuilder()
+		f(b)
+		return getTestPubSimulationRWSet(t, b)[0]
+	}
+	testCases := []struct {
+		name     string
+		rwset    *rwsetutil.TxRwSet
+		expected bool
+	}{
+		{"read of an updated key", txRWSet(func(b *rwsetutil.RWSetBuilder) {
+			b.AddToReadSet("ns1", "key5", nil)
+		}), true},
+		{"read of the same key in another namespace", txRWSet(func(b *rwsetutil.RWSetBuilder) {
+			b.AddToReadSet("ns2", "key5", nil)
+		}), false},
+		{"write of an updated key", txRWSet(func(b *rwsetutil.RWSetBuilder) {
+			b.AddToWriteSet("ns1", "key5", []byte("value"))
+		}), false},
+		{"range including an updated key", txRWSet(func(b *rwsetutil.RWSetBuilder) {
+			b.AddToRangeQuerySet("ns1", &kvrwset.RangeQueryInfo{StartKey: "key1", EndKey: "key9"})
+		}), true},
+		{"range ending at an updated key", txRWSet(func(b *rwsetutil.RWSetBuilder) {
+			b.AddToRangeQuerySet("ns1", &kvrwset.RangeQueryInfo{StartKey: "key1", EndKey: "key5"})
+		}), true},
+		{"range without an updated key", txRWSet(func(b *rwsetutil.RWSetBuilder) {
+			b.AddToRangeQuerySet("ns1", &kvrwset.RangeQueryInfo{StartKey: "key6", EndKey: "key9"})
+		}), false},
+		{"open ended range", txRWSet(func(b *rwsetutil.RWSetBuilder) {
+			b.AddToRangeQuerySet("ns1", &kvrwset.RangeQueryInfo{StartKey: "key1", EndKey: ""})
+		}), true},
+		{"read of an updated key hash", txRWSet(func(b *rwsetutil.RWSetBuilder) {
+			b.AddToHashedReadSet("ns1", "coll1", "pvtKey1", nil)
+		}), true},
+		{"read of the same key hash in another collection", txRWSet(func(b *rwsetutil.RWSetBuilder) {
+			b.AddToHashedReadSet("ns1", "coll2", "pvtKey1", nil)
+		}), false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, dependsOnUpdates(testCase.rwset, updates))
+		})
+	}
+}
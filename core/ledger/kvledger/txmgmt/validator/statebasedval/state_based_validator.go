@@ -7,6 +7,7 @@ package statebasedval
 
 import (
 	"justledger/common/flogging"
+	"justledger/core/ledger/ledgerconfig"
 	"justledger/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"justledger/core/ledger/kvledger/txmgmt/rwsetutil"
 	"justledger/core/ledger/kvledger/txmgmt/statedb"
@@ -22,11 +23,14 @@ var logger = flogging.MustGetLogger("statebasedval")
 // and preceding valid transactions with in the same block
 type Validator struct {
 	db privacyenabledstate.DB
+	// parallelism is the number of goroutines used for validating the
+	// transactions of a block in parallel. A value of 1 means serial validation
+	parallelism int
 }
 
 // NewValidator constructs StateValidator
 func NewValidator(db privacyenabledstate.DB) *Validator {
-	return &Validator{db}
+	return &Validator{db, ledgerconfig.GetMVCCValidationParallelism()}
 }
 
 // preLoadCommittedVersionOfRSet loads committed version of all keys in each
@@ -97,11 +101,24 @@ func (v *Validator) ValidateAndPrepareBatch(block *internal.Block, doMVCCValidat
 		}
 	}
 
+	// In the parallel mode, all the transactions are first validated concurrently against the committed state.
+	// Then, the transactions are processed serially in the block order, and only the ones that read the writes
+	// of the preceding valid transactions are validated again, so that the result is the same as the serial validation
+	var committedStateValidationCodes []peer.TxValidationCode
+	if doMVCCValidation && v.parallelism > 1 && len(block.Txs) > 1 {
+		var err error
+		if committedStateValidationCodes, err = v.validateAgainstCommittedState(block); err != nil {
+			return nil, err
+		}
+	}
+
 	updates := internal.NewPubAndHashUpdates()
-	for _, tx := range block.Txs {
+	for i, tx := range block.Txs {
 		var validationCode peer.TxValidationCode
 		var err error
-		if validationCode, err = v.validateEndorserTX(tx.RWSet, doMVCCValidation, updates); err != nil {
+		if committedStateValidationCodes != nil && !dependsOnUpdates(tx.RWSet, updates) {
+			validationCode = committedStateValidationCodes[i]
+		} else if validationCode, err = v.validateEndorserTX(tx.RWSet, doMVCCValidation, updates); err != nil {
 			return nil, err
 		}
 
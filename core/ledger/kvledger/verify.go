@@ -0,0 +1,40 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"justledger/core/ledger/ledgerstorage"
+	"justledger/protos/common"
+	"github.com/pkg/errors"
+)
+
+// VerifyKVLedger checks that the chain stored in the block store of the given ledger is internally consistent,
+// including the entries of the block index, and invokes verifyBlock for every block in sequence. The number of
+// blocks verified is returned. The first inconsistency found is returned as an error. This function fails if the
+// ledger data is in use by a running peer
+func VerifyKVLedger(ledgerID string, verifyBlock func(block *common.Block) error) (uint64, error) {
+	fileLock, err := lockLedgerData()
+	if err != nil {
+		return 0, err
+	}
+	defer fileLock.Unlock()
+
+	ledgerIDs, err := loadLedgerIDs()
+	if err != nil {
+		return 0, err
+	}
+	if !contains(ledgerIDs, ledgerID) {
+		return 0, errors.Errorf("ledger [%s] does not exist", ledgerID)
+	}
+	logger.Infof("Verifying the ledger [%s]", ledgerID)
+	numBlocks, err := ledgerstorage.VerifyBlockStore(ledgerID, verifyBlock)
+	if err != nil {
+		return numBlocks, err
+	}
+	logger.Infof("Verified [%d] blocks of the ledger [%s]", numBlocks, ledgerID)
+	return numBlocks, nil
+}
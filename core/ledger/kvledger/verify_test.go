@@ -0,0 +1,51 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"testing"
+
+	"justledger/common/ledger/testutil"
+	"justledger/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyKVLedger(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := testutilNewProvider(t)
+	testLedgerid := "testLedger"
+	bg, gb := testutil.NewBlockGenerator(t, testLedgerid, false)
+	ledger, err := provider.Create(gb)
+	require.NoError(t, err)
+	for i, v := range []string{"1", "2", "3"} {
+		blk := prepareNextBlockForTest(t, ledger, bg, "SimulateForBlk"+v,
+			map[string]string{"key1": "value1." + v}, nil)
+		blk.BlockPvtData = nil
+		require.NoError(t, ledger.CommitWithPvtData(blk), "block %d", i+1)
+	}
+
+	// verification is not allowed while the ledger data is in use
+	_, err = VerifyKVLedger(testLedgerid, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the ledger data is in use")
+	ledger.Close()
+	provider.Close()
+
+	_, err = VerifyKVLedger("nonExistingLedger", nil)
+	assert.EqualError(t, err, "ledger [nonExistingLedger] does not exist")
+
+	var blockNums []uint64
+	numBlocks, err := VerifyKVLedger(testLedgerid, func(block *common.Block) error {
+		blockNums = append(blockNums, block.Header.Number)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), numBlocks)
+	assert.Equal(t, []uint64{0, 1, 2, 3}, blockNums)
+}
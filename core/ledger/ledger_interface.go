@@ -8,8 +8,10 @@ package ledger
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	commonledger "justledger/common/ledger"
 	"justledger/protos/common"
 	"justledger/protos/ledger/rwset"
@@ -35,8 +37,14 @@ type PeerLedgerProvider interface {
 	Open(ledgerID string) (PeerLedger, error)
 	// Exists tells whether the ledger with given id exists
 	Exists(ledgerID string) (bool, error)
-	// List lists the ids of the existing ledgers
+	// IsPaused tells whether the ledger with given id is paused. A paused ledger cannot be opened
+	IsPaused(ledgerID string) (bool, error)
+	// List lists the ids of the existing ledgers that are not paused
 	List() ([]string, error)
+	// ListPaused lists the ids of the existing ledgers that are paused
+	ListPaused() ([]string, error)
+	// Remove deletes the ledger with given id and all of its data. The ledger should have been closed
+	Remove(ledgerID string) error
 	// Close closes the PeerLedgerProvider
 	Close()
 }
@@ -53,6 +61,13 @@ type PeerLedger interface {
 	GetBlockByTxID(txID string) (*common.Block, error)
 	// GetTxValidationCodeByTxID returns reason code of transaction validation
 	GetTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error)
+	// GetBlocksByTimeRange returns a page of at most pageSize blocks (all the blocks if pageSize is 0) committed in the
+	// time range [startTime, endTime) in the order of their commit time, starting from the given bookmark (the first
+	// block if empty), along with the bookmark of the next page (empty for the last page)
+	GetBlocksByTimeRange(startTime, endTime time.Time, pageSize int, bookmark string) ([]*common.Block, string, error)
+	// GetTransactionsByTimeRange returns a page of the transactions whose channel header timestamp is in the time range
+	// [startTime, endTime), along with their validation code, the same way as GetBlocksByTimeRange returns the blocks
+	GetTransactionsByTimeRange(startTime, endTime time.Time, pageSize int, bookmark string) ([]*peer.ProcessedTransaction, string, error)
 	// NewTxSimulator gives handle to a transaction simulator.
 	// A client can obtain more than one 'TxSimulator's for parallel execution.
 	// Any snapshoting/synchronization should be performed at the implementation level if required
@@ -169,6 +184,55 @@ type HistoryQueryExecutor interface {
 	// GetHistoryForKey retrieves the history of values for a key.
 	// The returned ResultsIterator contains results of type *KeyModification which is defined in protos/ledger/queryresult.
 	GetHistoryForKey(namespace string, key string) (commonledger.ResultsIterator, error)
+	// GetHistoryForKeyWithOptions retrieves the history of values for a key, restricted and ordered by the options.
+	// The returned ResultsIterator contains results of type *KeyModification which is defined in protos/ledger/queryresult.
+	GetHistoryForKeyWithOptions(namespace string, key string, options *HistoryQueryOptions) (commonledger.ResultsIterator, error)
+	// GetHistoryForKeysByPrefix retrieves the history of values for all the keys starting with the given prefix,
+	// e.g., the prefix of a partial composite key, restricted and ordered by the options. The history of each key
+	// is returned in turn, in the order of the keys, or in the reverse order if the most recent values are
+	// retrieved first. The returned QueryResultsIterator contains results of type *KeyModification, with the key
+	// set, starting from the bookmark of the options, and returns the bookmark of the next result.
+	GetHistoryForKeysByPrefix(namespace string, keyPrefix string, options *HistoryQueryOptions) (commonledger.QueryResultsIterator, error)
+}
+
+// HistoryQueryOptions restricts and orders the results of the history queries
+type HistoryQueryOptions struct {
+	// StartBlock and EndBlock restrict the results to the values written in the blocks from StartBlock
+	// to EndBlock, both included. An EndBlock of 0 means no upper bound
+	StartBlock uint64
+	EndBlock   uint64
+	// StartTime and EndTime restrict the results to the values written by the transactions whose
+	// timestamp is in [StartTime, EndTime). A zero time means no bound
+	StartTime time.Time
+	EndTime   time.Time
+	// NewestFirst retrieves the most recent values first
+	NewestFirst bool
+	// Bookmark is the position, returned by a previous query with the same options, from which the results of
+	// GetHistoryForKeysByPrefix are retrieved. An empty bookmark means the first result
+	Bookmark string
+}
+
+// NewHistoryQueryOptions returns the HistoryQueryOptions corresponding to the options of a history query request
+func NewHistoryQueryOptions(options *peer.HistoryQueryOptions) (*HistoryQueryOptions, error) {
+	historyQueryOptions := &HistoryQueryOptions{}
+	if options == nil {
+		return historyQueryOptions, nil
+	}
+	historyQueryOptions.StartBlock = options.StartBlock
+	historyQueryOptions.EndBlock = options.EndBlock
+	historyQueryOptions.NewestFirst = options.NewestFirst
+	var err error
+	if options.StartTime != nil {
+		if historyQueryOptions.StartTime, err = ptypes.Timestamp(options.StartTime); err != nil {
+			return nil, err
+		}
+	}
+	if options.EndTime != nil {
+		if historyQueryOptions.EndTime, err = ptypes.Timestamp(options.EndTime); err != nil {
+			return nil, err
+		}
+	}
+	return historyQueryOptions, nil
 }
 
 // TxSimulator simulates a transaction on a consistent snapshot of the 'as recent state as possible'
@@ -193,6 +257,10 @@ type TxSimulator interface {
 	SetPrivateDataMultipleKeys(namespace, collection string, kvs map[string][]byte) error
 	// DeletePrivateData deletes the given tuple <namespace, collection, key> from private data
 	DeletePrivateData(namespace, collection, key string) error
+	// PurgePrivateData deletes the given tuple <namespace, collection, key> from private data and, in addition,
+	// causes the past versions of the private data of the key to be removed permanently from the peers upon commit.
+	// The hashes of the key on the chain are not affected
+	PurgePrivateData(namespace, collection, key string) error
 	// SetPrivateDataMetadata sets the metadata associated with an existing key-tuple <namespace, collection, key>
 	SetPrivateDataMetadata(namespace, collection, key string, metadata map[string][]byte) error
 	// DeletePrivateDataMetadata deletes the metadata associated with an existing key-tuple <namespace, collection, key>
@@ -238,6 +306,16 @@ type MissingPrivateDataList struct {
 	List []*MissingPrivateData
 }
 
+// PvtdataPurge identifies a private data key, by its hash, that is purged by the valid
+// transaction `SeqInBlock` of a block. The private data of the key written by this or
+// any earlier transaction is to be removed permanently
+type PvtdataPurge struct {
+	SeqInBlock uint64
+	Namespace  string
+	Collection string
+	KeyHash    []byte
+}
+
 // BlockAndPvtData encapsulates the block and a map that contains the tuples <seqInBlock, *TxPvtData>
 // The map is expected to contain the entries only for the transactions that has associated pvt data
 type BlockAndPvtData struct {
@@ -40,12 +40,17 @@ const confBookkeeper = "bookkeeper"
 const confConfigHistory = "configHistory"
 const confChains = "chains"
 const confPvtdataStore = "pvtdataStore"
+const confFileLock = "fileLock"
+const confBlockfileCompression = "ledger.blockchain.compression"
+const confEnableTimeIndex = "ledger.blockchain.enableTimeIndex"
 const confTotalQueryLimit = "ledger.state.totalQueryLimit"
+const confStateCacheSize = "ledger.state.cacheSize"
 const confInternalQueryLimit = "ledger.state.couchDBConfig.internalQueryLimit"
 const confEnableHistoryDatabase = "ledger.history.enableHistoryDatabase"
 const confMaxBatchSize = "ledger.state.couchDBConfig.maxBatchUpdateSize"
 const confAutoWarmIndexes = "ledger.state.couchDBConfig.autoWarmIndexes"
 const confWarmIndexesAfterNBlocks = "ledger.state.couchDBConfig.warmIndexesAfterNBlocks"
+const confMVCCValidationParallelism = "ledger.validation.mvccValidationParallelism"
 
 // GetRootPath returns the filesystem path.
 // All ledger related contents are expected to be stored under this path
@@ -89,11 +94,27 @@ func GetConfigHistoryPath() string {
 	return filepath.Join(GetRootPath(), confConfigHistory)
 }
 
+// GetFileLockPath returns the filesystem path that is used to ensure that a single process
+// (a running peer or an offline ledger command) accesses the ledger data at a time
+func GetFileLockPath() string {
+	return filepath.Join(GetRootPath(), confFileLock)
+}
+
 // GetMaxBlockfileSize returns maximum size of the block file
 func GetMaxBlockfileSize() int {
 	return 64 * 1024 * 1024
 }
 
+// GetBlockfileCompression returns the name of the compression of the blocks stored in the new block files
+func GetBlockfileCompression() string {
+	return viper.GetString(confBlockfileCompression)
+}
+
+// IsTimeIndexEnabled returns true if the blocks and the transactions are indexed by time in the block store
+func IsTimeIndexEnabled() bool {
+	return viper.GetBool(confEnableTimeIndex)
+}
+
 //GetTotalLimit exposes the totalLimit variable
 func GetTotalQueryLimit() int {
 	totalQueryLimit := viper.GetInt(confTotalQueryLimit)
@@ -104,6 +125,16 @@ func GetTotalQueryLimit() int {
 	return totalQueryLimit
 }
 
+//GetStateCacheSize exposes the cacheSize variable, in megabytes
+func GetStateCacheSize() int {
+	cacheSize := viper.GetInt(confStateCacheSize)
+	// if cacheSize was unset or invalid, the state cache is disabled
+	if cacheSize < 0 {
+		cacheSize = 0
+	}
+	return cacheSize
+}
+
 //GetQueryLimit exposes the queryLimit variable
 func GetInternalQueryLimit() int {
 	internalQueryLimit := viper.GetInt(confInternalQueryLimit)
@@ -171,3 +202,13 @@ func GetWarmIndexesAfterNBlocks() int {
 	}
 	return warmAfterNBlocks
 }
+
+//GetMVCCValidationParallelism exposes the mvccValidationParallelism variable
+func GetMVCCValidationParallelism() int {
+	parallelism := viper.GetInt(confMVCCValidationParallelism)
+	// if mvccValidationParallelism was unset or invalid, default to the serial validation
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	return parallelism
+}
@@ -55,6 +55,7 @@ func TestLedgerConfigPathDefault(t *testing.T) {
 	assert.Equal(t, "/var/hyperledger/production/ledgersData/chains", GetBlockStorePath())
 	assert.Equal(t, "/var/hyperledger/production/ledgersData/pvtdataStore", GetPvtdataStorePath())
 	assert.Equal(t, "/var/hyperledger/production/ledgersData/bookkeeper", GetInternalBookkeeperPath())
+	assert.Equal(t, "/var/hyperledger/production/ledgersData/fileLock", GetFileLockPath())
 }
 
 func TestLedgerConfigPath(t *testing.T) {
@@ -68,6 +69,7 @@ func TestLedgerConfigPath(t *testing.T) {
 	assert.Equal(t, "/tmp/hyperledger/production/ledgersData/chains", GetBlockStorePath())
 	assert.Equal(t, "/tmp/hyperledger/production/ledgersData/pvtdataStore", GetPvtdataStorePath())
 	assert.Equal(t, "/tmp/hyperledger/production/ledgersData/bookkeeper", GetInternalBookkeeperPath())
+	assert.Equal(t, "/tmp/hyperledger/production/ledgersData/fileLock", GetFileLockPath())
 }
 
 func TestGetTotalLimitDefault(t *testing.T) {
@@ -228,10 +230,68 @@ func TestGetWarmIndexesAfterNBlocks(t *testing.T) {
 	assert.Equal(t, 10, updatedValue)
 }
 
+func TestGetMVCCValidationParallelismDefault(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defaultValue := GetMVCCValidationParallelism()
+	assert.Equal(t, 1, defaultValue) //test default config is 1
+}
+
+func TestGetMVCCValidationParallelismUnset(t *testing.T) {
+	viper.Reset()
+	defaultValue := GetMVCCValidationParallelism()
+	assert.Equal(t, 1, defaultValue) //test default config is 1
+}
+
+func TestGetMVCCValidationParallelism(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	viper.Set("ledger.validation.mvccValidationParallelism", 8)
+	assert.Equal(t, 8, GetMVCCValidationParallelism())
+	viper.Set("ledger.validation.mvccValidationParallelism", -1)
+	assert.Equal(t, 1, GetMVCCValidationParallelism())
+}
+
+func TestGetStateCacheSizeDefault(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defaultValue := GetStateCacheSize()
+	assert.Equal(t, 64, defaultValue) //test default config is 64
+}
+
+func TestGetStateCacheSizeUnset(t *testing.T) {
+	viper.Reset()
+	defaultValue := GetStateCacheSize()
+	assert.Equal(t, 0, defaultValue) //test the cache is disabled when unset
+}
+
+func TestGetStateCacheSize(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	viper.Set("ledger.state.cacheSize", 128)
+	assert.Equal(t, 128, GetStateCacheSize())
+	viper.Set("ledger.state.cacheSize", -1)
+	assert.Equal(t, 0, GetStateCacheSize())
+}
+
 func TestGetMaxBlockfileSize(t *testing.T) {
 	assert.Equal(t, 67108864, GetMaxBlockfileSize())
 }
 
+func TestGetBlockfileCompression(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	assert.Equal(t, "none", GetBlockfileCompression()) //test default config is none
+	viper.Set("ledger.blockchain.compression", "snappy")
+	assert.Equal(t, "snappy", GetBlockfileCompression())
+}
+
+func TestIsTimeIndexEnabled(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	assert.False(t, IsTimeIndexEnabled()) //test default config is false
+	viper.Set("ledger.blockchain.enableTimeIndex", true)
+	assert.True(t, IsTimeIndexEnabled())
+}
+
 func setUpCoreYAMLConfig() {
 	//call a helper method to load the core.yaml
 	ledgertestutil.SetupCoreYAMLConfig()
@@ -34,6 +34,12 @@ var lock sync.Mutex
 var initialized bool
 var once sync.Once
 
+// pausedLedgers holds the ids of the paused ledgers. The ledgers can only be paused
+// and resumed while the peer is stopped, so they are loaded once at initialization
+// and kept in memory, with a lock of their own, as they are checked on every proposal
+var pausedLedgers map[string]struct{}
+var pausedLock sync.RWMutex
+
 // Initializer encapsulates all the external dependencies for the ledger module
 type Initializer struct {
 	CustomTxProcessors            customtx.Processors
@@ -69,6 +75,7 @@ func initialize(initializer *Initializer) {
 	})
 
 	ledgerProvider = provider
+	loadPausedLedgers()
 	logger.Info("ledger mgmt initialized")
 }
 
@@ -129,6 +136,67 @@ func GetLedgerIDs() ([]string, error) {
 	return ledgerProvider.List()
 }
 
+// IsLedgerPaused tells whether the ledger with the given id is paused on this peer
+func IsLedgerPaused(id string) (bool, error) {
+	pausedLock.RLock()
+	defer pausedLock.RUnlock()
+	if pausedLedgers == nil {
+		return false, ErrLedgerMgmtNotInitialized
+	}
+	_, paused := pausedLedgers[id]
+	return paused, nil
+}
+
+func loadPausedLedgers() {
+	ids, err := ledgerProvider.ListPaused()
+	if err != nil {
+		panic(errors.WithMessage(err, "Error in listing the paused ledgers"))
+	}
+	paused := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		paused[id] = struct{}{}
+	}
+	pausedLock.Lock()
+	pausedLedgers = paused
+	pausedLock.Unlock()
+}
+
+// RemoveLedger closes the ledger with the given id, if opened, and deletes all of its data.
+// The in-flight transaction simulators and query executors of the ledger are waited for
+// before the ledger is closed and the new ones are rejected
+func RemoveLedger(id string) error {
+	logger.Infof("Removing ledger [%s]", id)
+	lock.Lock()
+	if !initialized {
+		lock.Unlock()
+		return ErrLedgerMgmtNotInitialized
+	}
+	l, opened := openedLedgers[id]
+	lock.Unlock()
+
+	// the lock is not held while draining so that the other ledgers can be used in the meantime.
+	// The ledger stays in the opened ledgers map, which prevents it from being opened again
+	if opened {
+		if d, ok := l.(*closableLedger).PeerLedger.(drainable); ok {
+			d.Drain()
+		}
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	if l, ok := openedLedgers[id]; ok {
+		l.(*closableLedger).closeWithoutLock()
+	}
+	if err := ledgerProvider.Remove(id); err != nil {
+		return err
+	}
+	pausedLock.Lock()
+	delete(pausedLedgers, id)
+	pausedLock.Unlock()
+	logger.Infof("Removed ledger [%s]", id)
+	return nil
+}
+
 // Close closes all the opened ledgers and any resources held for ledger management
 func Close() {
 	logger.Infof("Closing ledger mgmt")
@@ -142,6 +210,9 @@ func Close() {
 	}
 	ledgerProvider.Close()
 	openedLedgers = nil
+	pausedLock.Lock()
+	pausedLedgers = nil
+	pausedLock.Unlock()
 	logger.Infof("ledger mgmt closed")
 }
 
@@ -149,6 +220,12 @@ func wrapLedger(id string, l ledger.PeerLedger) ledger.PeerLedger {
 	return &closableLedger{id, l}
 }
 
+// drainable is implemented by the ledgers that can wait for their in-flight
+// transaction simulators and query executors to be done
+type drainable interface {
+	Drain()
+}
+
 // closableLedger extends from actual validated ledger and overwrites the Close method
 type closableLedger struct {
 	id string
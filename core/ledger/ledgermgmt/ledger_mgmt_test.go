@@ -25,6 +25,8 @@ import (
 	"justledger/core/chaincode/platforms"
 	"justledger/core/chaincode/platforms/golang"
 	"justledger/core/ledger"
+	"justledger/core/ledger/kvledger"
+	"justledger/core/ledger/mock"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
@@ -94,6 +96,74 @@ func TestLedgerMgmt(t *testing.T) {
 	Close()
 }
 
+func TestPausedLedger(t *testing.T) {
+	InitializeTestEnv()
+	defer CleanupTestEnv()
+	for i := 0; i < 2; i++ {
+		gb, _ := test.MakeGenesisBlock(constructTestLedgerID(i))
+		_, err := CreateLedger(gb)
+		assert.NoError(t, err)
+	}
+	Close()
+
+	assert.NoError(t, kvledger.PauseChannel(constructTestLedgerID(1)))
+
+	initialize(&Initializer{
+		PlatformRegistry:              platforms.NewRegistry(&golang.Platform{}),
+		DeployedChaincodeInfoProvider: &mock.DeployedChaincodeInfoProvider{},
+	})
+	ids, err := GetLedgerIDs()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{constructTestLedgerID(0)}, ids)
+	paused, err := IsLedgerPaused(constructTestLedgerID(1))
+	assert.NoError(t, err)
+	assert.True(t, paused)
+	paused, err = IsLedgerPaused(constructTestLedgerID(0))
+	assert.NoError(t, err)
+	assert.False(t, paused)
+	_, err = OpenLedger(constructTestLedgerID(1))
+	assert.EqualError(t, err, "ledger [ledger_000001] is paused and cannot be opened")
+
+	Close()
+	_, err = IsLedgerPaused(constructTestLedgerID(1))
+	assert.Equal(t, ErrLedgerMgmtNotInitialized, err)
+}
+
+func TestRemoveLedger(t *testing.T) {
+	InitializeTestEnv()
+	defer CleanupTestEnv()
+	var ledgers []ledger.PeerLedger
+	for i := 0; i < 2; i++ {
+		gb, _ := test.MakeGenesisBlock(constructTestLedgerID(i))
+		l, err := CreateLedger(gb)
+		assert.NoError(t, err)
+		ledgers = append(ledgers, l)
+	}
+
+	assert.NoError(t, RemoveLedger(constructTestLedgerID(0)))
+	ids, err := GetLedgerIDs()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{constructTestLedgerID(1)}, ids)
+	_, err = ledgers[0].NewTxSimulator("txid")
+	assert.EqualError(t, err, "ledger [ledger_000000] is being closed")
+	_, err = OpenLedger(constructTestLedgerID(0))
+	assert.Equal(t, kvledger.ErrNonExistingLedgerID, err)
+	assert.EqualError(t, RemoveLedger(constructTestLedgerID(0)), kvledger.ErrNonExistingLedgerID.Error())
+
+	// the removed ledger can be created again
+	gb, _ := test.MakeGenesisBlock(constructTestLedgerID(0))
+	l, err := CreateLedger(gb)
+	assert.NoError(t, err)
+	bcInfo, err := l.GetBlockchainInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), bcInfo.Height)
+
+	// the other ledger is not affected
+	bcInfo, err = ledgers[1].GetBlockchainInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), bcInfo.Height)
+}
+
 func constructTestLedgerID(i int) string {
 	return fmt.Sprintf("ledger_%06d", i)
 }
@@ -0,0 +1,41 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledgerstorage
+
+import (
+	"justledger/common/ledger/blkstorage/fsblkstorage"
+	"justledger/core/ledger/ledgerconfig"
+	"justledger/core/ledger/pvtdatastorage"
+)
+
+// ValidateRollbackParams checks whether the ledger can be rolled back to the given block number
+func ValidateRollbackParams(ledgerID string, blockNum uint64) error {
+	return fsblkstorage.ValidateRollbackParams(ledgerconfig.GetBlockStorePath(), ledgerID, blockNum)
+}
+
+// Rollback rolls back the block store and the pvt data store of the given ledger to the given block number.
+// The block store is rolled back first so that a failure in between leaves the pvt data store ahead of the
+// block store, which is the same state as a peer that rejoins a channel
+func Rollback(ledgerID string, blockNum uint64) error {
+	if err := fsblkstorage.Rollback(ledgerconfig.GetBlockStorePath(), ledgerID, blockNum); err != nil {
+		return err
+	}
+	return pvtdatastorage.Rollback(ledgerID, blockNum)
+}
+
+// Reset resets the block stores and the pvt data stores of the given ledgers to the genesis block
+func Reset(ledgerIDs []string) error {
+	if err := fsblkstorage.ResetBlockStore(ledgerconfig.GetBlockStorePath()); err != nil {
+		return err
+	}
+	for _, ledgerID := range ledgerIDs {
+		if err := pvtdatastorage.Rollback(ledgerID, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
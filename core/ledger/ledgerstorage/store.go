@@ -13,10 +13,13 @@ import (
 	"justledger/common/ledger/blkstorage"
 	"justledger/common/ledger/blkstorage/fsblkstorage"
 	"justledger/core/ledger"
+	"justledger/core/ledger/kvledger/txmgmt/rwsetutil"
 	"justledger/core/ledger/ledgerconfig"
 	"justledger/core/ledger/pvtdatapolicy"
 	"justledger/core/ledger/pvtdatastorage"
+	"justledger/core/ledger/util"
 	"justledger/protos/common"
+	putils "justledger/protos/utils"
 	"github.com/pkg/errors"
 )
 
@@ -36,10 +39,9 @@ type Store struct {
 	rwlock       *sync.RWMutex
 }
 
-// NewProvider returns the handle to the provider
-func NewProvider() *Provider {
-	// Initialize the block storage
-	attrsToIndex := []blkstorage.IndexableAttr{
+// attrsToIndex returns the attributes of the blocks that are indexed in the block store
+func attrsToIndex() []blkstorage.IndexableAttr {
+	attrs := []blkstorage.IndexableAttr{
 		blkstorage.IndexableAttrBlockHash,
 		blkstorage.IndexableAttrBlockNum,
 		blkstorage.IndexableAttrTxID,
@@ -47,9 +49,22 @@ func NewProvider() *Provider {
 		blkstorage.IndexableAttrBlockTxID,
 		blkstorage.IndexableAttrTxValidationCode,
 	}
-	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex}
+	if ledgerconfig.IsTimeIndexEnabled() {
+		attrs = append(attrs, blkstorage.IndexableAttrBlockTime, blkstorage.IndexableAttrTxTimestamp)
+	}
+	return attrs
+}
+
+// NewProvider returns the handle to the provider
+func NewProvider() *Provider {
+	// Initialize the block storage
+	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex()}
+	compression, err := fsblkstorage.ParseCompression(ledgerconfig.GetBlockfileCompression())
+	if err != nil {
+		logger.Panicf("Invalid configuration of the block files: %s", err)
+	}
 	blockStoreProvider := fsblkstorage.NewProvider(
-		fsblkstorage.NewConf(ledgerconfig.GetBlockStorePath(), ledgerconfig.GetMaxBlockfileSize()),
+		fsblkstorage.NewConfWithCompression(ledgerconfig.GetBlockStorePath(), ledgerconfig.GetMaxBlockfileSize(), compression),
 		indexConfig)
 
 	pvtStoreProvider := pvtdatastorage.NewProvider()
@@ -75,6 +90,15 @@ func (p *Provider) Open(ledgerid string) (*Store, error) {
 	return store, nil
 }
 
+// Remove deletes the block store and the pvt data store of the given ledger.
+// The store of the ledger should have been shut down before calling this function
+func (p *Provider) Remove(ledgerid string) error {
+	if err := p.blkStoreProvider.Remove(ledgerid); err != nil {
+		return err
+	}
+	return p.pvtdataStoreProvider.Remove(ledgerid)
+}
+
 // Close closes the provider
 func (p *Provider) Close() {
 	p.blkStoreProvider.Close()
@@ -113,7 +137,8 @@ func (s *Store) CommitWithPvtData(blockAndPvtdata *ledger.BlockAndPvtData) error
 		for _, v := range blockAndPvtdata.BlockPvtData {
 			pvtdata = append(pvtdata, v)
 		}
-		if err := s.pvtdataStore.Prepare(blockAndPvtdata.Block.Header.Number, pvtdata, missingDataList); err != nil {
+		purges := purgesOfValidTxs(blockAndPvtdata.Block)
+		if err := s.pvtdataStore.Prepare(blockAndPvtdata.Block.Header.Number, pvtdata, missingDataList, purges); err != nil {
 			return err
 		}
 		writtenToPvtStore = true
@@ -267,3 +292,69 @@ func constructPvtdataMap(pvtdata []*ledger.TxPvtData) map[uint64]*ledger.TxPvtDa
 	}
 	return m
 }
+
+// purgesOfValidTxs returns the private data keys purged by the valid transactions of the block.
+// The purges are recorded in the public hashed rwsets so that the peers that are missing the
+// private data of a purged key still remove whatever they have
+func purgesOfValidTxs(block *common.Block) []*ledger.PvtdataPurge {
+	var purges []*ledger.PvtdataPurge
+	txsFilter := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	for txIndex, envBytes := range block.Data.Data {
+		if txIndex < len(txsFilter) && txsFilter.IsInvalid(txIndex) {
+			continue
+		}
+		txPurges, err := purgesOfTx(uint64(txIndex), envBytes)
+		if err != nil {
+			// a transaction whose rwset cannot be parsed does not purge anything,
+			// the validator marks it invalid and the state is not updated either
+			logger.Debugf("Could not extract purges from transaction [%d] of block [%d]: %s", txIndex, block.Header.Number, err)
+			continue
+		}
+		purges = append(purges, txPurges...)
+	}
+	return purges
+}
+
+func purgesOfTx(seqInBlock uint64, envBytes []byte) ([]*ledger.PvtdataPurge, error) {
+	env, err := putils.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := putils.GetPayload(env)
+	if err != nil {
+		return nil, err
+	}
+	chdr, err := putils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	if common.HeaderType(chdr.Type) != common.HeaderType_ENDORSER_TRANSACTION {
+		return nil, nil
+	}
+	ccAction, err := putils.GetActionFromEnvelope(envBytes)
+	if err != nil {
+		return nil, err
+	}
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(ccAction.Results); err != nil {
+		return nil, err
+	}
+
+	var purges []*ledger.PvtdataPurge
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		for _, collRWSet := range nsRWSet.CollHashedRwSets {
+			for _, hashedWrite := range collRWSet.HashedRwSet.HashedWrites {
+				if !hashedWrite.IsPurge {
+					continue
+				}
+				purges = append(purges, &ledger.PvtdataPurge{
+					SeqInBlock: seqInBlock,
+					Namespace:  nsRWSet.NameSpace,
+					Collection: collRWSet.CollectionName,
+					KeyHash:    hashedWrite.KeyHash,
+				})
+			}
+		}
+	}
+	return purges, nil
+}
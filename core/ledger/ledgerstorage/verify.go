@@ -0,0 +1,21 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledgerstorage
+
+import (
+	"justledger/common/ledger/blkstorage"
+	"justledger/common/ledger/blkstorage/fsblkstorage"
+	"justledger/core/ledger/ledgerconfig"
+	"justledger/protos/common"
+)
+
+// VerifyBlockStore checks the internal consistency of the block store of the given ledger and invokes verifyBlock
+// for every block in sequence. The number of blocks verified is returned
+func VerifyBlockStore(ledgerID string, verifyBlock func(block *common.Block) error) (uint64, error) {
+	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex()}
+	return fsblkstorage.VerifyBlockStore(ledgerconfig.GetBlockStorePath(), ledgerID, indexConfig, verifyBlock)
+}
@@ -7,11 +7,15 @@ SPDX-License-Identifier: Apache-2.0
 package pvtdatastorage
 
 import (
+	"bytes"
 	"math"
 
+	"github.com/golang/protobuf/proto"
 	"justledger/core/ledger"
 	"justledger/core/ledger/pvtdatapolicy"
+	"justledger/core/ledger/util"
 	"justledger/protos/ledger/rwset"
+	"justledger/protos/ledger/rwset/kvrwset"
 	"github.com/willf/bitset"
 )
 
@@ -47,6 +51,32 @@ func prepareDataEntries(blockNum uint64, pvtData []*ledger.TxPvtData) []*dataEnt
 	return dataEntries
 }
 
+// preparePurgeIndexKeys returns the keys of the entries of the index of the private data writes by key hash
+// for the writes, and the metadata writes, of the given data entries. The writes of a data entry that cannot
+// be parsed are not indexed, as they could not be purged selectively anyway
+func preparePurgeIndexKeys(dataEntries []*dataEntry) [][]byte {
+	var keys [][]byte
+	for _, dataEntry := range dataEntries {
+		kvRWSet := &kvrwset.KVRWSet{}
+		if err := proto.Unmarshal(dataEntry.value.Rwset, kvRWSet); err != nil {
+			logger.Warningf("Not indexing the private data writes of [%s:%s] at block [%d], tx [%d]: %s",
+				dataEntry.key.ns, dataEntry.key.coll, dataEntry.key.blkNum, dataEntry.key.txNum, err)
+			continue
+		}
+		writtenKeys := make(map[string]struct{})
+		for _, write := range kvRWSet.Writes {
+			writtenKeys[write.Key] = struct{}{}
+		}
+		for _, metadataWrite := range kvRWSet.MetadataWrites {
+			writtenKeys[metadataWrite.Key] = struct{}{}
+		}
+		for key := range writtenKeys {
+			keys = append(keys, encodePurgeIndexKey(dataEntry.key, util.ComputeStringHash(key)))
+		}
+	}
+	return keys
+}
+
 func prepareMissingDataEntries(committingBlk uint64, missingData *ledger.MissingPrivateDataList) map[missingDataKey]*bitset.BitSet {
 	if missingData == nil {
 		return nil
@@ -159,6 +189,59 @@ func deriveKeys(expiryEntry *expiryEntry) (dataKeys []*dataKey, missingDataKeys
 	return
 }
 
+// purgeKeysFromCollPvtRwset removes from the given collection rwset the writes of the keys
+// for which a purge marker exists at the same or a later height. The returned bool indicates
+// whether any write was removed and a nil rwset is returned if no write is left
+func purgeKeysFromCollPvtRwset(dataKey *dataKey, collPvtRwset *rwset.CollectionPvtReadWriteSet,
+	markers []*purgeMarkerKey) (*rwset.CollectionPvtReadWriteSet, bool, error) {
+	isPurged := func(key string) bool {
+		keyHash := util.ComputeStringHash(key)
+		for _, marker := range markers {
+			if (marker.blkNum > dataKey.blkNum || (marker.blkNum == dataKey.blkNum && marker.txNum >= dataKey.txNum)) &&
+				bytes.Equal(marker.keyHash, keyHash) {
+				return true
+			}
+		}
+		return false
+	}
+
+	kvRWSet := &kvrwset.KVRWSet{}
+	if err := proto.Unmarshal(collPvtRwset.Rwset, kvRWSet); err != nil {
+		return nil, false, err
+	}
+	purged := false
+	var writes []*kvrwset.KVWrite
+	for _, write := range kvRWSet.Writes {
+		if isPurged(write.Key) {
+			purged = true
+			continue
+		}
+		writes = append(writes, write)
+	}
+	var metadataWrites []*kvrwset.KVMetadataWrite
+	for _, metadataWrite := range kvRWSet.MetadataWrites {
+		if isPurged(metadataWrite.Key) {
+			purged = true
+			continue
+		}
+		metadataWrites = append(metadataWrites, metadataWrite)
+	}
+	if !purged {
+		return collPvtRwset, false, nil
+	}
+	if len(writes) == 0 && len(metadataWrites) == 0 {
+		return nil, true, nil
+	}
+
+	kvRWSet.Writes = writes
+	kvRWSet.MetadataWrites = metadataWrites
+	rwsetBytes, err := proto.Marshal(kvRWSet)
+	if err != nil {
+		return nil, false, err
+	}
+	return &rwset.CollectionPvtReadWriteSet{CollectionName: collPvtRwset.CollectionName, Rwset: rwsetBytes}, true, nil
+}
+
 func passesFilter(dataKey *dataKey, filter ledger.PvtNsCollFilter) bool {
 	return filter == nil || filter.Has(dataKey.ns, dataKey.coll)
 }
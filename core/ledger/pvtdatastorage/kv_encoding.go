@@ -23,6 +23,9 @@ var (
 	expiryKeyPrefix                = []byte{3}
 	eligibleMissingDataKeyPrefix   = []byte{4}
 	ineligibleMissingDataKeyPrefix = []byte{5}
+	purgeMarkerKeyPrefix           = []byte{6}
+	purgeIndexKeyPrefix            = []byte{7}
+	purgeIndexBuiltKey             = []byte{8}
 
 	nilByte    = byte(0)
 	emptyValue = []byte{}
@@ -145,6 +148,63 @@ func decodeMissingDataValue(bitmapBytes []byte) (*bitset.BitSet, error) {
 	return bitmap, nil
 }
 
+func encodePurgeMarkerKey(key *purgeMarkerKey) []byte {
+	keyBytes := append(purgeMarkerKeyPrefix, version.NewHeight(key.blkNum, key.txNum).ToBytes()...)
+	keyBytes = append(keyBytes, []byte(key.ns)...)
+	keyBytes = append(keyBytes, nilByte)
+	keyBytes = append(keyBytes, []byte(key.coll)...)
+	keyBytes = append(keyBytes, nilByte)
+	return append(keyBytes, key.keyHash...)
+}
+
+func decodePurgeMarkerKey(keyBytes []byte) *purgeMarkerKey {
+	v, n := version.NewHeightFromBytes(keyBytes[1:])
+	remainingBytes := keyBytes[n+1:]
+	nilByteIndex := bytes.IndexByte(remainingBytes, nilByte)
+	ns := string(remainingBytes[:nilByteIndex])
+	remainingBytes = remainingBytes[nilByteIndex+1:]
+	nilByteIndex = bytes.IndexByte(remainingBytes, nilByte)
+	coll := string(remainingBytes[:nilByteIndex])
+	keyHash := append([]byte{}, remainingBytes[nilByteIndex+1:]...)
+	return &purgeMarkerKey{nsCollBlk{ns, coll, v.BlockNum}, v.TxNum, keyHash}
+}
+
+func getPurgeMarkerKeysForRangeScan(maxBlkNum uint64) (startKey, endKey []byte) {
+	startKey = purgeMarkerKeyPrefix
+	endKey = append(purgeMarkerKeyPrefix, version.NewHeight(maxBlkNum+1, 0).ToBytes()...)
+	return
+}
+
+// encodePurgeIndexKey encodes the key of the entry of the index of the private data writes by key hash
+// that records the write of the key with the given hash by the given data entry
+func encodePurgeIndexKey(key *dataKey, keyHash []byte) []byte {
+	return append(getPurgeIndexKeyPrefix(key.ns, key.coll, keyHash), version.NewHeight(key.blkNum, key.txNum).ToBytes()...)
+}
+
+// decodePurgeIndexKey returns the key of the data entry recorded by an entry of the index of the private
+// data writes by key hash. The key hash is of fixed length, hence the prefix of the key is known
+func decodePurgeIndexKey(keyBytes []byte, ns, coll string, keyHash []byte) *dataKey {
+	v, _ := version.NewHeightFromBytes(keyBytes[len(getPurgeIndexKeyPrefix(ns, coll, keyHash)):])
+	return &dataKey{nsCollBlk{ns, coll, v.BlockNum}, v.TxNum}
+}
+
+func getPurgeIndexKeyPrefix(ns, coll string, keyHash []byte) []byte {
+	keyBytes := append(purgeIndexKeyPrefix, []byte(ns)...)
+	keyBytes = append(keyBytes, nilByte)
+	keyBytes = append(keyBytes, []byte(coll)...)
+	keyBytes = append(keyBytes, nilByte)
+	return append(keyBytes, keyHash...)
+}
+
+// getPurgeIndexKeysForRangeScan returns the range of the index entries of the writes of the key purged
+// by the given marker that were made at or before the height of the marker
+func getPurgeIndexKeysForRangeScan(marker *purgeMarkerKey) (startKey, endKey []byte) {
+	startKey = getPurgeIndexKeyPrefix(marker.ns, marker.coll, marker.keyHash)
+	endKey = append(getPurgeIndexKeyPrefix(marker.ns, marker.coll, marker.keyHash),
+		version.NewHeight(marker.blkNum, marker.txNum+1).ToBytes()...)
+	return
+}
+
 func createRangeScanKeysForEligibleMissingDataEntries(blkNum uint64) (startKey, endKey []byte) {
 	startKey = append(eligibleMissingDataKeyPrefix, util.EncodeReverseOrderVarUint64(blkNum)...)
 	endKey = append(eligibleMissingDataKeyPrefix, util.EncodeReverseOrderVarUint64(0)...)
@@ -0,0 +1,100 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pvtdatastorage
+
+import (
+	"justledger/common/ledger/util/leveldbhelper"
+	"justledger/core/ledger/kvledger/txmgmt/version"
+	"justledger/core/ledger/ledgerconfig"
+)
+
+// Rollback removes the private data, the expiry entries, the missing data entries, the purge markers and the
+// entries of the index of the private data writes by key hash of the
+// blocks above blockNum from the pvtdata store of the given ledger and marks blockNum as the
+// last committed block. The private data of the removed blocks is fetched again when the blocks
+// are committed again. The store must not be open while the rollback is in progress
+func Rollback(ledgerID string, blockNum uint64) error {
+	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: ledgerconfig.GetPvtdataStorePath()})
+	defer dbProvider.Close()
+	s := &store{db: dbProvider.GetDBHandle(ledgerID), ledgerid: ledgerID}
+	if err := s.initState(); err != nil {
+		return err
+	}
+	if s.isEmpty {
+		logger.Infof("Pvtdata store of ledger [%s] is empty. Nothing to rollback", ledgerID)
+		return nil
+	}
+
+	batch := leveldbhelper.NewUpdateBatch()
+	startKey := append(pvtDataKeyPrefix, version.NewHeight(blockNum+1, 0).ToBytes()...)
+	if err := s.addPurgeIndexKeysToBeDeleted(batch, startKey, expiryKeyPrefix); err != nil {
+		return err
+	}
+	if err := s.addKeysToBeDeleted(batch, startKey, expiryKeyPrefix, func(key []byte) bool {
+		return true
+	}); err != nil {
+		return err
+	}
+	if err := s.addKeysToBeDeleted(batch, expiryKeyPrefix, eligibleMissingDataKeyPrefix, func(key []byte) bool {
+		return decodeExpiryKey(key).committingBlk > blockNum
+	}); err != nil {
+		return err
+	}
+	if err := s.addKeysToBeDeleted(batch, eligibleMissingDataKeyPrefix, []byte{ineligibleMissingDataKeyPrefix[0] + 1}, func(key []byte) bool {
+		return decodeMissingDataKey(key).blkNum > blockNum
+	}); err != nil {
+		return err
+	}
+	purgeMarkersStartKey := append(purgeMarkerKeyPrefix, version.NewHeight(blockNum+1, 0).ToBytes()...)
+	if err := s.addKeysToBeDeleted(batch, purgeMarkersStartKey, []byte{purgeMarkerKeyPrefix[0] + 1}, func(key []byte) bool {
+		return true
+	}); err != nil {
+		return err
+	}
+	batch.Delete(pendingCommitKey)
+	if s.lastCommittedBlock > blockNum {
+		batch.Put(lastCommittedBlkkey, encodeLastCommittedBlockVal(blockNum))
+	}
+	logger.Infof("Rolling back pvtdata store of ledger [%s] to block number [%d]", ledgerID, blockNum)
+	return s.db.WriteBatch(batch, true)
+}
+
+// addKeysToBeDeleted adds a delete to the batch for every key in the range [startKey, endKey)
+// that satisfies the given condition
+func (s *store) addKeysToBeDeleted(batch *leveldbhelper.UpdateBatch, startKey, endKey []byte, toDelete func([]byte) bool) error {
+	itr := s.db.GetIterator(startKey, endKey)
+	defer itr.Release()
+	for itr.Next() {
+		key := append([]byte{}, itr.Key()...)
+		if toDelete(key) {
+			batch.Delete(key)
+		}
+	}
+	return itr.Error()
+}
+
+// addPurgeIndexKeysToBeDeleted adds a delete to the batch for every entry of the index of the private data writes
+// by key hash that records a write of the data entries in the range [startKey, endKey)
+func (s *store) addPurgeIndexKeysToBeDeleted(batch *leveldbhelper.UpdateBatch, startKey, endKey []byte) error {
+	itr := s.db.GetIterator(startKey, endKey)
+	defer itr.Release()
+	for itr.Next() {
+		dataKeyBytes := itr.Key()
+		if v11Format(dataKeyBytes) {
+			// the private data of a block in the v1.1 format is not indexed
+			continue
+		}
+		dataValue, err := decodeDataValue(itr.Value())
+		if err != nil {
+			return err
+		}
+		for _, keyBytes := range preparePurgeIndexKeys([]*dataEntry{{key: decodeDatakey(dataKeyBytes), value: dataValue}}) {
+			batch.Delete(keyBytes)
+		}
+	}
+	return itr.Error()
+}
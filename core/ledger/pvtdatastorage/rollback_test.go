@@ -0,0 +1,77 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pvtdatastorage
+
+import (
+	"testing"
+
+	"justledger/core/ledger"
+	"justledger/core/ledger/pvtdatapolicy"
+	btltestutil "justledger/core/ledger/pvtdatapolicy/testutil"
+	"justledger/core/ledger/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollback(t *testing.T) {
+	ledgerid := "TestRollback"
+	cs := btltestutil.NewMockCollectionStore()
+	cs.SetBTL("ns-1", "coll-1", 10)
+	cs.SetBTL("ns-1", "coll-2", 0)
+	btlPolicy := pvtdatapolicy.ConstructBTLPolicy(cs)
+
+	env := NewTestStoreEnv(t, ledgerid, btlPolicy)
+	defer env.Cleanup()
+	s := env.TestStore
+
+	testData := []*ledger.TxPvtData{
+		produceSamplePvtdata(t, 2, []string{"ns-1:coll-1", "ns-1:coll-2"}),
+	}
+	require.NoError(t, s.Prepare(0, nil, nil, nil))
+	require.NoError(t, s.Commit())
+	for blkNum := uint64(1); blkNum <= 4; blkNum++ {
+		missingData := &ledger.MissingPrivateDataList{}
+		missingData.Add("tx1", 1, "ns-1", "coll-1", true)
+		missingData.Add("tx3", 3, "ns-1", "coll-2", false)
+		require.NoError(t, s.Prepare(blkNum, testData, missingData, nil))
+		require.NoError(t, s.Commit())
+	}
+	// a pending batch is discarded by the rollback
+	require.NoError(t, s.Prepare(5, testData, nil, nil))
+	env.TestStoreProvider.Close()
+
+	require.NoError(t, Rollback(ledgerid, 2))
+
+	env.CloseAndReopen()
+	s = env.TestStore
+	testEmpty(false, assert.New(t), s)
+	testPendingBatch(false, assert.New(t), s)
+	testLastCommittedBlockHeight(3, assert.New(t), s)
+	for blkNum := uint64(1); blkNum <= 4; blkNum++ {
+		retained := blkNum <= 2
+		dataKey := &dataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: blkNum}, txNum: 2}
+		assert.Equal(t, retained, testDataKeyExists(t, s, dataKey))
+		assert.Equal(t, retained, testPurgeIndexKeyExists(t, s, dataKey, util.ComputeStringHash("key-ns-1-coll-1")))
+		elgMD := &missingDataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: blkNum}, isEligible: true}
+		assert.Equal(t, retained, testMissingDataKeyExists(t, s, elgMD))
+		inelgMD := &missingDataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-2", blkNum: blkNum}, isEligible: false}
+		assert.Equal(t, retained, testMissingDataKeyExists(t, s, inelgMD))
+
+		expiryEntries, err := s.(*store).retrieveExpiryEntries(blkNum+11, blkNum+11)
+		require.NoError(t, err)
+		assert.Equal(t, retained, len(expiryEntries) == 1)
+	}
+
+	// the removed blocks can be committed again
+	require.NoError(t, s.Prepare(3, testData, nil, nil))
+	require.NoError(t, s.Commit())
+	testLastCommittedBlockHeight(4, assert.New(t), s)
+
+	// rolling back an empty store is a no-op
+	env.TestStoreProvider.Close()
+	require.NoError(t, Rollback("EmptyLedger", 0))
+}
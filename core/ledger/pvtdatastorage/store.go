@@ -15,6 +15,8 @@ import (
 // private write sets for a ledger
 type Provider interface {
 	OpenStore(id string) (Store, error)
+	// Remove deletes the store of the given ledger. The store should not be in use
+	Remove(id string) error
 	Close()
 }
 
@@ -54,8 +56,10 @@ type Store interface {
 	// is expected to call either `Commit` or `Rollback` function. Return from this should ensure
 	// that enough preparation is done such that `Commit` function invoked afterwards can commit the
 	// data and the store is capable of surviving a crash between this function call and the next
-	// invoke to the `Commit`
-	Prepare(blockNum uint64, pvtData []*ledger.TxPvtData, missing *ledger.MissingPrivateDataList) error
+	// invoke to the `Commit`.
+	// The supplied purges are recorded along with the pvt data and, once the block is committed, the private
+	// data of the purged keys written at or before the height of the purge is removed from the store
+	Prepare(blockNum uint64, pvtData []*ledger.TxPvtData, missing *ledger.MissingPrivateDataList, purges []*ledger.PvtdataPurge) error
 	// Commit commits the pvt data passed in the previous invoke to the `Prepare` function
 	Commit() error
 	// Rollback rolls back the pvt data passed in the previous invoke to the `Prepare` function
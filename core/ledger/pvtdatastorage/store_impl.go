@@ -22,6 +22,10 @@ import (
 
 var logger = flogging.MustGetLogger("pvtdatastorage")
 
+// maxPurgeIndexBatchSize is the number of index entries written at once while the index of the
+// private data writes by key hash is built for an existing store
+const maxPurgeIndexBatchSize = 10000
+
 type provider struct {
 	dbProvider *leveldbhelper.Provider
 }
@@ -34,6 +38,7 @@ type store struct {
 	isEmpty            bool
 	lastCommittedBlock uint64
 	batchPending       bool
+	purgesPending      bool
 	purgerLock         sync.Mutex
 }
 
@@ -69,6 +74,12 @@ type missingDataKey struct {
 	isEligible bool
 }
 
+type purgeMarkerKey struct {
+	nsCollBlk
+	txNum   uint64
+	keyHash []byte
+}
+
 type storeEntries struct {
 	dataEntries        []*dataEntry
 	expiryEntries      []*expiryEntry
@@ -92,11 +103,19 @@ func (p *provider) OpenStore(ledgerid string) (Store, error) {
 	if err := s.initState(); err != nil {
 		return nil, err
 	}
+	if err := s.buildPurgeIndexIfNeeded(); err != nil {
+		return nil, err
+	}
 	logger.Debugf("Pvtdata store opened. Initial state: isEmpty [%t], lastCommittedBlock [%d], batchPending [%t]",
 		s.isEmpty, s.lastCommittedBlock, s.batchPending)
 	return s, nil
 }
 
+// Remove deletes all the private data of the given ledger
+func (p *provider) Remove(ledgerid string) error {
+	return p.dbProvider.GetDBHandle(ledgerid).DeleteAll()
+}
+
 // Close closes the store
 func (p *provider) Close() {
 	p.dbProvider.Close()
@@ -122,7 +141,8 @@ func (s *store) Init(btlPolicy pvtdatapolicy.BTLPolicy) {
 }
 
 // Prepare implements the function in the interface `Store`
-func (s *store) Prepare(blockNum uint64, pvtData []*ledger.TxPvtData, missingData *ledger.MissingPrivateDataList) error {
+func (s *store) Prepare(blockNum uint64, pvtData []*ledger.TxPvtData, missingData *ledger.MissingPrivateDataList,
+	purges []*ledger.PvtdataPurge) error {
 	if s.batchPending {
 		return &ErrIllegalCall{`A pending batch exists as as result of last invoke to "Prepare" call.
 			 Invoke "Commit" or "Rollback" on the pending batch before invoking "Prepare" function`}
@@ -150,6 +170,10 @@ func (s *store) Prepare(blockNum uint64, pvtData []*ledger.TxPvtData, missingDat
 		batch.Put(keyBytes, valBytes)
 	}
 
+	for _, keyBytes := range preparePurgeIndexKeys(storeEntries.dataEntries) {
+		batch.Put(keyBytes, emptyValue)
+	}
+
 	for _, expiryEntry := range storeEntries.expiryEntries {
 		keyBytes = encodeExpiryKey(expiryEntry.key)
 		if valBytes, err = encodeExpiryValue(expiryEntry.value); err != nil {
@@ -166,11 +190,18 @@ func (s *store) Prepare(blockNum uint64, pvtData []*ledger.TxPvtData, missingDat
 		batch.Put(keyBytes, valBytes)
 	}
 
+	for _, purge := range purges {
+		keyBytes = encodePurgeMarkerKey(&purgeMarkerKey{
+			nsCollBlk{purge.Namespace, purge.Collection, blockNum}, purge.SeqInBlock, purge.KeyHash})
+		batch.Put(keyBytes, emptyValue)
+	}
+
 	batch.Put(pendingCommitKey, emptyValue)
 	if err := s.db.WriteBatch(batch, true); err != nil {
 		return err
 	}
 	s.batchPending = true
+	s.purgesPending = len(purges) > 0
 	logger.Debugf("Saved %d private data write sets for block [%d]", len(pvtData), blockNum)
 	return nil
 }
@@ -192,6 +223,10 @@ func (s *store) Commit() error {
 	s.isEmpty = false
 	s.lastCommittedBlock = committingBlockNum
 	logger.Debugf("Committed private data for block [%d]", committingBlockNum)
+	if s.purgesPending {
+		s.purgesPending = false
+		s.performPurgeOfMarkedKeys(committingBlockNum)
+	}
 	s.performPurgeIfScheduled(committingBlockNum)
 	return nil
 }
@@ -204,6 +239,7 @@ func (s *store) Rollback() error {
 		return &ErrIllegalCall{"No pending batch to rollback"}
 	}
 	s.batchPending = false
+	s.purgesPending = false
 	return nil
 }
 
@@ -364,10 +400,166 @@ func (s *store) performPurgeIfScheduled(latestCommittedBlk uint64) {
 		if err != nil {
 			logger.Warningf("Could not purge data from pvtdata store:%s", err)
 		}
+		// the purges marked by the blocks committed just before a crash are picked up here
+		if err := s.purgeMarkedKeys(latestCommittedBlk); err != nil {
+			logger.Warningf("Could not purge marked keys from pvtdata store:%s", err)
+		}
 		logger.Debug("Purger finished")
 	}()
 }
 
+func (s *store) performPurgeOfMarkedKeys(latestCommittedBlk uint64) {
+	go func() {
+		s.purgerLock.Lock()
+		logger.Debugf("Purger started: Purging marked keys till block number [%d]", latestCommittedBlk)
+		defer s.purgerLock.Unlock()
+		if err := s.purgeMarkedKeys(latestCommittedBlk); err != nil {
+			logger.Warningf("Could not purge marked keys from pvtdata store:%s", err)
+		}
+		logger.Debug("Purger finished")
+	}()
+}
+
+// purgeMarkedKeys removes, from the private data of the blocks till maxBlkNum, the writes of the keys
+// marked for purge by the blocks till maxBlkNum. A write is removed only if it was made at or before
+// the height of the purge. Once processed, the purge markers are deleted
+func (s *store) purgeMarkedKeys(maxBlkNum uint64) error {
+	markers, err := s.retrievePurgeMarkers(maxBlkNum)
+	if err != nil || len(markers) == 0 {
+		return err
+	}
+	markersByNsColl := make(map[[2]string][]*purgeMarkerKey)
+	for _, marker := range markers {
+		nsColl := [2]string{marker.ns, marker.coll}
+		markersByNsColl[nsColl] = append(markersByNsColl[nsColl], marker)
+	}
+
+	// only the data entries holding the writes of the purged keys are visited, through the index of the
+	// private data writes by key hash. The index entries of the purged writes are removed along with them
+	batch := leveldbhelper.NewUpdateBatch()
+	dataKeys := make(map[dataKey]struct{})
+	for _, marker := range markers {
+		indexKeys, markedDataKeys, err := s.retrievePurgeIndexEntries(marker)
+		if err != nil {
+			return err
+		}
+		for i, indexKey := range indexKeys {
+			batch.Delete(indexKey)
+			dataKeys[*markedDataKeys[i]] = struct{}{}
+		}
+	}
+
+	numPurgedEntries := 0
+	for key := range dataKeys {
+		dataKeyBytes := encodeDataKey(&key)
+		dataValueBytes, err := s.db.Get(dataKeyBytes)
+		if err != nil {
+			return err
+		}
+		if dataValueBytes == nil {
+			// the data entry expired, or was rolled back, after its writes were indexed
+			continue
+		}
+		dataValue, err := decodeDataValue(dataValueBytes)
+		if err != nil {
+			return err
+		}
+		purgedValue, purged, err := purgeKeysFromCollPvtRwset(&key, dataValue, markersByNsColl[[2]string{key.ns, key.coll}])
+		if err != nil {
+			return err
+		}
+		if !purged {
+			continue
+		}
+		numPurgedEntries++
+		if purgedValue == nil {
+			batch.Delete(dataKeyBytes)
+			continue
+		}
+		valBytes, err := encodeDataValue(purgedValue)
+		if err != nil {
+			return err
+		}
+		batch.Put(dataKeyBytes, valBytes)
+	}
+	for _, marker := range markers {
+		batch.Delete(encodePurgeMarkerKey(marker))
+	}
+	if err := s.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	logger.Infof("[%s] - [%d] Keys purged from [%d] entries of private data storage till block number [%d]",
+		s.ledgerid, len(markers), numPurgedEntries, maxBlkNum)
+	return nil
+}
+
+// retrievePurgeIndexEntries returns the keys of the index entries, and of the data entries, of the writes
+// of the key purged by the given marker that were made at or before the height of the marker
+func (s *store) retrievePurgeIndexEntries(marker *purgeMarkerKey) ([][]byte, []*dataKey, error) {
+	startKey, endKey := getPurgeIndexKeysForRangeScan(marker)
+	itr := s.db.GetIterator(startKey, endKey)
+	defer itr.Release()
+
+	var indexKeys [][]byte
+	var dataKeys []*dataKey
+	for itr.Next() {
+		indexKey := append([]byte{}, itr.Key()...)
+		indexKeys = append(indexKeys, indexKey)
+		dataKeys = append(dataKeys, decodePurgeIndexKey(indexKey, marker.ns, marker.coll, marker.keyHash))
+	}
+	return indexKeys, dataKeys, itr.Error()
+}
+
+// buildPurgeIndexIfNeeded indexes by key hash the writes of the private data committed before the
+// index of the private data writes by key hash was introduced. The index is built once per store
+func (s *store) buildPurgeIndexIfNeeded() error {
+	built, err := s.db.Get(purgeIndexBuiltKey)
+	if err != nil || built != nil {
+		return err
+	}
+	batch := leveldbhelper.NewUpdateBatch()
+	itr := s.db.GetIterator(pvtDataKeyPrefix, expiryKeyPrefix)
+	defer itr.Release()
+	for itr.Next() {
+		dataKeyBytes := itr.Key()
+		if v11Format(dataKeyBytes) {
+			// the private data of a block in the v1.1 format cannot be purged selectively
+			continue
+		}
+		dataValue, err := decodeDataValue(itr.Value())
+		if err != nil {
+			return err
+		}
+		for _, keyBytes := range preparePurgeIndexKeys([]*dataEntry{{key: decodeDatakey(dataKeyBytes), value: dataValue}}) {
+			batch.Put(keyBytes, emptyValue)
+		}
+		if len(batch.KVs) >= maxPurgeIndexBatchSize {
+			if err := s.db.WriteBatch(batch, false); err != nil {
+				return err
+			}
+			batch = leveldbhelper.NewUpdateBatch()
+		}
+	}
+	if err := itr.Error(); err != nil {
+		return err
+	}
+	batch.Put(purgeIndexBuiltKey, emptyValue)
+	logger.Debugf("Built the index of the private data writes of ledger [%s]", s.ledgerid)
+	return s.db.WriteBatch(batch, true)
+}
+
+func (s *store) retrievePurgeMarkers(maxBlkNum uint64) ([]*purgeMarkerKey, error) {
+	startKey, endKey := getPurgeMarkerKeysForRangeScan(maxBlkNum)
+	itr := s.db.GetIterator(startKey, endKey)
+	defer itr.Release()
+
+	var markers []*purgeMarkerKey
+	for itr.Next() {
+		markers = append(markers, decodePurgeMarkerKey(itr.Key()))
+	}
+	return markers, itr.Error()
+}
+
 func (s *store) purgeExpiredData(minBlkNum, maxBlkNum uint64) error {
 	batch := leveldbhelper.NewUpdateBatch()
 	expiryEntries, err := s.retrieveExpiryEntries(minBlkNum, maxBlkNum)
@@ -380,7 +572,15 @@ func (s *store) purgeExpiredData(minBlkNum, maxBlkNum uint64) error {
 		batch.Delete(encodeExpiryKey(expiryEntry.key))
 		dataKeys, missingDataKeys := deriveKeys(expiryEntry)
 		for _, dataKey := range dataKeys {
-			batch.Delete(encodeDataKey(dataKey))
+			dataKeyBytes := encodeDataKey(dataKey)
+			purgeIndexKeys, err := s.retrievePurgeIndexKeys(dataKeyBytes)
+			if err != nil {
+				return err
+			}
+			for _, purgeIndexKey := range purgeIndexKeys {
+				batch.Delete(purgeIndexKey)
+			}
+			batch.Delete(dataKeyBytes)
 		}
 		for _, missingDataKey := range missingDataKeys {
 			batch.Delete(encodeMissingDataKey(missingDataKey))
@@ -391,6 +591,20 @@ func (s *store) purgeExpiredData(minBlkNum, maxBlkNum uint64) error {
 	return nil
 }
 
+// retrievePurgeIndexKeys returns the keys of the entries of the index of the private data writes by key hash
+// that record the writes of the data entry with the given key, if the data entry exists
+func (s *store) retrievePurgeIndexKeys(dataKeyBytes []byte) ([][]byte, error) {
+	dataValueBytes, err := s.db.Get(dataKeyBytes)
+	if err != nil || dataValueBytes == nil {
+		return nil, err
+	}
+	dataValue, err := decodeDataValue(dataValueBytes)
+	if err != nil {
+		return nil, err
+	}
+	return preparePurgeIndexKeys([]*dataEntry{{key: decodeDatakey(dataKeyBytes), value: dataValue}}), nil
+}
+
 func (s *store) retrieveExpiryEntries(minBlkNum, maxBlkNum uint64) ([]*expiryEntry, error) {
 	startKey, endKey := getExpiryKeysForRangeScan(minBlkNum, maxBlkNum)
 	logger.Debugf("retrieveExpiryEntries(): startKey=%#v, endKey=%#v", startKey, endKey)
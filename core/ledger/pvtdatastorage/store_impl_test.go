@@ -20,6 +20,7 @@ import (
 	"justledger/core/ledger"
 	"justledger/core/ledger/kvledger/txmgmt/rwsetutil"
 	btltestutil "justledger/core/ledger/pvtdatapolicy/testutil"
+	"justledger/core/ledger/util"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
@@ -80,15 +81,15 @@ func TestStoreBasicCommitAndRetrieval(t *testing.T) {
 	blk2MissingData.Add("tx1", 1, "ns-1", "coll-2", true)
 
 	// no pvt data with block 0
-	assert.NoError(store.Prepare(0, nil, nil))
+	assert.NoError(store.Prepare(0, nil, nil, nil))
 	assert.NoError(store.Commit())
 
 	// pvt data with block 1 - commit
-	assert.NoError(store.Prepare(1, testData, blk1MissingData))
+	assert.NoError(store.Prepare(1, testData, blk1MissingData, nil))
 	assert.NoError(store.Commit())
 
 	// pvt data with block 2 - rollback
-	assert.NoError(store.Prepare(2, testData, nil))
+	assert.NoError(store.Prepare(2, testData, nil, nil))
 	assert.NoError(store.Rollback())
 
 	// pvt data retrieval for block 0 should return nil
@@ -126,7 +127,7 @@ func TestStoreBasicCommitAndRetrieval(t *testing.T) {
 	assert.Nil(retrievedData)
 
 	// pvt data with block 2 - commit
-	assert.NoError(store.Prepare(2, testData, blk2MissingData))
+	assert.NoError(store.Prepare(2, testData, blk2MissingData, nil))
 	assert.NoError(store.Commit())
 
 	// retrieve the stored missing entries using GetMissingPvtDataInfoForMostRecentBlocks
@@ -193,7 +194,7 @@ func TestExpiryDataNotIncluded(t *testing.T) {
 	blk2MissingData.Add("tx1", 1, "ns-1", "coll-2", true)
 
 	// no pvt data with block 0
-	assert.NoError(store.Prepare(0, nil, nil))
+	assert.NoError(store.Prepare(0, nil, nil, nil))
 	assert.NoError(store.Commit())
 
 	// write pvt data for block 1
@@ -201,7 +202,7 @@ func TestExpiryDataNotIncluded(t *testing.T) {
 		produceSamplePvtdata(t, 2, []string{"ns-1:coll-1", "ns-1:coll-2", "ns-2:coll-1", "ns-2:coll-2"}),
 		produceSamplePvtdata(t, 4, []string{"ns-1:coll-1", "ns-1:coll-2", "ns-2:coll-1", "ns-2:coll-2"}),
 	}
-	assert.NoError(store.Prepare(1, testDataForBlk1, blk1MissingData))
+	assert.NoError(store.Prepare(1, testDataForBlk1, blk1MissingData, nil))
 	assert.NoError(store.Commit())
 
 	// write pvt data for block 2
@@ -209,7 +210,7 @@ func TestExpiryDataNotIncluded(t *testing.T) {
 		produceSamplePvtdata(t, 3, []string{"ns-1:coll-1", "ns-1:coll-2", "ns-2:coll-1", "ns-2:coll-2"}),
 		produceSamplePvtdata(t, 5, []string{"ns-1:coll-1", "ns-1:coll-2", "ns-2:coll-1", "ns-2:coll-2"}),
 	}
-	assert.NoError(store.Prepare(2, testDataForBlk2, blk2MissingData))
+	assert.NoError(store.Prepare(2, testDataForBlk2, blk2MissingData, nil))
 	assert.NoError(store.Commit())
 
 	retrievedData, _ := store.GetPvtDataByBlockNum(1, nil)
@@ -234,7 +235,7 @@ func TestExpiryDataNotIncluded(t *testing.T) {
 	assert.Equal(expectedMissingPvtDataInfo, missingPvtDataInfo)
 
 	// Commit block 3 with no pvtdata
-	assert.NoError(store.Prepare(3, nil, nil))
+	assert.NoError(store.Prepare(3, nil, nil, nil))
 	assert.NoError(store.Commit())
 
 	// After committing block 3, the data for "ns-1:coll1" of block 1 should have expired and should not be returned by the store
@@ -258,7 +259,7 @@ func TestExpiryDataNotIncluded(t *testing.T) {
 	assert.Equal(expectedMissingPvtDataInfo, missingPvtDataInfo)
 
 	// Commit block 4 with no pvtdata
-	assert.NoError(store.Prepare(4, nil, nil))
+	assert.NoError(store.Prepare(4, nil, nil, nil))
 	assert.NoError(store.Commit())
 
 	// After committing block 4, the data for "ns-2:coll2" of block 1 should also have expired and should not be returned by the store
@@ -309,7 +310,7 @@ func TestStorePurge(t *testing.T) {
 	s := env.TestStore
 
 	// no pvt data with block 0
-	assert.NoError(s.Prepare(0, nil, nil))
+	assert.NoError(s.Prepare(0, nil, nil, nil))
 	assert.NoError(s.Commit())
 
 	// construct missing data for block 1
@@ -326,11 +327,11 @@ func TestStorePurge(t *testing.T) {
 		produceSamplePvtdata(t, 2, []string{"ns-1:coll-1", "ns-1:coll-2", "ns-2:coll-1", "ns-2:coll-2"}),
 		produceSamplePvtdata(t, 4, []string{"ns-1:coll-1", "ns-1:coll-2", "ns-2:coll-1", "ns-2:coll-2"}),
 	}
-	assert.NoError(s.Prepare(1, testDataForBlk1, blk1MissingData))
+	assert.NoError(s.Prepare(1, testDataForBlk1, blk1MissingData, nil))
 	assert.NoError(s.Commit())
 
 	// write pvt data for block 2
-	assert.NoError(s.Prepare(2, nil, nil))
+	assert.NoError(s.Prepare(2, nil, nil, nil))
 	assert.NoError(s.Commit())
 	// data for ns-1:coll-1 and ns-2:coll-2 should exist in store
 	ns1_coll1 := &dataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: 1}, txNum: 2}
@@ -355,7 +356,7 @@ func TestStorePurge(t *testing.T) {
 	assert.True(testMissingDataKeyExists(t, s, ns3_coll2_inelgMD))
 
 	// write pvt data for block 3
-	assert.NoError(s.Prepare(3, nil, nil))
+	assert.NoError(s.Prepare(3, nil, nil, nil))
 	assert.NoError(s.Commit())
 	// data for ns-1:coll-1 and ns-2:coll-2 should exist in store (because purger should not be launched at block 3)
 	testWaitForPurgerRoutineToFinish(s)
@@ -369,7 +370,7 @@ func TestStorePurge(t *testing.T) {
 	assert.True(testMissingDataKeyExists(t, s, ns3_coll2_inelgMD))
 
 	// write pvt data for block 4
-	assert.NoError(s.Prepare(4, nil, nil))
+	assert.NoError(s.Prepare(4, nil, nil, nil))
 	assert.NoError(s.Commit())
 	// data for ns-1:coll-1 should not exist in store (because purger should be launched at block 4)
 	// but ns-2:coll-2 should exist because it expires at block 5
@@ -384,7 +385,7 @@ func TestStorePurge(t *testing.T) {
 	assert.True(testMissingDataKeyExists(t, s, ns3_coll2_inelgMD))
 
 	// write pvt data for block 5
-	assert.NoError(s.Prepare(5, nil, nil))
+	assert.NoError(s.Prepare(5, nil, nil, nil))
 	assert.NoError(s.Commit())
 	// ns-2:coll-2 should exist because though the data expires at block 5 but purger is launched every second block
 	testWaitForPurgerRoutineToFinish(s)
@@ -392,7 +393,7 @@ func TestStorePurge(t *testing.T) {
 	assert.True(testDataKeyExists(t, s, ns2_coll2))
 
 	// write pvt data for block 6
-	assert.NoError(s.Prepare(6, nil, nil))
+	assert.NoError(s.Prepare(6, nil, nil, nil))
 	assert.NoError(s.Commit())
 	// ns-2:coll-2 should not exists now (because purger should be launched at block 6)
 	testWaitForPurgerRoutineToFinish(s)
@@ -403,6 +404,140 @@ func TestStorePurge(t *testing.T) {
 	assert.True(testDataKeyExists(t, s, &dataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-2", blkNum: 1}, txNum: 2}))
 }
 
+func TestStorePurgeExpiredPurgeIndex(t *testing.T) {
+	viper.Set("ledger.pvtdataStore.purgeInterval", 2)
+	cs := btltestutil.NewMockCollectionStore()
+	cs.SetBTL("ns-1", "coll-1", 1)
+	cs.SetBTL("ns-1", "coll-2", 1)
+	btlPolicy := pvtdatapolicy.ConstructBTLPolicy(cs)
+
+	env := NewTestStoreEnv(t, "TestStorePurgeExpiredPurgeIndex", btlPolicy)
+	defer env.Cleanup()
+	assert := assert.New(t)
+	s := env.TestStore
+
+	testDataForBlk1 := []*ledger.TxPvtData{
+		produceSamplePvtdata(t, 2, []string{"ns-1:coll-1", "ns-1:coll-2"}),
+	}
+	assert.NoError(s.Prepare(0, nil, nil, nil))
+	assert.NoError(s.Commit())
+	assert.NoError(s.Prepare(1, testDataForBlk1, nil, nil))
+	assert.NoError(s.Commit())
+	keyHash := util.ComputeStringHash("key-ns-1-coll-1")
+	assert.True(testPurgeIndexKeyExists(t, s, &dataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: 1}, txNum: 2}, keyHash))
+
+	// the data expires at block 3 and is purged at block 4, along with its index entries
+	for blkNum := uint64(2); blkNum <= 4; blkNum++ {
+		assert.NoError(s.Prepare(blkNum, nil, nil, nil))
+		assert.NoError(s.Commit())
+	}
+	testWaitForPurgerRoutineToFinish(s)
+	assert.False(testDataKeyExists(t, s, &dataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: 1}, txNum: 2}))
+	itr := s.(*store).db.GetIterator(purgeIndexKeyPrefix, []byte{purgeIndexKeyPrefix[0] + 1})
+	defer itr.Release()
+	assert.False(itr.Next())
+}
+
+func TestStorePurgeMarkedKeys(t *testing.T) {
+	cs := btltestutil.NewMockCollectionStore()
+	cs.SetBTL("ns-1", "coll-1", 0)
+	cs.SetBTL("ns-1", "coll-2", 0)
+	btlPolicy := pvtdatapolicy.ConstructBTLPolicy(cs)
+
+	env := NewTestStoreEnv(t, "TestStorePurgeMarkedKeys", btlPolicy)
+	defer env.Cleanup()
+	assert := assert.New(t)
+	s := env.TestStore
+
+	assert.NoError(s.Prepare(0, nil, nil, nil))
+	assert.NoError(s.Commit())
+
+	// write pvt data for block 1
+	testDataForBlk1 := []*ledger.TxPvtData{
+		produceSamplePvtdata(t, 2, []string{"ns-1:coll-1", "ns-1:coll-2"}),
+		produceSamplePvtdata(t, 4, []string{"ns-1:coll-1", "ns-1:coll-2"}),
+	}
+	assert.NoError(s.Prepare(1, testDataForBlk1, nil, nil))
+	assert.NoError(s.Commit())
+
+	// block 2 purges the key of ns-1:coll-1 in tx 1 and writes the key again in tx 3
+	testDataForBlk2 := []*ledger.TxPvtData{
+		produceSamplePvtdata(t, 3, []string{"ns-1:coll-1"}),
+	}
+	purge := &ledger.PvtdataPurge{
+		SeqInBlock: 1,
+		Namespace:  "ns-1",
+		Collection: "coll-1",
+		KeyHash:    util.ComputeStringHash("key-ns-1-coll-1"),
+	}
+	assert.NoError(s.Prepare(2, testDataForBlk2, nil, []*ledger.PvtdataPurge{purge}))
+	marker := &purgeMarkerKey{nsCollBlk{"ns-1", "coll-1", 2}, 1, purge.KeyHash}
+	assert.NoError(s.Commit())
+	testWaitForPurgerRoutineToFinish(s)
+
+	// the writes till the purge are removed, the write after the purge and the other collections are retained
+	assert.False(testDataKeyExists(t, s, &dataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: 1}, txNum: 2}))
+	assert.False(testDataKeyExists(t, s, &dataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: 1}, txNum: 4}))
+	assert.True(testDataKeyExists(t, s, &dataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-2", blkNum: 1}, txNum: 2}))
+	assert.True(testDataKeyExists(t, s, &dataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-2", blkNum: 1}, txNum: 4}))
+	assert.True(testDataKeyExists(t, s, &dataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: 2}, txNum: 3}))
+
+	// the purge marker is removed once processed
+	val, err := s.(*store).db.Get(encodePurgeMarkerKey(marker))
+	assert.NoError(err)
+	assert.Nil(val)
+
+	// the index entries of the purged writes are removed
+	keyHash := util.ComputeStringHash("key-ns-1-coll-1")
+	assert.False(testPurgeIndexKeyExists(t, s, &dataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: 1}, txNum: 2}, keyHash))
+	assert.False(testPurgeIndexKeyExists(t, s, &dataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: 1}, txNum: 4}, keyHash))
+	assert.True(testPurgeIndexKeyExists(t, s, &dataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: 2}, txNum: 3}, keyHash))
+	assert.True(testPurgeIndexKeyExists(t, s, &dataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-2", blkNum: 1}, txNum: 2},
+		util.ComputeStringHash("key-ns-1-coll-2")))
+
+	retrievedData, err := s.GetPvtDataByBlockNum(1, nil)
+	assert.NoError(err)
+	assert.Len(retrievedData, 2)
+	for _, txPvtData := range retrievedData {
+		assert.Len(txPvtData.WriteSet.NsPvtRwset, 1)
+		assert.Len(txPvtData.WriteSet.NsPvtRwset[0].CollectionPvtRwset, 1)
+		assert.Equal("coll-2", txPvtData.WriteSet.NsPvtRwset[0].CollectionPvtRwset[0].CollectionName)
+	}
+}
+
+func TestStoreBuildsPurgeIndexOfExistingData(t *testing.T) {
+	cs := btltestutil.NewMockCollectionStore()
+	cs.SetBTL("ns-1", "coll-1", 0)
+	btlPolicy := pvtdatapolicy.ConstructBTLPolicy(cs)
+
+	env := NewTestStoreEnv(t, "TestStoreBuildsPurgeIndexOfExistingData", btlPolicy)
+	defer env.Cleanup()
+	assert := assert.New(t)
+	s := env.TestStore
+
+	assert.NoError(s.Prepare(0, nil, nil, nil))
+	assert.NoError(s.Commit())
+	assert.NoError(s.Prepare(1, []*ledger.TxPvtData{produceSamplePvtdata(t, 2, []string{"ns-1:coll-1"})}, nil, nil))
+	assert.NoError(s.Commit())
+
+	// simulate a store committed before the index existed
+	indexedKey := &dataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: 1}, txNum: 2}
+	keyHash := util.ComputeStringHash("key-ns-1-coll-1")
+	db := s.(*store).db
+	assert.NoError(db.Delete(encodePurgeIndexKey(indexedKey, keyHash), true))
+	assert.NoError(db.Delete(purgeIndexBuiltKey, true))
+
+	env.CloseAndReopen()
+	s = env.TestStore
+	assert.True(testPurgeIndexKeyExists(t, s, indexedKey, keyHash))
+
+	purge := &ledger.PvtdataPurge{SeqInBlock: 0, Namespace: "ns-1", Collection: "coll-1", KeyHash: keyHash}
+	assert.NoError(s.Prepare(2, nil, nil, []*ledger.PvtdataPurge{purge}))
+	assert.NoError(s.Commit())
+	testWaitForPurgerRoutineToFinish(s)
+	assert.False(testDataKeyExists(t, s, indexedKey))
+}
+
 func TestStoreState(t *testing.T) {
 	cs := btltestutil.NewMockCollectionStore()
 	cs.SetBTL("ns-1", "coll-1", 0)
@@ -416,14 +551,14 @@ func TestStoreState(t *testing.T) {
 	testData := []*ledger.TxPvtData{
 		produceSamplePvtdata(t, 0, []string{"ns-1:coll-1", "ns-1:coll-2"}),
 	}
-	_, ok := store.Prepare(1, testData, nil).(*ErrIllegalArgs)
+	_, ok := store.Prepare(1, testData, nil, nil).(*ErrIllegalArgs)
 	assert.True(ok)
 
-	assert.Nil(store.Prepare(0, testData, nil))
+	assert.Nil(store.Prepare(0, testData, nil, nil))
 	assert.NoError(store.Commit())
 
-	assert.Nil(store.Prepare(1, testData, nil))
-	_, ok = store.Prepare(2, testData, nil).(*ErrIllegalCall)
+	assert.Nil(store.Prepare(1, testData, nil, nil))
+	_, ok = store.Prepare(2, testData, nil, nil).(*ErrIllegalCall)
 	assert.True(ok)
 }
 
@@ -449,6 +584,36 @@ func TestInitLastCommittedBlock(t *testing.T) {
 	assert.True(ok)
 }
 
+func TestRemove(t *testing.T) {
+	cs := btltestutil.NewMockCollectionStore()
+	cs.SetBTL("ns-1", "coll-1", 0)
+	btlPolicy := pvtdatapolicy.ConstructBTLPolicy(cs)
+
+	env := NewTestStoreEnv(t, "TestRemove", btlPolicy)
+	defer env.Cleanup()
+	assert := assert.New(t)
+	otherStore, err := env.TestStoreProvider.OpenStore("TestRemove-other")
+	assert.NoError(err)
+	otherStore.Init(btlPolicy)
+	testData := []*ledger.TxPvtData{
+		produceSamplePvtdata(t, 0, []string{"ns-1:coll-1"}),
+	}
+	for _, s := range []Store{env.TestStore, otherStore} {
+		assert.NoError(s.Prepare(0, testData, nil, nil))
+		assert.NoError(s.Commit())
+	}
+
+	assert.NoError(env.TestStoreProvider.Remove("TestRemove"))
+	store, err := env.TestStoreProvider.OpenStore("TestRemove")
+	assert.NoError(err)
+	testEmpty(true, assert, store)
+	testEmpty(false, assert, otherStore)
+	data, err := otherStore.GetPvtDataByBlockNum(0, nil)
+	assert.NoError(err)
+	assert.Len(data, 1)
+	assert.True(proto.Equal(testData[0].WriteSet, data[0].WriteSet))
+}
+
 // TODO Add tests for simulating a crash between calls `Prepare` and `Commit`/`Rollback`
 
 func testEmpty(expectedEmpty bool, assert *assert.Assertions, store Store) {
@@ -483,6 +648,12 @@ func testMissingDataKeyExists(t *testing.T, s Store, missingDataKey *missingData
 	return len(val) != 0
 }
 
+func testPurgeIndexKeyExists(t *testing.T, s Store, dataKey *dataKey, keyHash []byte) bool {
+	val, err := s.(*store).db.Get(encodePurgeIndexKey(dataKey, keyHash))
+	assert.NoError(t, err)
+	return val != nil
+}
+
 func testWaitForPurgerRoutineToFinish(s Store) {
 	time.Sleep(1 * time.Second)
 	s.(*store).purgerLock.Lock()
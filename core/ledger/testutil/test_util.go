@@ -74,6 +74,8 @@ func SetupCoreYAMLConfig() {
 // ResetConfigToDefaultValues resets configurations optins back to defaults
 func ResetConfigToDefaultValues() {
 	//reset to defaults
+	viper.Set("ledger.blockchain.compression", "none")
+	viper.Set("ledger.blockchain.enableTimeIndex", false)
 	viper.Set("ledger.state.totalQueryLimit", 10000)
 	viper.Set("ledger.state.couchDBConfig.internalQueryLimit", 1000)
 	viper.Set("ledger.state.stateDatabase", "goleveldb")
@@ -439,6 +439,43 @@ func (couchInstance *CouchInstance) VerifyCouchConfig() (*ConnectionInfo, *DBRet
 	return dbResponse, couchDBReturn, nil
 }
 
+//RetrieveApplicationDBNames returns the names of all the databases in the couch instance
+//except the system databases, whose names start with an underscore
+func (couchInstance *CouchInstance) RetrieveApplicationDBNames() ([]string, error) {
+
+	logger.Debugf("Entering RetrieveApplicationDBNames()")
+	defer logger.Debugf("Exiting RetrieveApplicationDBNames()")
+
+	connectURL, err := url.Parse(couchInstance.conf.URL)
+	if err != nil {
+		logger.Errorf("URL parse error: %s", err)
+		return nil, errors.Wrapf(err, "error parsing couch instance URL: %s", couchInstance.conf.URL)
+	}
+	connectURL.Path = "/_all_dbs"
+
+	//get the number of retries
+	maxRetries := couchInstance.conf.MaxRetries
+
+	resp, _, err := couchInstance.handleRequest(http.MethodGet, connectURL.String(), nil, "", "", maxRetries, true)
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to retrieve the database names")
+	}
+	defer closeResponseBody(resp)
+
+	var dbNames []string
+	if err := json.NewDecoder(resp.Body).Decode(&dbNames); err != nil {
+		return nil, errors.Wrap(err, "error decoding response body")
+	}
+
+	var applicationDBNames []string
+	for _, dbName := range dbNames {
+		if !strings.HasPrefix(dbName, "_") {
+			applicationDBNames = append(applicationDBNames, dbName)
+		}
+	}
+	return applicationDBNames, nil
+}
+
 //DropDatabase provides method to drop an existing database
 func (dbclient *CouchDatabase) DropDatabase() (*DBOperationResponse, error) {
 
@@ -108,6 +108,18 @@ func CreateCouchDatabase(couchInstance *CouchInstance, dbName string) (*CouchDat
 	return &couchDBDatabase, nil
 }
 
+//NewCouchDatabase constructs a CouchDB database object without creating the underlying database
+func NewCouchDatabase(couchInstance *CouchInstance, dbName string) (*CouchDatabase, error) {
+
+	databaseName, err := mapAndValidateDatabaseName(dbName)
+	if err != nil {
+		logger.Errorf("Error validating dbName: %s, error: %s", dbName, err)
+		return nil, err
+	}
+
+	return &CouchDatabase{CouchInstance: couchInstance, DBName: databaseName, IndexWarmCounter: 1}, nil
+}
+
 //CreateSystemDatabasesIfNotExist - creates the system databases if they do not exist
 func CreateSystemDatabasesIfNotExist(couchInstance *CouchInstance) error {
 
@@ -40,6 +40,8 @@ type MockSupport struct {
 	IsJavaErr                        error
 	GetApplicationConfigRv           channelconfig.Application
 	GetApplicationConfigBoolRv       bool
+	IsChannelPausedRv                bool
+	IsChannelPausedErr               error
 }
 
 func (s *MockSupport) Serialize() ([]byte, error) {
@@ -86,6 +88,10 @@ func (s *MockSupport) GetLedgerHeight(channelID string) (uint64, error) {
 	return args.Get(0).(uint64), args.Error(1)
 }
 
+func (s *MockSupport) IsChannelPaused(channelID string) (bool, error) {
+	return s.IsChannelPausedRv, s.IsChannelPausedErr
+}
+
 func (s *MockSupport) IsSysCC(name string) bool {
 	if s.SysCCMap != nil {
 		_, in := s.SysCCMap[name]
@@ -16,6 +16,7 @@ limitations under the License.
 package peer
 
 import (
+	"fmt"
 	"runtime/debug"
 	"time"
 
@@ -23,8 +24,11 @@ import (
 	"justledger/common/deliver"
 	"justledger/common/flogging"
 	"justledger/core/aclmgmt/resources"
+	"justledger/core/common/privdata"
+	"justledger/core/ledger"
 	"justledger/core/ledger/util"
 	"justledger/protos/common"
+	"justledger/protos/ledger/rwset"
 	"justledger/protos/peer"
 	"justledger/protos/utils"
 	"github.com/pkg/errors"
@@ -41,8 +45,18 @@ type PolicyCheckerProvider func(resourceName string) deliver.PolicyCheckerFunc
 
 // server holds the dependencies necessary to create a deliver server
 type server struct {
-	dh                    *deliver.Handler
-	policyCheckerProvider PolicyCheckerProvider
+	dh                      *deliver.Handler
+	policyCheckerProvider   PolicyCheckerProvider
+	collectionAccessChecker collectionAccessChecker
+}
+
+// collectionAccessChecker reports whether the given signed data satisfies
+// the member access policy of the collection of the supplied namespace
+type collectionAccessChecker func(l ledger.PeerLedger, channelID, namespace, collection string, signedData *common.SignedData) (bool, error)
+
+// ledgerProvider is implemented by the chains that expose their peer ledger
+type ledgerProvider interface {
+	Ledger() ledger.PeerLedger
 }
 
 // blockResponseSender structure used to send block responses
@@ -59,7 +73,7 @@ func (brs *blockResponseSender) SendStatusResponse(status common.Status) error {
 }
 
 // SendBlockResponse generates deliver response with block message
-func (brs *blockResponseSender) SendBlockResponse(block *common.Block) error {
+func (brs *blockResponseSender) SendBlockResponse(block *common.Block, channelID string, chain deliver.Chain, envelope *common.Envelope) error {
 	response := &peer.DeliverResponse{
 		Type: &peer.DeliverResponse_Block{Block: block},
 	}
@@ -69,6 +83,10 @@ func (brs *blockResponseSender) SendBlockResponse(block *common.Block) error {
 // filteredBlockResponseSender structure used to send filtered block responses
 type filteredBlockResponseSender struct {
 	peer.Deliver_DeliverFilteredServer
+	// filter is the transaction filter of the deliver request
+	// that is identified by filterEnvelope
+	filter         *transactionFilter
+	filterEnvelope *common.Envelope
 }
 
 func (fbrs *filteredBlockResponseSender) SendStatusResponse(status common.Status) error {
@@ -79,10 +97,23 @@ func (fbrs *filteredBlockResponseSender) SendStatusResponse(status common.Status
 }
 
 // SendBlockResponse generates deliver response with block message
-func (fbrs *filteredBlockResponseSender) SendBlockResponse(block *common.Block) error {
+func (fbrs *filteredBlockResponseSender) SendBlockResponse(block *common.Block, channelID string, chain deliver.Chain, envelope *common.Envelope) error {
+	// the filter is parsed once per deliver request
+	if fbrs.filterEnvelope != envelope {
+		filter, err := newTransactionFilter(envelope)
+		if err != nil {
+			logger.Warningf("Invalid deliver filter: %s", err)
+			if err := fbrs.SendStatusResponse(common.Status_BAD_REQUEST); err != nil {
+				return err
+			}
+			return errors.WithMessage(err, "invalid deliver filter")
+		}
+		fbrs.filter, fbrs.filterEnvelope = filter, envelope
+	}
+
 	// Generates filtered block response
 	b := blockEvent(*block)
-	filteredBlock, err := b.toFilteredBlock()
+	filteredBlock, err := b.toFilteredBlock(fbrs.filter)
 	if err != nil {
 		logger.Warningf("Failed to generate filtered block due to: %s", err)
 		return fbrs.SendStatusResponse(common.Status_BAD_REQUEST)
@@ -93,6 +124,115 @@ func (fbrs *filteredBlockResponseSender) SendBlockResponse(block *common.Block)
 	return fbrs.Send(response)
 }
 
+// blockAndPrivateDataResponseSender structure used to send blocks along with
+// the private data the requesting identity is eligible to receive
+type blockAndPrivateDataResponseSender struct {
+	peer.Deliver_DeliverWithPrivateDataServer
+	collectionAccessChecker collectionAccessChecker
+}
+
+// SendStatusResponse generates status reply proto message
+func (bprs *blockAndPrivateDataResponseSender) SendStatusResponse(status common.Status) error {
+	response := &peer.DeliverResponse{
+		Type: &peer.DeliverResponse_Status{Status: status},
+	}
+	return bprs.Send(response)
+}
+
+// SendBlockResponse generates deliver response with the block and the private
+// data of the collections the requesting identity is a member of
+func (bprs *blockAndPrivateDataResponseSender) SendBlockResponse(block *common.Block, channelID string, chain deliver.Chain, envelope *common.Envelope) error {
+	lp, ok := chain.(ledgerProvider)
+	if !ok {
+		logger.Errorf("The ledger of channel [%s] is not available", channelID)
+		if err := bprs.SendStatusResponse(common.Status_INTERNAL_SERVER_ERROR); err != nil {
+			return err
+		}
+		return errors.Errorf("the ledger of channel [%s] is not available", channelID)
+	}
+	pvtData, err := bprs.getPrivateData(lp.Ledger(), block, channelID, envelope)
+	if err != nil {
+		logger.Errorf("Failed to retrieve the private data of block [%d] of channel [%s]: %s", block.Header.Number, channelID, err)
+		if err := bprs.SendStatusResponse(common.Status_INTERNAL_SERVER_ERROR); err != nil {
+			return err
+		}
+		return errors.WithMessage(err, fmt.Sprintf("failed retrieving the private data of block [%d]", block.Header.Number))
+	}
+	response := &peer.DeliverResponse{
+		Type: &peer.DeliverResponse_BlockAndPrivateData{
+			BlockAndPrivateData: &peer.BlockAndPrivateData{
+				Block:          block,
+				PrivateDataMap: pvtData,
+			},
+		},
+	}
+	return bprs.Send(response)
+}
+
+// getPrivateData retrieves the private data of the given block from the ledger and
+// removes the collections whose member access policy is not satisfied by the creator of the request
+func (bprs *blockAndPrivateDataResponseSender) getPrivateData(l ledger.PeerLedger, block *common.Block, channelID string, envelope *common.Envelope) (map[uint64]*rwset.TxPvtReadWriteSet, error) {
+	blockAndPvtData, err := l.GetPvtDataAndBlockByNum(block.Header.Number, nil)
+	if err != nil {
+		return nil, err
+	}
+	signedData, err := envelope.AsSignedData()
+	if err != nil {
+		return nil, err
+	}
+
+	// the access decision only depends on the namespace and the collection,
+	// so it is computed once per collection within a block
+	type nsColl struct{ namespace, collection string }
+	eligible := make(map[nsColl]bool)
+	pvtData := make(map[uint64]*rwset.TxPvtReadWriteSet)
+	for seqInBlock, txPvtData := range blockAndPvtData.BlockPvtData {
+		if txPvtData == nil || txPvtData.WriteSet == nil {
+			continue
+		}
+		filteredWriteSet := &rwset.TxPvtReadWriteSet{DataModel: txPvtData.WriteSet.DataModel}
+		for _, nsPvtRwset := range txPvtData.WriteSet.NsPvtRwset {
+			filteredNsRwset := &rwset.NsPvtReadWriteSet{Namespace: nsPvtRwset.Namespace}
+			for _, collPvtRwset := range nsPvtRwset.CollectionPvtRwset {
+				key := nsColl{nsPvtRwset.Namespace, collPvtRwset.CollectionName}
+				isEligible, checked := eligible[key]
+				if !checked {
+					isEligible, err = bprs.collectionAccessChecker(l, channelID, nsPvtRwset.Namespace, collPvtRwset.CollectionName, signedData[0])
+					if err != nil {
+						return nil, err
+					}
+					eligible[key] = isEligible
+				}
+				if isEligible {
+					filteredNsRwset.CollectionPvtRwset = append(filteredNsRwset.CollectionPvtRwset, collPvtRwset)
+				}
+			}
+			if len(filteredNsRwset.CollectionPvtRwset) > 0 {
+				filteredWriteSet.NsPvtRwset = append(filteredWriteSet.NsPvtRwset, filteredNsRwset)
+			}
+		}
+		if len(filteredWriteSet.NsPvtRwset) > 0 {
+			pvtData[seqInBlock] = filteredWriteSet
+		}
+	}
+	return pvtData, nil
+}
+
+// checkCollectionAccess evaluates the signed data against the member access policy
+// of the collection as it is currently configured in the ledger
+func checkCollectionAccess(l ledger.PeerLedger, channelID, namespace, collection string, signedData *common.SignedData) (bool, error) {
+	collectionStore := privdata.NewSimpleCollectionStore(&collectionSupport{PeerLedger: l})
+	accessPolicy, err := collectionStore.RetrieveCollectionAccessPolicy(common.CollectionCriteria{
+		Channel:    channelID,
+		Namespace:  namespace,
+		Collection: collection,
+	})
+	if err != nil {
+		return false, errors.WithMessage(err, fmt.Sprintf("failed retrieving the access policy of collection [%s:%s]", namespace, collection))
+	}
+	return accessPolicy.AccessFilter()(*signedData), nil
+}
+
 // transactionActions aliasing for peer.TransactionAction pointers slice
 type transactionActions []*peer.TransactionAction
 
@@ -115,6 +255,23 @@ func (s *server) DeliverFiltered(srv peer.Deliver_DeliverFilteredServer) error {
 	return s.dh.Handle(srv.Context(), deliverServer)
 }
 
+// DeliverWithPrivateData sends a stream of blocks along with the private data
+// the client is eligible to receive after commitment
+func (s *server) DeliverWithPrivateData(srv peer.Deliver_DeliverWithPrivateDataServer) error {
+	logger.Debugf("Starting new DeliverWithPrivateData handler")
+	defer dumpStacktraceOnPanic()
+	// getting policy checker based on resources.Event_BlockAndPrivateData resource name
+	deliverServer := &deliver.Server{
+		Receiver:      srv,
+		PolicyChecker: s.policyCheckerProvider(resources.Event_BlockAndPrivateData),
+		ResponseSender: &blockAndPrivateDataResponseSender{
+			Deliver_DeliverWithPrivateDataServer: srv,
+			collectionAccessChecker:              s.collectionAccessChecker,
+		},
+	}
+	return s.dh.Handle(srv.Context(), deliverServer)
+}
+
 // Deliver sends a stream of blocks to a client after commitment
 func (s *server) Deliver(srv peer.Deliver_DeliverServer) (err error) {
 	logger.Debugf("Starting new Deliver handler")
@@ -141,7 +298,8 @@ func NewDeliverEventsServer(mutualTLS bool, policyCheckerProvider PolicyCheckerP
 	}
 	return &server{
 		dh: deliver.NewHandler(chainManager, timeWindow, mutualTLS),
-		policyCheckerProvider: policyCheckerProvider,
+		policyCheckerProvider:   policyCheckerProvider,
+		collectionAccessChecker: checkCollectionAccess,
 	}
 }
 
@@ -156,7 +314,9 @@ func (s *server) sendProducer(srv peer.Deliver_DeliverFilteredServer) func(msg p
 	}
 }
 
-func (block *blockEvent) toFilteredBlock() (*peer.FilteredBlock, error) {
+// toFilteredBlock converts the block into a filtered block containing the transactions
+// accepted by the filter. The filtered block is returned even if no transaction is accepted
+func (block *blockEvent) toFilteredBlock(filter *transactionFilter) (*peer.FilteredBlock, error) {
 	filteredBlock := &peer.FilteredBlock{
 		Number: block.Header.Number,
 	}
@@ -201,19 +361,26 @@ func (block *blockEvent) toFilteredBlock() (*peer.FilteredBlock, error) {
 			Type:             common.HeaderType(chdr.Type),
 			TxValidationCode: txsFltr.Flag(txIndex),
 		}
+		if !filter.acceptsTransaction(filteredTransaction.Txid, filteredTransaction.TxValidationCode) {
+			continue
+		}
 
+		invokedChaincode := false
 		if filteredTransaction.Type == common.HeaderType_ENDORSER_TRANSACTION {
 			tx, err := utils.GetTransaction(payload.Data)
 			if err != nil {
 				return nil, errors.WithMessage(err, "error unmarshal transaction payload for block event")
 			}
 
-			filteredTransaction.Data, err = transactionActions(tx.Actions).toFilteredActions()
+			filteredTransaction.Data, invokedChaincode, err = transactionActions(tx.Actions).toFilteredActions(filter)
 			if err != nil {
 				logger.Errorf(err.Error())
 				return nil, err
 			}
 		}
+		if !filter.acceptsActions(invokedChaincode, filteredTransaction.GetTransactionActions()) {
+			continue
+		}
 
 		filteredBlock.FilteredTransactions = append(filteredBlock.FilteredTransactions, filteredTransaction)
 	}
@@ -221,12 +388,15 @@ func (block *blockEvent) toFilteredBlock() (*peer.FilteredBlock, error) {
 	return filteredBlock, nil
 }
 
-func (ta transactionActions) toFilteredActions() (*peer.FilteredTransaction_TransactionActions, error) {
+// toFilteredActions returns the chaincode events of the actions accepted by the filter,
+// and whether any of the actions invoked a chaincode accepted by the filter
+func (ta transactionActions) toFilteredActions(filter *transactionFilter) (*peer.FilteredTransaction_TransactionActions, bool, error) {
 	transactionActions := &peer.FilteredTransactionActions{}
+	invokedChaincode := false
 	for _, action := range ta {
 		chaincodeActionPayload, err := utils.GetChaincodeActionPayload(action.Payload)
 		if err != nil {
-			return nil, errors.WithMessage(err, "error unmarshal transaction action payload for block event")
+			return nil, false, errors.WithMessage(err, "error unmarshal transaction action payload for block event")
 		}
 
 		if chaincodeActionPayload.Action == nil {
@@ -235,20 +405,23 @@ func (ta transactionActions) toFilteredActions() (*peer.FilteredTransaction_Tran
 		}
 		propRespPayload, err := utils.GetProposalResponsePayload(chaincodeActionPayload.Action.ProposalResponsePayload)
 		if err != nil {
-			return nil, errors.WithMessage(err, "error unmarshal proposal response payload for block event")
+			return nil, false, errors.WithMessage(err, "error unmarshal proposal response payload for block event")
 		}
 
 		caPayload, err := utils.GetChaincodeAction(propRespPayload.Extension)
 		if err != nil {
-			return nil, errors.WithMessage(err, "error unmarshal chaincode action for block event")
+			return nil, false, errors.WithMessage(err, "error unmarshal chaincode action for block event")
+		}
+		if filter.acceptsChaincode(caPayload.GetChaincodeId().GetName()) {
+			invokedChaincode = true
 		}
 
 		ccEvent, err := utils.GetChaincodeEvents(caPayload.Events)
 		if err != nil {
-			return nil, errors.WithMessage(err, "error unmarshal chaincode event for block event")
+			return nil, false, errors.WithMessage(err, "error unmarshal chaincode event for block event")
 		}
 
-		if ccEvent.GetChaincodeId() != "" {
+		if ccEvent.GetChaincodeId() != "" && filter.acceptsEvent(ccEvent) {
 			filteredAction := &peer.FilteredChaincodeAction{
 				ChaincodeEvent: &peer.ChaincodeEvent{
 					TxId:        ccEvent.TxId,
@@ -261,7 +434,7 @@ func (ta transactionActions) toFilteredActions() (*peer.FilteredTransaction_Tran
 	}
 	return &peer.FilteredTransaction_TransactionActions{
 		TransactionActions: transactionActions,
-	}, nil
+	}, invokedChaincode, nil
 }
 
 func dumpStacktraceOnPanic() {
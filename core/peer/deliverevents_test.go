@@ -17,7 +17,9 @@ import (
 	"justledger/common/ledger/blockledger"
 	"justledger/common/policies"
 	"justledger/common/util"
+	"justledger/core/ledger"
 	"justledger/protos/common"
+	"justledger/protos/ledger/rwset"
 	"justledger/protos/orderer"
 	"justledger/protos/peer"
 	"justledger/protos/utils"
@@ -93,6 +95,27 @@ func (*mockChainSupport) Errored() <-chan struct{} {
 	return make(chan struct{})
 }
 
+// mockLedgerChainSupport extends the mockChainSupport with access to a peer ledger
+type mockLedgerChainSupport struct {
+	*mockChainSupport
+	ledger ledger.PeerLedger
+}
+
+func (m *mockLedgerChainSupport) Ledger() ledger.PeerLedger {
+	return m.ledger
+}
+
+// mockPvtDataLedger mock implementation of the peer ledger
+// serving a fixed block and private data
+type mockPvtDataLedger struct {
+	ledger.PeerLedger
+	blockAndPvtData *ledger.BlockAndPvtData
+}
+
+func (m *mockPvtDataLedger) GetPvtDataAndBlockByNum(blockNum uint64, filter ledger.PvtNsCollFilter) (*ledger.BlockAndPvtData, error) {
+	return m.blockAndPvtData, nil
+}
+
 // mockChainManager mock implementation of the ChainManager interface
 type mockChainManager struct {
 	mock.Mock
@@ -379,6 +402,114 @@ func TestEventsServer_DeliverFiltered(t *testing.T) {
 		})
 	}
 }
+func TestEventsServer_DeliverWithPrivateData(t *testing.T) {
+	viper.Set("peer.authentication.timewindow", "1s")
+	config := testConfig{
+		channelID:  "testChainID",
+		txID:       "testID",
+		Assertions: assert.New(t),
+	}
+	chainManager := createDefaultSupportMamangerMock(config, nil)
+	chain, _ := chainManager.GetChain(config.channelID)
+	block, _ := chain.Reader().Iterator(nil)
+	nextBlock, _ := block.Next()
+
+	pvtWriteSet := func(collections ...string) *rwset.TxPvtReadWriteSet {
+		nsRwset := &rwset.NsPvtReadWriteSet{Namespace: "mycc"}
+		for _, coll := range collections {
+			nsRwset.CollectionPvtRwset = append(nsRwset.CollectionPvtRwset, &rwset.CollectionPvtReadWriteSet{
+				CollectionName: coll,
+				Rwset:          []byte(coll),
+			})
+		}
+		return &rwset.TxPvtReadWriteSet{NsPvtRwset: []*rwset.NsPvtReadWriteSet{nsRwset}}
+	}
+	ledgerChain := &mockLedgerChainSupport{
+		mockChainSupport: chain.(*mockChainSupport),
+		ledger: &mockPvtDataLedger{
+			blockAndPvtData: &ledger.BlockAndPvtData{
+				Block: nextBlock,
+				BlockPvtData: map[uint64]*ledger.TxPvtData{
+					0: {SeqInBlock: 0, WriteSet: pvtWriteSet("coll1", "coll2")},
+					1: {SeqInBlock: 1, WriteSet: pvtWriteSet("coll2")},
+				},
+			},
+		},
+	}
+	chainManager = &mockChainManager{}
+	chainManager.On("GetChain", config.channelID).Return(ledgerChain, true)
+
+	p := &peer2.Peer{}
+	payload := &common.Payload{
+		Header: &common.Header{
+			ChannelHeader: utils.MarshalOrPanic(&common.ChannelHeader{
+				ChannelId: config.channelID,
+				Timestamp: util.CreateUtcTimestamp(),
+			}),
+			SignatureHeader: utils.MarshalOrPanic(&common.SignatureHeader{}),
+		},
+		Data: utils.MarshalOrPanic(&orderer.SeekInfo{
+			Start:    &orderer.SeekPosition{Type: &orderer.SeekPosition_Specified{Specified: &orderer.SeekSpecified{Number: 0}}},
+			Stop:     &orderer.SeekPosition{Type: &orderer.SeekPosition_Newest{Newest: &orderer.SeekNewest{}}},
+			Behavior: orderer.SeekInfo_BLOCK_UNTIL_READY,
+		}),
+	}
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	deliverServer := &mockDeliverServer{}
+	deliverServer.On("Context").Return(peer2.NewContext(context.TODO(), p))
+	deliverServer.On("Recv").Return(&common.Envelope{
+		Payload: utils.MarshalOrPanic(payload),
+	}, nil).Run(func(_ mock.Arguments) {
+		deliverServer.Mock = mock.Mock{}
+		deliverServer.On("Context").Return(peer2.NewContext(context.TODO(), p))
+		deliverServer.On("Recv").Return(&common.Envelope{}, io.EOF)
+		deliverServer.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+			defer wg.Done()
+			response := args.Get(0).(*peer.DeliverResponse)
+			switch response.Type.(type) {
+			case *peer.DeliverResponse_Status:
+				config.Equal(common.Status_SUCCESS, response.GetStatus())
+			case *peer.DeliverResponse_BlockAndPrivateData:
+				blockAndPvtData := response.GetBlockAndPrivateData()
+				config.Equal(nextBlock, blockAndPvtData.Block)
+				// only the private data of the collection the client is a member of is delivered
+				config.Equal(map[uint64]*rwset.TxPvtReadWriteSet{0: pvtWriteSet("coll1")}, blockAndPvtData.PrivateDataMap)
+			default:
+				config.FailNow("Unexpected response type")
+			}
+		}).Return(nil)
+	})
+
+	var checkedCollections []string
+	deliverEventsServer := NewDeliverEventsServer(false, defaultPolicyCheckerProvider, chainManager)
+	deliverEventsServer.(*server).collectionAccessChecker = func(_ ledger.PeerLedger, channelID, namespace, collection string, _ *common.SignedData) (bool, error) {
+		config.Equal("testChainID", channelID)
+		config.Equal("mycc", namespace)
+		checkedCollections = append(checkedCollections, collection)
+		return collection == "coll1", nil
+	}
+	err := deliverEventsServer.DeliverWithPrivateData(deliverServer)
+	wg.Wait()
+	assert.NoError(t, err)
+	// the access policy of a collection is evaluated once per block
+	assert.Len(t, checkedCollections, 2)
+	assert.ElementsMatch(t, []string{"coll1", "coll2"}, checkedCollections)
+}
+
+func TestBlockAndPrivateDataResponseSenderWithoutLedger(t *testing.T) {
+	deliverServer := &mockDeliverServer{}
+	deliverServer.On("Send", &peer.DeliverResponse{
+		Type: &peer.DeliverResponse_Status{Status: common.Status_INTERNAL_SERVER_ERROR},
+	}).Return(nil)
+	sender := &blockAndPrivateDataResponseSender{Deliver_DeliverWithPrivateDataServer: deliverServer}
+	block := &common.Block{Header: &common.BlockHeader{Number: 1}}
+	err := sender.SendBlockResponse(block, "testChainID", &mockChainSupport{}, &common.Envelope{})
+	// the error stops the deliver loop once the status is sent
+	assert.EqualError(t, err, "the ledger of channel [testChainID] is not available")
+	deliverServer.AssertExpectations(t)
+}
+
 func createDefaultSupportMamangerMock(config testConfig, chaincodeActionPayload *peer.ChaincodeActionPayload) *mockChainManager {
 	chainManager := &mockChainManager{}
 	iter := &mockIterator{}
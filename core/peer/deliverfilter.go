@@ -0,0 +1,128 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"regexp"
+
+	"github.com/golang/protobuf/proto"
+	"justledger/protos/common"
+	"justledger/protos/peer"
+	"justledger/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// transactionFilter selects the transactions and the chaincode events sent
+// by the DeliverFiltered service. A nil transactionFilter accepts everything
+type transactionFilter struct {
+	chaincodeNames  map[string]struct{}
+	eventNameRegex  *regexp.Regexp
+	validationCodes map[peer.TxValidationCode]struct{}
+	txIDs           map[string]struct{}
+}
+
+// newTransactionFilter builds the transaction filter carried by the extension of the
+// channel header of the given deliver request. It returns nil if the request has no filter
+func newTransactionFilter(envelope *common.Envelope) (*transactionFilter, error) {
+	payload, err := utils.UnmarshalPayload(envelope.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, errors.New("missing payload header")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	if len(chdr.Extension) == 0 {
+		return nil, nil
+	}
+
+	deliverFilter := &peer.DeliverFilter{}
+	if err := proto.Unmarshal(chdr.Extension, deliverFilter); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling deliver filter")
+	}
+	filter := &transactionFilter{}
+	if len(deliverFilter.ChaincodeNames) > 0 {
+		filter.chaincodeNames = make(map[string]struct{})
+		for _, name := range deliverFilter.ChaincodeNames {
+			filter.chaincodeNames[name] = struct{}{}
+		}
+	}
+	if deliverFilter.EventNameRegex != "" {
+		filter.eventNameRegex, err = regexp.Compile(deliverFilter.EventNameRegex)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid event name regex [%s]", deliverFilter.EventNameRegex)
+		}
+	}
+	if len(deliverFilter.ValidationCodes) > 0 {
+		filter.validationCodes = make(map[peer.TxValidationCode]struct{})
+		for _, code := range deliverFilter.ValidationCodes {
+			filter.validationCodes[code] = struct{}{}
+		}
+	}
+	if len(deliverFilter.TxIds) > 0 {
+		filter.txIDs = make(map[string]struct{})
+		for _, txID := range deliverFilter.TxIds {
+			filter.txIDs[txID] = struct{}{}
+		}
+	}
+	return filter, nil
+}
+
+// acceptsTransaction reports whether the ID and the validation code of a
+// transaction satisfy the filter
+func (f *transactionFilter) acceptsTransaction(txID string, code peer.TxValidationCode) bool {
+	if f == nil {
+		return true
+	}
+	if f.txIDs != nil {
+		if _, ok := f.txIDs[txID]; !ok {
+			return false
+		}
+	}
+	if f.validationCodes != nil {
+		if _, ok := f.validationCodes[code]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// acceptsChaincode reports whether the chaincode satisfies the chaincode names of the filter
+func (f *transactionFilter) acceptsChaincode(name string) bool {
+	if f == nil || f.chaincodeNames == nil {
+		return true
+	}
+	_, ok := f.chaincodeNames[name]
+	return ok
+}
+
+// acceptsEvent reports whether the chaincode event is emitted by one of the chaincodes
+// of the filter and its name matches the event name regex of the filter
+func (f *transactionFilter) acceptsEvent(event *peer.ChaincodeEvent) bool {
+	if f == nil {
+		return true
+	}
+	if !f.acceptsChaincode(event.ChaincodeId) {
+		return false
+	}
+	return f.eventNameRegex == nil || f.eventNameRegex.MatchString(event.EventName)
+}
+
+// acceptsActions reports whether a transaction satisfies the chaincode criteria of the filter,
+// given whether it invoked an accepted chaincode and the chaincode actions retained for it
+func (f *transactionFilter) acceptsActions(invokedChaincode bool, actions *peer.FilteredTransactionActions) bool {
+	if f == nil {
+		return true
+	}
+	if f.chaincodeNames != nil && !invokedChaincode {
+		return false
+	}
+	return f.eventNameRegex == nil || len(actions.GetChaincodeActions()) > 0
+}
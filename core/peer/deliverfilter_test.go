@@ -0,0 +1,172 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"testing"
+
+	"justledger/protos/common"
+	"justledger/protos/peer"
+	"justledger/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createFilterEnvelope(t *testing.T, filter *peer.DeliverFilter) *common.Envelope {
+	var extension []byte
+	if filter != nil {
+		extension = utils.MarshalOrPanic(filter)
+	}
+	env, err := utils.CreateSignedEnvelopeWithTLSBindingAndExtension(common.HeaderType_DELIVER_SEEK_INFO, "testChainID", nil, &common.Block{}, 0, 0, nil, extension)
+	require.NoError(t, err)
+	return env
+}
+
+func createFilterTestBlock(t *testing.T) *common.Block {
+	txs := []struct {
+		txID, chaincodeName, eventName string
+		code                           peer.TxValidationCode
+	}{
+		{"tx1", "mycc", "transfer", peer.TxValidationCode_VALID},
+		{"tx2", "othercc", "mint", peer.TxValidationCode_VALID},
+		{"tx3", "mycc", "approve", peer.TxValidationCode_MVCC_READ_CONFLICT},
+	}
+	var envs []*common.Envelope
+	for _, tx := range txs {
+		ccActionPayload, err := createChaincodeAction(tx.chaincodeName, tx.eventName, tx.txID)
+		require.NoError(t, err)
+		payload, err := createEndorsement("testChainID", tx.txID, ccActionPayload)
+		require.NoError(t, err)
+		envs = append(envs, &common.Envelope{Payload: utils.MarshalOrPanic(payload)})
+	}
+	block, err := createTestBlock(envs)
+	require.NoError(t, err)
+	for i, tx := range txs {
+		block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER][i] = uint8(tx.code)
+	}
+	return block
+}
+
+func TestNewTransactionFilter(t *testing.T) {
+	filter, err := newTransactionFilter(createFilterEnvelope(t, nil))
+	assert.NoError(t, err)
+	assert.Nil(t, filter)
+
+	filter, err = newTransactionFilter(createFilterEnvelope(t, &peer.DeliverFilter{
+		ChaincodeNames:  []string{"mycc"},
+		EventNameRegex:  "^tr",
+		ValidationCodes: []peer.TxValidationCode{peer.TxValidationCode_VALID},
+		TxIds:           []string{"tx1"},
+	}))
+	assert.NoError(t, err)
+	assert.True(t, filter.acceptsTransaction("tx1", peer.TxValidationCode_VALID))
+	assert.False(t, filter.acceptsTransaction("tx2", peer.TxValidationCode_VALID))
+	assert.False(t, filter.acceptsTransaction("tx1", peer.TxValidationCode_MVCC_READ_CONFLICT))
+	assert.True(t, filter.acceptsEvent(&peer.ChaincodeEvent{ChaincodeId: "mycc", EventName: "transfer"}))
+	assert.False(t, filter.acceptsEvent(&peer.ChaincodeEvent{ChaincodeId: "mycc", EventName: "approve"}))
+	assert.False(t, filter.acceptsEvent(&peer.ChaincodeEvent{ChaincodeId: "othercc", EventName: "transfer"}))
+
+	_, err = newTransactionFilter(createFilterEnvelope(t, &peer.DeliverFilter{EventNameRegex: "["}))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid event name regex [[]")
+
+	env, err := utils.CreateSignedEnvelopeWithTLSBindingAndExtension(common.HeaderType_DELIVER_SEEK_INFO, "testChainID", nil, &common.Block{}, 0, 0, nil, []byte("garbage"))
+	require.NoError(t, err)
+	_, err = newTransactionFilter(env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error unmarshaling deliver filter")
+}
+
+func TestToFilteredBlockWithFilter(t *testing.T) {
+	block := createFilterTestBlock(t)
+
+	tests := []struct {
+		name           string
+		filter         *peer.DeliverFilter
+		expectedTxIDs  []string
+		expectedEvents []string
+	}{
+		{
+			name:           "no filter",
+			expectedTxIDs:  []string{"tx1", "tx2", "tx3"},
+			expectedEvents: []string{"transfer", "mint", "approve"},
+		},
+		{
+			name:           "chaincode names",
+			filter:         &peer.DeliverFilter{ChaincodeNames: []string{"mycc"}},
+			expectedTxIDs:  []string{"tx1", "tx3"},
+			expectedEvents: []string{"transfer", "approve"},
+		},
+		{
+			name:           "event name regex",
+			filter:         &peer.DeliverFilter{EventNameRegex: "^(mint|approve)$"},
+			expectedTxIDs:  []string{"tx2", "tx3"},
+			expectedEvents: []string{"mint", "approve"},
+		},
+		{
+			name:           "validation codes",
+			filter:         &peer.DeliverFilter{ValidationCodes: []peer.TxValidationCode{peer.TxValidationCode_VALID}},
+			expectedTxIDs:  []string{"tx1", "tx2"},
+			expectedEvents: []string{"transfer", "mint"},
+		},
+		{
+			name:           "tx IDs",
+			filter:         &peer.DeliverFilter{TxIds: []string{"tx2", "tx4"}},
+			expectedTxIDs:  []string{"tx2"},
+			expectedEvents: []string{"mint"},
+		},
+		{
+			name: "all criteria",
+			filter: &peer.DeliverFilter{
+				ChaincodeNames:  []string{"mycc"},
+				EventNameRegex:  "a",
+				ValidationCodes: []peer.TxValidationCode{peer.TxValidationCode_VALID},
+			},
+			expectedTxIDs:  []string{"tx1"},
+			expectedEvents: []string{"transfer"},
+		},
+		{
+			name:   "nothing matches",
+			filter: &peer.DeliverFilter{ChaincodeNames: []string{"unknowncc"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filter, err := newTransactionFilter(createFilterEnvelope(t, test.filter))
+			require.NoError(t, err)
+			b := blockEvent(*block)
+			filteredBlock, err := b.toFilteredBlock(filter)
+			require.NoError(t, err)
+			// the block boundary is always delivered
+			assert.Equal(t, uint64(0), filteredBlock.Number)
+			assert.Equal(t, "testChainID", filteredBlock.ChannelId)
+
+			var txIDs, events []string
+			for _, tx := range filteredBlock.FilteredTransactions {
+				txIDs = append(txIDs, tx.Txid)
+				for _, action := range tx.GetTransactionActions().ChaincodeActions {
+					events = append(events, action.ChaincodeEvent.EventName)
+				}
+			}
+			assert.Equal(t, test.expectedTxIDs, txIDs)
+			assert.Equal(t, test.expectedEvents, events)
+		})
+	}
+}
+
+func TestFilteredBlockResponseSenderWithInvalidFilter(t *testing.T) {
+	deliverServer := &mockDeliverServer{}
+	deliverServer.On("Send", &peer.DeliverResponse{
+		Type: &peer.DeliverResponse_Status{Status: common.Status_BAD_REQUEST},
+	}).Return(nil)
+	sender := &filteredBlockResponseSender{Deliver_DeliverFilteredServer: deliverServer}
+	env := createFilterEnvelope(t, &peer.DeliverFilter{EventNameRegex: "("})
+	err := sender.SendBlockResponse(createFilterTestBlock(t), "testChainID", nil, env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid deliver filter")
+	deliverServer.AssertExpectations(t)
+}
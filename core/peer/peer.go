@@ -96,6 +96,17 @@ func (sp *storeProvider) OpenStore(ledgerID string) (transientstore.Store, error
 	return store, err
 }
 
+// RemoveStore deletes the transient store of the given ledger
+func (sp *storeProvider) RemoveStore(ledgerID string) error {
+	sp.Lock()
+	defer sp.Unlock()
+	if sp.StoreProvider == nil {
+		sp.StoreProvider = transientstore.NewStoreProvider()
+	}
+	delete(sp.stores, ledgerID)
+	return sp.StoreProvider.RemoveStore(ledgerID)
+}
+
 func (cs *chainSupport) Apply(configtx *common.ConfigEnvelope) error {
 	err := cs.ConfigtxValidator().Validate(configtx)
 	if err != nil {
@@ -176,7 +187,9 @@ type chain struct {
 var chains = struct {
 	sync.RWMutex
 	list map[string]*chain
-}{list: make(map[string]*chain)}
+	// leaving holds the channels that are being left
+	leaving map[string]struct{}
+}{list: make(map[string]*chain), leaving: make(map[string]struct{})}
 
 var chainInitializer func(string)
 
@@ -429,6 +442,47 @@ func CreateChainFromBlock(cb *common.Block, ccp ccprovider.ChaincodeProvider, sc
 	return createChain(cid, l, cb, ccp, sccp, pluginMapper)
 }
 
+// LeaveChain makes the peer leave the chain with the given chain ID. The chain is unregistered first so that
+// no new proposals are accepted for it, then the gossip services of the chain, including the deliver service
+// and the committer, are stopped and finally the ledger and the transient store of the chain are removed.
+// The removal of the ledger waits for the in-flight transaction simulations on the chain to be done
+func LeaveChain(cid string) error {
+	chains.Lock()
+	if _, ok := chains.list[cid]; !ok {
+		chains.Unlock()
+		return errors.Errorf("peer is not joined to channel [%s]", cid)
+	}
+	if _, ok := chains.leaving[cid]; ok {
+		chains.Unlock()
+		return errors.Errorf("peer is already leaving channel [%s]", cid)
+	}
+	chains.leaving[cid] = struct{}{}
+	chains.Unlock()
+	defer func() {
+		chains.Lock()
+		delete(chains.leaving, cid)
+		chains.Unlock()
+	}()
+
+	peerLogger.Infof("Leaving channel [%s]", cid)
+	service.GetGossipService().StopChannel(cid)
+	if err := ledgermgmt.RemoveLedger(cid); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed removing the ledger of channel [%s]", cid))
+	}
+
+	// the channel is only forgotten once its services are stopped and its ledger is removed,
+	// so that leaving it can be retried if either fails
+	chains.Lock()
+	delete(chains.list, cid)
+	chains.Unlock()
+
+	if err := TransientStoreFactory.RemoveStore(cid); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed removing the transient store of channel [%s]", cid))
+	}
+	peerLogger.Infof("Left channel [%s]", cid)
+	return nil
+}
+
 // GetLedger returns the ledger of the chain with chain ID. Note that this
 // call returns nil if chain cid has not been created.
 func GetLedger(cid string) ledger.PeerLedger {
@@ -713,6 +767,8 @@ type DeliverChainManager struct {
 }
 
 func (DeliverChainManager) GetChain(chainID string) (deliver.Chain, bool) {
+	chains.RLock()
+	defer chains.RUnlock()
 	channel, ok := chains.list[chainID]
 	if !ok {
 		return nil, ok
@@ -30,6 +30,7 @@ type Operations interface {
 	GetMSPIDs(cid string) []string
 	GetPolicyManager(cid string) policies.Manager
 	InitChain(cid string)
+	LeaveChain(cid string) error
 	Initialize(init func(string), ccp ccprovider.ChaincodeProvider, sccp sysccprovider.SystemChaincodeProvider, pm txvalidator.PluginMapper, pr *platforms.Registry, deployedCCInfoProvider ledger.DeployedChaincodeInfoProvider)
 }
 
@@ -42,6 +43,7 @@ type peerImpl struct {
 	getMSPIDs            func(cid string) []string
 	getPolicyManager     func(cid string) policies.Manager
 	initChain            func(cid string)
+	leaveChain           func(cid string) error
 	initialize           func(init func(string), ccp ccprovider.ChaincodeProvider, sccp sysccprovider.SystemChaincodeProvider, mapper txvalidator.PluginMapper, pr *platforms.Registry, deployedCCInfoProvider ledger.DeployedChaincodeInfoProvider)
 }
 
@@ -56,6 +58,7 @@ var Default Operations = &peerImpl{
 	getMSPIDs:            GetMSPIDs,
 	getPolicyManager:     GetPolicyManager,
 	initChain:            InitChain,
+	leaveChain:           LeaveChain,
 	initialize:           Initialize,
 }
 
@@ -73,6 +76,7 @@ func (p *peerImpl) GetLedger(cid string) ledger.PeerLedger       { return p.getL
 func (p *peerImpl) GetMSPIDs(cid string) []string                { return p.getMSPIDs(cid) }
 func (p *peerImpl) GetPolicyManager(cid string) policies.Manager { return p.getPolicyManager(cid) }
 func (p *peerImpl) InitChain(cid string)                         { p.initChain(cid) }
+func (p *peerImpl) LeaveChain(cid string) error                  { return p.leaveChain(cid) }
 func (p *peerImpl) Initialize(init func(string), ccp ccprovider.ChaincodeProvider, sccp sysccprovider.SystemChaincodeProvider, mapper txvalidator.PluginMapper, pr *platforms.Registry, deployedCCInfoProvider ledger.DeployedChaincodeInfoProvider) {
 	p.initialize(init, ccp, sccp, mapper, pr, deployedCCInfoProvider)
 }
@@ -21,6 +21,7 @@ import (
 	"justledger/core/deliverservice"
 	"justledger/core/deliverservice/blocksprovider"
 	"justledger/core/handlers/validation/api"
+	"justledger/core/ledger/ledgermgmt"
 	ledgermocks "justledger/core/ledger/mock"
 	"justledger/core/mocks/ccprovider"
 	"justledger/gossip/api"
@@ -192,6 +193,34 @@ func TestCreateChainFromBlock(t *testing.T) {
 		t.Fatalf("incorrect number of channels")
 	}
 
+	// Leave the chain
+	err = LeaveChain(testChainID)
+	assert.NoError(t, err)
+	assert.Nil(t, GetLedger(testChainID))
+	assert.Nil(t, TransientStoreFactory.StoreForChannel(testChainID))
+	assert.Empty(t, GetChannelsInfo())
+	ledgerIDs, err := ledgermgmt.GetLedgerIDs()
+	assert.NoError(t, err)
+	assert.NotContains(t, ledgerIDs, testChainID)
+	err = LeaveChain(testChainID)
+	assert.EqualError(t, err, fmt.Sprintf("peer is not joined to channel [%s]", testChainID))
+
+	// The chain can be joined again after leaving it
+	block, err = configtxtest.MakeGenesisBlock(testChainID)
+	require.NoError(t, err)
+	err = CreateChainFromBlock(block, nil, nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, GetLedger(testChainID))
+
+	// The channel stays joined if its ledger cannot be removed, so that leaving it can be retried
+	ledgermgmt.Close()
+	err = LeaveChain(testChainID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("failed removing the ledger of channel [%s]", testChainID))
+	assert.Len(t, GetChannelsInfo(), 1)
+	_, ok = (&DeliverChainManager{}).GetChain(testChainID)
+	assert.True(t, ok)
+
 	// cleanup the chain referenes to enable execution with -count n
 	chains.Lock()
 	chains.list = map[string]*chain{}
@@ -15,6 +15,7 @@ import (
 	"fmt"
 
 	"github.com/golang/protobuf/proto"
+	"justledger/common/audit"
 	"justledger/common/channelconfig"
 	"justledger/common/config"
 	"justledger/common/flogging"
@@ -35,7 +36,12 @@ import (
 
 // New creates a new instance of the CSCC.
 // Typically, only one will be created per peer instance.
-func New(ccp ccprovider.ChaincodeProvider, sccp sysccprovider.SystemChaincodeProvider, aclProvider aclmgmt.ACLProvider) *PeerConfiger {
+// Channel joins and leaves are recorded to the given audit recorder,
+// which may be nil if no audit log is configured.
+func New(ccp ccprovider.ChaincodeProvider, sccp sysccprovider.SystemChaincodeProvider, aclProvider aclmgmt.ACLProvider, recorder audit.Recorder) *PeerConfiger {
+	if recorder == nil {
+		recorder = audit.NoopRecorder{}
+	}
 	return &PeerConfiger{
 		policyChecker: policy.NewPolicyChecker(
 			peer.NewChannelPolicyManagerGetter(),
@@ -46,6 +52,7 @@ func New(ccp ccprovider.ChaincodeProvider, sccp sysccprovider.SystemChaincodePro
 		ccp:         ccp,
 		sccp:        sccp,
 		aclProvider: aclProvider,
+		recorder:    recorder,
 	}
 }
 
@@ -66,6 +73,7 @@ type PeerConfiger struct {
 	ccp           ccprovider.ChaincodeProvider
 	sccp          sysccprovider.SystemChaincodeProvider
 	aclProvider   aclmgmt.ACLProvider
+	recorder      audit.Recorder
 }
 
 var cnflogger = flogging.MustGetLogger("cscc")
@@ -73,6 +81,7 @@ var cnflogger = flogging.MustGetLogger("cscc")
 // These are function names from Invoke first parameter
 const (
 	JoinChain                string = "JoinChain"
+	LeaveChain               string = "LeaveChain"
 	GetConfigBlock           string = "GetConfigBlock"
 	GetChannels              string = "GetChannels"
 	GetConfigTree            string = "GetConfigTree"
@@ -87,10 +96,11 @@ func (e *PeerConfiger) Init(stub shim.ChaincodeStubInterface) pb.Response {
 
 // Invoke is called for the following:
 // # to process joining a chain (called by app as a transaction proposal)
+// # to process leaving a chain (called by app as a transaction proposal)
 // # to get the current configuration block (called by app)
 // # to update the configuration block (called by committer)
 // Peer calls this function with 2 arguments:
-// # args[0] is the function name, which must be JoinChain, GetConfigBlock or
+// # args[0] is the function name, which must be JoinChain, LeaveChain, GetConfigBlock or
 // UpdateConfigBlock
 // # args[1] is a configuration Block if args[0] is JoinChain or
 // UpdateConfigBlock; otherwise it is the chain id
@@ -142,14 +152,14 @@ func (e *PeerConfiger) InvokeNoShim(args [][]byte, sp *pb.SignedProposal) pb.Res
 		}
 
 		if err := validateConfigBlock(block); err != nil {
-			return shim.Error(fmt.Sprintf("\"JoinChain\" for chainID = %s failed because of validation "+
-				"of configuration block, because of %s", cid, err))
+			return e.audit(sp, fname, cid, shim.Error(fmt.Sprintf("\"JoinChain\" for chainID = %s failed because of validation "+
+				"of configuration block, because of %s", cid, err)))
 		}
 
 		// 2. check local MSP Admins policy
 		// TODO: move to ACLProvider once it will support chainless ACLs
 		if err = e.policyChecker.CheckPolicyNoChannel(mgmt.Admins, sp); err != nil {
-			return shim.Error(fmt.Sprintf("access denied for [%s][%s]: [%s]", fname, cid, err))
+			return e.audit(sp, fname, cid, shim.Error(fmt.Sprintf("access denied for [%s][%s]: [%s]", fname, cid, err)))
 		}
 
 		// Initialize txsFilter if it does not yet exist. We can do this safely since
@@ -161,7 +171,20 @@ func (e *PeerConfiger) InvokeNoShim(args [][]byte, sp *pb.SignedProposal) pb.Res
 			block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsFilter
 		}
 
-		return joinChain(cid, block, e.ccp, e.sccp)
+		return e.audit(sp, fname, cid, joinChain(cid, block, e.ccp, e.sccp))
+	case LeaveChain:
+		cid := string(args[1])
+		if cid == "" {
+			return shim.Error("Cannot leave the channel, no channel ID provided")
+		}
+
+		// 2. check local MSP Admins policy
+		// TODO: move to ACLProvider once it will support chainless ACLs
+		if err = e.policyChecker.CheckPolicyNoChannel(mgmt.Admins, sp); err != nil {
+			return e.audit(sp, fname, cid, shim.Error(fmt.Sprintf("access denied for [%s][%s]: [%s]", fname, cid, err)))
+		}
+
+		return e.audit(sp, fname, cid, leaveChain(cid))
 	case GetConfigBlock:
 		// 2. check policy
 		if err = e.aclProvider.CheckACL(resources.Cscc_GetConfigBlock, string(args[1]), sp); err != nil {
@@ -195,6 +218,38 @@ func (e *PeerConfiger) InvokeNoShim(args [][]byte, sp *pb.SignedProposal) pb.Res
 	return shim.Error(fmt.Sprintf("Requested function %s not found.", fname))
 }
 
+// audit records the outcome of an administrative operation on a channel
+// to the audit log and returns its response
+func (e *PeerConfiger) audit(sp *pb.SignedProposal, fname, cid string, res pb.Response) pb.Response {
+	var err error
+	if res.Status != shim.OK {
+		err = errors.New(res.Message)
+	}
+	e.recorder.Record(creatorOf(sp), fname, cid, err)
+	return res
+}
+
+// creatorOf returns the serialized identity that created the proposal,
+// or nil if it cannot be extracted
+func creatorOf(sp *pb.SignedProposal) []byte {
+	if sp == nil {
+		return nil
+	}
+	prop, err := utils.GetProposal(sp.ProposalBytes)
+	if err != nil {
+		return nil
+	}
+	hdr, err := utils.GetHeader(prop.Header)
+	if err != nil {
+		return nil
+	}
+	shdr, err := utils.GetSignatureHeader(hdr.SignatureHeader)
+	if err != nil {
+		return nil
+	}
+	return shdr.Creator
+}
+
 // validateConfigBlock validate configuration block to see whenever it's contains valid config transaction
 func validateConfigBlock(block *common.Block) error {
 	envelopeConfig, err := utils.ExtractEnvelope(block, 0)
@@ -242,6 +297,15 @@ func joinChain(chainID string, block *common.Block, ccp ccprovider.ChaincodeProv
 	return shim.Success(nil)
 }
 
+// leaveChain will make the peer leave the specified chain and remove all of its data
+func leaveChain(chainID string) pb.Response {
+	if err := peer.LeaveChain(chainID); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(nil)
+}
+
 // Return the current configuration block for the specified chainID. If the
 // peer doesn't belong to the chain, return error
 func getConfigBlock(chainID []byte) pb.Response {
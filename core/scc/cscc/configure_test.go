@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"justledger/common/audit"
 	"justledger/common/config"
 	"justledger/common/configtx"
 	configtxtest "justledger/common/configtx/test"
@@ -67,6 +68,11 @@ type aclProvider interface {
 	aclmgmt.ACLProvider
 }
 
+//go:generate counterfeiter -o mock/recorder.go --fake-name Recorder . recorder
+type recorder interface {
+	audit.Recorder
+}
+
 //go:generate counterfeiter -o mock/configtx_validator.go --fake-name ConfigtxValidator . configtxValidator
 type configtxValidator interface {
 	configtx.Validator
@@ -115,7 +121,7 @@ func TestMain(m *testing.M) {
 }
 
 func TestConfigerInit(t *testing.T) {
-	e := New(nil, nil, mockAclProvider)
+	e := New(nil, nil, mockAclProvider, nil)
 	stub := shim.NewMockStub("PeerConfiger", e)
 
 	if res := stub.MockInit("1", nil); res.Status != shim.OK {
@@ -125,7 +131,7 @@ func TestConfigerInit(t *testing.T) {
 }
 
 func TestConfigerInvokeInvalidParameters(t *testing.T) {
-	e := New(nil, nil, mockAclProvider)
+	e := New(nil, nil, mockAclProvider, nil)
 	stub := shim.NewMockStub("PeerConfiger", e)
 
 	res := stub.MockInit("1", nil)
@@ -159,7 +165,7 @@ func TestConfigerInvokeJoinChainMissingParams(t *testing.T) {
 	os.Mkdir("/tmp/hyperledgertest", 0755)
 	defer os.RemoveAll("/tmp/hyperledgertest/")
 
-	e := New(nil, nil, mockAclProvider)
+	e := New(nil, nil, mockAclProvider, nil)
 	stub := shim.NewMockStub("PeerConfiger", e)
 
 	if res := stub.MockInit("1", nil); res.Status != shim.OK {
@@ -180,7 +186,7 @@ func TestConfigerInvokeJoinChainWrongParams(t *testing.T) {
 	os.Mkdir("/tmp/hyperledgertest", 0755)
 	defer os.RemoveAll("/tmp/hyperledgertest/")
 
-	e := New(nil, nil, mockAclProvider)
+	e := New(nil, nil, mockAclProvider, nil)
 	stub := shim.NewMockStub("PeerConfiger", e)
 
 	if res := stub.MockInit("1", nil); res.Status != shim.OK {
@@ -208,7 +214,8 @@ func TestConfigerInvokeJoinChainCorrectParams(t *testing.T) {
 	defer ledgermgmt.CleanupTestEnv()
 	defer os.RemoveAll("/tmp/hyperledgertest/")
 
-	e := New(ccp, mp, mockAclProvider)
+	recorder := &mock.Recorder{}
+	e := New(ccp, mp, mockAclProvider, recorder)
 	stub := shim.NewMockStub("PeerConfiger", e)
 
 	peerEndpoint := "localhost:13611"
@@ -309,6 +316,18 @@ func TestConfigerInvokeJoinChainCorrectParams(t *testing.T) {
 	assert.Contains(t, res.Message, "access denied for [JoinChain][mytestchainid]")
 	sProp.Signature = sProp.ProposalBytes
 
+	// Both the successful and the denied join must have been audited
+	assert.Equal(t, 2, recorder.RecordCallCount())
+	creator, action, target, err := recorder.RecordArgsForCall(0)
+	assert.Equal(t, []byte("Alice"), creator)
+	assert.Equal(t, JoinChain, action)
+	assert.Equal(t, "mytestchainid", target)
+	assert.NoError(t, err)
+	_, action, target, err = recorder.RecordArgsForCall(1)
+	assert.Equal(t, JoinChain, action)
+	assert.Equal(t, "mytestchainid", target)
+	assert.Contains(t, err.Error(), "access denied for [JoinChain][mytestchainid]")
+
 	// Query the configuration block
 	//chainID := []byte{143, 222, 22, 192, 73, 145, 76, 110, 167, 154, 118, 66, 132, 204, 113, 168}
 	chainID, err := utils.GetChainIDFromBlockBytes(blockBytes)
@@ -351,6 +370,33 @@ func TestConfigerInvokeJoinChainCorrectParams(t *testing.T) {
 	if len(cqr.GetChannels()) != 1 {
 		t.FailNow()
 	}
+
+	// LeaveChain without a channel ID must fail
+	res = stub.MockInvokeWithSignedProposal("2", [][]byte{[]byte(LeaveChain), nil}, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Equal(t, "Cannot leave the channel, no channel ID provided", res.Message)
+
+	// LeaveChain must fail for a non admin
+	args = [][]byte{[]byte(LeaveChain), []byte(chainID)}
+	sProp.Signature = nil
+	res = stub.MockInvokeWithSignedProposal("3", args, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "access denied for [LeaveChain][mytestchainid]")
+	sProp.Signature = sProp.ProposalBytes
+
+	// LeaveChain for a channel the peer has not joined must fail
+	res = stub.MockInvokeWithSignedProposal("2", [][]byte{[]byte(LeaveChain), []byte("unknownchain")}, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Equal(t, "peer is not joined to channel [unknownchain]", res.Message)
+
+	// Successful path for LeaveChain
+	res = stub.MockInvokeWithSignedProposal("2", args, sProp)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	res = stub.MockInvokeWithSignedProposal("2", [][]byte{[]byte(GetChannels)}, sProp)
+	assert.Equal(t, int32(shim.OK), res.Status)
+	cqr = &pb.ChannelQueryResponse{}
+	assert.NoError(t, proto.Unmarshal(res.Payload, cqr))
+	assert.Empty(t, cqr.GetChannels())
 }
 
 func TestGetConfigTree(t *testing.T) {
@@ -495,7 +541,7 @@ func TestPeerConfiger_SubmittingOrdererGenesis(t *testing.T) {
 	os.Mkdir("/tmp/hyperledgertest", 0755)
 	defer os.RemoveAll("/tmp/hyperledgertest/")
 
-	e := New(nil, nil, nil)
+	e := New(nil, nil, nil, nil)
 	stub := shim.NewMockStub("PeerConfiger", e)
 
 	if res := stub.MockInit("1", nil); res.Status != shim.OK {
@@ -11,6 +11,7 @@ import (
 	"regexp"
 
 	"github.com/golang/protobuf/proto"
+	"justledger/common/audit"
 	"justledger/common/cauthdsl"
 	"justledger/common/flogging"
 	"justledger/core/aclmgmt"
@@ -139,6 +140,9 @@ type LifeCycleSysCC struct {
 	Support FilesystemSupport
 
 	PlatformRegistry *platforms.Registry
+
+	// Recorder records chaincode installs to the audit log
+	Recorder audit.Recorder
 }
 
 // New creates a new instance of the LSCC
@@ -150,6 +154,7 @@ func New(sccp sysccprovider.SystemChaincodeProvider, ACLProvider aclmgmt.ACLProv
 		SCCProvider:      sccp,
 		ACLProvider:      ACLProvider,
 		PlatformRegistry: platformRegistry,
+		Recorder:         audit.NoopRecorder{},
 	}
 }
 
@@ -555,6 +560,21 @@ func isValidStatedbArtifactsTar(statedbArtifactsTar []byte) error {
 	return nil
 }
 
+// auditInstall records the outcome of a chaincode install to the audit log.
+// The target is the name and version of the chaincode, if they can be
+// extracted from the package.
+func (lscc *LifeCycleSysCC) auditInstall(stub shim.ChaincodeStubInterface, ccbytes []byte, err error) {
+	if lscc.Recorder == nil {
+		return
+	}
+	creator, _ := stub.GetCreator()
+	target := ""
+	if ccpack, cerr := ccprovider.GetCCPackage(ccbytes); cerr == nil {
+		target = ccpack.GetChaincodeData().Name + ":" + ccpack.GetChaincodeData().Version
+	}
+	lscc.Recorder.Record(creator, INSTALL, target, err)
+}
+
 // executeInstall implements the "install" Invoke transaction
 func (lscc *LifeCycleSysCC) executeInstall(stub shim.ChaincodeStubInterface, ccbytes []byte) error {
 	ccpack, err := ccprovider.GetCCPackage(ccbytes)
@@ -822,14 +842,17 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 			return shim.Error(InvalidArgsLenErr(len(args)).Error())
 		}
 
+		depSpec := args[1]
+
 		// 2. check local MSP Admins policy
 		if err = lscc.PolicyChecker.CheckPolicyNoChannel(mgmt.Admins, sp); err != nil {
-			return shim.Error(fmt.Sprintf("access denied for [%s]: %s", function, err))
+			err = errors.Errorf("access denied for [%s]: %s", function, err)
+			lscc.auditInstall(stub, depSpec, err)
+			return shim.Error(err.Error())
 		}
 
-		depSpec := args[1]
-
 		err := lscc.executeInstall(stub, depSpec)
+		lscc.auditInstall(stub, depSpec, err)
 		if err != nil {
 			return shim.Error(err.Error())
 		}
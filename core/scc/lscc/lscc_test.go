@@ -16,6 +16,7 @@ import (
 	"testing"
 
 	"github.com/golang/protobuf/proto"
+	"justledger/common/audit"
 	"justledger/common/cauthdsl"
 	"justledger/common/mocks/config"
 	mscc "justledger/common/mocks/scc"
@@ -52,6 +53,11 @@ type chaincodeStub interface {
 	shim.ChaincodeStubInterface
 }
 
+//go:generate counterfeiter -o mock/recorder.go --fake-name Recorder . recorder
+type recorder interface {
+	audit.Recorder
+}
+
 //go:generate counterfeiter -o mock/state_query_iterator.go --fake-name StateQueryIterator . stateQueryIterator
 type stateQueryIterator interface {
 	shim.StateQueryIteratorInterface
@@ -148,6 +154,32 @@ func TestInstall(t *testing.T) {
 	testInstall(t, "lscc", "0", path, false, "cannot install: lscc is the name of a system chaincode", "Alice", scc, stub)
 }
 
+func TestInstallAudit(t *testing.T) {
+	cceventmgmt.Initialize(platforms.NewRegistry(&golang.Platform{}))
+
+	recorder := &mock.Recorder{}
+	scc := New(NewMockProvider(), mockAclProvider, platforms.NewRegistry(&golang.Platform{}))
+	scc.Support = &lscc.MockSupport{}
+	scc.Recorder = recorder
+	stub := shim.NewMockStub("lscc", scc)
+	res := stub.MockInit("1", nil)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+
+	path := "justledger/examples/chaincode/go/example02/cmd"
+	testInstall(t, "example02", "0", path, false, "", "Alice", scc, stub)
+	testInstall(t, "example02", "1.0", path, false, "access denied for [install]", "Bob", scc, stub)
+
+	assert.Equal(t, 2, recorder.RecordCallCount())
+	_, action, target, err := recorder.RecordArgsForCall(0)
+	assert.Equal(t, INSTALL, action)
+	assert.Equal(t, "example02:0", target)
+	assert.NoError(t, err)
+	_, action, target, err = recorder.RecordArgsForCall(1)
+	assert.Equal(t, INSTALL, action)
+	assert.Equal(t, "example02:1.0", target)
+	assert.Contains(t, err.Error(), "access denied for [install]")
+}
+
 func testInstall(t *testing.T, ccname string, version string, path string, createInvalidIndex bool, expectedErrorMsg string, caller string, scc *LifeCycleSysCC, stub *shim.MockStub) {
 	identityDeserializer := &policymocks.MockIdentityDeserializer{
 		Identity: []byte("Alice"),
@@ -9,13 +9,17 @@ package qscc
 import (
 	"fmt"
 	"strconv"
+	"time"
 
+	"github.com/golang/protobuf/proto"
 	"justledger/common/flogging"
+	commonledger "justledger/common/ledger"
 
 	"justledger/core/aclmgmt"
 	"justledger/core/chaincode/shim"
 	"justledger/core/ledger"
 	"justledger/core/peer"
+	"justledger/protos/ledger/queryresult"
 	pb "justledger/protos/peer"
 	"justledger/protos/utils"
 )
@@ -41,6 +45,10 @@ func (e *LedgerQuerier) Enabled() bool             { return true }
 // - GetBlockByNumber returns a block
 // - GetBlockByHash returns a block
 // - GetTransactionByID returns a transaction
+// - GetHistoryForKey returns the history of a chaincode key
+// - GetHistoryForKeysByPrefix returns the history of the chaincode keys with a prefix
+// - GetBlocksByTimeRange returns the blocks committed in a time range
+// - GetTransactionsByTimeRange returns the transactions of a time range
 type LedgerQuerier struct {
 	aclProvider aclmgmt.ACLProvider
 }
@@ -49,11 +57,22 @@ var qscclogger = flogging.MustGetLogger("qscc")
 
 // These are function names from Invoke first parameter
 const (
-	GetChainInfo       string = "GetChainInfo"
-	GetBlockByNumber   string = "GetBlockByNumber"
-	GetBlockByHash     string = "GetBlockByHash"
-	GetTransactionByID string = "GetTransactionByID"
-	GetBlockByTxID     string = "GetBlockByTxID"
+	GetChainInfo               string = "GetChainInfo"
+	GetBlockByNumber           string = "GetBlockByNumber"
+	GetBlockByHash             string = "GetBlockByHash"
+	GetTransactionByID         string = "GetTransactionByID"
+	GetBlockByTxID             string = "GetBlockByTxID"
+	GetHistoryForKey           string = "GetHistoryForKey"
+	GetHistoryForKeysByPrefix  string = "GetHistoryForKeysByPrefix"
+	GetBlocksByTimeRange       string = "GetBlocksByTimeRange"
+	GetTransactionsByTimeRange string = "GetTransactionsByTimeRange"
+)
+
+const (
+	// defaultPageSize is the page size of the paged queries that do not specify one
+	defaultPageSize = 100
+	// maxPageSize is the largest page size of the paged queries
+	maxPageSize = 1000
 )
 
 // Init is called once per chain when the chain is created.
@@ -72,6 +91,24 @@ func (e *LedgerQuerier) Init(stub shim.ChaincodeStubInterface) pb.Response {
 // # GetBlockByNumber: Return the block specified by block number in args[2]
 // # GetBlockByHash: Return the block specified by block hash in args[2]
 // # GetTransactionByID: Return the transaction specified by ID in args[2]
+// # GetHistoryForKey: Return the history of the key in args[3] of the chaincode
+//   in args[2] as a QueryResponse of KeyModification objects. The optional
+//   args[4] is a marshalled HistoryQueryOptions restricting and ordering the
+//   history
+// # GetHistoryForKeysByPrefix: Return a page of the history of the keys of the
+//   chaincode in args[2] starting with the non empty prefix in args[3], with the
+//   same optional args[4] as GetHistoryForKey. The optional args[5] is the page
+//   size, between 1 and 1000 (100 by default), and the optional args[6] is the
+//   bookmark of the page returned in the metadata of the QueryResponse of the
+//   previous page
+// # GetBlocksByTimeRange: Return a page of the blocks committed in the time range
+//   [args[2], args[3]), in RFC3339 format, as a QueryResponse of Block objects. The
+//   optional args[4] is the page size, between 1 and 1000 (100 by default), and
+//   the optional args[5] is the bookmark of the page returned in the metadata of
+//   the QueryResponse of the previous page
+// # GetTransactionsByTimeRange: Return a page of the transactions whose timestamp
+//   is in the time range as a QueryResponse of ProcessedTransaction objects, with
+//   the same arguments as GetBlocksByTimeRange
 func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 	args := stub.GetArgs()
 
@@ -85,6 +122,10 @@ func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 		return shim.Error(fmt.Sprintf("missing 3rd argument for %s", fname))
 	}
 
+	if (fname == GetHistoryForKey || fname == GetHistoryForKeysByPrefix || fname == GetBlocksByTimeRange || fname == GetTransactionsByTimeRange) && len(args) < 4 {
+		return shim.Error(fmt.Sprintf("missing 4th argument for %s", fname))
+	}
+
 	targetLedger := peer.GetLedger(cid)
 	if targetLedger == nil {
 		return shim.Error(fmt.Sprintf("Invalid chain ID, %s", cid))
@@ -116,6 +157,14 @@ func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 		return getChainInfo(targetLedger)
 	case GetBlockByTxID:
 		return getBlockByTxID(targetLedger, args[2])
+	case GetHistoryForKey:
+		return getHistoryForKey(targetLedger, args[2:])
+	case GetHistoryForKeysByPrefix:
+		return getHistoryForKeysByPrefix(targetLedger, args[2:])
+	case GetBlocksByTimeRange:
+		return getBlocksByTimeRange(targetLedger, args[2:])
+	case GetTransactionsByTimeRange:
+		return getTransactionsByTimeRange(targetLedger, args[2:])
 	}
 
 	return shim.Error(fmt.Sprintf("Requested function %s not found.", fname))
@@ -215,6 +264,217 @@ func getBlockByTxID(vledger ledger.PeerLedger, rawTxID []byte) pb.Response {
 	return shim.Success(bytes)
 }
 
+func getHistoryForKey(vledger ledger.PeerLedger, historyArgs [][]byte) pb.Response {
+	ccName := string(historyArgs[0])
+	if ccName == "" {
+		return shim.Error("Chaincode name must not be empty.")
+	}
+	key := string(historyArgs[1])
+
+	hqe, err := vledger.NewHistoryQueryExecutor()
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get history query executor, error %s", err))
+	}
+	var itr commonledger.ResultsIterator
+	if len(historyArgs) < 3 {
+		itr, err = hqe.GetHistoryForKey(ccName, key)
+	} else {
+		var options *ledger.HistoryQueryOptions
+		if options, err = parseHistoryQueryOptions(historyArgs[2]); err != nil {
+			return shim.Error(err.Error())
+		}
+		itr, err = hqe.GetHistoryForKeyWithOptions(ccName, key, options)
+	}
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get history for key %s of chaincode %s, error %s", key, ccName, err))
+	}
+	defer itr.Close()
+
+	resp := &pb.QueryResponse{}
+	if err := appendKeyModifications(resp, itr, 0); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get history for key %s of chaincode %s, error %s", key, ccName, err))
+	}
+
+	bytes, err := utils.Marshal(resp)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(bytes)
+}
+
+func getHistoryForKeysByPrefix(vledger ledger.PeerLedger, historyArgs [][]byte) pb.Response {
+	ccName := string(historyArgs[0])
+	if ccName == "" {
+		return shim.Error("Chaincode name must not be empty.")
+	}
+	keyPrefix := string(historyArgs[1])
+	if keyPrefix == "" {
+		return shim.Error("Key prefix must not be empty.")
+	}
+	options := &ledger.HistoryQueryOptions{}
+	var pageArgs [][]byte
+	var err error
+	if len(historyArgs) > 2 {
+		if options, err = parseHistoryQueryOptions(historyArgs[2]); err != nil {
+			return shim.Error(err.Error())
+		}
+		pageArgs = historyArgs[3:]
+	}
+	var pageSize int
+	if pageSize, options.Bookmark, err = parsePageArgs(pageArgs); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	hqe, err := vledger.NewHistoryQueryExecutor()
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get history query executor, error %s", err))
+	}
+	itr, err := hqe.GetHistoryForKeysByPrefix(ccName, keyPrefix, options)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get history for keys with prefix %s of chaincode %s, error %s", keyPrefix, ccName, err))
+	}
+
+	resp := &pb.QueryResponse{}
+	if err := appendKeyModifications(resp, itr, pageSize); err != nil {
+		itr.Close()
+		return shim.Error(fmt.Sprintf("Failed to get history for keys with prefix %s of chaincode %s, error %s", keyPrefix, ccName, err))
+	}
+	nextBookmark := ""
+	if len(resp.Results) == pageSize {
+		nextBookmark = itr.GetBookmarkAndClose()
+	} else {
+		itr.Close()
+	}
+	return pagedQueryResponse(resp, nextBookmark)
+}
+
+// parseHistoryQueryOptions parses the marshalled HistoryQueryOptions of a history query
+func parseHistoryQueryOptions(optionsBytes []byte) (*ledger.HistoryQueryOptions, error) {
+	protoOptions := &pb.HistoryQueryOptions{}
+	if err := proto.Unmarshal(optionsBytes, protoOptions); err != nil {
+		return nil, fmt.Errorf("Invalid history query options, error %s", err)
+	}
+	options, err := ledger.NewHistoryQueryOptions(protoOptions)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid history query options, error %s", err)
+	}
+	return options, nil
+}
+
+// appendKeyModifications appends to the query response the key modifications of the iterator, at most maxResults
+// of them unless maxResults is 0
+func appendKeyModifications(resp *pb.QueryResponse, itr commonledger.ResultsIterator, maxResults int) error {
+	for maxResults == 0 || len(resp.Results) < maxResults {
+		res, err := itr.Next()
+		if err != nil {
+			return err
+		}
+		if res == nil {
+			break
+		}
+		bytes, err := utils.Marshal(res.(*queryresult.KeyModification))
+		if err != nil {
+			return err
+		}
+		resp.Results = append(resp.Results, &pb.QueryResultBytes{ResultBytes: bytes})
+	}
+	return nil
+}
+
+func getBlocksByTimeRange(vledger ledger.PeerLedger, rangeArgs [][]byte) pb.Response {
+	startTime, endTime, pageSize, bookmark, err := parseTimeRangeArgs(rangeArgs)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	blocks, nextBookmark, err := vledger.GetBlocksByTimeRange(startTime, endTime, pageSize, bookmark)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get blocks in time range [%s, %s), error %s", rangeArgs[0], rangeArgs[1], err))
+	}
+
+	resp := &pb.QueryResponse{}
+	for _, block := range blocks {
+		bytes, err := utils.Marshal(block)
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		resp.Results = append(resp.Results, &pb.QueryResultBytes{ResultBytes: bytes})
+	}
+	return pagedQueryResponse(resp, nextBookmark)
+}
+
+func getTransactionsByTimeRange(vledger ledger.PeerLedger, rangeArgs [][]byte) pb.Response {
+	startTime, endTime, pageSize, bookmark, err := parseTimeRangeArgs(rangeArgs)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	txs, nextBookmark, err := vledger.GetTransactionsByTimeRange(startTime, endTime, pageSize, bookmark)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get transactions in time range [%s, %s), error %s", rangeArgs[0], rangeArgs[1], err))
+	}
+
+	resp := &pb.QueryResponse{}
+	for _, tx := range txs {
+		bytes, err := utils.Marshal(tx)
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		resp.Results = append(resp.Results, &pb.QueryResultBytes{ResultBytes: bytes})
+	}
+	return pagedQueryResponse(resp, nextBookmark)
+}
+
+// parseTimeRangeArgs parses the start time, the end time, the optional page size and the optional bookmark of a
+// time range query
+func parseTimeRangeArgs(rangeArgs [][]byte) (startTime, endTime time.Time, pageSize int, bookmark string, err error) {
+	if startTime, err = time.Parse(time.RFC3339Nano, string(rangeArgs[0])); err != nil {
+		return startTime, endTime, 0, "", fmt.Errorf("Failed to parse start time with error %s", err)
+	}
+	if endTime, err = time.Parse(time.RFC3339Nano, string(rangeArgs[1])); err != nil {
+		return startTime, endTime, 0, "", fmt.Errorf("Failed to parse end time with error %s", err)
+	}
+	if !startTime.Before(endTime) {
+		return startTime, endTime, 0, "", fmt.Errorf("Start time %s must be before end time %s", rangeArgs[0], rangeArgs[1])
+	}
+	pageSize, bookmark, err = parsePageArgs(rangeArgs[2:])
+	return startTime, endTime, pageSize, bookmark, err
+}
+
+// parsePageArgs parses the optional page size and the optional bookmark of a paged query
+func parsePageArgs(pageArgs [][]byte) (pageSize int, bookmark string, err error) {
+	pageSize = defaultPageSize
+	if len(pageArgs) > 0 && len(pageArgs[0]) > 0 {
+		if pageSize, err = strconv.Atoi(string(pageArgs[0])); err != nil || pageSize < 1 || pageSize > maxPageSize {
+			return 0, "", fmt.Errorf("Invalid page size %s, it must be between 1 and %d", pageArgs[0], maxPageSize)
+		}
+	}
+	if len(pageArgs) > 1 {
+		bookmark = string(pageArgs[1])
+	}
+	return pageSize, bookmark, nil
+}
+
+// pagedQueryResponse returns the query response of a page along with the metadata holding the number of records
+// of the page and the bookmark of the next page, which is empty for the last page
+func pagedQueryResponse(resp *pb.QueryResponse, nextBookmark string) pb.Response {
+	resp.HasMore = nextBookmark != ""
+	metadata, err := utils.Marshal(&pb.QueryResponseMetadata{
+		FetchedRecordsCount: int32(len(resp.Results)),
+		Bookmark:            nextBookmark,
+	})
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	resp.Metadata = metadata
+
+	bytes, err := utils.Marshal(resp)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(bytes)
+}
+
 func getACLResource(fname string) string {
 	return "qscc/" + fname
 }
@@ -20,7 +20,9 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/proto"
 	"justledger/common/ledger/testutil"
 	"justledger/common/util"
 	"justledger/core/aclmgmt/mocks"
@@ -29,6 +31,7 @@ import (
 	ledger2 "justledger/core/ledger"
 	"justledger/core/peer"
 	"justledger/protos/common"
+	"justledger/protos/ledger/queryresult"
 	peer2 "justledger/protos/peer"
 	"justledger/protos/utils"
 	"github.com/pkg/errors"
@@ -179,6 +182,329 @@ func TestQueryGetBlockByTxID(t *testing.T) {
 	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlockByTxID should have failed with blank txId.")
 }
 
+func TestQueryGetHistoryForKey(t *testing.T) {
+	chainid := "mytestchainid9"
+	path := tempDir(t, "test9")
+	defer os.RemoveAll(path)
+
+	viper.Set("ledger.history.enableHistoryDatabase", true)
+	defer viper.Set("ledger.history.enableHistoryDatabase", false)
+	stub, err := setupTestLedger(chainid, path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	addBlockForTesting(t, chainid)
+
+	args := [][]byte{[]byte(GetHistoryForKey), []byte(chainid), []byte("ns1"), []byte("key1")}
+	prop := resetProvider(resources.Qscc_GetHistoryForKey, chainid, &peer2.SignedProposal{}, nil)
+	res := stub.MockInvokeWithSignedProposal("1", args, prop)
+	assert.Equal(t, int32(shim.OK), res.Status, "GetHistoryForKey should have succeeded: %s", res.Message)
+
+	resp := &peer2.QueryResponse{}
+	require.NoError(t, proto.Unmarshal(res.Payload, resp))
+	require.Len(t, resp.Results, 1)
+	km := &queryresult.KeyModification{}
+	require.NoError(t, proto.Unmarshal(resp.Results[0].ResultBytes, km))
+	assert.Equal(t, []byte("value1"), km.Value)
+
+	args = [][]byte{[]byte(GetHistoryForKey), []byte(chainid), []byte("ns1")}
+	res = stub.MockInvokeWithSignedProposal("2", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetHistoryForKey should have failed with a missing key")
+
+	args = [][]byte{[]byte(GetHistoryForKey), []byte(chainid), []byte(""), []byte("key1")}
+	prop = resetProvider(resources.Qscc_GetHistoryForKey, chainid, &peer2.SignedProposal{}, nil)
+	res = stub.MockInvokeWithSignedProposal("3", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetHistoryForKey should have failed with a blank chaincode name")
+}
+
+func TestQueryGetHistoryForKeysByPrefix(t *testing.T) {
+	chainid := "mytestchainid13"
+	path := tempDir(t, "test13")
+	defer os.RemoveAll(path)
+
+	viper.Set("ledger.history.enableHistoryDatabase", true)
+	defer viper.Set("ledger.history.enableHistoryDatabase", false)
+	stub, err := setupTestLedger(chainid, path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	addBlockForTesting(t, chainid)
+
+	args := [][]byte{[]byte(GetHistoryForKeysByPrefix), []byte(chainid), []byte("ns1"), []byte("key")}
+	prop := resetProvider(resources.Qscc_GetHistoryForKeysByPrefix, chainid, &peer2.SignedProposal{}, nil)
+	res := stub.MockInvokeWithSignedProposal("1", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetHistoryForKeysByPrefix should have succeeded: %s", res.Message)
+	assert.Equal(t, []string{"key1", "key2", "key3"}, unmarshalHistoryKeys(t, res.Payload))
+
+	// the options apply to the history of each key
+	options, err := proto.Marshal(&peer2.HistoryQueryOptions{StartBlock: 2})
+	require.NoError(t, err)
+	args = [][]byte{[]byte(GetHistoryForKeysByPrefix), []byte(chainid), []byte("ns1"), []byte("key"), options}
+	prop = resetProvider(resources.Qscc_GetHistoryForKeysByPrefix, chainid, &peer2.SignedProposal{}, nil)
+	res = stub.MockInvokeWithSignedProposal("2", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetHistoryForKeysByPrefix should have succeeded: %s", res.Message)
+	assert.Empty(t, unmarshalHistoryKeys(t, res.Payload))
+
+	options, err = proto.Marshal(&peer2.HistoryQueryOptions{NewestFirst: true})
+	require.NoError(t, err)
+	args = [][]byte{[]byte(GetHistoryForKey), []byte(chainid), []byte("ns2"), []byte("key5"), options}
+	prop = resetProvider(resources.Qscc_GetHistoryForKey, chainid, &peer2.SignedProposal{}, nil)
+	res = stub.MockInvokeWithSignedProposal("3", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetHistoryForKey should have succeeded: %s", res.Message)
+	assert.Equal(t, []string{"key5"}, unmarshalHistoryKeys(t, res.Payload))
+
+	options, err = proto.Marshal(&peer2.HistoryQueryOptions{StartBlock: 2, EndBlock: 1})
+	require.NoError(t, err)
+	args = [][]byte{[]byte(GetHistoryForKey), []byte(chainid), []byte("ns2"), []byte("key5"), options}
+	prop = resetProvider(resources.Qscc_GetHistoryForKey, chainid, &peer2.SignedProposal{}, nil)
+	res = stub.MockInvokeWithSignedProposal("4", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetHistoryForKey should have failed with an invalid block range")
+
+	args = [][]byte{[]byte(GetHistoryForKeysByPrefix), []byte(chainid), []byte("ns1"), []byte("key"), []byte("bogus")}
+	prop = resetProvider(resources.Qscc_GetHistoryForKeysByPrefix, chainid, &peer2.SignedProposal{}, nil)
+	res = stub.MockInvokeWithSignedProposal("5", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetHistoryForKeysByPrefix should have failed with invalid options")
+	assert.Contains(t, res.Message, "Invalid history query options")
+
+	args = [][]byte{[]byte(GetHistoryForKeysByPrefix), []byte(chainid), []byte("ns1")}
+	res = stub.MockInvokeWithSignedProposal("6", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetHistoryForKeysByPrefix should have failed with a missing prefix")
+
+	args = [][]byte{[]byte(GetHistoryForKeysByPrefix), []byte(chainid), []byte("ns1"), []byte("")}
+	prop = resetProvider(resources.Qscc_GetHistoryForKeysByPrefix, chainid, &peer2.SignedProposal{}, nil)
+	res = stub.MockInvokeWithSignedProposal("7", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetHistoryForKeysByPrefix should have failed with an empty prefix")
+	assert.Equal(t, "Key prefix must not be empty.", res.Message)
+}
+
+func TestQueryGetHistoryForKeysByPrefixPaging(t *testing.T) {
+	chainid := "mytestchainid14"
+	path := tempDir(t, "test14")
+	defer os.RemoveAll(path)
+
+	viper.Set("ledger.history.enableHistoryDatabase", true)
+	defer viper.Set("ledger.history.enableHistoryDatabase", false)
+	stub, err := setupTestLedger(chainid, path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	addBlockForTesting(t, chainid)
+
+	for i, newestFirst := range []bool{false, true} {
+		options, err := proto.Marshal(&peer2.HistoryQueryOptions{NewestFirst: newestFirst})
+		require.NoError(t, err)
+		expectedKeys := []string{"key1", "key2", "key3"}
+		if newestFirst {
+			expectedKeys = []string{"key3", "key2", "key1"}
+		}
+
+		// the first page holds two keys
+		args := [][]byte{[]byte(GetHistoryForKeysByPrefix), []byte(chainid), []byte("ns1"), []byte("key"), options, []byte("2")}
+		prop := resetProvider(resources.Qscc_GetHistoryForKeysByPrefix, chainid, &peer2.SignedProposal{}, nil)
+		res := stub.MockInvokeWithSignedProposal(fmt.Sprintf("%d-1", i), args, prop)
+		require.Equal(t, int32(shim.OK), res.Status, "GetHistoryForKeysByPrefix should have succeeded: %s", res.Message)
+		resp, metadata := unmarshalPagedQueryResponse(t, res.Payload)
+		assert.True(t, resp.HasMore)
+		assert.Equal(t, int32(2), metadata.FetchedRecordsCount)
+		assert.NotEmpty(t, metadata.Bookmark)
+		assert.Equal(t, expectedKeys[:2], unmarshalHistoryKeys(t, res.Payload))
+
+		// the last page holds the remaining key
+		args = append(args, []byte(metadata.Bookmark))
+		prop = resetProvider(resources.Qscc_GetHistoryForKeysByPrefix, chainid, &peer2.SignedProposal{}, nil)
+		res = stub.MockInvokeWithSignedProposal(fmt.Sprintf("%d-2", i), args, prop)
+		require.Equal(t, int32(shim.OK), res.Status, "GetHistoryForKeysByPrefix should have succeeded: %s", res.Message)
+		resp, metadata = unmarshalPagedQueryResponse(t, res.Payload)
+		assert.False(t, resp.HasMore)
+		assert.Empty(t, metadata.Bookmark)
+		assert.Equal(t, expectedKeys[2:], unmarshalHistoryKeys(t, res.Payload))
+	}
+
+	// a page that holds the last key exactly has no next page
+	args := [][]byte{[]byte(GetHistoryForKeysByPrefix), []byte(chainid), []byte("ns1"), []byte("key"), nil, []byte("3")}
+	prop := resetProvider(resources.Qscc_GetHistoryForKeysByPrefix, chainid, &peer2.SignedProposal{}, nil)
+	res := stub.MockInvokeWithSignedProposal("3", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetHistoryForKeysByPrefix should have succeeded: %s", res.Message)
+	resp, _ := unmarshalPagedQueryResponse(t, res.Payload)
+	assert.False(t, resp.HasMore)
+	assert.Len(t, resp.Results, 3)
+
+	for _, pageSize := range []string{"0", "1001", "bogus"} {
+		args = [][]byte{[]byte(GetHistoryForKeysByPrefix), []byte(chainid), []byte("ns1"), []byte("key"), nil, []byte(pageSize)}
+		prop = resetProvider(resources.Qscc_GetHistoryForKeysByPrefix, chainid, &peer2.SignedProposal{}, nil)
+		res = stub.MockInvokeWithSignedProposal("4", args, prop)
+		assert.Equal(t, int32(shim.ERROR), res.Status, "GetHistoryForKeysByPrefix should have failed with page size %s", pageSize)
+		assert.Equal(t, "Invalid page size "+pageSize+", it must be between 1 and 1000", res.Message)
+	}
+
+	for _, bookmark := range []string{"bogus", "00"} {
+		args = [][]byte{[]byte(GetHistoryForKeysByPrefix), []byte(chainid), []byte("ns1"), []byte("key"), nil, nil, []byte(bookmark)}
+		prop = resetProvider(resources.Qscc_GetHistoryForKeysByPrefix, chainid, &peer2.SignedProposal{}, nil)
+		res = stub.MockInvokeWithSignedProposal("5", args, prop)
+		assert.Equal(t, int32(shim.ERROR), res.Status, "GetHistoryForKeysByPrefix should have failed with bookmark %s", bookmark)
+		assert.Contains(t, res.Message, "bookmark")
+	}
+}
+
+func unmarshalHistoryKeys(t *testing.T, payload []byte) []string {
+	resp := &peer2.QueryResponse{}
+	require.NoError(t, proto.Unmarshal(payload, resp))
+	var keys []string
+	for _, result := range resp.Results {
+		km := &queryresult.KeyModification{}
+		require.NoError(t, proto.Unmarshal(result.ResultBytes, km))
+		keys = append(keys, km.Key)
+	}
+	return keys
+}
+
+func TestQueryGetBlocksByTimeRange(t *testing.T) {
+	chainid := "mytestchainid10"
+	path := tempDir(t, "test10")
+	defer os.RemoveAll(path)
+
+	viper.Set("ledger.blockchain.enableTimeIndex", true)
+	defer viper.Set("ledger.blockchain.enableTimeIndex", false)
+	stub, err := setupTestLedger(chainid, path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	block1 := addBlockForTesting(t, chainid)
+	startTime := []byte(time.Now().Add(-time.Hour).Format(time.RFC3339))
+	endTime := []byte(time.Now().Add(time.Hour).Format(time.RFC3339))
+
+	// the first page holds the genesis block
+	args := [][]byte{[]byte(GetBlocksByTimeRange), []byte(chainid), startTime, endTime, []byte("1")}
+	prop := resetProvider(resources.Qscc_GetBlocksByTimeRange, chainid, &peer2.SignedProposal{}, nil)
+	res := stub.MockInvokeWithSignedProposal("1", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetBlocksByTimeRange should have succeeded: %s", res.Message)
+	resp, metadata := unmarshalPagedQueryResponse(t, res.Payload)
+	require.Len(t, resp.Results, 1)
+	assert.True(t, resp.HasMore)
+	assert.Equal(t, int32(1), metadata.FetchedRecordsCount)
+	assert.NotEmpty(t, metadata.Bookmark)
+	block := &common.Block{}
+	require.NoError(t, proto.Unmarshal(resp.Results[0].ResultBytes, block))
+	assert.Equal(t, uint64(0), block.Header.Number)
+
+	// the last page holds block1
+	args = [][]byte{[]byte(GetBlocksByTimeRange), []byte(chainid), startTime, endTime, []byte("1"), []byte(metadata.Bookmark)}
+	prop = resetProvider(resources.Qscc_GetBlocksByTimeRange, chainid, &peer2.SignedProposal{}, nil)
+	res = stub.MockInvokeWithSignedProposal("2", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetBlocksByTimeRange should have succeeded: %s", res.Message)
+	resp, metadata = unmarshalPagedQueryResponse(t, res.Payload)
+	require.Len(t, resp.Results, 1)
+	assert.False(t, resp.HasMore)
+	assert.Empty(t, metadata.Bookmark)
+	require.NoError(t, proto.Unmarshal(resp.Results[0].ResultBytes, block))
+	assert.True(t, proto.Equal(block1.Header, block.Header))
+
+	// without page size, a page of the default size is returned
+	args = [][]byte{[]byte(GetBlocksByTimeRange), []byte(chainid), startTime, endTime}
+	prop = resetProvider(resources.Qscc_GetBlocksByTimeRange, chainid, &peer2.SignedProposal{}, nil)
+	res = stub.MockInvokeWithSignedProposal("3", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetBlocksByTimeRange should have succeeded: %s", res.Message)
+	resp, _ = unmarshalPagedQueryResponse(t, res.Payload)
+	assert.Len(t, resp.Results, 2)
+
+	args = [][]byte{[]byte(GetBlocksByTimeRange), []byte(chainid), endTime, []byte(time.Now().Add(2 * time.Hour).Format(time.RFC3339))}
+	prop = resetProvider(resources.Qscc_GetBlocksByTimeRange, chainid, &peer2.SignedProposal{}, nil)
+	res = stub.MockInvokeWithSignedProposal("4", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetBlocksByTimeRange should have succeeded: %s", res.Message)
+	resp, _ = unmarshalPagedQueryResponse(t, res.Payload)
+	assert.Empty(t, resp.Results)
+
+	args = [][]byte{[]byte(GetBlocksByTimeRange), []byte(chainid), startTime}
+	res = stub.MockInvokeWithSignedProposal("5", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlocksByTimeRange should have failed with a missing end time")
+
+	args = [][]byte{[]byte(GetBlocksByTimeRange), []byte(chainid), []byte("yesterday"), endTime}
+	res = stub.MockInvokeWithSignedProposal("6", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlocksByTimeRange should have failed with an invalid start time")
+
+	args = [][]byte{[]byte(GetBlocksByTimeRange), []byte(chainid), endTime, startTime}
+	res = stub.MockInvokeWithSignedProposal("7", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlocksByTimeRange should have failed with an empty time range")
+
+	for _, pageSize := range []string{"-1", "0", "1001", "ten"} {
+		args = [][]byte{[]byte(GetBlocksByTimeRange), []byte(chainid), startTime, endTime, []byte(pageSize)}
+		res = stub.MockInvokeWithSignedProposal("8", args, prop)
+		assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlocksByTimeRange should have failed with page size %s", pageSize)
+		assert.Equal(t, fmt.Sprintf("Invalid page size %s, it must be between 1 and 1000", pageSize), res.Message)
+	}
+
+	args = [][]byte{[]byte(GetBlocksByTimeRange), []byte(chainid), startTime, endTime, []byte("1"), []byte("not a bookmark")}
+	res = stub.MockInvokeWithSignedProposal("9", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlocksByTimeRange should have failed with an invalid bookmark")
+}
+
+func TestQueryGetTransactionsByTimeRange(t *testing.T) {
+	chainid := "mytestchainid11"
+	path := tempDir(t, "test11")
+	defer os.RemoveAll(path)
+
+	viper.Set("ledger.blockchain.enableTimeIndex", true)
+	defer viper.Set("ledger.blockchain.enableTimeIndex", false)
+	stub, err := setupTestLedger(chainid, path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	block1 := addBlockForTesting(t, chainid)
+	startTime := []byte(time.Now().Add(-time.Hour).Format(time.RFC3339))
+	endTime := []byte(time.Now().Add(time.Hour).Format(time.RFC3339))
+
+	var txs []*peer2.ProcessedTransaction
+	bookmark := ""
+	for i := 0; ; i++ {
+		args := [][]byte{[]byte(GetTransactionsByTimeRange), []byte(chainid), startTime, endTime, []byte("1"), []byte(bookmark)}
+		prop := resetProvider(resources.Qscc_GetTransactionsByTimeRange, chainid, &peer2.SignedProposal{}, nil)
+		res := stub.MockInvokeWithSignedProposal(fmt.Sprintf("%d", i), args, prop)
+		require.Equal(t, int32(shim.OK), res.Status, "GetTransactionsByTimeRange should have succeeded: %s", res.Message)
+		resp, metadata := unmarshalPagedQueryResponse(t, res.Payload)
+		require.Len(t, resp.Results, 1)
+		tx := &peer2.ProcessedTransaction{}
+		require.NoError(t, proto.Unmarshal(resp.Results[0].ResultBytes, tx))
+		txs = append(txs, tx)
+		if !resp.HasMore {
+			break
+		}
+		bookmark = metadata.Bookmark
+	}
+
+	// the transactions of block1 are the last ones of the range
+	require.True(t, len(txs) >= 2)
+	for i, tx := range txs[len(txs)-2:] {
+		assert.Equal(t, block1.Data.Data[i], utils.MarshalOrPanic(tx.TransactionEnvelope))
+		assert.Equal(t, int32(peer2.TxValidationCode_VALID), tx.ValidationCode)
+	}
+}
+
+func TestQueryTimeRangeWithoutTimeIndex(t *testing.T) {
+	chainid := "mytestchainid12"
+	path := tempDir(t, "test12")
+	defer os.RemoveAll(path)
+
+	stub, err := setupTestLedger(chainid, path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	startTime := []byte(time.Now().Add(-time.Hour).Format(time.RFC3339))
+	endTime := []byte(time.Now().Add(time.Hour).Format(time.RFC3339))
+
+	args := [][]byte{[]byte(GetTransactionsByTimeRange), []byte(chainid), startTime, endTime}
+	prop := resetProvider(resources.Qscc_GetTransactionsByTimeRange, chainid, &peer2.SignedProposal{}, nil)
+	res := stub.MockInvokeWithSignedProposal("1", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetTransactionsByTimeRange should have failed without time index")
+}
+
+func unmarshalPagedQueryResponse(t *testing.T, payload []byte) (*peer2.QueryResponse, *peer2.QueryResponseMetadata) {
+	resp := &peer2.QueryResponse{}
+	require.NoError(t, proto.Unmarshal(payload, resp))
+	metadata := &peer2.QueryResponseMetadata{}
+	require.NoError(t, proto.Unmarshal(resp.Metadata, metadata))
+	return resp, metadata
+}
+
 func TestFailingAccessControl(t *testing.T) {
 	chainid := "mytestchainid6"
 	path := tempDir(t, "test6")
@@ -34,6 +34,8 @@ var ErrStoreEmpty = errors.New("Transient store is empty")
 // StoreProvider provides an instance of a TransientStore
 type StoreProvider interface {
 	OpenStore(ledgerID string) (Store, error)
+	// RemoveStore deletes the private write sets of all the transactions of the given ledger
+	RemoveStore(ledgerID string) error
 	Close()
 }
 
@@ -78,6 +80,10 @@ type Store interface {
 	// after successful block commit, PurgeByHeight() is still required to remove orphan entries (as
 	// transaction that gets endorsed may not be submitted by the client for commit)
 	PurgeByHeight(maxBlockNumToRetain uint64) error
+	// PurgeByKeyHashes removes the private data keys purged by the transactions of the given block
+	// from the private write sets that were persisted at a block height of blockNum or lower, i.e.,
+	// the private write sets that were simulated before the purge was committed
+	PurgeByKeyHashes(blockNum uint64, purges []*ledger.PvtdataPurge) error
 	// GetMinTransientBlkHt returns the lowest block height remaining in transient store
 	GetMinTransientBlkHt() (uint64, error)
 	Shutdown()
@@ -131,6 +137,11 @@ func (provider *storeProvider) OpenStore(ledgerID string) (Store, error) {
 	return &store{db: dbHandle, ledgerID: ledgerID}, nil
 }
 
+// RemoveStore deletes the private write sets of all the transactions of the given ledger
+func (provider *storeProvider) RemoveStore(ledgerID string) error {
+	return provider.dbProvider.GetDBHandle(ledgerID).DeleteAll()
+}
+
 // Close closes the TransientStoreProvider
 func (provider *storeProvider) Close() {
 	provider.dbProvider.Close()
@@ -337,6 +348,40 @@ func (s *store) PurgeByHeight(maxBlockNumToRetain uint64) error {
 	return s.db.WriteBatch(dbBatch, true)
 }
 
+// PurgeByKeyHashes removes the private data keys purged by the transactions of the given block
+// from the private write sets that were persisted at a block height of blockNum or lower. This
+// makes sure that a purged value does not survive in the private write set of a transaction that
+// was endorsed before the purge and that is still waiting to be committed
+func (s *store) PurgeByKeyHashes(blockNum uint64, purges []*ledger.PvtdataPurge) error {
+	if len(purges) == 0 {
+		return nil
+	}
+
+	logger.Debugf("Purging %d private data keys from transient store for block [%d]", len(purges), blockNum)
+
+	startKey := createPvtRWSetRangeStartKey()
+	endKey := createPvtRWSetRangeEndKey()
+	iter := s.db.GetIterator(startKey, endKey)
+	defer iter.Release()
+
+	dbBatch := leveldbhelper.NewUpdateBatch()
+	for iter.Next() {
+		compositeKeyPvtRWSet := iter.Key()
+		_, blockHeight := splitCompositeKeyOfPvtRWSet(compositeKeyPvtRWSet)
+		if blockHeight > blockNum {
+			continue
+		}
+		value, purged, err := purgeKeysFromPvtRWSetValue(iter.Value(), purges)
+		if err != nil {
+			return err
+		}
+		if purged {
+			dbBatch.Put(append([]byte{}, compositeKeyPvtRWSet...), value)
+		}
+	}
+	return s.db.WriteBatch(dbBatch, true)
+}
+
 // GetMinTransientBlkHt returns the lowest block height remaining in transient store
 func (s *store) GetMinTransientBlkHt() (uint64, error) {
 	// Current approach performs a range query on purgeIndex with startKey
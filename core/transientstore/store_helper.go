@@ -11,11 +11,15 @@ import (
 	"errors"
 	"path/filepath"
 
+	"github.com/golang/protobuf/proto"
 	"justledger/common/ledger/util"
 	"justledger/core/config"
 	"justledger/core/ledger"
+	ledgerutil "justledger/core/ledger/util"
 	"justledger/protos/common"
 	"justledger/protos/ledger/rwset"
+	"justledger/protos/ledger/rwset/kvrwset"
+	"justledger/protos/transientstore"
 )
 
 var (
@@ -178,6 +182,18 @@ func createPurgeIndexByTxidRangeEndKey(txid string) []byte {
 	return endKey
 }
 
+// createPvtRWSetRangeStartKey returns a startKey to do a range query on all the private
+// write sets in the transient store
+func createPvtRWSetRangeStartKey() []byte {
+	return []byte{prwsetPrefix, compositeKeySep}
+}
+
+// createPvtRWSetRangeEndKey returns a endKey to do a range query on all the private
+// write sets in the transient store
+func createPvtRWSetRangeEndKey() []byte {
+	return []byte{prwsetPrefix, compositeKeySep + 1}
+}
+
 // GetTransientStorePath returns the filesystem path for temporarily storing the private rwset
 func GetTransientStorePath() string {
 	sysPath := config.GetPath("peer.fileSystemPath")
@@ -240,3 +256,103 @@ func trimPvtCollectionConfigs(configs map[string]*common.CollectionConfigPackage
 	}
 	return result, nil
 }
+
+// purgeKeysFromPvtRWSetValue removes the purged keys from the private write set stored as the given
+// value, which is either a TxPvtReadWriteSetWithConfigInfo prefixed by a nil byte or a plain
+// TxPvtReadWriteSet. The returned bool indicates whether any write was removed
+func purgeKeysFromPvtRWSetValue(value []byte, purges []*ledger.PvtdataPurge) ([]byte, bool, error) {
+	if len(value) > 0 && value[0] == nilByte {
+		txPvtRWSetWithConfig := &transientstore.TxPvtReadWriteSetWithConfigInfo{}
+		if err := proto.Unmarshal(value[1:], txPvtRWSetWithConfig); err != nil {
+			return nil, false, err
+		}
+		purged, err := purgeKeysFromTxPvtRWSet(txPvtRWSetWithConfig.PvtRwset, purges)
+		if err != nil || !purged {
+			return nil, false, err
+		}
+		txPvtRWSetWithConfigBytes, err := proto.Marshal(txPvtRWSetWithConfig)
+		if err != nil {
+			return nil, false, err
+		}
+		return append([]byte{nilByte}, txPvtRWSetWithConfigBytes...), true, nil
+	}
+
+	txPvtRWSet := &rwset.TxPvtReadWriteSet{}
+	if err := proto.Unmarshal(value, txPvtRWSet); err != nil {
+		return nil, false, err
+	}
+	purged, err := purgeKeysFromTxPvtRWSet(txPvtRWSet, purges)
+	if err != nil || !purged {
+		return nil, false, err
+	}
+	txPvtRWSetBytes, err := proto.Marshal(txPvtRWSet)
+	if err != nil {
+		return nil, false, err
+	}
+	return txPvtRWSetBytes, true, nil
+}
+
+// purgeKeysFromTxPvtRWSet removes, in place, the writes of the purged keys from the given
+// private write set. The returned bool indicates whether any write was removed
+func purgeKeysFromTxPvtRWSet(txPvtRWSet *rwset.TxPvtReadWriteSet, purges []*ledger.PvtdataPurge) (bool, error) {
+	if txPvtRWSet == nil {
+		return false, nil
+	}
+	purged := false
+	for _, nsPvtRWSet := range txPvtRWSet.NsPvtRwset {
+		for _, collPvtRWSet := range nsPvtRWSet.CollectionPvtRwset {
+			var keyHashes [][]byte
+			for _, purge := range purges {
+				if purge.Namespace == nsPvtRWSet.Namespace && purge.Collection == collPvtRWSet.CollectionName {
+					keyHashes = append(keyHashes, purge.KeyHash)
+				}
+			}
+			if len(keyHashes) == 0 {
+				continue
+			}
+			isPurged := func(key string) bool {
+				keyHash := ledgerutil.ComputeStringHash(key)
+				for _, purgedKeyHash := range keyHashes {
+					if bytes.Equal(purgedKeyHash, keyHash) {
+						return true
+					}
+				}
+				return false
+			}
+
+			kvRWSet := &kvrwset.KVRWSet{}
+			if err := proto.Unmarshal(collPvtRWSet.Rwset, kvRWSet); err != nil {
+				return false, err
+			}
+			collPurged := false
+			var writes []*kvrwset.KVWrite
+			for _, write := range kvRWSet.Writes {
+				if isPurged(write.Key) {
+					collPurged = true
+					continue
+				}
+				writes = append(writes, write)
+			}
+			var metadataWrites []*kvrwset.KVMetadataWrite
+			for _, metadataWrite := range kvRWSet.MetadataWrites {
+				if isPurged(metadataWrite.Key) {
+					collPurged = true
+					continue
+				}
+				metadataWrites = append(metadataWrites, metadataWrite)
+			}
+			if !collPurged {
+				continue
+			}
+			kvRWSet.Writes = writes
+			kvRWSet.MetadataWrites = metadataWrites
+			rwsetBytes, err := proto.Marshal(kvRWSet)
+			if err != nil {
+				return false, err
+			}
+			collPvtRWSet.Rwset = rwsetBytes
+			purged = true
+		}
+	}
+	return purged, nil
+}
@@ -19,6 +19,7 @@ import (
 	"justledger/core/ledger/util"
 	"justledger/protos/common"
 	"justledger/protos/ledger/rwset"
+	"justledger/protos/ledger/rwset/kvrwset"
 	"justledger/protos/transientstore"
 
 	"github.com/spf13/viper"
@@ -123,6 +124,36 @@ func TestTransientStorePersistAndRetrieve(t *testing.T) {
 	assert.Equal(endorsersResults, actualEndorsersResults)
 }
 
+func TestTransientStoreRemoveStore(t *testing.T) {
+	env := NewTestStoreEnv(t)
+	defer env.Cleanup()
+	assert := assert.New(t)
+	otherStore, err := env.TestStoreProvider.OpenStore("TestOtherStore")
+	assert.NoError(err)
+	samplePvtRWSetWithConfig := samplePvtDataWithConfigInfo(t)
+	for _, s := range []Store{env.TestStore, otherStore} {
+		assert.NoError(s.PersistWithConfig("txid-1", 10, samplePvtRWSetWithConfig))
+	}
+
+	assert.NoError(env.TestStoreProvider.RemoveStore("TestStore"))
+	countResults := func(s Store) int {
+		iter, err := s.GetTxPvtRWSetByTxid("txid-1", nil)
+		assert.NoError(err)
+		defer iter.Close()
+		count := 0
+		for {
+			result, err := iter.NextWithConfig()
+			assert.NoError(err)
+			if result == nil {
+				return count
+			}
+			count++
+		}
+	}
+	assert.Equal(0, countResults(env.TestStore))
+	assert.Equal(1, countResults(otherStore))
+}
+
 func TestTransientStorePersistAndRetrieveBothOldAndNewProto(t *testing.T) {
 	env := NewTestStoreEnv(t)
 	assert := assert.New(t)
@@ -457,6 +488,70 @@ func TestTransientStorePurgeByHeight(t *testing.T) {
 	env.Cleanup()
 }
 
+func TestTransientStorePurgeByKeyHashes(t *testing.T) {
+	env := NewTestStoreEnv(t)
+	assert := assert.New(t)
+
+	samplePvtRWSet := func() *rwset.TxPvtReadWriteSet {
+		kvRWSet := &kvrwset.KVRWSet{
+			Writes: []*kvrwset.KVWrite{
+				{Key: "key-1", Value: []byte("value-1")},
+				{Key: "key-2", Value: []byte("value-2")},
+			},
+		}
+		kvRWSetBytes, err := proto.Marshal(kvRWSet)
+		assert.NoError(err)
+		return &rwset.TxPvtReadWriteSet{
+			DataModel: rwset.TxReadWriteSet_KV,
+			NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+				{
+					Namespace: "ns-1",
+					CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{
+						{CollectionName: "coll-1", Rwset: kvRWSetBytes},
+						{CollectionName: "coll-2", Rwset: []byte("RandomBytes-PvtRWSet-ns1-coll2")},
+					},
+				},
+			},
+		}
+	}
+
+	// private write sets simulated before (txid-1 and txid-2) and after (txid-3) the purge in block 10
+	assert.NoError(env.TestStore.Persist("txid-1", 5, samplePvtRWSet()))
+	assert.NoError(env.TestStore.PersistWithConfig("txid-2", 10,
+		&transientstore.TxPvtReadWriteSetWithConfigInfo{PvtRwset: samplePvtRWSet()}))
+	assert.NoError(env.TestStore.PersistWithConfig("txid-3", 11,
+		&transientstore.TxPvtReadWriteSetWithConfigInfo{PvtRwset: samplePvtRWSet()}))
+
+	purges := []*ledger.PvtdataPurge{
+		{SeqInBlock: 0, Namespace: "ns-1", Collection: "coll-1", KeyHash: util.ComputeStringHash("key-1")},
+	}
+	assert.NoError(env.TestStore.PurgeByKeyHashes(10, purges))
+
+	retrieveKeys := func(txid string) []string {
+		iter, err := env.TestStore.GetTxPvtRWSetByTxid(txid, nil)
+		assert.NoError(err)
+		defer iter.Close()
+		result, err := iter.NextWithConfig()
+		assert.NoError(err)
+		assert.NotNil(result)
+		collPvtRWSets := result.PvtSimulationResultsWithConfig.PvtRwset.NsPvtRwset[0].CollectionPvtRwset
+		assert.Equal([]byte("RandomBytes-PvtRWSet-ns1-coll2"), collPvtRWSets[1].Rwset)
+		kvRWSet := &kvrwset.KVRWSet{}
+		assert.NoError(proto.Unmarshal(collPvtRWSets[0].Rwset, kvRWSet))
+		var keys []string
+		for _, write := range kvRWSet.Writes {
+			keys = append(keys, write.Key)
+		}
+		return keys
+	}
+	assert.Equal([]string{"key-2"}, retrieveKeys("txid-1"))
+	assert.Equal([]string{"key-2"}, retrieveKeys("txid-2"))
+	assert.Equal([]string{"key-1", "key-2"}, retrieveKeys("txid-3"))
+
+	// purging nothing is a noop
+	assert.NoError(env.TestStore.PurgeByKeyHashes(10, nil))
+}
+
 func TestTransientStoreRetrievalWithFilter(t *testing.T) {
 	env := NewTestStoreEnv(t)
 	store := env.TestStore
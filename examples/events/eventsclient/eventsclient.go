@@ -30,6 +30,7 @@ import (
 	"justledger/protos/orderer"
 	"justledger/protos/peer"
 	"justledger/protos/utils"
+	"github.com/golang/protobuf/proto"
 	"github.com/spf13/viper"
 )
 
@@ -44,6 +45,10 @@ var (
 	filtered         bool
 	tlsEnabled       bool
 	mTlsEnabled      bool
+	chaincodeNames   string
+	eventNameRegex   string
+	validationCodes  string
+	txIDs            string
 
 	oldest  = &orderer.SeekPosition{Type: &orderer.SeekPosition_Oldest{Oldest: &orderer.SeekOldest{}}}
 	newest  = &orderer.SeekPosition{Type: &orderer.SeekPosition_Newest{Newest: &orderer.SeekNewest{}}}
@@ -88,17 +93,48 @@ func (r *eventsClient) seekSingle(blockNumber uint64) error {
 }
 
 func (r *eventsClient) seekHelper(start *orderer.SeekPosition, stop *orderer.SeekPosition) *common.Envelope {
-	env, err := utils.CreateSignedEnvelopeWithTLSBinding(common.HeaderType_DELIVER_SEEK_INFO, channelID, r.signer, &orderer.SeekInfo{
+	env, err := utils.CreateSignedEnvelopeWithTLSBindingAndExtension(common.HeaderType_DELIVER_SEEK_INFO, channelID, r.signer, &orderer.SeekInfo{
 		Start:    start,
 		Stop:     stop,
 		Behavior: orderer.SeekInfo_BLOCK_UNTIL_READY,
-	}, 0, 0, r.tlsCertHash)
+	}, 0, 0, r.tlsCertHash, deliverFilter())
 	if err != nil {
 		panic(err)
 	}
 	return env
 }
 
+// deliverFilter returns the marshaled deliver filter built from the
+// command line inputs, or nil if no filter has been requested
+func deliverFilter() []byte {
+	if !filtered {
+		return nil
+	}
+	filter := &peer.DeliverFilter{
+		ChaincodeNames: splitList(chaincodeNames),
+		EventNameRegex: eventNameRegex,
+		TxIds:          splitList(txIDs),
+	}
+	for _, code := range splitList(validationCodes) {
+		value, ok := peer.TxValidationCode_value[code]
+		if !ok {
+			panic(fmt.Sprintf("unknown validation code %s", code))
+		}
+		filter.ValidationCodes = append(filter.ValidationCodes, peer.TxValidationCode(value))
+	}
+	if proto.Equal(filter, &peer.DeliverFilter{}) {
+		return nil
+	}
+	return utils.MarshalOrPanic(filter)
+}
+
+func splitList(list string) []string {
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
+
 func (r *eventsClient) readEventsStream() {
 	for {
 		msg, err := r.client.Recv()
@@ -242,6 +278,10 @@ func readCLInputs() {
 	flag.StringVar(&clientKeyPath, "clientKey", "", "Specify path to the client TLS key")
 	flag.StringVar(&clientCertPath, "clientCert", "", "Specify path to the client TLS certificate")
 	flag.StringVar(&serverRootCAPath, "rootCert", "", "Specify path to the server root CA certificate")
+	flag.StringVar(&chaincodeNames, "chaincodes", "", "Comma separated names of the chaincodes whose transactions are delivered (filtered events only)")
+	flag.StringVar(&eventNameRegex, "eventRegex", "", "Regular expression the names of the delivered chaincode events match (filtered events only)")
+	flag.StringVar(&validationCodes, "validationCodes", "", "Comma separated validation codes of the delivered transactions, e.g. VALID (filtered events only)")
+	flag.StringVar(&txIDs, "txIDs", "", "Comma separated IDs of the delivered transactions (filtered events only)")
 	flag.IntVar(&seek, "seek", OLDEST, "Specify the range of requested blocks."+
 		"Acceptable values:"+
 		"-2 (or -1) to start from oldest (or newest) and keep at it indefinitely."+
@@ -63,6 +63,10 @@ type TransientStore interface {
 	// after successful block commit, PurgeByHeight() is still required to remove orphan entries (as
 	// transaction that gets endorsed may not be submitted by the client for commit)
 	PurgeByHeight(maxBlockNumToRetain uint64) error
+
+	// PurgeByKeyHashes removes the private data keys purged by the transactions of the given block
+	// from the private write sets that were persisted at a block height of blockNum or lower
+	PurgeByKeyHashes(blockNum uint64, purges []*ledger.PvtdataPurge) error
 }
 
 // Coordinator orchestrates the flow of the new
@@ -238,6 +242,14 @@ func (c *coordinator) StoreBlock(block *common.Block, privateDataSets util.PvtDa
 		}
 	}
 
+	if len(privateInfo.purges) > 0 {
+		// Remove the private data keys purged by the block from the private write sets
+		// of the transactions that were endorsed before the purge and are not committed yet
+		if err := c.PurgeByKeyHashes(block.Header.Number, privateInfo.purges); err != nil {
+			logger.Error("Purging private data keys of block", block.Header.Number, "from transient store failed:", err)
+		}
+	}
+
 	seq := block.Header.Number
 	if seq%c.transientBlockRetention == 0 && seq > c.transientBlockRetention {
 		err := c.PurgeByHeight(seq - c.transientBlockRetention)
@@ -622,6 +634,7 @@ type privateDataInfo struct {
 	missingKeys             rwsetKeys
 	txns                    txns
 	missingRWSButIneligible []rwSetKey
+	purges                  []*ledger.PvtdataPurge
 }
 
 // listMissingPrivateData identifies missing private write sets and attempts to retrieve them from local transient store
@@ -652,6 +665,7 @@ func (c *coordinator) listMissingPrivateData(block *common.Block, ownedRWsets ma
 		missingKeysByTxIDs: missing,
 		txns:               txList,
 		missingRWSButIneligible: bi.missingRWSButIneligible,
+		purges:                  bi.purges,
 	}
 
 	logger.Debug("Retrieving private write sets for", len(privateInfo.missingKeysByTxIDs), "transactions from transient store")
@@ -685,11 +699,13 @@ type transactionInspector struct {
 	sources                 map[rwSetKey][]*peer.Endorsement
 	ownedRWsets             map[rwSetKey][]byte
 	missingRWSButIneligible []rwSetKey
+	purges                  []*ledger.PvtdataPurge
 }
 
 func (bi *transactionInspector) inspectTransaction(seqInBlock uint64, chdr *common.ChannelHeader, txRWSet *rwsetutil.TxRwSet, endorsers []*peer.Endorsement) {
 	for _, ns := range txRWSet.NsRwSets {
 		for _, hashedCollection := range ns.CollHashedRwSets {
+			bi.collectPurges(seqInBlock, ns.NameSpace, hashedCollection)
 			if !containsWrites(chdr.TxId, ns.NameSpace, hashedCollection) {
 				continue
 			}
@@ -734,6 +750,21 @@ func (bi *transactionInspector) inspectTransaction(seqInBlock uint64, chdr *comm
 	} // for all RW sets
 }
 
+// collectPurges records the private data keys purged by the given hashed collection rwset
+func (bi *transactionInspector) collectPurges(seqInBlock uint64, namespace string, hashedCollection *rwsetutil.CollHashedRwSet) {
+	for _, hashedWrite := range hashedCollection.HashedRwSet.HashedWrites {
+		if !hashedWrite.IsPurge {
+			continue
+		}
+		bi.purges = append(bi.purges, &ledger.PvtdataPurge{
+			SeqInBlock: seqInBlock,
+			Namespace:  namespace,
+			Collection: hashedCollection.CollectionName,
+			KeyHash:    hashedWrite.KeyHash,
+		})
+	}
+}
+
 // accessPolicyForCollection retrieves a CollectionAccessPolicy for a given namespace, collection name
 // that corresponds to a given ChannelHeader
 func (c *coordinator) accessPolicyForCollection(chdr *common.ChannelHeader, namespace string, col string) privdata.CollectionAccessPolicy {
@@ -126,6 +126,10 @@ func (store *mockTransientStore) PurgeByHeight(maxBlockNumToRetain uint64) error
 	return store.Called(maxBlockNumToRetain).Error(0)
 }
 
+func (store *mockTransientStore) PurgeByKeyHashes(blockNum uint64, purges []*ledger.PvtdataPurge) error {
+	return store.Called(blockNum, purges).Error(0)
+}
+
 func (store *mockTransientStore) GetTxPvtRWSetByTxid(txid string, filter ledger.PvtNsCollFilter) (transientstore.RWSetScanner, error) {
 	store.lastReqTxID = txid
 	store.lastReqFilter = filter
@@ -1404,6 +1408,27 @@ func TestContainsWrites(t *testing.T) {
 	assert.True(t, containsWrites("tx", "ns", col))
 }
 
+func TestCollectPurges(t *testing.T) {
+	bi := &transactionInspector{}
+	col := &rwsetutil.CollHashedRwSet{
+		CollectionName: "col1",
+		HashedRwSet: &kvrwset.HashedRWSet{
+			HashedWrites: []*kvrwset.KVWriteHash{
+				{KeyHash: []byte("key1")},
+				{KeyHash: []byte("key2"), IsDelete: true, IsPurge: true},
+			},
+		},
+	}
+	bi.collectPurges(3, "ns", col)
+	assert.Equal(t, []*ledger.PvtdataPurge{
+		{SeqInBlock: 3, Namespace: "ns", Collection: "col1", KeyHash: []byte("key2")},
+	}, bi.purges)
+
+	// A collection without hashed writes purges nothing
+	bi.collectPurges(4, "ns", &rwsetutil.CollHashedRwSet{CollectionName: "col2", HashedRwSet: &kvrwset.HashedRWSet{}})
+	assert.Len(t, bi.purges, 1)
+}
+
 func TestIgnoreReadOnlyColRWSets(t *testing.T) {
 	// Scenario: The transaction has some ColRWSets that have only reads and no writes,
 	// These should be ignored and not considered as missing private data that needs to be retrieved
@@ -48,6 +48,8 @@ type GossipService interface {
 	NewConfigEventer() ConfigProcessor
 	// InitializeChannel allocates the state provider and should be invoked once per channel per execution
 	InitializeChannel(chainID string, endpoints []string, support Support)
+	// StopChannel stops the services allocated for the given channel by InitializeChannel and leaves the channel
+	StopChannel(chainID string)
 	// AddPayload appends message payload to for given chain
 	AddPayload(chainID string, payload *gproto.Payload) error
 }
@@ -346,7 +348,40 @@ func (g *gossipServiceImpl) updateEndpoints(chainID string, endpoints []string)
 func (g *gossipServiceImpl) AddPayload(chainID string, payload *gproto.Payload) error {
 	g.lock.RLock()
 	defer g.lock.RUnlock()
-	return g.chains[chainID].AddPayload(payload)
+	sp, exists := g.chains[chainID]
+	if !exists {
+		return errors.Errorf("channel %s is not initialized", chainID)
+	}
+	return sp.AddPayload(payload)
+}
+
+// StopChannel stops the leader election, the delivery service, the state provider and the private data
+// handlers of the given channel and makes the gossip component leave the channel
+func (g *gossipServiceImpl) StopChannel(chainID string) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	logger.Info("Stopping chain", chainID)
+	if le, exists := g.leaderElection[chainID]; exists {
+		logger.Infof("Stopping leader election for %s", chainID)
+		le.Stop()
+		delete(g.leaderElection, chainID)
+	}
+	if ds, exists := g.deliveryService[chainID]; exists {
+		if ds != nil {
+			ds.Stop()
+		}
+		delete(g.deliveryService, chainID)
+	}
+	if sp, exists := g.chains[chainID]; exists {
+		sp.Stop()
+		delete(g.chains, chainID)
+	}
+	if ph, exists := g.privateHandlers[chainID]; exists {
+		ph.close()
+		delete(g.privateHandlers, chainID)
+	}
+	g.LeaveChan(gossipCommon.ChainID(chainID))
 }
 
 // Stop stops the gossip component
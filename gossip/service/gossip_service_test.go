@@ -51,6 +51,10 @@ func (*mockTransientStore) PurgeByHeight(maxBlockNumToRetain uint64) error {
 	return nil
 }
 
+func (*mockTransientStore) PurgeByKeyHashes(blockNum uint64, purges []*ledger.PvtdataPurge) error {
+	return nil
+}
+
 func (*mockTransientStore) Persist(txid string, blockHeight uint64, privateSimulationResults *rwset.TxPvtReadWriteSet) error {
 	panic("implement me")
 }
@@ -269,6 +273,48 @@ func TestWithStaticDeliverClientNotLeader(t *testing.T) {
 	stopPeers(gossips)
 }
 
+func TestStopChannel(t *testing.T) {
+	viper.Set("peer.gossip.useLeaderElection", false)
+	viper.Set("peer.gossip.orgLeader", true)
+
+	n := 2
+	gossips := startPeers(t, n, 20600)
+	defer stopPeers(gossips)
+
+	deliverServiceFactory := &mockDeliverServiceFactory{
+		service: &mockDeliverService{
+			running: make(map[string]bool),
+		},
+	}
+	channels := []string{"chanA", "chanB"}
+	for _, channelName := range channels {
+		peerIndexes := make([]int, n)
+		for i := 0; i < n; i++ {
+			peerIndexes[i] = i
+		}
+		addPeersToChannel(t, n, 20600, channelName, gossips, peerIndexes)
+		for i := 0; i < n; i++ {
+			gossips[i].(*gossipServiceImpl).deliveryFactory = deliverServiceFactory
+			gossips[i].InitializeChannel(channelName, []string{"localhost:5005"}, Support{
+				Committer: &mockLedgerInfo{1},
+				Store:     &mockTransientStore{},
+			})
+		}
+	}
+
+	g := gossips[0].(*gossipServiceImpl)
+	g.StopChannel("chanA")
+	assert.NotContains(t, g.chains, "chanA")
+	assert.NotContains(t, g.privateHandlers, "chanA")
+	assert.NotContains(t, g.deliveryService, "chanA")
+	assert.Contains(t, g.chains, "chanB")
+	assert.Contains(t, g.privateHandlers, "chanB")
+	assert.Contains(t, g.deliveryService, "chanB")
+	assert.EqualError(t, g.AddPayload("chanA", nil), "channel chanA is not initialized")
+	// stopping a channel that is not initialized is a no-op
+	g.StopChannel("chanC")
+}
+
 func TestWithStaticDeliverClientBothStaticAndLeaderElection(t *testing.T) {
 	viper.Set("peer.gossip.useLeaderElection", true)
 	viper.Set("peer.gossip.orgLeader", true)
@@ -30,6 +30,10 @@ func (*transientStoreMock) PurgeByHeight(maxBlockNumToRetain uint64) error {
 	return nil
 }
 
+func (*transientStoreMock) PurgeByKeyHashes(blockNum uint64, purges []*ledger.PvtdataPurge) error {
+	return nil
+}
+
 func (*transientStoreMock) Persist(txid string, blockHeight uint64, privateSimulationResults *rwset.TxPvtReadWriteSet) error {
 	panic("implement me")
 }
@@ -32,7 +32,7 @@ func (m *ECP) Reset()         { *m = ECP{} }
 func (m *ECP) String() string { return proto.CompactTextString(m) }
 func (*ECP) ProtoMessage()    {}
 func (*ECP) Descriptor() ([]byte, []int) {
-	return fileDescriptor_idemix_ea623f6980eee47e, []int{0}
+	return fileDescriptor_idemix_9a596304d7177b75, []int{0}
 }
 func (m *ECP) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ECP.Unmarshal(m, b)
@@ -82,7 +82,7 @@ func (m *ECP2) Reset()         { *m = ECP2{} }
 func (m *ECP2) String() string { return proto.CompactTextString(m) }
 func (*ECP2) ProtoMessage()    {}
 func (*ECP2) Descriptor() ([]byte, []int) {
-	return fileDescriptor_idemix_ea623f6980eee47e, []int{1}
+	return fileDescriptor_idemix_9a596304d7177b75, []int{1}
 }
 func (m *ECP2) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ECP2.Unmarshal(m, b)
@@ -155,7 +155,7 @@ func (m *IssuerPublicKey) Reset()         { *m = IssuerPublicKey{} }
 func (m *IssuerPublicKey) String() string { return proto.CompactTextString(m) }
 func (*IssuerPublicKey) ProtoMessage()    {}
 func (*IssuerPublicKey) Descriptor() ([]byte, []int) {
-	return fileDescriptor_idemix_ea623f6980eee47e, []int{2}
+	return fileDescriptor_idemix_9a596304d7177b75, []int{2}
 }
 func (m *IssuerPublicKey) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_IssuerPublicKey.Unmarshal(m, b)
@@ -260,7 +260,7 @@ func (m *IssuerKey) Reset()         { *m = IssuerKey{} }
 func (m *IssuerKey) String() string { return proto.CompactTextString(m) }
 func (*IssuerKey) ProtoMessage()    {}
 func (*IssuerKey) Descriptor() ([]byte, []int) {
-	return fileDescriptor_idemix_ea623f6980eee47e, []int{3}
+	return fileDescriptor_idemix_9a596304d7177b75, []int{3}
 }
 func (m *IssuerKey) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_IssuerKey.Unmarshal(m, b)
@@ -312,7 +312,7 @@ func (m *Credential) Reset()         { *m = Credential{} }
 func (m *Credential) String() string { return proto.CompactTextString(m) }
 func (*Credential) ProtoMessage()    {}
 func (*Credential) Descriptor() ([]byte, []int) {
-	return fileDescriptor_idemix_ea623f6980eee47e, []int{4}
+	return fileDescriptor_idemix_9a596304d7177b75, []int{4}
 }
 func (m *Credential) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Credential.Unmarshal(m, b)
@@ -386,7 +386,7 @@ func (m *CredRequest) Reset()         { *m = CredRequest{} }
 func (m *CredRequest) String() string { return proto.CompactTextString(m) }
 func (*CredRequest) ProtoMessage()    {}
 func (*CredRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_idemix_ea623f6980eee47e, []int{5}
+	return fileDescriptor_idemix_9a596304d7177b75, []int{5}
 }
 func (m *CredRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CredRequest.Unmarshal(m, b)
@@ -467,7 +467,7 @@ func (m *Signature) Reset()         { *m = Signature{} }
 func (m *Signature) String() string { return proto.CompactTextString(m) }
 func (*Signature) ProtoMessage()    {}
 func (*Signature) Descriptor() ([]byte, []int) {
-	return fileDescriptor_idemix_ea623f6980eee47e, []int{6}
+	return fileDescriptor_idemix_9a596304d7177b75, []int{6}
 }
 func (m *Signature) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Signature.Unmarshal(m, b)
@@ -619,7 +619,7 @@ func (m *NonRevocationProof) Reset()         { *m = NonRevocationProof{} }
 func (m *NonRevocationProof) String() string { return proto.CompactTextString(m) }
 func (*NonRevocationProof) ProtoMessage()    {}
 func (*NonRevocationProof) Descriptor() ([]byte, []int) {
-	return fileDescriptor_idemix_ea623f6980eee47e, []int{7}
+	return fileDescriptor_idemix_9a596304d7177b75, []int{7}
 }
 func (m *NonRevocationProof) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NonRevocationProof.Unmarshal(m, b)
@@ -676,7 +676,7 @@ func (m *NymSignature) Reset()         { *m = NymSignature{} }
 func (m *NymSignature) String() string { return proto.CompactTextString(m) }
 func (*NymSignature) ProtoMessage()    {}
 func (*NymSignature) Descriptor() ([]byte, []int) {
-	return fileDescriptor_idemix_ea623f6980eee47e, []int{8}
+	return fileDescriptor_idemix_9a596304d7177b75, []int{8}
 }
 func (m *NymSignature) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NymSignature.Unmarshal(m, b)
@@ -744,7 +744,7 @@ func (m *CredentialRevocationInformation) Reset()         { *m = CredentialRevoc
 func (m *CredentialRevocationInformation) String() string { return proto.CompactTextString(m) }
 func (*CredentialRevocationInformation) ProtoMessage()    {}
 func (*CredentialRevocationInformation) Descriptor() ([]byte, []int) {
-	return fileDescriptor_idemix_ea623f6980eee47e, []int{9}
+	return fileDescriptor_idemix_9a596304d7177b75, []int{9}
 }
 func (m *CredentialRevocationInformation) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CredentialRevocationInformation.Unmarshal(m, b)
@@ -799,6 +799,152 @@ func (m *CredentialRevocationInformation) GetRevocationData() []byte {
 	return nil
 }
 
+// RevocationHandleSignature is a weak Boneh-Boyen signature on a revocation handle
+type RevocationHandleSignature struct {
+	RevocationHandle     []byte   `protobuf:"bytes,1,opt,name=revocation_handle,json=revocationHandle,proto3" json:"revocation_handle,omitempty"`
+	Signature            *ECP     `protobuf:"bytes,2,opt,name=signature" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevocationHandleSignature) Reset()         { *m = RevocationHandleSignature{} }
+func (m *RevocationHandleSignature) String() string { return proto.CompactTextString(m) }
+func (*RevocationHandleSignature) ProtoMessage()    {}
+func (*RevocationHandleSignature) Descriptor() ([]byte, []int) {
+	return fileDescriptor_idemix_9a596304d7177b75, []int{10}
+}
+func (m *RevocationHandleSignature) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevocationHandleSignature.Unmarshal(m, b)
+}
+func (m *RevocationHandleSignature) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevocationHandleSignature.Marshal(b, m, deterministic)
+}
+func (dst *RevocationHandleSignature) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevocationHandleSignature.Merge(dst, src)
+}
+func (m *RevocationHandleSignature) XXX_Size() int {
+	return xxx_messageInfo_RevocationHandleSignature.Size(m)
+}
+func (m *RevocationHandleSignature) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevocationHandleSignature.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevocationHandleSignature proto.InternalMessageInfo
+
+func (m *RevocationHandleSignature) GetRevocationHandle() []byte {
+	if m != nil {
+		return m.RevocationHandle
+	}
+	return nil
+}
+
+func (m *RevocationHandleSignature) GetSignature() *ECP {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+// PlainSigRevocationData is the revocation data of a CredentialRevocationInformation that uses
+// the plain signature revocation algorithm: it contains a signature under the epoch key on the
+// revocation handle of every credential that is not revoked in the epoch
+type PlainSigRevocationData struct {
+	Signatures           []*RevocationHandleSignature `protobuf:"bytes,1,rep,name=signatures" json:"signatures,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                     `json:"-"`
+	XXX_unrecognized     []byte                       `json:"-"`
+	XXX_sizecache        int32                        `json:"-"`
+}
+
+func (m *PlainSigRevocationData) Reset()         { *m = PlainSigRevocationData{} }
+func (m *PlainSigRevocationData) String() string { return proto.CompactTextString(m) }
+func (*PlainSigRevocationData) ProtoMessage()    {}
+func (*PlainSigRevocationData) Descriptor() ([]byte, []int) {
+	return fileDescriptor_idemix_9a596304d7177b75, []int{11}
+}
+func (m *PlainSigRevocationData) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PlainSigRevocationData.Unmarshal(m, b)
+}
+func (m *PlainSigRevocationData) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PlainSigRevocationData.Marshal(b, m, deterministic)
+}
+func (dst *PlainSigRevocationData) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PlainSigRevocationData.Merge(dst, src)
+}
+func (m *PlainSigRevocationData) XXX_Size() int {
+	return xxx_messageInfo_PlainSigRevocationData.Size(m)
+}
+func (m *PlainSigRevocationData) XXX_DiscardUnknown() {
+	xxx_messageInfo_PlainSigRevocationData.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PlainSigRevocationData proto.InternalMessageInfo
+
+func (m *PlainSigRevocationData) GetSignatures() []*RevocationHandleSignature {
+	if m != nil {
+		return m.Signatures
+	}
+	return nil
+}
+
+// PlainSigNonRevocationProof is the non-revocation proof of the plain signature revocation algorithm.
+// It proves knowledge of a signature under the epoch key on the (hidden) revocation handle
+// sigma_prime is a randomized signature and sigma_bar is sigma_prime raised to the epoch secret key
+// proof_s_r is the s-value proving knowledge of the randomness
+type PlainSigNonRevocationProof struct {
+	SigmaPrime           *ECP     `protobuf:"bytes,1,opt,name=sigma_prime,json=sigmaPrime" json:"sigma_prime,omitempty"`
+	SigmaBar             *ECP     `protobuf:"bytes,2,opt,name=sigma_bar,json=sigmaBar" json:"sigma_bar,omitempty"`
+	ProofSR              []byte   `protobuf:"bytes,3,opt,name=proof_s_r,json=proofSR,proto3" json:"proof_s_r,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PlainSigNonRevocationProof) Reset()         { *m = PlainSigNonRevocationProof{} }
+func (m *PlainSigNonRevocationProof) String() string { return proto.CompactTextString(m) }
+func (*PlainSigNonRevocationProof) ProtoMessage()    {}
+func (*PlainSigNonRevocationProof) Descriptor() ([]byte, []int) {
+	return fileDescriptor_idemix_9a596304d7177b75, []int{12}
+}
+func (m *PlainSigNonRevocationProof) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PlainSigNonRevocationProof.Unmarshal(m, b)
+}
+func (m *PlainSigNonRevocationProof) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PlainSigNonRevocationProof.Marshal(b, m, deterministic)
+}
+func (dst *PlainSigNonRevocationProof) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PlainSigNonRevocationProof.Merge(dst, src)
+}
+func (m *PlainSigNonRevocationProof) XXX_Size() int {
+	return xxx_messageInfo_PlainSigNonRevocationProof.Size(m)
+}
+func (m *PlainSigNonRevocationProof) XXX_DiscardUnknown() {
+	xxx_messageInfo_PlainSigNonRevocationProof.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PlainSigNonRevocationProof proto.InternalMessageInfo
+
+func (m *PlainSigNonRevocationProof) GetSigmaPrime() *ECP {
+	if m != nil {
+		return m.SigmaPrime
+	}
+	return nil
+}
+
+func (m *PlainSigNonRevocationProof) GetSigmaBar() *ECP {
+	if m != nil {
+		return m.SigmaBar
+	}
+	return nil
+}
+
+func (m *PlainSigNonRevocationProof) GetProofSR() []byte {
+	if m != nil {
+		return m.ProofSR
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ECP)(nil), "ECP")
 	proto.RegisterType((*ECP2)(nil), "ECP2")
@@ -810,61 +956,71 @@ func init() {
 	proto.RegisterType((*NonRevocationProof)(nil), "NonRevocationProof")
 	proto.RegisterType((*NymSignature)(nil), "NymSignature")
 	proto.RegisterType((*CredentialRevocationInformation)(nil), "CredentialRevocationInformation")
-}
-
-func init() { proto.RegisterFile("idemix/idemix.proto", fileDescriptor_idemix_ea623f6980eee47e) }
-
-var fileDescriptor_idemix_ea623f6980eee47e = []byte{
-	// 816 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x55, 0xdd, 0x6e, 0xe2, 0x46,
-	0x14, 0xd6, 0x60, 0x9b, 0x84, 0x83, 0x13, 0xb2, 0x93, 0xa8, 0x3b, 0xfd, 0x53, 0x59, 0xab, 0xdb,
-	0x8d, 0x7a, 0x41, 0xba, 0x44, 0x7d, 0x80, 0x2c, 0xa5, 0xd5, 0xaa, 0x12, 0x42, 0xe6, 0xae, 0x37,
-	0xa3, 0x19, 0x18, 0xb0, 0x05, 0xb6, 0xe9, 0xd8, 0x74, 0x71, 0x2f, 0xfa, 0x34, 0x7d, 0x9b, 0x5e,
-	0xf4, 0x95, 0xaa, 0xf9, 0x01, 0x0f, 0x61, 0xb7, 0x57, 0xf1, 0x39, 0xdf, 0xf9, 0xe3, 0xfb, 0x3e,
-	0xc7, 0x70, 0x9b, 0x2e, 0x44, 0x96, 0xee, 0x1f, 0xcc, 0x9f, 0xc1, 0x56, 0x16, 0x55, 0x11, 0xbd,
-	0x02, 0x6f, 0x3c, 0x9a, 0xe2, 0x10, 0xd0, 0x9e, 0xa0, 0x3e, 0xba, 0x0f, 0x63, 0xb4, 0x57, 0x51,
-	0x4d, 0x5a, 0x26, 0xaa, 0xa3, 0x9f, 0xc1, 0x1f, 0x8f, 0xa6, 0x43, 0x7c, 0x0d, 0xad, 0x3d, 0xb3,
-	0x45, 0xad, 0x3d, 0xd3, 0x31, 0xb7, 0x65, 0xad, 0x3d, 0x57, 0x71, 0xcd, 0x88, 0x67, 0xe2, 0x5a,
-	0xe3, 0x35, 0x27, 0xbe, 0x8d, 0x79, 0xf4, 0x77, 0x0b, 0x7a, 0xef, 0xcb, 0x72, 0x27, 0xe4, 0x74,
-	0xc7, 0x37, 0xe9, 0xfc, 0x57, 0x51, 0xe3, 0x37, 0xd0, 0x63, 0x55, 0x25, 0x53, 0xbe, 0xab, 0x04,
-	0xcd, 0x59, 0x26, 0x4a, 0x82, 0xfa, 0xde, 0x7d, 0x27, 0xbe, 0x3e, 0xa6, 0x27, 0x2a, 0x8b, 0x5f,
-	0x82, 0x9f, 0xd0, 0x72, 0xad, 0xd7, 0x75, 0x87, 0xfe, 0x60, 0x3c, 0x9a, 0xc6, 0x5e, 0x32, 0x5b,
-	0xe3, 0x2f, 0xa1, 0x9d, 0x50, 0xc9, 0xf2, 0x85, 0xde, 0x7c, 0x80, 0x82, 0x24, 0x66, 0xf9, 0x02,
-	0x7f, 0x0d, 0x17, 0x09, 0x55, 0x93, 0x4a, 0xe2, 0xf7, 0xbd, 0x23, 0xda, 0x4e, 0x9e, 0x54, 0x0e,
-	0xdf, 0x02, 0xfa, 0x40, 0x02, 0xdd, 0x16, 0x28, 0x60, 0x18, 0xa3, 0x0f, 0x6a, 0x20, 0x67, 0x92,
-	0xae, 0xde, 0x92, 0xb6, 0x3b, 0x90, 0x33, 0xf9, 0xcb, 0xdb, 0x23, 0x38, 0x24, 0x17, 0xcf, 0xc1,
-	0x21, 0x7e, 0x09, 0x17, 0x5b, 0x59, 0x14, 0x4b, 0x3a, 0x27, 0x97, 0xfa, 0x57, 0xb7, 0x75, 0x38,
-	0x6a, 0x80, 0x92, 0x74, 0x1c, 0x60, 0x86, 0x31, 0xf8, 0x09, 0x2b, 0x13, 0x02, 0x3a, 0xab, 0x9f,
-	0xa3, 0x27, 0xe8, 0x18, 0x96, 0x14, 0x3f, 0x37, 0xe0, 0xa5, 0xe5, 0xda, 0x92, 0xae, 0x1e, 0x71,
-	0x04, 0x5e, 0xba, 0x3d, 0xf0, 0x70, 0x33, 0x78, 0x46, 0x68, 0xac, 0xc0, 0x68, 0x09, 0x30, 0x92,
-	0x62, 0x21, 0xf2, 0x2a, 0x65, 0x1b, 0x8c, 0x01, 0x19, 0xd9, 0x0e, 0xe7, 0x22, 0xa6, 0x72, 0xfc,
-	0x84, 0x4b, 0xc4, 0x95, 0xea, 0xc2, 0xca, 0x87, 0x84, 0x8a, 0x4a, 0x2b, 0x1e, 0x2a, 0xf1, 0x1d,
-	0x04, 0x86, 0xc6, 0xa0, 0xef, 0xdd, 0x87, 0xb1, 0x09, 0xa2, 0x3f, 0xa1, 0xab, 0xf6, 0xc4, 0xe2,
-	0xf7, 0x9d, 0x28, 0x2b, 0xfc, 0x19, 0x78, 0x79, 0x9d, 0x9d, 0xac, 0x52, 0x09, 0xfc, 0x0a, 0xc2,
-	0x54, 0x9f, 0x49, 0xf3, 0x22, 0x9f, 0x0b, 0x6b, 0x99, 0xae, 0xc9, 0x4d, 0x54, 0xca, 0xa5, 0xce,
-	0xfb, 0x14, 0x75, 0xbe, 0x4b, 0x5d, 0xf4, 0xaf, 0x0f, 0x9d, 0x59, 0xba, 0xca, 0x59, 0xb5, 0x93,
-	0x42, 0x09, 0xcd, 0xe8, 0x56, 0xa6, 0x99, 0x38, 0x59, 0xdf, 0x66, 0x53, 0x95, 0xc3, 0x9f, 0x43,
-	0xc0, 0x28, 0x67, 0xf2, 0xe4, 0x27, 0xfb, 0xec, 0x1d, 0x93, 0xaa, 0x93, 0xdb, 0x4e, 0xd7, 0x40,
-	0x6d, 0x6e, 0x3a, 0x9d, 0xc3, 0xfc, 0x93, 0xc3, 0xbe, 0x02, 0xb0, 0x87, 0x29, 0x5b, 0x06, 0x1a,
-	0xbb, 0x34, 0xb7, 0xcd, 0xd6, 0xf8, 0x0b, 0xe8, 0x1c, 0x50, 0xa1, 0x7d, 0x14, 0xc6, 0x66, 0xce,
-	0x6c, 0xec, 0x76, 0x4a, 0xe3, 0xa3, 0x63, 0x67, 0x3c, 0x3c, 0x41, 0x1f, 0xad, 0x8f, 0x0e, 0xe8,
-	0x23, 0x7e, 0x0d, 0xbd, 0xe3, 0x56, 0x7b, 0xb5, 0x71, 0x54, 0x68, 0x57, 0x9b, 0xab, 0x23, 0xb8,
-	0x3a, 0x94, 0x19, 0xd9, 0x40, 0xcb, 0xd6, 0x35, 0x45, 0xc6, 0xfc, 0x77, 0x10, 0x18, 0x39, 0xba,
-	0x7a, 0x80, 0x09, 0x0e, 0x1a, 0x86, 0xe7, 0x1a, 0x1e, 0x27, 0x4a, 0xaa, 0x2a, 0xae, 0x74, 0x17,
-	0xd8, 0xcb, 0x26, 0x75, 0x86, 0x7f, 0x84, 0x5b, 0x29, 0xfe, 0x28, 0xe6, 0xac, 0x4a, 0x8b, 0x9c,
-	0x8a, 0x6d, 0x31, 0x4f, 0xe8, 0x76, 0x4d, 0xae, 0xdd, 0xf7, 0xeb, 0x45, 0x53, 0x31, 0x56, 0x05,
-	0xd3, 0x35, 0xfe, 0x1e, 0x9c, 0x24, 0xdd, 0xae, 0x69, 0x99, 0xae, 0x48, 0x4f, 0x4f, 0xef, 0x35,
-	0xc0, 0x74, 0x3d, 0x4b, 0x57, 0xea, 0x66, 0x3d, 0x97, 0xdc, 0xf4, 0xd1, 0xbd, 0x17, 0x9b, 0x00,
-	0x8f, 0xe1, 0x2e, 0x2f, 0x72, 0xea, 0x4e, 0x51, 0x57, 0x91, 0x17, 0x7a, 0xf3, 0xed, 0x60, 0x52,
-	0xe4, 0x71, 0x33, 0x48, 0x41, 0x31, 0xce, 0xcf, 0x72, 0x51, 0x06, 0xf8, 0xbc, 0x12, 0xbf, 0x86,
-	0x6b, 0x67, 0x30, 0xdb, 0xac, 0xb4, 0xc1, 0x82, 0xf8, 0xaa, 0xc9, 0x3e, 0x6d, 0x56, 0xf8, 0x87,
-	0x4f, 0xdc, 0x60, 0xbc, 0xfe, 0xb1, 0x75, 0x7f, 0x41, 0x38, 0xa9, 0xb3, 0xc6, 0xc2, 0x8e, 0xd3,
-	0xd0, 0xff, 0x38, 0xad, 0xf5, 0xcc, 0x69, 0x67, 0xc2, 0x78, 0x67, 0xc2, 0x1c, 0x95, 0xf6, 0x1d,
-	0xa5, 0xa3, 0x7f, 0x10, 0x7c, 0xd3, 0xfc, 0x97, 0x68, 0xae, 0x7b, 0x9f, 0x2f, 0x0b, 0x99, 0xe9,
-	0xc7, 0x86, 0x6f, 0xe4, 0xf2, 0xdd, 0x87, 0xcb, 0xa3, 0xba, 0x2d, 0x57, 0xdd, 0x0b, 0x61, 0x35,
-	0xed, 0x43, 0x78, 0xa8, 0xd0, 0x72, 0xda, 0x9b, 0x2c, 0xac, 0x94, 0x3c, 0xa7, 0xd5, 0xff, 0x18,
-	0xad, 0x6f, 0xc0, 0xf1, 0x00, 0x5d, 0xb0, 0x8a, 0xd9, 0x57, 0xcd, 0xe9, 0xfe, 0x89, 0x55, 0xec,
-	0xdd, 0x77, 0xbf, 0x7d, 0xbb, 0x4a, 0xab, 0x64, 0xc7, 0x07, 0xf3, 0x22, 0x7b, 0x48, 0xea, 0xad,
-	0x90, 0x1b, 0xb1, 0x58, 0x09, 0xf9, 0xb0, 0x64, 0x5c, 0xa6, 0x73, 0xfb, 0xd5, 0xe3, 0x6d, 0xfd,
-	0xd9, 0x7b, 0xfc, 0x2f, 0x00, 0x00, 0xff, 0xff, 0xaa, 0xcc, 0x9c, 0x10, 0x0d, 0x07, 0x00, 0x00,
+	proto.RegisterType((*RevocationHandleSignature)(nil), "RevocationHandleSignature")
+	proto.RegisterType((*PlainSigRevocationData)(nil), "PlainSigRevocationData")
+	proto.RegisterType((*PlainSigNonRevocationProof)(nil), "PlainSigNonRevocationProof")
+}
+
+func init() { proto.RegisterFile("idemix/idemix.proto", fileDescriptor_idemix_9a596304d7177b75) }
+
+var fileDescriptor_idemix_9a596304d7177b75 = []byte{
+	// 920 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x56, 0xcf, 0x6f, 0xe3, 0x44,
+	0x14, 0x96, 0x63, 0x27, 0x4d, 0x5e, 0xdc, 0xa6, 0x3b, 0xad, 0x76, 0x67, 0x0b, 0x88, 0xd4, 0x62,
+	0xd9, 0x0a, 0xa4, 0x94, 0x4d, 0xc5, 0x85, 0x5b, 0x37, 0x04, 0x58, 0x21, 0x45, 0x91, 0xc3, 0x89,
+	0x8b, 0x35, 0x4e, 0xa6, 0xf6, 0x28, 0xb1, 0x1d, 0xc6, 0x0e, 0x1b, 0x73, 0xe0, 0xc0, 0xdf, 0xc2,
+	0x7f, 0xc3, 0x81, 0x7f, 0x09, 0xcd, 0x8f, 0xd8, 0xe3, 0xa6, 0xe5, 0x54, 0xbf, 0xf7, 0xbd, 0xf7,
+	0xe6, 0xf3, 0xfb, 0x3e, 0x4f, 0x03, 0x17, 0x6c, 0x45, 0x13, 0xb6, 0xbf, 0x55, 0x7f, 0x46, 0x5b,
+	0x9e, 0x15, 0x99, 0x77, 0x0d, 0xf6, 0x74, 0x32, 0x47, 0x2e, 0x58, 0x7b, 0x6c, 0x0d, 0xad, 0x1b,
+	0xd7, 0xb7, 0xf6, 0x22, 0x2a, 0x71, 0x4b, 0x45, 0xa5, 0xf7, 0x03, 0x38, 0xd3, 0xc9, 0x7c, 0x8c,
+	0xce, 0xa0, 0xb5, 0x27, 0xba, 0xa8, 0xb5, 0x27, 0x32, 0x0e, 0x75, 0x59, 0x6b, 0x1f, 0x8a, 0xb8,
+	0x24, 0xd8, 0x56, 0x71, 0x29, 0xf1, 0x32, 0xc4, 0x8e, 0x8e, 0x43, 0xef, 0xef, 0x16, 0x0c, 0x3e,
+	0xe4, 0xf9, 0x8e, 0xf2, 0xf9, 0x2e, 0xdc, 0xb0, 0xe5, 0xcf, 0xb4, 0x44, 0x6f, 0x61, 0x40, 0x8a,
+	0x82, 0xb3, 0x70, 0x57, 0xd0, 0x20, 0x25, 0x09, 0xcd, 0xb1, 0x35, 0xb4, 0x6f, 0x7a, 0xfe, 0x59,
+	0x95, 0x9e, 0x89, 0x2c, 0x7a, 0x05, 0x4e, 0x1c, 0xe4, 0x6b, 0x79, 0x5c, 0x7f, 0xec, 0x8c, 0xa6,
+	0x93, 0xb9, 0x6f, 0xc7, 0x8b, 0x35, 0xfa, 0x04, 0x3a, 0x71, 0xc0, 0x49, 0xba, 0xc2, 0xb6, 0x01,
+	0xb5, 0x63, 0x9f, 0xa4, 0x2b, 0xf4, 0x19, 0x9c, 0xc4, 0x81, 0x98, 0x94, 0x63, 0x67, 0x68, 0x57,
+	0x68, 0x27, 0xbe, 0x17, 0x39, 0x74, 0x01, 0xd6, 0x47, 0xdc, 0x96, 0x6d, 0x6d, 0x01, 0x8c, 0x7d,
+	0xeb, 0xa3, 0x18, 0x18, 0x12, 0x1e, 0x44, 0xef, 0x70, 0xc7, 0x1c, 0x18, 0x12, 0xfe, 0xe3, 0xbb,
+	0x0a, 0x1c, 0xe3, 0x93, 0xc7, 0xe0, 0x18, 0xbd, 0x82, 0x93, 0x2d, 0xcf, 0xb2, 0x87, 0x60, 0x89,
+	0xbb, 0xf2, 0xad, 0x3b, 0x32, 0x9c, 0xd4, 0x40, 0x8e, 0x7b, 0x06, 0xb0, 0x40, 0x08, 0x9c, 0x98,
+	0xe4, 0x31, 0x06, 0x99, 0x95, 0xcf, 0xde, 0x3d, 0xf4, 0xd4, 0x96, 0xc4, 0x7e, 0xce, 0xc1, 0x66,
+	0xf9, 0x5a, 0x2f, 0x5d, 0x3c, 0x22, 0x0f, 0x6c, 0xb6, 0x3d, 0xec, 0xe1, 0x7c, 0xf4, 0x68, 0xa1,
+	0xbe, 0x00, 0xbd, 0x07, 0x80, 0x09, 0xa7, 0x2b, 0x9a, 0x16, 0x8c, 0x6c, 0x10, 0x02, 0x4b, 0xc9,
+	0x76, 0xa0, 0x6b, 0x11, 0x91, 0x0b, 0x1b, 0xbb, 0xb4, 0x42, 0xa1, 0x3a, 0xd5, 0xf2, 0x59, 0x54,
+	0x44, 0xb9, 0x16, 0xcf, 0xca, 0xd1, 0x25, 0xb4, 0xd5, 0x1a, 0xdb, 0x43, 0xfb, 0xc6, 0xf5, 0x55,
+	0xe0, 0xfd, 0x01, 0x7d, 0x71, 0x8e, 0x4f, 0x7f, 0xdb, 0xd1, 0xbc, 0x40, 0x2f, 0xc1, 0x4e, 0xcb,
+	0xa4, 0x71, 0x94, 0x48, 0xa0, 0x6b, 0x70, 0x99, 0xa4, 0x19, 0xa4, 0x59, 0xba, 0xa4, 0xda, 0x32,
+	0x7d, 0x95, 0x9b, 0x89, 0x94, 0xb9, 0x3a, 0xfb, 0xb9, 0xd5, 0x39, 0xe6, 0xea, 0xbc, 0x7f, 0x1d,
+	0xe8, 0x2d, 0x58, 0x94, 0x92, 0x62, 0xc7, 0xa9, 0x10, 0x9a, 0x04, 0x5b, 0xce, 0x12, 0xda, 0x38,
+	0xbe, 0x43, 0xe6, 0x22, 0x87, 0x5e, 0x43, 0x9b, 0x04, 0x21, 0xe1, 0x8d, 0x57, 0x76, 0xc8, 0x7b,
+	0xc2, 0x45, 0x67, 0xa8, 0x3b, 0x4d, 0x03, 0x75, 0x42, 0xd5, 0x69, 0x10, 0x73, 0x1a, 0xc4, 0x3e,
+	0x05, 0xd0, 0xc4, 0x84, 0x2d, 0xdb, 0x12, 0xeb, 0x2a, 0x6e, 0x8b, 0x35, 0xba, 0x82, 0xde, 0x01,
+	0xa5, 0xd2, 0x47, 0xae, 0xaf, 0xe6, 0x2c, 0xa6, 0x66, 0x27, 0x57, 0x3e, 0xaa, 0x3a, 0xfd, 0x71,
+	0x03, 0xbd, 0xc3, 0xdd, 0x06, 0x7a, 0x87, 0xde, 0xc0, 0xa0, 0x3a, 0x55, 0xb3, 0x56, 0x8e, 0x72,
+	0xf5, 0xd1, 0x8a, 0xb5, 0x07, 0xa7, 0x87, 0x32, 0x25, 0x1b, 0x48, 0xd9, 0xfa, 0xaa, 0x48, 0x99,
+	0xff, 0x12, 0xda, 0x4a, 0x8e, 0xbe, 0x1c, 0xa0, 0x82, 0x83, 0x86, 0xee, 0xb1, 0x86, 0xd5, 0x44,
+	0x1e, 0x88, 0x8a, 0x53, 0xd9, 0x05, 0x9a, 0xd9, 0xac, 0x4c, 0xd0, 0xb7, 0x70, 0xc1, 0xe9, 0xef,
+	0xd9, 0x92, 0x14, 0x2c, 0x4b, 0x03, 0xba, 0xcd, 0x96, 0x71, 0xb0, 0x5d, 0xe3, 0x33, 0xf3, 0xfb,
+	0x7a, 0x51, 0x57, 0x4c, 0x45, 0xc1, 0x7c, 0x8d, 0xbe, 0x02, 0x23, 0x19, 0x6c, 0xd7, 0x41, 0xce,
+	0x22, 0x3c, 0x90, 0xd3, 0x07, 0x35, 0x30, 0x5f, 0x2f, 0x58, 0x24, 0x38, 0xcb, 0xb9, 0xf8, 0x7c,
+	0x68, 0xdd, 0xd8, 0xbe, 0x0a, 0xd0, 0x14, 0x2e, 0xd3, 0x2c, 0x0d, 0xcc, 0x29, 0x82, 0x15, 0x7e,
+	0x21, 0x4f, 0xbe, 0x18, 0xcd, 0xb2, 0xd4, 0xaf, 0x07, 0x09, 0xc8, 0x47, 0xe9, 0x51, 0xce, 0x4b,
+	0x00, 0x1d, 0x57, 0xa2, 0x37, 0x70, 0x66, 0x0c, 0x26, 0x9b, 0x48, 0x1a, 0xac, 0xed, 0x9f, 0xd6,
+	0xd9, 0xfb, 0x4d, 0x84, 0xbe, 0x79, 0x86, 0x83, 0xf2, 0xfa, 0x53, 0xc7, 0xfd, 0x09, 0xee, 0xac,
+	0x4c, 0x6a, 0x0b, 0x1b, 0x4e, 0xb3, 0xfe, 0xc7, 0x69, 0xad, 0x47, 0x4e, 0x3b, 0x12, 0xc6, 0x3e,
+	0x12, 0xa6, 0x52, 0xda, 0x31, 0x94, 0xf6, 0xfe, 0xb1, 0xe0, 0xf3, 0xfa, 0x96, 0xa8, 0xd9, 0x7d,
+	0x48, 0x1f, 0x32, 0x9e, 0xc8, 0xc7, 0x7a, 0xdf, 0x96, 0xb9, 0xef, 0x21, 0x74, 0x2b, 0x75, 0x5b,
+	0xa6, 0xba, 0x27, 0x54, 0x6b, 0x3a, 0x04, 0xf7, 0x50, 0x21, 0xe5, 0xd4, 0x9c, 0x34, 0x2c, 0x94,
+	0x3c, 0x5e, 0xab, 0xf3, 0xd4, 0x5a, 0xdf, 0x82, 0xe1, 0x81, 0x60, 0x45, 0x0a, 0xa2, 0x3f, 0x35,
+	0xa3, 0xfb, 0x7b, 0x52, 0x10, 0x6f, 0x03, 0xaf, 0xeb, 0x57, 0xf8, 0x89, 0xa4, 0xab, 0x0d, 0xad,
+	0x57, 0xfb, 0x75, 0xc3, 0x62, 0xb1, 0x44, 0xf5, 0x92, 0xcf, 0xf9, 0xa3, 0x2e, 0xe4, 0x41, 0x2f,
+	0x3f, 0x74, 0x36, 0xee, 0x8b, 0x3a, 0xed, 0xfd, 0x02, 0x2f, 0xe7, 0x1b, 0xc2, 0xd2, 0x05, 0x8b,
+	0xfc, 0x06, 0x0f, 0xf4, 0x1d, 0x40, 0x55, 0xa6, 0xfe, 0x97, 0xf5, 0xc7, 0x57, 0xa3, 0x67, 0xa9,
+	0xf9, 0x46, 0xb5, 0xf7, 0x97, 0x05, 0x57, 0x87, 0xb1, 0x4f, 0x3a, 0xb1, 0x9f, 0xb3, 0x28, 0x79,
+	0xea, 0x9e, 0x03, 0x09, 0xa8, 0x6f, 0xff, 0x5a, 0xf2, 0x4f, 0x8e, 0xef, 0xbb, 0xae, 0x4c, 0x8b,
+	0x3b, 0xcf, 0xb8, 0x9d, 0xb8, 0xd6, 0x46, 0xdf, 0x4e, 0xfe, 0xfb, 0x2f, 0x7f, 0xfd, 0x22, 0x62,
+	0x45, 0xbc, 0x0b, 0x47, 0xcb, 0x2c, 0xb9, 0x8d, 0xcb, 0x2d, 0xe5, 0x1b, 0xba, 0x8a, 0x28, 0xbf,
+	0x7d, 0x20, 0x21, 0x67, 0x4b, 0xfd, 0xf3, 0x21, 0xec, 0xc8, 0xdf, 0x0f, 0x77, 0xff, 0x0d, 0x00,
+	0x11, 0xc5, 0xba, 0xb4, 0x56, 0x08, 0x00, 0x00,
 }
@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-amcl/amcl/FP256BN"
 	"github.com/stretchr/testify/assert"
 )
@@ -152,6 +153,17 @@ func TestIdemix(t *testing.T) {
 	err = sig.Ver(disclosure, key.Ipk, msg, attrs, rhindex, &revocationKey.PublicKey, epoch)
 	assert.NoError(t, err)
 
+	// Without revocation, the revocation handle can be disclosed as well
+	disclosure = []byte{1, 1, 1, 1, 1}
+	sig, err = NewSignature(cred, sk, Nym, RandNym, key.Ipk, disclosure, msg, rhindex, cri, rng)
+	assert.NoError(t, err)
+	err = sig.Ver(disclosure, key.Ipk, msg, attrs, rhindex, &revocationKey.PublicKey, epoch)
+	assert.NoError(t, err)
+	wrongAttrs := append([]*FP256BN.BIG{}, attrs...)
+	wrongAttrs[rhindex] = FP256BN.NewBIGint(42)
+	err = sig.Ver(disclosure, key.Ipk, msg, wrongAttrs, rhindex, &revocationKey.PublicKey, epoch)
+	assert.Error(t, err, "a signature should not verify against a different revocation handle")
+
 	// Test NymSignatures
 	nymsig, err := NewNymSignature(sk, Nym, RandNym, key.Ipk, []byte("testing"), rng)
 	assert.NoError(t, err)
@@ -162,3 +174,96 @@ func TestIdemix(t *testing.T) {
 		return
 	}
 }
+
+func TestPlainSignatureRevocation(t *testing.T) {
+	rng, err := GetRand()
+	assert.NoError(t, err)
+
+	AttributeNames := []string{"Attr1", "Attr2", "Attr3", "Attr4", "Attr5"}
+	key, err := NewIssuerKey(AttributeNames, rng)
+	assert.NoError(t, err)
+
+	// issue a credential whose revocation handle (the last attribute) is 42
+	rhindex := 4
+	attrs := make([]*FP256BN.BIG, len(AttributeNames))
+	for i := range AttributeNames {
+		attrs[i] = FP256BN.NewBIGint(i)
+	}
+	attrs[rhindex] = FP256BN.NewBIGint(42)
+	sk := RandModOrder(rng)
+	ni := RandModOrder(rng)
+	cred, err := NewCredential(key, NewCredRequest(sk, ni, key.Ipk, rng), attrs, rng)
+	assert.NoError(t, err)
+
+	revocationKey, err := GenerateLongTermRevocationKey()
+	assert.NoError(t, err)
+
+	epoch := 3
+	unrevokedHandles := []*FP256BN.BIG{FP256BN.NewBIGint(7), FP256BN.NewBIGint(42)}
+	cri, err := CreateCRI(revocationKey, unrevokedHandles, epoch, ALG_PLAIN_SIGNATURE, rng)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyEpochPK(&revocationKey.PublicKey, cri.EpochPk, cri.EpochPkSig, epoch, ALG_PLAIN_SIGNATURE))
+
+	// the signatures on the unrevoked handles are valid under the epoch key
+	revocationData := &PlainSigRevocationData{}
+	assert.NoError(t, proto.Unmarshal(cri.RevocationData, revocationData))
+	assert.Len(t, revocationData.Signatures, 2)
+	for i, rhSig := range revocationData.Signatures {
+		assert.NoError(t, WBBVerify(Ecp2FromProto(cri.EpochPk), EcpFromProto(rhSig.Signature), unrevokedHandles[i]))
+	}
+
+	Nym, RandNym := MakeNym(sk, key.Ipk, rng)
+	msg := []byte{1, 2, 3, 4, 5}
+	disclosure := []byte{0, 1, 1, 1, 0}
+	sig, err := NewSignature(cred, sk, Nym, RandNym, key.Ipk, disclosure, msg, rhindex, cri, rng)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(ALG_PLAIN_SIGNATURE), sig.NonRevocationProof.RevocationAlg)
+	assert.NoError(t, sig.Ver(disclosure, key.Ipk, msg, attrs, rhindex, &revocationKey.PublicKey, epoch))
+
+	// the signature is not valid in another epoch
+	err = sig.Ver(disclosure, key.Ipk, msg, attrs, rhindex, &revocationKey.PublicKey, epoch+1)
+	assert.EqualError(t, err, "signature invalid: signature is for epoch 3 but the current epoch is 4")
+
+	// nor with an epoch key that is not signed by the revocation authority
+	otherRevocationKey, err := GenerateLongTermRevocationKey()
+	assert.NoError(t, err)
+	err = sig.Ver(disclosure, key.Ipk, msg, attrs, rhindex, &otherRevocationKey.PublicKey, epoch)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "revocation epoch key is invalid")
+
+	// the revocation handle must remain hidden
+	_, err = NewSignature(cred, sk, Nym, RandNym, key.Ipk, []byte{0, 1, 1, 1, 1}, msg, rhindex, cri, rng)
+	assert.Error(t, err)
+
+	// a tampered non-revocation proof is rejected
+	proofBytes := sig.NonRevocationProof.NonRevocationProof
+	tamperedProof := &PlainSigNonRevocationProof{}
+	assert.NoError(t, proto.Unmarshal(proofBytes, tamperedProof))
+	tamperedProof.ProofSR = BigToBytes(RandModOrder(rng))
+	sig.NonRevocationProof.NonRevocationProof, err = proto.Marshal(tamperedProof)
+	assert.NoError(t, err)
+	assert.Error(t, sig.Ver(disclosure, key.Ipk, msg, attrs, rhindex, &revocationKey.PublicKey, epoch))
+
+	tamperedProof.SigmaBar = EcpToProto(GenG1)
+	sig.NonRevocationProof.NonRevocationProof, err = proto.Marshal(tamperedProof)
+	assert.NoError(t, err)
+	err = sig.Ver(disclosure, key.Ipk, msg, attrs, rhindex, &revocationKey.PublicKey, epoch)
+	assert.EqualError(t, err, "non-revocation proof invalid: sigmaPrime and sigmaBar don't have the expected structure")
+	sig.NonRevocationProof.NonRevocationProof = proofBytes
+
+	// a revoked credential cannot produce a signature for the epoch
+	criRevoked, err := CreateCRI(revocationKey, []*FP256BN.BIG{FP256BN.NewBIGint(7)}, epoch+1, ALG_PLAIN_SIGNATURE, rng)
+	assert.NoError(t, err)
+	_, err = NewSignature(cred, sk, Nym, RandNym, key.Ipk, disclosure, msg, rhindex, criRevoked, rng)
+	assert.EqualError(t, err, "failed to compute non-revoked proof: the revocation handle is not in the unrevoked handles of epoch 4")
+
+	// and reusing the proof of the previous epoch with the new epoch key fails
+	sig.Epoch = criRevoked.Epoch
+	sig.RevocationEpochPk = criRevoked.EpochPk
+	sig.RevocationPkSig = criRevoked.EpochPkSig
+	assert.Error(t, sig.Ver(disclosure, key.Ipk, msg, attrs, rhindex, &revocationKey.PublicKey, epoch+1))
+
+	// unknown revocation algorithms are not supported
+	_, err = CreateCRI(revocationKey, unrevokedHandles, epoch, RevocationAlgorithm(42), rng)
+	assert.EqualError(t, err, "the specified revocation algorithm is not supported.")
+}
@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package idemix
 
 import (
+	"bytes"
+
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-amcl/amcl"
 	"github.com/hyperledger/fabric-amcl/amcl/FP256BN"
 	"github.com/pkg/errors"
@@ -27,10 +30,78 @@ func (prover *nopNonRevokedProver) getNonRevokedProof(chal *FP256BN.BIG) (*NonRe
 	return ret, nil
 }
 
+// plainSigNonRevokedProver proves knowledge of a weak Boneh-Boyen signature sigma, under the epoch key,
+// on the hidden revocation handle rh. The signature is randomized to sigma' = sigma^r, and
+// sigmaBar = sigma'^(-rh) * g1^r = sigma'^epochSk is computed, such that e(sigma', epochPk) = e(sigmaBar, g2).
+// The prover then proves knowledge of rh and r such that sigmaBar = sigma'^(-rh) * g1^r, where
+// the randomness used for rh is the one of the signature so that the s-value of rh links both proofs
+type plainSigNonRevokedProver struct {
+	sigmaPrime *FP256BN.ECP
+	sigmaBar   *FP256BN.ECP
+	r          *FP256BN.BIG
+	rR         *FP256BN.BIG
+}
+
+func (prover *plainSigNonRevokedProver) getFSContribution(rh *FP256BN.BIG, rRh *FP256BN.BIG, cri *CredentialRevocationInformation, rng *amcl.RAND) ([]byte, error) {
+	revocationData := &PlainSigRevocationData{}
+	err := proto.Unmarshal(cri.RevocationData, revocationData)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal revocation data")
+	}
+
+	rhBytes := BigToBytes(rh)
+	var sigma *FP256BN.ECP
+	for _, rhSig := range revocationData.Signatures {
+		if bytes.Equal(rhSig.RevocationHandle, rhBytes) && rhSig.Signature != nil {
+			sigma = EcpFromProto(rhSig.Signature)
+			break
+		}
+	}
+	if sigma == nil {
+		return nil, errors.Errorf("the revocation handle is not in the unrevoked handles of epoch %d", cri.Epoch)
+	}
+
+	prover.r = RandModOrder(rng)
+	prover.rR = RandModOrder(rng)
+
+	prover.sigmaPrime = FP256BN.G1mul(sigma, prover.r)
+	prover.sigmaBar = FP256BN.G1mul(GenG1, prover.r)
+	prover.sigmaBar.Sub(FP256BN.G1mul(prover.sigmaPrime, rh))
+
+	t := FP256BN.G1mul(GenG1, prover.rR)
+	t.Sub(FP256BN.G1mul(prover.sigmaPrime, rRh))
+
+	proofData := make([]byte, ProofBytes[ALG_PLAIN_SIGNATURE])
+	index := 0
+	index = appendBytesG1(proofData, index, prover.sigmaPrime)
+	index = appendBytesG1(proofData, index, prover.sigmaBar)
+	appendBytesG1(proofData, index, t)
+	return proofData, nil
+}
+
+func (prover *plainSigNonRevokedProver) getNonRevokedProof(chal *FP256BN.BIG) (*NonRevocationProof, error) {
+	proofSR := Modadd(prover.rR, FP256BN.Modmul(chal, prover.r, GroupOrder), GroupOrder)
+	proofBytes, err := proto.Marshal(&PlainSigNonRevocationProof{
+		SigmaPrime: EcpToProto(prover.sigmaPrime),
+		SigmaBar:   EcpToProto(prover.sigmaBar),
+		ProofSR:    BigToBytes(proofSR),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal non-revocation proof")
+	}
+
+	ret := &NonRevocationProof{}
+	ret.RevocationAlg = int32(ALG_PLAIN_SIGNATURE)
+	ret.NonRevocationProof = proofBytes
+	return ret, nil
+}
+
 func getNonRevocationProver(algorithm RevocationAlgorithm) (nonRevokedProver, error) {
 	switch algorithm {
 	case ALG_NO_REVOCATION:
 		return &nopNonRevokedProver{}, nil
+	case ALG_PLAIN_SIGNATURE:
+		return &plainSigNonRevokedProver{}, nil
 	default:
 		// unknown revocation algorithm
 		return nil, errors.Errorf("unknown revocation algorithm %d", algorithm)
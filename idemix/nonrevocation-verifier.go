@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package idemix
 
 import (
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-amcl/amcl/FP256BN"
 	"github.com/pkg/errors"
 )
@@ -20,10 +21,56 @@ func (verifier *nopNonRevocationVerifier) recomputeFSContribution(proof *NonRevo
 	return nil, nil
 }
 
+// plainSigNonRevocationVerifier verifies the proof of knowledge of a weak Boneh-Boyen signature,
+// under the epoch key, on the hidden revocation handle (see plainSigNonRevokedProver)
+type plainSigNonRevocationVerifier struct{}
+
+func (verifier *plainSigNonRevocationVerifier) recomputeFSContribution(proof *NonRevocationProof, chal *FP256BN.BIG, epochPK *FP256BN.ECP2, proofSRh *FP256BN.BIG) ([]byte, error) {
+	plainSigProof := &PlainSigNonRevocationProof{}
+	err := proto.Unmarshal(proof.NonRevocationProof, plainSigProof)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal non-revocation proof")
+	}
+	if plainSigProof.SigmaPrime == nil || plainSigProof.SigmaBar == nil || epochPK == nil {
+		return nil, errors.Errorf("non-revocation proof invalid: received nil input")
+	}
+
+	sigmaPrime := EcpFromProto(plainSigProof.SigmaPrime)
+	sigmaBar := EcpFromProto(plainSigProof.SigmaBar)
+	proofSR := FP256BN.FromBytes(plainSigProof.ProofSR)
+
+	if sigmaPrime.Is_infinity() {
+		return nil, errors.Errorf("non-revocation proof invalid: sigmaPrime = 1")
+	}
+
+	// check that e(sigma', epochPk) = e(sigmaBar, g2)
+	temp1 := FP256BN.Ate(epochPK, sigmaPrime)
+	temp2 := FP256BN.Ate(GenG2, sigmaBar)
+	temp2.Inverse()
+	temp1.Mul(temp2)
+	if !FP256BN.Fexp(temp1).Isunity() {
+		return nil, errors.Errorf("non-revocation proof invalid: sigmaPrime and sigmaBar don't have the expected structure")
+	}
+
+	// t = g1^{s_r} * sigma'^{-s_rh} * sigmaBar^{-c}
+	t := FP256BN.G1mul(GenG1, proofSR)
+	t.Sub(FP256BN.G1mul(sigmaPrime, proofSRh))
+	t.Sub(FP256BN.G1mul(sigmaBar, chal))
+
+	proofData := make([]byte, ProofBytes[ALG_PLAIN_SIGNATURE])
+	index := 0
+	index = appendBytesG1(proofData, index, sigmaPrime)
+	index = appendBytesG1(proofData, index, sigmaBar)
+	appendBytesG1(proofData, index, t)
+	return proofData, nil
+}
+
 func getNonRevocationVerifier(algorithm RevocationAlgorithm) (nonRevocationVerifier, error) {
 	switch algorithm {
 	case ALG_NO_REVOCATION:
 		return &nopNonRevocationVerifier{}, nil
+	case ALG_PLAIN_SIGNATURE:
+		return &plainSigNonRevocationVerifier{}, nil
 	default:
 		// unknown revocation algorithm
 		return nil, errors.Errorf("unknown revocation algorithm %d", algorithm)
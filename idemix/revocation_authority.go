@@ -25,10 +25,17 @@ type RevocationAlgorithm int32
 
 const (
 	ALG_NO_REVOCATION RevocationAlgorithm = iota
+	// ALG_PLAIN_SIGNATURE revokes credentials with a signed whitelist: in every epoch the revocation
+	// authority signs the revocation handles of the credentials that are not revoked with a fresh
+	// epoch key, and a signer proves in zero-knowledge that it knows a signature on its handle
+	ALG_PLAIN_SIGNATURE
 )
 
 var ProofBytes = map[RevocationAlgorithm]int{
 	ALG_NO_REVOCATION: 0,
+	// the randomized signature, the randomized signature to the power of the epoch
+	// secret key and the commitment of the zero-knowledge proof, all in G1
+	ALG_PLAIN_SIGNATURE: 3 * (2*FieldBytes + 1),
 }
 
 // GenerateLongTermRevocationKey generates a long term signing key that will be used for revocation
@@ -40,6 +47,7 @@ func GenerateLongTermRevocationKey() (*ecdsa.PrivateKey, error) {
 // Users can use the CRI to prove that they are not revoked.
 // Note that when not using revocation (i.e., alg = ALG_NO_REVOCATION), the entered unrevokedHandles are not used,
 // and the resulting CRI can be used by any signer.
+// When using ALG_PLAIN_SIGNATURE, the CRI can only be used by the signers whose revocation handle is in unrevokedHandles.
 func CreateCRI(key *ecdsa.PrivateKey, unrevokedHandles []*FP256BN.BIG, epoch int, alg RevocationAlgorithm, rng *amcl.RAND) (*CredentialRevocationInformation, error) {
 	if key == nil || rng == nil {
 		return nil, errors.Errorf("CreateCRI received nil input")
@@ -48,13 +56,18 @@ func CreateCRI(key *ecdsa.PrivateKey, unrevokedHandles []*FP256BN.BIG, epoch int
 	cri.RevocationAlg = int32(alg)
 	cri.Epoch = int64(epoch)
 
-	if alg == ALG_NO_REVOCATION {
+	var epochSk *FP256BN.BIG
+	switch alg {
+	case ALG_NO_REVOCATION:
 		// put a dummy PK in the proto
 		cri.EpochPk = Ecp2ToProto(GenG2)
-	} else {
+	case ALG_PLAIN_SIGNATURE:
 		// create epoch key
-		_, epochPk := WBBKeyGen(rng)
+		var epochPk *FP256BN.ECP2
+		epochSk, epochPk = WBBKeyGen(rng)
 		cri.EpochPk = Ecp2ToProto(epochPk)
+	default:
+		return nil, errors.Errorf("the specified revocation algorithm is not supported.")
 	}
 
 	// sign epoch + epoch key with long term key
@@ -70,11 +83,23 @@ func CreateCRI(key *ecdsa.PrivateKey, unrevokedHandles []*FP256BN.BIG, epoch int
 		return nil, err
 	}
 
-	if alg == ALG_NO_REVOCATION {
-		return cri, nil
-	} else {
-		return nil, errors.Errorf("the specified revocation algorithm is not supported.")
+	if alg == ALG_PLAIN_SIGNATURE {
+		// sign the unrevoked handles with the epoch key, whose
+		// validity is guaranteed by the signature of the long term key
+		revocationData := &PlainSigRevocationData{}
+		for _, rh := range unrevokedHandles {
+			revocationData.Signatures = append(revocationData.Signatures, &RevocationHandleSignature{
+				RevocationHandle: BigToBytes(rh),
+				Signature:        EcpToProto(WBBSign(epochSk, rh)),
+			})
+		}
+		cri.RevocationData, err = proto.Marshal(revocationData)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal revocation data")
+		}
 	}
+
+	return cri, nil
 }
 
 // VerifyEpochPK verifies that the revocation PK for a certain epoch is valid,
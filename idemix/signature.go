@@ -46,7 +46,8 @@ func hiddenIndices(Disclosure []byte) []int {
 // NewSignature creates a new idemix signature (Schnorr-type signature)
 // The []byte Disclosure steers which attributes are disclosed:
 // if Disclosure[i] == 0 then attribute i remains hidden and otherwise it is disclosed.
-// We require the revocation handle to remain undisclosed (i.e., Disclosure[rhIndex] == 0).
+// We require the revocation handle to remain undisclosed (i.e., Disclosure[rhIndex] == 0),
+// unless cri.RevocationAlg is ALG_NO_REVOCATION.
 // We use the zero-knowledge proof by http://eprint.iacr.org/2016/663.pdf to prove knowledge of a BBS+ signature
 func NewSignature(cred *Credential, sk *FP256BN.BIG, Nym *FP256BN.ECP, RNym *FP256BN.BIG, ipk *IssuerPublicKey, Disclosure []byte, msg []byte, rhIndex int, cri *CredentialRevocationInformation, rng *amcl.RAND) (*Signature, error) {
 	if cred == nil || sk == nil || Nym == nil || RNym == nil || ipk == nil || rng == nil || cri == nil {
@@ -105,7 +106,12 @@ func NewSignature(cred *Credential, sk *FP256BN.BIG, Nym *FP256BN.ECP, RNym *FP2
 	if err != nil {
 		return nil, err
 	}
-	nonRevokedProofHashData, err := prover.getFSContribution(FP256BN.FromBytes(cred.Attrs[rhIndex]), rAttrs[sort.SearchInts(HiddenIndices, rhIndex)], cri, rng)
+	// a disclosed revocation handle, only allowed without revocation, has no randomness
+	rRh := FP256BN.NewBIG()
+	if Disclosure[rhIndex] == 0 {
+		rRh = rAttrs[sort.SearchInts(HiddenIndices, rhIndex)]
+	}
+	nonRevokedProofHashData, err := prover.getFSContribution(FP256BN.FromBytes(cred.Attrs[rhIndex]), rRh, cri, rng)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to compute non-revoked proof")
 	}
@@ -196,6 +202,8 @@ func NewSignature(cred *Credential, sk *FP256BN.BIG, Nym *FP256BN.ECP, RNym *FP2
 // Disclosure steers which attributes it expects to be disclosed
 // attributeValues contains the desired attribute values.
 // This function will check that if attribute i is disclosed, the i-th attribute equals attributeValues[i].
+// It also checks that the signature was produced for the given epoch with an epoch key signed by the
+// revocation authority (revPk), such that credentials revoked in an earlier epoch are rejected.
 func (sig *Signature) Ver(Disclosure []byte, ipk *IssuerPublicKey, msg []byte, attributeValues []*FP256BN.BIG, rhIndex int, revPk *ecdsa.PublicKey, epoch int) error {
 	if ipk == nil || revPk == nil {
 		return errors.Errorf("cannot verify idemix signature: received nil input")
@@ -209,6 +217,14 @@ func (sig *Signature) Ver(Disclosure []byte, ipk *IssuerPublicKey, msg []byte, a
 		return errors.Errorf("Attribute %d is disclosed but is also used as revocation handle, which should remain hidden.", rhIndex)
 	}
 
+	if sig.Epoch != int64(epoch) {
+		return errors.Errorf("signature invalid: signature is for epoch %d but the current epoch is %d", sig.Epoch, epoch)
+	}
+	err := VerifyEpochPK(revPk, sig.RevocationEpochPk, sig.RevocationPkSig, epoch, RevocationAlgorithm(sig.NonRevocationProof.RevocationAlg))
+	if err != nil {
+		return errors.WithMessage(err, "signature invalid: revocation epoch key is invalid")
+	}
+
 	HiddenIndices := hiddenIndices(Disclosure)
 
 	APrime := EcpFromProto(sig.GetAPrime())
@@ -282,8 +298,10 @@ func (sig *Signature) Ver(Disclosure []byte, ipk *IssuerPublicKey, msg []byte, a
 		return err
 	}
 
-	i := sort.SearchInts(HiddenIndices, rhIndex)
-	proofSRh := ProofSAttrs[i]
+	proofSRh := FP256BN.NewBIG()
+	if Disclosure[rhIndex] == 0 {
+		proofSRh = ProofSAttrs[sort.SearchInts(HiddenIndices, rhIndex)]
+	}
 	nonRevokedProofBytes, err := nonRevokedVer.recomputeFSContribution(sig.NonRevocationProof, ProofC, Ecp2FromProto(sig.RevocationEpochPk), proofSRh)
 	if err != nil {
 		return err
@@ -134,3 +134,17 @@ func (c *cachedMSP) cleanCash() error {
 
 	return nil
 }
+
+// CheckRevocationOnline checks the revocation status of id online if the
+// underlying MSP supports it. The outcome is never cached here, as the
+// underlying MSP bounds how long it can be reused
+func (c *cachedMSP) CheckRevocationOnline(id msp.Identity) error {
+	checker, ok := c.MSP.(msp.OnlineRevocationChecker)
+	if !ok {
+		return nil
+	}
+	if cached, ok := id.(*cachedIdentity); ok {
+		id = cached.Identity
+	}
+	return checker.CheckRevocationOnline(id)
+}
@@ -213,6 +213,48 @@ func TestValidate(t *testing.T) {
 	assert.False(t, ok)
 }
 
+type revocationCheckingMSP struct {
+	*mocks.MockMSP
+	checked msp.Identity
+	err     error
+}
+
+func (m *revocationCheckingMSP) CheckRevocationOnline(id msp.Identity) error {
+	m.checked = id
+	return m.err
+}
+
+func TestCheckRevocationOnline(t *testing.T) {
+	mockIdentity := &mocks.MockIdentity{ID: "Alice"}
+	mockIdentity.On("GetIdentifier").Return(&msp.IdentityIdentifier{Mspid: "MSP", Id: "Alice"})
+	serializedIdentity := []byte{1, 2, 3}
+
+	// MSPs that do not check revocation online accept every identity
+	mockMSP := &mocks.MockMSP{}
+	i, err := New(mockMSP)
+	assert.NoError(t, err)
+	assert.NoError(t, i.(msp.OnlineRevocationChecker).CheckRevocationOnline(mockIdentity))
+
+	checkingMSP := &revocationCheckingMSP{MockMSP: &mocks.MockMSP{}, err: errors.New("revoked")}
+	checkingMSP.On("DeserializeIdentity", serializedIdentity).Return(mockIdentity, nil)
+	checkingMSP.On("Validate", mockIdentity).Return(nil)
+	i, err = New(checkingMSP)
+	assert.NoError(t, err)
+
+	// The identity is unwrapped and the outcome is not cached
+	id, err := i.DeserializeIdentity(serializedIdentity)
+	assert.NoError(t, err)
+	assert.EqualError(t, i.(msp.OnlineRevocationChecker).CheckRevocationOnline(id), "revoked")
+	assert.Equal(t, mockIdentity, checkingMSP.checked)
+	checkingMSP.err = nil
+	assert.NoError(t, i.(msp.OnlineRevocationChecker).CheckRevocationOnline(id))
+
+	// Validation results are still cached
+	assert.NoError(t, i.Validate(mockIdentity))
+	assert.NoError(t, i.Validate(mockIdentity))
+	checkingMSP.AssertNumberOfCalls(t, "Validate", 1)
+}
+
 func TestSatisfiesValidateIndirectCall(t *testing.T) {
 	mockMSP := &mocks.MockMSP{}
 
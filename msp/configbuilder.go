@@ -12,6 +12,8 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/golang/protobuf/proto"
 	"justledger/bccsp"
@@ -43,6 +45,24 @@ type NodeOUs struct {
 	PeerOUIdentifier *OrganizationalUnitIdentifiersConfiguration `yaml:"PeerOUIdentifier,omitempty"`
 }
 
+// OnlineRevocation configures the MSP to check the revocation status of
+// certificates against the OCSP responders and CRL distribution points
+// advertised by the certificates themselves, in addition to the CRLs in
+// the crls folder.
+type OnlineRevocation struct {
+	// OCSP enables querying the OCSP responders of the certificates
+	OCSP bool `yaml:"OCSP,omitempty"`
+	// CRLDistributionPoints enables fetching the CRLs of the certificates
+	CRLDistributionPoints bool `yaml:"CRLDistributionPoints,omitempty"`
+	// FailClosed rejects identities whose revocation status cannot be
+	// determined, instead of accepting them
+	FailClosed bool `yaml:"FailClosed,omitempty"`
+	// CacheDuration bounds how long a revocation status is cached, e.g. 10m
+	CacheDuration string `yaml:"CacheDuration,omitempty"`
+	// Timeout bounds each request to a responder or distribution point, e.g. 5s
+	Timeout string `yaml:"Timeout,omitempty"`
+}
+
 // Configuration represents the accessory configuration an MSP can be equipped with.
 // By default, this configuration is stored in a yaml file
 type Configuration struct {
@@ -52,6 +72,9 @@ type Configuration struct {
 	// NodeOUs enables the MSP to tell apart clients, peers and orderers based
 	// on the identity's OU.
 	NodeOUs *NodeOUs `yaml:"NodeOUs,omitempty"`
+	// OnlineRevocation enables the MSP to check the revocation status of
+	// certificates online.
+	OnlineRevocation *OnlineRevocation `yaml:"OnlineRevocation,omitempty"`
 }
 
 func readFile(file string) ([]byte, error) {
@@ -257,6 +280,7 @@ func getMspConfig(dir string, ID string, sigid *msp.SigningIdentityInfo) (*msp.M
 	// otherwise skip it
 	var ouis []*msp.FabricOUIdentifier
 	var nodeOUs *msp.FabricNodeOUs
+	var onlineRevocation *msp.FabricOnlineRevocation
 	_, err = os.Stat(configFile)
 	if err == nil {
 		// load the file, if there is a failure in loading it then
@@ -325,6 +349,17 @@ func getMspConfig(dir string, ID string, sigid *msp.SigningIdentityInfo) (*msp.M
 				nodeOUs.PeerOuIdentifier.Certificate = raw
 			}
 		}
+
+		// Prepare OnlineRevocation
+		if configuration.OnlineRevocation != nil {
+			onlineRevocation = &msp.FabricOnlineRevocation{
+				Ocsp:                  configuration.OnlineRevocation.OCSP,
+				CrlDistributionPoints: configuration.OnlineRevocation.CRLDistributionPoints,
+				FailClosed:            configuration.OnlineRevocation.FailClosed,
+				CacheDuration:         configuration.OnlineRevocation.CacheDuration,
+				Timeout:               configuration.OnlineRevocation.Timeout,
+			}
+		}
 	} else {
 		mspLogger.Debugf("MSP configuration file not found at [%s]: [%s]", configFile, err)
 	}
@@ -348,6 +383,7 @@ func getMspConfig(dir string, ID string, sigid *msp.SigningIdentityInfo) (*msp.M
 		TlsRootCerts:                  tlsCACerts,
 		TlsIntermediateCerts:          tlsIntermediateCerts,
 		FabricNodeOus:                 nodeOUs,
+		OnlineRevocation:              onlineRevocation,
 	}
 
 	fmpsjs, _ := proto.Marshal(fmspconf)
@@ -363,6 +399,7 @@ const (
 	IdemixConfigFileIssuerPublicKey     = "IssuerPublicKey"
 	IdemixConfigFileRevocationPublicKey = "RevocationPublicKey"
 	IdemixConfigFileSigner              = "SignerConfig"
+	IdemixConfigFileRevocationEpoch     = "RevocationEpoch"
 )
 
 // GetIdemixMspConfig returns the configuration for the Idemix MSP
@@ -383,6 +420,15 @@ func GetIdemixMspConfig(dir string, ID string) (*msp.MSPConfig, error) {
 		RevocationPk: revocationPkBytes,
 	}
 
+	// the revocation epoch is optional, an MSP that never revoked a credential is at epoch 0
+	epochBytes, err := readFile(filepath.Join(dir, IdemixConfigDirMsp, IdemixConfigFileRevocationEpoch))
+	if err == nil {
+		idemixConfig.Epoch, err = strconv.ParseInt(strings.TrimSpace(string(epochBytes)), 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse revocation epoch file")
+		}
+	}
+
 	signerBytes, err := readFile(filepath.Join(dir, IdemixConfigDirUser, IdemixConfigFileSigner))
 	if err == nil {
 		signerConfig := &msp.IdemixMSPSignerConfig{}
@@ -12,7 +12,9 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"justledger/core/config/configtest"
+	"justledger/protos/msp"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -28,6 +30,50 @@ func TestGetLocalMspConfigFails(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestGetMspConfigWithOnlineRevocation(t *testing.T) {
+	mspDir, err := configtest.GetDevMspDir()
+	assert.NoError(t, err)
+
+	tempDir, err := ioutil.TempDir("", "fabric-msp-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	for _, dir := range []string{cacerts, admincerts, signcerts} {
+		err = os.Symlink(filepath.Join(mspDir, dir), filepath.Join(tempDir, dir))
+		assert.NoError(t, err)
+	}
+	config := `
+OnlineRevocation:
+  OCSP: true
+  CRLDistributionPoints: true
+  FailClosed: true
+  CacheDuration: 1m
+  Timeout: 2s
+`
+	err = ioutil.WriteFile(filepath.Join(tempDir, configfilename), []byte(config), 0644)
+	assert.NoError(t, err)
+
+	conf, err := GetVerifyingMspConfig(tempDir, "SampleOrg", ProviderTypeToString(FABRIC))
+	assert.NoError(t, err)
+	fabricConfig := &msp.FabricMSPConfig{}
+	err = proto.Unmarshal(conf.Config, fabricConfig)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(&msp.FabricOnlineRevocation{
+		Ocsp:                  true,
+		CrlDistributionPoints: true,
+		FailClosed:            true,
+		CacheDuration:         "1m",
+		Timeout:               "2s",
+	}, fabricConfig.OnlineRevocation))
+
+	// The resulting configuration can be used to set up an MSP
+	thisMSP, err := newBccspMsp(MSPv1_0)
+	assert.NoError(t, err)
+	err = thisMSP.Setup(conf)
+	assert.NoError(t, err)
+	assert.NotNil(t, thisMSP.(*bccspmsp).revocationChecker)
+}
+
 func TestGetPemMaterialFromDirWithFile(t *testing.T) {
 	tempFile, err := ioutil.TempFile("", "fabric-msp-test")
 	assert.NoError(t, err)
@@ -64,6 +64,20 @@ const rhIndex = 3
 // while hiding attributes EnrollmentID and RevocationHandle.
 var discloseFlags = []byte{1, 1, 0, 0}
 
+// getDiscloseFlags returns the disclosure flags for an identity that,
+// besides OU and Role, optionally discloses its enrollment ID and revocation handle.
+func getDiscloseFlags(discloseEnrollmentId, discloseRevocationHandle bool) []byte {
+	flags := make([]byte, len(discloseFlags))
+	copy(flags, discloseFlags)
+	if discloseEnrollmentId {
+		flags[AttributeIndexEnrollmentId] = 1
+	}
+	if discloseRevocationHandle {
+		flags[AttributeIndexRevocationHandle] = 1
+	}
+	return flags
+}
+
 type idemixmsp struct {
 	version      MSPVersion
 	ipk          *idemix.IssuerPublicKey
@@ -148,6 +162,7 @@ func (msp *idemixmsp) Setup(conf1 *m.MSPConfig) error {
 		return errors.Errorf("key is of type %v, not of type ECDSA", reflect.TypeOf(revocationPk))
 	}
 	msp.revocationPK = ecdsaPublicKey
+	msp.epoch = int(conf.Epoch)
 
 	if conf.Signer == nil {
 		// No credential in config, so we don't setup a default signer
@@ -214,15 +229,32 @@ func (msp *idemixmsp) Setup(conf1 *m.MSPConfig) error {
 	if err != nil {
 		return errors.Wrap(err, "failed to unmarshal credential revocation information")
 	}
+	if cri.Epoch != int64(msp.epoch) {
+		return errors.Errorf("credential revocation information is for epoch %d, but the current epoch is %d", cri.Epoch, msp.epoch)
+	}
+
+	// The revocation handle can only be disclosed when revocation is not in use,
+	// as disclosing it would otherwise make the identity linkable to the revocation list
+	if conf.Signer.DiscloseRevocationHandle && cri.RevocationAlg != int32(idemix.ALG_NO_REVOCATION) {
+		return errors.New("the revocation handle can only be disclosed when the revocation algorithm is ALG_NO_REVOCATION")
+	}
 
 	// Create the cryptographic evidence that this identity is valid
-	proof, err := idemix.NewSignature(cred, sk, Nym, RandNym, ipk, discloseFlags, nil, rhIndex, cri, rng)
+	flags := getDiscloseFlags(conf.Signer.DiscloseEnrollmentId, conf.Signer.DiscloseRevocationHandle)
+	proof, err := idemix.NewSignature(cred, sk, Nym, RandNym, ipk, flags, nil, rhIndex, cri, rng)
 	if err != nil {
 		return errors.Wrap(err, "Failed to setup cryptographic proof of identity")
 	}
 
 	// Set up default signer
-	msp.signer = &idemixSigningIdentity{newIdemixIdentity(msp, Nym, role, ou, proof), rng, cred, sk, RandNym, enrollmentId}
+	id := newIdemixIdentity(msp, Nym, role, ou, proof)
+	if conf.Signer.DiscloseEnrollmentId {
+		id.EnrollmentId = enrollmentId
+	}
+	if conf.Signer.DiscloseRevocationHandle {
+		id.RevocationHandle = cred.Attrs[AttributeIndexRevocationHandle]
+	}
+	msp.signer = &idemixSigningIdentity{id, rng, cred, sk, RandNym, enrollmentId}
 
 	return nil
 }
@@ -296,8 +328,11 @@ func (msp *idemixmsp) deserializeIdentityInternal(serializedID []byte) (Identity
 		return nil, errors.Wrap(err, "cannot deserialize the proof of the identity")
 	}
 
-	return newIdemixIdentity(msp, Nym, role, ou, proof), nil
+	id := newIdemixIdentity(msp, Nym, role, ou, proof)
+	id.EnrollmentId = serialized.EnrollmentId
+	id.RevocationHandle = serialized.RevocationHandle
 
+	return id, nil
 }
 
 func (msp *idemixmsp) Validate(id Identity) error {
@@ -320,9 +355,16 @@ func (msp *idemixmsp) Validate(id Identity) error {
 
 func (id *idemixidentity) verifyProof() error {
 	ouBytes := []byte(id.OU.OrganizationalUnitIdentifier)
-	attributeValues := []*FP256BN.BIG{idemix.HashModOrder(ouBytes), FP256BN.NewBIGint(getIdemixRoleFromMSPRole(id.Role))}
+	attributeValues := []*FP256BN.BIG{idemix.HashModOrder(ouBytes), FP256BN.NewBIGint(getIdemixRoleFromMSPRole(id.Role)), nil, nil}
+	if id.EnrollmentId != "" {
+		attributeValues[AttributeIndexEnrollmentId] = idemix.HashModOrder([]byte(id.EnrollmentId))
+	}
+	if len(id.RevocationHandle) != 0 {
+		attributeValues[AttributeIndexRevocationHandle] = FP256BN.FromBytes(id.RevocationHandle)
+	}
+	flags := getDiscloseFlags(id.EnrollmentId != "", len(id.RevocationHandle) != 0)
 
-	return id.associationProof.Ver(discloseFlags, id.msp.ipk, nil, attributeValues, rhIndex, id.msp.revocationPK, id.msp.epoch)
+	return id.associationProof.Ver(flags, id.msp.ipk, nil, attributeValues, rhIndex, id.msp.revocationPK, id.msp.epoch)
 }
 
 func (msp *idemixmsp) SatisfiesPrincipal(id Identity, principal *m.MSPPrincipal) error {
@@ -491,6 +533,10 @@ type idemixidentity struct {
 	id   *IdentityIdentifier
 	Role *m.MSPRole
 	OU   *m.OrganizationUnit
+	// EnrollmentId and RevocationHandle are only set
+	// when the identity discloses them
+	EnrollmentId     string
+	RevocationHandle []byte
 	// associationProof contains cryptographic proof that this identity
 	// belongs to the MSP id.msp, i.e., it proves that the pseudonym
 	// is constructed from a secret key on which the CA issued a credential.
@@ -576,6 +622,8 @@ func (id *idemixidentity) Serialize() ([]byte, error) {
 
 	serialized.Ou = ouBytes
 	serialized.Role = roleBytes
+	serialized.EnrollmentId = id.EnrollmentId
+	serialized.RevocationHandle = id.RevocationHandle
 
 	serialized.Proof, err = proto.Marshal(id.associationProof)
 	if err != nil {
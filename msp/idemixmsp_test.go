@@ -7,9 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-amcl/amcl/FP256BN"
 	"justledger/idemix"
 	"justledger/protos/msp"
 	"github.com/pkg/errors"
@@ -151,6 +156,177 @@ func TestSigning(t *testing.T) {
 	assert.Contains(t, err.Error(), "no default signer setup")
 }
 
+// setupAtEpoch sets up the MSP found in configPath at the given revocation epoch,
+// renewing the credential revocation information of its signer for that epoch
+func setupAtEpoch(t *testing.T, configPath string, ID string, epoch int) (MSP, error) {
+	conf, err := GetIdemixMspConfig(configPath, ID)
+	assert.NoError(t, err)
+	idemixConfig := &msp.IdemixMSPConfig{}
+	assert.NoError(t, proto.Unmarshal(conf.Config, idemixConfig))
+	idemixConfig.Epoch = int64(epoch)
+
+	if idemixConfig.Signer != nil {
+		keyBytes, err := ioutil.ReadFile(filepath.Join(configPath, "ca", "RevocationKey"))
+		assert.NoError(t, err)
+		block, _ := pem.Decode(keyBytes)
+		assert.NotNil(t, block)
+		revocationKey, err := x509.ParseECPrivateKey(block.Bytes)
+		assert.NoError(t, err)
+
+		cred := &idemix.Credential{}
+		assert.NoError(t, proto.Unmarshal(idemixConfig.Signer.Cred, cred))
+		rh := FP256BN.FromBytes(cred.Attrs[rhIndex])
+
+		rng, err := idemix.GetRand()
+		assert.NoError(t, err)
+		cri, err := idemix.CreateCRI(revocationKey, []*FP256BN.BIG{rh}, epoch, idemix.ALG_PLAIN_SIGNATURE, rng)
+		assert.NoError(t, err)
+		idemixConfig.Signer.CredentialRevocationInformation, err = proto.Marshal(cri)
+		assert.NoError(t, err)
+	}
+
+	conf.Config, err = proto.Marshal(idemixConfig)
+	assert.NoError(t, err)
+
+	m, err := newIdemixMsp(MSPv1_3)
+	assert.NoError(t, err)
+	return m, m.Setup(conf)
+}
+
+func TestRevocationEpoch(t *testing.T) {
+	signerMsp, err := setupAtEpoch(t, "testdata/idemix/MSP1OU1", "MSP1", 1)
+	assert.NoError(t, err)
+
+	id, err := getDefaultSigner(signerMsp)
+	assert.NoError(t, err)
+
+	msg := []byte("TestMessage")
+	sig, err := id.Sign(msg)
+	assert.NoError(t, err)
+	assert.NoError(t, id.Verify(msg, sig))
+
+	// a verifier at the same epoch accepts the identity
+	verMsp, err := setupAtEpoch(t, "testdata/idemix/MSP1Verifier", "MSP1", 1)
+	assert.NoError(t, err)
+	serializedID, err := id.Serialize()
+	assert.NoError(t, err)
+	verID, err := verMsp.DeserializeIdentity(serializedID)
+	assert.NoError(t, err)
+	assert.NoError(t, verMsp.Validate(verID))
+
+	// a verifier that moved on to a later epoch rejects it
+	verMsp, err = setupAtEpoch(t, "testdata/idemix/MSP1Verifier", "MSP1", 2)
+	assert.NoError(t, err)
+	verID, err = verMsp.DeserializeIdentity(serializedID)
+	assert.NoError(t, err)
+	err = verMsp.Validate(verID)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "signature is for epoch 1 but the current epoch is 2")
+
+	// the credential revocation information of the signer must be for the current epoch
+	conf, err := GetIdemixMspConfig("testdata/idemix/MSP1OU1", "MSP1")
+	assert.NoError(t, err)
+	idemixConfig := &msp.IdemixMSPConfig{}
+	assert.NoError(t, proto.Unmarshal(conf.Config, idemixConfig))
+	idemixConfig.Epoch = 1
+	conf.Config, err = proto.Marshal(idemixConfig)
+	assert.NoError(t, err)
+	m, err := newIdemixMsp(MSPv1_3)
+	assert.NoError(t, err)
+	err = m.Setup(conf)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "credential revocation information is for epoch 0, but the current epoch is 1")
+}
+
+func setupWithDisclosure(t *testing.T, configPath string, ID string, discloseEnrollmentId, discloseRevocationHandle bool) (MSP, error) {
+	conf, err := GetIdemixMspConfig(configPath, ID)
+	assert.NoError(t, err)
+	idemixConfig := &msp.IdemixMSPConfig{}
+	assert.NoError(t, proto.Unmarshal(conf.Config, idemixConfig))
+	idemixConfig.Signer.DiscloseEnrollmentId = discloseEnrollmentId
+	idemixConfig.Signer.DiscloseRevocationHandle = discloseRevocationHandle
+	conf.Config, err = proto.Marshal(idemixConfig)
+	assert.NoError(t, err)
+
+	m, err := newIdemixMsp(MSPv1_3)
+	assert.NoError(t, err)
+	return m, m.Setup(conf)
+}
+
+func TestDisclosedAttributes(t *testing.T) {
+	msp1, err := setupWithDisclosure(t, "testdata/idemix/MSP1OU1", "MSP1OU1", true, true)
+	assert.NoError(t, err)
+
+	id, err := getDefaultSigner(msp1)
+	assert.NoError(t, err)
+
+	serializedID, err := id.Serialize()
+	assert.NoError(t, err)
+	verID, err := msp1.DeserializeIdentity(serializedID)
+	assert.NoError(t, err)
+	assert.NoError(t, msp1.Validate(verID))
+
+	idemixID := verID.(*idemixidentity)
+	assert.NotEmpty(t, idemixID.EnrollmentId)
+	assert.NotEmpty(t, idemixID.RevocationHandle)
+
+	// a verifier without a signer accepts the disclosed attributes as well
+	verMsp, err := setup("testdata/idemix/MSP1Verifier", "MSP1OU1")
+	assert.NoError(t, err)
+	verID, err = verMsp.DeserializeIdentity(serializedID)
+	assert.NoError(t, err)
+	assert.NoError(t, verMsp.Validate(verID))
+
+	// tampering with a disclosed attribute invalidates the proof
+	idemixID.EnrollmentId = "someone else"
+	err = msp1.Validate(idemixID)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "signature invalid: zero-knowledge proof is invalid")
+
+	// without disclosure the attributes are not part of the identity
+	msp2, err := setupWithDisclosure(t, "testdata/idemix/MSP1OU1", "MSP1OU1", false, false)
+	assert.NoError(t, err)
+	id, err = getDefaultSigner(msp2)
+	assert.NoError(t, err)
+	serializedID, err = id.Serialize()
+	assert.NoError(t, err)
+	verID, err = msp2.DeserializeIdentity(serializedID)
+	assert.NoError(t, err)
+	assert.Empty(t, verID.(*idemixidentity).EnrollmentId)
+	assert.Empty(t, verID.(*idemixidentity).RevocationHandle)
+}
+
+func TestDisclosedRevocationHandleWithRevocation(t *testing.T) {
+	conf, err := GetIdemixMspConfig("testdata/idemix/MSP1OU1", "MSP1OU1")
+	assert.NoError(t, err)
+	idemixConfig := &msp.IdemixMSPConfig{}
+	assert.NoError(t, proto.Unmarshal(conf.Config, idemixConfig))
+	idemixConfig.Signer.DiscloseRevocationHandle = true
+
+	keyBytes, err := ioutil.ReadFile(filepath.Join("testdata/idemix/MSP1OU1", "ca", "RevocationKey"))
+	assert.NoError(t, err)
+	block, _ := pem.Decode(keyBytes)
+	assert.NotNil(t, block)
+	revocationKey, err := x509.ParseECPrivateKey(block.Bytes)
+	assert.NoError(t, err)
+	cred := &idemix.Credential{}
+	assert.NoError(t, proto.Unmarshal(idemixConfig.Signer.Cred, cred))
+	rng, err := idemix.GetRand()
+	assert.NoError(t, err)
+	cri, err := idemix.CreateCRI(revocationKey, []*FP256BN.BIG{FP256BN.FromBytes(cred.Attrs[rhIndex])}, 0, idemix.ALG_PLAIN_SIGNATURE, rng)
+	assert.NoError(t, err)
+	idemixConfig.Signer.CredentialRevocationInformation, err = proto.Marshal(cri)
+	assert.NoError(t, err)
+	conf.Config, err = proto.Marshal(idemixConfig)
+	assert.NoError(t, err)
+
+	m, err := newIdemixMsp(MSPv1_3)
+	assert.NoError(t, err)
+	err = m.Setup(conf)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the revocation handle can only be disclosed when the revocation algorithm is ALG_NO_REVOCATION")
+}
+
 func TestSigningBad(t *testing.T) {
 	msp, err := setup("testdata/idemix/MSP1OU1", "MSP1OU1")
 	assert.NoError(t, err)
@@ -96,6 +96,19 @@ type MSP interface {
 	SatisfiesPrincipal(id Identity, principal *msp.MSPPrincipal) error
 }
 
+// OnlineRevocationChecker is implemented by the MSPs that can check the
+// revocation status of their identities against the OCSP responders and
+// CRL distribution points advertised by their certificates. The outcome
+// depends on remote services and on the time of the check, therefore it
+// is not part of Validate: it is only requested for the local MSP when
+// endorsing, and never when validating transactions at commit time
+type OnlineRevocationChecker interface {
+	// CheckRevocationOnline returns an error if the supplied identity has
+	// been revoked, or if its revocation status cannot be determined and
+	// the MSP is configured to fail closed
+	CheckRevocationOnline(id Identity) error
+}
+
 // OUIdentifier represents an organizational unit and
 // its related chain of trust identifier.
 type OUIdentifier struct {
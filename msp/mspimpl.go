@@ -8,6 +8,7 @@ package msp
 
 import (
 	"bytes"
+	"crypto/ed25519"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/hex"
@@ -83,6 +84,10 @@ type bccspmsp struct {
 	// list of certificate revocation lists
 	CRL []*pkix.CertificateList
 
+	// revocationChecker checks the revocation status of certificates
+	// online; it is nil unless online revocation checking is configured
+	revocationChecker *revocationChecker
+
 	// list of OUs
 	ouIdentifiers map[string][][]byte
 
@@ -187,9 +192,17 @@ func (msp *bccspmsp) getSigningIdentityFromConf(sidInfo *m.SigningIdentityInfo)
 		}
 
 		pemKey, _ := pem.Decode(sidInfo.PrivateSigner.KeyMaterial)
-		privKey, err = msp.bccsp.KeyImport(pemKey.Bytes, &bccsp.ECDSAPrivateKeyImportOpts{Temporary: true})
-		if err != nil {
-			return nil, errors.WithMessage(err, "getIdentityFromBytes error: Failed to import EC private key")
+		// the type of the private key is the one of the public key in the certificate
+		if _, isED25519 := idPub.(*identity).cert.PublicKey.(ed25519.PublicKey); isED25519 {
+			privKey, err = msp.bccsp.KeyImport(pemKey.Bytes, &bccsp.ED25519PrivateKeyImportOpts{Temporary: true})
+			if err != nil {
+				return nil, errors.WithMessage(err, "getIdentityFromBytes error: Failed to import Ed25519 private key")
+			}
+		} else {
+			privKey, err = msp.bccsp.KeyImport(pemKey.Bytes, &bccsp.ECDSAPrivateKeyImportOpts{Temporary: true})
+			if err != nil {
+				return nil, errors.WithMessage(err, "getIdentityFromBytes error: Failed to import EC private key")
+			}
 		}
 	}
 
@@ -197,6 +197,23 @@ func (msp *bccspmsp) setupCRLs(conf *m.FabricMSPConfig) error {
 	return nil
 }
 
+func (msp *bccspmsp) setupOnlineRevocation(conf *m.FabricMSPConfig) error {
+	msp.revocationChecker = nil
+
+	onlineRevocation := conf.OnlineRevocation
+	if onlineRevocation == nil || (!onlineRevocation.Ocsp && !onlineRevocation.CrlDistributionPoints) {
+		return nil
+	}
+
+	checker, err := newRevocationChecker(onlineRevocation)
+	if err != nil {
+		return errors.WithMessage(err, "failed setting up online revocation checking")
+	}
+	msp.revocationChecker = checker
+
+	return nil
+}
+
 func (msp *bccspmsp) finalizeSetupCAs(config *m.FabricMSPConfig) error {
 	// ensure that our CAs are properly formed and that they are valid
 	for _, id := range append(append([]Identity{}, msp.rootCerts...), msp.intermediateCerts...) {
@@ -398,6 +415,11 @@ func (msp *bccspmsp) preSetupV1(conf *m.FabricMSPConfig) error {
 		return err
 	}
 
+	// Setup online revocation checking
+	if err := msp.setupOnlineRevocation(conf); err != nil {
+		return err
+	}
+
 	// Finalize setup of the CAs
 	if err := msp.finalizeSetupCAs(conf); err != nil {
 		return err
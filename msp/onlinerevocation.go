@@ -0,0 +1,331 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"justledger/common/crypto/ocsp"
+	m "justledger/protos/msp"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultRevocationCacheDuration = 10 * time.Minute
+	// defaultRevocationFailureCacheDuration bounds how long a failure to
+	// determine a revocation status is cached, so that an unreachable
+	// source does not cost a timeout on every check
+	defaultRevocationFailureCacheDuration = time.Minute
+	defaultRevocationTimeout              = 5 * time.Second
+	// maxRevocationResponseSize bounds the size of OCSP responses and CRLs
+	maxRevocationResponseSize = 10 * 1024 * 1024
+)
+
+var errCertificateRevoked = errors.New("The certificate has been revoked")
+
+// revocationStatus is a cached revocation status of a certificate.
+// A non-nil err means the status could not be determined
+type revocationStatus struct {
+	revoked bool
+	err     error
+	expiry  time.Time
+}
+
+// cachedCRL is a CRL fetched from a distribution point
+type cachedCRL struct {
+	crl    *pkix.CertificateList
+	expiry time.Time
+}
+
+// revocationChecker checks the revocation status of certificates against
+// the OCSP responders and CRL distribution points they advertise
+type revocationChecker struct {
+	ocsp                 bool
+	crlDPs               bool
+	failClosed           bool
+	cacheDuration        time.Duration
+	failureCacheDuration time.Duration
+	httpClient           *http.Client
+
+	lock     sync.Mutex
+	statuses map[string]revocationStatus
+	crls     map[string]cachedCRL
+}
+
+func newRevocationChecker(conf *m.FabricOnlineRevocation) (*revocationChecker, error) {
+	cacheDuration := defaultRevocationCacheDuration
+	if conf.CacheDuration != "" {
+		d, err := time.ParseDuration(conf.CacheDuration)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid cache duration %s", conf.CacheDuration)
+		}
+		cacheDuration = d
+	}
+
+	timeout := defaultRevocationTimeout
+	if conf.Timeout != "" {
+		d, err := time.ParseDuration(conf.Timeout)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid timeout %s", conf.Timeout)
+		}
+		timeout = d
+	}
+
+	failureCacheDuration := defaultRevocationFailureCacheDuration
+	if cacheDuration < failureCacheDuration {
+		failureCacheDuration = cacheDuration
+	}
+
+	return &revocationChecker{
+		ocsp:                 conf.Ocsp,
+		crlDPs:               conf.CrlDistributionPoints,
+		failClosed:           conf.FailClosed,
+		cacheDuration:        cacheDuration,
+		failureCacheDuration: failureCacheDuration,
+		httpClient:           &http.Client{Timeout: timeout},
+		statuses:             make(map[string]revocationStatus),
+		crls:                 make(map[string]cachedCRL),
+	}, nil
+}
+
+// CheckRevocationOnline checks the revocation status of id against the
+// OCSP responders and CRL distribution points advertised by its certificate,
+// if the MSP is configured to. id must be a valid identity of the MSP
+func (msp *bccspmsp) CheckRevocationOnline(id Identity) error {
+	if msp.revocationChecker == nil {
+		return nil
+	}
+
+	bccspID, ok := id.(*identity)
+	if !ok {
+		return errors.New("identity type not recognized")
+	}
+	validationChain, err := msp.getCertificationChainForBCCSPIdentity(bccspID)
+	if err != nil {
+		return errors.WithMessage(err, "could not obtain certification chain")
+	}
+	if len(validationChain) < 2 {
+		return errors.Errorf("expected a chain of length at least 2, got %d", len(validationChain))
+	}
+
+	return msp.revocationChecker.check(bccspID.cert, validationChain[1])
+}
+
+// check returns an error if cert, issued by issuer, has been revoked.
+// If the status cannot be determined from any of the sources advertised
+// by cert, an error is returned only when the checker fails closed.
+// Certificates that advertise no source are not checked.
+func (rc *revocationChecker) check(cert, issuer *x509.Certificate) error {
+	key := statusKey(cert, issuer)
+	if status, found := rc.cachedStatus(key); found {
+		if status.err != nil {
+			return rc.undetermined(cert, status.err)
+		}
+		if status.revoked {
+			return errCertificateRevoked
+		}
+		return nil
+	}
+
+	var sources int
+	var lastErr error
+
+	if rc.ocsp {
+		for _, url := range cert.OCSPServer {
+			sources++
+			revoked, expiry, err := rc.queryOCSP(url, cert, issuer)
+			if err != nil {
+				mspLogger.Warningf("Failed checking revocation status of certificate %s at OCSP responder %s: %s", cert.SerialNumber, url, err)
+				lastErr = err
+				continue
+			}
+			return rc.recordStatus(key, revoked, expiry)
+		}
+	}
+
+	if rc.crlDPs {
+		for _, url := range cert.CRLDistributionPoints {
+			sources++
+			revoked, expiry, err := rc.checkCRL(url, cert, issuer)
+			if err != nil {
+				mspLogger.Warningf("Failed checking revocation status of certificate %s at CRL distribution point %s: %s", cert.SerialNumber, url, err)
+				lastErr = err
+				continue
+			}
+			return rc.recordStatus(key, revoked, expiry)
+		}
+	}
+
+	if sources == 0 {
+		return nil
+	}
+	rc.lock.Lock()
+	rc.statuses[key] = revocationStatus{err: lastErr, expiry: time.Now().Add(rc.failureCacheDuration)}
+	rc.lock.Unlock()
+	return rc.undetermined(cert, lastErr)
+}
+
+// undetermined returns the outcome of a check that could not determine the
+// revocation status of cert because of err, which depends on whether the
+// checker fails closed
+func (rc *revocationChecker) undetermined(cert *x509.Certificate, err error) error {
+	if rc.failClosed {
+		return errors.WithMessage(err, "could not determine the revocation status of the certificate")
+	}
+	mspLogger.Warningf("Accepting certificate %s whose revocation status could not be determined", cert.SerialNumber)
+	return nil
+}
+
+func (rc *revocationChecker) queryOCSP(url string, cert, issuer *x509.Certificate) (bool, time.Time, error) {
+	req, err := ocsp.CreateRequest(cert, issuer)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+
+	resp, err := rc.httpClient.Post(url, "application/ocsp-request", bytes.NewReader(req))
+	if err != nil {
+		return false, time.Time{}, errors.Wrap(err, "OCSP request failed")
+	}
+	raw, err := readRevocationResponse(resp)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+
+	status, err := ocsp.ParseResponse(raw, cert, issuer)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	now := time.Now()
+	if !status.NextUpdate.IsZero() && status.NextUpdate.Before(now) {
+		return false, time.Time{}, errors.Errorf("OCSP response is stale, its next update was at %s", status.NextUpdate)
+	}
+
+	switch status.Status {
+	case ocsp.Good:
+		return false, rc.expiry(status.NextUpdate), nil
+	case ocsp.Revoked:
+		return true, rc.expiry(status.NextUpdate), nil
+	default:
+		return false, time.Time{}, errors.New("OCSP responder does not know the certificate")
+	}
+}
+
+func (rc *revocationChecker) checkCRL(url string, cert, issuer *x509.Certificate) (bool, time.Time, error) {
+	entry, err := rc.fetchCRL(url, issuer)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+
+	for _, revoked := range entry.crl.TBSCertList.RevokedCertificates {
+		if revoked.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+			return true, entry.expiry, nil
+		}
+	}
+	return false, entry.expiry, nil
+}
+
+// fetchCRL returns the CRL published at url, which must be signed by issuer
+func (rc *revocationChecker) fetchCRL(url string, issuer *x509.Certificate) (*cachedCRL, error) {
+	rc.lock.Lock()
+	entry, found := rc.crls[url]
+	rc.lock.Unlock()
+	if found && time.Now().Before(entry.expiry) {
+		if err := issuer.CheckCRLSignature(entry.crl); err == nil {
+			return &entry, nil
+		}
+	}
+
+	resp, err := rc.httpClient.Get(url)
+	if err != nil {
+		return nil, errors.Wrap(err, "CRL download failed")
+	}
+	raw, err := readRevocationResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	crl, err := x509.ParseCRL(raw)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed parsing CRL")
+	}
+	if err := issuer.CheckCRLSignature(crl); err != nil {
+		return nil, errors.Wrap(err, "CRL is not signed by the certificate issuer")
+	}
+	nextUpdate := crl.TBSCertList.NextUpdate
+	if !nextUpdate.IsZero() && nextUpdate.Before(time.Now()) {
+		return nil, errors.Errorf("CRL is stale, its next update was at %s", nextUpdate)
+	}
+
+	entry = cachedCRL{crl: crl, expiry: rc.expiry(nextUpdate)}
+	rc.lock.Lock()
+	rc.crls[url] = entry
+	rc.lock.Unlock()
+	return &entry, nil
+}
+
+// expiry returns until when a status should be cached, which is never
+// after the next update announced by the source
+func (rc *revocationChecker) expiry(nextUpdate time.Time) time.Time {
+	expiry := time.Now().Add(rc.cacheDuration)
+	if !nextUpdate.IsZero() && nextUpdate.Before(expiry) {
+		return nextUpdate
+	}
+	return expiry
+}
+
+func (rc *revocationChecker) cachedStatus(key string) (revocationStatus, bool) {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	status, found := rc.statuses[key]
+	if !found {
+		return revocationStatus{}, false
+	}
+	if !time.Now().Before(status.expiry) {
+		delete(rc.statuses, key)
+		return revocationStatus{}, false
+	}
+	return status, true
+}
+
+func (rc *revocationChecker) recordStatus(key string, revoked bool, expiry time.Time) error {
+	rc.lock.Lock()
+	rc.statuses[key] = revocationStatus{revoked: revoked, expiry: expiry}
+	rc.lock.Unlock()
+
+	if revoked {
+		return errCertificateRevoked
+	}
+	return nil
+}
+
+func readRevocationResponse(resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected HTTP status %s", resp.Status)
+	}
+	raw, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxRevocationResponseSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed reading response")
+	}
+	if len(raw) > maxRevocationResponseSize {
+		return nil, errors.Errorf("response exceeds %d bytes", maxRevocationResponseSize)
+	}
+	return raw, nil
+}
+
+func statusKey(cert, issuer *x509.Certificate) string {
+	return fmt.Sprintf("%x:%s", sha256.Sum256(issuer.Raw), cert.SerialNumber)
+}
@@ -0,0 +1,330 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"justledger/common/crypto/ocsp"
+	m "justledger/protos/msp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// revocationAuthority is a CA along with a local stand-in for its OCSP
+// responder and CRL distribution point
+type revocationAuthority struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+
+	lock     sync.Mutex
+	revoked  map[int64]bool
+	down     bool
+	requests int32
+
+	ocspServer *httptest.Server
+	crlServer  *httptest.Server
+}
+
+func newRevocationAuthority(t *testing.T) *revocationAuthority {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca.example.com"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	ra := &revocationAuthority{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		revoked: make(map[int64]bool),
+	}
+	ra.ocspServer = httptest.NewServer(http.HandlerFunc(ra.serveOCSP))
+	ra.crlServer = httptest.NewServer(http.HandlerFunc(ra.serveCRL))
+	return ra
+}
+
+func (ra *revocationAuthority) close() {
+	ra.ocspServer.Close()
+	ra.crlServer.Close()
+}
+
+func (ra *revocationAuthority) setRevoked(serial int64) {
+	ra.lock.Lock()
+	defer ra.lock.Unlock()
+	ra.revoked[serial] = true
+}
+
+func (ra *revocationAuthority) setDown(down bool) {
+	ra.lock.Lock()
+	defer ra.lock.Unlock()
+	ra.down = down
+}
+
+func (ra *revocationAuthority) serveOCSP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&ra.requests, 1)
+	ra.lock.Lock()
+	defer ra.lock.Unlock()
+	if ra.down {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	raw, _ := ioutil.ReadAll(r.Body)
+	req, err := ocsp.ParseRequest(raw)
+	if err != nil || !req.Matches(ra.cert) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	template := ocsp.Response{
+		Status:       ocsp.Good,
+		SerialNumber: req.SerialNumber,
+		ThisUpdate:   time.Now().Add(-time.Minute),
+		NextUpdate:   time.Now().Add(time.Hour),
+	}
+	if ra.revoked[req.SerialNumber.Int64()] {
+		template.Status = ocsp.Revoked
+		template.RevokedAt = time.Now().Add(-time.Minute)
+	}
+	resp, err := ocsp.CreateResponse(ra.cert, ra.cert, template, ra.key)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Write(resp)
+}
+
+func (ra *revocationAuthority) serveCRL(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&ra.requests, 1)
+	ra.lock.Lock()
+	defer ra.lock.Unlock()
+	if ra.down {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	var entries []x509.RevocationListEntry
+	for serial := range ra.revoked {
+		entries = append(entries, x509.RevocationListEntry{
+			SerialNumber:   big.NewInt(serial),
+			RevocationTime: time.Now().Add(-time.Minute),
+		})
+	}
+	crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(time.Now().UnixNano()),
+		ThisUpdate:                time.Now().Add(-time.Minute),
+		NextUpdate:                time.Now().Add(time.Hour),
+		RevokedCertificateEntries: entries,
+	}, ra.cert, ra.key)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Write(crl)
+}
+
+// issue returns the serialized identity of a certificate with the given
+// serial number, advertising the given OCSP responders and CRL distribution points
+func (ra *revocationAuthority) issue(t *testing.T, serial int64, ocspServers, crlDPs []string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: "user.example.com"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		OCSPServer:            ocspServers,
+		CRLDistributionPoints: crlDPs,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ra.cert, &key.PublicKey, ra.key)
+	require.NoError(t, err)
+
+	sID, err := proto.Marshal(&m.SerializedIdentity{
+		Mspid:   "RevocationOrg",
+		IdBytes: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	})
+	require.NoError(t, err)
+	return sID
+}
+
+func (ra *revocationAuthority) newMSP(t *testing.T, onlineRevocation *m.FabricOnlineRevocation) (MSP, error) {
+	fabricConfig, err := proto.Marshal(&m.FabricMSPConfig{
+		Name:             "RevocationOrg",
+		RootCerts:        [][]byte{ra.certPEM},
+		OnlineRevocation: onlineRevocation,
+	})
+	require.NoError(t, err)
+
+	thisMSP, err := newBccspMsp(MSPv1_0)
+	require.NoError(t, err)
+	err = thisMSP.Setup(&m.MSPConfig{Type: int32(FABRIC), Config: fabricConfig})
+	return thisMSP, err
+}
+
+func checkSerialized(t *testing.T, thisMSP MSP, sID []byte) error {
+	id, err := thisMSP.DeserializeIdentity(sID)
+	require.NoError(t, err)
+	require.NoError(t, thisMSP.Validate(id))
+	return thisMSP.(OnlineRevocationChecker).CheckRevocationOnline(id)
+}
+
+func TestOnlineRevocationOCSP(t *testing.T) {
+	ra := newRevocationAuthority(t)
+	defer ra.close()
+
+	thisMSP, err := ra.newMSP(t, &m.FabricOnlineRevocation{Ocsp: true})
+	require.NoError(t, err)
+
+	good := ra.issue(t, 10, []string{ra.ocspServer.URL}, nil)
+	revoked := ra.issue(t, 11, []string{ra.ocspServer.URL}, nil)
+	ra.setRevoked(11)
+
+	assert.NoError(t, checkSerialized(t, thisMSP, good))
+	assert.EqualError(t, checkSerialized(t, thisMSP, revoked), "The certificate has been revoked")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&ra.requests))
+
+	// Statuses are served from the cache, even once the certificate is revoked
+	ra.setRevoked(10)
+	assert.NoError(t, checkSerialized(t, thisMSP, good))
+	assert.EqualError(t, checkSerialized(t, thisMSP, revoked), "The certificate has been revoked")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&ra.requests))
+
+	// Without caching, the revocation is detected right away
+	thisMSP, err = ra.newMSP(t, &m.FabricOnlineRevocation{Ocsp: true, CacheDuration: "0s"})
+	require.NoError(t, err)
+	assert.EqualError(t, checkSerialized(t, thisMSP, good), "The certificate has been revoked")
+
+	// Certificates that advertise no responder are not checked
+	silent := ra.issue(t, 12, nil, nil)
+	ra.setRevoked(12)
+	assert.NoError(t, checkSerialized(t, thisMSP, silent))
+}
+
+func TestOnlineRevocationCRLDistributionPoints(t *testing.T) {
+	ra := newRevocationAuthority(t)
+	defer ra.close()
+
+	thisMSP, err := ra.newMSP(t, &m.FabricOnlineRevocation{CrlDistributionPoints: true})
+	require.NoError(t, err)
+
+	good := ra.issue(t, 20, nil, []string{ra.crlServer.URL})
+	revoked := ra.issue(t, 21, nil, []string{ra.crlServer.URL})
+	ra.setRevoked(21)
+
+	assert.NoError(t, checkSerialized(t, thisMSP, good))
+	assert.EqualError(t, checkSerialized(t, thisMSP, revoked), "The certificate has been revoked")
+	// The CRL is fetched once for both certificates
+	assert.Equal(t, int32(1), atomic.LoadInt32(&ra.requests))
+
+	// OCSP responders are ignored unless enabled
+	ocspOnly := ra.issue(t, 22, []string{ra.ocspServer.URL}, nil)
+	ra.setRevoked(22)
+	assert.NoError(t, checkSerialized(t, thisMSP, ocspOnly))
+
+	// When both are enabled, the CRL is used if the responder is unreachable
+	thisMSP, err = ra.newMSP(t, &m.FabricOnlineRevocation{Ocsp: true, CrlDistributionPoints: true, FailClosed: true})
+	require.NoError(t, err)
+	both := ra.issue(t, 23, []string{"http://127.0.0.1:1/ocsp"}, []string{ra.crlServer.URL})
+	ra.setRevoked(23)
+	assert.EqualError(t, checkSerialized(t, thisMSP, both), "The certificate has been revoked")
+}
+
+func TestOnlineRevocationFailOpenAndClosed(t *testing.T) {
+	ra := newRevocationAuthority(t)
+	defer ra.close()
+
+	sID := ra.issue(t, 30, []string{ra.ocspServer.URL}, []string{ra.crlServer.URL})
+	ra.setDown(true)
+
+	failOpen, err := ra.newMSP(t, &m.FabricOnlineRevocation{Ocsp: true, CrlDistributionPoints: true})
+	require.NoError(t, err)
+	assert.NoError(t, checkSerialized(t, failOpen, sID))
+
+	failClosed, err := ra.newMSP(t, &m.FabricOnlineRevocation{Ocsp: true, CrlDistributionPoints: true, FailClosed: true})
+	require.NoError(t, err)
+	err = checkSerialized(t, failClosed, sID)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "could not determine the revocation status of the certificate")
+
+	// Failures are cached for a while, so that an unreachable responder does
+	// not cost a timeout on every check
+	requests := atomic.LoadInt32(&ra.requests)
+	ra.setDown(false)
+	err = checkSerialized(t, failClosed, sID)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "could not determine the revocation status of the certificate")
+	assert.NoError(t, checkSerialized(t, failOpen, sID))
+	assert.Equal(t, requests, atomic.LoadInt32(&ra.requests))
+
+	failClosed.(*bccspmsp).revocationChecker.failureCacheDuration = 0
+	ra.setDown(true)
+	other := ra.issue(t, 32, []string{ra.ocspServer.URL}, nil)
+	assert.Error(t, checkSerialized(t, failClosed, other))
+	ra.setDown(false)
+	assert.NoError(t, checkSerialized(t, failClosed, other))
+
+	// The responder of another CA cannot vouch for the certificate
+	otherCA := newRevocationAuthority(t)
+	defer otherCA.close()
+	forged := ra.issue(t, 31, []string{otherCA.ocspServer.URL}, nil)
+	err = checkSerialized(t, failClosed, forged)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "could not determine the revocation status of the certificate")
+}
+
+func TestOnlineRevocationBadConfig(t *testing.T) {
+	ra := newRevocationAuthority(t)
+	defer ra.close()
+
+	_, err := ra.newMSP(t, &m.FabricOnlineRevocation{Ocsp: true, CacheDuration: "forever"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed setting up online revocation checking: invalid cache duration forever")
+
+	_, err = ra.newMSP(t, &m.FabricOnlineRevocation{Ocsp: true, Timeout: "soon"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed setting up online revocation checking: invalid timeout soon")
+
+	// The failures are cached at most as long as the statuses
+	thisMSP, err := ra.newMSP(t, &m.FabricOnlineRevocation{Ocsp: true, CacheDuration: "10s"})
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, thisMSP.(*bccspmsp).revocationChecker.failureCacheDuration)
+	thisMSP, err = ra.newMSP(t, &m.FabricOnlineRevocation{Ocsp: true})
+	require.NoError(t, err)
+	assert.Equal(t, defaultRevocationFailureCacheDuration, thisMSP.(*bccspmsp).revocationChecker.failureCacheDuration)
+
+	// Nothing is checked unless a source is enabled
+	thisMSP, err = ra.newMSP(t, &m.FabricOnlineRevocation{FailClosed: true, CacheDuration: "forever"})
+	require.NoError(t, err)
+	assert.Nil(t, thisMSP.(*bccspmsp).revocationChecker)
+}
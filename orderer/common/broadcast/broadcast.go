@@ -9,6 +9,7 @@ package broadcast
 import (
 	"io"
 
+	"justledger/common/audit"
 	"justledger/common/flogging"
 	"justledger/common/util"
 	"justledger/orderer/common/msgprocessor"
@@ -60,13 +61,20 @@ type Consenter interface {
 }
 
 type handlerImpl struct {
-	sm ChannelSupportRegistrar
+	sm       ChannelSupportRegistrar
+	recorder audit.Recorder
 }
 
-// NewHandlerImpl constructs a new implementation of the Handler interface
-func NewHandlerImpl(sm ChannelSupportRegistrar) Handler {
+// NewHandlerImpl constructs a new implementation of the Handler interface.
+// Config updates are recorded to the given audit recorder, which may be nil
+// if no audit log is configured.
+func NewHandlerImpl(sm ChannelSupportRegistrar, recorder audit.Recorder) Handler {
+	if recorder == nil {
+		recorder = audit.NoopRecorder{}
+	}
 	return &handlerImpl{
-		sm: sm,
+		sm:       sm,
+		recorder: recorder,
 	}
 }
 
@@ -120,14 +128,19 @@ func (bh *handlerImpl) Handle(srv ab.AtomicBroadcast_BroadcastServer) error {
 			config, configSeq, err := processor.ProcessConfigUpdateMsg(msg)
 			if err != nil {
 				logger.Warningf("[channel: %s] Rejecting broadcast of config message from %s because of error: %s", chdr.ChannelId, addr, err)
+				bh.recorder.Record(audit.CreatorOf(msg), "ConfigUpdate", chdr.ChannelId, err)
 				return srv.Send(&ab.BroadcastResponse{Status: ClassifyError(err), Info: err.Error()})
 			}
 
 			err = processor.Configure(config, configSeq)
 			if err != nil {
+				bh.recorder.Record(audit.CreatorOf(msg), "ConfigUpdate", chdr.ChannelId, err)
 				logger.Warningf("[channel: %s] Rejecting broadcast of config message from %s with SERVICE_UNAVAILABLE: rejected by Configure: %s", chdr.ChannelId, addr, err)
 				return srv.Send(&ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()})
 			}
+			// Configure only enqueues the config update for ordering, which
+			// may still invalidate it, so its outcome is not known yet
+			bh.recorder.RecordSubmitted(audit.CreatorOf(msg), "ConfigUpdate", chdr.ChannelId)
 		}
 
 		logger.Debugf("[channel: %s] Broadcast has successfully enqueued message of type %s from %s", chdr.ChannelId, cb.HeaderType_name[chdr.Type], addr)
@@ -16,7 +16,9 @@ import (
 	"justledger/common/flogging"
 	"justledger/orderer/common/msgprocessor"
 	cb "justledger/protos/common"
+	mspproto "justledger/protos/msp"
 	ab "justledger/protos/orderer"
+	"justledger/protos/utils"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
@@ -154,7 +156,7 @@ func getMockSupportManager() *mockSupportManager {
 
 func TestEnqueueFailure(t *testing.T) {
 	mm := getMockSupportManager()
-	bh := NewHandlerImpl(mm)
+	bh := NewHandlerImpl(mm, nil)
 	m := newMockB()
 	defer close(m.recvChan)
 	done := make(chan struct{})
@@ -203,7 +205,7 @@ func TestClassifyError(t *testing.T) {
 func TestBadChannelId(t *testing.T) {
 	mm := getMockSupportManager()
 	mm.MsgProcessorVal = &mockSupport{ProcessErr: msgprocessor.ErrChannelDoesNotExist}
-	bh := NewHandlerImpl(mm)
+	bh := NewHandlerImpl(mm, nil)
 	m := newMockB()
 	defer close(m.recvChan)
 	done := make(chan struct{})
@@ -228,7 +230,7 @@ func TestBadChannelId(t *testing.T) {
 func TestGoodConfigUpdate(t *testing.T) {
 	mm := getMockSupportManager()
 	mm.MsgProcessorIsConfig = true
-	bh := NewHandlerImpl(mm)
+	bh := NewHandlerImpl(mm, nil)
 	m := newMockB()
 	defer close(m.recvChan)
 	go bh.Handle(m)
@@ -242,7 +244,7 @@ func TestBadConfigUpdate(t *testing.T) {
 	mm := getMockSupportManager()
 	mm.MsgProcessorIsConfig = true
 	mm.MsgProcessorVal.ProcessErr = fmt.Errorf("Error")
-	bh := NewHandlerImpl(mm)
+	bh := NewHandlerImpl(mm, nil)
 	m := newMockB()
 	defer close(m.recvChan)
 	go bh.Handle(m)
@@ -252,8 +254,106 @@ func TestBadConfigUpdate(t *testing.T) {
 	assert.NotEqual(t, cb.Status_SUCCESS, reply.Status, "Should have rejected CONFIG_UPDATE")
 }
 
+type recordedOp struct {
+	creator   []byte
+	action    string
+	target    string
+	err       error
+	submitted bool
+}
+
+type mockRecorder struct {
+	ops chan recordedOp
+}
+
+func (r *mockRecorder) Record(creator []byte, action, target string, err error) {
+	r.ops <- recordedOp{creator: creator, action: action, target: target, err: err}
+}
+
+func (r *mockRecorder) RecordSubmitted(creator []byte, action, target string) {
+	r.ops <- recordedOp{creator: creator, action: action, target: target, submitted: true}
+}
+
+func TestAuditedConfigUpdate(t *testing.T) {
+	creator := utils.MarshalOrPanic(&mspproto.SerializedIdentity{Mspid: "SampleOrg", IdBytes: []byte("cert")})
+	env := &cb.Envelope{
+		Payload: utils.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{Creator: creator}),
+			},
+		}),
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mm := getMockSupportManager()
+		mm.MsgProcessorIsConfig = true
+		mm.ChdrVal.ChannelId = "mychannel"
+		recorder := &mockRecorder{ops: make(chan recordedOp, 1)}
+		bh := NewHandlerImpl(mm, recorder)
+		m := newMockB()
+		defer close(m.recvChan)
+		go bh.Handle(m)
+
+		m.recvChan <- env
+		reply := <-m.sendChan
+		assert.Equal(t, cb.Status_SUCCESS, reply.Status)
+		op := <-recorder.ops
+		assert.Equal(t, recordedOp{creator: creator, action: "ConfigUpdate", target: "mychannel", submitted: true}, op)
+	})
+
+	t.Run("NotEnqueued", func(t *testing.T) {
+		mm := getMockSupportManager()
+		mm.MsgProcessorIsConfig = true
+		mm.MsgProcessorVal.rejectEnqueue = true
+		mm.ChdrVal.ChannelId = "mychannel"
+		recorder := &mockRecorder{ops: make(chan recordedOp, 1)}
+		bh := NewHandlerImpl(mm, recorder)
+		m := newMockB()
+		defer close(m.recvChan)
+		go bh.Handle(m)
+
+		m.recvChan <- env
+		reply := <-m.sendChan
+		assert.Equal(t, cb.Status_SERVICE_UNAVAILABLE, reply.Status)
+		op := <-recorder.ops
+		assert.Equal(t, recordedOp{creator: creator, action: "ConfigUpdate", target: "mychannel", err: fmt.Errorf("Reject")}, op)
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		mm := getMockSupportManager()
+		mm.MsgProcessorIsConfig = true
+		mm.MsgProcessorVal.ProcessErr = fmt.Errorf("Error")
+		mm.ChdrVal.ChannelId = "mychannel"
+		recorder := &mockRecorder{ops: make(chan recordedOp, 1)}
+		bh := NewHandlerImpl(mm, recorder)
+		m := newMockB()
+		defer close(m.recvChan)
+		go bh.Handle(m)
+
+		m.recvChan <- env
+		reply := <-m.sendChan
+		assert.NotEqual(t, cb.Status_SUCCESS, reply.Status)
+		op := <-recorder.ops
+		assert.Equal(t, recordedOp{creator: creator, action: "ConfigUpdate", target: "mychannel", err: mm.MsgProcessorVal.ProcessErr}, op)
+	})
+
+	t.Run("NormalMessage", func(t *testing.T) {
+		mm := getMockSupportManager()
+		recorder := &mockRecorder{ops: make(chan recordedOp, 1)}
+		bh := NewHandlerImpl(mm, recorder)
+		m := newMockB()
+		defer close(m.recvChan)
+		go bh.Handle(m)
+
+		m.recvChan <- env
+		reply := <-m.sendChan
+		assert.Equal(t, cb.Status_SUCCESS, reply.Status)
+		assert.Len(t, recorder.ops, 0)
+	})
+}
+
 func TestGracefulShutdown(t *testing.T) {
-	bh := NewHandlerImpl(nil)
+	bh := NewHandlerImpl(nil, nil)
 	m := newMockB()
 	close(m.recvChan)
 	assert.NoError(t, bh.Handle(m), "Should exit normally upon EOF")
@@ -264,7 +364,7 @@ func TestRejected(t *testing.T) {
 		MsgProcessorVal: &mockSupport{ProcessErr: fmt.Errorf("Reject")},
 		ChdrVal:         &cb.ChannelHeader{},
 	}
-	bh := NewHandlerImpl(mm)
+	bh := NewHandlerImpl(mm, nil)
 	m := newMockB()
 	defer close(m.recvChan)
 	go bh.Handle(m)
@@ -276,13 +376,13 @@ func TestRejected(t *testing.T) {
 }
 
 func TestBadStreamRecv(t *testing.T) {
-	bh := NewHandlerImpl(nil)
+	bh := NewHandlerImpl(nil, nil)
 	assert.Error(t, bh.Handle(&erroneousRecvMockB{}), "Should catch unexpected stream error")
 }
 
 func TestBadStreamSend(t *testing.T) {
 	mm := getMockSupportManager()
-	bh := NewHandlerImpl(mm)
+	bh := NewHandlerImpl(mm, nil)
 	m := &erroneousSendMockB{recvVal: nil}
 	assert.Error(t, bh.Handle(m), "Should catch unexpected stream error")
 }
@@ -291,7 +391,7 @@ func TestMalformedHeader(t *testing.T) {
 	mm := getMockSupportManager()
 	mm.ChdrVal = nil
 	mm.MsgProcessorErr = errors.New("Mocked Error")
-	bh := NewHandlerImpl(mm)
+	bh := NewHandlerImpl(mm, nil)
 	m := newMockB()
 	defer close(m.recvChan)
 	done := make(chan struct{})
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package cluster_test
 
 import (
+	"crypto/tls"
 	"crypto/x509"
 	"sync"
 	"testing"
@@ -74,6 +75,23 @@ func TestDialerCustomKeepAliveOptions(t *testing.T) {
 	assert.Equal(t, time.Second*12345, timeout)
 }
 
+func TestDialerClientCertificateCallback(t *testing.T) {
+	t.Parallel()
+	clientCert := &tls.Certificate{}
+	dialer := cluster.NewTLSPinningDialer(comm.ClientConfig{
+		SecOpts: &comm.SecureOptions{
+			UseTLS:            true,
+			RequireClientCert: true,
+			GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				return clientCert, nil
+			},
+		},
+	})
+	cert, err := dialer.Config.Load().(comm.ClientConfig).SecOpts.GetClientCertificate(&tls.CertificateRequestInfo{})
+	assert.NoError(t, err)
+	assert.True(t, cert == clientCert)
+}
+
 func TestDialerBadConfig(t *testing.T) {
 	t.Parallel()
 	emptyCertificate := []byte("-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----")
@@ -33,6 +33,7 @@ type TopLevel struct {
 	FileLedger FileLedger
 	RAMLedger  RAMLedger
 	Kafka      Kafka
+	Metrics    Metrics
 	Debug      Debug
 }
 
@@ -54,6 +55,22 @@ type General struct {
 	LocalMSPID     string
 	BCCSP          *bccsp.FactoryOpts
 	Authentication Authentication
+	CertExpiration CertExpiration
+	Audit          Audit
+}
+
+// Audit contains configuration for the audit log of administrative
+// operations, such as channel config updates.
+type Audit struct {
+	// File is the path of the audit log. If empty, no audit log is kept.
+	File string
+}
+
+// CertExpiration contains configuration for monitoring the expiration
+// of the certificates of the orderer.
+type CertExpiration struct {
+	WarningThresholds []time.Duration
+	CheckInterval     time.Duration
 }
 
 // Keepalive contains configuration for gRPC servers.
@@ -71,6 +88,7 @@ type TLS struct {
 	RootCAs            []string
 	ClientAuthRequired bool
 	ClientRootCAs      []string
+	WatchInterval      time.Duration
 }
 
 // SASLPlain contains configuration for SASL/PLAIN authentication
@@ -162,6 +180,28 @@ type Topic struct {
 }
 
 // Debug contains configuration for the orderer's debug parameters.
+// Metrics contains configuration for the metrics reported by the orderer.
+type Metrics struct {
+	Enabled bool
+	// Reporter is either "statsd" or "prom"
+	Reporter       string
+	Interval       time.Duration
+	StatsdReporter StatsdReporter
+	PromReporter   PromReporter
+}
+
+// StatsdReporter contains configuration for pushing the metrics to a statsd server.
+type StatsdReporter struct {
+	Address       string
+	FlushInterval time.Duration
+	FlushBytes    int
+}
+
+// PromReporter contains configuration for serving the metrics to prometheus.
+type PromReporter struct {
+	ListenAddress string
+}
+
 type Debug struct {
 	BroadcastTraceDir string
 	DeliverTraceDir   string
@@ -189,6 +229,10 @@ var Defaults = TopLevel{
 		Authentication: Authentication{
 			TimeWindow: time.Duration(15 * time.Minute),
 		},
+		CertExpiration: CertExpiration{
+			WarningThresholds: []time.Duration{720 * time.Hour, 168 * time.Hour, 24 * time.Hour},
+			CheckInterval:     time.Hour,
+		},
 	},
 	RAMLedger: RAMLedger{
 		HistorySize: 10000,
@@ -229,6 +273,19 @@ var Defaults = TopLevel{
 			ReplicationFactor: 3,
 		},
 	},
+	Metrics: Metrics{
+		Enabled:  false,
+		Reporter: "statsd",
+		Interval: time.Second,
+		StatsdReporter: StatsdReporter{
+			Address:       "0.0.0.0:8125",
+			FlushInterval: 2 * time.Second,
+			FlushBytes:    1432,
+		},
+		PromReporter: PromReporter{
+			ListenAddress: "0.0.0.0:8080",
+		},
+	},
 	Debug: Debug{
 		BroadcastTraceDir: "",
 		DeliverTraceDir:   "",
@@ -267,6 +324,9 @@ func (c *TopLevel) completeInitialization(configDir string) {
 		coreconfig.TranslatePathInPlace(configDir, &c.General.TLS.Certificate)
 		coreconfig.TranslatePathInPlace(configDir, &c.General.GenesisFile)
 		coreconfig.TranslatePathInPlace(configDir, &c.General.LocalMSPDir)
+		if c.General.Audit.File != "" {
+			coreconfig.TranslatePathInPlace(configDir, &c.General.Audit.File)
+		}
 	}()
 
 	for {
@@ -325,6 +385,13 @@ func (c *TopLevel) completeInitialization(configDir string) {
 			logger.Infof("General.Authentication.TimeWindow unset, setting to %s", Defaults.General.Authentication.TimeWindow)
 			c.General.Authentication.TimeWindow = Defaults.General.Authentication.TimeWindow
 
+		case len(c.General.CertExpiration.WarningThresholds) == 0:
+			logger.Infof("General.CertExpiration.WarningThresholds unset, setting to %v", Defaults.General.CertExpiration.WarningThresholds)
+			c.General.CertExpiration.WarningThresholds = Defaults.General.CertExpiration.WarningThresholds
+		case c.General.CertExpiration.CheckInterval == 0:
+			logger.Infof("General.CertExpiration.CheckInterval unset, setting to %s", Defaults.General.CertExpiration.CheckInterval)
+			c.General.CertExpiration.CheckInterval = Defaults.General.CertExpiration.CheckInterval
+
 		case c.FileLedger.Prefix == "":
 			logger.Infof("FileLedger.Prefix unset, setting to %s", Defaults.FileLedger.Prefix)
 			c.FileLedger.Prefix = Defaults.FileLedger.Prefix
@@ -374,6 +441,19 @@ func (c *TopLevel) completeInitialization(configDir string) {
 			logger.Infof("Kafka.Version unset, setting to %v", Defaults.Kafka.Version)
 			c.Kafka.Version = Defaults.Kafka.Version
 
+		case c.Metrics.Reporter == "":
+			logger.Infof("Metrics.Reporter unset, setting to %s", Defaults.Metrics.Reporter)
+			c.Metrics.Reporter = Defaults.Metrics.Reporter
+		case c.Metrics.Interval == 0:
+			logger.Infof("Metrics.Interval unset, setting to %s", Defaults.Metrics.Interval)
+			c.Metrics.Interval = Defaults.Metrics.Interval
+		case c.Metrics.StatsdReporter.FlushInterval == 0:
+			logger.Infof("Metrics.StatsdReporter.FlushInterval unset, setting to %s", Defaults.Metrics.StatsdReporter.FlushInterval)
+			c.Metrics.StatsdReporter.FlushInterval = Defaults.Metrics.StatsdReporter.FlushInterval
+		case c.Metrics.StatsdReporter.FlushBytes == 0:
+			logger.Infof("Metrics.StatsdReporter.FlushBytes unset, setting to %d", Defaults.Metrics.StatsdReporter.FlushBytes)
+			c.Metrics.StatsdReporter.FlushBytes = Defaults.Metrics.StatsdReporter.FlushBytes
+
 		default:
 			return
 		}
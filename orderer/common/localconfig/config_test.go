@@ -138,3 +138,39 @@ func TestSystemChannel(t *testing.T) {
 	assert.Equal(t, Defaults.General.SystemChannel, conf.General.SystemChannel,
 		"Expected default system channel ID to be '%s', got '%s' instead", Defaults.General.SystemChannel, conf.General.SystemChannel)
 }
+
+func TestCertExpiration(t *testing.T) {
+	cleanup := configtest.SetDevFabricConfigPath(t)
+	defer cleanup()
+	conf, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{720 * time.Hour, 168 * time.Hour, 24 * time.Hour}, conf.General.CertExpiration.WarningThresholds)
+	assert.Equal(t, time.Hour, conf.General.CertExpiration.CheckInterval)
+
+	conf = &TopLevel{}
+	conf.completeInitialization("/dummy/path")
+	assert.Equal(t, Defaults.General.CertExpiration, conf.General.CertExpiration)
+}
+
+func TestTLSWatchInterval(t *testing.T) {
+	cleanup := configtest.SetDevFabricConfigPath(t)
+	defer cleanup()
+	conf, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, conf.General.TLS.WatchInterval)
+}
+
+func TestMetrics(t *testing.T) {
+	cleanup := configtest.SetDevFabricConfigPath(t)
+	defer cleanup()
+	conf, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, Defaults.Metrics, conf.Metrics)
+
+	conf = &TopLevel{}
+	conf.completeInitialization("/dummy/path")
+	assert.Equal(t, Defaults.Metrics.Reporter, conf.Metrics.Reporter)
+	assert.Equal(t, Defaults.Metrics.Interval, conf.Metrics.Interval)
+	assert.Equal(t, Defaults.Metrics.StatsdReporter.FlushInterval, conf.Metrics.StatsdReporter.FlushInterval)
+	assert.Equal(t, Defaults.Metrics.StatsdReporter.FlushBytes, conf.Metrics.StatsdReporter.FlushBytes)
+}
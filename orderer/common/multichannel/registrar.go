@@ -11,6 +11,7 @@ package multichannel
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	"justledger/common/channelconfig"
@@ -303,6 +304,19 @@ func (r *Registrar) ChannelsCount() int {
 	return len(r.chains)
 }
 
+// ChannelIDs returns the IDs of the current channels, sorted.
+func (r *Registrar) ChannelIDs() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	channelIDs := make([]string, 0, len(r.chains))
+	for channelID := range r.chains {
+		channelIDs = append(channelIDs, channelID)
+	}
+	sort.Strings(channelIDs)
+	return channelIDs
+}
+
 // NewChannelConfig produces a new template channel configuration based on the system channel's current config.
 func (r *Registrar) NewChannelConfig(envConfigUpdate *cb.Envelope) (channelconfig.Resources, error) {
 	return r.templator.NewChannelConfig(envConfigUpdate)
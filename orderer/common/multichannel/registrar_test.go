@@ -144,6 +144,7 @@ func TestManagerImpl(t *testing.T) {
 
 	chainSupport, ok := manager.GetChain(genesisconfig.TestChainID)
 	assert.True(t, ok, "Should have gotten chain which was initialized by ramledger")
+	assert.Equal(t, []string{genesisconfig.TestChainID}, manager.ChannelIDs())
 
 	messages := make([]*cb.Envelope, conf.Orderer.BatchSize.MaxMessageCount)
 	for i := 0; i < int(conf.Orderer.BatchSize.MaxMessageCount); i++ {
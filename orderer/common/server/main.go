@@ -5,6 +5,7 @@ package server
 
 import (
 	"bytes"
+	"crypto/tls"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -12,12 +13,16 @@ import (
 	_ "net/http/pprof" // This is essentially the main package for the orderer
 
 	"os"
+	"strings"
 	"time"
 
+	"justledger/common/audit"
+	"justledger/common/certmonitor"
 	"justledger/common/channelconfig"
 	"justledger/common/crypto"
 	"justledger/common/flogging"
 	"justledger/common/ledger/blockledger"
+	"justledger/common/metrics"
 	"justledger/common/tools/configtxgen/encoder"
 	genesisconfig "justledger/common/tools/configtxgen/localconfig"
 	"justledger/core/comm"
@@ -80,6 +85,10 @@ func Start(cmd string, conf *localconfig.TopLevel) {
 	signer := localmsp.NewSigner()
 	serverConfig := initializeServerConfig(conf)
 	grpcServer := initializeGrpcServer(conf, serverConfig)
+	if grpcServer.TLSEnabled() {
+		// the TLS key pair of the orderer is also used by its clients
+		comm.GetCredentialSupport().SetClientCertificate(grpcServer.ServerCertificate())
+	}
 	caSupport := &comm.CASupport{
 		AppRootCAsByChain:     make(map[string][][]byte),
 		OrdererRootCAsByChain: make(map[string][][]byte),
@@ -95,12 +104,16 @@ func Start(cmd string, conf *localconfig.TopLevel) {
 
 	manager := initializeMultichannelRegistrar(conf, signer, tlsCallback)
 	mutualTLS := serverConfig.SecOpts.UseTLS && serverConfig.SecOpts.RequireClientCert
-	server := NewServer(manager, signer, &conf.Debug, conf.General.Authentication.TimeWindow, mutualTLS)
+	server := NewServer(manager, signer, &conf.Debug, conf.General.Authentication.TimeWindow, mutualTLS, initializeAuditLog(conf))
 
 	switch cmd {
 	case start.FullCommand(): // "start" command
 		logger.Infof("Starting %s", metadata.GetVersionInfo())
 		initializeProfilingService(conf)
+		initializeCertMonitor(conf, manager, initializeMetrics(conf)).Start()
+		if keyPairWatcher := initializeKeyPairWatcher(conf, grpcServer); keyPairWatcher != nil {
+			keyPairWatcher.Start()
+		}
 		ab.RegisterAtomicBroadcastServer(grpcServer.Server(), server)
 		logger.Info("Beginning to serve requests")
 		grpcServer.Start()
@@ -190,6 +203,21 @@ func initializeServerConfig(conf *localconfig.TopLevel) comm.ServerConfig {
 	return comm.ServerConfig{SecOpts: secureOpts, KaOpts: kaOpts}
 }
 
+// initializeKeyPairWatcher returns a watcher which hands the TLS key pair of the orderer to
+// the gRPC server and to the gRPC and Kafka clients when its files change, or nil if TLS is
+// disabled or General.TLS.WatchInterval is unset
+func initializeKeyPairWatcher(conf *localconfig.TopLevel, grpcServer *comm.GRPCServer) *comm.KeyPairWatcher {
+	if !conf.General.TLS.Enabled || conf.General.TLS.WatchInterval <= 0 {
+		return nil
+	}
+	watcher, err := comm.NewKeyPairWatcher(conf.General.TLS.Certificate, conf.General.TLS.PrivateKey,
+		conf.General.TLS.WatchInterval, grpcServer.SetServerCertificate, comm.GetCredentialSupport().SetClientCertificate)
+	if err != nil {
+		logger.Fatalf("Failed watching the TLS key pair: %s", err)
+	}
+	return watcher
+}
+
 func initializeBootstrapChannel(conf *localconfig.TopLevel, lf blockledger.Factory) {
 	var genesisBlock *cb.Block
 
@@ -253,11 +281,133 @@ func initializeMultichannelRegistrar(conf *localconfig.TopLevel, signer crypto.L
 
 	consenters := make(map[string]consensus.Consenter)
 	consenters["solo"] = solo.New()
-	consenters["kafka"] = kafka.New(conf.Kafka)
+	consenters["kafka"] = kafka.New(conf.Kafka, kafkaClientCertificate(conf))
 
 	return multichannel.NewRegistrar(lf, consenters, signer, callbacks...)
 }
 
+// kafkaClientCertificate returns the callback supplying the TLS client certificate of the
+// connections to the Kafka brokers, which follows the changes of the TLS key pair of the
+// orderer if the brokers are authenticated to with that key pair, or nil if they are
+// authenticated to with a separate key pair, which is then used as is
+func kafkaClientCertificate(conf *localconfig.TopLevel) func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	if !conf.General.TLS.Enabled || !conf.Kafka.TLS.Enabled {
+		return nil
+	}
+	certPEM, err := ioutil.ReadFile(conf.General.TLS.Certificate)
+	if err != nil {
+		logger.Warningf("Failed reading the TLS certificate of the orderer: %s", err)
+		return nil
+	}
+	if strings.TrimSpace(string(certPEM)) != strings.TrimSpace(conf.Kafka.TLS.Certificate) {
+		logger.Infof("Kafka.TLS.Certificate is not the TLS certificate of the orderer, changes of the latter won't be used to connect to Kafka")
+		return nil
+	}
+	return comm.GetCredentialSupport().ClientCertificateCallback()
+}
+
+// initializeAuditLog returns the recorder of administrative operations, which
+// appends them to the configured audit log file, if any
+func initializeAuditLog(conf *localconfig.TopLevel) audit.Recorder {
+	if conf.General.Audit.File == "" {
+		return audit.NoopRecorder{}
+	}
+	auditLog, err := audit.NewLog(conf.General.Audit.File)
+	if err != nil {
+		logger.Fatalf("Failed opening the audit log: %s", err)
+	}
+	return auditLog
+}
+
+// initializeMetrics initializes the metrics of the orderer, starts reporting
+// them if they are enabled and returns their root scope
+func initializeMetrics(conf *localconfig.TopLevel) metrics.Scope {
+	opts := metrics.Opts{
+		Enabled:  conf.Metrics.Enabled,
+		Reporter: conf.Metrics.Reporter,
+		Interval: conf.Metrics.Interval,
+		StatsdReporterOpts: metrics.StatsdReporterOpts{
+			Address:       conf.Metrics.StatsdReporter.Address,
+			FlushInterval: conf.Metrics.StatsdReporter.FlushInterval,
+			FlushBytes:    conf.Metrics.StatsdReporter.FlushBytes,
+		},
+		PromReporterOpts: metrics.PromReporterOpts{
+			ListenAddress: conf.Metrics.PromReporter.ListenAddress,
+		},
+	}
+	if err := metrics.Init(opts); err != nil {
+		logger.Fatalf("Failed initializing metrics: %s", err)
+	}
+	if conf.Metrics.Enabled {
+		go func() {
+			if err := metrics.Start(); err != nil {
+				logger.Errorf("Error starting metrics server: %s", err)
+			}
+		}()
+	}
+	return metrics.RootScope
+}
+
+// initializeCertMonitor returns a monitor of the expiration of the signing certificate of the
+// local MSP, the TLS certificates and the certificates of the MSPs of the channels, which
+// reports the days left before they expire to metricsScope
+func initializeCertMonitor(conf *localconfig.TopLevel, manager *multichannel.Registrar, metricsScope metrics.Scope) *certmonitor.Monitor {
+	localCerts := func() []certmonitor.Certificate {
+		certs, err := localCertificates(conf)
+		if err != nil {
+			logger.Warningf("Failed checking certificate expiration: %s", err)
+		}
+		return certs
+	}
+	channelCerts := func() []certmonitor.Certificate {
+		var certs []certmonitor.Certificate
+		for _, channelID := range manager.ChannelIDs() {
+			cs, ok := manager.GetChain(channelID)
+			if !ok {
+				continue
+			}
+			channelCerts, err := certmonitor.FromChannelConfig(channelID, cs.ConfigtxValidator().ConfigProto())
+			if err != nil {
+				logger.Warningf("Failed checking certificate expiration: %s", err)
+				continue
+			}
+			certs = append(certs, channelCerts...)
+		}
+		return certs
+	}
+	monitorConf := certmonitor.Config{
+		WarningThresholds: conf.General.CertExpiration.WarningThresholds,
+		CheckInterval:     conf.General.CertExpiration.CheckInterval,
+	}
+	return certmonitor.NewMonitor(monitorConf, metricsScope, localCerts, channelCerts)
+}
+
+func localCertificates(conf *localconfig.TopLevel) ([]certmonitor.Certificate, error) {
+	serializedSigner, err := mspmgmt.GetLocalSigningIdentityOrPanic().Serialize()
+	if err != nil {
+		return nil, err
+	}
+	certs, err := certmonitor.FromSerializedIdentity(certmonitor.KindEnrollment, "", serializedSigner)
+	if err != nil {
+		return nil, err
+	}
+	if conf.General.TLS.Enabled {
+		tlsCerts, err := certmonitor.FromFile(certmonitor.KindTLSServer, "", "", conf.General.TLS.Certificate)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, tlsCerts...)
+	}
+	if conf.Kafka.TLS.Enabled {
+		kafkaCerts, err := certmonitor.FromPEM(certmonitor.KindTLSClient, "", "", []byte(conf.Kafka.TLS.Certificate))
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, kafkaCerts...)
+	}
+	return certs, nil
+}
+
 func updateTrustedRoots(srv *comm.GRPCServer, rootCASupport *comm.CASupport,
 	cm channelconfig.Resources) {
 	rootCASupport.Lock()
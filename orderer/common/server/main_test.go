@@ -4,9 +4,11 @@
 package server
 
 import (
+	"crypto/tls"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -14,10 +16,13 @@ import (
 	"time"
 
 	"justledger/bccsp/factory"
+	"justledger/common/certmonitor"
 	"justledger/common/channelconfig"
+	"justledger/common/crypto/tlsgen"
 	"justledger/common/flogging"
 	"justledger/common/flogging/floggingtest"
 	"justledger/common/localmsp"
+	"justledger/common/metrics"
 	genesisconfig "justledger/common/tools/configtxgen/localconfig"
 	"justledger/core/comm"
 	"justledger/core/config/configtest"
@@ -237,6 +242,157 @@ func TestInitializeMultiChainManager(t *testing.T) {
 	})
 }
 
+func TestInitializeCertMonitor(t *testing.T) {
+	cleanup := configtest.SetDevFabricConfigPath(t)
+	defer cleanup()
+	conf := genesisConfig(t)
+	initializeLocalMsp(conf)
+	manager := initializeMultichannelRegistrar(conf, localmsp.NewSigner())
+
+	conf.General.TLS.Enabled = true
+	conf.General.TLS.Certificate = filepath.Join(conf.General.LocalMSPDir, "signcerts", "peer.pem")
+	metricsScope := &fakeScope{gauges: make(map[string]float64)}
+	statuses := initializeCertMonitor(conf, manager, metricsScope).Check(time.Now())
+	kinds := make(map[string]bool)
+	for _, s := range statuses {
+		kinds[s.Channel+"/"+s.Kind] = true
+	}
+	assert.True(t, kinds["/"+certmonitor.KindEnrollment])
+	assert.True(t, kinds["/"+certmonitor.KindTLSServer])
+	assert.True(t, kinds[genesisconfig.TestChainID+"/"+certmonitor.KindCA])
+	assert.True(t, kinds[genesisconfig.TestChainID+"/"+certmonitor.KindAdmin])
+	assert.Len(t, metricsScope.gauges, len(statuses))
+
+	conf.General.TLS.Certificate = "missing.pem"
+	statuses = initializeCertMonitor(conf, manager, metricsScope).Check(time.Now())
+	for _, s := range statuses {
+		assert.NotEmpty(t, s.Channel, "local certificates should be skipped on error")
+	}
+}
+
+func TestInitializeMetrics(t *testing.T) {
+	conf := &localconfig.TopLevel{
+		Metrics: localconfig.Metrics{Enabled: false},
+	}
+	assert.NotNil(t, initializeMetrics(conf))
+}
+
+type fakeScope struct {
+	metrics.Scope
+	tags   string
+	gauges map[string]float64
+}
+
+func (s *fakeScope) SubScope(prefix string) metrics.Scope {
+	return s
+}
+
+func (s *fakeScope) Tagged(tags map[string]string) metrics.Scope {
+	return &fakeScope{tags: tags["channel"] + "/" + tags["kind"] + "/" + tags["serial"], gauges: s.gauges}
+}
+
+func (s *fakeScope) Gauge(name string) metrics.Gauge {
+	return &fakeGauge{name: s.tags + "/" + name, gauges: s.gauges}
+}
+
+type fakeGauge struct {
+	name   string
+	gauges map[string]float64
+}
+
+func (g *fakeGauge) Update(v float64) {
+	g.gauges[g.name] = v
+}
+
+func TestInitializeKeyPairWatcher(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "keypairwatcher")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	ca, err := tlsgen.NewCA()
+	assert.NoError(t, err)
+	writeKeyPair := func() *tlsgen.CertKeyPair {
+		kp, err := ca.NewServerCertKeyPair("127.0.0.1")
+		assert.NoError(t, err)
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "server.crt"), kp.Cert, 0600))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "server.key"), kp.Key, 0600))
+		return kp
+	}
+	writeKeyPair()
+
+	conf := &localconfig.TopLevel{
+		General: localconfig.General{
+			ListenAddress: "127.0.0.1",
+			TLS: localconfig.TLS{
+				Enabled:     true,
+				Certificate: filepath.Join(tempDir, "server.crt"),
+				PrivateKey:  filepath.Join(tempDir, "server.key"),
+			},
+		},
+	}
+	grpcServer := initializeGrpcServer(conf, initializeServerConfig(conf))
+	defer grpcServer.Listener().Close()
+
+	assert.Nil(t, initializeKeyPairWatcher(conf, grpcServer))
+
+	conf.General.TLS.WatchInterval = time.Minute
+	watcher := initializeKeyPairWatcher(conf, grpcServer)
+	assert.NotNil(t, watcher)
+	kp := writeKeyPair()
+	changed, err := watcher.Check()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, kp.TLSCert.Raw, grpcServer.ServerCertificate().Certificate[0])
+	assert.Equal(t, kp.TLSCert.Raw, comm.GetCredentialSupport().GetClientCertificate().Certificate[0])
+}
+
+func TestKafkaClientCertificate(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "kafkaclientcert")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	ca, err := tlsgen.NewCA()
+	assert.NoError(t, err)
+	kp, err := ca.NewClientCertKeyPair()
+	assert.NoError(t, err)
+	otherKP, err := ca.NewClientCertKeyPair()
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "server.crt"), kp.Cert, 0600))
+
+	conf := &localconfig.TopLevel{
+		General: localconfig.General{
+			TLS: localconfig.TLS{
+				Enabled:     true,
+				Certificate: filepath.Join(tempDir, "server.crt"),
+			},
+		},
+		Kafka: localconfig.Kafka{
+			TLS: localconfig.TLS{
+				Enabled:     true,
+				Certificate: string(kp.Cert),
+			},
+		},
+	}
+	clientCert, err := tls.X509KeyPair(kp.Cert, kp.Key)
+	assert.NoError(t, err)
+	comm.GetCredentialSupport().SetClientCertificate(clientCert)
+	getClientCertificate := kafkaClientCertificate(conf)
+	assert.NotNil(t, getClientCertificate)
+	cert, err := getClientCertificate(&tls.CertificateRequestInfo{})
+	assert.NoError(t, err)
+	assert.Equal(t, kp.TLSCert.Raw, cert.Certificate[0])
+
+	conf.Kafka.TLS.Certificate = string(otherKP.Cert)
+	assert.Nil(t, kafkaClientCertificate(conf))
+
+	conf.Kafka.TLS.Certificate = string(kp.Cert)
+	conf.General.TLS.Certificate = filepath.Join(tempDir, "missing.crt")
+	assert.Nil(t, kafkaClientCertificate(conf))
+
+	conf.Kafka.TLS.Enabled = false
+	assert.Nil(t, kafkaClientCertificate(conf))
+}
+
 func TestInitializeGrpcServer(t *testing.T) {
 	// get a free random port
 	listenAddr := func() string {
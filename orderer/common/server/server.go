@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"justledger/common/audit"
 	"justledger/common/crypto"
 	"justledger/common/deliver"
 	"justledger/common/policies"
@@ -60,18 +61,19 @@ func (rs *responseSender) SendStatusResponse(status cb.Status) error {
 	return rs.Send(reply)
 }
 
-func (rs *responseSender) SendBlockResponse(block *cb.Block) error {
+func (rs *responseSender) SendBlockResponse(block *cb.Block, channelID string, chain deliver.Chain, envelope *cb.Envelope) error {
 	response := &ab.DeliverResponse{
 		Type: &ab.DeliverResponse_Block{Block: block},
 	}
 	return rs.Send(response)
 }
 
-// NewServer creates an ab.AtomicBroadcastServer based on the broadcast target and ledger Reader
-func NewServer(r *multichannel.Registrar, _ crypto.LocalSigner, debug *localconfig.Debug, timeWindow time.Duration, mutualTLS bool) ab.AtomicBroadcastServer {
+// NewServer creates an ab.AtomicBroadcastServer based on the broadcast target and ledger Reader.
+// Config updates are recorded to the given audit recorder.
+func NewServer(r *multichannel.Registrar, _ crypto.LocalSigner, debug *localconfig.Debug, timeWindow time.Duration, mutualTLS bool, recorder audit.Recorder) ab.AtomicBroadcastServer {
 	s := &server{
 		dh:        deliver.NewHandler(deliverSupport{Registrar: r}, timeWindow, mutualTLS),
-		bh:        broadcast.NewHandlerImpl(broadcastSupport{Registrar: r}),
+		bh:        broadcast.NewHandlerImpl(broadcastSupport{Registrar: r}, recorder),
 		debug:     debug,
 		Registrar: r,
 	}
@@ -14,8 +14,13 @@ import (
 	localconfig "justledger/orderer/common/localconfig"
 )
 
+// newBrokerConfig creates the configuration of the connections to the Kafka
+// brokers. If getClientCertificate is not nil, it supplies the client
+// certificate of each TLS handshake instead of the key pair of tlsConfig, so
+// that the certificate can be replaced without a restart
 func newBrokerConfig(
 	tlsConfig localconfig.TLS,
+	getClientCertificate func(*tls.CertificateRequestInfo) (*tls.Certificate, error),
 	saslPlain localconfig.SASLPlain,
 	retryOptions localconfig.Retry,
 	kafkaVersion sarama.KafkaVersion,
@@ -58,6 +63,10 @@ func newBrokerConfig(
 			MinVersion:   tls.VersionTLS12,
 			MaxVersion:   0, // Latest supported TLS version
 		}
+		if getClientCertificate != nil {
+			brokerConfig.Net.TLS.Config.Certificates = nil
+			brokerConfig.Net.TLS.Config.GetClientCertificate = getClientCertificate
+		}
 	}
 	brokerConfig.Net.SASL.Enable = saslPlain.Enabled
 	if brokerConfig.Net.SASL.Enable {
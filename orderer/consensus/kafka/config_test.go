@@ -42,6 +42,7 @@ func TestBrokerConfig(t *testing.T) {
 	t.Run("Partitioner", func(t *testing.T) {
 		mockBrokerConfig2 := newBrokerConfig(
 			mockLocalConfig.General.TLS,
+			nil,
 			mockLocalConfig.Kafka.SASLPlain,
 			mockLocalConfig.Kafka.Retry,
 			mockLocalConfig.Kafka.Version,
@@ -89,7 +90,7 @@ func TestBrokerConfigTLSConfigEnabled(t *testing.T) {
 			PrivateKey:  privateKey,
 			Certificate: publicKey,
 			RootCAs:     []string{caPublicKey},
-		},
+		}, nil,
 			mockLocalConfig.Kafka.SASLPlain,
 			mockLocalConfig.Kafka.Retry,
 			mockLocalConfig.Kafka.Version,
@@ -103,13 +104,36 @@ func TestBrokerConfigTLSConfigEnabled(t *testing.T) {
 		assert.Equal(t, uint16(tls.VersionTLS12), testBrokerConfig.Net.TLS.Config.MinVersion)
 	})
 
+	t.Run("ClientCertificateCallback", func(t *testing.T) {
+		clientCert := &tls.Certificate{}
+		getClientCertificate := func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return clientCert, nil
+		}
+		testBrokerConfig := newBrokerConfig(localconfig.TLS{
+			Enabled:     true,
+			PrivateKey:  privateKey,
+			Certificate: publicKey,
+			RootCAs:     []string{caPublicKey},
+		}, getClientCertificate,
+			mockLocalConfig.Kafka.SASLPlain,
+			mockLocalConfig.Kafka.Retry,
+			mockLocalConfig.Kafka.Version,
+			defaultPartition)
+
+		assert.True(t, testBrokerConfig.Net.TLS.Enable)
+		assert.Empty(t, testBrokerConfig.Net.TLS.Config.Certificates)
+		cert, err := testBrokerConfig.Net.TLS.Config.GetClientCertificate(&tls.CertificateRequestInfo{})
+		assert.NoError(t, err)
+		assert.True(t, cert == clientCert)
+	})
+
 	t.Run("Disabled", func(t *testing.T) {
 		testBrokerConfig := newBrokerConfig(localconfig.TLS{
 			Enabled:     false,
 			PrivateKey:  privateKey,
 			Certificate: publicKey,
 			RootCAs:     []string{caPublicKey},
-		},
+		}, nil,
 			mockLocalConfig.Kafka.SASLPlain,
 			mockLocalConfig.Kafka.Retry,
 			mockLocalConfig.Kafka.Version,
@@ -131,7 +155,7 @@ func TestBrokerConfigTLSConfigBadCert(t *testing.T) {
 				PrivateKey:  privateKey,
 				Certificate: "TRASH",
 				RootCAs:     []string{caPublicKey},
-			},
+			}, nil,
 				mockLocalConfig.Kafka.SASLPlain,
 				mockLocalConfig.Kafka.Retry,
 				mockLocalConfig.Kafka.Version,
@@ -145,7 +169,7 @@ func TestBrokerConfigTLSConfigBadCert(t *testing.T) {
 				PrivateKey:  "TRASH",
 				Certificate: publicKey,
 				RootCAs:     []string{caPublicKey},
-			},
+			}, nil,
 				mockLocalConfig.Kafka.SASLPlain,
 				mockLocalConfig.Kafka.Retry,
 				mockLocalConfig.Kafka.Version,
@@ -159,7 +183,7 @@ func TestBrokerConfigTLSConfigBadCert(t *testing.T) {
 				PrivateKey:  privateKey,
 				Certificate: publicKey,
 				RootCAs:     []string{"TRASH"},
-			},
+			}, nil,
 				mockLocalConfig.Kafka.SASLPlain,
 				mockLocalConfig.Kafka.Retry,
 				mockLocalConfig.Kafka.Version,
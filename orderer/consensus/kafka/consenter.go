@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package kafka
 
 import (
+	"crypto/tls"
+
 	"github.com/Shopify/sarama"
 	localconfig "justledger/orderer/common/localconfig"
 	"justledger/orderer/consensus"
@@ -15,12 +17,15 @@ import (
 )
 
 // New creates a Kafka-based consenter. Called by orderer's main.go.
-func New(config localconfig.Kafka) consensus.Consenter {
+// getClientCertificate, which may be nil, supplies the TLS client certificate
+// used to connect to the brokers in place of the one in config.
+func New(config localconfig.Kafka, getClientCertificate func(*tls.CertificateRequestInfo) (*tls.Certificate, error)) consensus.Consenter {
 	if config.Verbose {
 		logging.SetLevel(logging.DEBUG, saramaLogID)
 	}
 	brokerConfig := newBrokerConfig(
 		config.TLS,
+		getClientCertificate,
 		config.SASLPlain,
 		config.Retry,
 		config.Version,
@@ -69,11 +69,11 @@ func init() {
 }
 
 func TestNew(t *testing.T) {
-	_ = consensus.Consenter(New(mockLocalConfig.Kafka))
+	_ = consensus.Consenter(New(mockLocalConfig.Kafka, nil))
 }
 
 func TestHandleChain(t *testing.T) {
-	consenter := consensus.Consenter(New(mockLocalConfig.Kafka))
+	consenter := consensus.Consenter(New(mockLocalConfig.Kafka, nil))
 
 	oldestOffset := int64(0)
 	newestOffset := int64(5)
@@ -131,6 +131,7 @@ func newMockBrokerConfig(
 
 	brokerConfig := newBrokerConfig(
 		tlsConfig,
+		nil,
 		saslPlain,
 		retryOptions,
 		kafkaVersion,
@@ -84,6 +84,8 @@ var (
 	connectionProfile     string
 	waitForEvent          bool
 	waitForEventTimeout   time.Duration
+	dryRun                bool
+	envelopeFile          string
 )
 
 var chaincodeCmd = &cobra.Command{
@@ -142,6 +144,10 @@ func resetFlags() {
 		fmt.Sprint("Whether to wait for the event from each peer's deliver filtered service signifying that the 'invoke' transaction has been committed successfully"))
 	flags.DurationVar(&waitForEventTimeout, "waitForEventTimeout", 30*time.Second,
 		fmt.Sprint("Time to wait for the event from each peer's deliver filtered service signifying that the 'invoke' transaction has been committed successfully"))
+	flags.BoolVar(&dryRun, "dry-run", false,
+		fmt.Sprint("Collect and compare the endorsements of an 'invoke' without sending the transaction to the orderer"))
+	flags.StringVar(&envelopeFile, "envelopeFile", common.UndefinedParamValue,
+		fmt.Sprint("The file the signed transaction envelope is written to by a dry-run 'invoke'. Defaults to <txid>.tx"))
 }
 
 func attachFlags(cmd *cobra.Command, names []string) {
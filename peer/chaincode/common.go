@@ -158,6 +158,7 @@ type collectionConfigJson struct {
 	RequiredCount int32  `json:"requiredPeerCount"`
 	MaxPeerCount  int32  `json:"maxPeerCount"`
 	BlockToLive   uint64 `json:"blockToLive"`
+	PurgePolicy   string `json:"purgePolicy"`
 }
 
 // getCollectionConfig retrieves the collection configuration
@@ -195,6 +196,19 @@ func getCollectionConfigFromBytes(cconfBytes []byte) ([]byte, error) {
 			},
 		}
 
+		var purgePolicy *pcommon.CollectionPolicyConfig
+		if cconfitem.PurgePolicy != "" {
+			pp, err := cauthdsl.FromString(cconfitem.PurgePolicy)
+			if err != nil {
+				return nil, errors.WithMessage(err, fmt.Sprintf("invalid purge policy %s", cconfitem.PurgePolicy))
+			}
+			purgePolicy = &pcommon.CollectionPolicyConfig{
+				Payload: &pcommon.CollectionPolicyConfig_SignaturePolicy{
+					SignaturePolicy: pp,
+				},
+			}
+		}
+
 		cc := &pcommon.CollectionConfig{
 			Payload: &pcommon.CollectionConfig_StaticCollectionConfig{
 				StaticCollectionConfig: &pcommon.StaticCollectionConfig{
@@ -203,6 +217,7 @@ func getCollectionConfigFromBytes(cconfBytes []byte) ([]byte, error) {
 					RequiredPeerCount: cconfitem.RequiredCount,
 					MaximumPeerCount:  cconfitem.MaxPeerCount,
 					BlockToLive:       cconfitem.BlockToLive,
+					PurgePolicy:       purgePolicy,
 				},
 			},
 		}
@@ -432,35 +447,14 @@ func ChaincodeInvokeOrQuery(
 	deliverClients []api.PeerDeliverClient,
 	bc common.BroadcastClient,
 ) (*pb.ProposalResponse, error) {
-	// Build the ChaincodeInvocationSpec message
-	invocation := &pb.ChaincodeInvocationSpec{ChaincodeSpec: spec}
-
-	creator, err := signer.Serialize()
-	if err != nil {
-		return nil, errors.WithMessage(err, fmt.Sprintf("error serializing identity for %s", signer.GetIdentifier()))
-	}
-
 	funcName := "invoke"
 	if !invoke {
 		funcName = "query"
 	}
 
-	// extract the transient field if it exists
-	var tMap map[string][]byte
-	if transient != "" {
-		if err := json.Unmarshal([]byte(transient), &tMap); err != nil {
-			return nil, errors.Wrap(err, "error parsing transient string")
-		}
-	}
-
-	prop, txid, err := putils.CreateChaincodeProposalWithTxIDAndTransient(pcommon.HeaderType_ENDORSER_TRANSACTION, cID, invocation, creator, txID, tMap)
-	if err != nil {
-		return nil, errors.WithMessage(err, fmt.Sprintf("error creating proposal for %s", funcName))
-	}
-
-	signedProp, err := putils.GetSignedProposal(prop, signer)
+	prop, signedProp, txid, err := createSignedProposal(spec, cID, txID, funcName, signer)
 	if err != nil {
-		return nil, errors.WithMessage(err, fmt.Sprintf("error creating signed proposal for %s", funcName))
+		return nil, err
 	}
 	var responses []*pb.ProposalResponse
 	for _, endorser := range endorserClients {
@@ -522,6 +516,37 @@ func ChaincodeInvokeOrQuery(
 	return proposalResp, nil
 }
 
+// createSignedProposal builds the chaincode proposal for the supplied spec,
+// including the transient map passed on the command line, and signs it
+func createSignedProposal(spec *pb.ChaincodeSpec, cID, txID, funcName string, signer msp.SigningIdentity) (*pb.Proposal, *pb.SignedProposal, string, error) {
+	// Build the ChaincodeInvocationSpec message
+	invocation := &pb.ChaincodeInvocationSpec{ChaincodeSpec: spec}
+
+	creator, err := signer.Serialize()
+	if err != nil {
+		return nil, nil, "", errors.WithMessage(err, fmt.Sprintf("error serializing identity for %s", signer.GetIdentifier()))
+	}
+
+	// extract the transient field if it exists
+	var tMap map[string][]byte
+	if transient != "" {
+		if err := json.Unmarshal([]byte(transient), &tMap); err != nil {
+			return nil, nil, "", errors.Wrap(err, "error parsing transient string")
+		}
+	}
+
+	prop, txid, err := putils.CreateChaincodeProposalWithTxIDAndTransient(pcommon.HeaderType_ENDORSER_TRANSACTION, cID, invocation, creator, txID, tMap)
+	if err != nil {
+		return nil, nil, "", errors.WithMessage(err, fmt.Sprintf("error creating proposal for %s", funcName))
+	}
+
+	signedProp, err := putils.GetSignedProposal(prop, signer)
+	if err != nil {
+		return nil, nil, "", errors.WithMessage(err, fmt.Sprintf("error creating signed proposal for %s", funcName))
+	}
+	return prop, signedProp, txid, nil
+}
+
 // deliverGroup holds all of the information needed to connect
 // to a set of peers to wait for the interested txid to be
 // committed to the ledgers of all peers. This functionality
@@ -182,7 +182,8 @@ const sampleCollectionConfigGood = `[
 		"policy": "OR('A.member', 'B.member')",
 		"requiredPeerCount": 3,
 		"maxPeerCount": 483279847,
-		"blockToLive":10
+		"blockToLive":10,
+		"purgePolicy": "AND('A.member', 'B.member')"
 	}
 ]`
 
@@ -195,6 +196,16 @@ const sampleCollectionConfigBad = `[
 	}
 ]`
 
+const sampleCollectionConfigBadPurgePolicy = `[
+	{
+		"name": "foo",
+		"policy": "OR('A.member', 'B.member')",
+		"requiredPeerCount": 3,
+		"maxPeerCount": 483279847,
+		"purgePolicy": "barf"
+	}
+]`
+
 func TestCollectionParsing(t *testing.T) {
 	cc, err := getCollectionConfigFromBytes([]byte(sampleCollectionConfigGood))
 	assert.NoError(t, err)
@@ -208,12 +219,19 @@ func TestCollectionParsing(t *testing.T) {
 	assert.Equal(t, "foo", conf.Name)
 	assert.Equal(t, pol, conf.MemberOrgsPolicy.GetSignaturePolicy())
 	assert.Equal(t, 10, int(conf.BlockToLive))
+	purgePol, _ := cauthdsl.FromString("AND('A.member', 'B.member')")
+	assert.Equal(t, purgePol, conf.PurgePolicy.GetSignaturePolicy())
 	t.Logf("conf=%s", conf)
 
 	cc, err = getCollectionConfigFromBytes([]byte(sampleCollectionConfigBad))
 	assert.Error(t, err)
 	assert.Nil(t, cc)
 
+	cc, err = getCollectionConfigFromBytes([]byte(sampleCollectionConfigBadPurgePolicy))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid purge policy")
+	assert.Nil(t, cc)
+
 	cc, err = getCollectionConfigFromBytes([]byte("barf"))
 	assert.Error(t, err)
 	assert.Nil(t, cc)
@@ -0,0 +1,373 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"justledger/common/util"
+	"justledger/core/chaincode/shim"
+	"justledger/core/ledger/kvledger/txmgmt/rwsetutil"
+	"justledger/msp"
+	"justledger/peer/common"
+	pcommon "justledger/protos/common"
+	"justledger/protos/ledger/rwset/kvrwset"
+	pb "justledger/protos/peer"
+	putils "justledger/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// absentItem is reported for a read-write set item that an endorser did
+// not produce while at least one other endorser did
+const absentItem = "<absent>"
+
+// dryRunReport is the result of a dry-run invoke. It holds the decoded
+// proposal response of every endorser and the items of their read-write
+// sets on which the endorsers disagree
+type dryRunReport struct {
+	TxID       string            `json:"txid"`
+	Endorsers  []*endorserResult `json:"endorsers"`
+	Mismatches []*rwsetMismatch  `json:"mismatches,omitempty"`
+	Envelope   string            `json:"envelope,omitempty"`
+}
+
+// endorserResult holds the decoded proposal response of a single endorser
+type endorserResult struct {
+	Endorser   string         `json:"endorser"`
+	Error      string         `json:"error,omitempty"`
+	Status     int32          `json:"status"`
+	Message    string         `json:"message,omitempty"`
+	Namespaces []*nsRwSetDump `json:"namespaces,omitempty"`
+
+	// items flattens the response into comparable entries keyed by a
+	// description of the item (e.g. "mycc read a")
+	items map[string]string
+}
+
+type nsRwSetDump struct {
+	Namespace    string                 `json:"namespace"`
+	Reads        []*kvReadDump          `json:"reads,omitempty"`
+	Writes       []*kvWriteDump         `json:"writes,omitempty"`
+	RangeQueries []*rangeQueryDump      `json:"range_queries,omitempty"`
+	Collections  []*collHashedRwSetDump `json:"collections,omitempty"`
+}
+
+type kvReadDump struct {
+	Key     string `json:"key"`
+	Version string `json:"version"`
+}
+
+type kvWriteDump struct {
+	Key       string `json:"key"`
+	IsDelete  bool   `json:"is_delete,omitempty"`
+	ValueHash string `json:"value_hash,omitempty"`
+}
+
+type rangeQueryDump struct {
+	StartKey       string        `json:"start_key"`
+	EndKey         string        `json:"end_key"`
+	ItrExhausted   bool          `json:"itr_exhausted"`
+	Reads          []*kvReadDump `json:"reads,omitempty"`
+	MerkleMaxLevel uint32        `json:"merkle_max_level,omitempty"`
+	MerkleHashes   []string      `json:"merkle_hashes,omitempty"`
+}
+
+type collHashedRwSetDump struct {
+	Collection   string         `json:"collection"`
+	HashedReads  []*kvReadDump  `json:"hashed_reads,omitempty"`
+	HashedWrites []*kvWriteDump `json:"hashed_writes,omitempty"`
+	PvtRwSetHash string         `json:"pvt_rwset_hash,omitempty"`
+}
+
+// rwsetMismatch describes an item on which the endorsers disagree,
+// along with the value produced by each endorser
+type rwsetMismatch struct {
+	Item   string            `json:"item"`
+	Values map[string]string `json:"values"`
+}
+
+// chaincodeDryRun endorses the invoke on all the configured peers, prints
+// how the endorsers' read-write sets compare and, when they agree, writes
+// the signed transaction to a file instead of sending it for ordering
+func chaincodeDryRun(cmd *cobra.Command, cf *ChaincodeCmdFactory) error {
+	spec, err := getChaincodeSpec(cmd)
+	if err != nil {
+		return err
+	}
+
+	report, env, err := dryRunEndorse(spec, channelID, "", cf.Signer, cf.EndorserClients, peerAddresses)
+	if err != nil {
+		return err
+	}
+
+	if env != nil {
+		outputFile := envelopeFile
+		if outputFile == common.UndefinedParamValue || outputFile == "" {
+			outputFile = report.TxID + ".tx"
+		}
+		if err = ioutil.WriteFile(outputFile, putils.MarshalOrPanic(env), 0644); err != nil {
+			return errors.Wrapf(err, "error writing transaction envelope to %s", outputFile)
+		}
+		report.Envelope = outputFile
+	}
+
+	reportBytes, err := json.MarshalIndent(report, "", "\t")
+	if err != nil {
+		return errors.Wrap(err, "error marshaling dry-run report")
+	}
+	fmt.Println(string(reportBytes))
+
+	if env == nil {
+		return errors.Errorf("endorsements for transaction %s are not consistent; no envelope was written", report.TxID)
+	}
+	logger.Infof("Chaincode invoke dry run successful. Transaction envelope written to %s", report.Envelope)
+	return nil
+}
+
+// dryRunEndorse sends an invoke proposal to all of the endorsers and
+// decodes and compares their proposal responses. The signed transaction
+// envelope is returned only when every endorser succeeded and all of the
+// responses are identical; it is never sent to the orderer
+func dryRunEndorse(
+	spec *pb.ChaincodeSpec,
+	cID string,
+	txID string,
+	signer msp.SigningIdentity,
+	endorserClients []pb.EndorserClient,
+	endorserAddresses []string,
+) (*dryRunReport, *pcommon.Envelope, error) {
+	prop, signedProp, txid, err := createSignedProposal(spec, cID, txID, "invoke", signer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(endorserClients) == 0 {
+		return nil, nil, errors.New("no endorser clients supplied for dry run")
+	}
+
+	report := &dryRunReport{TxID: txid}
+	var responses []*pb.ProposalResponse
+	consistent := true
+	for i, endorser := range endorserClients {
+		name := fmt.Sprintf("endorser%d", i)
+		if i < len(endorserAddresses) && endorserAddresses[i] != common.UndefinedParamValue {
+			name = endorserAddresses[i]
+		}
+
+		proposalResp, err := endorser.ProcessProposal(context.Background(), signedProp)
+		if err != nil {
+			report.Endorsers = append(report.Endorsers, &endorserResult{Endorser: name, Error: err.Error()})
+			consistent = false
+			continue
+		}
+		result, err := decodeEndorserResult(name, proposalResp)
+		report.Endorsers = append(report.Endorsers, result)
+		if err != nil {
+			result.Error = err.Error()
+			result.items = nil
+			consistent = false
+			continue
+		}
+		if proposalResp.Response.Status >= shim.ERRORTHRESHOLD || proposalResp.Endorsement == nil {
+			consistent = false
+		}
+		responses = append(responses, proposalResp)
+	}
+
+	report.Mismatches = diffEndorserResults(report.Endorsers)
+	if !consistent || len(report.Mismatches) > 0 {
+		return report, nil, nil
+	}
+
+	env, err := putils.CreateSignedTx(prop, signer, responses...)
+	if err != nil {
+		return report, nil, errors.WithMessage(err, "could not assemble transaction")
+	}
+	return report, env, nil
+}
+
+// decodeEndorserResult unpacks the chaincode action carried by a proposal
+// response and converts its read-write set into a printable form
+func decodeEndorserResult(name string, resp *pb.ProposalResponse) (*endorserResult, error) {
+	result := &endorserResult{
+		Endorser: name,
+		items:    map[string]string{},
+	}
+	if resp == nil || resp.Response == nil {
+		return result, errors.New("received nil proposal response")
+	}
+	result.Status = resp.Response.Status
+	result.Message = resp.Response.Message
+	result.items["response"] = fmt.Sprintf("status=%d payload=%s", resp.Response.Status, hashString(resp.Response.Payload))
+	if resp.Response.Status >= shim.ERRORTHRESHOLD || len(resp.Payload) == 0 {
+		return result, nil
+	}
+
+	prp, err := putils.GetProposalResponsePayload(resp.Payload)
+	if err != nil {
+		return result, errors.WithMessage(err, "error while unmarshaling proposal response payload")
+	}
+	ca, err := putils.GetChaincodeAction(prp.Extension)
+	if err != nil {
+		return result, errors.WithMessage(err, "error while unmarshaling chaincode action")
+	}
+	if len(ca.Events) > 0 {
+		result.items["events"] = hashString(ca.Events)
+	}
+
+	txRwSet := &rwsetutil.TxRwSet{}
+	if err := txRwSet.FromProtoBytes(ca.Results); err != nil {
+		return result, errors.WithMessage(err, "error while unmarshaling read-write set")
+	}
+	for _, nsRwSet := range txRwSet.NsRwSets {
+		result.Namespaces = append(result.Namespaces, dumpNsRwSet(nsRwSet, result.items))
+	}
+	return result, nil
+}
+
+func dumpNsRwSet(nsRwSet *rwsetutil.NsRwSet, items map[string]string) *nsRwSetDump {
+	ns := nsRwSet.NameSpace
+	dump := &nsRwSetDump{Namespace: ns}
+	kvRwSet := nsRwSet.KvRwSet
+	if kvRwSet == nil {
+		kvRwSet = &kvrwset.KVRWSet{}
+	}
+
+	for _, read := range kvRwSet.Reads {
+		r := &kvReadDump{Key: read.Key, Version: versionString(read.Version)}
+		dump.Reads = append(dump.Reads, r)
+		items[fmt.Sprintf("%s read %s", ns, r.Key)] = r.Version
+	}
+	for _, write := range kvRwSet.Writes {
+		w := &kvWriteDump{Key: write.Key, IsDelete: write.IsDelete}
+		if !write.IsDelete {
+			w.ValueHash = hashString(write.Value)
+		}
+		dump.Writes = append(dump.Writes, w)
+		items[fmt.Sprintf("%s write %s", ns, w.Key)] = writeString(w)
+	}
+	for i, rqi := range kvRwSet.RangeQueriesInfo {
+		rq := &rangeQueryDump{StartKey: rqi.StartKey, EndKey: rqi.EndKey, ItrExhausted: rqi.ItrExhausted}
+		var reads []string
+		if rawReads := rqi.GetRawReads(); rawReads != nil {
+			for _, read := range rawReads.KvReads {
+				r := &kvReadDump{Key: read.Key, Version: versionString(read.Version)}
+				rq.Reads = append(rq.Reads, r)
+				reads = append(reads, r.Key+"@"+r.Version)
+			}
+		}
+		if merkle := rqi.GetReadsMerkleHashes(); merkle != nil {
+			rq.MerkleMaxLevel = merkle.MaxLevel
+			for _, h := range merkle.MaxLevelHashes {
+				rq.MerkleHashes = append(rq.MerkleHashes, hex.EncodeToString(h))
+				reads = append(reads, hex.EncodeToString(h))
+			}
+		}
+		dump.RangeQueries = append(dump.RangeQueries, rq)
+		items[fmt.Sprintf("%s range-query[%d]", ns, i)] = fmt.Sprintf("[%s, %s) exhausted=%t reads=[%s]",
+			rq.StartKey, rq.EndKey, rq.ItrExhausted, strings.Join(reads, " "))
+	}
+
+	for _, collRwSet := range nsRwSet.CollHashedRwSets {
+		coll := &collHashedRwSetDump{
+			Collection:   collRwSet.CollectionName,
+			PvtRwSetHash: hex.EncodeToString(collRwSet.PvtRwSetHash),
+		}
+		prefix := fmt.Sprintf("%s/%s", ns, coll.Collection)
+		if collRwSet.HashedRwSet != nil {
+			for _, read := range collRwSet.HashedRwSet.HashedReads {
+				r := &kvReadDump{Key: hex.EncodeToString(read.KeyHash), Version: versionString(read.Version)}
+				coll.HashedReads = append(coll.HashedReads, r)
+				items[fmt.Sprintf("%s hashed-read %s", prefix, r.Key)] = r.Version
+			}
+			for _, write := range collRwSet.HashedRwSet.HashedWrites {
+				w := &kvWriteDump{Key: hex.EncodeToString(write.KeyHash), IsDelete: write.IsDelete}
+				if !write.IsDelete {
+					w.ValueHash = hex.EncodeToString(write.ValueHash)
+				}
+				coll.HashedWrites = append(coll.HashedWrites, w)
+				items[fmt.Sprintf("%s hashed-write %s", prefix, w.Key)] = writeString(w)
+			}
+		}
+		items[fmt.Sprintf("%s pvt-rwset-hash", prefix)] = coll.PvtRwSetHash
+		dump.Collections = append(dump.Collections, coll)
+	}
+	return dump
+}
+
+// diffEndorserResults compares the flattened read-write sets of the
+// endorsers that returned a decodable response and returns the items on
+// which they disagree, sorted by item
+func diffEndorserResults(results []*endorserResult) []*rwsetMismatch {
+	var decoded []*endorserResult
+	allItems := map[string]struct{}{}
+	for _, r := range results {
+		if r.items == nil {
+			continue
+		}
+		decoded = append(decoded, r)
+		for item := range r.items {
+			allItems[item] = struct{}{}
+		}
+	}
+	if len(decoded) < 2 {
+		return nil
+	}
+
+	var sortedItems []string
+	for item := range allItems {
+		sortedItems = append(sortedItems, item)
+	}
+	sort.Strings(sortedItems)
+
+	var mismatches []*rwsetMismatch
+	for _, item := range sortedItems {
+		values := map[string]string{}
+		differ := false
+		for i, r := range decoded {
+			v, ok := r.items[item]
+			if !ok {
+				v = absentItem
+			}
+			values[r.Endorser] = v
+			if i > 0 && v != values[decoded[0].Endorser] {
+				differ = true
+			}
+		}
+		if differ {
+			mismatches = append(mismatches, &rwsetMismatch{Item: item, Values: values})
+		}
+	}
+	return mismatches
+}
+
+func versionString(v *kvrwset.Version) string {
+	if v == nil {
+		return "nil"
+	}
+	return fmt.Sprintf("%d:%d", v.BlockNum, v.TxNum)
+}
+
+func writeString(w *kvWriteDump) string {
+	if w.IsDelete {
+		return "delete"
+	}
+	return w.ValueHash
+}
+
+func hashString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	return hex.EncodeToString(util.ComputeSHA256(b))
+}
@@ -0,0 +1,137 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"justledger/core/ledger/kvledger/txmgmt/rwsetutil"
+	"justledger/core/ledger/kvledger/txmgmt/version"
+	"justledger/peer/common"
+	pb "justledger/protos/peer"
+	"justledger/protos/utils"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func getMockDryRunResponse(t *testing.T, readVersion *version.Height, writeValue []byte) *pb.ProposalResponse {
+	b := rwsetutil.NewRWSetBuilder()
+	b.AddToReadSet("example02", "a", readVersion)
+	b.AddToWriteSet("example02", "b", writeValue)
+	b.AddToHashedReadSet("example02", "coll1", "c", version.NewHeight(1, 1))
+	b.AddToPvtAndHashedWriteSet("example02", "coll1", "c", []byte("pvt"))
+	simRes, err := b.GetTxSimulationResults()
+	assert.NoError(t, err)
+	pubBytes, err := simRes.GetPubSimulationBytes()
+	assert.NoError(t, err)
+
+	response := &pb.Response{Status: 200}
+	prpBytes, err := utils.GetBytesProposalResponsePayload([]byte("hash"), response, pubBytes, nil, &pb.ChaincodeID{Name: "example02"})
+	assert.NoError(t, err)
+	return &pb.ProposalResponse{
+		Response:    response,
+		Payload:     prpBytes,
+		Endorsement: &pb.Endorsement{},
+	}
+}
+
+func TestDryRunEndorse(t *testing.T) {
+	mockCF, err := getMockChaincodeCmdFactory()
+	assert.NoError(t, err)
+	spec := &pb.ChaincodeSpec{
+		ChaincodeId: &pb.ChaincodeID{Name: "example02"},
+		Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte("invoke")}},
+	}
+
+	t.Run("consistent endorsements", func(t *testing.T) {
+		resp := getMockDryRunResponse(t, version.NewHeight(2, 0), []byte("100"))
+		endorsers := []pb.EndorserClient{
+			common.GetMockEndorserClient(resp, nil),
+			common.GetMockEndorserClient(resp, nil),
+		}
+		report, env, err := dryRunEndorse(spec, "mychannel", "", mockCF.Signer, endorsers, []string{"peer0:7051", "peer1:7051"})
+		assert.NoError(t, err)
+		assert.NotNil(t, env)
+		assert.Empty(t, report.Mismatches)
+		assert.Len(t, report.Endorsers, 2)
+		assert.Equal(t, "peer0:7051", report.Endorsers[0].Endorser)
+		ns := report.Endorsers[0].Namespaces[0]
+		assert.Equal(t, "example02", ns.Namespace)
+		assert.Equal(t, "2:0", ns.Reads[0].Version)
+		assert.Equal(t, "b", ns.Writes[0].Key)
+		assert.Equal(t, "coll1", ns.Collections[0].Collection)
+		assert.Len(t, ns.Collections[0].HashedWrites, 1)
+		assert.NotEmpty(t, ns.Collections[0].PvtRwSetHash)
+	})
+
+	t.Run("diverging endorsements", func(t *testing.T) {
+		endorsers := []pb.EndorserClient{
+			common.GetMockEndorserClient(getMockDryRunResponse(t, version.NewHeight(2, 0), []byte("100")), nil),
+			common.GetMockEndorserClient(getMockDryRunResponse(t, version.NewHeight(3, 1), []byte("100")), nil),
+			common.GetMockEndorserClient(getMockDryRunResponse(t, version.NewHeight(2, 0), []byte("90")), nil),
+		}
+		report, env, err := dryRunEndorse(spec, "mychannel", "", mockCF.Signer, endorsers, nil)
+		assert.NoError(t, err)
+		assert.Nil(t, env)
+		assert.Len(t, report.Mismatches, 2)
+		assert.Equal(t, "example02 read a", report.Mismatches[0].Item)
+		assert.Equal(t, "2:0", report.Mismatches[0].Values["endorser0"])
+		assert.Equal(t, "3:1", report.Mismatches[0].Values["endorser1"])
+		assert.Equal(t, "example02 write b", report.Mismatches[1].Item)
+	})
+
+	t.Run("endorser error", func(t *testing.T) {
+		endorsers := []pb.EndorserClient{
+			common.GetMockEndorserClient(getMockDryRunResponse(t, version.NewHeight(2, 0), []byte("100")), nil),
+			common.GetMockEndorserClient(nil, errors.New("unreachable")),
+		}
+		report, env, err := dryRunEndorse(spec, "mychannel", "", mockCF.Signer, endorsers, nil)
+		assert.NoError(t, err)
+		assert.Nil(t, env)
+		assert.Equal(t, "unreachable", report.Endorsers[1].Error)
+	})
+}
+
+func TestInvokeCmdDryRun(t *testing.T) {
+	defer viper.Reset()
+	defer resetFlags()
+
+	// --dryRun is accepted as an alias of --dry-run
+	for _, flag := range []string{"--dry-run", "--dryRun"} {
+		resetFlags()
+		mockCF, err := getMockChaincodeCmdFactory()
+		assert.NoError(t, err)
+		resp := getMockDryRunResponse(t, version.NewHeight(2, 0), []byte("100"))
+		mockCF.EndorserClients = []pb.EndorserClient{
+			common.GetMockEndorserClient(resp, nil),
+			common.GetMockEndorserClient(resp, nil),
+		}
+		mockCF.BroadcastClient = common.GetMockBroadcastClient(errors.New("dry run must not broadcast"))
+
+		dir, err := ioutil.TempDir("", "dryrun")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dir)
+		output := filepath.Join(dir, "tx.envelope")
+
+		cmd := invokeCmd(mockCF)
+		addFlags(cmd)
+		cmd.SetArgs([]string{"-n", "example02", "-c", "{\"Args\": [\"invoke\",\"a\",\"b\",\"10\"]}", "-C", "mychannel",
+			flag, "--envelopeFile", output})
+		assert.NoError(t, cmd.Execute(), "invoke with %s", flag)
+		assert.Equal(t, "dry-run", cmd.Flags().Lookup("dryRun").Name)
+
+		envBytes, err := ioutil.ReadFile(output)
+		assert.NoError(t, err)
+		env, err := utils.GetEnvelopeFromBlock(envBytes)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, env.Signature)
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var chaincodeInvokeCmd *cobra.Command
@@ -35,12 +36,23 @@ func invokeCmd(cf *ChaincodeCmdFactory) *cobra.Command {
 		"connectionProfile",
 		"waitForEvent",
 		"waitForEventTimeout",
+		"dry-run",
+		"envelopeFile",
 	}
 	attachFlags(chaincodeInvokeCmd, flagList)
+	chaincodeInvokeCmd.Flags().SetNormalizeFunc(normalizeDryRunFlag)
 
 	return chaincodeInvokeCmd
 }
 
+// normalizeDryRunFlag accepts --dryRun as an alias of --dry-run
+func normalizeDryRunFlag(f *pflag.FlagSet, name string) pflag.NormalizedName {
+	if name == "dryRun" {
+		name = "dry-run"
+	}
+	return pflag.NormalizedName(name)
+}
+
 func chaincodeInvoke(cmd *cobra.Command, cf *ChaincodeCmdFactory) error {
 	if channelID == "" {
 		return errors.New("The required parameter 'channelID' is empty. Rerun the command with -C flag")
@@ -50,11 +62,15 @@ func chaincodeInvoke(cmd *cobra.Command, cf *ChaincodeCmdFactory) error {
 
 	var err error
 	if cf == nil {
-		cf, err = InitCmdFactory(cmd.Name(), true, true)
+		// a dry run never reaches the orderer
+		cf, err = InitCmdFactory(cmd.Name(), true, !dryRun)
 		if err != nil {
 			return err
 		}
 	}
+	if dryRun {
+		return chaincodeDryRun(cmd, cf)
+	}
 	defer cf.BroadcastClient.Close()
 
 	return chaincodeInvokeOrQuery(cmd, true, cf)
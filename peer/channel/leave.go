@@ -0,0 +1,98 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"context"
+	"fmt"
+
+	"justledger/core/scc/cscc"
+	"justledger/peer/common"
+	cb "justledger/protos/common"
+	pb "justledger/protos/peer"
+	"justledger/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const leaveCommandDescription = "Makes the peer leave a channel and removes all the data of the channel from the peer."
+
+func leaveCmd(cf *ChannelCmdFactory) *cobra.Command {
+	leaveCmd := &cobra.Command{
+		Use:   "leave",
+		Short: leaveCommandDescription,
+		Long: leaveCommandDescription + " The deliver service, the gossip and the committer of the channel are stopped " +
+			"and its ledger, state database, history, private data and transient data are deleted. Requires '-c'.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return leave(cmd, cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+	}
+	attachFlags(leaveCmd, flagList)
+
+	return leaveCmd
+}
+
+func executeLeave(cf *ChannelCmdFactory) error {
+	invocation := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_Type(pb.ChaincodeSpec_Type_value["GOLANG"]),
+			ChaincodeId: &pb.ChaincodeID{Name: "cscc"},
+			Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte(cscc.LeaveChain), []byte(channelID)}},
+		},
+	}
+
+	creator, err := cf.Signer.Serialize()
+	if err != nil {
+		return errors.WithMessage(err, "cannot serialize the signer identity")
+	}
+
+	prop, _, err := utils.CreateProposalFromCIS(cb.HeaderType_CONFIG, "", invocation, creator)
+	if err != nil {
+		return errors.WithMessage(err, "cannot create proposal")
+	}
+
+	signedProp, err := utils.GetSignedProposal(prop, cf.Signer)
+	if err != nil {
+		return errors.WithMessage(err, "cannot create signed proposal")
+	}
+
+	proposalResp, err := cf.EndorserClient.ProcessProposal(context.Background(), signedProp)
+	if err != nil {
+		return ProposalFailedErr(err.Error())
+	}
+
+	if proposalResp == nil || proposalResp.Response == nil {
+		return ProposalFailedErr("nil proposal response")
+	}
+
+	if proposalResp.Response.Status != 0 && proposalResp.Response.Status != 200 {
+		return ProposalFailedErr(fmt.Sprintf("bad proposal response %d: %s", proposalResp.Response.Status, proposalResp.Response.Message))
+	}
+	logger.Infof("Successfully submitted proposal to leave channel %s", channelID)
+	return nil
+}
+
+func leave(cmd *cobra.Command, cf *ChannelCmdFactory) error {
+	//the global chainID filled by the "-c" command
+	if channelID == common.UndefinedParamValue {
+		return errors.New("Must supply channel ID")
+	}
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory(EndorserRequired, PeerDeliverNotRequired, OrdererNotRequired)
+		if err != nil {
+			return err
+		}
+	}
+	return executeLeave(cf)
+}
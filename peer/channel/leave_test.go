@@ -0,0 +1,95 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"testing"
+
+	"justledger/peer/common"
+	pb "justledger/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeave(t *testing.T) {
+	defer resetFlags()
+	InitMSP()
+	resetFlags()
+
+	signer, err := common.GetDefaultSigner()
+	assert.NoError(t, err)
+
+	mockResponse := &pb.ProposalResponse{
+		Response:    &pb.Response{Status: 200},
+		Endorsement: &pb.Endorsement{},
+	}
+	mockCF := &ChannelCmdFactory{
+		EndorserClient:   common.GetMockEndorserClient(mockResponse, nil),
+		BroadcastFactory: mockBroadcastClientFactory,
+		Signer:           signer,
+	}
+
+	cmd := leaveCmd(mockCF)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-c", mockChannel})
+	assert.NoError(t, cmd.Execute())
+}
+
+func TestLeaveMissingChannelID(t *testing.T) {
+	defer resetFlags()
+	InitMSP()
+	resetFlags()
+
+	cmd := leaveCmd(nil)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{})
+	assert.EqualError(t, cmd.Execute(), "Must supply channel ID")
+}
+
+func TestLeaveFailures(t *testing.T) {
+	defer resetFlags()
+	InitMSP()
+
+	signer, err := common.GetDefaultSigner()
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		response      *pb.ProposalResponse
+		err           error
+		expectedError string
+	}{
+		{
+			name:          "endorser error",
+			err:           errors.New("connection refused"),
+			expectedError: "proposal failed (err: connection refused)",
+		},
+		{
+			name: "bad response",
+			response: &pb.ProposalResponse{
+				Response:    &pb.Response{Status: 500, Message: "peer is not joined to channel [mockChannel]"},
+				Endorsement: &pb.Endorsement{},
+			},
+			expectedError: "proposal failed (err: bad proposal response 500: peer is not joined to channel [mockChannel])",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resetFlags()
+			mockCF := &ChannelCmdFactory{
+				EndorserClient:   common.GetMockEndorserClient(tc.response, tc.err),
+				BroadcastFactory: mockBroadcastClientFactory,
+				Signer:           signer,
+			}
+			cmd := leaveCmd(mockCF)
+			AddFlags(cmd)
+			cmd.SetArgs([]string{"-c", mockChannel})
+			assert.EqualError(t, cmd.Execute(), tc.expectedError)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"justledger/core/scc/qscc"
+	cb "justledger/protos/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func blockCmd(cf *LedgerCmdFactory) *cobra.Command {
+	blockCmd := &cobra.Command{
+		Use:   "block <number>",
+		Short: "Print a decoded block.",
+		Long:  "Print the block with the given number as JSON, along with a summary of its transactions. Requires '-c'.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getBlock(cmd, args, cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+	}
+	attachFlags(blockCmd, flagList)
+
+	return blockCmd
+}
+
+func getBlock(cmd *cobra.Command, args []string, cf *LedgerCmdFactory) error {
+	if len(args) != 1 {
+		return errors.New("block number required")
+	}
+	if _, err := strconv.ParseUint(args[0], 10, 64); err != nil {
+		return errors.Errorf("invalid block number %s", args[0])
+	}
+
+	cf, err := initFactory(cmd, cf)
+	if err != nil {
+		return err
+	}
+
+	payload, err := cf.queryQSCC(qscc.GetBlockByNumber, args[0])
+	if err != nil {
+		return err
+	}
+	block := &cb.Block{}
+	if err := proto.Unmarshal(payload, block); err != nil {
+		return errors.Wrap(err, "cannot read qscc response")
+	}
+
+	output, err := decodeBlock(block)
+	if err != nil {
+		return err
+	}
+	jsonBytes, err := json.MarshalIndent(output, "", "\t")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(jsonBytes))
+
+	return nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"encoding/json"
+	"testing"
+
+	cb "justledger/protos/common"
+	pb "justledger/protos/peer"
+	"justledger/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBlock(t *testing.T) {
+	defer resetFlags()
+	resetFlags()
+
+	env, _ := createEndorserTx(t)
+	block := cb.NewBlock(3, []byte("previous"))
+	block.Data.Data = [][]byte{utils.MarshalOrPanic(env)}
+	block.Header.DataHash = block.Data.Hash()
+
+	cmd := blockCmd(getMockLedgerCmdFactory(t, block, 200))
+	cmd.SetArgs([]string{"3", "-c", mockChannel})
+	assert.NoError(t, cmd.Execute())
+
+	cmd = blockCmd(getMockLedgerCmdFactory(t, block, 200))
+	cmd.SetArgs([]string{"three", "-c", mockChannel})
+	assert.EqualError(t, cmd.Execute(), "invalid block number three")
+
+	cmd = blockCmd(getMockLedgerCmdFactory(t, block, 200))
+	cmd.SetArgs([]string{"-c", mockChannel})
+	assert.EqualError(t, cmd.Execute(), "block number required")
+}
+
+func TestDecodeBlock(t *testing.T) {
+	env, txid := createEndorserTx(t)
+	block := cb.NewBlock(3, []byte("previous"))
+	block.Data.Data = [][]byte{utils.MarshalOrPanic(env), utils.MarshalOrPanic(env)}
+	block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = []byte{
+		byte(pb.TxValidationCode_VALID),
+		byte(pb.TxValidationCode_BAD_PAYLOAD),
+	}
+
+	output, err := decodeBlock(block)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), output.Number)
+	require.Len(t, output.Transactions, 2)
+
+	tx := output.Transactions[0]
+	assert.Equal(t, txid, tx.TxID)
+	assert.Equal(t, "ENDORSER_TRANSACTION", tx.Type)
+	assert.Equal(t, "SampleOrg", tx.CreatorMSPID)
+	assert.Equal(t, "VALID", tx.ValidationCode)
+	require.Len(t, tx.ChaincodeEvents, 1)
+	assert.Equal(t, "moved", tx.ChaincodeEvents[0].EventName)
+	assert.Empty(t, tx.Error)
+
+	assert.Equal(t, 1, output.Transactions[1].TxNum)
+	assert.Equal(t, "BAD_PAYLOAD", output.Transactions[1].ValidationCode)
+
+	// the decoded block exposes the nested messages rather than raw bytes
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(output.Block, &decoded))
+	assert.Contains(t, string(output.Block), `"mspid": "SampleOrg"`)
+
+	// a malformed transaction is reported in its summary
+	summary := summarizeEnvelopeBytes([]byte("garbage"), pb.TxValidationCode_BAD_PAYLOAD)
+	assert.Equal(t, "BAD_PAYLOAD", summary.ValidationCode)
+	assert.NotEmpty(t, summary.Error)
+
+	_, err = decodeBlock(&cb.Block{})
+	assert.EqualError(t, err, "block is missing its header or data")
+}
@@ -0,0 +1,205 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"justledger/common/tools/protolator"
+	cb "justledger/protos/common"
+	"justledger/protos/msp"
+	pb "justledger/protos/peer"
+	"justledger/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// blockOutput is printed by 'peer ledger block'. The summary of each
+// transaction is listed ahead of the fully decoded block
+type blockOutput struct {
+	Number       uint64          `json:"number"`
+	Transactions []*txSummary    `json:"transactions"`
+	Block        json.RawMessage `json:"block"`
+}
+
+// txOutput is printed by 'peer ledger tx'
+type txOutput struct {
+	Summary     *txSummary      `json:"summary"`
+	Transaction json.RawMessage `json:"transaction"`
+}
+
+// txSummary holds the fields of a transaction that are otherwise buried
+// deep inside the decoded envelope
+type txSummary struct {
+	TxNum           int               `json:"tx_num"`
+	TxID            string            `json:"txid,omitempty"`
+	Type            string            `json:"type"`
+	Timestamp       string            `json:"timestamp,omitempty"`
+	CreatorMSPID    string            `json:"creator_msp_id,omitempty"`
+	ValidationCode  string            `json:"validation_code"`
+	ChaincodeEvents []*chaincodeEvent `json:"chaincode_events,omitempty"`
+	Error           string            `json:"error,omitempty"`
+}
+
+type chaincodeEvent struct {
+	ChaincodeID string `json:"chaincode_id"`
+	EventName   string `json:"event_name"`
+	Payload     []byte `json:"payload,omitempty"`
+}
+
+// protoToJSON decodes the message, including all of its nested opaque
+// fields, into JSON
+func protoToJSON(msg proto.Message) (json.RawMessage, error) {
+	buf := &bytes.Buffer{}
+	if err := protolator.DeepMarshalJSON(buf, msg); err != nil {
+		return nil, errors.Wrapf(err, "error decoding %T", msg)
+	}
+	return json.RawMessage(buf.Bytes()), nil
+}
+
+// decodeBlock builds the output for a block, using the transaction filter
+// of the block metadata for the validation codes
+func decodeBlock(block *cb.Block) (*blockOutput, error) {
+	if block.Header == nil || block.Data == nil {
+		return nil, errors.New("block is missing its header or data")
+	}
+
+	var txFilter []byte
+	if len(block.Metadata.GetMetadata()) > int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txFilter = block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER]
+	}
+
+	output := &blockOutput{
+		Number:       block.Header.Number,
+		Transactions: []*txSummary{},
+	}
+	for i, envBytes := range block.Data.Data {
+		code := pb.TxValidationCode_NOT_VALIDATED
+		if i < len(txFilter) {
+			code = pb.TxValidationCode(txFilter[i])
+		}
+		summary := summarizeEnvelopeBytes(envBytes, code)
+		summary.TxNum = i
+		output.Transactions = append(output.Transactions, summary)
+	}
+
+	blockJSON, err := protoToJSON(block)
+	if err != nil {
+		return nil, err
+	}
+	output.Block = blockJSON
+	return output, nil
+}
+
+// decodeProcessedTransaction builds the output for a transaction
+// retrieved by its ID
+func decodeProcessedTransaction(pt *pb.ProcessedTransaction) (*txOutput, error) {
+	if pt.TransactionEnvelope == nil {
+		return nil, errors.New("processed transaction is missing its envelope")
+	}
+	txJSON, err := protoToJSON(pt)
+	if err != nil {
+		return nil, err
+	}
+	return &txOutput{
+		Summary:     summarizeEnvelope(pt.TransactionEnvelope, pb.TxValidationCode(pt.ValidationCode)),
+		Transaction: txJSON,
+	}, nil
+}
+
+func summarizeEnvelopeBytes(envBytes []byte, code pb.TxValidationCode) *txSummary {
+	env, err := utils.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		return &txSummary{ValidationCode: code.String(), Error: err.Error()}
+	}
+	return summarizeEnvelope(env, code)
+}
+
+// summarizeEnvelope extracts the header fields, the creator's MSP ID and
+// the chaincode events of a transaction. Decoding errors are recorded in
+// the summary rather than returned so that one malformed transaction does
+// not hide the rest of the block
+func summarizeEnvelope(env *cb.Envelope, code pb.TxValidationCode) *txSummary {
+	summary := &txSummary{ValidationCode: code.String()}
+
+	payload, err := utils.GetPayload(env)
+	if err != nil {
+		summary.Error = err.Error()
+		return summary
+	}
+	if payload.Header == nil {
+		summary.Error = "envelope payload is missing its header"
+		return summary
+	}
+
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		summary.Error = err.Error()
+		return summary
+	}
+	summary.TxID = chdr.TxId
+	summary.Type = cb.HeaderType(chdr.Type).String()
+	if chdr.Timestamp != nil {
+		summary.Timestamp = ptypes.TimestampString(chdr.Timestamp)
+	}
+
+	shdr, err := utils.GetSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		summary.Error = err.Error()
+		return summary
+	}
+	creator := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(shdr.Creator, creator); err != nil {
+		summary.Error = fmt.Sprintf("error unmarshaling creator: %s", err)
+		return summary
+	}
+	summary.CreatorMSPID = creator.Mspid
+
+	if cb.HeaderType(chdr.Type) != cb.HeaderType_ENDORSER_TRANSACTION {
+		return summary
+	}
+	events, err := chaincodeEvents(payload.Data)
+	if err != nil {
+		summary.Error = err.Error()
+		return summary
+	}
+	summary.ChaincodeEvents = events
+	return summary
+}
+
+// chaincodeEvents returns the chaincode events set by each action of an
+// endorser transaction
+func chaincodeEvents(txBytes []byte) ([]*chaincodeEvent, error) {
+	tx, err := utils.GetTransaction(txBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*chaincodeEvent
+	for _, action := range tx.Actions {
+		_, ca, err := utils.GetPayloads(action)
+		if err != nil {
+			return nil, err
+		}
+		if len(ca.Events) == 0 {
+			continue
+		}
+		ccEvent, err := utils.GetChaincodeEvents(ca.Events)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &chaincodeEvent{
+			ChaincodeID: ccEvent.ChaincodeId,
+			EventName:   ccEvent.EventName,
+			Payload:     ccEvent.Payload,
+		})
+	}
+	return events, nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"justledger/core/scc/qscc"
+	"justledger/protos/ledger/queryresult"
+	pb "justledger/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func historyCmd(cf *LedgerCmdFactory) *cobra.Command {
+	historyCmd := &cobra.Command{
+		Use:   "history <chaincode> <key>",
+		Short: "Print the history of a key.",
+		Long:  "Print every modification of a chaincode key as JSON. Requires '-c' and the history database to be enabled on the peer.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getHistory(cmd, args, cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+	}
+	attachFlags(historyCmd, flagList)
+
+	return historyCmd
+}
+
+func getHistory(cmd *cobra.Command, args []string, cf *LedgerCmdFactory) error {
+	if len(args) != 2 {
+		return errors.New("chaincode name and key required")
+	}
+
+	cf, err := initFactory(cmd, cf)
+	if err != nil {
+		return err
+	}
+
+	payload, err := cf.queryQSCC(qscc.GetHistoryForKey, args[0], args[1])
+	if err != nil {
+		return err
+	}
+	resp := &pb.QueryResponse{}
+	if err := proto.Unmarshal(payload, resp); err != nil {
+		return errors.Wrap(err, "cannot read qscc response")
+	}
+
+	modifications := []json.RawMessage{}
+	for _, result := range resp.Results {
+		km := &queryresult.KeyModification{}
+		if err := proto.Unmarshal(result.ResultBytes, km); err != nil {
+			return errors.Wrap(err, "cannot read key modification")
+		}
+		kmJSON, err := protoToJSON(km)
+		if err != nil {
+			return err
+		}
+		modifications = append(modifications, kmJSON)
+	}
+
+	jsonBytes, err := json.MarshalIndent(modifications, "", "\t")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(jsonBytes))
+
+	return nil
+}
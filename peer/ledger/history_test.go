@@ -0,0 +1,47 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes"
+	"justledger/protos/ledger/queryresult"
+	pb "justledger/protos/peer"
+	"justledger/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHistory(t *testing.T) {
+	defer resetFlags()
+	resetFlags()
+
+	km := &queryresult.KeyModification{
+		TxId:      "txid1",
+		Value:     []byte("value1"),
+		Timestamp: ptypes.TimestampNow(),
+	}
+	resp := &pb.QueryResponse{
+		Results: []*pb.QueryResultBytes{
+			{ResultBytes: utils.MarshalOrPanic(km)},
+			{ResultBytes: utils.MarshalOrPanic(&queryresult.KeyModification{TxId: "txid2", IsDelete: true})},
+		},
+	}
+
+	cmd := historyCmd(getMockLedgerCmdFactory(t, resp, 200))
+	cmd.SetArgs([]string{"mycc", "key1", "-c", mockChannel})
+	assert.NoError(t, cmd.Execute())
+
+	cmd = historyCmd(getMockLedgerCmdFactory(t, resp, 200))
+	cmd.SetArgs([]string{"mycc", "-c", mockChannel})
+	assert.EqualError(t, cmd.Execute(), "chaincode name and key required")
+
+	badResp := &pb.QueryResponse{Results: []*pb.QueryResultBytes{{ResultBytes: []byte("garbage")}}}
+	cmd = historyCmd(getMockLedgerCmdFactory(t, badResp, 200))
+	cmd.SetArgs([]string{"mycc", "key1", "-c", mockChannel})
+	assert.Error(t, cmd.Execute())
+}
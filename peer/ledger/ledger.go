@@ -0,0 +1,159 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"context"
+
+	"justledger/common/flogging"
+	"justledger/msp"
+	"justledger/peer/common"
+	cb "justledger/protos/common"
+	pb "justledger/protos/peer"
+	"justledger/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	ledgerFuncName = "ledger"
+	ledgerCmdDes   = "Query a channel's ledger on a peer: block|tx|history."
+)
+
+var logger = flogging.MustGetLogger("ledgerCmd")
+
+var channelID string
+
+// Cmd returns the cobra command for Ledger
+func Cmd(cf *LedgerCmdFactory) *cobra.Command {
+	ledgerCmd.AddCommand(blockCmd(cf))
+	ledgerCmd.AddCommand(txCmd(cf))
+	ledgerCmd.AddCommand(historyCmd(cf))
+
+	return ledgerCmd
+}
+
+var ledgerCmd = &cobra.Command{
+	Use:   ledgerFuncName,
+	Short: ledgerCmdDes,
+	Long:  ledgerCmdDes,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		common.InitCmd(cmd, args)
+	},
+}
+
+var flags *pflag.FlagSet
+
+func init() {
+	resetFlags()
+}
+
+// Explicitly define a method to facilitate tests
+func resetFlags() {
+	flags = &pflag.FlagSet{}
+
+	flags.StringVarP(&channelID, "channelID", "c", common.UndefinedParamValue, "The channel whose ledger is queried")
+}
+
+func attachFlags(cmd *cobra.Command, names []string) {
+	cmdFlags := cmd.Flags()
+	for _, name := range names {
+		if flag := flags.Lookup(name); flag != nil {
+			cmdFlags.AddFlag(flag)
+		} else {
+			logger.Fatalf("Could not find flag '%s' to attach to command '%s'", name, cmd.Name())
+		}
+	}
+}
+
+// LedgerCmdFactory holds the clients used by LedgerCmd
+type LedgerCmdFactory struct {
+	EndorserClient pb.EndorserClient
+	Signer         msp.SigningIdentity
+}
+
+// InitCmdFactory init the LedgerCmdFactory with the default endorser client
+func InitCmdFactory() (*LedgerCmdFactory, error) {
+	signer, err := common.GetDefaultSignerFnc()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error getting default signer")
+	}
+
+	// creating an EndorserClient with these empty parameters will create a
+	// connection using the values of "peer.address" and
+	// "peer.tls.rootcert.file"
+	endorserClient, err := common.GetEndorserClientFnc(common.UndefinedParamValue, common.UndefinedParamValue)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error getting endorser client for ledger")
+	}
+
+	return &LedgerCmdFactory{
+		EndorserClient: endorserClient,
+		Signer:         signer,
+	}, nil
+}
+
+// initFactory validates the channel ID and, unless one is supplied,
+// creates the command factory
+func initFactory(cmd *cobra.Command, cf *LedgerCmdFactory) (*LedgerCmdFactory, error) {
+	if channelID == common.UndefinedParamValue {
+		return nil, errors.New("Must supply channel ID")
+	}
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	if cf != nil {
+		return cf, nil
+	}
+	return InitCmdFactory()
+}
+
+// queryQSCC invokes the given qscc function on the channel with the
+// supplied arguments and returns the payload of the response
+func (cf *LedgerCmdFactory) queryQSCC(fname string, args ...string) ([]byte, error) {
+	input := [][]byte{[]byte(fname), []byte(channelID)}
+	for _, arg := range args {
+		input = append(input, []byte(arg))
+	}
+
+	invocation := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_Type(pb.ChaincodeSpec_Type_value["GOLANG"]),
+			ChaincodeId: &pb.ChaincodeID{Name: "qscc"},
+			Input:       &pb.ChaincodeInput{Args: input},
+		},
+	}
+
+	creator, err := cf.Signer.Serialize()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error serializing identity")
+	}
+	prop, _, err := utils.CreateProposalFromCIS(cb.HeaderType_ENDORSER_TRANSACTION, "", invocation, creator)
+	if err != nil {
+		return nil, errors.WithMessage(err, "cannot create proposal")
+	}
+
+	signedProp, err := utils.GetSignedProposal(prop, cf.Signer)
+	if err != nil {
+		return nil, errors.WithMessage(err, "cannot create signed proposal")
+	}
+
+	proposalResp, err := cf.EndorserClient.ProcessProposal(context.Background(), signedProp)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed sending proposal")
+	}
+
+	if proposalResp.Response == nil {
+		return nil, errors.New("received nil response")
+	}
+	if proposalResp.Response.Status != 200 {
+		return nil, errors.Errorf("received bad response, status %d: %s", proposalResp.Response.Status, proposalResp.Response.Message)
+	}
+
+	return proposalResp.Response.Payload, nil
+}
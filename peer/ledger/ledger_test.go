@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	msptesttools "justledger/msp/mgmt/testtools"
+	"justledger/peer/common"
+	cb "justledger/protos/common"
+	pb "justledger/protos/peer"
+	"justledger/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mockChannel = "mockchannel"
+
+func TestMain(m *testing.M) {
+	err := msptesttools.LoadMSPSetupForTesting()
+	if err != nil {
+		panic(fmt.Sprintf("Fatal error when reading MSP config: %s", err))
+	}
+
+	os.Exit(m.Run())
+}
+
+// getMockLedgerCmdFactory returns a factory whose endorser replies to every
+// proposal with the given payload
+func getMockLedgerCmdFactory(t *testing.T, payload proto.Message, status int32) *LedgerCmdFactory {
+	signer, err := common.GetDefaultSigner()
+	require.NoError(t, err)
+
+	var payloadBytes []byte
+	if payload != nil {
+		payloadBytes = utils.MarshalOrPanic(payload)
+	}
+	mockResponse := &pb.ProposalResponse{
+		Response:    &pb.Response{Status: status, Payload: payloadBytes, Message: "mock message"},
+		Endorsement: &pb.Endorsement{},
+	}
+
+	return &LedgerCmdFactory{
+		EndorserClient: common.GetMockEndorserClient(mockResponse, nil),
+		Signer:         signer,
+	}
+}
+
+// createEndorserTx builds a signed endorser transaction carrying a
+// chaincode event
+func createEndorserTx(t *testing.T) (*cb.Envelope, string) {
+	signer, err := common.GetDefaultSigner()
+	require.NoError(t, err)
+	creator, err := signer.Serialize()
+	require.NoError(t, err)
+
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			ChaincodeId: &pb.ChaincodeID{Name: "mycc"},
+			Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte("invoke")}},
+		},
+	}
+	prop, txid, err := utils.CreateProposalFromCIS(cb.HeaderType_ENDORSER_TRANSACTION, mockChannel, cis, creator)
+	require.NoError(t, err)
+
+	events, err := utils.GetBytesChaincodeEvent(&pb.ChaincodeEvent{ChaincodeId: "mycc", TxId: txid, EventName: "moved", Payload: []byte("10")})
+	require.NoError(t, err)
+	resp, err := utils.CreateProposalResponse(prop.Header, prop.Payload, &pb.Response{Status: 200}, []byte{}, events, &pb.ChaincodeID{Name: "mycc"}, nil, signer)
+	require.NoError(t, err)
+
+	env, err := utils.CreateSignedTx(prop, signer, resp)
+	require.NoError(t, err)
+	return env, txid
+}
+
+func TestMissingChannelID(t *testing.T) {
+	resetFlags()
+
+	cf := getMockLedgerCmdFactory(t, nil, 200)
+	cmd := blockCmd(cf)
+	cmd.SetArgs([]string{"1"})
+	assert.EqualError(t, cmd.Execute(), "Must supply channel ID")
+
+	cmd = txCmd(cf)
+	cmd.SetArgs([]string{"txid"})
+	assert.EqualError(t, cmd.Execute(), "Must supply channel ID")
+
+	cmd = historyCmd(cf)
+	cmd.SetArgs([]string{"mycc", "key"})
+	assert.EqualError(t, cmd.Execute(), "Must supply channel ID")
+}
+
+func TestQueryQSCCBadResponse(t *testing.T) {
+	cf := getMockLedgerCmdFactory(t, nil, 500)
+	_, err := cf.queryQSCC("GetBlockByNumber", "1")
+	assert.EqualError(t, err, "received bad response, status 500: mock message")
+}
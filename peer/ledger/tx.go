@@ -0,0 +1,67 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"justledger/core/scc/qscc"
+	pb "justledger/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func txCmd(cf *LedgerCmdFactory) *cobra.Command {
+	txCmd := &cobra.Command{
+		Use:   "tx <txid>",
+		Short: "Print a decoded transaction.",
+		Long:  "Print the transaction with the given ID as JSON, along with its validation code. Requires '-c'.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getTx(cmd, args, cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+	}
+	attachFlags(txCmd, flagList)
+
+	return txCmd
+}
+
+func getTx(cmd *cobra.Command, args []string, cf *LedgerCmdFactory) error {
+	if len(args) != 1 || args[0] == "" {
+		return errors.New("transaction ID required")
+	}
+
+	cf, err := initFactory(cmd, cf)
+	if err != nil {
+		return err
+	}
+
+	payload, err := cf.queryQSCC(qscc.GetTransactionByID, args[0])
+	if err != nil {
+		return err
+	}
+	pt := &pb.ProcessedTransaction{}
+	if err := proto.Unmarshal(payload, pt); err != nil {
+		return errors.Wrap(err, "cannot read qscc response")
+	}
+
+	output, err := decodeProcessedTransaction(pt)
+	if err != nil {
+		return err
+	}
+	jsonBytes, err := json.MarshalIndent(output, "", "\t")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(jsonBytes))
+
+	return nil
+}
@@ -0,0 +1,42 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"testing"
+
+	pb "justledger/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTx(t *testing.T) {
+	defer resetFlags()
+	resetFlags()
+
+	env, txid := createEndorserTx(t)
+	pt := &pb.ProcessedTransaction{
+		TransactionEnvelope: env,
+		ValidationCode:      int32(pb.TxValidationCode_MVCC_READ_CONFLICT),
+	}
+
+	cmd := txCmd(getMockLedgerCmdFactory(t, pt, 200))
+	cmd.SetArgs([]string{txid, "-c", mockChannel})
+	assert.NoError(t, cmd.Execute())
+
+	cmd = txCmd(getMockLedgerCmdFactory(t, pt, 404))
+	cmd.SetArgs([]string{txid, "-c", mockChannel})
+	assert.Error(t, cmd.Execute())
+
+	output, err := decodeProcessedTransaction(pt)
+	require.NoError(t, err)
+	assert.Equal(t, txid, output.Summary.TxID)
+	assert.Equal(t, "MVCC_READ_CONFLICT", output.Summary.ValidationCode)
+
+	_, err = decodeProcessedTransaction(&pb.ProcessedTransaction{})
+	assert.EqualError(t, err, "processed transaction is missing its envelope")
+}
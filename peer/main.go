@@ -15,6 +15,7 @@ import (
 	"justledger/peer/channel"
 	"justledger/peer/clilogging"
 	"justledger/peer/common"
+	"justledger/peer/ledger"
 	"justledger/peer/node"
 	"justledger/peer/version"
 	"github.com/spf13/cobra"
@@ -46,6 +47,7 @@ func main() {
 	mainCmd.AddCommand(chaincode.Cmd(nil))
 	mainCmd.AddCommand(clilogging.Cmd(nil))
 	mainCmd.AddCommand(channel.Cmd(nil))
+	mainCmd.AddCommand(ledger.Cmd(nil))
 
 	// On failure Cobra prints the usage message and error string, so we only
 	// need to exit with a non-0 status
@@ -0,0 +1,198 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"text/tabwriter"
+	"time"
+
+	"justledger/common/certmonitor"
+	"justledger/common/channelconfig"
+	"justledger/core/config"
+	"justledger/core/peer"
+	"justledger/msp"
+	"justledger/msp/mgmt"
+	"justledger/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var configBlockFiles []string
+
+func certsCmd() *cobra.Command {
+	nodeCertsCmd.ResetFlags()
+	flags := nodeCertsCmd.Flags()
+	flags.StringArrayVarP(&configBlockFiles, "configBlock", "b", nil,
+		"Path to a config block of a channel, as fetched with 'peer channel fetch config', whose MSP certificates are listed. May be repeated.")
+	return nodeCertsCmd
+}
+
+var nodeCertsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Lists the certificates of the peer and when they expire.",
+	Long: `Lists the enrollment certificate and the CA, admin and TLS CA certificates of the local MSP, the TLS ` +
+		`server and client certificates and, if config blocks are supplied, the certificates of the MSPs of the ` +
+		`channels, along with the number of days left before they expire. Certificates within the largest of the ` +
+		`peer.certExpiration.warningThresholds are flagged.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("trailing args detected: %s", args)
+		}
+		// Parsing of the command line is done so silence cmd usage
+		cmd.SilenceUsage = true
+		conf, err := certExpirationConfig()
+		if err != nil {
+			return err
+		}
+		certs, err := localCertificates()
+		if err != nil {
+			return err
+		}
+		for _, file := range configBlockFiles {
+			channelCerts, err := configBlockCertificates(file)
+			if err != nil {
+				return err
+			}
+			certs = append(certs, channelCerts...)
+		}
+		printCertificates(cmd.OutOrStdout(), certmonitor.Statuses(time.Now(), conf.WarningThresholds, certs...))
+		return nil
+	},
+}
+
+// certExpirationConfig returns the configuration of the certificate expiration monitoring
+func certExpirationConfig() (certmonitor.Config, error) {
+	conf := certmonitor.Config{
+		WarningThresholds: certmonitor.DefaultWarningThresholds,
+		CheckInterval:     viper.GetDuration("peer.certExpiration.checkInterval"),
+	}
+	if !viper.IsSet("peer.certExpiration.warningThresholds") {
+		return conf, nil
+	}
+	conf.WarningThresholds = nil
+	for _, s := range viper.GetStringSlice("peer.certExpiration.warningThresholds") {
+		threshold, err := time.ParseDuration(s)
+		if err != nil {
+			return conf, errors.Wrap(err, "invalid peer.certExpiration.warningThresholds")
+		}
+		conf.WarningThresholds = append(conf.WarningThresholds, threshold)
+	}
+	return conf, nil
+}
+
+// localCertificates returns the certificates of the local MSP and the TLS certificates of the peer
+func localCertificates() ([]certmonitor.Certificate, error) {
+	signer, err := mgmt.GetLocalMSP().GetDefaultSigningIdentity()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed getting the local signing identity")
+	}
+	serializedSigner, err := signer.Serialize()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed serializing the local signing identity")
+	}
+	certs, err := certmonitor.FromSerializedIdentity(certmonitor.KindEnrollment, "", serializedSigner)
+	if err != nil {
+		return nil, err
+	}
+
+	mspConfig, err := msp.GetVerifyingMspConfig(config.GetPath("peer.mspConfigPath"), viper.GetString("peer.localMspId"), msp.ProviderTypeToString(msp.FABRIC))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed loading the local MSP configuration")
+	}
+	mspCerts, err := certmonitor.FromMSPConfig("", mspConfig)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed collecting the certificates of the local MSP")
+	}
+	certs = append(certs, mspCerts...)
+
+	if !viper.GetBool("peer.tls.enabled") {
+		return certs, nil
+	}
+	tlsCerts, err := certmonitor.FromFile(certmonitor.KindTLSServer, "", "", config.GetPath("peer.tls.cert.file"))
+	if err != nil {
+		return nil, err
+	}
+	certs = append(certs, tlsCerts...)
+	if viper.GetString("peer.tls.clientCert.file") != "" {
+		clientCerts, err := certmonitor.FromFile(certmonitor.KindTLSClient, "", "", config.GetPath("peer.tls.clientCert.file"))
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, clientCerts...)
+	}
+	return certs, nil
+}
+
+// channelCertificates returns the certificates of the MSPs of the channels the peer joined
+func channelCertificates() []certmonitor.Certificate {
+	var certs []certmonitor.Certificate
+	for _, channel := range peer.GetChannelsInfo() {
+		resources := peer.GetChannelConfig(channel.ChannelId)
+		if resources == nil {
+			continue
+		}
+		channelCerts, err := certmonitor.FromChannelConfig(channel.ChannelId, resources.ConfigtxValidator().ConfigProto())
+		if err != nil {
+			logger.Warningf("Failed checking certificate expiration: %s", err)
+			continue
+		}
+		certs = append(certs, channelCerts...)
+	}
+	return certs
+}
+
+// configBlockCertificates returns the certificates of the MSPs in the config block stored in the given file
+func configBlockCertificates(file string) ([]certmonitor.Certificate, error) {
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed reading config block")
+	}
+	block, err := utils.UnmarshalBlock(raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed unmarshaling config block from %s", file)
+	}
+	if !utils.IsConfigBlock(block) {
+		return nil, errors.Errorf("%s is not a config block", file)
+	}
+	env, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error extracting the config envelope")
+	}
+	bundle, err := channelconfig.NewBundleFromEnvelope(env)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error building the channel configuration")
+	}
+	return certmonitor.FromChannelConfig(bundle.ConfigtxValidator().ChainID(), bundle.ConfigtxValidator().ConfigProto())
+}
+
+func printCertificates(out io.Writer, statuses []certmonitor.Status) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tCHANNEL\tMSPID\tSUBJECT\tNOT AFTER\tDAYS LEFT\tSTATUS")
+	for _, s := range statuses {
+		status := "OK"
+		switch {
+		case s.Expired():
+			status = "EXPIRED"
+		case s.Warning:
+			status = "WARNING"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%.1f\t%s\n", s.Kind, orDash(s.Channel), orDash(s.MSPID),
+			s.Cert.Subject.CommonName, s.Cert.NotAfter.UTC().Format(time.RFC3339), s.DaysLeft(), status)
+	}
+	w.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,101 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"justledger/common/configtx/test"
+	"justledger/core/config/configtest"
+	msptesttools "justledger/msp/mgmt/testtools"
+	"justledger/protos/utils"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertsCmd(t *testing.T) {
+	defer viper.Reset()
+	require.NoError(t, msptesttools.LoadMSPSetupForTesting())
+	mspDir, err := configtest.GetDevMspDir()
+	require.NoError(t, err)
+	viper.Set("peer.mspConfigPath", mspDir)
+	viper.Set("peer.localMspId", "SampleOrg")
+
+	tempDir, err := ioutil.TempDir("", "certscmd")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	genesisBlock, err := test.MakeGenesisBlock("testchannel")
+	require.NoError(t, err)
+	blockFile := filepath.Join(tempDir, "testchannel.block")
+	require.NoError(t, ioutil.WriteFile(blockFile, utils.MarshalOrPanic(genesisBlock), 0600))
+
+	run := func(args ...string) (string, error) {
+		out := &bytes.Buffer{}
+		cmd := certsCmd()
+		cmd.SetOutput(out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run()
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	assert.Regexp(t, "^KIND +CHANNEL +MSPID +SUBJECT +NOT AFTER +DAYS LEFT +STATUS$", lines[0])
+	assert.Contains(t, out, "enrollment")
+	assert.Contains(t, out, "SampleOrg")
+	assert.NotContains(t, out, "testchannel")
+	assert.NotContains(t, out, "tls server")
+
+	viper.Set("peer.tls.enabled", true)
+	viper.Set("peer.tls.cert.file", filepath.Join(mspDir, "signcerts", "peer.pem"))
+	out, err = run("-b", blockFile)
+	require.NoError(t, err)
+	assert.Contains(t, out, "tls server")
+	assert.Regexp(t, "(?m)^ca +testchannel +SampleOrg ", out)
+
+	// Every certificate of the sample MSP expires within a century
+	viper.Set("peer.certExpiration.warningThresholds", []string{"876000h"})
+	out, err = run()
+	require.NoError(t, err)
+	assert.NotContains(t, out, " OK\n")
+	assert.Regexp(t, " (WARNING|EXPIRED)\n", out)
+
+	_, err = run("trailing")
+	assert.EqualError(t, err, "trailing args detected: [trailing]")
+
+	_, err = run("-b", filepath.Join(tempDir, "missing.block"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed reading config block")
+
+	viper.Set("peer.certExpiration.warningThresholds", []string{"a month"})
+	_, err = run()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid peer.certExpiration.warningThresholds")
+}
+
+func TestCertExpirationConfig(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("peer.certExpiration.checkInterval", "10m")
+	conf, err := certExpirationConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, conf.CheckInterval)
+	assert.Len(t, conf.WarningThresholds, 3)
+
+	viper.Set("peer.certExpiration.warningThresholds", []string{"48h", "1h"})
+	conf, err = certExpirationConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{48 * time.Hour, time.Hour}, conf.WarningThresholds)
+}
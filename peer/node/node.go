@@ -26,7 +26,7 @@ import (
 
 const (
 	nodeFuncName = "node"
-	nodeCmdDes   = "Operate a peer node: start|status."
+	nodeCmdDes   = "Operate a peer node: start|status|reset|rollback|pause|resume|verify-ledger|certs."
 )
 
 var logger = flogging.MustGetLogger("nodeCmd")
@@ -35,6 +35,12 @@ var logger = flogging.MustGetLogger("nodeCmd")
 func Cmd() *cobra.Command {
 	nodeCmd.AddCommand(startCmd())
 	nodeCmd.AddCommand(statusCmd())
+	nodeCmd.AddCommand(resetCmd())
+	nodeCmd.AddCommand(rollbackCmd())
+	nodeCmd.AddCommand(pauseCmd())
+	nodeCmd.AddCommand(resumeCmd())
+	nodeCmd.AddCommand(verifyLedgerCmd())
+	nodeCmd.AddCommand(certsCmd())
 
 	return nodeCmd
 }
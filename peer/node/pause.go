@@ -0,0 +1,42 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"fmt"
+
+	"justledger/core/ledger/kvledger"
+	"justledger/peer/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func pauseCmd() *cobra.Command {
+	nodePauseCmd.ResetFlags()
+	flags := nodePauseCmd.Flags()
+	flags.StringVarP(&channelID, "channelID", "c", common.UndefinedParamValue, "Channel to pause.")
+	return nodePauseCmd
+}
+
+var nodePauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Pauses a channel on the peer.",
+	Long: `Pauses a channel on the peer. When the command is executed, the peer must be offline. ` +
+		`When the peer starts after pause, it will not receive blocks for the paused channel and ` +
+		`will reject the proposals for the paused channel.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("trailing args detected: %s", args)
+		}
+		if channelID == common.UndefinedParamValue {
+			return errors.New("Must supply channel ID")
+		}
+		// Parsing of the command line is done so silence cmd usage
+		cmd.SilenceUsage = true
+		return kvledger.PauseChannel(channelID)
+	},
+}
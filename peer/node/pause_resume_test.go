@@ -0,0 +1,40 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPauseAndResumeCmd(t *testing.T) {
+	defer viper.Reset()
+	tempDir, err := ioutil.TempDir("", "pauseresumecmd")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	viper.Set("peer.fileSystemPath", tempDir)
+
+	for name, newCmd := range map[string]func() *cobra.Command{"pause": pauseCmd, "resume": resumeCmd} {
+		cmd := newCmd()
+		cmd.SetArgs([]string{})
+		assert.EqualError(t, cmd.Execute(), "Must supply channel ID", name)
+
+		cmd = newCmd()
+		cmd.SetArgs([]string{"-c", "ch1", "trailing"})
+		assert.EqualError(t, cmd.Execute(), "trailing args detected: [trailing]", name)
+
+		cmd = newCmd()
+		cmd.SetArgs([]string{"-c", "ch1"})
+		assert.EqualError(t, cmd.Execute(), "ledger [ch1] does not exist", name)
+	}
+}
@@ -0,0 +1,34 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"fmt"
+
+	"justledger/core/ledger/kvledger"
+	"github.com/spf13/cobra"
+)
+
+func resetCmd() *cobra.Command {
+	return nodeResetCmd
+}
+
+var nodeResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Resets the node.",
+	Long: `Resets all channels to the genesis block. When the command is executed, the peer must be offline. ` +
+		`When the peer starts after the reset, it rebuilds the state and history databases of all the ` +
+		`channels and receives the blocks after the genesis block again from the ordering service or the other peers.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("trailing args detected: %s", args)
+		}
+		// Parsing of the command line is done so silence cmd usage
+		cmd.SilenceUsage = true
+		return kvledger.ResetAllKVLedgers()
+	},
+}
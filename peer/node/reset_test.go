@@ -0,0 +1,48 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"justledger/common/ledger/util/leveldbhelper"
+	"justledger/core/ledger/ledgerconfig"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResetCmd(t *testing.T) {
+	defer viper.Reset()
+	tempDir, err := ioutil.TempDir("", "resetcmd")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	viper.Set("peer.fileSystemPath", tempDir)
+
+	cmd := resetCmd()
+	cmd.SetArgs([]string{"trailing"})
+	assert.EqualError(t, cmd.Execute(), "trailing args detected: [trailing]")
+
+	cmd = resetCmd()
+	cmd.SetArgs([]string{})
+	assert.NoError(t, cmd.Execute())
+	_, err = os.Stat(filepath.Join(tempDir, "ledgersData", "stateLeveldb"))
+	assert.True(t, os.IsNotExist(err))
+
+	// the command fails while the ledger data is in use by a peer
+	fileLock := leveldbhelper.NewFileLock(ledgerconfig.GetFileLockPath())
+	require.NoError(t, fileLock.Lock())
+	defer fileLock.Unlock()
+	cmd = resetCmd()
+	cmd.SetArgs([]string{})
+	err = cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the ledger data is in use")
+}
@@ -0,0 +1,42 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"fmt"
+
+	"justledger/core/ledger/kvledger"
+	"justledger/peer/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func resumeCmd() *cobra.Command {
+	nodeResumeCmd.ResetFlags()
+	flags := nodeResumeCmd.Flags()
+	flags.StringVarP(&channelID, "channelID", "c", common.UndefinedParamValue, "Channel to resume.")
+	return nodeResumeCmd
+}
+
+var nodeResumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resumes a channel on the peer.",
+	Long: `Resumes a paused channel on the peer. When the command is executed, the peer must be offline. ` +
+		`When the peer starts after resume, it will receive blocks for the resumed channel and ` +
+		`will endorse the proposals for the resumed channel.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("trailing args detected: %s", args)
+		}
+		if channelID == common.UndefinedParamValue {
+			return errors.New("Must supply channel ID")
+		}
+		// Parsing of the command line is done so silence cmd usage
+		cmd.SilenceUsage = true
+		return kvledger.ResumeChannel(channelID)
+	},
+}
@@ -0,0 +1,37 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollbackCmd(t *testing.T) {
+	defer viper.Reset()
+	tempDir, err := ioutil.TempDir("", "rollbackcmd")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	viper.Set("peer.fileSystemPath", tempDir)
+
+	cmd := rollbackCmd()
+	cmd.SetArgs([]string{"-b", "10"})
+	assert.EqualError(t, cmd.Execute(), "Must supply channel ID")
+
+	cmd = rollbackCmd()
+	cmd.SetArgs([]string{"-c", "ch1", "-b", "10", "trailing"})
+	assert.EqualError(t, cmd.Execute(), "trailing args detected: [trailing]")
+
+	cmd = rollbackCmd()
+	cmd.SetArgs([]string{"-c", "ch1", "-b", "10"})
+	assert.EqualError(t, cmd.Execute(), "ledger [ch1] does not exist")
+}
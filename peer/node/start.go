@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package node
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
 	"net/http"
@@ -16,12 +17,15 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"justledger/common/audit"
 	"justledger/common/cauthdsl"
+	"justledger/common/certmonitor"
 	ccdef "justledger/common/chaincode"
 	"justledger/common/crypto/tlsgen"
 	"justledger/common/deliver"
 	"justledger/common/flogging"
 	"justledger/common/localmsp"
+	"justledger/common/metrics"
 	"justledger/common/policies"
 	"justledger/common/viperutil"
 	"justledger/core/aclmgmt"
@@ -39,6 +43,7 @@ import (
 	"justledger/core/comm"
 	"justledger/core/committer/txvalidator"
 	"justledger/core/common/ccprovider"
+	coreconfig "justledger/core/config"
 	"justledger/core/container"
 	"justledger/core/container/dockercontroller"
 	"justledger/core/container/inproccontroller"
@@ -226,13 +231,18 @@ func serve(args []string) error {
 	abServer := peer.NewDeliverEventsServer(mutualTLS, policyCheckerProvider, &peer.DeliverChainManager{})
 	pb.RegisterDeliverServer(peerServer.Server(), abServer)
 
+	auditLog, err := newAuditRecorder()
+	if err != nil {
+		return err
+	}
+
 	// Initialize chaincode service
-	chaincodeSupport, ccp, sccp, packageProvider := startChaincodeServer(peerHost, aclProvider, pr)
+	chaincodeSupport, ccp, sccp, packageProvider := startChaincodeServer(peerHost, aclProvider, pr, auditLog)
 
 	logger.Debugf("Running peer")
 
 	// Start the Admin server
-	startAdminServer(listenAddr, peerServer.Server())
+	adminServer := startAdminServer(listenAddr, peerServer.Server(), auditLog)
 
 	privDataDist := func(channel string, txID string, privateData *transientstore.TxPvtReadWriteSetWithConfigInfo, blkHt uint64) error {
 		return service.GetGossipService().DistributePrivateData(channel, txID, privateData, blkHt)
@@ -278,13 +288,39 @@ func serve(args []string) error {
 
 	policyMgr := peer.NewChannelPolicyManagerGetter()
 
+	var gossipCerts *gossipcommon.TLSCertificates
+	if peerServer.TLSEnabled() {
+		serverCert := peerServer.ServerCertificate()
+		clientCert, err := peer.GetClientCertificate()
+		if err != nil {
+			return errors.Wrap(err, "failed obtaining client certificates")
+		}
+		gossipCerts = &gossipcommon.TLSCertificates{}
+		gossipCerts.TLSServerCert.Store(&serverCert)
+		gossipCerts.TLSClientCert.Store(&clientCert)
+	}
+
 	// Initialize gossip component
-	err = initGossipService(policyMgr, peerServer, serializedIdentity, peerEndpoint.Address)
+	err = initGossipService(policyMgr, peerServer, gossipCerts, serializedIdentity, peerEndpoint.Address)
 	if err != nil {
 		return err
 	}
 	defer service.GetGossipService().Stop()
 
+	// Reload the TLS key pairs when their files change
+	tlsServers := []*comm.GRPCServer{peerServer}
+	if adminServer != nil {
+		tlsServers = append(tlsServers, adminServer)
+	}
+	keyPairWatchers, err := watchTLSKeyPairs(tlsServers, gossipCerts)
+	if err != nil {
+		return err
+	}
+	for _, w := range keyPairWatchers {
+		w.Start()
+		defer w.Stop()
+	}
+
 	// initialize system chaincodes
 
 	// deploy system chaincodes
@@ -320,6 +356,31 @@ func serve(args []string) error {
 		registerDiscoveryService(peerServer, policyMgr, lifecycle)
 	}
 
+	if err := metrics.Init(metrics.NewOpts()); err != nil {
+		return errors.WithMessage(err, "failed initializing metrics")
+	}
+	if viper.GetBool("metrics.enabled") {
+		go func() {
+			if err := metrics.Start(); err != nil {
+				logger.Errorf("Error starting metrics server: %s", err)
+			}
+		}()
+	}
+
+	certMonitorConf, err := certExpirationConfig()
+	if err != nil {
+		return err
+	}
+	certMonitor := certmonitor.NewMonitor(certMonitorConf, metrics.RootScope, func() []certmonitor.Certificate {
+		certs, err := localCertificates()
+		if err != nil {
+			logger.Warningf("Failed checking certificate expiration: %s", err)
+		}
+		return certs
+	}, channelCertificates)
+	certMonitor.Start()
+	defer certMonitor.Stop()
+
 	logger.Infof("Starting peer with ID=[%s], network ID=[%s], address=[%s]",
 		peerEndpoint.Id, viper.GetString("peer.networkId"), peerEndpoint.Address)
 
@@ -546,7 +607,7 @@ func computeChaincodeEndpoint(peerHostname string) (ccEndpoint string, err error
 //NOTE - when we implement JOIN we will no longer pass the chainID as param
 //The chaincode support will come up without registering system chaincodes
 //which will be registered only during join phase.
-func registerChaincodeSupport(grpcServer *comm.GRPCServer, ccEndpoint string, ca tlsgen.CA, packageProvider *persistence.PackageProvider, aclProvider aclmgmt.ACLProvider, pr *platforms.Registry) (*chaincode.ChaincodeSupport, ccprovider.ChaincodeProvider, *scc.Provider) {
+func registerChaincodeSupport(grpcServer *comm.GRPCServer, ccEndpoint string, ca tlsgen.CA, packageProvider *persistence.PackageProvider, aclProvider aclmgmt.ACLProvider, pr *platforms.Registry, auditLog audit.Recorder) (*chaincode.ChaincodeSupport, ccprovider.ChaincodeProvider, *scc.Provider) {
 	//get user mode
 	userRunsCC := chaincode.IsDevMode()
 	tlsEnabled := viper.GetBool("peer.tls.enabled")
@@ -556,6 +617,7 @@ func registerChaincodeSupport(grpcServer *comm.GRPCServer, ccEndpoint string, ca
 
 	sccp := scc.NewProvider(peer.Default, peer.DefaultSupport, ipRegistry)
 	lsccInst := lscc.New(sccp, aclProvider, pr)
+	lsccInst.Recorder = auditLog
 	lifecycleSCC := &lifecycle.SCC{}
 
 	chaincodeSupport := chaincode.NewChaincodeSupport(
@@ -586,7 +648,7 @@ func registerChaincodeSupport(grpcServer *comm.GRPCServer, ccEndpoint string, ca
 		ccSrv = authenticator.Wrap(ccSrv)
 	}
 
-	csccInst := cscc.New(ccp, sccp, aclProvider)
+	csccInst := cscc.New(ccp, sccp, aclProvider, auditLog)
 	qsccInst := qscc.New(aclProvider)
 
 	//Now that chaincode is initialized, register all system chaincodes.
@@ -603,7 +665,7 @@ func registerChaincodeSupport(grpcServer *comm.GRPCServer, ccEndpoint string, ca
 // 1) setup local chaincode install path
 // 2) create chaincode specific tls CA
 // 3) start the chaincode specific gRPC listening service
-func startChaincodeServer(peerHost string, aclProvider aclmgmt.ACLProvider, pr *platforms.Registry) (*chaincode.ChaincodeSupport, ccprovider.ChaincodeProvider, *scc.Provider, *persistence.PackageProvider) {
+func startChaincodeServer(peerHost string, aclProvider aclmgmt.ACLProvider, pr *platforms.Registry, auditLog audit.Recorder) (*chaincode.ChaincodeSupport, ccprovider.ChaincodeProvider, *scc.Provider, *persistence.PackageProvider) {
 	// Setup chaincode path
 	chaincodeInstallPath := ccprovider.GetChaincodeInstallPathFromViper()
 	ccprovider.SetChaincodesPath(chaincodeInstallPath)
@@ -632,11 +694,25 @@ func startChaincodeServer(peerHost string, aclProvider aclmgmt.ACLProvider, pr *
 		packageProvider,
 		aclProvider,
 		pr,
+		auditLog,
 	)
 	go ccSrv.Start()
 	return chaincodeSupport, ccp, sccp, packageProvider
 }
 
+// newAuditRecorder returns the recorder of administrative operations, which
+// appends them to the audit log file configured in peer.audit.file, if any
+func newAuditRecorder() (audit.Recorder, error) {
+	if viper.GetString("peer.audit.file") == "" {
+		return audit.NoopRecorder{}, nil
+	}
+	auditLog, err := audit.NewLog(coreconfig.GetPath("peer.audit.file"))
+	if err != nil {
+		return nil, err
+	}
+	return auditLog, nil
+}
+
 func adminHasSeparateListener(peerListenAddr string, adminListenAddress string) bool {
 	// By default, admin listens on the same port as the peer data service
 	if adminListenAddress == "" {
@@ -656,12 +732,15 @@ func adminHasSeparateListener(peerListenAddr string, adminListenAddress string)
 	return adminPort != peerPort
 }
 
-func startAdminServer(peerListenAddr string, peerServer *grpc.Server) {
+// startAdminServer registers the admin service on the peer server, or on a
+// separate server returned if the admin service has a separate listener
+func startAdminServer(peerListenAddr string, peerServer *grpc.Server, auditLog audit.Recorder) *comm.GRPCServer {
 	adminListenAddress := viper.GetString("peer.adminService.listenAddress")
 	separateLsnrForAdmin := adminHasSeparateListener(peerListenAddr, adminListenAddress)
 	mspID := viper.GetString("peer.localMspId")
 	adminPolicy := localPolicy(cauthdsl.SignedByAnyAdmin([]string{mspID}))
 	gRPCService := peerServer
+	var adminServer *comm.GRPCServer
 	if separateLsnrForAdmin {
 		logger.Info("Creating gRPC server for admin service on", adminListenAddress)
 		serverConfig, err := peer.GetServerConfig()
@@ -669,7 +748,7 @@ func startAdminServer(peerListenAddr string, peerServer *grpc.Server) {
 		if err != nil {
 			logger.Fatalf("Error loading secure config for admin service (%s)", err)
 		}
-		adminServer, err := peer.NewPeerServer(adminListenAddress, serverConfig)
+		adminServer, err = peer.NewPeerServer(adminListenAddress, serverConfig)
 		if err != nil {
 			logger.Fatalf("Failed to create admin server (%s)", err)
 		}
@@ -679,7 +758,54 @@ func startAdminServer(peerListenAddr string, peerServer *grpc.Server) {
 		}()
 	}
 
-	pb.RegisterAdminServer(gRPCService, admin.NewAdminServer(adminPolicy))
+	pb.RegisterAdminServer(gRPCService, admin.NewAdminServer(adminPolicy, auditLog))
+	return adminServer
+}
+
+// watchTLSKeyPairs returns watchers of the TLS server and client key pair files of the
+// peer, which hand the key pairs to the given servers, the gRPC clients and gossip when
+// the files change, if TLS is enabled and peer.tls.watchInterval is set
+func watchTLSKeyPairs(servers []*comm.GRPCServer, gossipCerts *gossipcommon.TLSCertificates) ([]*comm.KeyPairWatcher, error) {
+	interval := viper.GetDuration("peer.tls.watchInterval")
+	if !viper.GetBool("peer.tls.enabled") || interval <= 0 {
+		return nil, nil
+	}
+
+	setServerCertificate := func(cert tls.Certificate) {
+		for _, server := range servers {
+			server.SetServerCertificate(cert)
+		}
+		if gossipCerts != nil {
+			gossipCerts.TLSServerCert.Store(&cert)
+		}
+	}
+	setClientCertificate := func(cert tls.Certificate) {
+		comm.GetCredentialSupport().SetClientCertificate(cert)
+		if gossipCerts != nil {
+			gossipCerts.TLSClientCert.Store(&cert)
+		}
+	}
+
+	// the server key pair is also used for client connections unless a
+	// separate client key pair is configured
+	separateClientKeyPair := viper.GetString("peer.tls.clientCert.file") != ""
+	serverListeners := []func(tls.Certificate){setServerCertificate}
+	if !separateClientKeyPair {
+		serverListeners = append(serverListeners, setClientCertificate)
+	}
+	serverWatcher, err := comm.NewKeyPairWatcher(coreconfig.GetPath("peer.tls.cert.file"), coreconfig.GetPath("peer.tls.key.file"), interval, serverListeners...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed watching the TLS server key pair")
+	}
+	watchers := []*comm.KeyPairWatcher{serverWatcher}
+	if separateClientKeyPair {
+		clientWatcher, err := comm.NewKeyPairWatcher(coreconfig.GetPath("peer.tls.clientCert.file"), coreconfig.GetPath("peer.tls.clientKey.file"), interval, setClientCertificate)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed watching the TLS client key pair")
+		}
+		watchers = append(watchers, clientWatcher)
+	}
+	return watchers, nil
 }
 
 // secureDialOpts is the callback function for secure dial options for gossip service
@@ -710,23 +836,11 @@ func secureDialOpts() []grpc.DialOption {
 }
 
 // initGossipService will initialize the gossip service by:
-// 1. Enable TLS if configured;
+// 1. Enable TLS with the given certificates, if any;
 // 2. Init the message crypto service;
 // 3. Init the security advisor;
 // 4. Init gossip related struct.
-func initGossipService(policyMgr policies.ChannelPolicyManagerGetter, peerServer *comm.GRPCServer, serializedIdentity []byte, peerAddr string) error {
-	var certs *gossipcommon.TLSCertificates
-	if peerServer.TLSEnabled() {
-		serverCert := peerServer.ServerCertificate()
-		clientCert, err := peer.GetClientCertificate()
-		if err != nil {
-			return errors.Wrap(err, "failed obtaining client certificates")
-		}
-		certs = &gossipcommon.TLSCertificates{}
-		certs.TLSServerCert.Store(&serverCert)
-		certs.TLSClientCert.Store(&clientCert)
-	}
-
+func initGossipService(policyMgr policies.ChannelPolicyManagerGetter, peerServer *comm.GRPCServer, certs *gossipcommon.TLSCertificates, serializedIdentity []byte, peerAddr string) error {
 	messageCryptoService := peergossip.NewMCS(
 		policyMgr,
 		localmsp.NewSigner(),
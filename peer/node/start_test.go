@@ -8,16 +8,22 @@ package node
 
 import (
 	"bytes"
+	"crypto/tls"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
+	"justledger/common/crypto/tlsgen"
 	"justledger/common/viperutil"
+	"justledger/core/comm"
 	"justledger/core/handlers/library"
+	gossipcommon "justledger/gossip/common"
 	"justledger/msp/mgmt/testtools"
 	. "github.com/onsi/gomega"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 )
 
@@ -178,3 +184,80 @@ func grpcProbe(addr string) bool {
 	}
 	return false
 }
+
+func TestWatchTLSKeyPairs(t *testing.T) {
+	defer viper.Reset()
+	defer comm.GetCredentialSupport().SetClientCertificate(comm.GetCredentialSupport().GetClientCertificate())
+
+	tempDir, err := ioutil.TempDir("", "watchtlskeypairs")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+	writeKeyPair := func(name string) *tlsgen.CertKeyPair {
+		kp, err := ca.NewServerCertKeyPair("127.0.0.1")
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, name+".crt"), kp.Cert, 0600))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, name+".key"), kp.Key, 0600))
+		return kp
+	}
+	serverKeyPair := writeKeyPair("server")
+	viper.Set("peer.tls.cert.file", filepath.Join(tempDir, "server.crt"))
+	viper.Set("peer.tls.key.file", filepath.Join(tempDir, "server.key"))
+
+	server, err := comm.NewGRPCServer("127.0.0.1:", comm.ServerConfig{
+		SecOpts: &comm.SecureOptions{UseTLS: true, Certificate: serverKeyPair.Cert, Key: serverKeyPair.Key},
+	})
+	require.NoError(t, err)
+	defer server.Stop()
+	gossipCerts := &gossipcommon.TLSCertificates{}
+
+	// TLS disabled or no interval
+	watchers, err := watchTLSKeyPairs([]*comm.GRPCServer{server}, gossipCerts)
+	assert.NoError(t, err)
+	assert.Empty(t, watchers)
+	viper.Set("peer.tls.enabled", true)
+	watchers, err = watchTLSKeyPairs([]*comm.GRPCServer{server}, gossipCerts)
+	assert.NoError(t, err)
+	assert.Empty(t, watchers)
+
+	// The server key pair is also the client key pair
+	viper.Set("peer.tls.watchInterval", "1m")
+	watchers, err = watchTLSKeyPairs([]*comm.GRPCServer{server}, gossipCerts)
+	require.NoError(t, err)
+	require.Len(t, watchers, 1)
+	serverKeyPair = writeKeyPair("server")
+	changed, err := watchers[0].Check()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, serverKeyPair.TLSCert.Raw, server.ServerCertificate().Certificate[0])
+	assert.Equal(t, serverKeyPair.TLSCert.Raw, gossipCerts.TLSServerCert.Load().(*tls.Certificate).Certificate[0])
+	assert.Equal(t, serverKeyPair.TLSCert.Raw, gossipCerts.TLSClientCert.Load().(*tls.Certificate).Certificate[0])
+	assert.Equal(t, serverKeyPair.TLSCert.Raw, comm.GetCredentialSupport().GetClientCertificate().Certificate[0])
+
+	// Separate client key pair
+	clientKeyPair := writeKeyPair("client")
+	viper.Set("peer.tls.clientCert.file", filepath.Join(tempDir, "client.crt"))
+	viper.Set("peer.tls.clientKey.file", filepath.Join(tempDir, "client.key"))
+	watchers, err = watchTLSKeyPairs([]*comm.GRPCServer{server}, gossipCerts)
+	require.NoError(t, err)
+	require.Len(t, watchers, 2)
+	newServerKeyPair := writeKeyPair("server")
+	changed, err = watchers[0].Check()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, newServerKeyPair.TLSCert.Raw, server.ServerCertificate().Certificate[0])
+	assert.Equal(t, serverKeyPair.TLSCert.Raw, comm.GetCredentialSupport().GetClientCertificate().Certificate[0])
+	clientKeyPair = writeKeyPair("client")
+	changed, err = watchers[1].Check()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, clientKeyPair.TLSCert.Raw, gossipCerts.TLSClientCert.Load().(*tls.Certificate).Certificate[0])
+	assert.Equal(t, clientKeyPair.TLSCert.Raw, comm.GetCredentialSupport().GetClientCertificate().Certificate[0])
+
+	viper.Set("peer.tls.clientKey.file", filepath.Join(tempDir, "missing.key"))
+	_, err = watchTLSKeyPairs([]*comm.GRPCServer{server}, gossipCerts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed watching the TLS client key pair")
+}
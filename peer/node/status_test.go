@@ -57,7 +57,7 @@ func TestStatusCmd(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to create peer server (%s)", err)
 	} else {
-		pb.RegisterAdminServer(peerServer.Server(), admin.NewAdminServer(&mockEvaluator{}))
+		pb.RegisterAdminServer(peerServer.Server(), admin.NewAdminServer(&mockEvaluator{}, nil))
 		go peerServer.Start()
 		defer peerServer.Stop()
 
@@ -106,7 +106,7 @@ func TestStatus(t *testing.T) {
 			if err != nil {
 				t.Fatalf("Failed to create peer server (%s)", err)
 			} else {
-				pb.RegisterAdminServer(peerServer.Server(), admin.NewAdminServer(&mockEvaluator{}))
+				pb.RegisterAdminServer(peerServer.Server(), admin.NewAdminServer(&mockEvaluator{}, nil))
 				go peerServer.Start()
 				defer peerServer.Stop()
 				if test.shouldSucceed {
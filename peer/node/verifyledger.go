@@ -0,0 +1,114 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"fmt"
+
+	"justledger/common/channelconfig"
+	"justledger/common/policies"
+	"justledger/common/util"
+	"justledger/core/ledger/kvledger"
+	"justledger/peer/common"
+	cb "justledger/protos/common"
+	"justledger/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func verifyLedgerCmd() *cobra.Command {
+	nodeVerifyLedgerCmd.ResetFlags()
+	flags := nodeVerifyLedgerCmd.Flags()
+	flags.StringVarP(&channelID, "channelID", "c", common.UndefinedParamValue, "Channel to verify.")
+	return nodeVerifyLedgerCmd
+}
+
+var nodeVerifyLedgerCmd = &cobra.Command{
+	Use:   "verify-ledger",
+	Short: "Verifies the ledger of a channel.",
+	Long: `Verifies that the chain stored in the ledger of a channel is internally consistent. The DataHash and the ` +
+		`PreviousHash of every block, the orderer signatures of every block against the channel configuration in effect ` +
+		`at the height of the block and the entries of the block index are checked and the first inconsistency found is ` +
+		`reported. When the command is executed, the peer must be offline.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("trailing args detected: %s", args)
+		}
+		if channelID == common.UndefinedParamValue {
+			return errors.New("Must supply channel ID")
+		}
+		// Parsing of the command line is done so silence cmd usage
+		cmd.SilenceUsage = true
+		verifier := &blockSignatureVerifier{channelID: channelID}
+		numBlocks, err := kvledger.VerifyKVLedger(channelID, verifier.verify)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("The ledger of channel [%s] is consistent: [%d] blocks verified\n", channelID, numBlocks)
+		return nil
+	},
+}
+
+// blockSignatureVerifier verifies the orderer signatures in the metadata of the blocks of a channel, which are
+// fed in sequence from the genesis block, against the BlockValidation policy of the channel configuration in
+// effect at the height of each block, i.e., the configuration of the last config block before the block.
+// The genesis block is the root of trust and its signatures are not verified
+type blockSignatureVerifier struct {
+	channelID string
+	bundle    *channelconfig.Bundle
+}
+
+func (v *blockSignatureVerifier) verify(block *cb.Block) error {
+	if v.bundle != nil {
+		if err := v.verifySignatures(block); err != nil {
+			return err
+		}
+	}
+	if !utils.IsConfigBlock(block) {
+		if v.bundle == nil {
+			return errors.New("the genesis block is not a config block")
+		}
+		return nil
+	}
+	env, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return errors.WithMessage(err, "error extracting the config envelope")
+	}
+	bundle, err := channelconfig.NewBundleFromEnvelope(env)
+	if err != nil {
+		return errors.WithMessage(err, "error building the channel configuration")
+	}
+	if chainID := bundle.ConfigtxValidator().ChainID(); chainID != v.channelID {
+		return errors.Errorf("config block of channel [%s] instead of [%s]", chainID, v.channelID)
+	}
+	v.bundle = bundle
+	return nil
+}
+
+func (v *blockSignatureVerifier) verifySignatures(block *cb.Block) error {
+	metadata, err := utils.GetMetadataFromBlock(block, cb.BlockMetadataIndex_SIGNATURES)
+	if err != nil {
+		return errors.WithMessage(err, "error unmarshaling the signatures metadata")
+	}
+	policy, _ := v.bundle.PolicyManager().GetPolicy(policies.BlockValidation)
+	var signatureSet []*cb.SignedData
+	for _, metadataSignature := range metadata.Signatures {
+		shdr, err := utils.GetSignatureHeader(metadataSignature.SignatureHeader)
+		if err != nil {
+			return errors.WithMessage(err, "error unmarshaling the signature header")
+		}
+		signatureSet = append(signatureSet, &cb.SignedData{
+			Identity:  shdr.Creator,
+			Data:      util.ConcatenateBytes(metadata.Value, metadataSignature.SignatureHeader, block.Header.Bytes()),
+			Signature: metadataSignature.Signature,
+		})
+	}
+	if err := policy.Evaluate(signatureSet); err != nil {
+		return errors.WithMessage(err, "the orderer signatures do not satisfy the block validation policy")
+	}
+	return nil
+}
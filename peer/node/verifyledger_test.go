@@ -0,0 +1,113 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"justledger/common/configtx/test"
+	"justledger/common/localmsp"
+	"justledger/common/util"
+	msptesttools "justledger/msp/mgmt/testtools"
+	cb "justledger/protos/common"
+	"justledger/protos/utils"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyLedgerCmd(t *testing.T) {
+	defer viper.Reset()
+	tempDir, err := ioutil.TempDir("", "verifyledgercmd")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	viper.Set("peer.fileSystemPath", tempDir)
+
+	cmd := verifyLedgerCmd()
+	cmd.SetArgs([]string{})
+	assert.EqualError(t, cmd.Execute(), "Must supply channel ID")
+
+	cmd = verifyLedgerCmd()
+	cmd.SetArgs([]string{"-c", "ch1", "trailing"})
+	assert.EqualError(t, cmd.Execute(), "trailing args detected: [trailing]")
+
+	cmd = verifyLedgerCmd()
+	cmd.SetArgs([]string{"-c", "ch1"})
+	assert.EqualError(t, cmd.Execute(), "ledger [ch1] does not exist")
+}
+
+func TestBlockSignatureVerifier(t *testing.T) {
+	require.NoError(t, msptesttools.LoadMSPSetupForTesting())
+	genesisBlock, err := test.MakeGenesisBlock("testchannel")
+	require.NoError(t, err)
+
+	nextBlock := func(previous *cb.Block, data [][]byte, sign bool) *cb.Block {
+		block := cb.NewBlock(previous.Header.Number+1, previous.Header.Hash())
+		block.Data.Data = data
+		block.Header.DataHash = block.Data.Hash()
+		if sign {
+			signBlock(t, block)
+		}
+		return block
+	}
+
+	t.Run("signed blocks", func(t *testing.T) {
+		v := &blockSignatureVerifier{channelID: "testchannel"}
+		assert.NoError(t, v.verify(genesisBlock))
+		block1 := nextBlock(genesisBlock, [][]byte{[]byte("tx1")}, true)
+		assert.NoError(t, v.verify(block1))
+		// a config block is verified against the previous configuration and takes effect for the next blocks
+		configBlock := nextBlock(block1, genesisBlock.Data.Data, true)
+		assert.NoError(t, v.verify(configBlock))
+		assert.NoError(t, v.verify(nextBlock(configBlock, [][]byte{[]byte("tx2")}, true)))
+	})
+
+	t.Run("unsigned block", func(t *testing.T) {
+		v := &blockSignatureVerifier{channelID: "testchannel"}
+		assert.NoError(t, v.verify(genesisBlock))
+		err := v.verify(nextBlock(genesisBlock, [][]byte{[]byte("tx1")}, false))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "the orderer signatures do not satisfy the block validation policy")
+	})
+
+	t.Run("tampered block", func(t *testing.T) {
+		v := &blockSignatureVerifier{channelID: "testchannel"}
+		assert.NoError(t, v.verify(genesisBlock))
+		block1 := nextBlock(genesisBlock, [][]byte{[]byte("tx1")}, true)
+		block1.Header.DataHash = util.ComputeSHA256([]byte("other data"))
+		err := v.verify(block1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "the orderer signatures do not satisfy the block validation policy")
+	})
+
+	t.Run("genesis block of another channel", func(t *testing.T) {
+		v := &blockSignatureVerifier{channelID: "otherchannel"}
+		assert.EqualError(t, v.verify(genesisBlock), "config block of channel [testchannel] instead of [otherchannel]")
+	})
+
+	t.Run("genesis block not a config block", func(t *testing.T) {
+		v := &blockSignatureVerifier{channelID: "testchannel"}
+		block := cb.NewBlock(0, nil)
+		block.Data.Data = [][]byte{[]byte("tx1")}
+		assert.EqualError(t, v.verify(block), "the genesis block is not a config block")
+	})
+}
+
+// signBlock signs the block with the local signer, the same way as the ordering service does
+func signBlock(t *testing.T, block *cb.Block) {
+	signer := localmsp.NewSigner()
+	sigHdr, err := signer.NewSignatureHeader()
+	require.NoError(t, err)
+	sigHdrBytes := utils.MarshalOrPanic(sigHdr)
+	signature, err := signer.Sign(util.ConcatenateBytes(nil, sigHdrBytes, block.Header.Bytes()))
+	require.NoError(t, err)
+	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(&cb.Metadata{
+		Signatures: []*cb.MetadataSignature{{SignatureHeader: sigHdrBytes, Signature: signature}},
+	})
+}
@@ -32,7 +32,7 @@ func (m *CollectionConfigPackage) Reset()         { *m = CollectionConfigPackage
 func (m *CollectionConfigPackage) String() string { return proto.CompactTextString(m) }
 func (*CollectionConfigPackage) ProtoMessage()    {}
 func (*CollectionConfigPackage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_collection_b741380d1d025da1, []int{0}
+	return fileDescriptor_collection_c4e57fcc8cd2efd1, []int{0}
 }
 func (m *CollectionConfigPackage) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CollectionConfigPackage.Unmarshal(m, b)
@@ -75,7 +75,7 @@ func (m *CollectionConfig) Reset()         { *m = CollectionConfig{} }
 func (m *CollectionConfig) String() string { return proto.CompactTextString(m) }
 func (*CollectionConfig) ProtoMessage()    {}
 func (*CollectionConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_collection_b741380d1d025da1, []int{1}
+	return fileDescriptor_collection_c4e57fcc8cd2efd1, []int{1}
 }
 func (m *CollectionConfig) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CollectionConfig.Unmarshal(m, b)
@@ -194,17 +194,21 @@ type StaticCollectionConfig struct {
 	// The number of blocks after which the collection data expires.
 	// For instance if the value is set to 10, a key last modified by block number 100
 	// will be purged at block number 111. A zero value is treated same as MaxUint64
-	BlockToLive          uint64   `protobuf:"varint,5,opt,name=block_to_live,json=blockToLive" json:"block_to_live,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	BlockToLive uint64 `protobuf:"varint,5,opt,name=block_to_live,json=blockToLive" json:"block_to_live,omitempty"`
+	// The policy that has to be satisfied by the endorsements of a transaction
+	// that purges a private data key of this collection on demand. If it is
+	// not set, private data of the collection cannot be purged on demand.
+	PurgePolicy          *CollectionPolicyConfig `protobuf:"bytes,6,opt,name=purge_policy,json=purgePolicy" json:"purge_policy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
 }
 
 func (m *StaticCollectionConfig) Reset()         { *m = StaticCollectionConfig{} }
 func (m *StaticCollectionConfig) String() string { return proto.CompactTextString(m) }
 func (*StaticCollectionConfig) ProtoMessage()    {}
 func (*StaticCollectionConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_collection_b741380d1d025da1, []int{2}
+	return fileDescriptor_collection_c4e57fcc8cd2efd1, []int{2}
 }
 func (m *StaticCollectionConfig) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StaticCollectionConfig.Unmarshal(m, b)
@@ -259,6 +263,13 @@ func (m *StaticCollectionConfig) GetBlockToLive() uint64 {
 	return 0
 }
 
+func (m *StaticCollectionConfig) GetPurgePolicy() *CollectionPolicyConfig {
+	if m != nil {
+		return m.PurgePolicy
+	}
+	return nil
+}
+
 // Collection policy configuration. Initially, the configuration can only
 // contain a SignaturePolicy. In the future, the SignaturePolicy may be a
 // more general Policy. Instead of containing the actual policy, the
@@ -276,7 +287,7 @@ func (m *CollectionPolicyConfig) Reset()         { *m = CollectionPolicyConfig{}
 func (m *CollectionPolicyConfig) String() string { return proto.CompactTextString(m) }
 func (*CollectionPolicyConfig) ProtoMessage()    {}
 func (*CollectionPolicyConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_collection_b741380d1d025da1, []int{3}
+	return fileDescriptor_collection_c4e57fcc8cd2efd1, []int{3}
 }
 func (m *CollectionPolicyConfig) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CollectionPolicyConfig.Unmarshal(m, b)
@@ -391,7 +402,7 @@ func (m *CollectionCriteria) Reset()         { *m = CollectionCriteria{} }
 func (m *CollectionCriteria) String() string { return proto.CompactTextString(m) }
 func (*CollectionCriteria) ProtoMessage()    {}
 func (*CollectionCriteria) Descriptor() ([]byte, []int) {
-	return fileDescriptor_collection_b741380d1d025da1, []int{4}
+	return fileDescriptor_collection_c4e57fcc8cd2efd1, []int{4}
 }
 func (m *CollectionCriteria) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CollectionCriteria.Unmarshal(m, b)
@@ -447,37 +458,38 @@ func init() {
 	proto.RegisterType((*CollectionCriteria)(nil), "common.CollectionCriteria")
 }
 
-func init() { proto.RegisterFile("common/collection.proto", fileDescriptor_collection_b741380d1d025da1) }
-
-var fileDescriptor_collection_b741380d1d025da1 = []byte{
-	// 453 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0x41, 0x6b, 0xdb, 0x40,
-	0x10, 0x85, 0xa3, 0xc6, 0x76, 0xd0, 0x98, 0x52, 0x77, 0x43, 0x1d, 0x51, 0x4a, 0x6a, 0x44, 0x0f,
-	0x86, 0x16, 0xa9, 0xa4, 0xff, 0x20, 0xa6, 0x90, 0x52, 0x43, 0x8d, 0xd2, 0x53, 0x2e, 0x62, 0xb5,
-	0x9a, 0xc8, 0x4b, 0x24, 0xad, 0xb2, 0xbb, 0x32, 0xf6, 0xb1, 0xff, 0xbb, 0x87, 0xe0, 0x5d, 0xc9,
-	0x52, 0x8c, 0x6f, 0x9e, 0x79, 0xdf, 0x3c, 0xcf, 0x3c, 0x2d, 0x5c, 0x31, 0x51, 0x14, 0xa2, 0x0c,
-	0x99, 0xc8, 0x73, 0x64, 0x9a, 0x8b, 0x32, 0xa8, 0xa4, 0xd0, 0x82, 0x8c, 0xac, 0xf0, 0xf1, 0x43,
-	0x03, 0x54, 0x22, 0xe7, 0x8c, 0xa3, 0xb2, 0xb2, 0xff, 0x1b, 0xae, 0x16, 0x87, 0x91, 0x85, 0x28,
-	0x1f, 0x79, 0xb6, 0xa2, 0xec, 0x89, 0x66, 0x48, 0xbe, 0xc3, 0x88, 0x99, 0x86, 0xe7, 0xcc, 0xce,
-	0xe7, 0xe3, 0x1b, 0x2f, 0xb0, 0x16, 0xc1, 0xf1, 0x40, 0xd4, 0x70, 0xfe, 0x0e, 0x26, 0xc7, 0x1a,
-	0x79, 0x00, 0x4f, 0x69, 0xaa, 0x39, 0x8b, 0xbb, 0xd5, 0xe2, 0x83, 0xaf, 0x33, 0x1f, 0xdf, 0x5c,
-	0xb7, 0xbe, 0xf7, 0x86, 0x3b, 0x76, 0xb8, 0x3b, 0x8b, 0xa6, 0xea, 0xa4, 0x72, 0xeb, 0xc2, 0x45,
-	0x45, 0x77, 0xb9, 0xa0, 0xa9, 0xff, 0xdf, 0x81, 0xe9, 0xe9, 0x79, 0x42, 0x60, 0x50, 0xd2, 0x02,
-	0xcd, 0xbf, 0xb9, 0x91, 0xf9, 0x4d, 0x96, 0x40, 0x0a, 0x2c, 0x12, 0x94, 0xb1, 0x90, 0x99, 0x8a,
-	0x4d, 0x28, 0x3b, 0xef, 0xcd, 0xeb, 0x7d, 0x3a, 0xa7, 0x95, 0xd1, 0x9b, 0x6b, 0x27, 0x76, 0xf2,
-	0x8f, 0xcc, 0x94, 0xed, 0x93, 0x00, 0x2e, 0x25, 0x3e, 0xd7, 0x5c, 0x62, 0x1a, 0x57, 0x88, 0x32,
-	0x66, 0xa2, 0x2e, 0xb5, 0x77, 0x3e, 0x73, 0xe6, 0xc3, 0xe8, 0x7d, 0x2b, 0xad, 0x10, 0xe5, 0x62,
-	0x2f, 0x90, 0x6f, 0x40, 0x0a, 0xba, 0xe5, 0x45, 0x5d, 0xf4, 0xf1, 0x81, 0xc1, 0x27, 0x8d, 0xd2,
-	0xd1, 0x3e, 0xbc, 0x4d, 0x72, 0xc1, 0x9e, 0x62, 0x2d, 0xe2, 0x9c, 0x6f, 0xd0, 0x1b, 0xce, 0x9c,
-	0xf9, 0x20, 0x1a, 0x9b, 0xe6, 0x5f, 0xb1, 0xe4, 0x1b, 0xf4, 0x9f, 0x61, 0x7a, 0x7a, 0x5b, 0xb2,
-	0x84, 0x89, 0xe2, 0x59, 0x49, 0x75, 0x2d, 0xb1, 0xbd, 0xd3, 0xe6, 0xfe, 0xf9, 0x90, 0x7b, 0xab,
-	0xdb, 0xc1, 0x9f, 0xe5, 0x06, 0x73, 0x51, 0xe1, 0xdd, 0x59, 0xf4, 0x4e, 0xbd, 0x96, 0xfa, 0x89,
-	0xff, 0x73, 0x80, 0xf4, 0xb2, 0x96, 0x5c, 0xa3, 0xe4, 0x94, 0x78, 0x70, 0xc1, 0xd6, 0xb4, 0x2c,
-	0x31, 0x6f, 0x02, 0x6f, 0x4b, 0x72, 0x09, 0x43, 0xbd, 0x8d, 0x79, 0x6a, 0x62, 0x76, 0xa3, 0x81,
-	0xde, 0xfe, 0x4a, 0xc9, 0x35, 0x40, 0xf7, 0x2e, 0x4c, 0x62, 0x6e, 0xd4, 0xeb, 0x90, 0x4f, 0xe0,
-	0xee, 0x3f, 0x98, 0xaa, 0x28, 0x43, 0x93, 0x90, 0x1b, 0x75, 0x8d, 0xdb, 0x7b, 0xf8, 0x22, 0x64,
-	0x16, 0xac, 0x77, 0x15, 0xca, 0x1c, 0xd3, 0x0c, 0x65, 0xf0, 0x48, 0x13, 0xc9, 0x99, 0x7d, 0xdd,
-	0xaa, 0xb9, 0xf0, 0xe1, 0x6b, 0xc6, 0xf5, 0xba, 0x4e, 0xf6, 0x65, 0xd8, 0x83, 0x43, 0x0b, 0x87,
-	0x16, 0x0e, 0x2d, 0x9c, 0x8c, 0x4c, 0xf9, 0xe3, 0x25, 0x00, 0x00, 0xff, 0xff, 0x04, 0x6f, 0x60,
-	0x95, 0x53, 0x03, 0x00, 0x00,
+func init() { proto.RegisterFile("common/collection.proto", fileDescriptor_collection_c4e57fcc8cd2efd1) }
+
+var fileDescriptor_collection_c4e57fcc8cd2efd1 = []byte{
+	// 468 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x53, 0x4f, 0x6b, 0xdb, 0x30,
+	0x14, 0x6f, 0xda, 0x24, 0xc5, 0x2f, 0x1b, 0xcb, 0x54, 0x96, 0x9a, 0x31, 0xba, 0x60, 0x76, 0x08,
+	0x6c, 0xd8, 0xa3, 0xfb, 0x04, 0x6b, 0x18, 0x74, 0x2c, 0xb0, 0xe0, 0xee, 0xd4, 0x8b, 0x91, 0xe5,
+	0x57, 0x47, 0xd4, 0xb6, 0x5c, 0x49, 0x0e, 0xc9, 0x71, 0x9f, 0x6c, 0x5f, 0x6d, 0x44, 0xb2, 0x63,
+	0x37, 0xe4, 0xd0, 0x9b, 0xdf, 0xef, 0xcf, 0x93, 0xde, 0xfb, 0x59, 0x70, 0xc9, 0x44, 0x9e, 0x8b,
+	0x22, 0x60, 0x22, 0xcb, 0x90, 0x69, 0x2e, 0x0a, 0xbf, 0x94, 0x42, 0x0b, 0x32, 0xb4, 0xc4, 0xfb,
+	0x77, 0xb5, 0xa0, 0x14, 0x19, 0x67, 0x1c, 0x95, 0xa5, 0xbd, 0x5f, 0x70, 0x39, 0xdf, 0x5b, 0xe6,
+	0xa2, 0x78, 0xe0, 0xe9, 0x92, 0xb2, 0x47, 0x9a, 0x22, 0xf9, 0x0a, 0x43, 0x66, 0x00, 0xb7, 0x37,
+	0x3d, 0x9b, 0x8d, 0xae, 0x5d, 0xdf, 0xb6, 0xf0, 0x0f, 0x0d, 0x61, 0xad, 0xf3, 0xb6, 0x30, 0x3e,
+	0xe4, 0xc8, 0x3d, 0xb8, 0x4a, 0x53, 0xcd, 0x59, 0xd4, 0x5e, 0x2d, 0xda, 0xf7, 0xed, 0xcd, 0x46,
+	0xd7, 0x57, 0x4d, 0xdf, 0x3b, 0xa3, 0x3b, 0xec, 0x70, 0x7b, 0x12, 0x4e, 0xd4, 0x51, 0xe6, 0xc6,
+	0x81, 0xf3, 0x92, 0x6e, 0x33, 0x41, 0x13, 0xef, 0xdf, 0x29, 0x4c, 0x8e, 0xfb, 0x09, 0x81, 0x7e,
+	0x41, 0x73, 0x34, 0xa7, 0x39, 0xa1, 0xf9, 0x26, 0x0b, 0x20, 0x39, 0xe6, 0x31, 0xca, 0x48, 0xc8,
+	0x54, 0x45, 0x66, 0x29, 0x5b, 0xf7, 0xf4, 0xf9, 0x7d, 0xda, 0x4e, 0x4b, 0xc3, 0xd7, 0xd3, 0x8e,
+	0xad, 0xf3, 0xb7, 0x4c, 0x95, 0xc5, 0x89, 0x0f, 0x17, 0x12, 0x9f, 0x2a, 0x2e, 0x31, 0x89, 0x4a,
+	0x44, 0x19, 0x31, 0x51, 0x15, 0xda, 0x3d, 0x9b, 0xf6, 0x66, 0x83, 0xf0, 0x6d, 0x43, 0x2d, 0x11,
+	0xe5, 0x7c, 0x47, 0x90, 0x2f, 0x40, 0x72, 0xba, 0xe1, 0x79, 0x95, 0x77, 0xe5, 0x7d, 0x23, 0x1f,
+	0xd7, 0x4c, 0xab, 0xf6, 0xe0, 0x75, 0x9c, 0x09, 0xf6, 0x18, 0x69, 0x11, 0x65, 0x7c, 0x8d, 0xee,
+	0x60, 0xda, 0x9b, 0xf5, 0xc3, 0x91, 0x01, 0xff, 0x88, 0x05, 0x5f, 0x23, 0xf9, 0x0e, 0xaf, 0xca,
+	0x4a, 0xa6, 0xd8, 0x4c, 0x32, 0x7c, 0xd1, 0x24, 0x23, 0xe3, 0xb1, 0x90, 0xf7, 0x04, 0x93, 0xe3,
+	0x32, 0xb2, 0x80, 0xb1, 0xe2, 0x69, 0x41, 0x75, 0x25, 0xf7, 0x07, 0xd8, 0xe8, 0x3e, 0xee, 0xa3,
+	0x6b, 0x78, 0x6b, 0xfc, 0x51, 0xac, 0x31, 0x13, 0x25, 0xde, 0x9e, 0x84, 0x6f, 0xd4, 0x73, 0xaa,
+	0x1b, 0xda, 0xdf, 0x1e, 0x90, 0x4e, 0x5c, 0x92, 0x6b, 0x94, 0x9c, 0x12, 0x17, 0xce, 0xd9, 0x8a,
+	0x16, 0x05, 0x66, 0x75, 0x66, 0x4d, 0x49, 0x2e, 0x60, 0xa0, 0x37, 0x11, 0x4f, 0x4c, 0x52, 0x4e,
+	0xd8, 0xd7, 0x9b, 0x9f, 0x09, 0xb9, 0x02, 0x68, 0x7f, 0x2d, 0xb3, 0x74, 0x27, 0xec, 0x20, 0xe4,
+	0x03, 0x38, 0xbb, 0xcc, 0x55, 0x49, 0x19, 0x9a, 0x25, 0x3b, 0x61, 0x0b, 0xdc, 0xdc, 0xc1, 0x27,
+	0x21, 0x53, 0x7f, 0xb5, 0x2d, 0x51, 0x66, 0x98, 0xa4, 0x28, 0xfd, 0x07, 0x1a, 0x4b, 0xce, 0xec,
+	0x03, 0x51, 0xf5, 0x84, 0xf7, 0x9f, 0x53, 0xae, 0x57, 0x55, 0xbc, 0x2b, 0x83, 0x8e, 0x38, 0xb0,
+	0xe2, 0xc0, 0x8a, 0x03, 0x2b, 0x8e, 0x87, 0xa6, 0xfc, 0xf6, 0x7f, 0x00, 0x13, 0x60, 0x85, 0x90,
+	0x96, 0x03, 0x00, 0x00,
 }
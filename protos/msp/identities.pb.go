@@ -35,7 +35,7 @@ func (m *SerializedIdentity) Reset()         { *m = SerializedIdentity{} }
 func (m *SerializedIdentity) String() string { return proto.CompactTextString(m) }
 func (*SerializedIdentity) ProtoMessage()    {}
 func (*SerializedIdentity) Descriptor() ([]byte, []int) {
-	return fileDescriptor_identities_8fa8af3e5bf2070a, []int{0}
+	return fileDescriptor_identities_e6d309ac3a52fbdf, []int{0}
 }
 func (m *SerializedIdentity) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SerializedIdentity.Unmarshal(m, b)
@@ -87,7 +87,14 @@ type SerializedIdemixIdentity struct {
 	// role contains the role of this identity (e.g., ADMIN or MEMBER)
 	Role []byte `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
 	// proof contains the cryptographic evidence that this identity is valid
-	Proof                []byte   `protobuf:"bytes,5,opt,name=proof,proto3" json:"proof,omitempty"`
+	Proof []byte `protobuf:"bytes,5,opt,name=proof,proto3" json:"proof,omitempty"`
+	// enrollment_id contains the enrollment id of the identity if it discloses it,
+	// in which case the proof covers it
+	EnrollmentId string `protobuf:"bytes,6,opt,name=enrollment_id,json=enrollmentId" json:"enrollment_id,omitempty"`
+	// revocation_handle contains the revocation handle of the identity if it discloses it,
+	// in which case the proof covers it. It is a []byte representation of an amcl.BIG
+	// and can only be disclosed by identities that are not subject to revocation
+	RevocationHandle     []byte   `protobuf:"bytes,7,opt,name=revocation_handle,json=revocationHandle,proto3" json:"revocation_handle,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -97,7 +104,7 @@ func (m *SerializedIdemixIdentity) Reset()         { *m = SerializedIdemixIdenti
 func (m *SerializedIdemixIdentity) String() string { return proto.CompactTextString(m) }
 func (*SerializedIdemixIdentity) ProtoMessage()    {}
 func (*SerializedIdemixIdentity) Descriptor() ([]byte, []int) {
-	return fileDescriptor_identities_8fa8af3e5bf2070a, []int{1}
+	return fileDescriptor_identities_e6d309ac3a52fbdf, []int{1}
 }
 func (m *SerializedIdemixIdentity) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SerializedIdemixIdentity.Unmarshal(m, b)
@@ -152,28 +159,45 @@ func (m *SerializedIdemixIdentity) GetProof() []byte {
 	return nil
 }
 
+func (m *SerializedIdemixIdentity) GetEnrollmentId() string {
+	if m != nil {
+		return m.EnrollmentId
+	}
+	return ""
+}
+
+func (m *SerializedIdemixIdentity) GetRevocationHandle() []byte {
+	if m != nil {
+		return m.RevocationHandle
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*SerializedIdentity)(nil), "msp.SerializedIdentity")
 	proto.RegisterType((*SerializedIdemixIdentity)(nil), "msp.SerializedIdemixIdentity")
 }
 
-func init() { proto.RegisterFile("msp/identities.proto", fileDescriptor_identities_8fa8af3e5bf2070a) }
-
-var fileDescriptor_identities_8fa8af3e5bf2070a = []byte{
-	// 238 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x8f, 0x3f, 0x4f, 0xc3, 0x30,
-	0x10, 0x47, 0x95, 0x34, 0xe1, 0x8f, 0x55, 0x31, 0x98, 0x0e, 0x66, 0x2b, 0x9d, 0x32, 0xc5, 0x03,
-	0xdf, 0xa0, 0x12, 0x03, 0x03, 0x4b, 0x58, 0x80, 0xa5, 0x6a, 0xea, 0x6b, 0x7a, 0x52, 0x2e, 0x67,
-	0xd9, 0x8e, 0x54, 0x33, 0xf0, 0xd9, 0x51, 0x62, 0x40, 0xb0, 0xdd, 0xef, 0xe9, 0xe9, 0xc9, 0x16,
-	0x2b, 0xf2, 0x56, 0xa3, 0x81, 0x21, 0x60, 0x40, 0xf0, 0xb5, 0x75, 0x1c, 0x58, 0x2e, 0xc8, 0xdb,
-	0xcd, 0xa3, 0x90, 0x2f, 0xe0, 0x70, 0xdf, 0xe3, 0x07, 0x98, 0xa7, 0xa4, 0x44, 0xb9, 0x12, 0x25,
-	0x79, 0x8b, 0x46, 0x65, 0xeb, 0xac, 0xba, 0x6e, 0xd2, 0x90, 0x77, 0xe2, 0x0a, 0xcd, 0xae, 0x8d,
-	0x01, 0xbc, 0xca, 0xd7, 0x59, 0xb5, 0x6c, 0x2e, 0xd1, 0x6c, 0xa7, 0xb9, 0xf9, 0x14, 0xea, 0x5f,
-	0x86, 0xf0, 0xfc, 0x1b, 0xbb, 0x15, 0xe5, 0x10, 0x69, 0x77, 0x9e, 0x63, 0xcb, 0xa6, 0x18, 0x22,
-	0xbd, 0xfe, 0xc0, 0xf8, 0x1d, 0x9a, 0xe0, 0x9b, 0xbc, 0x11, 0x39, 0x8f, 0x6a, 0x31, 0x93, 0x9c,
-	0x47, 0x29, 0x45, 0xe1, 0xb8, 0x07, 0x55, 0x24, 0x67, 0xba, 0xa7, 0xa7, 0x59, 0xc7, 0x7c, 0x54,
-	0xe5, 0x0c, 0xd3, 0xd8, 0x3e, 0x8b, 0x7b, 0x76, 0x5d, 0x7d, 0x8a, 0x16, 0x5c, 0x0f, 0xa6, 0x03,
-	0x57, 0x1f, 0xf7, 0xad, 0xc3, 0x43, 0xfa, 0xab, 0xaf, 0xc9, 0xdb, 0xf7, 0xaa, 0xc3, 0x70, 0x1a,
-	0xdb, 0xfa, 0xc0, 0xa4, 0xff, 0x98, 0x3a, 0x99, 0x3a, 0x99, 0x9a, 0xbc, 0x6d, 0x2f, 0xe6, 0xfb,
-	0xe1, 0x2b, 0x00, 0x00, 0xff, 0xff, 0x13, 0xdc, 0xc8, 0x62, 0x39, 0x01, 0x00, 0x00,
+func init() { proto.RegisterFile("msp/identities.proto", fileDescriptor_identities_e6d309ac3a52fbdf) }
+
+var fileDescriptor_identities_e6d309ac3a52fbdf = []byte{
+	// 282 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x90, 0xcd, 0x4a, 0x03, 0x31,
+	0x14, 0x85, 0x99, 0xe9, 0x9f, 0x86, 0x2a, 0x1a, 0xbb, 0x88, 0xbb, 0x5a, 0x37, 0x05, 0x61, 0x66,
+	0xe1, 0x1b, 0x14, 0x04, 0xbb, 0x70, 0x53, 0x37, 0xea, 0x66, 0x98, 0x69, 0x6e, 0xdb, 0x0b, 0x93,
+	0xdc, 0x90, 0xa4, 0xd2, 0xf8, 0x94, 0x3e, 0x92, 0x4c, 0x82, 0xad, 0xee, 0xee, 0xf9, 0xf2, 0x71,
+	0x38, 0x84, 0x4d, 0x94, 0x33, 0x25, 0x4a, 0xd0, 0x1e, 0x3d, 0x82, 0x2b, 0x8c, 0x25, 0x4f, 0xbc,
+	0xa7, 0x9c, 0x99, 0x3d, 0x31, 0xfe, 0x0a, 0x16, 0xeb, 0x16, 0xbf, 0x40, 0x2e, 0x93, 0x12, 0xf8,
+	0x84, 0x0d, 0x94, 0x33, 0x28, 0x45, 0x36, 0xcd, 0xe6, 0xe7, 0xab, 0x14, 0xf8, 0x2d, 0x3b, 0x43,
+	0x59, 0x35, 0xc1, 0x83, 0x13, 0xf9, 0x34, 0x9b, 0x8f, 0x57, 0x23, 0x94, 0x8b, 0x2e, 0xce, 0xbe,
+	0x33, 0x26, 0xfe, 0xf5, 0x28, 0x3c, 0x1c, 0xdb, 0x6e, 0xd8, 0x40, 0x07, 0x55, 0x1d, 0x62, 0xdb,
+	0x78, 0xd5, 0xd7, 0x41, 0xbd, 0xfd, 0xc2, 0x20, 0xf2, 0x23, 0x7c, 0xe7, 0x97, 0x2c, 0xa7, 0xbd,
+	0xe8, 0x45, 0x92, 0xd3, 0x9e, 0x73, 0xd6, 0xb7, 0xd4, 0x82, 0xe8, 0x27, 0xa7, 0xbb, 0xbb, 0x6d,
+	0xc6, 0x12, 0x6d, 0xc4, 0x20, 0xc2, 0x14, 0xf8, 0x3d, 0xbb, 0x00, 0x6d, 0xa9, 0x6d, 0x15, 0x68,
+	0x5f, 0xa1, 0x14, 0xc3, 0xb8, 0x7c, 0x7c, 0x82, 0x4b, 0xc9, 0x1f, 0xd8, 0xb5, 0x85, 0x4f, 0x5a,
+	0xd7, 0x1e, 0x49, 0x57, 0xbb, 0x5a, 0xcb, 0x16, 0xc4, 0x28, 0xd6, 0x5c, 0x9d, 0x1e, 0x9e, 0x23,
+	0x5f, 0xbc, 0xb0, 0x3b, 0xb2, 0xdb, 0x62, 0x17, 0x0c, 0xd8, 0x16, 0xe4, 0x16, 0x6c, 0xb1, 0xa9,
+	0x1b, 0x8b, 0xeb, 0xf4, 0x7d, 0xae, 0x50, 0xce, 0x7c, 0xcc, 0xb7, 0xe8, 0x77, 0xfb, 0xa6, 0x58,
+	0x93, 0x2a, 0xff, 0x98, 0x65, 0x32, 0xcb, 0x64, 0x96, 0xca, 0x99, 0x66, 0x18, 0xef, 0xc7, 0x9f,
+	0x01, 0x00, 0xe0, 0x14, 0x3a, 0x8b, 0x8c, 0x01, 0x00, 0x00,
 }
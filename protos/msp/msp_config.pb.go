@@ -36,7 +36,7 @@ func (m *MSPConfig) Reset()         { *m = MSPConfig{} }
 func (m *MSPConfig) String() string { return proto.CompactTextString(m) }
 func (*MSPConfig) ProtoMessage()    {}
 func (*MSPConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_msp_config_e749e5bd1d6d997b, []int{0}
+	return fileDescriptor_msp_config_2afc0dbfc1f4be75, []int{0}
 }
 func (m *MSPConfig) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MSPConfig.Unmarshal(m, b)
@@ -121,17 +121,22 @@ type FabricMSPConfig struct {
 	TlsIntermediateCerts [][]byte `protobuf:"bytes,10,rep,name=tls_intermediate_certs,json=tlsIntermediateCerts,proto3" json:"tls_intermediate_certs,omitempty"`
 	// fabric_node_ous contains the configuration to distinguish clients from peers from orderers
 	// based on the OUs.
-	FabricNodeOus        *FabricNodeOUs `protobuf:"bytes,11,opt,name=fabric_node_ous,json=fabricNodeOus" json:"fabric_node_ous,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
-	XXX_unrecognized     []byte         `json:"-"`
-	XXX_sizecache        int32          `json:"-"`
+	FabricNodeOus *FabricNodeOUs `protobuf:"bytes,11,opt,name=fabric_node_ous,json=fabricNodeOus" json:"fabric_node_ous,omitempty"`
+	// online_revocation configures the checking of the revocation status of
+	// certificates against the OCSP responders and CRL distribution points
+	// they advertise, in addition to revocation_list, when proposals are
+	// endorsed
+	OnlineRevocation     *FabricOnlineRevocation `protobuf:"bytes,12,opt,name=online_revocation,json=onlineRevocation" json:"online_revocation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
 }
 
 func (m *FabricMSPConfig) Reset()         { *m = FabricMSPConfig{} }
 func (m *FabricMSPConfig) String() string { return proto.CompactTextString(m) }
 func (*FabricMSPConfig) ProtoMessage()    {}
 func (*FabricMSPConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_msp_config_e749e5bd1d6d997b, []int{1}
+	return fileDescriptor_msp_config_2afc0dbfc1f4be75, []int{1}
 }
 func (m *FabricMSPConfig) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FabricMSPConfig.Unmarshal(m, b)
@@ -228,6 +233,13 @@ func (m *FabricMSPConfig) GetFabricNodeOus() *FabricNodeOUs {
 	return nil
 }
 
+func (m *FabricMSPConfig) GetOnlineRevocation() *FabricOnlineRevocation {
+	if m != nil {
+		return m.OnlineRevocation
+	}
+	return nil
+}
+
 // FabricCryptoConfig contains configuration parameters
 // for the cryptographic algorithms used by the MSP
 // this configuration refers to
@@ -249,7 +261,7 @@ func (m *FabricCryptoConfig) Reset()         { *m = FabricCryptoConfig{} }
 func (m *FabricCryptoConfig) String() string { return proto.CompactTextString(m) }
 func (*FabricCryptoConfig) ProtoMessage()    {}
 func (*FabricCryptoConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_msp_config_e749e5bd1d6d997b, []int{2}
+	return fileDescriptor_msp_config_2afc0dbfc1f4be75, []int{2}
 }
 func (m *FabricCryptoConfig) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FabricCryptoConfig.Unmarshal(m, b)
@@ -305,7 +317,7 @@ func (m *IdemixMSPConfig) Reset()         { *m = IdemixMSPConfig{} }
 func (m *IdemixMSPConfig) String() string { return proto.CompactTextString(m) }
 func (*IdemixMSPConfig) ProtoMessage()    {}
 func (*IdemixMSPConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_msp_config_e749e5bd1d6d997b, []int{3}
+	return fileDescriptor_msp_config_2afc0dbfc1f4be75, []int{3}
 }
 func (m *IdemixMSPConfig) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_IdemixMSPConfig.Unmarshal(m, b)
@@ -373,17 +385,22 @@ type IdemixMSPSignerConfig struct {
 	// enrollment_id contains the enrollment id of this signer
 	EnrollmentId string `protobuf:"bytes,5,opt,name=enrollment_id,json=enrollmentId" json:"enrollment_id,omitempty"`
 	// credential_revocation_information contains a serialized CredentialRevocationInformation
-	CredentialRevocationInformation []byte   `protobuf:"bytes,6,opt,name=credential_revocation_information,json=credentialRevocationInformation,proto3" json:"credential_revocation_information,omitempty"`
-	XXX_NoUnkeyedLiteral            struct{} `json:"-"`
-	XXX_unrecognized                []byte   `json:"-"`
-	XXX_sizecache                   int32    `json:"-"`
+	CredentialRevocationInformation []byte `protobuf:"bytes,6,opt,name=credential_revocation_information,json=credentialRevocationInformation,proto3" json:"credential_revocation_information,omitempty"`
+	// disclose_enrollment_id makes the identity of the signer disclose its enrollment id
+	DiscloseEnrollmentId bool `protobuf:"varint,7,opt,name=disclose_enrollment_id,json=discloseEnrollmentId" json:"disclose_enrollment_id,omitempty"`
+	// disclose_revocation_handle makes the identity of the signer disclose its revocation handle,
+	// which is only possible if its credential revocation information uses no revocation algorithm
+	DiscloseRevocationHandle bool     `protobuf:"varint,8,opt,name=disclose_revocation_handle,json=discloseRevocationHandle" json:"disclose_revocation_handle,omitempty"`
+	XXX_NoUnkeyedLiteral     struct{} `json:"-"`
+	XXX_unrecognized         []byte   `json:"-"`
+	XXX_sizecache            int32    `json:"-"`
 }
 
 func (m *IdemixMSPSignerConfig) Reset()         { *m = IdemixMSPSignerConfig{} }
 func (m *IdemixMSPSignerConfig) String() string { return proto.CompactTextString(m) }
 func (*IdemixMSPSignerConfig) ProtoMessage()    {}
 func (*IdemixMSPSignerConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_msp_config_e749e5bd1d6d997b, []int{4}
+	return fileDescriptor_msp_config_2afc0dbfc1f4be75, []int{4}
 }
 func (m *IdemixMSPSignerConfig) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_IdemixMSPSignerConfig.Unmarshal(m, b)
@@ -445,6 +462,20 @@ func (m *IdemixMSPSignerConfig) GetCredentialRevocationInformation() []byte {
 	return nil
 }
 
+func (m *IdemixMSPSignerConfig) GetDiscloseEnrollmentId() bool {
+	if m != nil {
+		return m.DiscloseEnrollmentId
+	}
+	return false
+}
+
+func (m *IdemixMSPSignerConfig) GetDiscloseRevocationHandle() bool {
+	if m != nil {
+		return m.DiscloseRevocationHandle
+	}
+	return false
+}
+
 // SigningIdentityInfo represents the configuration information
 // related to the signing identity the peer is to use for generating
 // endorsements
@@ -465,7 +496,7 @@ func (m *SigningIdentityInfo) Reset()         { *m = SigningIdentityInfo{} }
 func (m *SigningIdentityInfo) String() string { return proto.CompactTextString(m) }
 func (*SigningIdentityInfo) ProtoMessage()    {}
 func (*SigningIdentityInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_msp_config_e749e5bd1d6d997b, []int{5}
+	return fileDescriptor_msp_config_2afc0dbfc1f4be75, []int{5}
 }
 func (m *SigningIdentityInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SigningIdentityInfo.Unmarshal(m, b)
@@ -520,7 +551,7 @@ func (m *KeyInfo) Reset()         { *m = KeyInfo{} }
 func (m *KeyInfo) String() string { return proto.CompactTextString(m) }
 func (*KeyInfo) ProtoMessage()    {}
 func (*KeyInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_msp_config_e749e5bd1d6d997b, []int{6}
+	return fileDescriptor_msp_config_2afc0dbfc1f4be75, []int{6}
 }
 func (m *KeyInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_KeyInfo.Unmarshal(m, b)
@@ -577,7 +608,7 @@ func (m *FabricOUIdentifier) Reset()         { *m = FabricOUIdentifier{} }
 func (m *FabricOUIdentifier) String() string { return proto.CompactTextString(m) }
 func (*FabricOUIdentifier) ProtoMessage()    {}
 func (*FabricOUIdentifier) Descriptor() ([]byte, []int) {
-	return fileDescriptor_msp_config_e749e5bd1d6d997b, []int{7}
+	return fileDescriptor_msp_config_2afc0dbfc1f4be75, []int{7}
 }
 func (m *FabricOUIdentifier) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FabricOUIdentifier.Unmarshal(m, b)
@@ -630,7 +661,7 @@ func (m *FabricNodeOUs) Reset()         { *m = FabricNodeOUs{} }
 func (m *FabricNodeOUs) String() string { return proto.CompactTextString(m) }
 func (*FabricNodeOUs) ProtoMessage()    {}
 func (*FabricNodeOUs) Descriptor() ([]byte, []int) {
-	return fileDescriptor_msp_config_e749e5bd1d6d997b, []int{8}
+	return fileDescriptor_msp_config_2afc0dbfc1f4be75, []int{8}
 }
 func (m *FabricNodeOUs) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FabricNodeOUs.Unmarshal(m, b)
@@ -671,6 +702,87 @@ func (m *FabricNodeOUs) GetPeerOuIdentifier() *FabricOUIdentifier {
 	return nil
 }
 
+// FabricOnlineRevocation contains configuration to check the revocation status
+// of certificates online. Responses are cached, and whether identities are
+// accepted when their status cannot be determined is configurable.
+type FabricOnlineRevocation struct {
+	// If true, the OCSP responders listed in the Authority Information Access
+	// extension of a certificate are queried
+	Ocsp bool `protobuf:"varint,1,opt,name=ocsp" json:"ocsp,omitempty"`
+	// If true, the CRLs published at the CRL distribution points of a
+	// certificate are fetched
+	CrlDistributionPoints bool `protobuf:"varint,2,opt,name=crl_distribution_points,json=crlDistributionPoints" json:"crl_distribution_points,omitempty"`
+	// If true, identities whose revocation status cannot be determined are
+	// rejected (fail-closed), otherwise they are accepted (fail-open)
+	FailClosed bool `protobuf:"varint,3,opt,name=fail_closed,json=failClosed" json:"fail_closed,omitempty"`
+	// cache_duration bounds how long a revocation status is cached, e.g. "10m"
+	CacheDuration string `protobuf:"bytes,4,opt,name=cache_duration,json=cacheDuration" json:"cache_duration,omitempty"`
+	// timeout bounds each request to a responder or distribution point, e.g. "5s"
+	Timeout              string   `protobuf:"bytes,5,opt,name=timeout" json:"timeout,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FabricOnlineRevocation) Reset()         { *m = FabricOnlineRevocation{} }
+func (m *FabricOnlineRevocation) String() string { return proto.CompactTextString(m) }
+func (*FabricOnlineRevocation) ProtoMessage()    {}
+func (*FabricOnlineRevocation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_msp_config_2afc0dbfc1f4be75, []int{9}
+}
+func (m *FabricOnlineRevocation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FabricOnlineRevocation.Unmarshal(m, b)
+}
+func (m *FabricOnlineRevocation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FabricOnlineRevocation.Marshal(b, m, deterministic)
+}
+func (dst *FabricOnlineRevocation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FabricOnlineRevocation.Merge(dst, src)
+}
+func (m *FabricOnlineRevocation) XXX_Size() int {
+	return xxx_messageInfo_FabricOnlineRevocation.Size(m)
+}
+func (m *FabricOnlineRevocation) XXX_DiscardUnknown() {
+	xxx_messageInfo_FabricOnlineRevocation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FabricOnlineRevocation proto.InternalMessageInfo
+
+func (m *FabricOnlineRevocation) GetOcsp() bool {
+	if m != nil {
+		return m.Ocsp
+	}
+	return false
+}
+
+func (m *FabricOnlineRevocation) GetCrlDistributionPoints() bool {
+	if m != nil {
+		return m.CrlDistributionPoints
+	}
+	return false
+}
+
+func (m *FabricOnlineRevocation) GetFailClosed() bool {
+	if m != nil {
+		return m.FailClosed
+	}
+	return false
+}
+
+func (m *FabricOnlineRevocation) GetCacheDuration() string {
+	if m != nil {
+		return m.CacheDuration
+	}
+	return ""
+}
+
+func (m *FabricOnlineRevocation) GetTimeout() string {
+	if m != nil {
+		return m.Timeout
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*MSPConfig)(nil), "msp.MSPConfig")
 	proto.RegisterType((*FabricMSPConfig)(nil), "msp.FabricMSPConfig")
@@ -681,63 +793,74 @@ func init() {
 	proto.RegisterType((*KeyInfo)(nil), "msp.KeyInfo")
 	proto.RegisterType((*FabricOUIdentifier)(nil), "msp.FabricOUIdentifier")
 	proto.RegisterType((*FabricNodeOUs)(nil), "msp.FabricNodeOUs")
-}
-
-func init() { proto.RegisterFile("msp/msp_config.proto", fileDescriptor_msp_config_e749e5bd1d6d997b) }
-
-var fileDescriptor_msp_config_e749e5bd1d6d997b = []byte{
-	// 847 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x55, 0x5f, 0x6f, 0xe3, 0x44,
-	0x10, 0x57, 0x92, 0x26, 0x77, 0x99, 0x38, 0x49, 0xd9, 0xeb, 0x15, 0x0b, 0x71, 0x77, 0xa9, 0x01,
-	0x91, 0x17, 0x52, 0xa9, 0x87, 0x84, 0x84, 0x78, 0xba, 0xc2, 0x09, 0x03, 0xa5, 0xd5, 0x56, 0x7d,
-	0xe1, 0xc5, 0xda, 0xd8, 0x9b, 0x64, 0x65, 0x7b, 0xd7, 0xda, 0x5d, 0x9f, 0x08, 0xe2, 0x99, 0x2f,
-	0xc0, 0x77, 0xe0, 0x99, 0x57, 0xbe, 0x1d, 0xda, 0x3f, 0x8d, 0x9d, 0x6b, 0x15, 0x78, 0x9b, 0x9d,
-	0xf9, 0xcd, 0xcf, 0xb3, 0xbf, 0x99, 0x59, 0xc3, 0x49, 0xa9, 0xaa, 0xf3, 0x52, 0x55, 0x49, 0x2a,
-	0xf8, 0x8a, 0xad, 0x17, 0x95, 0x14, 0x5a, 0xa0, 0x5e, 0xa9, 0xaa, 0xe8, 0x2b, 0x18, 0x5e, 0xdd,
-	0xde, 0x5c, 0x5a, 0x3f, 0x42, 0x70, 0xa4, 0xb7, 0x15, 0x0d, 0x3b, 0xb3, 0xce, 0xbc, 0x8f, 0xad,
-	0x8d, 0x4e, 0x61, 0xe0, 0xb2, 0xc2, 0xee, 0xac, 0x33, 0x0f, 0xb0, 0x3f, 0x45, 0x7f, 0x1f, 0xc1,
-	0xf4, 0x2d, 0x59, 0x4a, 0x96, 0xee, 0xe5, 0x73, 0x52, 0xba, 0xfc, 0x21, 0xb6, 0x36, 0x7a, 0x01,
-	0x20, 0x85, 0xd0, 0x49, 0x4a, 0xa5, 0x56, 0x61, 0x77, 0xd6, 0x9b, 0x07, 0x78, 0x68, 0x3c, 0x97,
-	0xc6, 0x81, 0xbe, 0x00, 0xc4, 0xb8, 0xa6, 0xb2, 0xa4, 0x19, 0x23, 0x9a, 0x7a, 0x58, 0xcf, 0xc2,
-	0x3e, 0x68, 0x47, 0x1c, 0xfc, 0x14, 0x06, 0x24, 0x2b, 0x19, 0x57, 0xe1, 0x91, 0x85, 0xf8, 0x13,
-	0xfa, 0x1c, 0xa6, 0x92, 0xbe, 0x13, 0x29, 0xd1, 0x4c, 0xf0, 0xa4, 0x60, 0x4a, 0x87, 0x7d, 0x0b,
-	0x98, 0x34, 0xee, 0x9f, 0x98, 0xd2, 0xe8, 0x12, 0x8e, 0x15, 0x5b, 0x73, 0xc6, 0xd7, 0x09, 0xcb,
-	0x28, 0xd7, 0x4c, 0x6f, 0xc3, 0xc1, 0xac, 0x33, 0x1f, 0x5d, 0x84, 0x8b, 0x52, 0x55, 0x8b, 0x5b,
-	0x17, 0x8c, 0x7d, 0x2c, 0xe6, 0x2b, 0x81, 0xa7, 0x6a, 0xdf, 0x89, 0x12, 0x78, 0x25, 0xe4, 0x9a,
-	0x70, 0xf6, 0x9b, 0x25, 0x26, 0x45, 0x52, 0x73, 0xa6, 0x3d, 0xe1, 0x8a, 0x51, 0xa9, 0xc2, 0x27,
-	0xb3, 0xde, 0x7c, 0x74, 0xf1, 0xa1, 0xe5, 0x74, 0x32, 0x5d, 0xdf, 0xc5, 0xbb, 0x38, 0x7e, 0xb1,
-	0x9f, 0x7f, 0xc7, 0x99, 0x6e, 0xa2, 0x0a, 0x7d, 0x03, 0xe3, 0x54, 0x6e, 0x2b, 0x2d, 0x7c, 0xc7,
-	0xc2, 0xa7, 0xb6, 0xc4, 0x36, 0xdd, 0xa5, 0x8d, 0x3b, 0xe1, 0x71, 0x90, 0xb6, 0x4e, 0xe8, 0x53,
-	0x98, 0xe8, 0x42, 0x25, 0x2d, 0xd9, 0x87, 0x56, 0x8b, 0x40, 0x17, 0x0a, 0xef, 0x94, 0xff, 0x12,
-	0x4e, 0x0d, 0xea, 0x11, 0xf5, 0xc1, 0xa2, 0x4f, 0x74, 0xa1, 0xe2, 0x07, 0x0d, 0xf8, 0x1a, 0xa6,
-	0x2b, 0xfb, 0xfd, 0x84, 0x8b, 0x8c, 0x26, 0xa2, 0x56, 0xe1, 0xc8, 0xd6, 0x86, 0x5a, 0xb5, 0xfd,
-	0x2c, 0x32, 0x7a, 0x7d, 0xa7, 0xf0, 0x78, 0xd5, 0x1c, 0x6b, 0x15, 0xfd, 0xd9, 0x01, 0xf4, 0xb0,
-	0x78, 0x74, 0x01, 0xcf, 0x8d, 0xc0, 0x44, 0xd7, 0x92, 0x26, 0x1b, 0xa2, 0x36, 0xc9, 0x8a, 0x94,
-	0xac, 0xd8, 0xfa, 0x31, 0x7a, 0xb6, 0x0b, 0x7e, 0x4f, 0xd4, 0xe6, 0xad, 0x0d, 0xa1, 0x18, 0xce,
-	0xee, 0xdb, 0xd7, 0x92, 0xdd, 0x67, 0xd7, 0x3c, 0x35, 0xb2, 0xda, 0x81, 0x1d, 0xe2, 0x97, 0xf7,
-	0xc0, 0x46, 0x60, 0x4b, 0xe4, 0x51, 0xd1, 0x5f, 0x1d, 0x98, 0xc6, 0x19, 0x2d, 0xd9, 0xaf, 0x87,
-	0x07, 0xf9, 0x18, 0x7a, 0xac, 0xca, 0xfd, 0x16, 0x18, 0x13, 0x5d, 0xc0, 0xc0, 0xd4, 0x46, 0x65,
-	0xd8, 0xb3, 0x12, 0x7c, 0x64, 0x25, 0xd8, 0x71, 0xdd, 0xda, 0x98, 0xef, 0x90, 0x47, 0xa2, 0x4f,
-	0x60, 0xdc, 0x1a, 0xd4, 0x2a, 0x0f, 0x8f, 0x2c, 0x5f, 0xd0, 0x38, 0x6f, 0x72, 0x74, 0x02, 0x7d,
-	0x5a, 0x89, 0x74, 0x13, 0xf6, 0x67, 0x9d, 0x79, 0x0f, 0xbb, 0x43, 0xf4, 0x47, 0x17, 0x9e, 0x3f,
-	0x4a, 0x6e, 0xca, 0x4d, 0x25, 0xcd, 0x6c, 0xb9, 0x01, 0xb6, 0x36, 0x9a, 0x40, 0x57, 0xdd, 0x57,
-	0xdb, 0x55, 0x39, 0xfa, 0x16, 0x5e, 0x1e, 0x9e, 0x59, 0x7b, 0x89, 0x21, 0xfe, 0xf8, 0xd0, 0x64,
-	0x9a, 0x2f, 0x49, 0x51, 0x50, 0x5b, 0x75, 0x1f, 0x5b, 0xdb, 0x5c, 0x89, 0x72, 0x29, 0x8a, 0xa2,
-	0xa4, 0xdc, 0x10, 0xda, 0xaa, 0x87, 0x38, 0x68, 0x9c, 0x71, 0x86, 0x7e, 0x80, 0x33, 0x53, 0x96,
-	0x21, 0x22, 0x45, 0xd2, 0x92, 0x80, 0xf1, 0x95, 0x90, 0xa5, 0xb5, 0xed, 0x22, 0x06, 0xf8, 0x55,
-	0x03, 0xc4, 0x3b, 0x5c, 0xdc, 0xc0, 0x22, 0x01, 0xcf, 0x1e, 0x59, 0x53, 0x53, 0x47, 0x55, 0x2f,
-	0x0b, 0x96, 0x26, 0xbe, 0x2b, 0x4e, 0x8e, 0xc0, 0x39, 0x9d, 0x60, 0xe8, 0x35, 0x4c, 0x2a, 0xc9,
-	0xde, 0x99, 0x61, 0xf7, 0xa8, 0xae, 0xed, 0x5d, 0x60, 0x7b, 0xf7, 0x23, 0x75, 0x1b, 0x3f, 0xf6,
-	0x18, 0x97, 0x14, 0xdd, 0xc2, 0x13, 0x1f, 0x41, 0x9f, 0xc1, 0x24, 0xa7, 0xed, 0x99, 0xf3, 0x33,
-	0x32, 0xce, 0x69, 0x6b, 0xc0, 0xd0, 0x19, 0x04, 0x06, 0x56, 0x12, 0x4d, 0x25, 0x23, 0x85, 0xef,
-	0xc3, 0x28, 0xa7, 0xdb, 0x2b, 0xef, 0x8a, 0x7e, 0xbf, 0x5f, 0x86, 0xf6, 0xc3, 0x80, 0x66, 0x30,
-	0x32, 0x4b, 0xc8, 0x56, 0x2c, 0x25, 0x9a, 0xfa, 0x2b, 0xb4, 0x5d, 0xff, 0xa3, 0x91, 0xdd, 0xff,
-	0x6e, 0x64, 0xf4, 0x4f, 0x07, 0xc6, 0x7b, 0xcb, 0x6a, 0x9e, 0x56, 0xca, 0xc9, 0xb2, 0x70, 0x1f,
-	0x7d, 0x8a, 0xfd, 0x09, 0xc5, 0x70, 0x92, 0x16, 0xcc, 0xb4, 0x56, 0xd4, 0xef, 0x7f, 0xe5, 0xc0,
-	0x0b, 0x87, 0x5c, 0xd2, 0x75, 0xdd, 0xba, 0xdc, 0x77, 0x80, 0x2a, 0x4a, 0xe5, 0x7b, 0x44, 0xbd,
-	0xc3, 0x44, 0xc7, 0x26, 0xa5, 0x4d, 0xf3, 0x26, 0x81, 0x33, 0x21, 0xd7, 0x8b, 0xcd, 0xb6, 0xa2,
-	0xb2, 0xa0, 0xd9, 0x9a, 0xca, 0x85, 0x7b, 0x68, 0xdc, 0x8f, 0x4d, 0x19, 0xa6, 0x37, 0xc7, 0x57,
-	0xaa, 0x72, 0xeb, 0x71, 0x43, 0xd2, 0x9c, 0xac, 0xe9, 0x2f, 0xf3, 0x35, 0xd3, 0x9b, 0x7a, 0xb9,
-	0x48, 0x45, 0x79, 0xde, 0xca, 0x3d, 0x77, 0xb9, 0xe7, 0x2e, 0xd7, 0xfc, 0x26, 0x97, 0x03, 0x6b,
-	0xbf, 0xfe, 0x37, 0x00, 0x00, 0xff, 0xff, 0x54, 0x67, 0x46, 0xdb, 0x38, 0x07, 0x00, 0x00,
+	proto.RegisterType((*FabricOnlineRevocation)(nil), "msp.FabricOnlineRevocation")
+}
+
+func init() { proto.RegisterFile("msp/msp_config.proto", fileDescriptor_msp_config_2afc0dbfc1f4be75) }
+
+var fileDescriptor_msp_config_2afc0dbfc1f4be75 = []byte{
+	// 1006 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x56, 0x5f, 0x6f, 0xdb, 0x36,
+	0x10, 0x87, 0xec, 0xfc, 0xf3, 0x59, 0x4e, 0x52, 0x36, 0x4d, 0x85, 0x6e, 0x6d, 0x1d, 0x6f, 0xc3,
+	0xfc, 0x32, 0x07, 0x48, 0x87, 0x0d, 0x18, 0xfa, 0xd4, 0xa4, 0x45, 0xbc, 0x2d, 0x4b, 0xa0, 0x20,
+	0x2f, 0x7b, 0x11, 0x64, 0x89, 0xb6, 0x09, 0x53, 0xa4, 0x40, 0x52, 0xc5, 0x3c, 0xec, 0x5b, 0xec,
+	0x3b, 0xec, 0x3b, 0xec, 0x0b, 0xec, 0x43, 0xf5, 0x69, 0xe0, 0x91, 0xb6, 0x94, 0x3f, 0xc8, 0xf6,
+	0x76, 0xbc, 0xfb, 0xdd, 0xf1, 0x78, 0xf7, 0xbb, 0x93, 0xe0, 0xa0, 0xd0, 0xe5, 0x71, 0xa1, 0xcb,
+	0x24, 0x93, 0x62, 0xca, 0x66, 0xa3, 0x52, 0x49, 0x23, 0x49, 0xbb, 0xd0, 0xe5, 0xe0, 0x7b, 0xe8,
+	0x5c, 0x5c, 0x5f, 0x9d, 0xa2, 0x9e, 0x10, 0xd8, 0x30, 0xcb, 0x92, 0x46, 0x41, 0x3f, 0x18, 0x6e,
+	0xc6, 0x28, 0x93, 0x43, 0xd8, 0x72, 0x5e, 0x51, 0xab, 0x1f, 0x0c, 0xc3, 0xd8, 0x9f, 0x06, 0x9f,
+	0x36, 0x60, 0xef, 0x43, 0x3a, 0x51, 0x2c, 0xbb, 0xe5, 0x2f, 0xd2, 0xc2, 0xf9, 0x77, 0x62, 0x94,
+	0xc9, 0x4b, 0x00, 0x25, 0xa5, 0x49, 0x32, 0xaa, 0x8c, 0x8e, 0x5a, 0xfd, 0xf6, 0x30, 0x8c, 0x3b,
+	0x56, 0x73, 0x6a, 0x15, 0xe4, 0x1b, 0x20, 0x4c, 0x18, 0xaa, 0x0a, 0x9a, 0xb3, 0xd4, 0x50, 0x0f,
+	0x6b, 0x23, 0xec, 0x49, 0xd3, 0xe2, 0xe0, 0x87, 0xb0, 0x95, 0xe6, 0x05, 0x13, 0x3a, 0xda, 0x40,
+	0x88, 0x3f, 0x91, 0xaf, 0x61, 0x4f, 0xd1, 0x8f, 0x32, 0x4b, 0x0d, 0x93, 0x22, 0xe1, 0x4c, 0x9b,
+	0x68, 0x13, 0x01, 0xbb, 0xb5, 0xfa, 0x67, 0xa6, 0x0d, 0x39, 0x85, 0x7d, 0xcd, 0x66, 0x82, 0x89,
+	0x59, 0xc2, 0x72, 0x2a, 0x0c, 0x33, 0xcb, 0x68, 0xab, 0x1f, 0x0c, 0xbb, 0x27, 0xd1, 0xa8, 0xd0,
+	0xe5, 0xe8, 0xda, 0x19, 0xc7, 0xde, 0x36, 0x16, 0x53, 0x19, 0xef, 0xe9, 0xdb, 0x4a, 0x92, 0xc0,
+	0x6b, 0xa9, 0x66, 0xa9, 0x60, 0xbf, 0x63, 0xe0, 0x94, 0x27, 0x95, 0x60, 0xc6, 0x07, 0x9c, 0x32,
+	0xaa, 0x74, 0xb4, 0xdd, 0x6f, 0x0f, 0xbb, 0x27, 0xcf, 0x31, 0xa6, 0x2b, 0xd3, 0xe5, 0xcd, 0x78,
+	0x6d, 0x8f, 0x5f, 0xde, 0xf6, 0xbf, 0x11, 0xcc, 0xd4, 0x56, 0x4d, 0xde, 0x42, 0x2f, 0x53, 0xcb,
+	0xd2, 0x48, 0xdf, 0xb1, 0x68, 0xa7, 0x1f, 0xdc, 0x09, 0x77, 0x8a, 0x76, 0x57, 0xf8, 0x38, 0xcc,
+	0x1a, 0x27, 0xf2, 0x25, 0xec, 0x1a, 0xae, 0x93, 0x46, 0xd9, 0x3b, 0x58, 0x8b, 0xd0, 0x70, 0x1d,
+	0xaf, 0x2b, 0xff, 0x2d, 0x1c, 0x5a, 0xd4, 0x03, 0xd5, 0x07, 0x44, 0x1f, 0x18, 0xae, 0xc7, 0xf7,
+	0x1a, 0xf0, 0x03, 0xec, 0x4d, 0xf1, 0xfe, 0x44, 0xc8, 0x9c, 0x26, 0xb2, 0xd2, 0x51, 0x17, 0x73,
+	0x23, 0x8d, 0xdc, 0x7e, 0x91, 0x39, 0xbd, 0xbc, 0xd1, 0x71, 0x6f, 0x5a, 0x1f, 0x2b, 0x4d, 0xce,
+	0xe1, 0x89, 0x14, 0x9c, 0x09, 0x9a, 0xd4, 0x4d, 0x89, 0x42, 0xf4, 0xfe, 0xac, 0x59, 0x28, 0xc4,
+	0xc4, 0x6b, 0x48, 0xbc, 0x2f, 0xef, 0x68, 0x06, 0x7f, 0x06, 0x40, 0xee, 0x97, 0x81, 0x9c, 0xc0,
+	0x33, 0xdb, 0xaa, 0xd4, 0x54, 0x8a, 0x26, 0xf3, 0x54, 0xcf, 0x93, 0x69, 0x5a, 0x30, 0xbe, 0xf4,
+	0x84, 0x7c, 0xba, 0x36, 0x9e, 0xa7, 0x7a, 0xfe, 0x01, 0x4d, 0x64, 0x0c, 0x47, 0x2b, 0x22, 0x34,
+	0x1a, 0xe8, 0xbd, 0x2b, 0x91, 0x61, 0x92, 0x2d, 0xf4, 0x7f, 0xb5, 0x02, 0xd6, 0xad, 0xc2, 0x40,
+	0x1e, 0x35, 0xf8, 0x2b, 0x80, 0xbd, 0x71, 0x4e, 0x0b, 0xf6, 0xdb, 0xe3, 0x23, 0xb1, 0x0f, 0x6d,
+	0x56, 0x2e, 0xfc, 0x3c, 0x59, 0x91, 0x9c, 0xc0, 0x96, 0xcd, 0x8d, 0xaa, 0xa8, 0x8d, 0xe5, 0x78,
+	0x81, 0xe5, 0x58, 0xc7, 0xba, 0x46, 0x9b, 0xef, 0xb5, 0x47, 0x92, 0x2f, 0xa0, 0xd7, 0xa0, 0x7c,
+	0xb9, 0x88, 0x36, 0x30, 0x5e, 0x58, 0x2b, 0xaf, 0x16, 0xe4, 0x00, 0x36, 0x69, 0x29, 0xb3, 0x79,
+	0xb4, 0xd9, 0x0f, 0x86, 0xed, 0xd8, 0x1d, 0x06, 0x9f, 0x5a, 0xf0, 0xec, 0xc1, 0xe0, 0x36, 0xdd,
+	0x4c, 0xd1, 0x1c, 0xd3, 0x0d, 0x63, 0x94, 0xc9, 0x2e, 0xb4, 0xf4, 0x2a, 0xdb, 0x96, 0x5e, 0x90,
+	0x33, 0x78, 0xf5, 0x38, 0xfb, 0xf1, 0x11, 0x9d, 0xf8, 0xf3, 0xc7, 0x38, 0x6e, 0x6f, 0x52, 0x92,
+	0x53, 0xcc, 0x7a, 0x33, 0x46, 0xd9, 0x3e, 0x89, 0x0a, 0x25, 0x39, 0x2f, 0xa8, 0xb0, 0x01, 0x31,
+	0xeb, 0x4e, 0x1c, 0xd6, 0xca, 0x71, 0x4e, 0x7e, 0x84, 0x23, 0x9b, 0x96, 0x0d, 0x94, 0xf2, 0x06,
+	0x93, 0x12, 0x26, 0xa6, 0x52, 0x15, 0x28, 0xe3, 0x48, 0x87, 0xf1, 0xeb, 0x1a, 0x58, 0x93, 0x67,
+	0x5c, 0xc3, 0xec, 0x0c, 0xe4, 0x4c, 0x67, 0x5c, 0x6a, 0x9a, 0xdc, 0xbe, 0x79, 0xbb, 0x1f, 0x0c,
+	0x77, 0xe2, 0x83, 0x95, 0xf5, 0x7d, 0x33, 0x83, 0xb7, 0xf0, 0x62, 0xed, 0xd5, 0xb8, 0x7f, 0x9e,
+	0x8a, 0x9c, 0x53, 0x1c, 0xd5, 0x9d, 0x38, 0x5a, 0x21, 0xea, 0x8b, 0xcf, 0xd1, 0x3e, 0x90, 0xf0,
+	0xf4, 0x81, 0x25, 0x63, 0xdf, 0x5e, 0x56, 0x13, 0xce, 0xb2, 0xc4, 0x33, 0xc1, 0xb5, 0x20, 0x74,
+	0x4a, 0xd7, 0x24, 0xf2, 0x06, 0x76, 0x4b, 0xc5, 0x3e, 0xda, 0x51, 0xf5, 0xa8, 0x16, 0xf2, 0x25,
+	0x44, 0xbe, 0xfc, 0x44, 0xdd, 0xbe, 0xea, 0x79, 0x8c, 0x73, 0x1a, 0x5c, 0xc3, 0xb6, 0xb7, 0x90,
+	0xaf, 0x60, 0x77, 0x41, 0x9b, 0x3c, 0xf7, 0xbc, 0xec, 0x2d, 0x68, 0x83, 0xd4, 0xe4, 0x08, 0x42,
+	0x0b, 0x2b, 0x52, 0x43, 0x15, 0x4b, 0xb9, 0xef, 0x7d, 0x77, 0x41, 0x97, 0x17, 0x5e, 0x35, 0xf8,
+	0x03, 0xc8, 0xfd, 0xb5, 0x46, 0xfa, 0xd0, 0xb5, 0x2b, 0x84, 0x4d, 0x59, 0x96, 0x1a, 0xea, 0x9f,
+	0xd0, 0x54, 0xfd, 0x0f, 0xf2, 0xb4, 0xfe, 0x9b, 0x3c, 0x83, 0xbf, 0x03, 0xe8, 0xdd, 0x5a, 0x35,
+	0xf6, 0xc3, 0x40, 0x45, 0x3a, 0xe1, 0xee, 0xd2, 0x9d, 0xd8, 0x9f, 0xc8, 0x18, 0x0e, 0x32, 0xce,
+	0x6c, 0x53, 0x65, 0x75, 0xf7, 0x96, 0x47, 0xf6, 0x33, 0x71, 0x4e, 0x97, 0x55, 0xe3, 0x71, 0xef,
+	0x81, 0x94, 0x94, 0xaa, 0x3b, 0x81, 0xda, 0x8f, 0x07, 0xda, 0xb7, 0x2e, 0xcd, 0x30, 0x83, 0x7f,
+	0x02, 0x38, 0x7c, 0x78, 0xd1, 0xd9, 0x99, 0x90, 0x99, 0x2e, 0xfd, 0x13, 0x50, 0x26, 0xdf, 0xc1,
+	0xf3, 0x4c, 0xf1, 0x24, 0x67, 0xda, 0x28, 0x36, 0xa9, 0xdc, 0xb0, 0x4b, 0x26, 0xf0, 0x63, 0x6a,
+	0x61, 0xcf, 0x32, 0xc5, 0xcf, 0x1a, 0xd6, 0x2b, 0x34, 0x92, 0xd7, 0xd0, 0x9d, 0xa6, 0x8c, 0x27,
+	0x48, 0xc2, 0x1c, 0xd3, 0xdc, 0x89, 0xc1, 0xaa, 0x4e, 0x51, 0x63, 0xb9, 0x90, 0xa5, 0xd9, 0x9c,
+	0x26, 0x79, 0xa5, 0xdc, 0xd0, 0x6c, 0x38, 0x2e, 0xa0, 0xf6, 0xcc, 0x2b, 0x49, 0x04, 0xdb, 0x86,
+	0x15, 0x54, 0x56, 0xc6, 0x4f, 0xe3, 0xea, 0xf8, 0x2e, 0x81, 0x23, 0xa9, 0x66, 0xa3, 0xf9, 0xb2,
+	0xa4, 0x8a, 0xd3, 0x7c, 0x46, 0xd5, 0xc8, 0xed, 0x7b, 0xf7, 0x7f, 0xa1, 0x6d, 0x49, 0xde, 0xed,
+	0x5f, 0xe8, 0xd2, 0xed, 0x96, 0xab, 0x34, 0x5b, 0xa4, 0x33, 0xfa, 0xeb, 0x70, 0xc6, 0xcc, 0xbc,
+	0x9a, 0x8c, 0x32, 0x59, 0x1c, 0x37, 0x7c, 0x8f, 0x9d, 0xef, 0xb1, 0xf3, 0xb5, 0x7f, 0x2b, 0x93,
+	0x2d, 0x94, 0xdf, 0xfc, 0x3b, 0x00, 0xfe, 0xe2, 0x0e, 0x2a, 0xbf, 0x08, 0x00, 0x00,
 }
@@ -48,6 +48,7 @@ const (
 	ChaincodeMessage_GET_HISTORY_FOR_KEY ChaincodeMessage_Type = 19
 	ChaincodeMessage_GET_STATE_METADATA  ChaincodeMessage_Type = 20
 	ChaincodeMessage_PUT_STATE_METADATA  ChaincodeMessage_Type = 21
+	ChaincodeMessage_PURGE_PRIVATE_DATA  ChaincodeMessage_Type = 22
 )
 
 var ChaincodeMessage_Type_name = map[int32]string{
@@ -72,6 +73,7 @@ var ChaincodeMessage_Type_name = map[int32]string{
 	19: "GET_HISTORY_FOR_KEY",
 	20: "GET_STATE_METADATA",
 	21: "PUT_STATE_METADATA",
+	22: "PURGE_PRIVATE_DATA",
 }
 var ChaincodeMessage_Type_value = map[string]int32{
 	"UNDEFINED":           0,
@@ -95,13 +97,14 @@ var ChaincodeMessage_Type_value = map[string]int32{
 	"GET_HISTORY_FOR_KEY": 19,
 	"GET_STATE_METADATA":  20,
 	"PUT_STATE_METADATA":  21,
+	"PURGE_PRIVATE_DATA":  22,
 }
 
 func (x ChaincodeMessage_Type) String() string {
 	return proto.EnumName(ChaincodeMessage_Type_name, int32(x))
 }
 func (ChaincodeMessage_Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{0, 0}
+	return fileDescriptor_chaincode_shim_d3364e2349053e7f, []int{0, 0}
 }
 
 type ChaincodeMessage struct {
@@ -125,7 +128,7 @@ func (m *ChaincodeMessage) Reset()         { *m = ChaincodeMessage{} }
 func (m *ChaincodeMessage) String() string { return proto.CompactTextString(m) }
 func (*ChaincodeMessage) ProtoMessage()    {}
 func (*ChaincodeMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{0}
+	return fileDescriptor_chaincode_shim_d3364e2349053e7f, []int{0}
 }
 func (m *ChaincodeMessage) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChaincodeMessage.Unmarshal(m, b)
@@ -209,7 +212,7 @@ func (m *GetState) Reset()         { *m = GetState{} }
 func (m *GetState) String() string { return proto.CompactTextString(m) }
 func (*GetState) ProtoMessage()    {}
 func (*GetState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{1}
+	return fileDescriptor_chaincode_shim_d3364e2349053e7f, []int{1}
 }
 func (m *GetState) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetState.Unmarshal(m, b)
@@ -255,7 +258,7 @@ func (m *GetStateMetadata) Reset()         { *m = GetStateMetadata{} }
 func (m *GetStateMetadata) String() string { return proto.CompactTextString(m) }
 func (*GetStateMetadata) ProtoMessage()    {}
 func (*GetStateMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{2}
+	return fileDescriptor_chaincode_shim_d3364e2349053e7f, []int{2}
 }
 func (m *GetStateMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetStateMetadata.Unmarshal(m, b)
@@ -306,7 +309,7 @@ func (m *PutState) Reset()         { *m = PutState{} }
 func (m *PutState) String() string { return proto.CompactTextString(m) }
 func (*PutState) ProtoMessage()    {}
 func (*PutState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{3}
+	return fileDescriptor_chaincode_shim_d3364e2349053e7f, []int{3}
 }
 func (m *PutState) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PutState.Unmarshal(m, b)
@@ -360,7 +363,7 @@ func (m *PutStateMetadata) Reset()         { *m = PutStateMetadata{} }
 func (m *PutStateMetadata) String() string { return proto.CompactTextString(m) }
 func (*PutStateMetadata) ProtoMessage()    {}
 func (*PutStateMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{4}
+	return fileDescriptor_chaincode_shim_d3364e2349053e7f, []int{4}
 }
 func (m *PutStateMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PutStateMetadata.Unmarshal(m, b)
@@ -417,7 +420,7 @@ func (m *DelState) Reset()         { *m = DelState{} }
 func (m *DelState) String() string { return proto.CompactTextString(m) }
 func (*DelState) ProtoMessage()    {}
 func (*DelState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{5}
+	return fileDescriptor_chaincode_shim_d3364e2349053e7f, []int{5}
 }
 func (m *DelState) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DelState.Unmarshal(m, b)
@@ -469,7 +472,7 @@ func (m *GetStateByRange) Reset()         { *m = GetStateByRange{} }
 func (m *GetStateByRange) String() string { return proto.CompactTextString(m) }
 func (*GetStateByRange) ProtoMessage()    {}
 func (*GetStateByRange) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{6}
+	return fileDescriptor_chaincode_shim_d3364e2349053e7f, []int{6}
 }
 func (m *GetStateByRange) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetStateByRange.Unmarshal(m, b)
@@ -534,7 +537,7 @@ func (m *GetQueryResult) Reset()         { *m = GetQueryResult{} }
 func (m *GetQueryResult) String() string { return proto.CompactTextString(m) }
 func (*GetQueryResult) ProtoMessage()    {}
 func (*GetQueryResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{7}
+	return fileDescriptor_chaincode_shim_d3364e2349053e7f, []int{7}
 }
 func (m *GetQueryResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetQueryResult.Unmarshal(m, b)
@@ -590,7 +593,7 @@ func (m *QueryMetadata) Reset()         { *m = QueryMetadata{} }
 func (m *QueryMetadata) String() string { return proto.CompactTextString(m) }
 func (*QueryMetadata) ProtoMessage()    {}
 func (*QueryMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{8}
+	return fileDescriptor_chaincode_shim_d3364e2349053e7f, []int{8}
 }
 func (m *QueryMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryMetadata.Unmarshal(m, b)
@@ -627,17 +630,20 @@ func (m *QueryMetadata) GetBookmark() string {
 // GetHistoryForKey is the payload of a ChaincodeMessage. It contains a key
 // for which the historical values need to be retrieved.
 type GetHistoryForKey struct {
-	Key                  string   `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Key string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	// the historical values of all the keys starting with key are retrieved
+	KeyPrefix            bool                 `protobuf:"varint,2,opt,name=key_prefix,json=keyPrefix" json:"key_prefix,omitempty"`
+	Options              *HistoryQueryOptions `protobuf:"bytes,3,opt,name=options" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
 func (m *GetHistoryForKey) Reset()         { *m = GetHistoryForKey{} }
 func (m *GetHistoryForKey) String() string { return proto.CompactTextString(m) }
 func (*GetHistoryForKey) ProtoMessage()    {}
 func (*GetHistoryForKey) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{9}
+	return fileDescriptor_chaincode_shim_d3364e2349053e7f, []int{9}
 }
 func (m *GetHistoryForKey) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetHistoryForKey.Unmarshal(m, b)
@@ -664,6 +670,97 @@ func (m *GetHistoryForKey) GetKey() string {
 	return ""
 }
 
+func (m *GetHistoryForKey) GetKeyPrefix() bool {
+	if m != nil {
+		return m.KeyPrefix
+	}
+	return false
+}
+
+func (m *GetHistoryForKey) GetOptions() *HistoryQueryOptions {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+// HistoryQueryOptions restricts and orders the historical values retrieved
+// by a GetHistoryForKey
+type HistoryQueryOptions struct {
+	// only the values written in the blocks from start_block to end_block,
+	// both included, are retrieved; an end_block of 0 means no upper bound
+	StartBlock uint64 `protobuf:"varint,1,opt,name=start_block,json=startBlock" json:"start_block,omitempty"`
+	EndBlock   uint64 `protobuf:"varint,2,opt,name=end_block,json=endBlock" json:"end_block,omitempty"`
+	// only the values written by the transactions whose timestamp is in
+	// [start_time, end_time) are retrieved; an unset time means no bound
+	StartTime *timestamp.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime" json:"start_time,omitempty"`
+	EndTime   *timestamp.Timestamp `protobuf:"bytes,4,opt,name=end_time,json=endTime" json:"end_time,omitempty"`
+	// the most recent values are retrieved first
+	NewestFirst          bool     `protobuf:"varint,5,opt,name=newest_first,json=newestFirst" json:"newest_first,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HistoryQueryOptions) Reset()         { *m = HistoryQueryOptions{} }
+func (m *HistoryQueryOptions) String() string { return proto.CompactTextString(m) }
+func (*HistoryQueryOptions) ProtoMessage()    {}
+func (*HistoryQueryOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chaincode_shim_d3364e2349053e7f, []int{10}
+}
+func (m *HistoryQueryOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryQueryOptions.Unmarshal(m, b)
+}
+func (m *HistoryQueryOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryQueryOptions.Marshal(b, m, deterministic)
+}
+func (dst *HistoryQueryOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryQueryOptions.Merge(dst, src)
+}
+func (m *HistoryQueryOptions) XXX_Size() int {
+	return xxx_messageInfo_HistoryQueryOptions.Size(m)
+}
+func (m *HistoryQueryOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryQueryOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryQueryOptions proto.InternalMessageInfo
+
+func (m *HistoryQueryOptions) GetStartBlock() uint64 {
+	if m != nil {
+		return m.StartBlock
+	}
+	return 0
+}
+
+func (m *HistoryQueryOptions) GetEndBlock() uint64 {
+	if m != nil {
+		return m.EndBlock
+	}
+	return 0
+}
+
+func (m *HistoryQueryOptions) GetStartTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.StartTime
+	}
+	return nil
+}
+
+func (m *HistoryQueryOptions) GetEndTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.EndTime
+	}
+	return nil
+}
+
+func (m *HistoryQueryOptions) GetNewestFirst() bool {
+	if m != nil {
+		return m.NewestFirst
+	}
+	return false
+}
+
 type QueryStateNext struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -675,7 +772,7 @@ func (m *QueryStateNext) Reset()         { *m = QueryStateNext{} }
 func (m *QueryStateNext) String() string { return proto.CompactTextString(m) }
 func (*QueryStateNext) ProtoMessage()    {}
 func (*QueryStateNext) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{10}
+	return fileDescriptor_chaincode_shim_d3364e2349053e7f, []int{11}
 }
 func (m *QueryStateNext) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryStateNext.Unmarshal(m, b)
@@ -713,7 +810,7 @@ func (m *QueryStateClose) Reset()         { *m = QueryStateClose{} }
 func (m *QueryStateClose) String() string { return proto.CompactTextString(m) }
 func (*QueryStateClose) ProtoMessage()    {}
 func (*QueryStateClose) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{11}
+	return fileDescriptor_chaincode_shim_d3364e2349053e7f, []int{12}
 }
 func (m *QueryStateClose) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryStateClose.Unmarshal(m, b)
@@ -752,7 +849,7 @@ func (m *QueryResultBytes) Reset()         { *m = QueryResultBytes{} }
 func (m *QueryResultBytes) String() string { return proto.CompactTextString(m) }
 func (*QueryResultBytes) ProtoMessage()    {}
 func (*QueryResultBytes) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{12}
+	return fileDescriptor_chaincode_shim_d3364e2349053e7f, []int{13}
 }
 func (m *QueryResultBytes) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryResultBytes.Unmarshal(m, b)
@@ -798,7 +895,7 @@ func (m *QueryResponse) Reset()         { *m = QueryResponse{} }
 func (m *QueryResponse) String() string { return proto.CompactTextString(m) }
 func (*QueryResponse) ProtoMessage()    {}
 func (*QueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{13}
+	return fileDescriptor_chaincode_shim_d3364e2349053e7f, []int{14}
 }
 func (m *QueryResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryResponse.Unmarshal(m, b)
@@ -860,7 +957,7 @@ func (m *QueryResponseMetadata) Reset()         { *m = QueryResponseMetadata{} }
 func (m *QueryResponseMetadata) String() string { return proto.CompactTextString(m) }
 func (*QueryResponseMetadata) ProtoMessage()    {}
 func (*QueryResponseMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{14}
+	return fileDescriptor_chaincode_shim_d3364e2349053e7f, []int{15}
 }
 func (m *QueryResponseMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryResponseMetadata.Unmarshal(m, b)
@@ -906,7 +1003,7 @@ func (m *StateMetadata) Reset()         { *m = StateMetadata{} }
 func (m *StateMetadata) String() string { return proto.CompactTextString(m) }
 func (*StateMetadata) ProtoMessage()    {}
 func (*StateMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{15}
+	return fileDescriptor_chaincode_shim_d3364e2349053e7f, []int{16}
 }
 func (m *StateMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StateMetadata.Unmarshal(m, b)
@@ -951,7 +1048,7 @@ func (m *StateMetadataResult) Reset()         { *m = StateMetadataResult{} }
 func (m *StateMetadataResult) String() string { return proto.CompactTextString(m) }
 func (*StateMetadataResult) ProtoMessage()    {}
 func (*StateMetadataResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{16}
+	return fileDescriptor_chaincode_shim_d3364e2349053e7f, []int{17}
 }
 func (m *StateMetadataResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StateMetadataResult.Unmarshal(m, b)
@@ -989,6 +1086,7 @@ func init() {
 	proto.RegisterType((*GetQueryResult)(nil), "protos.GetQueryResult")
 	proto.RegisterType((*QueryMetadata)(nil), "protos.QueryMetadata")
 	proto.RegisterType((*GetHistoryForKey)(nil), "protos.GetHistoryForKey")
+	proto.RegisterType((*HistoryQueryOptions)(nil), "protos.HistoryQueryOptions")
 	proto.RegisterType((*QueryStateNext)(nil), "protos.QueryStateNext")
 	proto.RegisterType((*QueryStateClose)(nil), "protos.QueryStateClose")
 	proto.RegisterType((*QueryResultBytes)(nil), "protos.QueryResultBytes")
@@ -1104,73 +1202,82 @@ var _ChaincodeSupport_serviceDesc = grpc.ServiceDesc{
 }
 
 func init() {
-	proto.RegisterFile("peer/chaincode_shim.proto", fileDescriptor_chaincode_shim_3c6cdf1ba834f673)
-}
-
-var fileDescriptor_chaincode_shim_3c6cdf1ba834f673 = []byte{
-	// 1010 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0xcf, 0x73, 0xda, 0x46,
-	0x14, 0x0e, 0x06, 0x8c, 0x78, 0xd8, 0x78, 0xb3, 0x8e, 0x5d, 0xc2, 0x4c, 0x5a, 0xca, 0xf4, 0x40,
-	0x2f, 0xd0, 0xd0, 0x1e, 0x7a, 0xe8, 0x4c, 0x06, 0xc3, 0x1a, 0x33, 0xb6, 0x05, 0x59, 0xc9, 0x99,
-	0xb8, 0x17, 0x8d, 0x90, 0xd6, 0x42, 0x63, 0xa1, 0x55, 0xa5, 0x25, 0x0d, 0xbd, 0xf5, 0xda, 0x7f,
-	0xa9, 0x7f, 0x58, 0xaf, 0x9d, 0xd5, 0x2f, 0x03, 0xae, 0x93, 0x4e, 0x4e, 0xe8, 0x7b, 0xef, 0xdb,
-	0xef, 0xfd, 0xda, 0x87, 0x04, 0x2f, 0x03, 0xc6, 0xc2, 0x9e, 0xb5, 0x30, 0x5d, 0xdf, 0xe2, 0x36,
-	0x33, 0xa2, 0x85, 0xbb, 0xec, 0x06, 0x21, 0x17, 0x1c, 0xef, 0xc7, 0x3f, 0x51, 0xb3, 0xb9, 0x43,
-	0x61, 0x1f, 0x98, 0x2f, 0x12, 0x4e, 0xf3, 0x38, 0xf6, 0x05, 0x21, 0x0f, 0x78, 0x64, 0x7a, 0xa9,
-	0xf1, 0x1b, 0x87, 0x73, 0xc7, 0x63, 0xbd, 0x18, 0xcd, 0x57, 0x77, 0x3d, 0xe1, 0x2e, 0x59, 0x24,
-	0xcc, 0x65, 0x90, 0x10, 0xda, 0x7f, 0x97, 0x01, 0x0d, 0x33, 0xbd, 0x6b, 0x16, 0x45, 0xa6, 0xc3,
-	0xf0, 0x6b, 0x28, 0x89, 0x75, 0xc0, 0x1a, 0x85, 0x56, 0xa1, 0x53, 0xef, 0xbf, 0x4a, 0xa8, 0x51,
-	0x77, 0x97, 0xd7, 0xd5, 0xd7, 0x01, 0xa3, 0x31, 0x15, 0xff, 0x0c, 0xd5, 0x5c, 0xba, 0xb1, 0xd7,
-	0x2a, 0x74, 0x6a, 0xfd, 0x66, 0x37, 0x09, 0xde, 0xcd, 0x82, 0x77, 0xf5, 0x8c, 0x41, 0x1f, 0xc8,
-	0xb8, 0x01, 0x95, 0xc0, 0x5c, 0x7b, 0xdc, 0xb4, 0x1b, 0xc5, 0x56, 0xa1, 0x73, 0x40, 0x33, 0x88,
-	0x31, 0x94, 0xc4, 0x47, 0xd7, 0x6e, 0x94, 0x5a, 0x85, 0x4e, 0x95, 0xc6, 0xcf, 0xb8, 0x0f, 0x4a,
-	0x56, 0x62, 0xa3, 0x1c, 0x87, 0x39, 0xcd, 0xd2, 0xd3, 0x5c, 0xc7, 0x67, 0xf6, 0x2c, 0xf5, 0xd2,
-	0x9c, 0x87, 0xdf, 0xc0, 0xd1, 0x4e, 0xcb, 0x1a, 0xfb, 0xdb, 0x47, 0xf3, 0xca, 0x88, 0xf4, 0xd2,
-	0xba, 0xb5, 0x85, 0xf1, 0x2b, 0x00, 0x6b, 0x61, 0xfa, 0x3e, 0xf3, 0x0c, 0xd7, 0x6e, 0x54, 0xe2,
-	0x74, 0xaa, 0xa9, 0x65, 0x62, 0xb7, 0xff, 0xd9, 0x83, 0x92, 0x6c, 0x05, 0x3e, 0x84, 0xea, 0x8d,
-	0x3a, 0x22, 0xe7, 0x13, 0x95, 0x8c, 0xd0, 0x33, 0x7c, 0x00, 0x0a, 0x25, 0xe3, 0x89, 0xa6, 0x13,
-	0x8a, 0x0a, 0xb8, 0x0e, 0x90, 0x21, 0x32, 0x42, 0x7b, 0x58, 0x81, 0xd2, 0x44, 0x9d, 0xe8, 0xa8,
-	0x88, 0xab, 0x50, 0xa6, 0x64, 0x30, 0xba, 0x45, 0x25, 0x7c, 0x04, 0x35, 0x9d, 0x0e, 0x54, 0x6d,
-	0x30, 0xd4, 0x27, 0x53, 0x15, 0x95, 0xa5, 0xe4, 0x70, 0x7a, 0x3d, 0xbb, 0x22, 0x3a, 0x19, 0xa1,
-	0x7d, 0x49, 0x25, 0x94, 0x4e, 0x29, 0xaa, 0x48, 0xcf, 0x98, 0xe8, 0x86, 0xa6, 0x0f, 0x74, 0x82,
-	0x14, 0x09, 0x67, 0x37, 0x19, 0xac, 0x4a, 0x38, 0x22, 0x57, 0x29, 0x04, 0xfc, 0x02, 0xd0, 0x44,
-	0x7d, 0x37, 0xbd, 0x24, 0xc6, 0xf0, 0x62, 0x30, 0x51, 0x87, 0xd3, 0x11, 0x41, 0xb5, 0x24, 0x41,
-	0x6d, 0x36, 0x55, 0x35, 0x82, 0x0e, 0xf1, 0x29, 0xe0, 0x5c, 0xd0, 0x38, 0xbb, 0x35, 0xe8, 0x40,
-	0x1d, 0x13, 0x54, 0x97, 0x67, 0xa5, 0xfd, 0xed, 0x0d, 0xa1, 0xb7, 0x06, 0x25, 0xda, 0xcd, 0x95,
-	0x8e, 0x8e, 0xa4, 0x35, 0xb1, 0x24, 0x7c, 0x95, 0xbc, 0xd7, 0x11, 0xc2, 0x27, 0xf0, 0x7c, 0xd3,
-	0x3a, 0xbc, 0x9a, 0x6a, 0x04, 0x3d, 0x97, 0xd9, 0x5c, 0x12, 0x32, 0x1b, 0x5c, 0x4d, 0xde, 0x11,
-	0x84, 0xf1, 0x57, 0x70, 0x2c, 0x15, 0x2f, 0x26, 0x9a, 0x3e, 0xa5, 0xb7, 0xc6, 0xf9, 0x94, 0x1a,
-	0x97, 0xe4, 0x16, 0x1d, 0x6f, 0xa7, 0x70, 0x4d, 0xf4, 0xc1, 0x68, 0xa0, 0x0f, 0xd0, 0x0b, 0x69,
-	0xcf, 0x8b, 0x7b, 0xb0, 0x9f, 0xb4, 0x7f, 0x01, 0x65, 0xcc, 0x84, 0x26, 0x4c, 0xc1, 0x30, 0x82,
-	0xe2, 0x3d, 0x5b, 0xc7, 0x77, 0xb6, 0x4a, 0xe5, 0x23, 0xfe, 0x1a, 0xc0, 0xe2, 0x9e, 0xc7, 0x2c,
-	0xe1, 0x72, 0x3f, 0xbe, 0x94, 0x55, 0xba, 0x61, 0x69, 0x8f, 0x00, 0x65, 0xa7, 0xaf, 0x99, 0x30,
-	0x6d, 0x53, 0x98, 0x5f, 0xa0, 0x42, 0x41, 0x99, 0xad, 0x9e, 0xcc, 0xe1, 0x05, 0x94, 0x3f, 0x98,
-	0xde, 0x8a, 0xc5, 0x07, 0x0f, 0x68, 0x02, 0x76, 0x34, 0x8b, 0x8f, 0x34, 0x7f, 0x07, 0x94, 0x69,
-	0xfe, 0xef, 0xcc, 0x1e, 0xa9, 0xe0, 0xd7, 0xa0, 0x2c, 0xd3, 0xd3, 0xf1, 0x0e, 0xd5, 0xfa, 0x27,
-	0xf9, 0xae, 0x6c, 0x4a, 0xd3, 0x9c, 0x26, 0x1b, 0x3a, 0x62, 0xde, 0x97, 0x36, 0xf4, 0xcf, 0x02,
-	0x1c, 0x65, 0x1d, 0x3d, 0x5b, 0x53, 0xd3, 0x77, 0x18, 0x6e, 0x82, 0x12, 0x09, 0x33, 0x14, 0x97,
-	0xb9, 0x54, 0x8e, 0xf1, 0x29, 0xec, 0x33, 0xdf, 0x96, 0x9e, 0x44, 0x2b, 0x45, 0x9f, 0x2d, 0xac,
-	0xb9, 0x53, 0xd8, 0xc1, 0x46, 0x05, 0x73, 0xa8, 0x8f, 0x99, 0x78, 0xbb, 0x62, 0xe1, 0x9a, 0xb2,
-	0x68, 0xe5, 0x09, 0x39, 0x82, 0xdf, 0x24, 0x4c, 0xc3, 0x27, 0xe0, 0x73, 0xb5, 0x6c, 0xc5, 0x28,
-	0xee, 0xc4, 0x18, 0xc3, 0x61, 0x1c, 0x20, 0x9f, 0x4d, 0x13, 0x94, 0xc0, 0x74, 0x98, 0xe6, 0xfe,
-	0x91, 0xfc, 0x69, 0x96, 0x69, 0x8e, 0xa5, 0x6f, 0xce, 0xf9, 0xfd, 0xd2, 0x0c, 0xef, 0xd3, 0x30,
-	0x39, 0x6e, 0x7f, 0x17, 0xdf, 0xc0, 0x0b, 0x37, 0x12, 0x3c, 0x5c, 0x9f, 0xf3, 0x50, 0x16, 0xff,
-	0xa8, 0xed, 0xed, 0x16, 0xd4, 0xe3, 0x70, 0x71, 0x5f, 0x55, 0xf6, 0x51, 0xe0, 0x3a, 0xec, 0xb9,
-	0x76, 0x4a, 0xd9, 0x73, 0xed, 0xf6, 0xb7, 0x70, 0xf4, 0xc0, 0x18, 0x7a, 0x3c, 0x62, 0x8f, 0x28,
-	0x3f, 0x01, 0xda, 0x68, 0xca, 0xd9, 0x5a, 0xb0, 0x08, 0xb7, 0xa0, 0x16, 0x3e, 0xc0, 0x98, 0x7c,
-	0x40, 0x37, 0x4d, 0xed, 0xbf, 0x0a, 0x69, 0xa9, 0x94, 0x45, 0x01, 0xf7, 0x23, 0x86, 0xfb, 0x50,
-	0x49, 0x08, 0x92, 0x5f, 0xec, 0xd4, 0xfa, 0x8d, 0xec, 0x4e, 0xed, 0xca, 0xd3, 0x8c, 0x88, 0x5f,
-	0x82, 0xb2, 0x30, 0x23, 0x63, 0xc9, 0xc3, 0x64, 0x0f, 0x14, 0x5a, 0x59, 0x98, 0xd1, 0x35, 0x0f,
-	0xb3, 0x34, 0x8b, 0x59, 0x9a, 0x9f, 0x1c, 0xad, 0x03, 0x27, 0x5b, 0xb9, 0xe4, 0xed, 0xef, 0xc3,
-	0xc9, 0x1d, 0x13, 0xd6, 0x82, 0xd9, 0x46, 0xc8, 0x2c, 0x1e, 0xda, 0x91, 0x61, 0xf1, 0x95, 0x2f,
-	0xd2, 0x59, 0x1c, 0xa7, 0x4e, 0x9a, 0xf8, 0x86, 0xd2, 0xf5, 0xc9, 0xb1, 0xbc, 0x81, 0xc3, 0xed,
-	0xdd, 0x6b, 0x40, 0x45, 0x66, 0xf1, 0x30, 0x97, 0x0c, 0xfe, 0xf7, 0x7e, 0xb7, 0xcf, 0xe1, 0x78,
-	0x7b, 0xc3, 0x92, 0x9b, 0xd8, 0x83, 0x0a, 0xf3, 0x45, 0xe8, 0xb2, 0xac, 0x77, 0x4f, 0xec, 0x63,
-	0xc6, 0xea, 0xbf, 0xdf, 0x78, 0x39, 0x6b, 0xab, 0x20, 0xe0, 0xa1, 0xc0, 0x23, 0x50, 0x28, 0x73,
-	0xdc, 0x48, 0xb0, 0x10, 0x37, 0x9e, 0x7a, 0x35, 0x37, 0x9f, 0xf4, 0xb4, 0x9f, 0x75, 0x0a, 0x3f,
-	0x14, 0xce, 0xa6, 0xd0, 0xe6, 0xa1, 0xd3, 0x5d, 0xac, 0x03, 0x16, 0x7a, 0xcc, 0x76, 0x58, 0xd8,
-	0xbd, 0x33, 0xe7, 0xa1, 0x6b, 0x65, 0xe7, 0xe4, 0xd7, 0xc4, 0xaf, 0xdf, 0x3b, 0xae, 0x58, 0xac,
-	0xe6, 0x5d, 0x8b, 0x2f, 0x7b, 0x1b, 0xd4, 0x5e, 0x42, 0x4d, 0xbe, 0x2a, 0xa2, 0x9e, 0xa4, 0xce,
-	0x93, 0x4f, 0x94, 0x1f, 0xff, 0x0d, 0x00, 0x00, 0xff, 0xff, 0x41, 0xbd, 0x1a, 0x01, 0xc6, 0x08,
+	proto.RegisterFile("peer/chaincode_shim.proto", fileDescriptor_chaincode_shim_d3364e2349053e7f)
+}
+
+var fileDescriptor_chaincode_shim_d3364e2349053e7f = []byte{
+	// 1154 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0x41, 0x73, 0x1a, 0x37,
+	0x14, 0x0e, 0x06, 0x9b, 0xe5, 0x61, 0x63, 0x45, 0x8e, 0x5d, 0x42, 0x26, 0x8d, 0xc3, 0xc9, 0xbd,
+	0x40, 0x43, 0x9b, 0x99, 0x76, 0xa6, 0x33, 0x19, 0x0c, 0x32, 0x61, 0x6c, 0x03, 0x11, 0xeb, 0x4c,
+	0xdc, 0xcb, 0xce, 0xc2, 0x3e, 0xc3, 0x8e, 0x61, 0xb5, 0xdd, 0x15, 0x89, 0xc9, 0xad, 0xd7, 0x5e,
+	0xfa, 0xe3, 0xfa, 0x27, 0xfa, 0x33, 0x3a, 0xd2, 0xee, 0x62, 0xc0, 0x71, 0x3c, 0xcd, 0x09, 0xbe,
+	0xef, 0x7d, 0xef, 0xd3, 0x93, 0xf4, 0xa4, 0x15, 0x3c, 0xf5, 0x11, 0x83, 0xea, 0x70, 0x6c, 0xbb,
+	0xde, 0x50, 0x38, 0x68, 0x85, 0x63, 0x77, 0x5a, 0xf1, 0x03, 0x21, 0x05, 0xdd, 0xd2, 0x3f, 0x61,
+	0xa9, 0xb4, 0x26, 0xc1, 0x8f, 0xe8, 0xc9, 0x48, 0x53, 0xda, 0xd3, 0x31, 0x3f, 0x10, 0xbe, 0x08,
+	0xed, 0x49, 0x4c, 0xbe, 0x18, 0x09, 0x31, 0x9a, 0x60, 0x55, 0xa3, 0xc1, 0xec, 0xaa, 0x2a, 0xdd,
+	0x29, 0x86, 0xd2, 0x9e, 0xfa, 0x91, 0xa0, 0xfc, 0xcf, 0x26, 0x90, 0x46, 0xe2, 0x77, 0x8e, 0x61,
+	0x68, 0x8f, 0x90, 0xbe, 0x82, 0x8c, 0x9c, 0xfb, 0x58, 0x4c, 0x1d, 0xa6, 0x8e, 0x0a, 0xb5, 0xe7,
+	0x91, 0x34, 0xac, 0xac, 0xeb, 0x2a, 0xe6, 0xdc, 0x47, 0xae, 0xa5, 0xf4, 0x17, 0xc8, 0x2d, 0xac,
+	0x8b, 0x1b, 0x87, 0xa9, 0xa3, 0x7c, 0xad, 0x54, 0x89, 0x06, 0xaf, 0x24, 0x83, 0x57, 0xcc, 0x44,
+	0xc1, 0x6f, 0xc5, 0xb4, 0x08, 0x59, 0xdf, 0x9e, 0x4f, 0x84, 0xed, 0x14, 0xd3, 0x87, 0xa9, 0xa3,
+	0x6d, 0x9e, 0x40, 0x4a, 0x21, 0x23, 0x6f, 0x5c, 0xa7, 0x98, 0x39, 0x4c, 0x1d, 0xe5, 0xb8, 0xfe,
+	0x4f, 0x6b, 0x60, 0x24, 0x53, 0x2c, 0x6e, 0xea, 0x61, 0x0e, 0x92, 0xf2, 0xfa, 0xee, 0xc8, 0x43,
+	0xa7, 0x17, 0x47, 0xf9, 0x42, 0x47, 0xdf, 0xc0, 0xee, 0xda, 0x92, 0x15, 0xb7, 0x56, 0x53, 0x17,
+	0x33, 0x63, 0x2a, 0xca, 0x0b, 0xc3, 0x15, 0x4c, 0x9f, 0x03, 0x0c, 0xc7, 0xb6, 0xe7, 0xe1, 0xc4,
+	0x72, 0x9d, 0x62, 0x56, 0x97, 0x93, 0x8b, 0x99, 0xb6, 0x53, 0xfe, 0x3b, 0x0d, 0x19, 0xb5, 0x14,
+	0x74, 0x07, 0x72, 0x17, 0x9d, 0x26, 0x3b, 0x69, 0x77, 0x58, 0x93, 0x3c, 0xa2, 0xdb, 0x60, 0x70,
+	0xd6, 0x6a, 0xf7, 0x4d, 0xc6, 0x49, 0x8a, 0x16, 0x00, 0x12, 0xc4, 0x9a, 0x64, 0x83, 0x1a, 0x90,
+	0x69, 0x77, 0xda, 0x26, 0x49, 0xd3, 0x1c, 0x6c, 0x72, 0x56, 0x6f, 0x5e, 0x92, 0x0c, 0xdd, 0x85,
+	0xbc, 0xc9, 0xeb, 0x9d, 0x7e, 0xbd, 0x61, 0xb6, 0xbb, 0x1d, 0xb2, 0xa9, 0x2c, 0x1b, 0xdd, 0xf3,
+	0xde, 0x19, 0x33, 0x59, 0x93, 0x6c, 0x29, 0x29, 0xe3, 0xbc, 0xcb, 0x49, 0x56, 0x45, 0x5a, 0xcc,
+	0xb4, 0xfa, 0x66, 0xdd, 0x64, 0xc4, 0x50, 0xb0, 0x77, 0x91, 0xc0, 0x9c, 0x82, 0x4d, 0x76, 0x16,
+	0x43, 0xa0, 0x4f, 0x80, 0xb4, 0x3b, 0xef, 0xbb, 0xa7, 0xcc, 0x6a, 0xbc, 0xad, 0xb7, 0x3b, 0x8d,
+	0x6e, 0x93, 0x91, 0x7c, 0x54, 0x60, 0xbf, 0xd7, 0xed, 0xf4, 0x19, 0xd9, 0xa1, 0x07, 0x40, 0x17,
+	0x86, 0xd6, 0xf1, 0xa5, 0xc5, 0xeb, 0x9d, 0x16, 0x23, 0x05, 0x95, 0xab, 0xf8, 0x77, 0x17, 0x8c,
+	0x5f, 0x5a, 0x9c, 0xf5, 0x2f, 0xce, 0x4c, 0xb2, 0xab, 0xd8, 0x88, 0x89, 0xf4, 0x1d, 0xf6, 0xc1,
+	0x24, 0x84, 0xee, 0xc3, 0xe3, 0x65, 0xb6, 0x71, 0xd6, 0xed, 0x33, 0xf2, 0x58, 0x55, 0x73, 0xca,
+	0x58, 0xaf, 0x7e, 0xd6, 0x7e, 0xcf, 0x08, 0xa5, 0xdf, 0xc1, 0x9e, 0x72, 0x7c, 0xdb, 0xee, 0x9b,
+	0x5d, 0x7e, 0x69, 0x9d, 0x74, 0xb9, 0x75, 0xca, 0x2e, 0xc9, 0xde, 0x6a, 0x09, 0xe7, 0xcc, 0xac,
+	0x37, 0xeb, 0x66, 0x9d, 0x3c, 0x51, 0x7c, 0xef, 0xe2, 0x0e, 0xbf, 0x1f, 0xf1, 0xbc, 0xc5, 0xac,
+	0x1e, 0x6f, 0xbf, 0x57, 0x31, 0xcd, 0x1f, 0x94, 0x7f, 0x03, 0xa3, 0x85, 0xb2, 0x2f, 0x6d, 0x89,
+	0x94, 0x40, 0xfa, 0x1a, 0xe7, 0xba, 0x97, 0x73, 0x5c, 0xfd, 0xa5, 0xdf, 0x03, 0x0c, 0xc5, 0x64,
+	0x82, 0x43, 0xe9, 0x0a, 0x4f, 0x37, 0x6b, 0x8e, 0x2f, 0x31, 0xe5, 0x26, 0x90, 0x24, 0xfb, 0x1c,
+	0xa5, 0xed, 0xd8, 0xd2, 0xfe, 0x06, 0x17, 0x0e, 0x46, 0x6f, 0x76, 0x6f, 0x0d, 0x4f, 0x60, 0xf3,
+	0xa3, 0x3d, 0x99, 0xa1, 0x4e, 0xdc, 0xe6, 0x11, 0x58, 0xf3, 0x4c, 0xdf, 0xf1, 0xfc, 0x04, 0xa4,
+	0x37, 0xfb, 0x9f, 0x95, 0xdd, 0x71, 0xa1, 0xaf, 0xc0, 0x98, 0xc6, 0xd9, 0xfa, 0x6c, 0xe5, 0x6b,
+	0xfb, 0x8b, 0x33, 0xb4, 0x6c, 0xcd, 0x17, 0x32, 0xb5, 0xa0, 0x4d, 0x9c, 0x7c, 0xeb, 0x82, 0xfe,
+	0x99, 0x82, 0xdd, 0x64, 0x45, 0x8f, 0xe7, 0xdc, 0xf6, 0x46, 0x48, 0x4b, 0x60, 0x84, 0xd2, 0x0e,
+	0xe4, 0xe9, 0xc2, 0x6a, 0x81, 0xe9, 0x01, 0x6c, 0xa1, 0xe7, 0xa8, 0x48, 0xe4, 0x15, 0xa3, 0x07,
+	0x27, 0x56, 0x5a, 0x9b, 0xd8, 0xf6, 0xd2, 0x0c, 0x06, 0x50, 0x68, 0xa1, 0x7c, 0x37, 0xc3, 0x60,
+	0xce, 0x31, 0x9c, 0x4d, 0xa4, 0xda, 0x82, 0x3f, 0x14, 0x8c, 0x87, 0x8f, 0xc0, 0x43, 0x73, 0x59,
+	0x19, 0x23, 0xbd, 0x36, 0x46, 0x0b, 0x76, 0xf4, 0x00, 0x8b, 0xbd, 0x29, 0x81, 0xe1, 0xdb, 0x23,
+	0xec, 0xbb, 0x9f, 0xa3, 0xcb, 0x74, 0x93, 0x2f, 0xb0, 0x8a, 0x0d, 0x84, 0xb8, 0x9e, 0xda, 0xc1,
+	0x75, 0x3c, 0xcc, 0x02, 0x97, 0x3f, 0xeb, 0x0e, 0x7c, 0xeb, 0x86, 0x52, 0x04, 0xf3, 0x13, 0x11,
+	0xa8, 0xc9, 0xdf, 0x5d, 0xf6, 0xe7, 0x00, 0xd7, 0x38, 0xb7, 0xfc, 0x00, 0xaf, 0xdc, 0x1b, 0xed,
+	0x61, 0xf0, 0xdc, 0x35, 0xce, 0x7b, 0x9a, 0xa0, 0xaf, 0x21, 0x2b, 0x7c, 0x55, 0x73, 0xa8, 0x0b,
+	0xcd, 0xd7, 0x9e, 0x25, 0xbb, 0x1c, 0x1b, 0xeb, 0x5a, 0xbb, 0x91, 0x84, 0x27, 0xda, 0xf2, 0xbf,
+	0x29, 0xd8, 0xfb, 0x82, 0x80, 0xbe, 0x80, 0xbc, 0xde, 0x20, 0x6b, 0x30, 0x11, 0xc3, 0x6b, 0x5d,
+	0x47, 0x86, 0x83, 0xa6, 0x8e, 0x15, 0x43, 0x9f, 0x41, 0x0e, 0x3d, 0x27, 0x0e, 0x6f, 0xe8, 0xb0,
+	0x81, 0x9e, 0x13, 0x05, 0x7f, 0x85, 0x48, 0x6a, 0xa9, 0x8b, 0xbf, 0x98, 0x7e, 0xf8, 0x03, 0xa1,
+	0xd5, 0x0a, 0xd3, 0xd7, 0xa0, 0x6c, 0xa2, 0xc4, 0xcc, 0x83, 0x89, 0x59, 0xf4, 0x1c, 0x9d, 0xf6,
+	0x12, 0xb6, 0x3d, 0xfc, 0x84, 0xa1, 0xb4, 0xae, 0xdc, 0x20, 0x94, 0xfa, 0x6b, 0x61, 0xf0, 0x7c,
+	0xc4, 0x9d, 0x28, 0xaa, 0x7c, 0x08, 0x05, 0x3d, 0x45, 0xdd, 0x98, 0x1d, 0xbc, 0x91, 0xb4, 0x00,
+	0x1b, 0xae, 0x13, 0xaf, 0xf1, 0x86, 0xeb, 0x94, 0x5f, 0xc2, 0xee, 0xad, 0xa2, 0x31, 0x11, 0x21,
+	0xde, 0x91, 0xfc, 0x0c, 0x64, 0xa9, 0xab, 0x8e, 0xe7, 0x12, 0x43, 0x7a, 0x08, 0xf9, 0xe0, 0x16,
+	0x6a, 0xf1, 0x36, 0x5f, 0xa6, 0xca, 0x7f, 0xa5, 0xe2, 0x5e, 0xe1, 0x18, 0xfa, 0xc2, 0x0b, 0x91,
+	0xd6, 0x20, 0x1b, 0x09, 0x94, 0x3e, 0x7d, 0x94, 0xaf, 0x15, 0x93, 0xed, 0x5a, 0xb7, 0xe7, 0x89,
+	0x90, 0x3e, 0x05, 0x63, 0x6c, 0x87, 0xd6, 0x54, 0x04, 0x18, 0xef, 0x7f, 0x76, 0x6c, 0x87, 0xe7,
+	0x22, 0x48, 0xca, 0x4c, 0x27, 0x65, 0x7e, 0xf5, 0x6c, 0x8c, 0x60, 0x7f, 0xa5, 0x96, 0x45, 0xff,
+	0xd6, 0x60, 0xff, 0x0a, 0xe5, 0x70, 0x8c, 0x8e, 0x15, 0xe0, 0x50, 0x04, 0x4e, 0x68, 0x0d, 0xc5,
+	0xcc, 0x93, 0x71, 0x33, 0xef, 0xc5, 0x41, 0x1e, 0xc5, 0x1a, 0x2a, 0xf4, 0xd5, 0xbe, 0x7e, 0x03,
+	0x3b, 0xab, 0x97, 0x57, 0x11, 0xb2, 0xaa, 0x8a, 0xdb, 0xc6, 0x4e, 0xe0, 0x97, 0x2f, 0xc8, 0xf2,
+	0x09, 0xec, 0xad, 0x5e, 0x51, 0xd1, 0x51, 0xae, 0x42, 0x16, 0x3d, 0x19, 0xb8, 0x98, 0xac, 0xdd,
+	0x3d, 0x17, 0x5a, 0xa2, 0xaa, 0x7d, 0x58, 0x7a, 0xf5, 0xf4, 0x67, 0xbe, 0x2f, 0x02, 0x49, 0x9b,
+	0x60, 0x70, 0x1c, 0xb9, 0xa1, 0xc4, 0x80, 0x16, 0xef, 0x7b, 0xf3, 0x94, 0xee, 0x8d, 0x94, 0x1f,
+	0x1d, 0xa5, 0x7e, 0x4c, 0x1d, 0x77, 0xa1, 0x2c, 0x82, 0x51, 0x65, 0x3c, 0xf7, 0x31, 0x98, 0xa0,
+	0x33, 0xc2, 0xa0, 0x72, 0x65, 0x0f, 0x02, 0x77, 0x98, 0xe4, 0xa9, 0x67, 0xda, 0xef, 0x3f, 0x8c,
+	0x5c, 0x39, 0x9e, 0x0d, 0x2a, 0x43, 0x31, 0xad, 0x2e, 0x49, 0xab, 0x91, 0x34, 0x7a, 0xae, 0x85,
+	0x55, 0x25, 0x1d, 0x44, 0x6f, 0xbf, 0x9f, 0xfe, 0x1b, 0x00, 0x28, 0x37, 0xaa, 0x93, 0x1f, 0x0a,
 	0x00, 0x00,
 }
@@ -8,6 +8,7 @@ import fmt "fmt"
 import math "math"
 import _ "github.com/golang/protobuf/ptypes/timestamp"
 import common "justledger/protos/common"
+import rwset "justledger/protos/ledger/rwset"
 
 import (
 	context "golang.org/x/net/context"
@@ -39,7 +40,7 @@ func (m *FilteredBlock) Reset()         { *m = FilteredBlock{} }
 func (m *FilteredBlock) String() string { return proto.CompactTextString(m) }
 func (*FilteredBlock) ProtoMessage()    {}
 func (*FilteredBlock) Descriptor() ([]byte, []int) {
-	return fileDescriptor_events_8af932975aef5a3c, []int{0}
+	return fileDescriptor_events_cdc0843478796df0, []int{0}
 }
 func (m *FilteredBlock) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FilteredBlock.Unmarshal(m, b)
@@ -98,7 +99,7 @@ func (m *FilteredTransaction) Reset()         { *m = FilteredTransaction{} }
 func (m *FilteredTransaction) String() string { return proto.CompactTextString(m) }
 func (*FilteredTransaction) ProtoMessage()    {}
 func (*FilteredTransaction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_events_8af932975aef5a3c, []int{1}
+	return fileDescriptor_events_cdc0843478796df0, []int{1}
 }
 func (m *FilteredTransaction) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FilteredTransaction.Unmarshal(m, b)
@@ -231,7 +232,7 @@ func (m *FilteredTransactionActions) Reset()         { *m = FilteredTransactionA
 func (m *FilteredTransactionActions) String() string { return proto.CompactTextString(m) }
 func (*FilteredTransactionActions) ProtoMessage()    {}
 func (*FilteredTransactionActions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_events_8af932975aef5a3c, []int{2}
+	return fileDescriptor_events_cdc0843478796df0, []int{2}
 }
 func (m *FilteredTransactionActions) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FilteredTransactionActions.Unmarshal(m, b)
@@ -271,7 +272,7 @@ func (m *FilteredChaincodeAction) Reset()         { *m = FilteredChaincodeAction
 func (m *FilteredChaincodeAction) String() string { return proto.CompactTextString(m) }
 func (*FilteredChaincodeAction) ProtoMessage()    {}
 func (*FilteredChaincodeAction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_events_8af932975aef5a3c, []int{3}
+	return fileDescriptor_events_cdc0843478796df0, []int{3}
 }
 func (m *FilteredChaincodeAction) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FilteredChaincodeAction.Unmarshal(m, b)
@@ -298,12 +299,136 @@ func (m *FilteredChaincodeAction) GetChaincodeEvent() *ChaincodeEvent {
 	return nil
 }
 
+// DeliverFilter restricts the content of the filtered blocks sent by the
+// DeliverFiltered service. It is carried in the extension of the channel
+// header of the DELIVER_SEEK_INFO envelope. Every criteria which is set
+// must be satisfied by a transaction for it to be delivered; blocks are
+// always delivered, possibly with no transactions, so that clients can
+// checkpoint their progress
+type DeliverFilter struct {
+	// the transaction must invoke one of the chaincodes; the chaincode
+	// events of the other chaincodes are omitted
+	ChaincodeNames []string `protobuf:"bytes,1,rep,name=chaincode_names,json=chaincodeNames" json:"chaincode_names,omitempty"`
+	// the transaction must emit a chaincode event whose name matches the
+	// regular expression; the other chaincode events are omitted
+	EventNameRegex string `protobuf:"bytes,2,opt,name=event_name_regex,json=eventNameRegex" json:"event_name_regex,omitempty"`
+	// the validation code of the transaction must be one of the codes
+	ValidationCodes []TxValidationCode `protobuf:"varint,3,rep,packed,name=validation_codes,json=validationCodes,enum=protos.TxValidationCode" json:"validation_codes,omitempty"`
+	// the transaction ID must be one of the IDs
+	TxIds                []string `protobuf:"bytes,4,rep,name=tx_ids,json=txIds" json:"tx_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeliverFilter) Reset()         { *m = DeliverFilter{} }
+func (m *DeliverFilter) String() string { return proto.CompactTextString(m) }
+func (*DeliverFilter) ProtoMessage()    {}
+func (*DeliverFilter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_events_cdc0843478796df0, []int{4}
+}
+func (m *DeliverFilter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeliverFilter.Unmarshal(m, b)
+}
+func (m *DeliverFilter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeliverFilter.Marshal(b, m, deterministic)
+}
+func (dst *DeliverFilter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeliverFilter.Merge(dst, src)
+}
+func (m *DeliverFilter) XXX_Size() int {
+	return xxx_messageInfo_DeliverFilter.Size(m)
+}
+func (m *DeliverFilter) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeliverFilter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeliverFilter proto.InternalMessageInfo
+
+func (m *DeliverFilter) GetChaincodeNames() []string {
+	if m != nil {
+		return m.ChaincodeNames
+	}
+	return nil
+}
+
+func (m *DeliverFilter) GetEventNameRegex() string {
+	if m != nil {
+		return m.EventNameRegex
+	}
+	return ""
+}
+
+func (m *DeliverFilter) GetValidationCodes() []TxValidationCode {
+	if m != nil {
+		return m.ValidationCodes
+	}
+	return nil
+}
+
+func (m *DeliverFilter) GetTxIds() []string {
+	if m != nil {
+		return m.TxIds
+	}
+	return nil
+}
+
+// BlockAndPrivateData contains a block and the private data of its
+// transactions that the requesting identity is eligible to receive
+type BlockAndPrivateData struct {
+	Block *common.Block `protobuf:"bytes,1,opt,name=block" json:"block,omitempty"`
+	// map from tx_seq_in_block to rwset.TxPvtReadWriteSet
+	PrivateDataMap       map[uint64]*rwset.TxPvtReadWriteSet `protobuf:"bytes,2,rep,name=private_data_map,json=privateDataMap" json:"private_data_map,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	XXX_NoUnkeyedLiteral struct{}                            `json:"-"`
+	XXX_unrecognized     []byte                              `json:"-"`
+	XXX_sizecache        int32                               `json:"-"`
+}
+
+func (m *BlockAndPrivateData) Reset()         { *m = BlockAndPrivateData{} }
+func (m *BlockAndPrivateData) String() string { return proto.CompactTextString(m) }
+func (*BlockAndPrivateData) ProtoMessage()    {}
+func (*BlockAndPrivateData) Descriptor() ([]byte, []int) {
+	return fileDescriptor_events_cdc0843478796df0, []int{5}
+}
+func (m *BlockAndPrivateData) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockAndPrivateData.Unmarshal(m, b)
+}
+func (m *BlockAndPrivateData) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockAndPrivateData.Marshal(b, m, deterministic)
+}
+func (dst *BlockAndPrivateData) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockAndPrivateData.Merge(dst, src)
+}
+func (m *BlockAndPrivateData) XXX_Size() int {
+	return xxx_messageInfo_BlockAndPrivateData.Size(m)
+}
+func (m *BlockAndPrivateData) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockAndPrivateData.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockAndPrivateData proto.InternalMessageInfo
+
+func (m *BlockAndPrivateData) GetBlock() *common.Block {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *BlockAndPrivateData) GetPrivateDataMap() map[uint64]*rwset.TxPvtReadWriteSet {
+	if m != nil {
+		return m.PrivateDataMap
+	}
+	return nil
+}
+
 // DeliverResponse
 type DeliverResponse struct {
 	// Types that are valid to be assigned to Type:
 	//	*DeliverResponse_Status
 	//	*DeliverResponse_Block
 	//	*DeliverResponse_FilteredBlock
+	//	*DeliverResponse_BlockAndPrivateData
 	Type                 isDeliverResponse_Type `protobuf_oneof:"Type"`
 	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
 	XXX_unrecognized     []byte                 `json:"-"`
@@ -314,7 +439,7 @@ func (m *DeliverResponse) Reset()         { *m = DeliverResponse{} }
 func (m *DeliverResponse) String() string { return proto.CompactTextString(m) }
 func (*DeliverResponse) ProtoMessage()    {}
 func (*DeliverResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_events_8af932975aef5a3c, []int{4}
+	return fileDescriptor_events_cdc0843478796df0, []int{6}
 }
 func (m *DeliverResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeliverResponse.Unmarshal(m, b)
@@ -347,10 +472,14 @@ type DeliverResponse_Block struct {
 type DeliverResponse_FilteredBlock struct {
 	FilteredBlock *FilteredBlock `protobuf:"bytes,3,opt,name=filtered_block,json=filteredBlock,oneof"`
 }
+type DeliverResponse_BlockAndPrivateData struct {
+	BlockAndPrivateData *BlockAndPrivateData `protobuf:"bytes,4,opt,name=block_and_private_data,json=blockAndPrivateData,oneof"`
+}
 
-func (*DeliverResponse_Status) isDeliverResponse_Type()        {}
-func (*DeliverResponse_Block) isDeliverResponse_Type()         {}
-func (*DeliverResponse_FilteredBlock) isDeliverResponse_Type() {}
+func (*DeliverResponse_Status) isDeliverResponse_Type()              {}
+func (*DeliverResponse_Block) isDeliverResponse_Type()               {}
+func (*DeliverResponse_FilteredBlock) isDeliverResponse_Type()       {}
+func (*DeliverResponse_BlockAndPrivateData) isDeliverResponse_Type() {}
 
 func (m *DeliverResponse) GetType() isDeliverResponse_Type {
 	if m != nil {
@@ -380,12 +509,20 @@ func (m *DeliverResponse) GetFilteredBlock() *FilteredBlock {
 	return nil
 }
 
+func (m *DeliverResponse) GetBlockAndPrivateData() *BlockAndPrivateData {
+	if x, ok := m.GetType().(*DeliverResponse_BlockAndPrivateData); ok {
+		return x.BlockAndPrivateData
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*DeliverResponse) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _DeliverResponse_OneofMarshaler, _DeliverResponse_OneofUnmarshaler, _DeliverResponse_OneofSizer, []interface{}{
 		(*DeliverResponse_Status)(nil),
 		(*DeliverResponse_Block)(nil),
 		(*DeliverResponse_FilteredBlock)(nil),
+		(*DeliverResponse_BlockAndPrivateData)(nil),
 	}
 }
 
@@ -406,6 +543,11 @@ func _DeliverResponse_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.FilteredBlock); err != nil {
 			return err
 		}
+	case *DeliverResponse_BlockAndPrivateData:
+		b.EncodeVarint(4<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.BlockAndPrivateData); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("DeliverResponse.Type has unexpected type %T", x)
@@ -439,6 +581,14 @@ func _DeliverResponse_OneofUnmarshaler(msg proto.Message, tag, wire int, b *prot
 		err := b.DecodeMessage(msg)
 		m.Type = &DeliverResponse_FilteredBlock{msg}
 		return true, err
+	case 4: // Type.block_and_private_data
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(BlockAndPrivateData)
+		err := b.DecodeMessage(msg)
+		m.Type = &DeliverResponse_BlockAndPrivateData{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -461,6 +611,11 @@ func _DeliverResponse_OneofSizer(msg proto.Message) (n int) {
 		n += 1 // tag and wire
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *DeliverResponse_BlockAndPrivateData:
+		s := proto.Size(x.BlockAndPrivateData)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -473,6 +628,9 @@ func init() {
 	proto.RegisterType((*FilteredTransaction)(nil), "protos.FilteredTransaction")
 	proto.RegisterType((*FilteredTransactionActions)(nil), "protos.FilteredTransactionActions")
 	proto.RegisterType((*FilteredChaincodeAction)(nil), "protos.FilteredChaincodeAction")
+	proto.RegisterType((*DeliverFilter)(nil), "protos.DeliverFilter")
+	proto.RegisterType((*BlockAndPrivateData)(nil), "protos.BlockAndPrivateData")
+	proto.RegisterMapType((map[uint64]*rwset.TxPvtReadWriteSet)(nil), "protos.BlockAndPrivateData.PrivateDataMapEntry")
 	proto.RegisterType((*DeliverResponse)(nil), "protos.DeliverResponse")
 }
 
@@ -495,6 +653,10 @@ type DeliverClient interface {
 	// Payload data as a marshaled orderer.SeekInfo message,
 	// then a stream of **filtered** block replies is received
 	DeliverFiltered(ctx context.Context, opts ...grpc.CallOption) (Deliver_DeliverFilteredClient, error)
+	// deliver first requires an Envelope of type ab.DELIVER_SEEK_INFO with
+	// Payload data as a marshaled orderer.SeekInfo message,
+	// then a stream of block and private data replies is received
+	DeliverWithPrivateData(ctx context.Context, opts ...grpc.CallOption) (Deliver_DeliverWithPrivateDataClient, error)
 }
 
 type deliverClient struct {
@@ -567,6 +729,37 @@ func (x *deliverDeliverFilteredClient) Recv() (*DeliverResponse, error) {
 	return m, nil
 }
 
+func (c *deliverClient) DeliverWithPrivateData(ctx context.Context, opts ...grpc.CallOption) (Deliver_DeliverWithPrivateDataClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Deliver_serviceDesc.Streams[2], c.cc, "/protos.Deliver/DeliverWithPrivateData", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &deliverDeliverWithPrivateDataClient{stream}
+	return x, nil
+}
+
+type Deliver_DeliverWithPrivateDataClient interface {
+	Send(*common.Envelope) error
+	Recv() (*DeliverResponse, error)
+	grpc.ClientStream
+}
+
+type deliverDeliverWithPrivateDataClient struct {
+	grpc.ClientStream
+}
+
+func (x *deliverDeliverWithPrivateDataClient) Send(m *common.Envelope) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *deliverDeliverWithPrivateDataClient) Recv() (*DeliverResponse, error) {
+	m := new(DeliverResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for Deliver service
 
 type DeliverServer interface {
@@ -578,6 +771,10 @@ type DeliverServer interface {
 	// Payload data as a marshaled orderer.SeekInfo message,
 	// then a stream of **filtered** block replies is received
 	DeliverFiltered(Deliver_DeliverFilteredServer) error
+	// deliver first requires an Envelope of type ab.DELIVER_SEEK_INFO with
+	// Payload data as a marshaled orderer.SeekInfo message,
+	// then a stream of block and private data replies is received
+	DeliverWithPrivateData(Deliver_DeliverWithPrivateDataServer) error
 }
 
 func RegisterDeliverServer(s *grpc.Server, srv DeliverServer) {
@@ -636,6 +833,32 @@ func (x *deliverDeliverFilteredServer) Recv() (*common.Envelope, error) {
 	return m, nil
 }
 
+func _Deliver_DeliverWithPrivateData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DeliverServer).DeliverWithPrivateData(&deliverDeliverWithPrivateDataServer{stream})
+}
+
+type Deliver_DeliverWithPrivateDataServer interface {
+	Send(*DeliverResponse) error
+	Recv() (*common.Envelope, error)
+	grpc.ServerStream
+}
+
+type deliverDeliverWithPrivateDataServer struct {
+	grpc.ServerStream
+}
+
+func (x *deliverDeliverWithPrivateDataServer) Send(m *DeliverResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *deliverDeliverWithPrivateDataServer) Recv() (*common.Envelope, error) {
+	m := new(common.Envelope)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _Deliver_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "protos.Deliver",
 	HandlerType: (*DeliverServer)(nil),
@@ -653,47 +876,68 @@ var _Deliver_serviceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "DeliverWithPrivateData",
+			Handler:       _Deliver_DeliverWithPrivateData_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "peer/events.proto",
 }
 
-func init() { proto.RegisterFile("peer/events.proto", fileDescriptor_events_8af932975aef5a3c) }
-
-var fileDescriptor_events_8af932975aef5a3c = []byte{
-	// 560 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0xcd, 0x6e, 0xd3, 0x40,
-	0x10, 0x8e, 0x69, 0x08, 0xea, 0x44, 0x49, 0xdb, 0x2d, 0x4d, 0xa3, 0x20, 0xd4, 0xc8, 0x12, 0xc8,
-	0x5c, 0x62, 0x64, 0x6e, 0x1c, 0x40, 0xa4, 0x3f, 0x0a, 0x12, 0x87, 0x6a, 0x09, 0x1c, 0x7a, 0xc0,
-	0x5a, 0xdb, 0x13, 0xc7, 0xd4, 0xf1, 0x5a, 0xbb, 0x9b, 0x28, 0x79, 0x04, 0xde, 0x80, 0x67, 0xe0,
-	0x09, 0x39, 0x22, 0xaf, 0xbd, 0x49, 0x9a, 0x52, 0x24, 0x4e, 0xf6, 0xce, 0x7c, 0x3f, 0x33, 0xb3,
-	0x63, 0xc3, 0x51, 0x8e, 0x28, 0x5c, 0x5c, 0x60, 0xa6, 0xe4, 0x20, 0x17, 0x5c, 0x71, 0xd2, 0xd0,
-	0x0f, 0xd9, 0x3b, 0x0e, 0xf9, 0x6c, 0xc6, 0x33, 0xb7, 0x7c, 0x94, 0xc9, 0xde, 0x59, 0xcc, 0x79,
-	0x9c, 0xa2, 0xab, 0x4f, 0xc1, 0x7c, 0xe2, 0xaa, 0x64, 0x86, 0x52, 0xb1, 0x59, 0x5e, 0x01, 0x7a,
-	0x5a, 0x30, 0x9c, 0xb2, 0x24, 0x0b, 0x79, 0x84, 0xbe, 0x96, 0xae, 0x72, 0x1d, 0x9d, 0x53, 0x82,
-	0x65, 0x92, 0x85, 0x2a, 0x31, 0xa2, 0xf6, 0x4f, 0x0b, 0x5a, 0x57, 0x49, 0xaa, 0x50, 0x60, 0x34,
-	0x4c, 0x79, 0x78, 0x4b, 0x9e, 0x03, 0x84, 0x53, 0x96, 0x65, 0x98, 0xfa, 0x49, 0xd4, 0xb5, 0xfa,
-	0x96, 0xb3, 0x4f, 0xf7, 0xab, 0xc8, 0xc7, 0x88, 0x74, 0xa0, 0x91, 0xcd, 0x67, 0x01, 0x8a, 0xee,
-	0xa3, 0xbe, 0xe5, 0xd4, 0x69, 0x75, 0x22, 0xd7, 0x70, 0x32, 0xa9, 0x74, 0xfc, 0x2d, 0x1b, 0xd9,
-	0xad, 0xf7, 0xf7, 0x9c, 0xa6, 0xf7, 0xac, 0xf4, 0x93, 0x03, 0x63, 0x36, 0xde, 0x60, 0xe8, 0xd3,
-	0xc9, 0xfd, 0xa0, 0xb4, 0x7f, 0x5b, 0x70, 0xfc, 0x17, 0x34, 0x21, 0x50, 0x57, 0xcb, 0x75, 0x69,
-	0xfa, 0x9d, 0xbc, 0x84, 0xba, 0x5a, 0xe5, 0xa8, 0x6b, 0x6a, 0x7b, 0x64, 0x50, 0x0d, 0x6e, 0x84,
-	0x2c, 0x42, 0x31, 0x5e, 0xe5, 0x48, 0x75, 0x9e, 0x5c, 0x01, 0x51, 0x4b, 0x7f, 0xc1, 0xd2, 0x24,
-	0x62, 0x85, 0x98, 0x5f, 0x0c, 0xaa, 0xbb, 0xa7, 0x59, 0x5d, 0x53, 0xe2, 0x78, 0xf9, 0x75, 0x0d,
-	0x38, 0xe7, 0x11, 0xd2, 0x43, 0xb5, 0x13, 0x21, 0x5f, 0xe0, 0x78, 0xab, 0x49, 0x7f, 0xd3, 0xab,
-	0xe5, 0x34, 0x3d, 0xfb, 0x1f, 0xbd, 0x7e, 0x28, 0x91, 0xa3, 0x1a, 0x25, 0xea, 0x5e, 0x74, 0xd8,
-	0x80, 0xfa, 0x05, 0x53, 0xcc, 0xfe, 0x0e, 0xbd, 0x87, 0xb9, 0xe4, 0x13, 0x1c, 0x6d, 0x2e, 0xd9,
-	0x58, 0x5b, 0x7a, 0xcc, 0x67, 0xbb, 0xd6, 0xe7, 0x06, 0x58, 0x92, 0xe9, 0x61, 0x78, 0x37, 0x20,
-	0xed, 0x1b, 0x38, 0x7d, 0x00, 0x4c, 0xde, 0xc3, 0xc1, 0xce, 0x36, 0xe9, 0xa1, 0x37, 0xbd, 0x8e,
-	0xb1, 0x59, 0x33, 0x2e, 0x8b, 0x2c, 0x6d, 0x87, 0x77, 0xce, 0xf6, 0x2f, 0x0b, 0x0e, 0x2e, 0x30,
-	0x4d, 0x16, 0x28, 0x28, 0xca, 0x9c, 0x67, 0x12, 0x89, 0x03, 0x0d, 0xa9, 0x98, 0x9a, 0x4b, 0xad,
-	0xd5, 0xf6, 0xda, 0xe6, 0xb2, 0x3e, 0xeb, 0xe8, 0xa8, 0x46, 0xab, 0x3c, 0x79, 0x01, 0x8f, 0x83,
-	0x62, 0x25, 0xf5, 0xad, 0x36, 0xbd, 0x96, 0x01, 0xea, 0x3d, 0x1d, 0xd5, 0x68, 0x99, 0x25, 0xef,
-	0xa0, 0xbd, 0xde, 0xbc, 0x12, 0xbf, 0xa7, 0xf1, 0x27, 0xbb, 0xb3, 0x30, 0xbc, 0xd6, 0x64, 0x3b,
-	0x50, 0x0c, 0xbd, 0xd8, 0x10, 0xef, 0x87, 0x05, 0x4f, 0xaa, 0x62, 0xc9, 0xdb, 0xcd, 0xeb, 0xa1,
-	0xb1, 0xbd, 0xcc, 0x16, 0x98, 0xf2, 0x1c, 0x7b, 0xa7, 0x46, 0x78, 0xa7, 0x35, 0xbb, 0xe6, 0x58,
-	0xaf, 0x2d, 0x32, 0x5c, 0xf7, 0x6c, 0x8c, 0xff, 0x5b, 0x63, 0xf8, 0x0d, 0x6c, 0x2e, 0xe2, 0xc1,
-	0x74, 0x95, 0xa3, 0x48, 0x31, 0x8a, 0x51, 0x0c, 0x26, 0x2c, 0x10, 0x49, 0x68, 0x68, 0xc5, 0xe7,
-	0x3c, 0x6c, 0xe9, 0x29, 0xcb, 0x6b, 0x16, 0xde, 0xb2, 0x18, 0x6f, 0x5e, 0xc5, 0x89, 0x9a, 0xce,
-	0x83, 0xc2, 0xcb, 0xdd, 0x62, 0xba, 0x25, 0xb3, 0xfc, 0x6f, 0x48, 0xb7, 0x60, 0x06, 0xe5, 0x8f,
-	0xe6, 0xcd, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xd3, 0x19, 0xd1, 0xa9, 0x84, 0x04, 0x00, 0x00,
+func init() { proto.RegisterFile("peer/events.proto", fileDescriptor_events_cdc0843478796df0) }
+
+var fileDescriptor_events_cdc0843478796df0 = []byte{
+	// 795 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0xd1, 0x8e, 0xe3, 0x34,
+	0x14, 0x6d, 0xa6, 0x9d, 0xa2, 0xde, 0x51, 0x3b, 0x5d, 0x97, 0xe9, 0x46, 0x5d, 0xa1, 0x1d, 0x05,
+	0x01, 0xe5, 0x25, 0x41, 0xe5, 0x05, 0xed, 0x03, 0x68, 0x3b, 0x3b, 0xab, 0x8e, 0x04, 0x68, 0xe4,
+	0x1d, 0x58, 0xb1, 0x48, 0x44, 0x6e, 0x72, 0xdb, 0x86, 0x49, 0x93, 0xc8, 0x76, 0x43, 0xfb, 0x27,
+	0x7c, 0x0d, 0x5f, 0xc1, 0x97, 0xf0, 0xc4, 0x13, 0x42, 0xb1, 0xe3, 0x36, 0xed, 0x74, 0x47, 0xda,
+	0x97, 0xd6, 0x3e, 0xf7, 0xdc, 0x7b, 0xec, 0x73, 0xed, 0x18, 0x9e, 0x64, 0x88, 0xdc, 0xc3, 0x1c,
+	0x13, 0x29, 0xdc, 0x8c, 0xa7, 0x32, 0x25, 0x4d, 0xf5, 0x27, 0x06, 0xbd, 0x20, 0x5d, 0x2e, 0xd3,
+	0xc4, 0xd3, 0x7f, 0x3a, 0x38, 0x78, 0x3e, 0x4f, 0xd3, 0x79, 0x8c, 0x9e, 0x9a, 0x4d, 0x57, 0x33,
+	0x4f, 0x46, 0x4b, 0x14, 0x92, 0x2d, 0xb3, 0x92, 0x60, 0xc7, 0x18, 0xce, 0x91, 0x7b, 0xfc, 0x0f,
+	0x81, 0x52, 0xff, 0x96, 0x91, 0x81, 0x92, 0x0a, 0x16, 0x2c, 0x4a, 0x82, 0x34, 0x44, 0x5f, 0x89,
+	0x96, 0xb1, 0xbe, 0x8a, 0x49, 0xce, 0x12, 0xc1, 0x02, 0x19, 0x19, 0x39, 0xe7, 0x4f, 0x0b, 0xda,
+	0xaf, 0xa3, 0x58, 0x22, 0xc7, 0x70, 0x1c, 0xa7, 0xc1, 0x3d, 0xf9, 0x04, 0x20, 0x58, 0xb0, 0x24,
+	0xc1, 0xd8, 0x8f, 0x42, 0xdb, 0xba, 0xb4, 0x86, 0x2d, 0xda, 0x2a, 0x91, 0x9b, 0x90, 0xf4, 0xa1,
+	0x99, 0xac, 0x96, 0x53, 0xe4, 0xf6, 0xc9, 0xa5, 0x35, 0x6c, 0xd0, 0x72, 0x46, 0x6e, 0xe1, 0x62,
+	0x56, 0xd6, 0xf1, 0x2b, 0x32, 0xc2, 0x6e, 0x5c, 0xd6, 0x87, 0x67, 0xa3, 0x67, 0x5a, 0x4f, 0xb8,
+	0x46, 0xec, 0x6e, 0xc7, 0xa1, 0x1f, 0xcf, 0x1e, 0x82, 0xc2, 0xf9, 0xd7, 0x82, 0xde, 0x11, 0x36,
+	0x21, 0xd0, 0x90, 0xeb, 0xed, 0xd2, 0xd4, 0x98, 0x7c, 0x0e, 0x0d, 0xb9, 0xc9, 0x50, 0xad, 0xa9,
+	0x33, 0x22, 0x6e, 0x69, 0xe9, 0x04, 0x59, 0x88, 0xfc, 0x6e, 0x93, 0x21, 0x55, 0x71, 0xf2, 0x1a,
+	0x88, 0x5c, 0xfb, 0x39, 0x8b, 0xa3, 0x90, 0x15, 0xc5, 0xfc, 0xc2, 0x28, 0xbb, 0xae, 0xb2, 0x6c,
+	0xb3, 0xc4, 0xbb, 0xf5, 0xcf, 0x5b, 0xc2, 0x55, 0x1a, 0x22, 0xed, 0xca, 0x03, 0x84, 0xfc, 0x04,
+	0xbd, 0xca, 0x26, 0xfd, 0xdd, 0x5e, 0xad, 0xe1, 0xd9, 0xc8, 0x79, 0x64, 0xaf, 0x2f, 0x35, 0x73,
+	0x52, 0xa3, 0x44, 0x3e, 0x40, 0xc7, 0x4d, 0x68, 0xbc, 0x62, 0x92, 0x39, 0xbf, 0xc3, 0xe0, 0xfd,
+	0xb9, 0xe4, 0x7b, 0x78, 0xb2, 0x6b, 0xb2, 0x91, 0xb6, 0x94, 0xcd, 0xcf, 0x0f, 0xa5, 0xaf, 0x0c,
+	0x51, 0x27, 0xd3, 0x6e, 0xb0, 0x0f, 0x08, 0xe7, 0x1d, 0x3c, 0x7d, 0x0f, 0x99, 0x7c, 0x07, 0xe7,
+	0x07, 0xa7, 0x49, 0x99, 0x7e, 0x36, 0xea, 0x1b, 0x99, 0x6d, 0xc6, 0x75, 0x11, 0xa5, 0x9d, 0x60,
+	0x6f, 0xee, 0xfc, 0x65, 0x41, 0xfb, 0x15, 0xc6, 0x51, 0x8e, 0x5c, 0x6b, 0x90, 0x2f, 0xaa, 0x25,
+	0x13, 0xb6, 0x44, 0xbd, 0xf2, 0x56, 0x25, 0xf5, 0xc7, 0x02, 0x25, 0x43, 0xe8, 0x2a, 0x45, 0x45,
+	0xf2, 0x39, 0xce, 0x71, 0xad, 0xba, 0xdb, 0xa2, 0x1d, 0x85, 0x17, 0x2c, 0x5a, 0xa0, 0xe4, 0x0a,
+	0xba, 0x07, 0x0d, 0x15, 0x76, 0xfd, 0xb2, 0xfe, 0x68, 0x47, 0xcf, 0xf3, 0xbd, 0xb9, 0x20, 0x17,
+	0xd0, 0x94, 0x6b, 0x3f, 0x0a, 0xf5, 0x79, 0x6d, 0xd1, 0x53, 0xb9, 0xbe, 0x09, 0x85, 0xf3, 0x8f,
+	0x05, 0x3d, 0x75, 0x2d, 0x5e, 0x26, 0xe1, 0x2d, 0x8f, 0x72, 0x26, 0xb1, 0x68, 0x10, 0xf9, 0x14,
+	0x4e, 0xa7, 0x05, 0x5c, 0xfa, 0xd1, 0x36, 0x07, 0x4e, 0x71, 0xa9, 0x8e, 0x91, 0x5f, 0xa0, 0x9b,
+	0xe9, 0x1c, 0x3f, 0x64, 0x92, 0xf9, 0x4b, 0x96, 0xd9, 0x27, 0xaa, 0x4d, 0x9e, 0x59, 0xd8, 0x91,
+	0xda, 0x6e, 0x65, 0xfc, 0x03, 0xcb, 0xae, 0x13, 0xc9, 0x37, 0xb4, 0x93, 0xed, 0x81, 0x83, 0x5f,
+	0xa1, 0x77, 0x84, 0x46, 0xba, 0x50, 0xbf, 0xc7, 0x8d, 0x5a, 0x54, 0x83, 0x16, 0x43, 0xe2, 0xc2,
+	0x69, 0xce, 0xe2, 0x95, 0xbe, 0x19, 0x67, 0x23, 0xdb, 0xd5, 0x1f, 0x8c, 0xbb, 0xf5, 0x6d, 0x2e,
+	0x29, 0xb2, 0xf0, 0x2d, 0x8f, 0x24, 0xbe, 0x41, 0x49, 0x35, 0xed, 0xc5, 0xc9, 0x37, 0x96, 0xf3,
+	0x9f, 0x05, 0xe7, 0x65, 0xd7, 0x28, 0x8a, 0x2c, 0x4d, 0x04, 0x92, 0x21, 0x34, 0x85, 0x64, 0x72,
+	0x25, 0x54, 0xf1, 0xce, 0xa8, 0x63, 0x76, 0xfc, 0x46, 0xa1, 0x93, 0x1a, 0x2d, 0xe3, 0xe4, 0x33,
+	0x63, 0xcd, 0xc9, 0x11, 0x6b, 0x26, 0x35, 0x63, 0xce, 0xb7, 0xd0, 0xd9, 0x7e, 0x2f, 0x34, 0xbf,
+	0xae, 0xf8, 0x17, 0x87, 0x27, 0xd8, 0xe4, 0xb5, 0x67, 0x55, 0x80, 0x50, 0xe8, 0xab, 0x34, 0x9f,
+	0x25, 0xa1, 0x5f, 0xb5, 0xb9, 0xbc, 0x84, 0xcf, 0x1e, 0xb1, 0x78, 0x52, 0xa3, 0xbd, 0xe9, 0x43,
+	0xb8, 0xb8, 0x7e, 0xc5, 0xb7, 0x62, 0xf4, 0xb7, 0x05, 0x1f, 0x95, 0x06, 0x90, 0x17, 0xbb, 0x61,
+	0xd7, 0x6c, 0xe5, 0x3a, 0xc9, 0x31, 0x4e, 0x33, 0x1c, 0x3c, 0x35, 0x22, 0x07, 0x76, 0x39, 0xb5,
+	0xa1, 0xf5, 0x95, 0x45, 0xc6, 0x5b, 0x1f, 0xcd, 0x66, 0x3e, 0xbc, 0xc6, 0x0d, 0xf4, 0xcb, 0xc0,
+	0xdb, 0x48, 0x2e, 0xaa, 0x67, 0xf0, 0x43, 0x4b, 0x8d, 0x7f, 0x03, 0x27, 0xe5, 0x73, 0x77, 0xb1,
+	0xc9, 0x90, 0xeb, 0x47, 0xc4, 0x9d, 0xb1, 0x29, 0x8f, 0x02, 0x93, 0x56, 0xbc, 0x11, 0xe3, 0xb6,
+	0xba, 0xba, 0xe2, 0x96, 0x05, 0xf7, 0x6c, 0x8e, 0xef, 0xbe, 0x9c, 0x47, 0x72, 0xb1, 0x9a, 0x16,
+	0x5a, 0x5e, 0x25, 0xd3, 0xd3, 0x99, 0xfa, 0x99, 0x12, 0x5e, 0x91, 0x39, 0xd5, 0xef, 0xda, 0xd7,
+	0xff, 0x0f, 0x00, 0x89, 0x10, 0xaf, 0x4b, 0xf3, 0x06, 0x00, 0x00,
 }
@@ -31,7 +31,13 @@ type TokenToIssue struct {
 	// Type refers to the token type
 	Type string `protobuf:"bytes,2,opt,name=type" json:"type,omitempty"`
 	// Quantity refers to the number of token units to be issued
-	Quantity             uint64   `protobuf:"varint,3,opt,name=quantity" json:"quantity,omitempty"`
+	Quantity uint64 `protobuf:"varint,3,opt,name=quantity" json:"quantity,omitempty"`
+	// RecipientChainCode is the chain code of the recipient's hierarchical deterministic public key.
+	// It is only used if DerivationPath is set.
+	RecipientChainCode []byte `protobuf:"bytes,4,opt,name=recipient_chain_code,json=recipientChainCode,proto3" json:"recipient_chain_code,omitempty"`
+	// DerivationPath, if set, requests that the token is issued to the owner key derived along
+	// this path from the hierarchical deterministic public key in Recipient and RecipientChainCode
+	DerivationPath       []uint32 `protobuf:"varint,5,rep,packed,name=derivation_path,json=derivationPath" json:"derivation_path,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -41,7 +47,7 @@ func (m *TokenToIssue) Reset()         { *m = TokenToIssue{} }
 func (m *TokenToIssue) String() string { return proto.CompactTextString(m) }
 func (*TokenToIssue) ProtoMessage()    {}
 func (*TokenToIssue) Descriptor() ([]byte, []int) {
-	return fileDescriptor_prover_886d64b71f8f88fa, []int{0}
+	return fileDescriptor_prover_12c429b1fb0a6736, []int{0}
 }
 func (m *TokenToIssue) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TokenToIssue.Unmarshal(m, b)
@@ -82,6 +88,20 @@ func (m *TokenToIssue) GetQuantity() uint64 {
 	return 0
 }
 
+func (m *TokenToIssue) GetRecipientChainCode() []byte {
+	if m != nil {
+		return m.RecipientChainCode
+	}
+	return nil
+}
+
+func (m *TokenToIssue) GetDerivationPath() []uint32 {
+	if m != nil {
+		return m.DerivationPath
+	}
+	return nil
+}
+
 // ImportRequest is used to request creation of imports
 type ImportRequest struct {
 	// Credential contains information about the party who is requesting the operation
@@ -98,7 +118,7 @@ func (m *ImportRequest) Reset()         { *m = ImportRequest{} }
 func (m *ImportRequest) String() string { return proto.CompactTextString(m) }
 func (*ImportRequest) ProtoMessage()    {}
 func (*ImportRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_prover_886d64b71f8f88fa, []int{1}
+	return fileDescriptor_prover_12c429b1fb0a6736, []int{1}
 }
 func (m *ImportRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ImportRequest.Unmarshal(m, b)
@@ -154,7 +174,7 @@ func (m *Header) Reset()         { *m = Header{} }
 func (m *Header) String() string { return proto.CompactTextString(m) }
 func (*Header) ProtoMessage()    {}
 func (*Header) Descriptor() ([]byte, []int) {
-	return fileDescriptor_prover_886d64b71f8f88fa, []int{2}
+	return fileDescriptor_prover_12c429b1fb0a6736, []int{2}
 }
 func (m *Header) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Header.Unmarshal(m, b)
@@ -220,7 +240,7 @@ func (m *Command) Reset()         { *m = Command{} }
 func (m *Command) String() string { return proto.CompactTextString(m) }
 func (*Command) ProtoMessage()    {}
 func (*Command) Descriptor() ([]byte, []int) {
-	return fileDescriptor_prover_886d64b71f8f88fa, []int{3}
+	return fileDescriptor_prover_12c429b1fb0a6736, []int{3}
 }
 func (m *Command) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Command.Unmarshal(m, b)
@@ -341,7 +361,7 @@ func (m *SignedCommand) Reset()         { *m = SignedCommand{} }
 func (m *SignedCommand) String() string { return proto.CompactTextString(m) }
 func (*SignedCommand) ProtoMessage()    {}
 func (*SignedCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_prover_886d64b71f8f88fa, []int{4}
+	return fileDescriptor_prover_12c429b1fb0a6736, []int{4}
 }
 func (m *SignedCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SignedCommand.Unmarshal(m, b)
@@ -395,7 +415,7 @@ func (m *CommandResponseHeader) Reset()         { *m = CommandResponseHeader{} }
 func (m *CommandResponseHeader) String() string { return proto.CompactTextString(m) }
 func (*CommandResponseHeader) ProtoMessage()    {}
 func (*CommandResponseHeader) Descriptor() ([]byte, []int) {
-	return fileDescriptor_prover_886d64b71f8f88fa, []int{5}
+	return fileDescriptor_prover_12c429b1fb0a6736, []int{5}
 }
 func (m *CommandResponseHeader) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CommandResponseHeader.Unmarshal(m, b)
@@ -451,7 +471,7 @@ func (m *Error) Reset()         { *m = Error{} }
 func (m *Error) String() string { return proto.CompactTextString(m) }
 func (*Error) ProtoMessage()    {}
 func (*Error) Descriptor() ([]byte, []int) {
-	return fileDescriptor_prover_886d64b71f8f88fa, []int{6}
+	return fileDescriptor_prover_12c429b1fb0a6736, []int{6}
 }
 func (m *Error) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Error.Unmarshal(m, b)
@@ -504,7 +524,7 @@ func (m *CommandResponse) Reset()         { *m = CommandResponse{} }
 func (m *CommandResponse) String() string { return proto.CompactTextString(m) }
 func (*CommandResponse) ProtoMessage()    {}
 func (*CommandResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_prover_886d64b71f8f88fa, []int{7}
+	return fileDescriptor_prover_12c429b1fb0a6736, []int{7}
 }
 func (m *CommandResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CommandResponse.Unmarshal(m, b)
@@ -655,7 +675,7 @@ func (m *SignedCommandResponse) Reset()         { *m = SignedCommandResponse{} }
 func (m *SignedCommandResponse) String() string { return proto.CompactTextString(m) }
 func (*SignedCommandResponse) ProtoMessage()    {}
 func (*SignedCommandResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_prover_886d64b71f8f88fa, []int{8}
+	return fileDescriptor_prover_12c429b1fb0a6736, []int{8}
 }
 func (m *SignedCommandResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SignedCommandResponse.Unmarshal(m, b)
@@ -781,47 +801,50 @@ var _Prover_serviceDesc = grpc.ServiceDesc{
 	Metadata: "token/prover.proto",
 }
 
-func init() { proto.RegisterFile("token/prover.proto", fileDescriptor_prover_886d64b71f8f88fa) }
-
-var fileDescriptor_prover_886d64b71f8f88fa = []byte{
-	// 613 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0x51, 0x6b, 0xdb, 0x30,
-	0x10, 0x4e, 0x9a, 0x36, 0x6d, 0x2e, 0x49, 0xbb, 0x8a, 0x96, 0x85, 0xb0, 0x6e, 0xa9, 0x07, 0x23,
-	0xec, 0xc1, 0x81, 0x8c, 0xc1, 0x60, 0x63, 0x8c, 0x8e, 0xb1, 0xf4, 0xad, 0xd5, 0xfa, 0x34, 0x06,
-	0x41, 0xb1, 0xaf, 0xb6, 0x58, 0x2c, 0xb9, 0x92, 0x32, 0x08, 0xec, 0x37, 0x0c, 0xf6, 0x6f, 0xf6,
-	0xf3, 0x86, 0x25, 0xd9, 0x71, 0x4a, 0xd9, 0xcb, 0x9e, 0xac, 0xbb, 0xfb, 0xa4, 0xfb, 0xee, 0xfb,
-	0x64, 0x01, 0x31, 0xf2, 0x3b, 0x8a, 0x49, 0xae, 0xe4, 0x0f, 0x54, 0x61, 0xae, 0xa4, 0x91, 0xa4,
-	0x6d, 0x3f, 0x7a, 0xf8, 0x2c, 0x91, 0x32, 0x59, 0xe2, 0xc4, 0x86, 0x8b, 0xd5, 0xed, 0xc4, 0xf0,
-	0x0c, 0xb5, 0x61, 0x59, 0xee, 0x80, 0xc3, 0xc7, 0x6e, 0xb3, 0x51, 0x4c, 0x68, 0x16, 0x19, 0x2e,
-	0x85, 0x2b, 0x04, 0xdf, 0xa0, 0x77, 0x53, 0x94, 0x6e, 0xe4, 0xa5, 0xd6, 0x2b, 0x24, 0x4f, 0xa0,
-	0xa3, 0x30, 0xe2, 0x39, 0x47, 0x61, 0x06, 0xcd, 0x51, 0x73, 0xdc, 0xa3, 0x9b, 0x04, 0x21, 0xb0,
-	0x6b, 0xd6, 0x39, 0x0e, 0x76, 0x46, 0xcd, 0x71, 0x87, 0xda, 0x35, 0x19, 0xc2, 0xc1, 0xdd, 0x8a,
-	0x09, 0xc3, 0xcd, 0x7a, 0xd0, 0x1a, 0x35, 0xc7, 0xbb, 0xb4, 0x8a, 0x83, 0x0c, 0xfa, 0x97, 0x59,
-	0x2e, 0x95, 0xa1, 0x78, 0xb7, 0x42, 0x6d, 0xc8, 0x53, 0x80, 0x48, 0x61, 0x8c, 0xc2, 0x70, 0xb6,
-	0xf4, 0xe7, 0xd7, 0x32, 0xe4, 0x1d, 0x1c, 0x59, 0xa6, 0x7a, 0x6e, 0xe4, 0x9c, 0x17, 0x8c, 0x06,
-	0x3b, 0xa3, 0xd6, 0xb8, 0x3b, 0x3d, 0x71, 0x7c, 0x75, 0x58, 0x67, 0x4b, 0xfb, 0x0e, 0xec, 0xc3,
-	0xe0, 0x77, 0x13, 0xda, 0x33, 0x64, 0x31, 0x2a, 0xf2, 0x06, 0x3a, 0x95, 0x06, 0xb6, 0x4f, 0x77,
-	0x3a, 0x0c, 0x9d, 0x4a, 0x61, 0xa9, 0x52, 0x78, 0x53, 0x22, 0xe8, 0x06, 0x4c, 0xce, 0x00, 0xa2,
-	0x94, 0x09, 0x81, 0xcb, 0x39, 0x8f, 0xfd, 0xa4, 0x1d, 0x9f, 0xb9, 0x8c, 0xc9, 0x09, 0xec, 0x09,
-	0x29, 0x22, 0xb4, 0xb3, 0xf6, 0xa8, 0x0b, 0xc8, 0x00, 0xf6, 0x23, 0x85, 0xcc, 0x48, 0x35, 0xd8,
-	0xb5, 0xf9, 0x32, 0x0c, 0x7e, 0xc2, 0xfe, 0x47, 0x99, 0x65, 0x4c, 0xc4, 0xe4, 0x05, 0xb4, 0x53,
-	0xcb, 0xce, 0x13, 0x3a, 0x2c, 0x67, 0x72, 0x9c, 0xa9, 0xaf, 0x92, 0xf7, 0x70, 0xc8, 0xad, 0x6a,
-	0x73, 0xe5, 0x64, 0xb3, 0x2c, 0xba, 0xd3, 0xd3, 0x12, 0xbf, 0xa5, 0xe9, 0xac, 0x41, 0xfb, 0xbc,
-	0x9e, 0xb8, 0xe8, 0xc0, 0x7e, 0xce, 0xd6, 0x4b, 0xc9, 0xe2, 0xe0, 0x33, 0xf4, 0xbf, 0xf0, 0x44,
-	0x60, 0x5c, 0x72, 0x28, 0x88, 0xba, 0xa5, 0x57, 0xbf, 0x0c, 0x0b, 0xe7, 0x35, 0x4f, 0x04, 0x33,
-	0x2b, 0xe5, 0x0c, 0xee, 0xd1, 0x4d, 0x22, 0xf8, 0xd5, 0x84, 0x53, 0x7f, 0x06, 0x45, 0x9d, 0x4b,
-	0xa1, 0xf1, 0xbf, 0x95, 0x3e, 0x87, 0x9e, 0x6f, 0x3e, 0x4f, 0x99, 0x4e, 0x7d, 0xd3, 0xae, 0xcf,
-	0xcd, 0x98, 0x4e, 0xeb, 0xba, 0xb6, 0xb6, 0x75, 0x7d, 0x0b, 0x7b, 0x9f, 0x94, 0x92, 0xaa, 0x80,
-	0x64, 0xa8, 0x35, 0x4b, 0xd0, 0x76, 0xef, 0xd0, 0x32, 0x2c, 0x2a, 0x5e, 0x07, 0x7f, 0x74, 0x25,
-	0xcb, 0x9f, 0x26, 0x1c, 0xdd, 0x9b, 0x86, 0xbc, 0xbe, 0xe7, 0xce, 0x59, 0xa9, 0xf6, 0x83, 0x63,
-	0x57, 0x66, 0x9d, 0x43, 0x0b, 0x95, 0xf2, 0x0e, 0xf5, 0xcb, 0x3d, 0x96, 0xda, 0xac, 0x41, 0x8b,
-	0x1a, 0xf9, 0x00, 0xc7, 0xf6, 0x9e, 0xce, 0x6b, 0xbf, 0x9f, 0x1d, 0xa7, 0x3b, 0x3d, 0xf6, 0xf7,
-	0x79, 0x53, 0x98, 0x35, 0xe8, 0x23, 0x73, 0x2f, 0x57, 0x77, 0xf4, 0x1a, 0x4e, 0xb7, 0x1c, 0xad,
-	0xf8, 0x0f, 0xe1, 0x40, 0xf9, 0xb5, 0xb7, 0xb6, 0x8a, 0xff, 0xed, 0xed, 0x94, 0x42, 0xfb, 0xca,
-	0xbe, 0x2a, 0x64, 0x06, 0x87, 0x57, 0x4a, 0x46, 0xa8, 0x75, 0x79, 0x5f, 0xaa, 0x3b, 0xb7, 0xd5,
-	0x74, 0x78, 0xf6, 0x60, 0xba, 0xe4, 0x12, 0x34, 0x2e, 0xae, 0xe1, 0xb9, 0x54, 0x49, 0x98, 0xae,
-	0x73, 0x54, 0x4b, 0x8c, 0x13, 0x54, 0xe1, 0x2d, 0x5b, 0x28, 0x1e, 0x95, 0x1b, 0xed, 0x8c, 0x5f,
-	0x5f, 0x26, 0xdc, 0xa4, 0xab, 0x45, 0x18, 0xc9, 0x6c, 0x52, 0xc3, 0x4e, 0x1c, 0xd6, 0xbd, 0x67,
-	0x7a, 0x62, 0xb1, 0x0b, 0xf7, 0xd8, 0xbd, 0xfa, 0x1b, 0x00, 0x00, 0xff, 0xff, 0xeb, 0xc4, 0xe5,
-	0x53, 0x09, 0x05, 0x00, 0x00,
+func init() { proto.RegisterFile("token/prover.proto", fileDescriptor_prover_12c429b1fb0a6736) }
+
+var fileDescriptor_prover_12c429b1fb0a6736 = []byte{
+	// 667 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0x5d, 0x6b, 0xdb, 0x4a,
+	0x10, 0xb5, 0xe2, 0xc4, 0x89, 0xc7, 0x1f, 0x49, 0x96, 0x84, 0x2b, 0xcc, 0xcd, 0xbd, 0x8e, 0x2f,
+	0x5c, 0x4c, 0x0b, 0x72, 0x71, 0x29, 0x14, 0x5a, 0x4a, 0x49, 0x28, 0x75, 0xde, 0x92, 0x6d, 0x9e,
+	0xfa, 0x22, 0xd6, 0xd2, 0x44, 0x5a, 0x6a, 0xed, 0x2a, 0xbb, 0xeb, 0x80, 0xa1, 0xbf, 0xa1, 0xd0,
+	0x7f, 0x53, 0xe8, 0x9f, 0x2b, 0x5a, 0xad, 0x64, 0x3b, 0x84, 0xbe, 0xf4, 0xc9, 0x9a, 0x99, 0xb3,
+	0x3b, 0x67, 0xce, 0x19, 0x2f, 0x10, 0x23, 0xbf, 0xa0, 0x98, 0xe4, 0x4a, 0x3e, 0xa0, 0x0a, 0x72,
+	0x25, 0x8d, 0x24, 0x2d, 0xfb, 0xa3, 0x07, 0xff, 0x26, 0x52, 0x26, 0x0b, 0x9c, 0xd8, 0x70, 0xbe,
+	0xbc, 0x9b, 0x18, 0x9e, 0xa1, 0x36, 0x2c, 0xcb, 0x4b, 0xe0, 0xe0, 0xaf, 0xf2, 0xb0, 0x51, 0x4c,
+	0x68, 0x16, 0x19, 0x2e, 0x45, 0x59, 0x18, 0xfd, 0xf4, 0xa0, 0x7b, 0x5b, 0xd4, 0x6e, 0xe5, 0x95,
+	0xd6, 0x4b, 0x24, 0x7f, 0x43, 0x5b, 0x61, 0xc4, 0x73, 0x8e, 0xc2, 0xf8, 0xde, 0xd0, 0x1b, 0x77,
+	0xe9, 0x3a, 0x41, 0x08, 0xec, 0x9a, 0x55, 0x8e, 0xfe, 0xce, 0xd0, 0x1b, 0xb7, 0xa9, 0xfd, 0x26,
+	0x03, 0x38, 0xb8, 0x5f, 0x32, 0x61, 0xb8, 0x59, 0xf9, 0xcd, 0xa1, 0x37, 0xde, 0xa5, 0x75, 0x4c,
+	0x5e, 0xc0, 0x49, 0x7d, 0x38, 0x8c, 0x52, 0xc6, 0x45, 0x18, 0xc9, 0x18, 0xfd, 0x5d, 0x7b, 0x31,
+	0xa9, 0x6b, 0x97, 0x45, 0xe9, 0x52, 0xc6, 0x48, 0x9e, 0xc3, 0x61, 0x8c, 0x8a, 0x3f, 0xb0, 0x82,
+	0x64, 0x98, 0x33, 0x93, 0xfa, 0x7b, 0xc3, 0xe6, 0xb8, 0x77, 0xb1, 0x73, 0xe4, 0xd1, 0xfe, 0xba,
+	0x74, 0xcd, 0x4c, 0x3a, 0xca, 0xa0, 0x77, 0x95, 0xe5, 0x52, 0x19, 0x8a, 0xf7, 0x4b, 0xd4, 0x86,
+	0xfc, 0x03, 0x10, 0x29, 0x8c, 0x51, 0x18, 0xce, 0x16, 0x8e, 0xfe, 0x46, 0x86, 0xbc, 0x85, 0x43,
+	0xab, 0x84, 0x0e, 0x8d, 0x0c, 0x79, 0x31, 0xb0, 0xbf, 0x33, 0x6c, 0x8e, 0x3b, 0xd3, 0x93, 0x52,
+	0x0f, 0x1d, 0x6c, 0x8a, 0x41, 0x7b, 0x25, 0xd8, 0x85, 0xa3, 0xef, 0x1e, 0xb4, 0x66, 0xc8, 0x62,
+	0x54, 0xe4, 0x35, 0xb4, 0x6b, 0x8d, 0x6d, 0x9f, 0xce, 0x74, 0x10, 0x94, 0x2e, 0x04, 0x95, 0x0b,
+	0xc1, 0x6d, 0x85, 0xa0, 0x6b, 0x30, 0x39, 0x03, 0x88, 0x52, 0x26, 0x04, 0x2e, 0x42, 0x1e, 0x3b,
+	0x21, 0xdb, 0x2e, 0x73, 0x15, 0x93, 0x13, 0xd8, 0x13, 0x52, 0x44, 0x68, 0xa5, 0xec, 0xd2, 0x32,
+	0x20, 0x3e, 0xec, 0x47, 0x0a, 0x99, 0x91, 0xca, 0x49, 0x57, 0x85, 0xa3, 0xaf, 0xb0, 0x7f, 0x29,
+	0xb3, 0x8c, 0x89, 0x98, 0xfc, 0x0f, 0xad, 0xd4, 0xb2, 0x73, 0x84, 0xfa, 0xd5, 0x4c, 0x25, 0x67,
+	0xea, 0xaa, 0xe4, 0x1d, 0xf4, 0xb9, 0x55, 0x2d, 0x54, 0xa5, 0x6c, 0x96, 0x45, 0x67, 0x7a, 0x5a,
+	0xe1, 0xb7, 0x34, 0x9d, 0x35, 0x68, 0x8f, 0x6f, 0x26, 0x2e, 0xda, 0xb0, 0x9f, 0xb3, 0xd5, 0x42,
+	0xb2, 0x78, 0xf4, 0x11, 0x7a, 0x9f, 0x78, 0x22, 0x30, 0xae, 0x38, 0x14, 0x44, 0xcb, 0x4f, 0xa7,
+	0x7e, 0x15, 0x16, 0x8b, 0xa5, 0x79, 0x22, 0x98, 0x59, 0xaa, 0x72, 0x7f, 0xba, 0x74, 0x9d, 0x18,
+	0x7d, 0xf3, 0xe0, 0xd4, 0xdd, 0x41, 0x51, 0xe7, 0x52, 0x68, 0xfc, 0x63, 0xa5, 0xcf, 0xa1, 0xeb,
+	0x9a, 0x87, 0x29, 0xd3, 0xa9, 0x6b, 0xda, 0x71, 0xb9, 0x19, 0xd3, 0xe9, 0xa6, 0xae, 0xcd, 0x6d,
+	0x5d, 0xdf, 0xc0, 0xde, 0x07, 0xa5, 0xa4, 0x2a, 0x20, 0x19, 0x6a, 0xcd, 0x12, 0xb4, 0xdd, 0xdb,
+	0xb4, 0x0a, 0x89, 0x5f, 0xeb, 0xe0, 0xae, 0xae, 0x65, 0xf9, 0xe1, 0xc1, 0xe1, 0xa3, 0x69, 0xc8,
+	0xab, 0x47, 0xee, 0x9c, 0x55, 0x6a, 0x3f, 0x39, 0x76, 0x6d, 0xd6, 0x39, 0x34, 0x51, 0x29, 0xe7,
+	0x50, 0xaf, 0x3a, 0x63, 0xa9, 0xcd, 0x1a, 0xb4, 0xa8, 0x91, 0xf7, 0x70, 0x6c, 0xf7, 0x34, 0xdc,
+	0xf8, 0x7b, 0xdb, 0x71, 0x3a, 0xd3, 0x63, 0xb7, 0xcf, 0xeb, 0xc2, 0xac, 0x41, 0x8f, 0xcc, 0xa3,
+	0xdc, 0xa6, 0xa3, 0x37, 0x70, 0xba, 0xe5, 0x68, 0xcd, 0x7f, 0x00, 0x07, 0xca, 0x7d, 0x3b, 0x6b,
+	0xeb, 0xf8, 0xf7, 0xde, 0x4e, 0x29, 0xb4, 0xae, 0xed, 0xab, 0x45, 0x66, 0xd0, 0xbf, 0x56, 0x32,
+	0x42, 0xad, 0xab, 0x7d, 0xa9, 0x77, 0x6e, 0xab, 0xe9, 0xe0, 0xec, 0xc9, 0x74, 0xc5, 0x65, 0xd4,
+	0xb8, 0xb8, 0x81, 0xff, 0xa4, 0x4a, 0x82, 0x74, 0x95, 0xa3, 0x5a, 0x60, 0x9c, 0xa0, 0x0a, 0xee,
+	0xd8, 0x5c, 0xf1, 0xa8, 0x3a, 0x68, 0x67, 0xfc, 0xfc, 0x2c, 0xe1, 0x26, 0x5d, 0xce, 0x83, 0x48,
+	0x66, 0x93, 0x0d, 0xec, 0xa4, 0xc4, 0x96, 0xef, 0xa5, 0x9e, 0x58, 0xec, 0xbc, 0x7c, 0x4c, 0x5f,
+	0xfe, 0x1a, 0x00, 0xd6, 0x68, 0xc9, 0x84, 0x69, 0x05, 0x00, 0x00,
 }
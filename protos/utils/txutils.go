@@ -71,8 +71,16 @@ func CreateSignedEnvelope(txType common.HeaderType, channelID string, signer cry
 // type, with marshaled dataMsg and signs it. It also includes a TLS cert hash
 // into the channel header
 func CreateSignedEnvelopeWithTLSBinding(txType common.HeaderType, channelID string, signer crypto.LocalSigner, dataMsg proto.Message, msgVersion int32, epoch uint64, tlsCertHash []byte) (*common.Envelope, error) {
+	return CreateSignedEnvelopeWithTLSBindingAndExtension(txType, channelID, signer, dataMsg, msgVersion, epoch, tlsCertHash, nil)
+}
+
+// CreateSignedEnvelopeWithTLSBindingAndExtension creates a signed envelope of the
+// desired type, with marshaled dataMsg and signs it. It also includes a TLS cert hash
+// and the given extension into the channel header
+func CreateSignedEnvelopeWithTLSBindingAndExtension(txType common.HeaderType, channelID string, signer crypto.LocalSigner, dataMsg proto.Message, msgVersion int32, epoch uint64, tlsCertHash []byte, extension []byte) (*common.Envelope, error) {
 	payloadChannelHeader := MakeChannelHeader(txType, msgVersion, channelID, epoch)
 	payloadChannelHeader.TlsCertHash = tlsCertHash
+	payloadChannelHeader.Extension = extension
 	var err error
 	payloadSignatureHeader := &common.SignatureHeader{}
 
@@ -327,6 +327,24 @@ func TestCreateSignedEnvelopeNilSigner(t *testing.T) {
 	assert.Equal(t, msg, data, "Payload data does not match expected value")
 }
 
+func TestCreateSignedEnvelopeWithTLSBindingAndExtension(t *testing.T) {
+	channelID := "mychannelID"
+	msg := &cb.ConfigEnvelope{}
+
+	env, err := utils.CreateSignedEnvelopeWithTLSBindingAndExtension(cb.HeaderType_DELIVER_SEEK_INFO, channelID,
+		goodSigner, msg, int32(1), uint64(1), []byte("certHash"), []byte("extension"))
+	assert.NoError(t, err, "Unexpected error creating signed envelope")
+	assert.Equal(t, env.Payload, env.Signature, "Unexpected signature returned")
+	payload := &cb.Payload{}
+	err = proto.Unmarshal(env.Payload, payload)
+	assert.NoError(t, err, "Failed to unmarshal payload")
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	assert.NoError(t, err, "Failed to unmarshal channel header")
+	assert.Equal(t, channelID, chdr.ChannelId)
+	assert.Equal(t, []byte("certHash"), chdr.TlsCertHash)
+	assert.Equal(t, []byte("extension"), chdr.Extension)
+}
+
 func TestGetSignedProposal(t *testing.T) {
 	var signedProp *pb.SignedProposal
 	var err error
@@ -34,7 +34,7 @@ cat docs/wrappers/peer_chaincode_postscript.md >> $DOC
 DOC=docs/source/commands/peerchannel.md
 cat docs/wrappers/peer_channel_preamble.md > $DOC
 
-for x in "peer channel" "peer channel create" "peer channel fetch" "peer channel getinfo" "peer channel join" "peer channel list" "peer channel signconfigtx" "peer channel update"; do
+for x in "peer channel" "peer channel create" "peer channel fetch" "peer channel getinfo" "peer channel join" "peer channel leave" "peer channel list" "peer channel signconfigtx" "peer channel update"; do
   echo "" >> $DOC
   echo "##" $x >> $DOC
   echo "\`\`\`" >> $DOC
@@ -44,6 +44,19 @@ for x in "peer channel" "peer channel create" "peer channel fetch" "peer channel
 done
 cat docs/wrappers/peer_channel_postscript.md >> $DOC
 
+DOC=docs/source/commands/peerledger.md
+cat docs/wrappers/peer_ledger_preamble.md > $DOC
+
+for x in "peer ledger block" "peer ledger history" "peer ledger tx"; do
+  echo "" >> $DOC
+  echo "##" $x >> $DOC
+  echo "\`\`\`" >> $DOC
+  .build/bin/${x} --help 1>> $DOC 2>/dev/null
+  echo "\`\`\`" >> $DOC
+  echo "" >> $DOC
+done
+cat docs/wrappers/peer_ledger_postscript.md >> $DOC
+
 DOC=docs/source/commands/peerlogging.md
 cat docs/wrappers/peer_logging_preamble.md > $DOC
 
@@ -60,7 +73,7 @@ cat docs/wrappers/peer_logging_postscript.md >> $DOC
 DOC=docs/source/commands/peernode.md
 cat docs/wrappers/peer_node_preamble.md > $DOC
 
-for x in "peer node start" "peer node status"; do
+for x in "peer node start" "peer node status" "peer node reset" "peer node rollback" "peer node pause" "peer node resume" "peer node verify-ledger"; do
   echo "" >> $DOC
   echo "##" $x >> $DOC
   echo "\`\`\`" >> $DOC
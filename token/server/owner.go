@@ -0,0 +1,36 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package server
+
+import (
+	"justledger/bccsp"
+	"github.com/pkg/errors"
+)
+
+// HDOwnerDeriver derives owner identities from hierarchical deterministic
+// public keys over P-256. The derived owner identity is the derived
+// public key in PKIX format.
+type HDOwnerDeriver struct {
+	CSP bccsp.BCCSP
+}
+
+// DeriveOwner returns the owner identity derived along path from the
+// public key publicKey, in PKIX format, whose chain code is chainCode.
+// Only non-hardened indices can be used, as the private key of the recipient is not known.
+func (d *HDOwnerDeriver) DeriveOwner(publicKey, chainCode []byte, path []uint32) ([]byte, error) {
+	k, err := d.CSP.KeyImport(publicKey, &bccsp.ECDSAP256HDPublicKeyImportOpts{Temporary: true, ChainCode: chainCode})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to import recipient public key")
+	}
+
+	derived, err := d.CSP.KeyDeriv(k, &bccsp.ECDSAP256HDKeyDerivOpts{Temporary: true, Path: path})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to derive owner key")
+	}
+
+	return derived.Bytes()
+}
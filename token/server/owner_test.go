@@ -0,0 +1,88 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package server_test
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"os"
+
+	"justledger/bccsp"
+	"justledger/bccsp/sw"
+	"justledger/token/server"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HDOwnerDeriver", func() {
+	var (
+		ksPath       string
+		csp          bccsp.BCCSP
+		account      bccsp.Key
+		publicKey    []byte
+		chainCode    []byte
+		ownerDeriver *server.HDOwnerDeriver
+	)
+
+	BeforeEach(func() {
+		var err error
+		ksPath, err = ioutil.TempDir("", "hdowner")
+		Expect(err).NotTo(HaveOccurred())
+		csp, err = sw.NewDefaultSecurityLevel(ksPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		master, err := csp.KeyImport([]byte("the recipient's master seed"), &bccsp.ECDSAP256HDMasterKeyImportOpts{Temporary: true})
+		Expect(err).NotTo(HaveOccurred())
+		account, err = csp.KeyDeriv(master, &bccsp.ECDSAP256HDKeyDerivOpts{Temporary: true, Path: []uint32{bccsp.HardenedKeyStart}})
+		Expect(err).NotTo(HaveOccurred())
+		accountPK, err := account.PublicKey()
+		Expect(err).NotTo(HaveOccurred())
+		publicKey, err = accountPK.Bytes()
+		Expect(err).NotTo(HaveOccurred())
+		chainCode = accountPK.(bccsp.HDKey).ChainCode()
+
+		ownerDeriver = &server.HDOwnerDeriver{CSP: csp}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(ksPath)
+	})
+
+	It("derives owner identities the recipient holds the private keys of", func() {
+		owner1, err := ownerDeriver.DeriveOwner(publicKey, chainCode, []uint32{1})
+		Expect(err).NotTo(HaveOccurred())
+		owner2, err := ownerDeriver.DeriveOwner(publicKey, chainCode, []uint32{2})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(owner1).NotTo(Equal(owner2))
+
+		ownerKey, err := csp.KeyImport(owner1, &bccsp.ECDSAPKIXPublicKeyImportOpts{Temporary: true})
+		Expect(err).NotTo(HaveOccurred())
+		sk, err := csp.KeyDeriv(account, &bccsp.ECDSAP256HDKeyDerivOpts{Temporary: true, Path: []uint32{1}})
+		Expect(err).NotTo(HaveOccurred())
+
+		digest := sha256.Sum256([]byte("transfer"))
+		signature, err := csp.Sign(sk, digest[:], nil)
+		Expect(err).NotTo(HaveOccurred())
+		valid, err := csp.Verify(ownerKey, signature, digest[:], nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(valid).To(BeTrue())
+	})
+
+	Context("when the chain code is invalid", func() {
+		It("returns an error", func() {
+			_, err := ownerDeriver.DeriveOwner(publicKey, []byte("short"), []uint32{1})
+			Expect(err).To(MatchError(ContainSubstring("failed to import recipient public key")))
+		})
+	})
+
+	Context("when a hardened owner key is requested", func() {
+		It("returns an error", func() {
+			_, err := ownerDeriver.DeriveOwner(publicKey, chainCode, []uint32{bccsp.HardenedKeyStart})
+			Expect(err).To(MatchError(ContainSubstring("failed to derive owner key")))
+		})
+	})
+})
@@ -28,11 +28,24 @@ type Marshaler interface {
 	MarshalCommandResponse(command []byte, responsePayload interface{}) (*token.SignedCommandResponse, error)
 }
 
+//go:generate counterfeiter -o mock/owner_deriver.go -fake-name OwnerDeriver . OwnerDeriver
+
+// An OwnerDeriver derives unlinkable owner identities from
+// the hierarchical deterministic public key of a recipient.
+type OwnerDeriver interface {
+	// DeriveOwner returns the owner identity derived along path from the
+	// public key publicKey, in PKIX format, whose chain code is chainCode.
+	DeriveOwner(publicKey, chainCode []byte, path []uint32) ([]byte, error)
+}
+
 // A Provider is responslble for processing token commands.
 type Prover struct {
 	Marshaler     Marshaler
 	PolicyChecker PolicyChecker
 	TMSManager    TMSManager
+	// OwnerDeriver is optional. Without it, tokens cannot be
+	// issued to owner identities derived for a recipient.
+	OwnerDeriver OwnerDeriver
 }
 
 func (s *Prover) ProcessCommand(ctx context.Context, sc *token.SignedCommand) (*token.SignedCommandResponse, error) {
@@ -74,7 +87,12 @@ func (s *Prover) RequestImport(ctx context.Context, header *token.Header, reques
 		return nil, err
 	}
 
-	tokenTransaction, err := issuer.RequestImport(requestImport.TokensToIssue)
+	tokensToIssue, err := s.DeriveRecipients(requestImport.TokensToIssue)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenTransaction, err := issuer.RequestImport(tokensToIssue)
 	if err != nil {
 		return nil, err
 	}
@@ -82,6 +100,32 @@ func (s *Prover) RequestImport(ctx context.Context, header *token.Header, reques
 	return &token.CommandResponse_TokenTransaction{TokenTransaction: tokenTransaction}, nil
 }
 
+// DeriveRecipients replaces the recipient of the tokens that request a derivation path
+// with the owner identity derived along that path from the recipient's public key.
+func (s *Prover) DeriveRecipients(tokensToIssue []*token.TokenToIssue) ([]*token.TokenToIssue, error) {
+	derived := make([]*token.TokenToIssue, len(tokensToIssue))
+	for i, tti := range tokensToIssue {
+		if len(tti.DerivationPath) == 0 {
+			derived[i] = tti
+			continue
+		}
+		if s.OwnerDeriver == nil {
+			return nil, errors.New("derived owner identities are not supported")
+		}
+
+		owner, err := s.OwnerDeriver.DeriveOwner(tti.Recipient, tti.RecipientChainCode, tti.DerivationPath)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to derive owner identity")
+		}
+		derived[i] = &token.TokenToIssue{
+			Recipient: owner,
+			Type:      tti.Type,
+			Quantity:  tti.Quantity,
+		}
+	}
+	return derived, nil
+}
+
 func (s *Prover) ValidateHeader(header *token.Header) error {
 	if header == nil {
 		return errors.New("command header is required")
@@ -310,5 +310,64 @@ var _ = Describe("Prover", func() {
 				Expect(err).To(MatchError("watermelon"))
 			})
 		})
+
+		Context("when a token is issued to a derived owner identity", func() {
+			var fakeOwnerDeriver *mock.OwnerDeriver
+
+			BeforeEach(func() {
+				fakeOwnerDeriver = &mock.OwnerDeriver{}
+				fakeOwnerDeriver.DeriveOwnerReturns([]byte("derived-owner"), nil)
+				prover.OwnerDeriver = fakeOwnerDeriver
+
+				importRequest.TokensToIssue = append(importRequest.TokensToIssue, &token.TokenToIssue{
+					Recipient:          []byte("recipient-public-key"),
+					RecipientChainCode: []byte("recipient-chain-code"),
+					DerivationPath:     []uint32{0, 5},
+					Type:               "XYZ",
+					Quantity:           11,
+				})
+			})
+
+			It("issues the token to the derived owner identity", func() {
+				_, err := prover.RequestImport(context.Background(), command.Header, importRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeOwnerDeriver.DeriveOwnerCallCount()).To(Equal(1))
+				pk, chainCode, path := fakeOwnerDeriver.DeriveOwnerArgsForCall(0)
+				Expect(pk).To(Equal([]byte("recipient-public-key")))
+				Expect(chainCode).To(Equal([]byte("recipient-chain-code")))
+				Expect(path).To(Equal([]uint32{0, 5}))
+
+				Expect(fakeIssuer.RequestImportCallCount()).To(Equal(1))
+				tti := fakeIssuer.RequestImportArgsForCall(0)
+				Expect(tti).To(Equal([]*token.TokenToIssue{
+					importRequest.TokensToIssue[0],
+					{Recipient: []byte("derived-owner"), Type: "XYZ", Quantity: 11},
+				}))
+			})
+
+			Context("when the owner identity cannot be derived", func() {
+				BeforeEach(func() {
+					fakeOwnerDeriver.DeriveOwnerReturns(nil, errors.New("mango"))
+				})
+
+				It("returns the error", func() {
+					_, err := prover.RequestImport(context.Background(), command.Header, importRequest)
+					Expect(err).To(MatchError("failed to derive owner identity: mango"))
+					Expect(fakeIssuer.RequestImportCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the prover has no owner deriver", func() {
+				BeforeEach(func() {
+					prover.OwnerDeriver = nil
+				})
+
+				It("returns an error", func() {
+					_, err := prover.RequestImport(context.Background(), command.Header, importRequest)
+					Expect(err).To(MatchError("derived owner identities are not supported"))
+				})
+			})
+		})
 	})
 })